@@ -120,30 +120,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/me": {
-            "get": {
+        "/auth/magic-link": {
+            "post": {
+                "description": "Always returns 202 for a well-formed email, whether or not an account exists.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Get current authenticated user",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Request magic login link",
+                "parameters": [
+                    {
+                        "description": "Magic link payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserResponse"
+                            "$ref": "#/definitions/dto.MagicLinkRequest"
                         }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -157,7 +169,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh": {
+        "/auth/magic-link/consume": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -168,15 +180,15 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Refresh tokens",
+                "summary": "Exchange magic link token for session tokens",
                 "parameters": [
                     {
-                        "description": "Refresh token payload",
+                        "description": "Magic link token payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.RefreshTokenRequest"
+                            "$ref": "#/definitions/dto.MagicLinkConsumeRequest"
                         }
                     }
                 ],
@@ -199,6 +211,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -208,44 +226,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/auth/me": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Register user",
-                "parameters": [
-                    {
-                        "description": "Registration payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RegisterRequest"
-                        }
-                    }
-                ],
+                "summary": "Get current authenticated user",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service.AuthResult"
+                            "$ref": "#/definitions/handlers.UserResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -259,55 +263,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/body-weight-logs": {
+        "/auth/oidc/providers": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "body-weight-logs"
+                    "auth"
                 ],
-                "summary": "List body weight logs by date range",
+                "summary": "List external sign-in providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/begin": {
+            "post": {
+                "description": "Returns the provider authorization URL (authorization code flow with PKCE). The provider redirects back to the configured client page with ` + "`" + `code` + "`" + ` and ` + "`" + `state` + "`" + `.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start external provider sign-in",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "From date (YYYY-MM-DD)",
-                        "name": "from",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "To date (YYYY-MM-DD)",
-                        "name": "to",
-                        "in": "query",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size (default 20, max 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset (default 0)",
-                        "name": "offset",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.BodyWeightLogResponse"
-                            }
+                            "$ref": "#/definitions/service.OIDCLoginStart"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -317,10 +328,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/auth/oidc/{provider}/finish": {
             "post": {
+                "description": "Exchanges the callback code for session tokens. Links the identity to the account with the same verified email, or creates one.",
                 "consumes": [
                     "application/json"
                 ],
@@ -328,25 +348,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "body-weight-logs"
+                    "auth"
                 ],
-                "summary": "Create body weight log",
+                "summary": "Complete external provider sign-in",
                 "parameters": [
                     {
-                        "description": "Body weight payload",
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Provider callback parameters",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateBodyWeightLogRequest"
+                            "$ref": "#/definitions/dto.OIDCFinishRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.BodyWeightLogResponse"
+                            "$ref": "#/definitions/service.AuthResult"
                         }
                     },
                     "400": {
@@ -355,33 +382,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
-                    }
-                }
-            }
-        },
-        "/body-weight-logs/latest": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "body-weight-logs"
-                ],
-                "summary": "Get latest body weight log",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.BodyWeightLogResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -392,42 +400,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
                     }
                 }
             }
         },
-        "/daily-totals": {
+        "/auth/passkeys": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
-                ],
-                "summary": "Get daily nutrition totals",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "List my passkeys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.DailyTotalsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.PasskeyResponse"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -441,47 +455,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/foods": {
-            "get": {
+        "/auth/passkeys/login/begin": {
+            "post": {
+                "description": "Returns options for navigator.credentials.get() using discoverable credentials.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "foods"
-                ],
-                "summary": "List foods",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Search by food name (case-insensitive, partial match)",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size (default 20, max 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset (default 0)",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "auth"
                 ],
+                "summary": "Start passkey login",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.FoodResponse"
-                            }
+                            "$ref": "#/definitions/webauthn.RequestOptions"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -493,7 +485,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/passkeys/login/finish": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -502,25 +496,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "foods"
+                    "auth"
                 ],
-                "summary": "Create food",
+                "summary": "Complete passkey login",
                 "parameters": [
                     {
-                        "description": "Food payload",
+                        "description": "Passkey assertion payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateFoodRequest"
+                            "$ref": "#/definitions/dto.PasskeyLoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.FoodResponse"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.AuthResult"
                         }
                     },
                     "400": {
@@ -529,8 +523,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -544,39 +544,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/foods/by-barcode/{barcode}": {
-            "get": {
+        "/auth/passkeys/register/begin": {
+            "post": {
+                "description": "Returns options for navigator.credentials.create(). The challenge is valid for one registration.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "foods"
-                ],
-                "summary": "Get food by barcode",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Food barcode",
-                        "name": "barcode",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Start passkey registration",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.FoodResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                            "$ref": "#/definitions/webauthn.CreationOptions"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -590,29 +576,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/foods/{id}": {
-            "get": {
+        "/auth/passkeys/register/finish": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "foods"
+                    "auth"
                 ],
-                "summary": "Get food by ID",
+                "summary": "Complete passkey registration",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Food ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Passkey registration payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasskeyRegistrationRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handlers.FoodResponse"
+                            "$ref": "#/definitions/handlers.PasskeyResponse"
                         }
                     },
                     "400": {
@@ -621,8 +612,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -634,19 +631,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/passkeys/{id}": {
             "delete": {
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "foods"
+                    "auth"
                 ],
-                "summary": "Delete food",
+                "summary": "Remove passkey",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Food ID",
+                        "description": "Passkey ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -654,10 +650,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "No Content",
-                        "schema": {
-                            "type": "string"
-                        }
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -690,8 +683,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/auth/refresh": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -699,24 +694,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "foods"
+                    "auth"
                 ],
-                "summary": "Update food",
+                "summary": "Refresh tokens",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Food ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Food update payload",
+                        "description": "Refresh token payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateFoodRequest"
+                            "$ref": "#/definitions/dto.RefreshTokenRequest"
                         }
                     }
                 ],
@@ -724,7 +712,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.FoodResponse"
+                            "$ref": "#/definitions/service.AuthResult"
                         }
                     },
                     "400": {
@@ -733,14 +721,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorEnvelope"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -754,71 +736,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Backward-compatible alias for readiness",
-                "produces": [
+        "/auth/register": {
+            "post": {
+                "consumes": [
                     "application/json"
                 ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.HealthResponse"
-                        }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorEnvelope"
-                        }
-                    }
-                }
-            }
-        },
-        "/health/live": {
-            "get": {
-                "description": "Returns process liveness status",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "auth"
                 ],
-                "summary": "Liveness check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Register user",
+                "parameters": [
+                    {
+                        "description": "Registration payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.HealthResponse"
+                            "$ref": "#/definitions/dto.RegisterRequest"
                         }
                     }
-                }
-            }
-        },
-        "/health/ready": {
-            "get": {
-                "description": "Returns dependency readiness status",
-                "produces": [
-                    "application/json"
                 ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Readiness check",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handlers.HealthResponse"
+                            "$ref": "#/definitions/service.AuthResult"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -826,20 +787,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/meals": {
+        "/body-weight-logs": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
+                    "body-weight-logs"
                 ],
-                "summary": "List meals by user and date",
+                "summary": "List body weight logs by date range",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
+                        "description": "From date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date (YYYY-MM-DD)",
+                        "name": "to",
                         "in": "query",
                         "required": true
                     },
@@ -862,7 +830,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/handlers.MealResponse"
+                                "$ref": "#/definitions/handlers.BodyWeightLogResponse"
                             }
                         }
                     },
@@ -888,17 +856,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
+                    "body-weight-logs"
                 ],
-                "summary": "Create meal",
+                "summary": "Create body weight log",
                 "parameters": [
                     {
-                        "description": "Meal payload",
+                        "description": "Body weight payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateMealRequest"
+                            "$ref": "#/definitions/dto.CreateBodyWeightLogRequest"
                         }
                     }
                 ],
@@ -906,7 +874,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MealResponse"
+                            "$ref": "#/definitions/handlers.BodyWeightLogResponse"
                         }
                     },
                     "400": {
@@ -924,29 +892,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/meals/{id}": {
+        "/body-weight-logs/latest": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
-                ],
-                "summary": "Get meal by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Meal ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "body-weight-logs"
                 ],
+                "summary": "Get latest body weight log",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MealResponse"
+                            "$ref": "#/definitions/handlers.BodyWeightLogResponse"
                         }
                     },
                     "400": {
@@ -968,33 +927,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/cooking-methods": {
+            "get": {
+                "description": "Returns the cooking methods with their yield and moisture-retention factors per food category. A factor without a category is the method's default.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "meals"
-                ],
-                "summary": "Delete meal",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Meal ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "foods"
                 ],
+                "summary": "List cooking methods",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.CookingMethodResponse"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -1006,41 +962,31 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/daily-totals": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "meals"
                 ],
-                "summary": "Update meal",
+                "summary": "Get daily nutrition totals",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Meal ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "description": "Meal update payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateMealRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MealResponse"
+                            "$ref": "#/definitions/handlers.DailyTotalsResponse"
                         }
                     },
                     "400": {
@@ -1049,12 +995,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorEnvelope"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1064,51 +1004,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/meals/{id}/items": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/food-categories": {
+            "get": {
+                "description": "Returns the food category taxonomy as a tree, sorted by name.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
-                ],
-                "summary": "Add meal item",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Meal ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Meal item payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.AddMealItemRequest"
-                        }
-                    }
+                    "foods"
                 ],
+                "summary": "List food categories",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.MealItemResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FoodCategoryResponse"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -1122,40 +1039,82 @@ const docTemplate = `{
                 }
             }
         },
-        "/meals/{meal_id}/items/{item_id}": {
-            "delete": {
+        "/foods": {
+            "get": {
+                "description": "Returns public and verified foods plus the caller's private foods. Archived foods are skipped unless include_archived is set. display_name holds the name in the first of the caller's saved languages, then Accept-Language languages, that the food is translated into.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "meals"
+                    "foods"
                 ],
-                "summary": "Delete meal item",
+                "summary": "List foods",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by food name, or by translated name or synonym in the caller's languages (case-insensitive, partial match)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show and search names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
                     {
                         "type": "integer",
-                        "description": "Meal ID",
-                        "name": "meal_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only foods in this category or its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only foods carrying every given tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived foods",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip foods conflicting with the caller's allergens and diet flags",
+                        "name": "exclude_conflicts",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Meal Item ID",
-                        "name": "item_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FoodResponse"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -1168,7 +1127,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "post": {
+                "description": "Energy is checked against the Atwater factors (4/4/9/7 kcal per g of protein/carbs/fat/alcohol) and macro mass against 100 g. In lenient mode failed checks are returned as warnings; in strict mode the food is rejected with inconsistent_nutrition.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1176,39 +1136,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meals"
+                    "foods"
                 ],
-                "summary": "Update meal item",
+                "summary": "Create food",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Meal ID",
-                        "name": "meal_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Meal Item ID",
-                        "name": "item_id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "lenient",
+                            "strict"
+                        ],
+                        "type": "string",
+                        "description": "Nutrition validation mode",
+                        "name": "validation",
+                        "in": "query"
                     },
                     {
-                        "description": "Meal item update payload",
+                        "description": "Food payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateMealItemRequest"
+                            "$ref": "#/definitions/dto.CreateFoodRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handlers.MealItemResponse"
+                            "$ref": "#/definitions/handlers.FoodResponse"
                         }
                     },
                     "400": {
@@ -1217,8 +1173,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -1232,21 +1200,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/progress/daily": {
+        "/foods/by-barcode/{barcode}": {
             "get": {
+                "description": "Resolves among foods visible to the caller. Verified entries win, then the caller's private entry, then public ones. Unknown barcodes are looked up in the configured external catalog; found products are imported as public catalog foods, and misses are cached.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user-goals"
+                    "foods"
                 ],
-                "summary": "Get daily progress against user goals",
+                "summary": "Get food by barcode",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Date (YYYY-MM-DD)",
-                        "name": "date",
-                        "in": "query",
+                        "description": "Food barcode",
+                        "name": "barcode",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1254,7 +1223,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.DailyProgressResponse"
+                            "$ref": "#/definitions/handlers.FoodResponse"
                         }
                     },
                     "400": {
@@ -1274,30 +1243,37 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
                     }
                 }
             }
         },
-        "/progress/energy": {
+        "/foods/nutrition-report": {
             "get": {
+                "description": "Returns active foods visible to the caller whose energy does not match the Atwater estimate or whose macros add up to more than 100 g, with the failed checks.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "progress"
+                    "foods"
                 ],
-                "summary": "Get observed/formula energy progress",
+                "summary": "List foods with inconsistent nutrition",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "From date (YYYY-MM-DD)",
-                        "name": "from",
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "To date (YYYY-MM-DD)",
-                        "name": "to",
+                        "type": "integer",
+                        "description": "Page offset (default 0)",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
@@ -1305,7 +1281,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.EnergyProgressResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FoodNutritionIssueResponse"
+                            }
                         }
                     },
                     "400": {
@@ -1314,7 +1293,13 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "500": {
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
@@ -1323,22 +1308,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/recipes": {
+        "/foods/search": {
             "get": {
+                "description": "Takes the same filters as GET /foods and also returns per-category and per-tag counts over all matches, for narrowing results.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recipes"
+                    "foods"
                 ],
-                "summary": "List recipes",
+                "summary": "Search foods with facets",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search by recipe name (case-insensitive, partial match)",
+                        "description": "Search by food name, or by translated name or synonym in the caller's languages (case-insensitive, partial match)",
                         "name": "q",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Languages to show and search names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only foods in this category or its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only foods carrying every given tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived foods",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip foods conflicting with the caller's allergens and diet flags",
+                        "name": "exclude_conflicts",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Page size (default 20, max 100)",
@@ -1356,10 +1376,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.RecipeResponse"
-                            }
+                            "$ref": "#/definitions/handlers.FoodSearchResponse"
                         }
                     },
                     "400": {
@@ -1368,45 +1385,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorEnvelope"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "recipes"
-                ],
-                "summary": "Create recipe",
-                "parameters": [
-                    {
-                        "description": "Recipe payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateRecipeRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.RecipeResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
@@ -1420,29 +1400,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/recipes/{id}": {
+        "/foods/{id}": {
             "get": {
+                "description": "Other users' private foods are reported as not found.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recipes"
+                    "foods"
                 ],
-                "summary": "Get recipe by ID",
+                "summary": "Get food by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Recipe ID",
+                        "description": "Food ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RecipeResponse"
+                            "$ref": "#/definitions/handlers.FoodResponse"
                         }
                     },
                     "400": {
@@ -1466,17 +1453,18 @@ const docTemplate = `{
                 }
             },
             "delete": {
+                "description": "Archives the food. It disappears from lists, search and barcode lookups but still resolves by ID for meal items and recipes that use it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recipes"
+                    "foods"
                 ],
-                "summary": "Delete recipe",
+                "summary": "Delete food",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Recipe ID",
+                        "description": "Food ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1522,6 +1510,7 @@ const docTemplate = `{
                 }
             },
             "patch": {
+                "description": "When nutrition values change, the resulting food is checked like on create: warnings in lenient mode, inconsistent_nutrition in strict mode.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1529,24 +1518,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recipes"
+                    "foods"
                 ],
-                "summary": "Update recipe",
+                "summary": "Update food",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Recipe ID",
+                        "description": "Food ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Recipe update payload",
+                        "enum": [
+                            "lenient",
+                            "strict"
+                        ],
+                        "type": "string",
+                        "description": "Nutrition validation mode",
+                        "name": "validation",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Food update payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateRecipeRequest"
+                            "$ref": "#/definitions/dto.UpdateFoodRequest"
                         }
                     }
                 ],
@@ -1554,7 +1553,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RecipeResponse"
+                            "$ref": "#/definitions/handlers.FoodResponse"
                         }
                     },
                     "400": {
@@ -1563,36 +1562,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
-                    }
-                }
-            }
-        },
-        "/user-goals": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user-goals"
-                ],
-                "summary": "Get current user goals",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.UserGoalResponse"
-                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -1600,6 +1580,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1607,34 +1593,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/foods/{id}/duplicates": {
+            "get": {
+                "description": "Scores visible foods against this one by normalized name, brand, barcode and nutrition similarity. Returns up to 20 candidates scoring at least 0.6, best first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user-goals"
+                    "foods"
                 ],
-                "summary": "Create or update user goals",
+                "summary": "Find duplicate foods",
                 "parameters": [
                     {
-                        "description": "User goals payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpsertUserGoalRequest"
-                        }
+                        "type": "integer",
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserGoalResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FoodDuplicateResponse"
+                            }
                         }
                     },
                     "400": {
@@ -1643,6 +1630,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1652,34 +1651,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
-            "patch": {
+        "/foods/{id}/image": {
+            "put": {
+                "description": "Stores a JPEG, PNG or GIF as the food's image, replacing any previous one, and returns the food with ` + "`" + `image_url` + "`" + ` and ` + "`" + `thumbnail_url` + "`" + `. The same edit rules as PATCH apply.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "foods"
                 ],
-                "summary": "Update current authenticated user profile",
+                "summary": "Upload food image",
                 "parameters": [
                     {
-                        "description": "Update me payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateMeRequest"
-                        }
+                        "type": "integer",
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserResponse"
+                            "$ref": "#/definitions/handlers.FoodResponse"
                         }
                     },
                     "400": {
@@ -1694,45 +1699,67 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
                     }
                 }
-            }
-        },
-        "/users/{id}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "tags": [
-                    "users"
+                    "foods"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Remove food image",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Food ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.UserResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1740,18 +1767,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
-                    "404": {
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorEnvelope"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1760,49 +1799,4087 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AddMealItemRequest": {
-            "type": "object",
-            "properties": {
-                "food_id": {
-                    "description": "Food source ID (mutually exclusive with recipe_id).",
-                    "type": "integer",
-                    "example": 1
-                },
-                "recipe_id": {
-                    "description": "Recipe source ID (mutually exclusive with food_id).",
-                    "type": "integer",
-                    "example": 1
+        },
+        "/foods/{id}/merge": {
+            "post": {
+                "description": "Admin only. Re-points meal items, recipe ingredients and shopping list items of the listed foods to the food in the path in one transaction, archives the merged foods and recalculates affected recipes. Recipes that fail to recalculate stay stale for the background pass. Each merged food is logged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "foods"
+                ],
+                "summary": "Merge foods into this one",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Surviving food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Foods to merge",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MergeFoodsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FoodMergeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/foods/{id}/restore": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "foods"
+                ],
+                "summary": "Restore archived food",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FoodResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/foods/{id}/verify": {
+            "post": {
+                "description": "Admin only. Marks a visible food as verified so it wins barcode lookups.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "foods"
+                ],
+                "summary": "Verify food",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FoodResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/foods/{id}/versions": {
+            "get": {
+                "description": "Returns the edit history of a visible food, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "foods"
+                ],
+                "summary": "List food versions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FoodVersionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/foods/{id}/versions/{version}/rollback": {
+            "post": {
+                "description": "Restores name, brand, barcode and nutrition values from an earlier version. The rollback is recorded as a new version; visibility is unchanged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "foods"
+                ],
+                "summary": "Roll back food to a version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Food ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to restore",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FoodResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Backward-compatible alias for readiness",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "Returns process liveness status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Returns dependency readiness status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "List meals by user and date",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.MealResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Create meal",
+                "parameters": [
+                    {
+                        "description": "Meal payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateMealRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MealResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/{id}": {
+            "get": {
+                "description": "With breakdown=true the response adds what each item contributes, its share of each total and the\nshare of calories from protein, carbs and fat.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Get meal by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add per-item nutrition",
+                        "name": "breakdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MealResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Delete meal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Update meal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Meal update payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateMealRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MealResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/{id}/image": {
+            "put": {
+                "description": "Stores a JPEG, PNG or GIF as a photo of the meal, replacing any previous one, and returns the meal with ` + "`" + `image_url` + "`" + ` and ` + "`" + `thumbnail_url` + "`" + `.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Upload meal photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MealResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Remove meal photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/{id}/items": {
+            "post": {
+                "description": "Items that break the user's dietary restrictions are still logged; the response lists the conflicts as warnings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Add meal item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Meal item payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddMealItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MealItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/{id}/share-links": {
+            "post": {
+                "description": "Makes a link that shows the meal and its nutrition to anyone holding the token, without signing in. The token is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share-links"
+                ],
+                "summary": "Share meal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Share link payload",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateShareLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShareLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/meals/{meal_id}/items/{item_id}": {
+            "delete": {
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Delete meal item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "meal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Meal Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "meals"
+                ],
+                "summary": "Update meal item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Meal ID",
+                        "name": "meal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Meal Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Meal item update payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateMealItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MealItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/daily": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-goals"
+                ],
+                "summary": "Get daily progress against user goals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DailyProgressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/progress/energy": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "progress"
+                ],
+                "summary": "Get observed/formula energy progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "From date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EnergyProgressResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/shared/{token}": {
+            "get": {
+                "description": "Public, no sign-in needed. Shows the shared recipe or meal read-only with the nutrition of each ingredient or item, and nothing about its owner. Each call that opens the link counts as an access. Unknown, revoked and expired links, and links to deleted recipes or meals, are all not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share-links"
+                ],
+                "summary": "Open share link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show names in",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SharedViewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes": {
+            "get": {
+                "description": "Archived recipes are skipped unless include_archived is set. With exclude_conflicts, recipes whose\ningredients break the caller's dietary restrictions are skipped too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "List recipes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by recipe name, or by translated name or synonym in the caller's languages (case-insensitive, partial match)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only recipes carrying every given tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only recipes whose stated prep and cook times add up to at most this many minutes",
+                        "name": "max_total_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show and search names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived recipes",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip recipes conflicting with the caller's allergens and diet flags",
+                        "name": "exclude_conflicts",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.RecipeResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Create recipe",
+                "parameters": [
+                    {
+                        "description": "Recipe payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateRecipeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/import": {
+            "post": {
+                "description": "Parses a web page with schema.org Recipe JSON-LD, the JSON-LD itself, or a pasted ingredient list with one\ningredient per line, such as \"200 g chicken breast\" or \"2 tbsp olive oil\". Each ingredient gets up to three\ncatalog foods with a confidence between 0 and 1, and ` + "`" + `food_id` + "`" + ` when the best one is confident enough.\nVolumes are converted to grams as water, so ` + "`" + `approximate_weight` + "`" + ` marks them for review. Nothing is saved:\nthe confirmed draft is sent to ` + "`" + `POST /recipes` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Import recipe draft",
+                "parameters": [
+                    {
+                        "description": "Recipe import payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportRecipeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show food names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeDraftResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}": {
+            "get": {
+                "description": "With breakdown=true the response adds what each ingredient contributes to the whole batch, its share of\neach total and the share of calories from protein, carbs and fat.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Get recipe by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add per-ingredient nutrition",
+                        "name": "breakdown",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Archives the recipe. It disappears from lists and search but still resolves by ID for meal items that use it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Delete recipe",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Update recipe",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Recipe update payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateRecipeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/changes": {
+            "get": {
+                "description": "Shows what a forked recipe changed relative to the recipe it was copied from: changed fields, added,\nremoved and changed ingredients, and the difference in per-100g values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Compare fork with original",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/fork": {
+            "post": {
+                "description": "Copies the recipe, with its ingredients, details and translations, into a new recipe owned by the caller,\nwhich records the original in ` + "`" + `forked_from_id` + "`" + `. The copy keeps every ingredient of the original and is\nrecalculated from their current values. Images are not copied.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Fork recipe",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/forks": {
+            "get": {
+                "description": "Lists the active recipes forked from this one, oldest first, each with what it changed relative to it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "List recipe forks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.RecipeForkResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/image": {
+            "put": {
+                "description": "Stores a JPEG, PNG or GIF as the recipe's image, replacing any previous one, and returns the recipe with ` + "`" + `image_url` + "`" + ` and ` + "`" + `thumbnail_url` + "`" + `. Only the owner may change it.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Upload recipe image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Remove recipe image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/recalculate": {
+            "post": {
+                "description": "Recomputes the recipe's per-100g values from the current values of its ingredient foods and clears ` + "`" + `stale` + "`" + `. Only the owner may recalculate it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Recalculate recipe",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/restore": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Restore archived recipe",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/scaled": {
+            "get": {
+                "description": "Returns the recipe resized to the given number of servings: the yield, ingredient weights and per-batch\nnutrition scale proportionally while per-100g and per-serving values stay the same. Nothing is saved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipes"
+                ],
+                "summary": "Scale recipe to servings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of servings (1-1000)",
+                        "name": "servings",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Languages to show names in, after the caller's saved languages",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipes/{id}/share-links": {
+            "post": {
+                "description": "Makes a link that shows the recipe and its nutrition to anyone holding the token, without signing in. The token is only returned here. Only the recipe's owner can share it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share-links"
+                ],
+                "summary": "Share recipe",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recipe ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Share link payload",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateShareLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShareLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/share-links": {
+            "get": {
+                "description": "Newest first, revoked and expired links included, with how often each was opened. Tokens are not returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "share-links"
+                ],
+                "summary": "List share links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ShareLinkResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/share-links/{id}": {
+            "delete": {
+                "description": "The link stops opening at once. It stays in the list, with its access count.",
+                "tags": [
+                    "share-links"
+                ],
+                "summary": "Revoke share link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Share link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/shopping-lists": {
+            "get": {
+                "description": "Newest first, without their items.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shopping-lists"
+                ],
+                "summary": "List shopping lists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ShoppingListResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds up the raw ingredient weights of either the given recipes, scaled to the requested servings, or the meals eaten from ` + "`" + `from` + "`" + ` to ` + "`" + `to` + "`" + ` (at most 31 days). Sub-recipes are expanded into their foods, and each food appears once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shopping-lists"
+                ],
+                "summary": "Create shopping list",
+                "parameters": [
+                    {
+                        "description": "Shopping list payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateShoppingListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShoppingListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/shopping-lists/{id}": {
+            "get": {
+                "description": "With format=text the list is exported as plain text, one line per item with a checkbox and the weight in grams.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "shopping-lists"
+                ],
+                "summary": "Get shopping list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "text"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShoppingListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "shopping-lists"
+                ],
+                "summary": "Delete shopping list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/shopping-lists/{id}/items/{item_id}": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shopping-lists"
+                ],
+                "summary": "Check or uncheck shopping list item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shopping list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Shopping list item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shopping list item update payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateShoppingListItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShoppingListItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-goals": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-goals"
+                ],
+                "summary": "Get current user goals",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserGoalResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-goals"
+                ],
+                "summary": "Create or update user goals",
+                "parameters": [
+                    {
+                        "description": "User goals payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpsertUserGoalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserGoalResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update current authenticated user profile",
+                "parameters": [
+                    {
+                        "description": "Update me payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateMeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AddMealItemRequest": {
+            "type": "object",
+            "properties": {
+                "cooked_weight_g": {
+                    "description": "Consumed weight of the food after cooking, converted back to its raw weight; requires cooking_method_id.",
+                    "type": "number",
+                    "example": 220
+                },
+                "cooking_method_id": {
+                    "description": "Cooking method the food was cooked by.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "food_id": {
+                    "description": "Food source ID (mutually exclusive with recipe_id).",
+                    "type": "integer",
+                    "example": 1
+                },
+                "recipe_id": {
+                    "description": "Recipe source ID (mutually exclusive with food_id).",
+                    "type": "integer",
+                    "example": 1
+                },
+                "servings": {
+                    "description": "Consumed servings of the recipe, converted to grams through its yield.",
+                    "type": "number",
+                    "example": 1.5
+                },
+                "weight_g": {
+                    "description": "Item consumed weight in grams (mutually exclusive with servings and cooked_weight_g).",
+                    "type": "number",
+                    "example": 150
+                }
+            }
+        },
+        "dto.CreateBodyWeightLogRequest": {
+            "type": "object",
+            "properties": {
+                "logged_at": {
+                    "description": "Measurement timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T08:00:00Z"
+                },
+                "weight_kg": {
+                    "description": "Body weight in kilograms.",
+                    "type": "number",
+                    "example": 85.2
+                }
+            }
+        },
+        "dto.CreateFoodRequest": {
+            "type": "object",
+            "properties": {
+                "alcohol_per_100g": {
+                    "description": "Optional alcohol grams per 100g; defaults to 0.",
+                    "type": "number",
+                    "example": 0
+                },
+                "allergens": {
+                    "description": "Optional EU major allergens the food contains.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "milk"
+                    ]
+                },
+                "barcode": {
+                    "description": "Optional product barcode (EAN/UPC digits).",
+                    "type": "string",
+                    "example": "5901234123457"
+                },
+                "brand_name": {
+                    "description": "Optional brand name.",
+                    "type": "string",
+                    "example": "Fage"
+                },
+                "carbs_per_100g": {
+                    "description": "Carbohydrate grams per 100g.",
+                    "type": "number",
+                    "example": 28
+                },
+                "category_id": {
+                    "description": "Optional category ID from GET /food-categories.",
+                    "type": "integer",
+                    "example": 16
+                },
+                "diet_flags": {
+                    "description": "Optional diet flags; vegan implies vegetarian.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian",
+                        "gluten_free"
+                    ]
+                },
+                "fat_per_100g": {
+                    "description": "Fat grams per 100g.",
+                    "type": "number",
+                    "example": 0.3
+                },
+                "kcal_per_100g": {
+                    "description": "Energy in kcal per 100g.",
+                    "type": "number",
+                    "example": 130
+                },
+                "name": {
+                    "description": "Human-readable food name.",
+                    "type": "string",
+                    "example": "Rice"
+                },
+                "protein_per_100g": {
+                    "description": "Protein grams per 100g.",
+                    "type": "number",
+                    "example": 2.7
+                },
+                "tags": {
+                    "description": "Optional free-form tags; stored lowercased without duplicates.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "breakfast",
+                        "high protein"
+                    ]
+                },
+                "translations": {
+                    "description": "Optional names in other languages, with synonyms search also matches.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/locale.Translation"
+                    }
+                },
+                "visibility": {
+                    "description": "Optional visibility; defaults to private. Only admins may create verified foods.",
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "public",
+                        "verified"
+                    ],
+                    "example": "public"
+                }
+            }
+        },
+        "dto.CreateMealRequest": {
+            "type": "object",
+            "properties": {
+                "eaten_at": {
+                    "description": "Meal time in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "items": {
+                    "description": "Optional items created atomically with meal.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AddMealItemRequest"
+                    }
+                },
+                "meal_type": {
+                    "description": "Meal type enum.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/meal.MealType"
+                        }
+                    ],
+                    "example": "lunch"
+                }
+            }
+        },
+        "dto.CreateRecipeRequest": {
+            "type": "object",
+            "properties": {
+                "cook_time_min": {
+                    "description": "Optional cooking time in minutes, up to a week.",
+                    "type": "integer",
+                    "example": 90
+                },
+                "description": {
+                    "description": "Optional description, up to 2000 characters.",
+                    "type": "string",
+                    "example": "Hearty Hungarian beef stew."
+                },
+                "ingredients": {
+                    "description": "Ingredient list used for nutrition calculation.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RecipeIngredientRequest"
+                    }
+                },
+                "instructions": {
+                    "description": "Optional instruction steps in cooking order, up to 50.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Brown the beef.",
+                        "Add paprika and stock and simmer for 90 minutes."
+                    ]
+                },
+                "name": {
+                    "description": "Human-readable recipe name.",
+                    "type": "string",
+                    "example": "Rice Bowl"
+                },
+                "prep_time_min": {
+                    "description": "Optional preparation time in minutes, up to a week.",
+                    "type": "integer",
+                    "example": 20
+                },
+                "serving_name": {
+                    "description": "Optional name of one serving; defaults to \"serving\".",
+                    "type": "string",
+                    "example": "bowl"
+                },
+                "servings": {
+                    "description": "Optional number of equal servings the yield makes; defaults to 1.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "tags": {
+                    "description": "Optional free-form tags; stored lowercased without duplicates.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dinner",
+                        "hungarian"
+                    ]
+                },
+                "translations": {
+                    "description": "Optional names in other languages, with synonyms search also matches.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/locale.Translation"
+                    }
+                },
+                "yield_weight_g": {
+                    "description": "Final cooked yield weight in grams; 0 or omitted estimates it from the ingredients and their cooking methods.",
+                    "type": "number",
+                    "example": 200
+                }
+            }
+        },
+        "dto.CreateShareLinkRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "When the link stops opening, in RFC3339 UTC. The link never expires when omitted.",
+                    "type": "string",
+                    "example": "2026-03-01T00:00:00Z"
+                }
+            }
+        },
+        "dto.CreateShoppingListRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "First day of planned meals to shop for (YYYY-MM-DD), with to.",
+                    "type": "string",
+                    "example": "2026-02-16"
+                },
+                "name": {
+                    "description": "Optional list name, \"Shopping list\" when omitted.",
+                    "type": "string",
+                    "example": "Weekend cooking"
+                },
+                "recipes": {
+                    "description": "Recipes to shop for. Leave empty to use planned meals instead.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ShoppingListRecipeRequest"
+                    }
+                },
+                "to": {
+                    "description": "Last day of planned meals to shop for (YYYY-MM-DD), included.",
+                    "type": "string",
+                    "example": "2026-02-22"
+                }
+            }
+        },
+        "dto.ImportRecipeRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Web page HTML, schema.org Recipe JSON-LD, or an ingredient list with one ingredient per line.",
+                    "type": "string",
+                    "example": "200 g chicken breast\n1 cup rice\n2 tbsp olive oil"
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@gmail.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "Pass1234!"
+                }
+            }
+        },
+        "dto.MagicLinkConsumeRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "Qm9uYXBwZXRpdA..."
+                }
+            }
+        },
+        "dto.MagicLinkRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@gmail.com"
+                }
+            }
+        },
+        "dto.MergeFoodsRequest": {
+            "type": "object",
+            "properties": {
+                "food_ids": {
+                    "description": "Foods folded into the food in the path. They are archived after the merge.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        15
+                    ]
+                }
+            }
+        },
+        "dto.OIDCFinishRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "` + "`" + `code` + "`" + ` query parameter the provider sent to the redirect URL.",
+                    "type": "string",
+                    "example": "SplxlOBeZQQYbYS6WxSbIA"
+                },
+                "state": {
+                    "description": "` + "`" + `state` + "`" + ` query parameter the provider sent to the redirect URL.",
+                    "type": "string",
+                    "example": "af0ifjsldkj"
+                }
+            }
+        },
+        "dto.PasskeyLoginRequest": {
+            "type": "object",
+            "properties": {
+                "credential": {
+                    "description": "PublicKeyCredential.toJSON() result of navigator.credentials.get().",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/webauthn.AssertionResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.PasskeyRegistrationRequest": {
+            "type": "object",
+            "properties": {
+                "credential": {
+                    "description": "PublicKeyCredential.toJSON() result of navigator.credentials.create().",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/webauthn.RegistrationResponse"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "Label shown in the passkey list.",
+                    "type": "string",
+                    "example": "MacBook Touch ID"
+                }
+            }
+        },
+        "dto.RecipeIngredientRequest": {
+            "type": "object",
+            "properties": {
+                "cooking_method_id": {
+                    "description": "Optional cooking method the food is cooked by; not for sub-recipes.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "food_id": {
+                    "description": "Existing food ID used as ingredient source.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "note": {
+                    "description": "Optional preparation note, up to 200 characters.",
+                    "type": "string",
+                    "example": "diced"
+                },
+                "position": {
+                    "description": "Optional ordering position.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "raw_weight_g": {
+                    "description": "Raw ingredient weight in grams.",
+                    "type": "number",
+                    "example": 200
+                },
+                "sub_recipe_id": {
+                    "description": "Existing recipe ID used as ingredient source instead of a food.",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "dto.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "9f5v..."
+                }
+            }
+        },
+        "dto.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "activity_level": {
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "birth_date": {
+                    "type": "string",
+                    "example": "1994-05-18"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john@gmail.com"
+                },
+                "height_cm": {
+                    "type": "number",
+                    "example": 178
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "Pass1234!"
+                },
+                "sex": {
+                    "type": "string",
+                    "example": "male"
+                }
+            }
+        },
+        "dto.ShoppingListRecipeRequest": {
+            "type": "object",
+            "properties": {
+                "recipe_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "servings": {
+                    "description": "Servings to cook, one batch when omitted.",
+                    "type": "number",
+                    "example": 6
+                }
+            }
+        },
+        "dto.UpdateFoodRequest": {
+            "type": "object",
+            "properties": {
+                "alcohol_per_100g": {
+                    "description": "Optional alcohol grams per 100g.",
+                    "type": "number",
+                    "example": 0
+                },
+                "allergens": {
+                    "description": "Optional allergens replacing the current ones.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "milk"
+                    ]
+                },
+                "barcode": {
+                    "description": "Optional product barcode (EAN/UPC digits).",
+                    "type": "string",
+                    "example": "5901234123457"
+                },
+                "brand_name": {
+                    "description": "Optional brand name.",
+                    "type": "string",
+                    "example": "Fage"
+                },
+                "carbs_per_100g": {
+                    "description": "Optional carbohydrate grams per 100g.",
+                    "type": "number",
+                    "example": 28
+                },
+                "category_id": {
+                    "description": "Optional category ID; 0 removes the food from its category.",
+                    "type": "integer",
+                    "example": 16
+                },
+                "diet_flags": {
+                    "description": "Optional diet flags replacing the current ones.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian",
+                        "gluten_free"
+                    ]
+                },
+                "fat_per_100g": {
+                    "description": "Optional fat grams per 100g.",
+                    "type": "number",
+                    "example": 0.3
+                },
+                "kcal_per_100g": {
+                    "description": "Optional energy in kcal per 100g.",
+                    "type": "number",
+                    "example": 130
+                },
+                "name": {
+                    "description": "Optional food name.",
+                    "type": "string",
+                    "example": "Cooked Rice"
+                },
+                "protein_per_100g": {
+                    "description": "Optional protein grams per 100g.",
+                    "type": "number",
+                    "example": 2.7
+                },
+                "tags": {
+                    "description": "Optional tags replacing the current ones; an empty list removes all tags.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "breakfast",
+                        "high protein"
+                    ]
+                },
+                "translations": {
+                    "description": "Optional translations replacing the current ones; an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/locale.Translation"
+                    }
+                },
+                "visibility": {
+                    "description": "Optional visibility. Only admins may set verified.",
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "public",
+                        "verified"
+                    ],
+                    "example": "public"
+                }
+            }
+        },
+        "dto.UpdateMeRequest": {
+            "type": "object",
+            "properties": {
+                "activity_level": {
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "avoid_allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "peanuts",
+                        "milk"
+                    ]
+                },
+                "birth_date": {
+                    "type": "string",
+                    "example": "1994-05-18"
+                },
+                "height_cm": {
+                    "type": "number",
+                    "example": 178
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "de",
+                        "en"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "required_diet_flags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian"
+                    ]
+                },
+                "sex": {
+                    "type": "string",
+                    "example": "male"
+                }
+            }
+        },
+        "dto.UpdateMealItemRequest": {
+            "type": "object",
+            "properties": {
+                "cooked_weight_g": {
+                    "description": "Optional consumed weight of the item's food after cooking.",
+                    "type": "number",
+                    "example": 250
+                },
+                "cooking_method_id": {
+                    "description": "Optional cooking method the item's food was cooked by.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "food_id": {
+                    "description": "Optional food source ID (mutually exclusive with recipe_id).",
+                    "type": "integer",
+                    "example": 1
+                },
+                "recipe_id": {
+                    "description": "Optional recipe source ID (mutually exclusive with food_id).",
+                    "type": "integer",
+                    "example": 1
+                },
+                "servings": {
+                    "description": "Optional consumed servings of the item's recipe.",
+                    "type": "number",
+                    "example": 2
+                },
+                "weight_g": {
+                    "description": "Optional consumed weight in grams (mutually exclusive with servings).",
+                    "type": "number",
+                    "example": 180
+                }
+            }
+        },
+        "dto.UpdateMealRequest": {
+            "type": "object",
+            "properties": {
+                "eaten_at": {
+                    "description": "Optional meal time in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T19:00:00Z"
+                },
+                "meal_type": {
+                    "description": "Optional meal type enum.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/meal.MealType"
+                        }
+                    ],
+                    "example": "dinner"
+                }
+            }
+        },
+        "dto.UpdateRecipeRequest": {
+            "type": "object",
+            "properties": {
+                "cook_time_min": {
+                    "description": "Optional cooking time in minutes; 0 removes it.",
+                    "type": "integer",
+                    "example": 90
+                },
+                "description": {
+                    "description": "Optional description; an empty one removes it.",
+                    "type": "string",
+                    "example": "Hearty Hungarian beef stew."
+                },
+                "ingredients": {
+                    "description": "Optional full replacement of ingredient list.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RecipeIngredientRequest"
+                    }
+                },
+                "instructions": {
+                    "description": "Optional instruction steps replacing the current ones; an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Brown the beef.",
+                        "Add paprika and stock and simmer for 90 minutes."
+                    ]
+                },
+                "name": {
+                    "description": "Optional recipe name.",
+                    "type": "string",
+                    "example": "Updated Rice Bowl"
+                },
+                "prep_time_min": {
+                    "description": "Optional preparation time in minutes; 0 removes it.",
+                    "type": "integer",
+                    "example": 20
+                },
+                "serving_name": {
+                    "description": "Optional serving name; an empty one resets it to \"serving\".",
+                    "type": "string",
+                    "example": "bowl"
+                },
+                "servings": {
+                    "description": "Optional number of equal servings the yield makes.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "tags": {
+                    "description": "Optional tags replacing the current ones; an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dinner",
+                        "hungarian"
+                    ]
+                },
+                "translations": {
+                    "description": "Optional translations replacing the current ones; an empty list removes them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/locale.Translation"
+                    }
+                },
+                "yield_weight_g": {
+                    "description": "Optional final cooked yield weight in grams; 0 estimates it from the ingredients.",
+                    "type": "number",
+                    "example": 210
+                }
+            }
+        },
+        "dto.UpdateShoppingListItemRequest": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "description": "Whether the item was bought.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.UpsertUserGoalRequest": {
+            "type": "object",
+            "properties": {
+                "activity_level": {
+                    "description": "Optional activity level: sedentary|light|moderate|active|very_active.",
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "target_carbs_g": {
+                    "description": "Daily carbs target in grams.",
+                    "type": "number",
+                    "example": 220
+                },
+                "target_fat_g": {
+                    "description": "Daily fat target in grams.",
+                    "type": "number",
+                    "example": 70
+                },
+                "target_kcal": {
+                    "description": "Daily kcal target.",
+                    "type": "number",
+                    "example": 2200
+                },
+                "target_protein_g": {
+                    "description": "Daily protein target in grams.",
+                    "type": "number",
+                    "example": 150
+                },
+                "weight_goal_kg": {
+                    "description": "Optional body weight goal in kilograms.",
+                    "type": "number",
+                    "example": 80
+                }
+            }
+        },
+        "handlers.APIError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Stable machine-readable error code.",
+                    "type": "string",
+                    "example": "invalid_food_payload"
+                },
+                "message": {
+                    "description": "Human-readable error message.",
+                    "type": "string",
+                    "example": "invalid food payload"
+                },
+                "request_id": {
+                    "description": "Request ID for log correlation.",
+                    "type": "string",
+                    "example": "f328f2f67798/SrQv8xTxwA-000001"
+                }
+            }
+        },
+        "handlers.BodyWeightLogResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T08:00:00Z"
+                },
+                "id": {
+                    "description": "Body weight log ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "logged_at": {
+                    "description": "Measurement timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T08:00:00Z"
+                },
+                "updated_at": {
+                    "description": "Last update timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T08:00:00Z"
+                },
+                "user_id": {
+                    "description": "Owner user ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "weight_kg": {
+                    "description": "Body weight in kilograms.",
+                    "type": "number",
+                    "example": 85.2
+                }
+            }
+        },
+        "handlers.BreakdownItemResponse": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "description": "Nutrients this item adds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutrientAmountsResponse"
+                        }
+                    ]
+                },
+                "display_name": {
+                    "description": "Name in the first of the caller's languages it is translated into, else name.",
+                    "type": "string",
+                    "example": "Olivenöl"
+                },
+                "food_id": {
+                    "description": "Food ID; absent for recipes.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "description": "Recipe ingredient or meal item ID.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "description": "Food or recipe name; empty for foods the caller may not see.",
+                    "type": "string",
+                    "example": "Olive oil"
+                },
+                "recipe_id": {
+                    "description": "Recipe ID when a recipe is used.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "share": {
+                    "description": "Percentage of each total this item adds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutrientSharesResponse"
+                        }
+                    ]
+                },
+                "weight_g": {
+                    "description": "Weight in grams, raw for recipe ingredients.",
+                    "type": "number",
+                    "example": 10
+                }
+            }
+        },
+        "handlers.CategoryFacetResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "description": "Category ID.",
+                    "type": "integer",
+                    "example": 16
+                },
+                "count": {
+                    "description": "Number of matching foods directly in this category.",
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "description": "Category name.",
+                    "type": "string",
+                    "example": "Yogurt"
+                },
+                "slug": {
+                    "description": "Category slug.",
+                    "type": "string",
+                    "example": "yogurt"
+                }
+            }
+        },
+        "handlers.CookingFactorResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "description": "Food category the factor applies to, with its subcategories; null for the method's default.",
+                    "type": "integer",
+                    "example": 21
+                },
+                "moisture_retention": {
+                    "description": "Share of the raw water still there after cooking; above 1 for foods that take up water.",
+                    "type": "number",
+                    "example": 13
+                },
+                "yield_factor": {
+                    "description": "Cooked weight over raw weight.",
+                    "type": "number",
+                    "example": 2.2
+                }
+            }
+        },
+        "handlers.CookingMethodResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Creation timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "factors": {
+                    "description": "Factors per food category, the method's default first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CookingFactorResponse"
+                    }
+                },
+                "id": {
+                    "description": "Cooking method ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "description": "Display name.",
+                    "type": "string",
+                    "example": "Boiled"
+                },
+                "slug": {
+                    "description": "Stable identifier.",
+                    "type": "string",
+                    "example": "boiled"
+                }
+            }
+        },
+        "handlers.DailyProgressResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Target date in YYYY-MM-DD.",
+                    "type": "string",
+                    "example": "2026-02-17"
+                },
+                "remaining_carbs_g": {
+                    "description": "Remaining carbs grams (target - total).",
+                    "type": "number",
+                    "example": 20
+                },
+                "remaining_fat_g": {
+                    "description": "Remaining fat grams (target - total).",
+                    "type": "number",
+                    "example": 10
+                },
+                "remaining_kcal": {
+                    "description": "Remaining kcal (target - total).",
+                    "type": "number",
+                    "example": 350
+                },
+                "remaining_protein_g": {
+                    "description": "Remaining protein grams (target - total).",
+                    "type": "number",
+                    "example": 30
+                },
+                "target_carbs_g": {
+                    "description": "Target carbohydrate grams for the day.",
+                    "type": "number",
+                    "example": 220
+                },
+                "target_fat_g": {
+                    "description": "Target fat grams for the day.",
+                    "type": "number",
+                    "example": 70
+                },
+                "target_kcal": {
+                    "description": "Target kcal for the day.",
+                    "type": "number",
+                    "example": 2200
+                },
+                "target_protein_g": {
+                    "description": "Target protein grams for the day.",
+                    "type": "number",
+                    "example": 150
+                },
+                "total_carbs_g": {
+                    "description": "Aggregated carbohydrate grams for the day.",
+                    "type": "number",
+                    "example": 200
+                },
+                "total_fat_g": {
+                    "description": "Aggregated fat grams for the day.",
+                    "type": "number",
+                    "example": 60
+                },
+                "total_kcal": {
+                    "description": "Aggregated kcal for the day.",
+                    "type": "number",
+                    "example": 1850
+                },
+                "total_protein_g": {
+                    "description": "Aggregated protein grams for the day.",
+                    "type": "number",
+                    "example": 120
+                }
+            }
+        },
+        "handlers.DailyTotalsResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Target date in YYYY-MM-DD.",
+                    "type": "string",
+                    "example": "2026-02-17"
+                },
+                "total_carbs_g": {
+                    "description": "Aggregated carbohydrate grams for the day.",
+                    "type": "number",
+                    "example": 220
+                },
+                "total_fat_g": {
+                    "description": "Aggregated fat grams for the day.",
+                    "type": "number",
+                    "example": 70
+                },
+                "total_kcal": {
+                    "description": "Aggregated kcal for the day.",
+                    "type": "number",
+                    "example": 2100
+                },
+                "total_protein_g": {
+                    "description": "Aggregated protein grams for the day.",
+                    "type": "number",
+                    "example": 140
+                }
+            }
+        },
+        "handlers.DietaryConflictResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Kind of restriction broken.",
+                    "type": "string",
+                    "enum": [
+                        "allergen",
+                        "diet"
+                    ],
+                    "example": "allergen"
+                },
+                "message": {
+                    "description": "Human-readable explanation.",
+                    "type": "string",
+                    "example": "contains peanuts, which you avoid"
+                },
+                "value": {
+                    "description": "Allergen contained or diet flag missing.",
+                    "type": "string",
+                    "example": "peanuts"
+                }
+            }
+        },
+        "handlers.DraftIngredientResponse": {
+            "type": "object",
+            "properties": {
+                "approximate_weight": {
+                    "description": "True when the weight was converted from a volume as water.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "food_id": {
+                    "description": "Best matching food, set when its confidence reaches 0.6.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "matches": {
+                    "description": "Up to three candidate foods, best first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FoodMatchResponse"
+                    }
+                },
+                "name": {
+                    "description": "Ingredient name without quantity, unit or preparation notes.",
+                    "type": "string",
+                    "example": "rice"
+                },
+                "quantity": {
+                    "description": "Parsed quantity; omitted when the line has none.",
+                    "type": "number",
+                    "example": 1
+                },
+                "raw_weight_g": {
+                    "description": "Weight in grams; omitted when the unit does not convert to grams.",
+                    "type": "number",
+                    "example": 240
+                },
+                "text": {
+                    "description": "Ingredient line as given.",
+                    "type": "string",
+                    "example": "1 cup rice"
+                },
+                "unit": {
+                    "description": "Canonical unit; omitted for counted items such as \"2 eggs\".",
+                    "type": "string",
+                    "example": "cup"
+                }
+            }
+        },
+        "handlers.EnergyProgressResponse": {
+            "type": "object",
+            "properties": {
+                "avg_intake_kcal": {
+                    "type": "number",
+                    "example": 2150
+                },
+                "data_quality_score": {
+                    "type": "number",
+                    "example": 0.78
+                },
+                "formula_tdee_kcal": {
+                    "type": "number",
+                    "example": 2500
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2026-01-22"
+                },
+                "observed_tdee_kcal": {
+                    "type": "number",
+                    "example": 2425
+                },
+                "recommended_tdee_kcal": {
+                    "type": "number",
+                    "example": 2460
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-02-18"
+                },
+                "weight_trend_kg_per_week": {
+                    "type": "number",
+                    "example": -0.25
+                }
+            }
+        },
+        "handlers.ErrorEnvelope": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/handlers.APIError"
+                }
+            }
+        },
+        "handlers.FoodCategoryResponse": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "Subcategories.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FoodCategoryResponse"
+                    }
+                },
+                "created_at": {
+                    "description": "Creation timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "id": {
+                    "description": "Category ID.",
+                    "type": "integer",
+                    "example": 16
+                },
+                "name": {
+                    "description": "Display name.",
+                    "type": "string",
+                    "example": "Yogurt"
+                },
+                "parent_id": {
+                    "description": "Parent category; omitted for top-level categories.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "slug": {
+                    "description": "Stable identifier.",
+                    "type": "string",
+                    "example": "yogurt"
+                }
+            }
+        },
+        "handlers.FoodDuplicateResponse": {
+            "type": "object",
+            "properties": {
+                "barcode_match": {
+                    "description": "True when both foods share a barcode.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "brand_score": {
+                    "description": "Brand similarity; omitted unless both foods have a brand.",
+                    "type": "number",
+                    "example": 1
+                },
+                "food": {
+                    "description": "Candidate duplicate.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.FoodResponse"
+                        }
+                    ]
+                },
+                "name_score": {
+                    "description": "Name similarity between 0 and 1.",
+                    "type": "number",
+                    "example": 0.82
+                },
+                "nutrition_score": {
+                    "description": "Nutrition similarity between 0 and 1.",
+                    "type": "number",
+                    "example": 0.95
+                },
+                "score": {
+                    "description": "Combined similarity between 0 and 1.",
+                    "type": "number",
+                    "example": 0.87
+                }
+            }
+        },
+        "handlers.FoodFacetsResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "description": "Matches per category, largest first. Uncategorized foods are not counted.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CategoryFacetResponse"
+                    }
+                },
+                "tags": {
+                    "description": "Matches per tag for the 20 most used tags, largest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TagFacetResponse"
+                    }
+                }
+            }
+        },
+        "handlers.FoodMatchResponse": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "description": "Name similarity between 0 and 1.",
+                    "type": "number",
+                    "example": 0.92
+                },
+                "food": {
+                    "description": "Candidate food.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.FoodResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.FoodMergeLogResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "When the merge ran, in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "id": {
+                    "description": "Merge log ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "meal_items_moved": {
+                    "description": "Meal items moved to the survivor.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "merged_by": {
+                    "description": "Admin who ran the merge; omitted if that user was deleted.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "merged_food_id": {
+                    "description": "Food that was folded in and archived.",
+                    "type": "integer",
+                    "example": 12
+                },
+                "recipe_ingredients_moved": {
+                    "description": "Recipe ingredients moved to the survivor.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "survivor_food_id": {
+                    "description": "Food that was kept.",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "handlers.FoodMergeResponse": {
+            "type": "object",
+            "properties": {
+                "merges": {
+                    "description": "One log entry per merged food.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FoodMergeLogResponse"
+                    }
+                },
+                "recalculated_recipe_ids": {
+                    "description": "Recipes recalculated because their ingredients moved; ones that failed stay stale.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2,
+                        5
+                    ]
                 },
-                "weight_g": {
-                    "description": "Item consumed weight in grams.",
-                    "type": "number",
-                    "example": 150
+                "survivor": {
+                    "description": "Food that was kept.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.FoodResponse"
+                        }
+                    ]
                 }
             }
         },
-        "dto.CreateBodyWeightLogRequest": {
+        "handlers.FoodNutritionIssueResponse": {
             "type": "object",
             "properties": {
-                "logged_at": {
-                    "description": "Measurement timestamp in RFC3339 UTC.",
-                    "type": "string",
-                    "example": "2026-02-17T08:00:00Z"
-                },
-                "weight_kg": {
-                    "description": "Body weight in kilograms.",
+                "estimated_kcal": {
+                    "description": "Energy estimated from macros with the Atwater factors.",
                     "type": "number",
-                    "example": 85.2
+                    "example": 360
+                },
+                "food": {
+                    "description": "Food with inconsistent nutrition.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.FoodResponse"
+                        }
+                    ]
+                },
+                "warnings": {
+                    "description": "Checks the food fails.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.NutritionWarningResponse"
+                    }
                 }
             }
         },
-        "dto.CreateFoodRequest": {
+        "handlers.FoodResponse": {
             "type": "object",
             "properties": {
+                "alcohol_per_100g": {
+                    "description": "Alcohol grams per 100g.",
+                    "type": "number",
+                    "example": 0
+                },
+                "allergens": {
+                    "description": "EU major allergens the food contains, sorted.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "milk"
+                    ]
+                },
+                "archived_at": {
+                    "description": "Set when the food was archived; archived foods are hidden from lists and search.",
+                    "type": "string",
+                    "example": "2026-02-18T09:00:00Z"
+                },
                 "barcode": {
-                    "description": "Optional product barcode (EAN/UPC digits).",
+                    "description": "Optional product barcode.",
                     "type": "string",
                     "example": "5901234123457"
                 },
@@ -1816,18 +5893,64 @@ const docTemplate = `{
                     "type": "number",
                     "example": 28
                 },
+                "category_id": {
+                    "description": "Category from GET /food-categories.",
+                    "type": "integer",
+                    "example": 16
+                },
+                "created_at": {
+                    "description": "Creation timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "diet_flags": {
+                    "description": "Diet flags, sorted; vegan implies vegetarian.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "gluten_free",
+                        "vegetarian"
+                    ]
+                },
+                "display_name": {
+                    "description": "Name in the first of the caller's languages the food is translated into, else name.",
+                    "type": "string",
+                    "example": "Reis"
+                },
                 "fat_per_100g": {
                     "description": "Fat grams per 100g.",
                     "type": "number",
                     "example": 0.3
                 },
+                "id": {
+                    "description": "Food ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "image_url": {
+                    "description": "Uploaded image, shrunk to at most 1600px on its longest edge.",
+                    "type": "string",
+                    "example": "http://localhost:8080/media/foods/1/5f2c9a0e7b1d4c3a8e6f0b21.jpg"
+                },
+                "imported_at": {
+                    "description": "When the food was imported from an external catalog.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
                 "kcal_per_100g": {
                     "description": "Energy in kcal per 100g.",
                     "type": "number",
                     "example": 130
                 },
+                "merged_into_id": {
+                    "description": "Set when the food was merged into another one.",
+                    "type": "integer",
+                    "example": 4
+                },
                 "name": {
-                    "description": "Human-readable food name.",
+                    "description": "Food name.",
                     "type": "string",
                     "example": "Rice"
                 },
@@ -1835,777 +5958,1390 @@ const docTemplate = `{
                     "description": "Protein grams per 100g.",
                     "type": "number",
                     "example": 2.7
+                },
+                "source": {
+                    "description": "Where the food came from: user for API entries, otherwise the external catalog it was imported from.",
+                    "type": "string",
+                    "example": "open_food_facts"
+                },
+                "source_ref": {
+                    "description": "Link to the entry in the source catalog.",
+                    "type": "string",
+                    "example": "https://world.openfoodfacts.org/product/5901234123457"
+                },
+                "tags": {
+                    "description": "Free-form lowercase tags.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "breakfast",
+                        "high protein"
+                    ]
+                },
+                "thumbnail_url": {
+                    "description": "320px JPEG thumbnail of the image.",
+                    "type": "string",
+                    "example": "http://localhost:8080/media/foods/1/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"
+                },
+                "translations": {
+                    "description": "Names in other languages, sorted by locale.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TranslationResponse"
+                    }
+                },
+                "updated_at": {
+                    "description": "Last update timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "version": {
+                    "description": "Current version number; increases on every edit.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "visibility": {
+                    "description": "Who can see the food.",
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "public",
+                        "verified"
+                    ],
+                    "example": "public"
+                },
+                "warnings": {
+                    "description": "Failed nutrition checks; only returned by create and update in lenient mode.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.NutritionWarningResponse"
+                    }
                 }
             }
         },
-        "dto.CreateMealRequest": {
+        "handlers.FoodSearchResponse": {
             "type": "object",
             "properties": {
-                "eaten_at": {
-                    "description": "Meal time in RFC3339 UTC.",
-                    "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
+                "facets": {
+                    "description": "Counts over all matches, not just this page.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.FoodFacetsResponse"
+                        }
+                    ]
                 },
                 "items": {
-                    "description": "Optional items created atomically with meal.",
+                    "description": "Page of matching foods.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.AddMealItemRequest"
+                        "$ref": "#/definitions/handlers.FoodResponse"
                     }
+                }
+            }
+        },
+        "handlers.FoodVersionResponse": {
+            "type": "object",
+            "properties": {
+                "alcohol_per_100g": {
+                    "description": "Alcohol grams per 100g.",
+                    "type": "number",
+                    "example": 0
                 },
-                "meal_type": {
-                    "description": "Meal type enum.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/meal.MealType"
-                        }
+                "barcode": {
+                    "description": "Optional product barcode.",
+                    "type": "string",
+                    "example": "5901234123457"
+                },
+                "brand_name": {
+                    "description": "Optional brand name.",
+                    "type": "string",
+                    "example": "Fage"
+                },
+                "carbs_per_100g": {
+                    "description": "Carbohydrate grams per 100g.",
+                    "type": "number",
+                    "example": 66.3
+                },
+                "created_at": {
+                    "description": "When the version was recorded, in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "edited_by": {
+                    "description": "User who made the change; omitted if that user was deleted.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "fat_per_100g": {
+                    "description": "Fat grams per 100g.",
+                    "type": "number",
+                    "example": 6.9
+                },
+                "food_id": {
+                    "description": "Food ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "description": "Version row ID.",
+                    "type": "integer",
+                    "example": 7
+                },
+                "kcal_per_100g": {
+                    "description": "Energy in kcal per 100g.",
+                    "type": "number",
+                    "example": 389
+                },
+                "name": {
+                    "description": "Food name.",
+                    "type": "string",
+                    "example": "Oats"
+                },
+                "protein_per_100g": {
+                    "description": "Protein grams per 100g.",
+                    "type": "number",
+                    "example": 16.9
+                },
+                "restored_from": {
+                    "description": "Set when this version restored an earlier one.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "version": {
+                    "description": "Version number, starting at 1.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "visibility": {
+                    "description": "Visibility at this version.",
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "public",
+                        "verified"
                     ],
-                    "example": "lunch"
+                    "example": "public"
                 }
             }
         },
-        "dto.CreateRecipeRequest": {
+        "handlers.HealthResponse": {
             "type": "object",
             "properties": {
-                "ingredients": {
-                    "description": "Ingredient list used for nutrition calculation.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.RecipeIngredientRequest"
-                    }
+                "message": {
+                    "description": "Human-readable health message.",
+                    "type": "string",
+                    "example": "goal-bite api is running"
                 },
-                "name": {
-                    "description": "Human-readable recipe name.",
+                "status": {
+                    "description": "Service status.",
                     "type": "string",
-                    "example": "Rice Bowl"
+                    "example": "ok"
+                }
+            }
+        },
+        "handlers.IngredientChangeResponse": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "description": "Kind of change.",
+                    "type": "string",
+                    "enum": [
+                        "added",
+                        "removed",
+                        "changed"
+                    ],
+                    "example": "changed"
                 },
-                "yield_weight_g": {
-                    "description": "Final cooked yield weight in grams.",
+                "food_id": {
+                    "description": "Ingredient food ID; absent for sub-recipes.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "from_note": {
+                    "description": "Original note, when there was one.",
+                    "type": "string",
+                    "example": "cubed"
+                },
+                "from_raw_weight_g": {
+                    "description": "Original raw weight in grams; absent for added ingredients.",
                     "type": "number",
-                    "example": 200
+                    "example": 500
+                },
+                "sub_recipe_id": {
+                    "description": "Ingredient recipe ID when another recipe is used.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "to_note": {
+                    "description": "Fork note, when there is one.",
+                    "type": "string",
+                    "example": "minced"
+                },
+                "to_raw_weight_g": {
+                    "description": "Fork raw weight in grams; absent for removed ingredients.",
+                    "type": "number",
+                    "example": 400
                 }
             }
         },
-        "dto.LoginRequest": {
+        "handlers.MacroSplitResponse": {
             "type": "object",
             "properties": {
-                "email": {
+                "carbs_pct": {
+                    "description": "Percentage of calories from carbohydrates.",
+                    "type": "number",
+                    "example": 65.7
+                },
+                "fat_pct": {
+                    "description": "Percentage of calories from fat.",
+                    "type": "number",
+                    "example": 28
+                },
+                "protein_pct": {
+                    "description": "Percentage of calories from protein.",
+                    "type": "number",
+                    "example": 6.3
+                }
+            }
+        },
+        "handlers.MealItemResponse": {
+            "type": "object",
+            "properties": {
+                "carbs_per_100g": {
+                    "description": "Carbohydrate snapshot in g per 100g at log time.",
+                    "type": "number",
+                    "example": 28
+                },
+                "cooked_weight_g": {
+                    "description": "Weight of the food after cooking it was logged by; weight_g is the raw weight it converted to.",
+                    "type": "number",
+                    "example": 280
+                },
+                "cooking_method_id": {
+                    "description": "Cooking method the cooked weight is for.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "description": "Creation timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "john@gmail.com"
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "fat_per_100g": {
+                    "description": "Fat snapshot in g per 100g at log time.",
+                    "type": "number",
+                    "example": 0.3
+                },
+                "food_id": {
+                    "description": "Optional food source ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "food_version": {
+                    "description": "Food version the nutrition snapshot was taken from.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "id": {
+                    "description": "Meal item ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "kcal_per_100g": {
+                    "description": "Energy snapshot in kcal per 100g at log time.",
+                    "type": "number",
+                    "example": 130
                 },
-                "password": {
-                    "type": "string",
-                    "example": "Pass1234!"
-                }
-            }
-        },
-        "dto.RecipeIngredientRequest": {
-            "type": "object",
-            "properties": {
-                "food_id": {
-                    "description": "Existing food ID used as ingredient source.",
+                "meal_id": {
+                    "description": "Parent meal ID.",
                     "type": "integer",
                     "example": 1
                 },
-                "position": {
-                    "description": "Optional ordering position.",
+                "protein_per_100g": {
+                    "description": "Protein snapshot in g per 100g at log time.",
+                    "type": "number",
+                    "example": 2.7
+                },
+                "recipe_id": {
+                    "description": "Optional recipe source ID.",
                     "type": "integer",
                     "example": 1
                 },
-                "raw_weight_g": {
-                    "description": "Raw ingredient weight in grams.",
+                "servings": {
+                    "description": "Servings of the recipe the item was logged in; weight_g is what they converted to.",
                     "type": "number",
-                    "example": 200
-                }
-            }
-        },
-        "dto.RefreshTokenRequest": {
-            "type": "object",
-            "properties": {
-                "refresh_token": {
+                    "example": 1.5
+                },
+                "updated_at": {
+                    "description": "Last update timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "9f5v..."
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "warnings": {
+                    "description": "Dietary restrictions of the user the item breaks; only returned when the item is added.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DietaryConflictResponse"
+                    }
+                },
+                "water_change_g": {
+                    "description": "Estimated water the food took up while cooking, negative when it lost water.",
+                    "type": "number",
+                    "example": 180
+                },
+                "weight_g": {
+                    "description": "Consumed weight in grams.",
+                    "type": "number",
+                    "example": 150
                 }
             }
         },
-        "dto.RegisterRequest": {
+        "handlers.MealResponse": {
             "type": "object",
             "properties": {
-                "activity_level": {
-                    "type": "string",
-                    "example": "moderate"
+                "breakdown": {
+                    "description": "Contribution of each item; only with breakdown=true.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutritionBreakdownResponse"
+                        }
+                    ]
                 },
-                "birth_date": {
+                "created_at": {
+                    "description": "Creation timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "1994-05-18"
+                    "example": "2026-02-17T12:00:00Z"
                 },
-                "email": {
+                "eaten_at": {
+                    "description": "Meal timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "john@gmail.com"
+                    "example": "2026-02-17T12:00:00Z"
                 },
-                "height_cm": {
-                    "type": "number",
-                    "example": 178
+                "id": {
+                    "description": "Meal ID.",
+                    "type": "integer",
+                    "example": 1
                 },
-                "name": {
+                "image_url": {
+                    "description": "Uploaded photo, shrunk to at most 1600px on its longest edge.",
                     "type": "string",
-                    "example": "John Doe"
+                    "example": "http://localhost:8080/media/meals/5f2c9a0e7b1d4c3a8e6f0b21.jpg"
                 },
-                "password": {
-                    "type": "string",
-                    "example": "Pass1234!"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.MealItemResponse"
+                    }
                 },
-                "sex": {
-                    "type": "string",
-                    "example": "male"
-                }
-            }
-        },
-        "dto.UpdateFoodRequest": {
-            "type": "object",
-            "properties": {
-                "barcode": {
-                    "description": "Optional product barcode (EAN/UPC digits).",
+                "meal_type": {
+                    "description": "Meal type.",
                     "type": "string",
-                    "example": "5901234123457"
+                    "example": "lunch"
                 },
-                "brand_name": {
-                    "description": "Optional brand name.",
+                "thumbnail_url": {
+                    "description": "320px JPEG thumbnail of the photo.",
                     "type": "string",
-                    "example": "Fage"
+                    "example": "http://localhost:8080/media/meals/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"
                 },
-                "carbs_per_100g": {
-                    "description": "Optional carbohydrate grams per 100g.",
+                "total_carbs_g": {
+                    "description": "Aggregated carbohydrate grams for this meal.",
                     "type": "number",
-                    "example": 28
+                    "example": 75
                 },
-                "fat_per_100g": {
-                    "description": "Optional fat grams per 100g.",
+                "total_fat_g": {
+                    "description": "Aggregated fat grams for this meal.",
                     "type": "number",
-                    "example": 0.3
+                    "example": 2.1
                 },
-                "kcal_per_100g": {
-                    "description": "Optional energy in kcal per 100g.",
+                "total_kcal": {
+                    "description": "Aggregated kcal for this meal.",
                     "type": "number",
-                    "example": 130
+                    "example": 350
                 },
-                "name": {
-                    "description": "Optional food name.",
+                "total_protein_g": {
+                    "description": "Aggregated protein grams for this meal.",
+                    "type": "number",
+                    "example": 10.5
+                },
+                "updated_at": {
+                    "description": "Last update timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "Cooked Rice"
+                    "example": "2026-02-17T12:00:00Z"
                 },
-                "protein_per_100g": {
-                    "description": "Optional protein grams per 100g.",
-                    "type": "number",
-                    "example": 2.7
+                "user_id": {
+                    "description": "Owner user ID.",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "dto.UpdateMeRequest": {
+        "handlers.NutrientAmountsResponse": {
             "type": "object",
             "properties": {
-                "activity_level": {
-                    "type": "string",
-                    "example": "moderate"
-                },
-                "birth_date": {
-                    "type": "string",
-                    "example": "1994-05-18"
+                "carbs_g": {
+                    "description": "Carbohydrate grams.",
+                    "type": "number",
+                    "example": 0
                 },
-                "height_cm": {
+                "fat_g": {
+                    "description": "Fat grams.",
                     "type": "number",
-                    "example": 178
+                    "example": 10
                 },
-                "name": {
-                    "type": "string",
-                    "example": "John Doe"
+                "kcal": {
+                    "description": "Energy in kcal.",
+                    "type": "number",
+                    "example": 88.4
                 },
-                "sex": {
-                    "type": "string",
-                    "example": "male"
+                "protein_g": {
+                    "description": "Protein grams.",
+                    "type": "number",
+                    "example": 0
                 }
             }
         },
-        "dto.UpdateMealItemRequest": {
+        "handlers.NutrientSharesResponse": {
             "type": "object",
             "properties": {
-                "food_id": {
-                    "description": "Optional food source ID (mutually exclusive with recipe_id).",
-                    "type": "integer",
-                    "example": 1
+                "carbs_pct": {
+                    "description": "Percentage of the carbohydrate total.",
+                    "type": "number",
+                    "example": 0
                 },
-                "recipe_id": {
-                    "description": "Optional recipe source ID (mutually exclusive with food_id).",
-                    "type": "integer",
-                    "example": 1
+                "fat_pct": {
+                    "description": "Percentage of the fat total.",
+                    "type": "number",
+                    "example": 94.3
                 },
-                "weight_g": {
-                    "description": "Optional consumed weight in grams.",
+                "kcal_pct": {
+                    "description": "Percentage of the energy total.",
                     "type": "number",
-                    "example": 180
+                    "example": 25.4
+                },
+                "protein_pct": {
+                    "description": "Percentage of the protein total.",
+                    "type": "number",
+                    "example": 0
                 }
             }
         },
-        "dto.UpdateMealRequest": {
+        "handlers.NutritionBreakdownResponse": {
             "type": "object",
             "properties": {
-                "eaten_at": {
-                    "description": "Optional meal time in RFC3339 UTC.",
-                    "type": "string",
-                    "example": "2026-02-17T19:00:00Z"
-                },
-                "meal_type": {
-                    "description": "Optional meal type enum.",
+                "calorie_split": {
+                    "description": "Share of the energy from macros that each macro provides, by the Atwater factors.",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/meal.MealType"
+                            "$ref": "#/definitions/handlers.MacroSplitResponse"
                         }
-                    ],
-                    "example": "dinner"
-                }
-            }
-        },
-        "dto.UpdateRecipeRequest": {
-            "type": "object",
-            "properties": {
-                "ingredients": {
-                    "description": "Optional full replacement of ingredient list.",
+                    ]
+                },
+                "items": {
+                    "description": "One entry per recipe ingredient or meal item, in order.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.RecipeIngredientRequest"
+                        "$ref": "#/definitions/handlers.BreakdownItemResponse"
                     }
                 },
-                "name": {
-                    "description": "Optional recipe name.",
-                    "type": "string",
-                    "example": "Updated Rice Bowl"
-                },
-                "yield_weight_g": {
-                    "description": "Optional final cooked yield weight in grams.",
-                    "type": "number",
-                    "example": 210
+                "total": {
+                    "description": "Sum of the contributions.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutrientAmountsResponse"
+                        }
+                    ]
                 }
             }
         },
-        "dto.UpsertUserGoalRequest": {
+        "handlers.NutritionDeltaResponse": {
             "type": "object",
             "properties": {
-                "activity_level": {
-                    "description": "Optional activity level: sedentary|light|moderate|active|very_active.",
-                    "type": "string",
-                    "example": "moderate"
-                },
-                "target_carbs_g": {
-                    "description": "Daily carbs target in grams.",
+                "carbs_g": {
+                    "description": "Carbohydrate difference in grams.",
                     "type": "number",
-                    "example": 220
-                },
-                "target_fat_g": {
-                    "description": "Daily fat target in grams.",
-                    "type": "number",
-                    "example": 70
+                    "example": 0
                 },
-                "target_kcal": {
-                    "description": "Daily kcal target.",
+                "fat_g": {
+                    "description": "Fat difference in grams.",
                     "type": "number",
-                    "example": 2200
+                    "example": -1.5
                 },
-                "target_protein_g": {
-                    "description": "Daily protein target in grams.",
+                "kcal": {
+                    "description": "Energy difference in kcal.",
                     "type": "number",
-                    "example": 150
+                    "example": -25
                 },
-                "weight_goal_kg": {
-                    "description": "Optional body weight goal in kilograms.",
+                "protein_g": {
+                    "description": "Protein difference in grams.",
                     "type": "number",
-                    "example": 80
+                    "example": -2.6
                 }
             }
         },
-        "handlers.APIError": {
+        "handlers.NutritionWarningResponse": {
             "type": "object",
             "properties": {
                 "code": {
-                    "description": "Stable machine-readable error code.",
+                    "description": "Machine-readable check that failed.",
                     "type": "string",
-                    "example": "invalid_food_payload"
+                    "enum": [
+                        "kcal_mismatch",
+                        "macros_exceed_100g"
+                    ],
+                    "example": "kcal_mismatch"
                 },
                 "message": {
-                    "description": "Human-readable error message.",
-                    "type": "string",
-                    "example": "invalid food payload"
-                },
-                "request_id": {
-                    "description": "Request ID for log correlation.",
+                    "description": "Human-readable explanation with the values involved.",
                     "type": "string",
-                    "example": "f328f2f67798/SrQv8xTxwA-000001"
+                    "example": "50 kcal stated but macros give about 360 kcal"
                 }
             }
         },
-        "handlers.BodyWeightLogResponse": {
+        "handlers.PasskeyResponse": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "description": "Creation timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17T08:00:00Z"
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "credential_id": {
+                    "description": "WebAuthn credential ID (base64url).",
+                    "type": "string",
+                    "example": "q2xKcL8zTQ6m0mC3b3Vx1g"
+                },
+                "id": {
+                    "description": "Passkey ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_used_at": {
+                    "description": "Last successful login timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
+                },
+                "name": {
+                    "description": "Label chosen at registration.",
+                    "type": "string",
+                    "example": "MacBook Touch ID"
                 },
-                "id": {
-                    "description": "Body weight log ID.",
+                "sign_count": {
+                    "description": "Last signature counter reported by the authenticator.",
                     "type": "integer",
-                    "example": 1
+                    "example": 4
                 },
-                "logged_at": {
-                    "description": "Measurement timestamp in RFC3339 UTC.",
-                    "type": "string",
-                    "example": "2026-02-17T08:00:00Z"
+                "transports": {
+                    "description": "Transports reported by the browser at registration.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "internal",
+                        "hybrid"
+                    ]
                 },
                 "updated_at": {
                     "description": "Last update timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17T08:00:00Z"
+                    "example": "2026-02-17T12:00:00Z"
                 },
                 "user_id": {
                     "description": "Owner user ID.",
                     "type": "integer",
                     "example": 1
-                },
-                "weight_kg": {
-                    "description": "Body weight in kilograms.",
-                    "type": "number",
-                    "example": 85.2
                 }
             }
         },
-        "handlers.DailyProgressResponse": {
+        "handlers.RecipeChangesResponse": {
             "type": "object",
             "properties": {
-                "date": {
-                    "description": "Target date in YYYY-MM-DD.",
-                    "type": "string",
-                    "example": "2026-02-17"
-                },
-                "remaining_carbs_g": {
-                    "description": "Remaining carbs grams (target - total).",
-                    "type": "number",
-                    "example": 20
-                },
-                "remaining_fat_g": {
-                    "description": "Remaining fat grams (target - total).",
-                    "type": "number",
-                    "example": 10
-                },
-                "remaining_kcal": {
-                    "description": "Remaining kcal (target - total).",
-                    "type": "number",
-                    "example": 350
-                },
-                "remaining_protein_g": {
-                    "description": "Remaining protein grams (target - total).",
-                    "type": "number",
-                    "example": 30
-                },
-                "target_carbs_g": {
-                    "description": "Target carbohydrate grams for the day.",
-                    "type": "number",
-                    "example": 220
+                "fields": {
+                    "description": "Changed recipe fields by JSON name.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "name",
+                        "cook_time_min"
+                    ]
                 },
-                "target_fat_g": {
-                    "description": "Target fat grams for the day.",
-                    "type": "number",
-                    "example": 70
+                "ingredients": {
+                    "description": "Added, removed and changed ingredients.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.IngredientChangeResponse"
+                    }
                 },
-                "target_kcal": {
-                    "description": "Target kcal for the day.",
-                    "type": "number",
-                    "example": 2200
+                "origin_id": {
+                    "description": "Recipe the fork was copied from.",
+                    "type": "integer",
+                    "example": 3
                 },
-                "target_protein_g": {
-                    "description": "Target protein grams for the day.",
-                    "type": "number",
-                    "example": 150
+                "per_100g": {
+                    "description": "Fork per-100g values minus the original's.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutritionDeltaResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.RecipeDraftResponse": {
+            "type": "object",
+            "properties": {
+                "ingredients": {
+                    "description": "Parsed ingredient lines in source order.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DraftIngredientResponse"
+                    }
                 },
-                "total_carbs_g": {
-                    "description": "Aggregated carbohydrate grams for the day.",
-                    "type": "number",
-                    "example": 200
+                "name": {
+                    "description": "Recipe name from the source; empty for a pasted list.",
+                    "type": "string",
+                    "example": "Chicken rice bowl"
                 },
-                "total_fat_g": {
-                    "description": "Aggregated fat grams for the day.",
-                    "type": "number",
-                    "example": 60
+                "servings": {
+                    "description": "Servings stated by the source, or 1.",
+                    "type": "integer",
+                    "example": 2
                 },
-                "total_kcal": {
-                    "description": "Aggregated kcal for the day.",
+                "yield_weight_g": {
+                    "description": "Sum of the known ingredient weights in grams, to replace with the cooked weight.",
                     "type": "number",
-                    "example": 1850
+                    "example": 470
+                }
+            }
+        },
+        "handlers.RecipeForkResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "What the fork changed relative to the original.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.RecipeChangesResponse"
+                        }
+                    ]
                 },
-                "total_protein_g": {
-                    "description": "Aggregated protein grams for the day.",
-                    "type": "number",
-                    "example": 120
+                "recipe": {
+                    "description": "The fork.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.RecipeResponse"
+                        }
+                    ]
                 }
             }
         },
-        "handlers.DailyTotalsResponse": {
+        "handlers.RecipeIngredientResponse": {
             "type": "object",
             "properties": {
-                "date": {
-                    "description": "Target date in YYYY-MM-DD.",
+                "cooking_method_id": {
+                    "description": "Cooking method the food is cooked by; omitted when not given.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "description": "Creation timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17"
+                    "example": "2026-02-17T12:00:00Z"
                 },
-                "total_carbs_g": {
-                    "description": "Aggregated carbohydrate grams for the day.",
-                    "type": "number",
-                    "example": 220
+                "food_id": {
+                    "description": "Referenced food ID; absent for sub-recipes.",
+                    "type": "integer",
+                    "example": 1
                 },
-                "total_fat_g": {
-                    "description": "Aggregated fat grams for the day.",
-                    "type": "number",
-                    "example": 70
+                "food_version": {
+                    "description": "Food version the recipe totals were computed from.",
+                    "type": "integer",
+                    "example": 2
                 },
-                "total_kcal": {
-                    "description": "Aggregated kcal for the day.",
-                    "type": "number",
-                    "example": 2100
+                "id": {
+                    "description": "Ingredient row ID.",
+                    "type": "integer",
+                    "example": 1
                 },
-                "total_protein_g": {
-                    "description": "Aggregated protein grams for the day.",
+                "note": {
+                    "description": "Preparation note; omitted when empty.",
+                    "type": "string",
+                    "example": "diced"
+                },
+                "position": {
+                    "description": "Optional ordering position.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "raw_weight_g": {
+                    "description": "Raw ingredient weight in grams.",
                     "type": "number",
-                    "example": 140
+                    "example": 200
+                },
+                "recipe_id": {
+                    "description": "Parent recipe ID.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "sub_recipe_id": {
+                    "description": "Referenced recipe ID when another recipe is used as the ingredient.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "updated_at": {
+                    "description": "Last update timestamp in RFC3339 UTC.",
+                    "type": "string",
+                    "example": "2026-02-17T12:00:00Z"
                 }
             }
         },
-        "handlers.EnergyProgressResponse": {
+        "handlers.RecipePortionResponse": {
             "type": "object",
             "properties": {
-                "avg_intake_kcal": {
-                    "type": "number",
-                    "example": 2150
-                },
-                "data_quality_score": {
+                "carbs_g": {
+                    "description": "Carbohydrate grams.",
                     "type": "number",
-                    "example": 0.78
+                    "example": 28
                 },
-                "formula_tdee_kcal": {
+                "fat_g": {
+                    "description": "Fat grams.",
                     "type": "number",
-                    "example": 2500
-                },
-                "from": {
-                    "type": "string",
-                    "example": "2026-01-22"
+                    "example": 0.3
                 },
-                "observed_tdee_kcal": {
+                "kcal": {
+                    "description": "Energy in kcal.",
                     "type": "number",
-                    "example": 2425
+                    "example": 130
                 },
-                "recommended_tdee_kcal": {
+                "protein_g": {
+                    "description": "Protein grams.",
                     "type": "number",
-                    "example": 2460
-                },
-                "to": {
-                    "type": "string",
-                    "example": "2026-02-18"
+                    "example": 2.7
                 },
-                "weight_trend_kg_per_week": {
+                "weight_g": {
+                    "description": "Portion weight in grams.",
                     "type": "number",
-                    "example": -0.25
-                }
-            }
-        },
-        "handlers.ErrorEnvelope": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "$ref": "#/definitions/handlers.APIError"
+                    "example": 100
                 }
             }
         },
-        "handlers.FoodResponse": {
+        "handlers.RecipeResponse": {
             "type": "object",
             "properties": {
-                "barcode": {
-                    "description": "Optional product barcode.",
-                    "type": "string",
-                    "example": "5901234123457"
+                "allergens": {
+                    "description": "Allergens of any ingredient, derived from the current ingredient foods.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "milk"
+                    ]
                 },
-                "brand_name": {
-                    "description": "Optional brand name.",
+                "archived_at": {
+                    "description": "Set when the recipe was archived; archived recipes are hidden from lists and search.",
                     "type": "string",
-                    "example": "Fage"
+                    "example": "2026-02-18T09:00:00Z"
+                },
+                "breakdown": {
+                    "description": "Contribution of each ingredient to the whole batch; only with breakdown=true.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutritionBreakdownResponse"
+                        }
+                    ]
                 },
                 "carbs_per_100g": {
                     "description": "Carbohydrate grams per 100g.",
                     "type": "number",
                     "example": 28
                 },
+                "cook_time_min": {
+                    "description": "Cooking time in minutes; 0 when not given.",
+                    "type": "integer",
+                    "example": 90
+                },
                 "created_at": {
                     "description": "Creation timestamp in RFC3339 UTC.",
                     "type": "string",
                     "example": "2026-02-17T12:00:00Z"
                 },
+                "description": {
+                    "description": "Description; empty when not given.",
+                    "type": "string",
+                    "example": "Hearty Hungarian beef stew."
+                },
+                "diet_flags": {
+                    "description": "Diet flags all ingredients carry, derived from the current ingredient foods.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian"
+                    ]
+                },
+                "display_name": {
+                    "description": "Name in the first of the caller's languages the recipe is translated into, else name.",
+                    "type": "string",
+                    "example": "Reisschüssel"
+                },
                 "fat_per_100g": {
                     "description": "Fat grams per 100g.",
                     "type": "number",
                     "example": 0.3
                 },
+                "forked_from_id": {
+                    "description": "Recipe this one was forked from; absent for originals.",
+                    "type": "integer",
+                    "example": 3
+                },
                 "id": {
-                    "description": "Food ID.",
+                    "description": "Recipe ID.",
                     "type": "integer",
                     "example": 1
                 },
+                "image_url": {
+                    "description": "Uploaded image, shrunk to at most 1600px on its longest edge.",
+                    "type": "string",
+                    "example": "http://localhost:8080/media/recipes/5f2c9a0e7b1d4c3a8e6f0b21.jpg"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RecipeIngredientResponse"
+                    }
+                },
+                "instructions": {
+                    "description": "Instruction steps in cooking order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Brown the beef.",
+                        "Add paprika and stock and simmer for 90 minutes."
+                    ]
+                },
                 "kcal_per_100g": {
                     "description": "Energy in kcal per 100g.",
                     "type": "number",
                     "example": 130
                 },
                 "name": {
-                    "description": "Food name.",
+                    "description": "Recipe name.",
                     "type": "string",
-                    "example": "Rice"
+                    "example": "Rice Bowl"
+                },
+                "per_batch": {
+                    "description": "Nutrition of the whole yield.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.RecipePortionResponse"
+                        }
+                    ]
+                },
+                "per_serving": {
+                    "description": "Nutrition of one serving.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.RecipePortionResponse"
+                        }
+                    ]
+                },
+                "prep_time_min": {
+                    "description": "Preparation time in minutes; 0 when not given.",
+                    "type": "integer",
+                    "example": 20
                 },
                 "protein_per_100g": {
                     "description": "Protein grams per 100g.",
                     "type": "number",
                     "example": 2.7
                 },
+                "serving_name": {
+                    "description": "Name of one serving.",
+                    "type": "string",
+                    "example": "bowl"
+                },
+                "servings": {
+                    "description": "Number of equal servings the yield makes.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "stale": {
+                    "description": "True when an ingredient food's nutrition changed and the per-100g values await recalculation.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "tags": {
+                    "description": "Free-form tags, lowercased.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dinner",
+                        "hungarian"
+                    ]
+                },
+                "thumbnail_url": {
+                    "description": "320px JPEG thumbnail of the image.",
+                    "type": "string",
+                    "example": "http://localhost:8080/media/recipes/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"
+                },
+                "translations": {
+                    "description": "Names in other languages, sorted by locale.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TranslationResponse"
+                    }
+                },
                 "updated_at": {
                     "description": "Last update timestamp in RFC3339 UTC.",
                     "type": "string",
                     "example": "2026-02-17T12:00:00Z"
-                }
-            }
-        },
-        "handlers.HealthResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "description": "Human-readable health message.",
-                    "type": "string",
-                    "example": "goal-bite api is running"
                 },
-                "status": {
-                    "description": "Service status.",
-                    "type": "string",
-                    "example": "ok"
+                "user_id": {
+                    "description": "Owner user ID.",
+                    "type": "integer",
+                    "example": 7
+                },
+                "yield_estimated": {
+                    "description": "Whether the yield was estimated from the ingredients and their cooking methods rather than stated.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "yield_weight_g": {
+                    "description": "Final cooked yield weight in grams.",
+                    "type": "number",
+                    "example": 200
                 }
             }
         },
-        "handlers.MealItemResponse": {
+        "handlers.ShareLinkResponse": {
             "type": "object",
             "properties": {
-                "carbs_per_100g": {
-                    "description": "Carbohydrate snapshot in g per 100g at log time.",
-                    "type": "number",
-                    "example": 28
+                "access_count": {
+                    "description": "Number of times the link was opened.",
+                    "type": "integer",
+                    "example": 3
                 },
                 "created_at": {
                     "description": "Creation timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
-                },
-                "fat_per_100g": {
-                    "description": "Fat snapshot in g per 100g at log time.",
-                    "type": "number",
-                    "example": 0.3
+                    "example": "2026-02-17T08:00:00Z"
                 },
-                "food_id": {
-                    "description": "Optional food source ID.",
-                    "type": "integer",
-                    "example": 1
+                "expires_at": {
+                    "description": "When the link stops opening; absent when it never expires.",
+                    "type": "string",
+                    "example": "2026-03-01T00:00:00Z"
                 },
                 "id": {
-                    "description": "Meal item ID.",
+                    "description": "Share link ID.",
                     "type": "integer",
                     "example": 1
                 },
-                "kcal_per_100g": {
-                    "description": "Energy snapshot in kcal per 100g at log time.",
-                    "type": "number",
-                    "example": 130
+                "last_accessed_at": {
+                    "description": "When the link was last opened.",
+                    "type": "string",
+                    "example": "2026-02-19T18:30:00Z"
                 },
                 "meal_id": {
-                    "description": "Parent meal ID.",
+                    "description": "Shared meal; absent for recipes.",
                     "type": "integer",
-                    "example": 1
-                },
-                "protein_per_100g": {
-                    "description": "Protein snapshot in g per 100g at log time.",
-                    "type": "number",
-                    "example": 2.7
+                    "example": 2
                 },
                 "recipe_id": {
-                    "description": "Optional recipe source ID.",
+                    "description": "Shared recipe; absent for meals.",
                     "type": "integer",
                     "example": 1
                 },
+                "revoked_at": {
+                    "description": "Set when the owner revoked the link.",
+                    "type": "string",
+                    "example": "2026-02-20T09:00:00Z"
+                },
+                "token": {
+                    "description": "Token to open the link with at /public/shared/{token}; only returned when the link is created.",
+                    "type": "string",
+                    "example": "q3X0b9mJ4r1vF8kT2zY6wN5sL7pC0dHa"
+                },
                 "updated_at": {
                     "description": "Last update timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
+                    "example": "2026-02-17T08:00:00Z"
                 },
-                "weight_g": {
-                    "description": "Consumed weight in grams.",
-                    "type": "number",
-                    "example": 150
+                "user_id": {
+                    "description": "Owner user ID.",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "handlers.MealResponse": {
+        "handlers.SharedMealResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "description": "Creation timestamp in RFC3339 UTC.",
-                    "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
-                },
                 "eaten_at": {
                     "description": "Meal timestamp in RFC3339 UTC.",
                     "type": "string",
                     "example": "2026-02-17T12:00:00Z"
                 },
-                "id": {
-                    "description": "Meal ID.",
-                    "type": "integer",
-                    "example": 1
+                "image_url": {
+                    "description": "Uploaded photo, shrunk to at most 1600px on its longest edge.",
+                    "type": "string",
+                    "example": "http://localhost:8080/media/meals/5f2c9a0e7b1d4c3a8e6f0b21.jpg"
+                },
+                "meal_type": {
+                    "description": "Meal type.",
+                    "type": "string",
+                    "example": "lunch"
+                },
+                "thumbnail_url": {
+                    "description": "320px JPEG thumbnail of the photo.",
+                    "type": "string",
+                    "example": "http://localhost:8080/media/meals/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"
+                }
+            }
+        },
+        "handlers.SharedNutritionItemResponse": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "description": "Nutrients this item adds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutrientAmountsResponse"
+                        }
+                    ]
+                },
+                "display_name": {
+                    "description": "Name in the first of the caller's languages it is translated into, else name.",
+                    "type": "string",
+                    "example": "Olivenöl"
+                },
+                "name": {
+                    "description": "Food or recipe name; empty for other users' private foods.",
+                    "type": "string",
+                    "example": "Olive oil"
+                },
+                "share": {
+                    "description": "Percentage of each total this item adds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutrientSharesResponse"
+                        }
+                    ]
+                },
+                "weight_g": {
+                    "description": "Weight in grams, raw for recipe ingredients.",
+                    "type": "number",
+                    "example": 10
+                }
+            }
+        },
+        "handlers.SharedNutritionResponse": {
+            "type": "object",
+            "properties": {
+                "calorie_split": {
+                    "description": "Share of the energy from macros that each macro provides, by the Atwater factors.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.MacroSplitResponse"
+                        }
+                    ]
                 },
                 "items": {
+                    "description": "One entry per recipe ingredient or meal item, in order.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.MealItemResponse"
+                        "$ref": "#/definitions/handlers.SharedNutritionItemResponse"
                     }
                 },
-                "meal_type": {
-                    "description": "Meal type.",
+                "total": {
+                    "description": "Sum of the contributions.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.NutrientAmountsResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.SharedRecipeResponse": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "description": "Allergens of any ingredient.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "milk"
+                    ]
+                },
+                "cook_time_min": {
+                    "description": "Cooking time in minutes; 0 when not given.",
+                    "type": "integer",
+                    "example": 90
+                },
+                "description": {
+                    "description": "Description; empty when not given.",
                     "type": "string",
-                    "example": "lunch"
+                    "example": "Hearty Hungarian beef stew."
                 },
-                "total_carbs_g": {
-                    "description": "Aggregated carbohydrate grams for this meal.",
-                    "type": "number",
-                    "example": 75
+                "diet_flags": {
+                    "description": "Diet flags all ingredients carry.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian"
+                    ]
                 },
-                "total_fat_g": {
-                    "description": "Aggregated fat grams for this meal.",
-                    "type": "number",
-                    "example": 2.1
+                "display_name": {
+                    "description": "Name in the first of the caller's languages the recipe is translated into, else name.",
+                    "type": "string",
+                    "example": "Reisschüssel"
                 },
-                "total_kcal": {
-                    "description": "Aggregated kcal for this meal.",
-                    "type": "number",
-                    "example": 350
+                "image_url": {
+                    "description": "Uploaded image, shrunk to at most 1600px on its longest edge.",
+                    "type": "string",
+                    "example": "http://localhost:8080/media/recipes/5f2c9a0e7b1d4c3a8e6f0b21.jpg"
                 },
-                "total_protein_g": {
-                    "description": "Aggregated protein grams for this meal.",
-                    "type": "number",
-                    "example": 10.5
+                "instructions": {
+                    "description": "Instruction steps in cooking order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Brown the beef.",
+                        "Add paprika and stock and simmer for 90 minutes."
+                    ]
                 },
-                "updated_at": {
-                    "description": "Last update timestamp in RFC3339 UTC.",
+                "name": {
+                    "description": "Recipe name.",
                     "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
+                    "example": "Rice Bowl"
                 },
-                "user_id": {
-                    "description": "Owner user ID.",
+                "per_batch": {
+                    "description": "Nutrition of the whole yield.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.RecipePortionResponse"
+                        }
+                    ]
+                },
+                "per_serving": {
+                    "description": "Nutrition of one serving.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.RecipePortionResponse"
+                        }
+                    ]
+                },
+                "prep_time_min": {
+                    "description": "Preparation time in minutes; 0 when not given.",
                     "type": "integer",
-                    "example": 1
+                    "example": 20
+                },
+                "serving_name": {
+                    "description": "Name of one serving.",
+                    "type": "string",
+                    "example": "bowl"
+                },
+                "servings": {
+                    "description": "Number of equal servings the yield makes.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "tags": {
+                    "description": "Free-form tags, lowercased.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dinner",
+                        "hungarian"
+                    ]
+                },
+                "thumbnail_url": {
+                    "description": "320px JPEG thumbnail of the image.",
+                    "type": "string",
+                    "example": "http://localhost:8080/media/recipes/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"
+                },
+                "yield_weight_g": {
+                    "description": "Final cooked yield weight in grams.",
+                    "type": "number",
+                    "example": 200
                 }
             }
         },
-        "handlers.RecipeIngredientResponse": {
+        "handlers.SharedViewResponse": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "What the link shares.",
+                    "type": "string",
+                    "enum": [
+                        "recipe",
+                        "meal"
+                    ],
+                    "example": "recipe"
+                },
+                "meal": {
+                    "description": "Shared meal; absent for recipes.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.SharedMealResponse"
+                        }
+                    ]
+                },
+                "nutrition": {
+                    "description": "Contribution of each recipe ingredient, to the whole batch, or of each meal item.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.SharedNutritionResponse"
+                        }
+                    ]
+                },
+                "recipe": {
+                    "description": "Shared recipe; absent for meals.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.SharedRecipeResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.ShoppingListItemResponse": {
             "type": "object",
             "properties": {
+                "checked": {
+                    "description": "Whether the item was bought.",
+                    "type": "boolean",
+                    "example": false
+                },
                 "created_at": {
                     "description": "Creation timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
+                    "example": "2026-02-17T08:00:00Z"
+                },
+                "display_name": {
+                    "description": "Food name in the caller's language.",
+                    "type": "string",
+                    "example": "Spaghetti"
                 },
                 "food_id": {
-                    "description": "Referenced food ID.",
+                    "description": "Food to buy.",
                     "type": "integer",
                     "example": 1
                 },
                 "id": {
-                    "description": "Ingredient row ID.",
-                    "type": "integer",
-                    "example": 1
-                },
-                "position": {
-                    "description": "Optional ordering position.",
+                    "description": "Shopping list item ID.",
                     "type": "integer",
                     "example": 1
                 },
-                "raw_weight_g": {
-                    "description": "Raw ingredient weight in grams.",
-                    "type": "number",
-                    "example": 200
-                },
-                "recipe_id": {
-                    "description": "Parent recipe ID.",
-                    "type": "integer",
-                    "example": 1
+                "name": {
+                    "description": "Food name; empty for foods the caller may no longer see.",
+                    "type": "string",
+                    "example": "Spaghetti"
                 },
                 "updated_at": {
                     "description": "Last update timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
+                    "example": "2026-02-17T08:00:00Z"
+                },
+                "weight_g": {
+                    "description": "Raw weight needed in grams, merged across recipes and meals.",
+                    "type": "number",
+                    "example": 300
                 }
             }
         },
-        "handlers.RecipeResponse": {
+        "handlers.ShoppingListResponse": {
             "type": "object",
             "properties": {
-                "carbs_per_100g": {
-                    "description": "Carbohydrate grams per 100g.",
-                    "type": "number",
-                    "example": 28
-                },
                 "created_at": {
                     "description": "Creation timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
-                },
-                "fat_per_100g": {
-                    "description": "Fat grams per 100g.",
-                    "type": "number",
-                    "example": 0.3
+                    "example": "2026-02-17T08:00:00Z"
                 },
                 "id": {
-                    "description": "Recipe ID.",
+                    "description": "Shopping list ID.",
                     "type": "integer",
                     "example": 1
                 },
-                "ingredients": {
+                "items": {
+                    "description": "One item per food, in the order the foods first came up; omitted in lists.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.RecipeIngredientResponse"
+                        "$ref": "#/definitions/handlers.ShoppingListItemResponse"
                     }
                 },
-                "kcal_per_100g": {
-                    "description": "Energy in kcal per 100g.",
-                    "type": "number",
-                    "example": 130
-                },
                 "name": {
-                    "description": "Recipe name.",
+                    "description": "List name.",
                     "type": "string",
-                    "example": "Rice Bowl"
-                },
-                "protein_per_100g": {
-                    "description": "Protein grams per 100g.",
-                    "type": "number",
-                    "example": 2.7
+                    "example": "Weekend cooking"
                 },
                 "updated_at": {
                     "description": "Last update timestamp in RFC3339 UTC.",
                     "type": "string",
-                    "example": "2026-02-17T12:00:00Z"
+                    "example": "2026-02-17T08:00:00Z"
                 },
-                "yield_weight_g": {
-                    "description": "Final cooked yield weight in grams.",
-                    "type": "number",
-                    "example": 200
+                "user_id": {
+                    "description": "Owner user ID.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.TagFacetResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Number of matching foods with this tag.",
+                    "type": "integer",
+                    "example": 7
+                },
+                "tag": {
+                    "description": "Tag.",
+                    "type": "string",
+                    "example": "breakfast"
+                }
+            }
+        },
+        "handlers.TranslationResponse": {
+            "type": "object",
+            "properties": {
+                "locale": {
+                    "description": "Lowercase ISO 639 language code.",
+                    "type": "string",
+                    "example": "de"
+                },
+                "name": {
+                    "description": "Name in that language.",
+                    "type": "string",
+                    "example": "Reis"
+                },
+                "synonyms": {
+                    "description": "Other names search matches in that language.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Langkornreis"
+                    ]
                 }
             }
         },
@@ -2672,6 +7408,16 @@ const docTemplate = `{
                     "type": "string",
                     "example": "moderate"
                 },
+                "avoid_allergens": {
+                    "description": "Allergens the user avoids.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "peanuts"
+                    ]
+                },
                 "birth_date": {
                     "description": "Optional birth date.",
                     "type": "string",
@@ -2697,11 +7443,32 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "languages": {
+                    "description": "Languages food and recipe names are shown in, most preferred first.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "de",
+                        "en"
+                    ]
+                },
                 "name": {
                     "description": "Display name.",
                     "type": "string",
                     "example": "Test User"
                 },
+                "required_diet_flags": {
+                    "description": "Diet flags every logged item should carry.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian"
+                    ]
+                },
                 "sex": {
                     "description": "Optional biological sex.",
                     "type": "string",
@@ -2714,6 +7481,23 @@ const docTemplate = `{
                 }
             }
         },
+        "locale.Translation": {
+            "type": "object",
+            "properties": {
+                "locale": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "synonyms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "meal.MealType": {
             "type": "string",
             "enum": [
@@ -2746,12 +7530,27 @@ const docTemplate = `{
                 }
             }
         },
+        "service.OIDCLoginStart": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string"
+                }
+            }
+        },
         "user.User": {
             "type": "object",
             "properties": {
                 "activity_level": {
                     "type": "string"
                 },
+                "avoid_allergens": {
+                    "description": "AvoidAllergens and RequiredDietFlags are dietary restrictions.\nLanguages lists the languages food and recipe names are shown and\nsearched in, most preferred first. All three start out empty through\nthe column defaults and change only through profile updates.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "birth_date": {
                     "type": "string"
                 },
@@ -2767,9 +7566,21 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
+                "required_diet_flags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "sex": {
                     "type": "string"
                 },
@@ -2777,6 +7588,234 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "webauthn.AssertionEvidence": {
+            "type": "object",
+            "properties": {
+                "authenticatorData": {
+                    "type": "string"
+                },
+                "clientDataJSON": {
+                    "type": "string"
+                },
+                "signature": {
+                    "type": "string"
+                },
+                "userHandle": {
+                    "type": "string"
+                }
+            }
+        },
+        "webauthn.AssertionResponse": {
+            "type": "object",
+            "properties": {
+                "authenticatorAttachment": {
+                    "type": "string"
+                },
+                "clientExtensionResults": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rawId": {
+                    "type": "string"
+                },
+                "response": {
+                    "$ref": "#/definitions/webauthn.AssertionEvidence"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "public-key"
+                }
+            }
+        },
+        "webauthn.AttestationResponse": {
+            "type": "object",
+            "properties": {
+                "attestationObject": {
+                    "type": "string"
+                },
+                "authenticatorData": {
+                    "type": "string"
+                },
+                "clientDataJSON": {
+                    "type": "string"
+                },
+                "publicKey": {
+                    "type": "string"
+                },
+                "publicKeyAlgorithm": {
+                    "type": "integer"
+                },
+                "transports": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "webauthn.AuthenticatorSelection": {
+            "type": "object",
+            "properties": {
+                "residentKey": {
+                    "type": "string",
+                    "example": "required"
+                },
+                "userVerification": {
+                    "type": "string",
+                    "example": "preferred"
+                }
+            }
+        },
+        "webauthn.CreationOptions": {
+            "type": "object",
+            "properties": {
+                "attestation": {
+                    "type": "string",
+                    "example": "none"
+                },
+                "authenticatorSelection": {
+                    "$ref": "#/definitions/webauthn.AuthenticatorSelection"
+                },
+                "challenge": {
+                    "type": "string"
+                },
+                "excludeCredentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webauthn.CredentialDescriptor"
+                    }
+                },
+                "pubKeyCredParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webauthn.CredentialParameter"
+                    }
+                },
+                "rp": {
+                    "$ref": "#/definitions/webauthn.RelyingParty"
+                },
+                "timeout": {
+                    "type": "integer",
+                    "example": 300000
+                },
+                "user": {
+                    "$ref": "#/definitions/webauthn.UserEntity"
+                }
+            }
+        },
+        "webauthn.CredentialDescriptor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "transports": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "public-key"
+                }
+            }
+        },
+        "webauthn.CredentialParameter": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "integer",
+                    "example": -7
+                },
+                "type": {
+                    "type": "string",
+                    "example": "public-key"
+                }
+            }
+        },
+        "webauthn.RegistrationResponse": {
+            "type": "object",
+            "properties": {
+                "authenticatorAttachment": {
+                    "type": "string"
+                },
+                "clientExtensionResults": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rawId": {
+                    "type": "string"
+                },
+                "response": {
+                    "$ref": "#/definitions/webauthn.AttestationResponse"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "public-key"
+                }
+            }
+        },
+        "webauthn.RelyingParty": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "webauthn.RequestOptions": {
+            "type": "object",
+            "properties": {
+                "allowCredentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webauthn.CredentialDescriptor"
+                    }
+                },
+                "challenge": {
+                    "type": "string"
+                },
+                "rpId": {
+                    "type": "string",
+                    "example": "localhost"
+                },
+                "timeout": {
+                    "type": "integer",
+                    "example": 300000
+                },
+                "userVerification": {
+                    "type": "string",
+                    "example": "preferred"
+                }
+            }
+        },
+        "webauthn.UserEntity": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "AAAAAAAAAAc"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "john@gmail.com"
+                }
+            }
         }
     }
 }`
@@ -12,22 +12,36 @@ import (
 	"time"
 
 	"goal-bite-api/internal/auth"
+	"goal-bite-api/internal/catalog"
 	"goal-bite-api/internal/config"
 	"goal-bite-api/internal/db"
 	httpapi "goal-bite-api/internal/http"
 	"goal-bite-api/internal/http/handlers"
+	"goal-bite-api/internal/mail"
+	"goal-bite-api/internal/oidc"
 	"goal-bite-api/internal/repository"
 	"goal-bite-api/internal/service"
+	"goal-bite-api/internal/storage"
+	"goal-bite-api/internal/webauthn"
 
 	"gorm.io/gorm"
 )
 
 type App struct {
-	cfg    config.Config
-	logger *slog.Logger
-	server *http.Server
+	cfg     config.Config
+	logger  *slog.Logger
+	server  *http.Server
+	recipes *service.RecipeService
+	// magicLinks is drained on shutdown so requested links still go out.
+	magicLinks *service.MagicLinkService
+	// breachedPasswords is the open breach list, nil when screening is off.
+	breachedPasswords *auth.BreachedPasswordFile
 }
 
+// staleRecipeBatch is how many stale recipes one background pass loads at a
+// time.
+const staleRecipeBatch = 100
+
 type dbReadinessChecker struct {
 	db *gorm.DB
 }
@@ -53,33 +67,147 @@ func New(cfg config.Config) (*App, error) {
 	if err != nil {
 		return nil, fmt.Errorf("init jwt manager: %w", err)
 	}
+	jwtManager.SetAccessTokenTTL(cfg.AccessTokenTTL)
 	authSessionRepository := repository.NewAuthSessionRepository(database)
+	sessionChecker := service.NewCachedSessionChecker(authSessionRepository, cfg.SessionCacheTTL)
 	loginAttempts := service.NewMemoryLoginAttemptTracker(
 		cfg.AuthLoginMaxAttempts,
 		cfg.AuthLoginAttemptWindow,
 		cfg.AuthLoginLockoutWindow,
 	)
 	authService := service.NewAuthService(userRepository, jwtManager, authSessionRepository, loginAttempts)
+	var breachedPasswords *auth.BreachedPasswordFile
+	if cfg.AuthBreachedPasswordsFile != "" {
+		breachedPasswords, err = auth.OpenBreachedPasswordFile(cfg.AuthBreachedPasswordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("open breached password list: %w", err)
+		}
+		authService.SetBreachedPasswordChecker(breachedPasswords)
+	}
+	magicLinkTokenRepository := repository.NewMagicLinkTokenRepository(database)
+	magicLinkService := service.NewMagicLinkService(
+		userRepository,
+		magicLinkTokenRepository,
+		authSessionRepository,
+		jwtManager,
+		mail.NewFileMailer(cfg.MailOutboxDir),
+		service.MagicLinkConfig{
+			BaseURL:         cfg.AuthMagicLinkBaseURL,
+			TTL:             cfg.AuthMagicLinkTTL,
+			Cooldown:        cfg.AuthMagicLinkCooldown,
+			MaxPendingSends: cfg.AuthMagicLinkMaxPending,
+			Logger:          logger,
+		},
+	)
+	passkeyService := service.NewPasskeyService(
+		userRepository,
+		repository.NewPasskeyRepository(database),
+		repository.NewWebAuthnChallengeRepository(database),
+		authSessionRepository,
+		jwtManager,
+		webauthn.Config{RPID: cfg.WebAuthnRPID, RPName: cfg.WebAuthnRPName, Origins: cfg.WebAuthnOrigins},
+	)
+	oidcProviders := make([]service.OIDCProvider, 0, len(cfg.OIDCProviders))
+	for _, p := range cfg.OIDCProviders {
+		oidcProviders = append(oidcProviders, oidc.NewProvider(oidc.ProviderConfig{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, nil))
+	}
+	oidcService := service.NewOIDCService(
+		userRepository,
+		repository.NewOIDCIdentityRepository(database),
+		repository.NewOIDCLoginStateRepository(database),
+		authSessionRepository,
+		jwtManager,
+		oidcProviders...,
+	)
+	var blobs service.BlobStore
+	var media http.Handler
+	switch cfg.MediaStore {
+	case "s3":
+		blobs = storage.NewS3Store(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretKey,
+			PublicBaseURL:   cfg.MediaBaseURL,
+		}, nil)
+	default:
+		local := storage.NewLocalStore(cfg.MediaLocalDir, cfg.MediaBaseURL)
+		blobs, media = local, local
+	}
+	images := service.ImageUploads{Store: blobs, MaxBytes: cfg.MediaMaxUploadBytes}
+
 	foodRepository := repository.NewFoodRepository(database)
-	foodService := service.NewFoodService(foodRepository)
 	recipeRepository := repository.NewRecipeRepository(database)
+	cookingRepository := repository.NewCookingRepository(database)
 	recipeService := service.NewRecipeService(recipeRepository, foodRepository)
+	recipeService.SetRestrictionReader(userRepository)
+	recipeService.SetImageUploads(images)
+	recipeService.SetFoodCandidateFinder(foodRepository)
+	recipeService.SetCookingFactors(cookingRepository)
+	recipeService.SetSyncLimit(cfg.RecipeRecalcSyncLimit)
+	foodService := service.NewFoodService(foodRepository)
+	foodService.SetAdminReader(userRepository)
+	foodService.SetRestrictionReader(userRepository)
+	foodService.SetRecipeRecalculator(recipeService)
+	foodService.SetImageUploads(images)
+	foodService.SetCookingFactors(cookingRepository)
+	switch cfg.BarcodeProvider {
+	case "openfoodfacts":
+		foodService.SetBarcodeLookup(service.BarcodeLookup{
+			Provider: catalog.NewOpenFoodFacts(cfg.OpenFoodFactsBaseURL, "GoalBite/1.0", nil),
+			Misses:   repository.NewBarcodeLookupMissRepository(database),
+			MissTTL:  cfg.BarcodeMissTTL,
+		})
+	case "file":
+		products, err := catalog.NewFileProvider(cfg.BarcodeProviderFile)
+		if err != nil {
+			if breachedPasswords != nil {
+				_ = breachedPasswords.Close()
+			}
+			return nil, fmt.Errorf("open barcode product file: %w", err)
+		}
+		foodService.SetBarcodeLookup(service.BarcodeLookup{
+			Provider: products,
+			Misses:   repository.NewBarcodeLookupMissRepository(database),
+			MissTTL:  cfg.BarcodeMissTTL,
+		})
+	}
 	mealRepository := repository.NewMealRepository(database)
 	mealService := service.NewMealService(mealRepository, foodRepository, recipeRepository)
+	mealService.SetRestrictionReader(userRepository)
+	mealService.SetImageUploads(images)
+	mealService.SetCookingFactors(cookingRepository)
 	bodyWeightLogRepository := repository.NewBodyWeightLogRepository(database)
 	bodyWeightLogService := service.NewBodyWeightLogService(bodyWeightLogRepository)
 	userGoalRepository := repository.NewUserGoalRepository(database)
 	userGoalService := service.NewUserGoalService(userGoalRepository, mealRepository)
 	energyService := service.NewEnergyService(userRepository, bodyWeightLogRepository, mealRepository)
+	shoppingListService := service.NewShoppingListService(repository.NewShoppingListRepository(database), recipeRepository, foodRepository, mealRepository)
+	shareLinkService := service.NewShareLinkService(repository.NewShareLinkRepository(database), recipeService, mealService)
 	readinessChecker := dbReadinessChecker{db: database}
-	handler := handlers.New(userService, authService, foodService, recipeService, mealService, bodyWeightLogService, userGoalService, energyService, readinessChecker)
-	router := httpapi.NewRouter(handler, logger, jwtManager)
+	handler := handlers.New(userService, authService, foodService, recipeService, mealService, bodyWeightLogService, userGoalService, energyService, readinessChecker, magicLinkService, passkeyService, oidcService, shoppingListService, shareLinkService)
+	router := httpapi.NewRouter(handler, logger, jwtManager, sessionChecker, media)
 	server := &http.Server{
 		Addr:    cfg.Addr(),
 		Handler: router,
 	}
 
-	return &App{cfg: cfg, logger: logger, server: server}, nil
+	return &App{
+		cfg:               cfg,
+		logger:            logger,
+		server:            server,
+		recipes:           recipeService,
+		magicLinks:        magicLinkService,
+		breachedPasswords: breachedPasswords,
+	}, nil
 }
 
 func (a *App) Run() error {
@@ -92,6 +220,10 @@ func (a *App) Run() error {
 		}
 	}()
 
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go a.recalculateStaleRecipes(background)
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
@@ -108,7 +240,47 @@ func (a *App) Run() error {
 	if err := a.server.Shutdown(ctx); err != nil {
 		return fmt.Errorf("graceful shutdown failed: %w", err)
 	}
+	a.magicLinks.Wait()
+	if a.breachedPasswords != nil {
+		if err := a.breachedPasswords.Close(); err != nil {
+			a.logger.Error("close breached password list", "error", err)
+		}
+	}
 
 	a.logger.Info("api stopped")
 	return nil
 }
+
+// recalculateStaleRecipes refreshes recipes left stale by food changes with
+// too many dependents to recalculate right away, until ctx is cancelled.
+func (a *App) recalculateStaleRecipes(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.RecipeRecalcInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		var afterID uint
+		for {
+			batch, err := a.recipes.RecalculateStale(ctx, afterID, staleRecipeBatch)
+			if err != nil {
+				if ctx.Err() == nil {
+					a.logger.Error("recalculate stale recipes", "error", err)
+				}
+				break
+			}
+			for id, err := range batch.Failed {
+				a.logger.Error("recalculate stale recipe", "recipe_id", id, "error", err)
+			}
+			if batch.Recalculated > 0 {
+				a.logger.Info("recalculated stale recipes", "count", batch.Recalculated)
+			}
+			if batch.Checked < staleRecipeBatch {
+				break
+			}
+			afterID = batch.LastID
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+const breachedLineReadChunk = 128
+
+// BreachedPasswordFile checks passwords against a local, sorted list of
+// SHA-1 hashes in the HIBP "HASH:COUNT" format (one entry per line, count
+// optional). Lookups binary-search the file on disk, so the full corpus
+// never has to be loaded into memory and no network access is required.
+type BreachedPasswordFile struct {
+	file *os.File
+	size int64
+}
+
+func OpenBreachedPasswordFile(path string) (*BreachedPasswordFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		_ = f.Close()
+		return nil, errors.New("breached password list must be a file")
+	}
+	return &BreachedPasswordFile{file: f, size: info.Size()}, nil
+}
+
+// IsBreached reports whether the SHA-1 of password appears in the list.
+func (b *BreachedPasswordFile) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	target := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// Invariant: every line starting before lo sorts below target and every
+	// line starting at or after hi sorts above it.
+	lo, hi := int64(0), b.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, err := b.lineStartFrom(mid)
+		if err != nil {
+			return false, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+		line, next, err := b.readLine(start)
+		if err != nil {
+			return false, err
+		}
+		switch cmp := strings.Compare(breachedLineHash(line), target); {
+		case cmp == 0:
+			return true, nil
+		case cmp < 0:
+			lo = next
+		default:
+			hi = start
+		}
+	}
+	return false, nil
+}
+
+func (b *BreachedPasswordFile) Close() error {
+	return b.file.Close()
+}
+
+// lineStartFrom returns the offset of the first line that starts at or after off.
+func (b *BreachedPasswordFile) lineStartFrom(off int64) (int64, error) {
+	if off == 0 {
+		return 0, nil
+	}
+	buf := make([]byte, breachedLineReadChunk)
+	pos := off - 1
+	for pos < b.size {
+		n, err := b.file.ReadAt(buf, pos)
+		if idx := bytes.IndexByte(buf[:n], '\n'); idx >= 0 {
+			return pos + int64(idx) + 1, nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		pos += int64(n)
+		if n == 0 {
+			break
+		}
+	}
+	return b.size, nil
+}
+
+// readLine returns the line starting at off and the offset of the following line.
+func (b *BreachedPasswordFile) readLine(off int64) (string, int64, error) {
+	var line []byte
+	buf := make([]byte, breachedLineReadChunk)
+	pos := off
+	for pos < b.size {
+		n, err := b.file.ReadAt(buf, pos)
+		if idx := bytes.IndexByte(buf[:n], '\n'); idx >= 0 {
+			line = append(line, buf[:idx]...)
+			return string(line), pos + int64(idx) + 1, nil
+		}
+		line = append(line, buf[:n]...)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", 0, err
+		}
+		pos += int64(n)
+		if n == 0 {
+			break
+		}
+	}
+	return string(line), b.size, nil
+}
+
+func breachedLineHash(line string) string {
+	line = strings.TrimSpace(line)
+	if idx := strings.IndexByte(line, ':'); idx >= 0 {
+		line = line[:idx]
+	}
+	return strings.ToUpper(line)
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeBreachedList(t *testing.T, passwords []string, lineEnding string) string {
+	t.Helper()
+	lines := make([]string, 0, len(passwords))
+	for i, p := range passwords {
+		sum := sha1.Sum([]byte(p))
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":"+strings.Repeat("9", i+1))
+	}
+	sort.Strings(lines)
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, lineEnding)+lineEnding), 0o600); err != nil {
+		t.Fatalf("write list: %v", err)
+	}
+	return path
+}
+
+func TestBreachedPasswordFile(t *testing.T) {
+	breached := []string{"password", "Password123!", "qwerty", "letmein", "Summer2024!!", "123456", "dragon"}
+
+	for _, ending := range []string{"\n", "\r\n"} {
+		path := writeBreachedList(t, breached, ending)
+		list, err := OpenBreachedPasswordFile(path)
+		if err != nil {
+			t.Fatalf("open list: %v", err)
+		}
+		t.Cleanup(func() { _ = list.Close() })
+
+		for _, p := range breached {
+			got, err := list.IsBreached(p)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got {
+				t.Fatalf("expected %q to be breached", p)
+			}
+		}
+		for _, p := range []string{"Pass1234!x", "Correct-Horse-9", ""} {
+			got, err := list.IsBreached(p)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got {
+				t.Fatalf("expected %q not to be breached", p)
+			}
+		}
+	}
+}
+
+func TestBreachedPasswordFileEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.txt")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("write list: %v", err)
+	}
+	list, err := OpenBreachedPasswordFile(path)
+	if err != nil {
+		t.Fatalf("open list: %v", err)
+	}
+	defer list.Close()
+
+	got, err := list.IsBreached("password")
+	if err != nil || got {
+		t.Fatalf("expected no match in empty list, got %v, %v", got, err)
+	}
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,9 +13,22 @@ import (
 
 var ErrInvalidToken = errors.New("invalid token")
 
+// DefaultAccessTokenTTL is the access token lifetime unless overridden with
+// SetAccessTokenTTL. Refresh tokens carry the long-lived session.
+const DefaultAccessTokenTTL = 15 * time.Minute
+
 type JWTManager struct {
 	activeKID string
 	keys      map[string][]byte
+	ttl       time.Duration
+}
+
+// AccessClaims are the verified claims of an access token.
+type AccessClaims struct {
+	UserID    uint
+	SessionID uint
+	TokenID   string
+	ExpiresAt time.Time
 }
 
 func NewJWTManager(secret string) *JWTManager {
@@ -22,6 +37,7 @@ func NewJWTManager(secret string) *JWTManager {
 		keys: map[string][]byte{
 			"v1": []byte(secret),
 		},
+		ttl: DefaultAccessTokenTTL,
 	}
 }
 
@@ -50,14 +66,30 @@ func NewJWTManagerWithKeys(activeKID string, keys map[string]string) (*JWTManage
 	return &JWTManager{
 		activeKID: active,
 		keys:      out,
+		ttl:       DefaultAccessTokenTTL,
 	}, nil
 }
 
-func (m *JWTManager) Generate(userID uint) (string, error) {
+func (m *JWTManager) SetAccessTokenTTL(ttl time.Duration) {
+	if ttl > 0 {
+		m.ttl = ttl
+	}
+}
+
+// Generate issues an access token for userID bound to the auth session
+// sessionID, so revoking the session also invalidates the token.
+func (m *JWTManager) Generate(userID, sessionID uint) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"sub": fmt.Sprintf("%d", userID),
-		"iat": time.Now().Unix(),
-		"exp": time.Now().Add(24 * time.Hour).Unix(),
+		"sid": fmt.Sprintf("%d", sessionID),
+		"jti": hex.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(m.ttl).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token.Header["kid"] = m.activeKID
@@ -68,7 +100,9 @@ func (m *JWTManager) Generate(userID uint) (string, error) {
 	return token.SignedString(secret)
 }
 
-func (m *JWTManager) Parse(tokenString string) (uint, error) {
+// Parse verifies signature and expiry and returns the token claims. Tokens
+// without a session binding are rejected.
+func (m *JWTManager) Parse(tokenString string) (AccessClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if token.Method != jwt.SigningMethodHS256 {
 			return nil, ErrInvalidToken
@@ -88,19 +122,45 @@ func (m *JWTManager) Parse(tokenString string) (uint, error) {
 		return secret, nil
 	})
 	if err != nil || !token.Valid {
-		return 0, ErrInvalidToken
+		return AccessClaims{}, ErrInvalidToken
 	}
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, ErrInvalidToken
+		return AccessClaims{}, ErrInvalidToken
 	}
 	sub, err := claims.GetSubject()
 	if err != nil {
-		return 0, ErrInvalidToken
+		return AccessClaims{}, ErrInvalidToken
+	}
+	userID, ok := parseIDClaim(sub)
+	if !ok {
+		return AccessClaims{}, ErrInvalidToken
+	}
+	sid, _ := claims["sid"].(string)
+	sessionID, ok := parseIDClaim(sid)
+	if !ok {
+		return AccessClaims{}, ErrInvalidToken
+	}
+	jti, _ := claims["jti"].(string)
+	if strings.TrimSpace(jti) == "" {
+		return AccessClaims{}, ErrInvalidToken
 	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return AccessClaims{}, ErrInvalidToken
+	}
+	return AccessClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenID:   jti,
+		ExpiresAt: exp.Time,
+	}, nil
+}
+
+func parseIDClaim(raw string) (uint, bool) {
 	var id uint
-	if _, err := fmt.Sscanf(sub, "%d", &id); err != nil || id == 0 {
-		return 0, ErrInvalidToken
+	if _, err := fmt.Sscanf(raw, "%d", &id); err != nil || id == 0 {
+		return 0, false
 	}
-	return id, nil
+	return id, true
 }
@@ -3,6 +3,7 @@ package auth
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -16,7 +17,7 @@ func TestJWTManagerWithKeys(t *testing.T) {
 		t.Fatalf("new manager: %v", err)
 	}
 
-	token, err := m.Generate(42)
+	token, err := m.Generate(42, 9)
 	if err != nil {
 		t.Fatalf("generate: %v", err)
 	}
@@ -31,12 +32,18 @@ func TestJWTManagerWithKeys(t *testing.T) {
 		t.Fatalf("expected kid v2, got %v", parsed.Header["kid"])
 	}
 
-	userID, err := m.Parse(token)
+	claims, err := m.Parse(token)
 	if err != nil {
 		t.Fatalf("parse managed token: %v", err)
 	}
-	if userID != 42 {
-		t.Fatalf("expected user id 42, got %d", userID)
+	if claims.UserID != 42 {
+		t.Fatalf("expected user id 42, got %d", claims.UserID)
+	}
+	if claims.SessionID != 9 {
+		t.Fatalf("expected session id 9, got %d", claims.SessionID)
+	}
+	if claims.TokenID == "" {
+		t.Fatalf("expected jti claim")
 	}
 }
 
@@ -85,3 +92,33 @@ func TestNewJWTManagerWithKeysValidation(t *testing.T) {
 		t.Fatalf("expected active kid error, got %v", err)
 	}
 }
+
+func TestJWTManagerAccessTokenTTLAndSessionBinding(t *testing.T) {
+	m := NewJWTManager("secret1")
+	m.SetAccessTokenTTL(2 * time.Minute)
+
+	token, err := m.Generate(1, 2)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	claims, err := m.Parse(token)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if remaining := time.Until(claims.ExpiresAt); remaining > 2*time.Minute || remaining < time.Minute {
+		t.Fatalf("expected ~2m lifetime, got %s", remaining)
+	}
+
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	legacy.Header["kid"] = "v1"
+	legacyToken, err := legacy.SignedString([]byte("secret1"))
+	if err != nil {
+		t.Fatalf("sign legacy token: %v", err)
+	}
+	if _, err := m.Parse(legacyToken); err == nil {
+		t.Fatalf("expected token without sid/jti to be rejected")
+	}
+}
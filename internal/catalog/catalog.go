@@ -0,0 +1,39 @@
+// Package catalog looks up packaged products by barcode in external food
+// databases.
+package catalog
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrNotFound = errors.New("product not found")
+	// ErrUnavailable wraps transport failures and unexpected responses.
+	ErrUnavailable = errors.New("product catalog unavailable")
+)
+
+// Product is a catalog entry with nutrition values per 100g.
+type Product struct {
+	Barcode        string
+	Name           string
+	BrandName      *string
+	KcalPer100g    float64
+	ProteinPer100g float64
+	CarbsPer100g   float64
+	FatPer100g     float64
+	// Categories holds the source's own category identifiers, such as Open
+	// Food Facts taxonomy tags, from broadest to most specific.
+	Categories []string
+	// Ref points back at the entry in its source, such as a product page.
+	// Empty when the source has none.
+	Ref string
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
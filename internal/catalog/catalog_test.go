@@ -0,0 +1,99 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenFoodFactsLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "goal-bite-test" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v2/product/5901234123457.json":
+			_, _ = w.Write([]byte(`{"status":1,"product":{"code":"5901234123457","product_name":"Greek Yogurt","brands":"Fage, Fage Total","categories_tags":["en:dairies","en:yogurts"],"nutriments":{"energy-kcal_100g":"97","proteins_100g":9,"carbohydrates_100g":3.9,"fat_100g":5}}}`))
+		case "/api/v2/product/4006381333931.json":
+			_, _ = w.Write([]byte(`{"status":1,"product":{"product_name_en":"Oat drink","nutriments":{"energy_100g":209.2}}}`))
+		case "/api/v2/product/4000000000000.json":
+			_, _ = w.Write([]byte(`{"status":0,"status_verbose":"product not found"}`))
+		case "/api/v2/product/5000000000000.json":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	provider := NewOpenFoodFacts(server.URL, "goal-bite-test", server.Client())
+
+	t.Run("maps product fields", func(t *testing.T) {
+		product, err := provider.Lookup(context.Background(), "5901234123457")
+		if err != nil {
+			t.Fatalf("lookup: %v", err)
+		}
+		if product.Name != "Greek Yogurt" || product.BrandName == nil || *product.BrandName != "Fage" {
+			t.Fatalf("unexpected product: %+v", product)
+		}
+		if product.KcalPer100g != 97 || product.ProteinPer100g != 9 || product.CarbsPer100g != 3.9 || product.FatPer100g != 5 {
+			t.Fatalf("unexpected nutrition: %+v", product)
+		}
+		if len(product.Categories) != 2 || product.Categories[1] != "en:yogurts" {
+			t.Fatalf("unexpected categories: %v", product.Categories)
+		}
+		if product.Ref != server.URL+"/product/5901234123457" {
+			t.Fatalf("unexpected ref: %q", product.Ref)
+		}
+	})
+
+	t.Run("converts kilojoules when kcal is missing", func(t *testing.T) {
+		product, err := provider.Lookup(context.Background(), "4006381333931")
+		if err != nil {
+			t.Fatalf("lookup: %v", err)
+		}
+		if product.Name != "Oat drink" || product.KcalPer100g != 50 {
+			t.Fatalf("unexpected product: %+v", product)
+		}
+	})
+
+	t.Run("reports unknown products as not found", func(t *testing.T) {
+		for _, code := range []string{"4000000000000", "4000000000001"} {
+			if _, err := provider.Lookup(context.Background(), code); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("%s: expected ErrNotFound, got %v", code, err)
+			}
+		}
+	})
+
+	t.Run("reports server errors as unavailable", func(t *testing.T) {
+		if _, err := provider.Lookup(context.Background(), "5000000000000"); !errors.Is(err, ErrUnavailable) {
+			t.Fatalf("expected ErrUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestFileProviderLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.json")
+	if err := os.WriteFile(path, []byte(`{"5901234123457":{"name":"Greek Yogurt","brand_name":"Fage","kcal_per_100g":97}}`), 0o600); err != nil {
+		t.Fatalf("write products: %v", err)
+	}
+	provider, err := NewFileProvider(path)
+	if err != nil {
+		t.Fatalf("open provider: %v", err)
+	}
+
+	product, err := provider.Lookup(context.Background(), "5901234123457")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	if product.Name != "Greek Yogurt" || product.BrandName == nil || product.KcalPer100g != 97 {
+		t.Fatalf("unexpected product: %+v", product)
+	}
+	if _, err := provider.Lookup(context.Background(), "4000000000000"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
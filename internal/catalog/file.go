@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const FileProviderName = "file"
+
+// FileProvider serves products from a JSON object keyed by barcode. It stands
+// in for a real catalog in tests and offline development.
+type FileProvider struct {
+	products map[string]Product
+}
+
+type fileEntry struct {
+	Name           string   `json:"name"`
+	BrandName      string   `json:"brand_name"`
+	KcalPer100g    float64  `json:"kcal_per_100g"`
+	ProteinPer100g float64  `json:"protein_per_100g"`
+	CarbsPer100g   float64  `json:"carbs_per_100g"`
+	FatPer100g     float64  `json:"fat_per_100g"`
+	Categories     []string `json:"categories"`
+	Ref            string   `json:"ref"`
+}
+
+func NewFileProvider(path string) (*FileProvider, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]fileEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("parse product file: %w", err)
+	}
+	products := make(map[string]Product, len(entries))
+	for code, e := range entries {
+		code = strings.TrimSpace(code)
+		if code == "" || strings.TrimSpace(e.Name) == "" {
+			return nil, fmt.Errorf("product file: entry %q needs a barcode and a name", code)
+		}
+		products[code] = Product{
+			Barcode:        code,
+			Name:           strings.TrimSpace(e.Name),
+			BrandName:      optionalString(e.BrandName),
+			KcalPer100g:    e.KcalPer100g,
+			ProteinPer100g: e.ProteinPer100g,
+			CarbsPer100g:   e.CarbsPer100g,
+			FatPer100g:     e.FatPer100g,
+			Categories:     e.Categories,
+			Ref:            strings.TrimSpace(e.Ref),
+		}
+	}
+	return &FileProvider{products: products}, nil
+}
+
+func (p *FileProvider) Name() string {
+	return FileProviderName
+}
+
+func (p *FileProvider) Lookup(_ context.Context, barcode string) (Product, error) {
+	product, ok := p.products[barcode]
+	if !ok {
+		return Product{}, ErrNotFound
+	}
+	return product, nil
+}
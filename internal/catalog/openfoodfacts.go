@@ -0,0 +1,143 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	OpenFoodFactsName       = "open_food_facts"
+	DefaultOpenFoodFactsURL = "https://world.openfoodfacts.org"
+	kilojoulesPerKcal       = 4.184
+)
+
+// OpenFoodFacts looks products up through the Open Food Facts v2 product API.
+// Open Food Facts asks clients to identify themselves with a User-Agent.
+type OpenFoodFacts struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+func NewOpenFoodFacts(baseURL, userAgent string, client *http.Client) *OpenFoodFacts {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	if baseURL == "" {
+		baseURL = DefaultOpenFoodFactsURL
+	}
+	return &OpenFoodFacts{baseURL: strings.TrimRight(baseURL, "/"), userAgent: userAgent, client: client}
+}
+
+func (p *OpenFoodFacts) Name() string {
+	return OpenFoodFactsName
+}
+
+type offResponse struct {
+	Status  int `json:"status"`
+	Product struct {
+		Code          string   `json:"code"`
+		ProductName   string   `json:"product_name"`
+		ProductNameEN string   `json:"product_name_en"`
+		Brands        string   `json:"brands"`
+		Categories    []string `json:"categories_tags"`
+		Nutriments    struct {
+			EnergyKcal offNumber `json:"energy-kcal_100g"`
+			EnergyKJ   offNumber `json:"energy_100g"`
+			Proteins   offNumber `json:"proteins_100g"`
+			Carbs      offNumber `json:"carbohydrates_100g"`
+			Fat        offNumber `json:"fat_100g"`
+		} `json:"nutriments"`
+	} `json:"product"`
+}
+
+// offNumber accepts numbers and numeric strings; Open Food Facts returns both.
+type offNumber struct {
+	value float64
+	set   bool
+}
+
+func (n *offNumber) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		// Free-text values such as "<0.5" are treated as missing.
+		return nil
+	}
+	n.value, n.set = v, true
+	return nil
+}
+
+// Lookup returns ErrNotFound for unknown products and for entries without a
+// name or energy value, which cannot be imported as foods.
+func (p *OpenFoodFacts) Lookup(ctx context.Context, barcode string) (Product, error) {
+	endpoint := fmt.Sprintf("%s/api/v2/product/%s.json?fields=%s", p.baseURL, url.PathEscape(barcode),
+		url.QueryEscape("code,product_name,product_name_en,brands,categories_tags,nutriments"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Product{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.userAgent != "" {
+		req.Header.Set("User-Agent", p.userAgent)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Product{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Product{}, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Product{}, fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode)
+	}
+	var body offResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return Product{}, fmt.Errorf("%w: decode product: %v", ErrUnavailable, err)
+	}
+	if body.Status != 1 {
+		return Product{}, ErrNotFound
+	}
+
+	product := body.Product
+	name := strings.TrimSpace(product.ProductName)
+	if name == "" {
+		name = strings.TrimSpace(product.ProductNameEN)
+	}
+	kcal := product.Nutriments.EnergyKcal.value
+	if !product.Nutriments.EnergyKcal.set {
+		if !product.Nutriments.EnergyKJ.set {
+			return Product{}, ErrNotFound
+		}
+		kcal = math.Round(product.Nutriments.EnergyKJ.value/kilojoulesPerKcal*10) / 10
+	}
+	if name == "" {
+		return Product{}, ErrNotFound
+	}
+	brand, _, _ := strings.Cut(product.Brands, ",")
+
+	return Product{
+		Barcode:        barcode,
+		Name:           name,
+		BrandName:      optionalString(brand),
+		KcalPer100g:    kcal,
+		ProteinPer100g: product.Nutriments.Proteins.value,
+		CarbsPer100g:   product.Nutriments.Carbs.value,
+		FatPer100g:     product.Nutriments.Fat.value,
+		Categories:     product.Categories,
+		Ref:            fmt.Sprintf("%s/product/%s", p.baseURL, url.PathEscape(barcode)),
+	}, nil
+}
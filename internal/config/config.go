@@ -10,31 +10,114 @@ import (
 )
 
 type Config struct {
-	AppEnv                 string
-	Port                   string
-	DatabaseURL            string
-	MigrationsPath         string
-	JWTSecret              string
-	JWTActiveKID           string
-	JWTKeys                map[string]string
+	AppEnv         string
+	Port           string
+	DatabaseURL    string
+	MigrationsPath string
+	JWTSecret      string
+	JWTActiveKID   string
+	JWTKeys        map[string]string
+	AccessTokenTTL time.Duration
+	// SessionCacheTTL bounds how long a revoked session keeps authenticating
+	// requests with its outstanding access tokens.
+	SessionCacheTTL        time.Duration
 	AuthLoginMaxAttempts   int
 	AuthLoginAttemptWindow time.Duration
 	AuthLoginLockoutWindow time.Duration
+	// AuthBreachedPasswordsFile is an optional path to a sorted SHA-1 breach
+	// list; when empty, breached-password screening is disabled.
+	AuthBreachedPasswordsFile string
+	AuthMagicLinkBaseURL      string
+	AuthMagicLinkTTL          time.Duration
+	MailOutboxDir             string
+	WebAuthnRPID              string
+	WebAuthnRPName            string
+	WebAuthnOrigins           []string
+	OIDCProviders             []OIDCProviderConfig
+	// AuthMagicLinkCooldown is how long repeated magic-link requests for an
+	// email are ignored; AuthMagicLinkMaxPending bounds the sends running
+	// at once.
+	AuthMagicLinkCooldown   time.Duration
+	AuthMagicLinkMaxPending int
+	// BarcodeProvider selects the external catalog consulted for unknown
+	// barcodes: empty (disabled), "openfoodfacts" or "file".
+	BarcodeProvider      string
+	BarcodeProviderFile  string
+	OpenFoodFactsBaseURL string
+	BarcodeMissTTL       time.Duration
+	// MediaStore selects where uploaded images are kept: "local" (files
+	// below MediaLocalDir, served by the API under /media) or "s3".
+	MediaStore          string
+	MediaLocalDir       string
+	MediaBaseURL        string
+	MediaMaxUploadBytes int
+	S3Endpoint          string
+	S3Region            string
+	S3Bucket            string
+	S3AccessKeyID       string
+	S3SecretKey         string
+	// RecipeRecalcSyncLimit is how many recipes a food change recalculates
+	// right away; recipes of more widely used foods are left stale and
+	// recalculated in the background every RecipeRecalcInterval.
+	RecipeRecalcSyncLimit int
+	RecipeRecalcInterval  time.Duration
+}
+
+// OIDCProviderConfig is one external sign-in provider. Providers are listed
+// in OIDC_PROVIDERS and configured with OIDC_<NAME>_* variables.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
 }
 
 func Load() (Config, error) {
 	cfg := Config{
-		AppEnv:                 getEnv("APP_ENV", "development"),
-		Port:                   getEnv("PORT", "8080"),
-		DatabaseURL:            os.Getenv("DATABASE_URL"),
-		MigrationsPath:         getEnv("MIGRATIONS_PATH", "file://internal/db/migrations"),
-		JWTSecret:              getEnv("JWT_SECRET", "change-me-dev-secret"),
-		JWTActiveKID:           getEnv("JWT_ACTIVE_KID", "v1"),
-		JWTKeys:                parseJWTKeys(getEnv("JWT_KEYS", "")),
-		AuthLoginMaxAttempts:   getEnvInt("AUTH_LOGIN_MAX_ATTEMPTS", 5),
-		AuthLoginAttemptWindow: time.Duration(getEnvInt("AUTH_LOGIN_WINDOW_MINUTES", 10)) * time.Minute,
-		AuthLoginLockoutWindow: time.Duration(getEnvInt("AUTH_LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		AppEnv:                    getEnv("APP_ENV", "development"),
+		Port:                      getEnv("PORT", "8080"),
+		DatabaseURL:               os.Getenv("DATABASE_URL"),
+		MigrationsPath:            getEnv("MIGRATIONS_PATH", "file://internal/db/migrations"),
+		JWTSecret:                 getEnv("JWT_SECRET", "change-me-dev-secret"),
+		JWTActiveKID:              getEnv("JWT_ACTIVE_KID", "v1"),
+		JWTKeys:                   parseJWTKeys(getEnv("JWT_KEYS", "")),
+		AccessTokenTTL:            time.Duration(getEnvInt("AUTH_ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute,
+		SessionCacheTTL:           time.Duration(getEnvInt("AUTH_SESSION_CACHE_SECONDS", 5)) * time.Second,
+		AuthLoginMaxAttempts:      getEnvInt("AUTH_LOGIN_MAX_ATTEMPTS", 5),
+		AuthLoginAttemptWindow:    time.Duration(getEnvInt("AUTH_LOGIN_WINDOW_MINUTES", 10)) * time.Minute,
+		AuthLoginLockoutWindow:    time.Duration(getEnvInt("AUTH_LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		AuthBreachedPasswordsFile: strings.TrimSpace(os.Getenv("AUTH_BREACHED_PASSWORDS_FILE")),
+		AuthMagicLinkBaseURL:      getEnv("AUTH_MAGIC_LINK_BASE_URL", "http://localhost:3000/auth/magic-link"),
+		AuthMagicLinkTTL:          time.Duration(getEnvInt("AUTH_MAGIC_LINK_TTL_MINUTES", 15)) * time.Minute,
+		AuthMagicLinkCooldown:     time.Duration(getEnvInt("AUTH_MAGIC_LINK_COOLDOWN_SECONDS", 60)) * time.Second,
+		AuthMagicLinkMaxPending:   getEnvInt("AUTH_MAGIC_LINK_MAX_PENDING", 16),
+		MailOutboxDir:             getEnv("MAIL_OUTBOX_DIR", "tmp/mail"),
+		WebAuthnRPID:              getEnv("WEBAUTHN_RP_ID", "localhost"),
+		WebAuthnRPName:            getEnv("WEBAUTHN_RP_NAME", "Goal Bite"),
+		WebAuthnOrigins:           parseList(getEnv("WEBAUTHN_ORIGINS", "http://localhost:3000")),
+		BarcodeProvider:           strings.ToLower(strings.TrimSpace(os.Getenv("BARCODE_PROVIDER"))),
+		BarcodeProviderFile:       strings.TrimSpace(os.Getenv("BARCODE_PROVIDER_FILE")),
+		OpenFoodFactsBaseURL:      getEnv("OPENFOODFACTS_BASE_URL", "https://world.openfoodfacts.org"),
+		BarcodeMissTTL:            time.Duration(getEnvInt("BARCODE_MISS_TTL_HOURS", 24)) * time.Hour,
+		MediaStore:                strings.ToLower(getEnv("MEDIA_STORE", "local")),
+		MediaLocalDir:             getEnv("MEDIA_LOCAL_DIR", "tmp/media"),
+		MediaBaseURL:              strings.TrimSpace(os.Getenv("MEDIA_BASE_URL")),
+		MediaMaxUploadBytes:       getEnvInt("MEDIA_MAX_UPLOAD_MB", 5) << 20,
+		S3Endpoint:                strings.TrimSpace(os.Getenv("S3_ENDPOINT")),
+		S3Region:                  getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:                  strings.TrimSpace(os.Getenv("S3_BUCKET")),
+		S3AccessKeyID:             strings.TrimSpace(os.Getenv("S3_ACCESS_KEY_ID")),
+		S3SecretKey:               os.Getenv("S3_SECRET_ACCESS_KEY"),
+		RecipeRecalcSyncLimit:     getEnvInt("RECIPE_RECALC_SYNC_LIMIT", 20),
+		RecipeRecalcInterval:      time.Duration(getEnvInt("RECIPE_RECALC_INTERVAL_SECONDS", 60)) * time.Second,
 	}
+	oidcProviders, err := loadOIDCProviders(getEnv("OIDC_PROVIDERS", ""))
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.OIDCProviders = oidcProviders
 	if len(cfg.JWTKeys) == 0 {
 		cfg.JWTKeys = map[string]string{
 			cfg.JWTActiveKID: cfg.JWTSecret,
@@ -59,6 +142,12 @@ func Load() (Config, error) {
 	if _, ok := cfg.JWTKeys[cfg.JWTActiveKID]; !ok {
 		return Config{}, errors.New("JWT_ACTIVE_KID must exist in JWT_KEYS")
 	}
+	if cfg.AccessTokenTTL <= 0 {
+		return Config{}, errors.New("AUTH_ACCESS_TOKEN_TTL_MINUTES must be > 0")
+	}
+	if cfg.SessionCacheTTL < 0 {
+		return Config{}, errors.New("AUTH_SESSION_CACHE_SECONDS must be >= 0")
+	}
 	if cfg.AuthLoginMaxAttempts <= 0 {
 		return Config{}, errors.New("AUTH_LOGIN_MAX_ATTEMPTS must be > 0")
 	}
@@ -68,6 +157,55 @@ func Load() (Config, error) {
 	if cfg.AuthLoginLockoutWindow <= 0 {
 		return Config{}, errors.New("AUTH_LOGIN_LOCKOUT_MINUTES must be > 0")
 	}
+	if cfg.AuthMagicLinkTTL <= 0 {
+		return Config{}, errors.New("AUTH_MAGIC_LINK_TTL_MINUTES must be > 0")
+	}
+	if cfg.AuthMagicLinkCooldown <= 0 {
+		return Config{}, errors.New("AUTH_MAGIC_LINK_COOLDOWN_SECONDS must be > 0")
+	}
+	if cfg.AuthMagicLinkMaxPending <= 0 {
+		return Config{}, errors.New("AUTH_MAGIC_LINK_MAX_PENDING must be > 0")
+	}
+	if cfg.WebAuthnRPID == "" {
+		return Config{}, errors.New("WEBAUTHN_RP_ID cannot be empty")
+	}
+	if len(cfg.WebAuthnOrigins) == 0 {
+		return Config{}, errors.New("WEBAUTHN_ORIGINS cannot be empty")
+	}
+	switch cfg.BarcodeProvider {
+	case "", "openfoodfacts":
+	case "file":
+		if cfg.BarcodeProviderFile == "" {
+			return Config{}, errors.New("BARCODE_PROVIDER_FILE is required when BARCODE_PROVIDER=file")
+		}
+	default:
+		return Config{}, fmt.Errorf("BARCODE_PROVIDER: unknown provider %q", cfg.BarcodeProvider)
+	}
+	if cfg.BarcodeMissTTL < 0 {
+		return Config{}, errors.New("BARCODE_MISS_TTL_HOURS must be >= 0")
+	}
+	switch cfg.MediaStore {
+	case "local":
+		if cfg.MediaBaseURL == "" {
+			cfg.MediaBaseURL = "http://localhost:" + cfg.Port + "/media"
+		}
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKeyID == "" || cfg.S3SecretKey == "" {
+			return Config{}, errors.New("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when MEDIA_STORE=s3")
+		}
+	default:
+		return Config{}, fmt.Errorf("MEDIA_STORE: unknown store %q", cfg.MediaStore)
+	}
+	// Uploads are read in one piece, and the API caps request bodies at 32 MB.
+	if cfg.MediaMaxUploadBytes <= 0 || cfg.MediaMaxUploadBytes > 32<<20 {
+		return Config{}, errors.New("MEDIA_MAX_UPLOAD_MB must be between 1 and 32")
+	}
+	if cfg.RecipeRecalcSyncLimit < 0 {
+		return Config{}, errors.New("RECIPE_RECALC_SYNC_LIMIT must be >= 0")
+	}
+	if cfg.RecipeRecalcInterval <= 0 {
+		return Config{}, errors.New("RECIPE_RECALC_INTERVAL_SECONDS must be > 0")
+	}
 	return cfg, nil
 }
 
@@ -115,3 +253,60 @@ func parseJWTKeys(raw string) map[string]string {
 	}
 	return out
 }
+
+func parseList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if v := strings.TrimSpace(part); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func loadOIDCProviders(raw string) ([]OIDCProviderConfig, error) {
+	var out []OIDCProviderConfig
+	seen := make(map[string]bool)
+	for _, name := range parseList(raw) {
+		name = strings.ToLower(name)
+		if !validOIDCProviderName(name) {
+			return nil, fmt.Errorf("OIDC_PROVIDERS: invalid provider name %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("OIDC_PROVIDERS: duplicate provider %q", name)
+		}
+		seen[name] = true
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       strings.TrimSpace(os.Getenv(prefix + "ISSUER")),
+			ClientID:     strings.TrimSpace(os.Getenv(prefix + "CLIENT_ID")),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  strings.TrimSpace(os.Getenv(prefix + "REDIRECT_URL")),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		switch {
+		case provider.Issuer == "":
+			return nil, fmt.Errorf("%sISSUER is required", prefix)
+		case provider.ClientID == "":
+			return nil, fmt.Errorf("%sCLIENT_ID is required", prefix)
+		case provider.RedirectURL == "":
+			return nil, fmt.Errorf("%sREDIRECT_URL is required", prefix)
+		}
+		out = append(out, provider)
+	}
+	return out, nil
+}
+
+func validOIDCProviderName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
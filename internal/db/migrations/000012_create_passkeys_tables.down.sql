@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_webauthn_challenges_expires_at;
+DROP TABLE IF EXISTS webauthn_challenges;
+DROP INDEX IF EXISTS idx_passkeys_user_id;
+DROP TABLE IF EXISTS passkeys;
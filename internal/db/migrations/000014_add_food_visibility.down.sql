@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_foods_visibility_user_id;
+DROP INDEX IF EXISTS idx_foods_barcode_shared_unique;
+DROP INDEX IF EXISTS idx_foods_barcode_private_unique;
+
+-- Restoring the global index fails if one barcode now exists in several
+-- scopes; resolve those rows before rolling back.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_foods_barcode_unique
+    ON foods(barcode)
+    WHERE barcode IS NOT NULL;
+
+ALTER TABLE foods
+    DROP CONSTRAINT IF EXISTS foods_visibility_check;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS visibility;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS is_admin;
@@ -0,0 +1,25 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'public';
+
+ALTER TABLE foods
+    ADD CONSTRAINT foods_visibility_check CHECK (visibility IN ('private', 'public', 'verified'));
+
+-- Foods created before visibility existed were shared with everyone, so they
+-- keep the 'public' default. New rows get their visibility from the API.
+ALTER TABLE foods
+    ALTER COLUMN visibility SET DEFAULT 'private';
+
+DROP INDEX IF EXISTS idx_foods_barcode_unique;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_foods_barcode_private_unique
+    ON foods(user_id, barcode)
+    WHERE barcode IS NOT NULL AND visibility = 'private';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_foods_barcode_shared_unique
+    ON foods(visibility, barcode)
+    WHERE barcode IS NOT NULL AND visibility IN ('public', 'verified');
+
+CREATE INDEX IF NOT EXISTS idx_foods_visibility_user_id ON foods(visibility, user_id);
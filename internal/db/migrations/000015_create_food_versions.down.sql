@@ -0,0 +1,10 @@
+ALTER TABLE recipe_ingredients
+    DROP COLUMN IF EXISTS food_version;
+
+ALTER TABLE meal_items
+    DROP COLUMN IF EXISTS food_version;
+
+DROP TABLE IF EXISTS food_versions;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS current_version;
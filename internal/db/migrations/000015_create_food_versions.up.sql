@@ -0,0 +1,36 @@
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS current_version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS food_versions (
+    id BIGSERIAL PRIMARY KEY,
+    food_id BIGINT NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    edited_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    restored_from INTEGER,
+    name TEXT NOT NULL,
+    brand_name TEXT,
+    barcode TEXT,
+    kcal_per_100g NUMERIC(12,4) NOT NULL,
+    protein_per_100g NUMERIC(12,4) NOT NULL,
+    carbs_per_100g NUMERIC(12,4) NOT NULL,
+    fat_per_100g NUMERIC(12,4) NOT NULL,
+    visibility TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_food_versions_food_version UNIQUE (food_id, version)
+);
+
+-- Existing foods start their history at version 1, attributed to the owner.
+INSERT INTO food_versions (
+    food_id, version, edited_by, name, brand_name, barcode,
+    kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, visibility, created_at
+)
+SELECT id, 1, user_id, name, brand_name, barcode,
+       kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, visibility, updated_at
+FROM foods
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE meal_items
+    ADD COLUMN IF NOT EXISTS food_version INTEGER;
+
+ALTER TABLE recipe_ingredients
+    ADD COLUMN IF NOT EXISTS food_version INTEGER;
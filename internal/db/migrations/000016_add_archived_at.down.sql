@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_foods_barcode_private_unique;
+DROP INDEX IF EXISTS idx_foods_barcode_shared_unique;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_foods_barcode_private_unique
+    ON foods(user_id, barcode)
+    WHERE barcode IS NOT NULL AND visibility = 'private';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_foods_barcode_shared_unique
+    ON foods(visibility, barcode)
+    WHERE barcode IS NOT NULL AND visibility IN ('public', 'verified');
+
+ALTER TABLE recipes
+    DROP COLUMN IF EXISTS archived_at;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,17 @@
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+ALTER TABLE recipes
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+-- Archived foods give up their barcode so a replacement entry can take it.
+DROP INDEX IF EXISTS idx_foods_barcode_private_unique;
+DROP INDEX IF EXISTS idx_foods_barcode_shared_unique;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_foods_barcode_private_unique
+    ON foods(user_id, barcode)
+    WHERE barcode IS NOT NULL AND visibility = 'private' AND archived_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_foods_barcode_shared_unique
+    ON foods(visibility, barcode)
+    WHERE barcode IS NOT NULL AND visibility IN ('public', 'verified') AND archived_at IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS food_merges;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS merged_into_id;
@@ -0,0 +1,15 @@
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS merged_into_id BIGINT REFERENCES foods(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS food_merges (
+    id BIGSERIAL PRIMARY KEY,
+    survivor_food_id BIGINT NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+    merged_food_id BIGINT NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+    merged_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    meal_items_moved INTEGER NOT NULL DEFAULT 0,
+    recipe_ingredients_moved INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_food_merges_survivor_food_id ON food_merges(survivor_food_id);
+CREATE INDEX IF NOT EXISTS idx_food_merges_merged_food_id ON food_merges(merged_food_id);
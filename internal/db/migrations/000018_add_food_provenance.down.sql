@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS barcode_lookup_misses;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS imported_at,
+    DROP COLUMN IF EXISTS source_ref,
+    DROP COLUMN IF EXISTS source;
@@ -0,0 +1,11 @@
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'user',
+    ADD COLUMN IF NOT EXISTS source_ref TEXT,
+    ADD COLUMN IF NOT EXISTS imported_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS barcode_lookup_misses (
+    source TEXT NOT NULL,
+    barcode TEXT NOT NULL,
+    checked_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (source, barcode)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE food_versions
+    DROP COLUMN IF EXISTS alcohol_per_100g;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS alcohol_per_100g;
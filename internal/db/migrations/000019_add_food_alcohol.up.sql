@@ -0,0 +1,6 @@
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS alcohol_per_100g NUMERIC(12,4) NOT NULL DEFAULT 0
+        CONSTRAINT foods_alcohol_per_100g_check CHECK (alcohol_per_100g >= 0 AND alcohol_per_100g <= 100);
+
+ALTER TABLE food_versions
+    ADD COLUMN IF NOT EXISTS alcohol_per_100g NUMERIC(12,4) NOT NULL DEFAULT 0;
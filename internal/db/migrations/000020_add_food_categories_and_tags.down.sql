@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_foods_tags;
+DROP INDEX IF EXISTS idx_foods_category_id;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS food_category_aliases;
+DROP TABLE IF EXISTS food_categories;
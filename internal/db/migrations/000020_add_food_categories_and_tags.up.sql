@@ -0,0 +1,107 @@
+CREATE TABLE IF NOT EXISTS food_categories (
+    id BIGSERIAL PRIMARY KEY,
+    parent_id BIGINT REFERENCES food_categories(id) ON DELETE RESTRICT,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_food_categories_parent_id ON food_categories(parent_id);
+
+-- Category identifiers used by external catalogs, such as Open Food Facts
+-- taxonomy tags, mapped onto our categories for imports.
+CREATE TABLE IF NOT EXISTS food_category_aliases (
+    alias TEXT PRIMARY KEY,
+    category_id BIGINT NOT NULL REFERENCES food_categories(id) ON DELETE CASCADE
+);
+
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS category_id BIGINT REFERENCES food_categories(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_foods_category_id ON foods(category_id);
+CREATE INDEX IF NOT EXISTS idx_foods_tags ON foods USING GIN (tags jsonb_path_ops);
+
+INSERT INTO food_categories (slug, name) VALUES
+    ('dairy', 'Dairy'),
+    ('cereals', 'Cereals and grains'),
+    ('meat', 'Meat'),
+    ('fish-seafood', 'Fish and seafood'),
+    ('eggs', 'Eggs'),
+    ('fruits', 'Fruits'),
+    ('vegetables', 'Vegetables'),
+    ('legumes', 'Legumes'),
+    ('nuts-seeds', 'Nuts and seeds'),
+    ('fats-oils', 'Fats and oils'),
+    ('sweets-snacks', 'Sweets and snacks'),
+    ('beverages', 'Beverages'),
+    ('condiments', 'Sauces and condiments'),
+    ('prepared-meals', 'Prepared meals')
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO food_categories (parent_id, slug, name)
+SELECT p.id, c.slug, c.name
+FROM (VALUES
+    ('dairy', 'milk', 'Milk'),
+    ('dairy', 'yogurt', 'Yogurt'),
+    ('dairy', 'cheese', 'Cheese'),
+    ('cereals', 'breakfast-cereals', 'Breakfast cereals'),
+    ('cereals', 'bread', 'Bread'),
+    ('cereals', 'pasta', 'Pasta'),
+    ('cereals', 'rice', 'Rice'),
+    ('meat', 'poultry', 'Poultry'),
+    ('meat', 'red-meat', 'Red meat'),
+    ('meat', 'processed-meat', 'Processed meat'),
+    ('sweets-snacks', 'chocolate', 'Chocolate'),
+    ('sweets-snacks', 'biscuits', 'Biscuits'),
+    ('sweets-snacks', 'savory-snacks', 'Savory snacks'),
+    ('beverages', 'soft-drinks', 'Soft drinks'),
+    ('beverages', 'juices', 'Juices'),
+    ('beverages', 'alcoholic-beverages', 'Alcoholic beverages')
+) AS c(parent_slug, slug, name)
+JOIN food_categories p ON p.slug = c.parent_slug
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO food_category_aliases (alias, category_id)
+SELECT a.alias, c.id
+FROM (VALUES
+    ('en:dairies', 'dairy'),
+    ('en:milks', 'milk'),
+    ('en:yogurts', 'yogurt'),
+    ('en:greek-style-yogurts', 'yogurt'),
+    ('en:cheeses', 'cheese'),
+    ('en:cereals-and-potatoes', 'cereals'),
+    ('en:cereals-and-their-products', 'cereals'),
+    ('en:breakfast-cereals', 'breakfast-cereals'),
+    ('en:breads', 'bread'),
+    ('en:pastas', 'pasta'),
+    ('en:rices', 'rice'),
+    ('en:meats', 'meat'),
+    ('en:poultries', 'poultry'),
+    ('en:beef', 'red-meat'),
+    ('en:prepared-meats', 'processed-meat'),
+    ('en:seafood', 'fish-seafood'),
+    ('en:fishes', 'fish-seafood'),
+    ('en:eggs', 'eggs'),
+    ('en:fruits', 'fruits'),
+    ('en:vegetables', 'vegetables'),
+    ('en:legumes', 'legumes'),
+    ('en:nuts', 'nuts-seeds'),
+    ('en:seeds', 'nuts-seeds'),
+    ('en:fats', 'fats-oils'),
+    ('en:vegetable-oils', 'fats-oils'),
+    ('en:snacks', 'sweets-snacks'),
+    ('en:sweet-snacks', 'sweets-snacks'),
+    ('en:chocolates', 'chocolate'),
+    ('en:biscuits', 'biscuits'),
+    ('en:salty-snacks', 'savory-snacks'),
+    ('en:beverages', 'beverages'),
+    ('en:sodas', 'soft-drinks'),
+    ('en:fruit-juices', 'juices'),
+    ('en:alcoholic-beverages', 'alcoholic-beverages'),
+    ('en:sauces', 'condiments'),
+    ('en:condiments', 'condiments'),
+    ('en:meals', 'prepared-meals')
+) AS a(alias, slug)
+JOIN food_categories c ON c.slug = a.slug
+ON CONFLICT (alias) DO NOTHING;
@@ -0,0 +1,10 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS required_diet_flags,
+    DROP COLUMN IF EXISTS avoid_allergens;
+
+DROP INDEX IF EXISTS idx_foods_diet_flags;
+DROP INDEX IF EXISTS idx_foods_allergens;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS diet_flags,
+    DROP COLUMN IF EXISTS allergens;
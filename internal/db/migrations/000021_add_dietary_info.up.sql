@@ -0,0 +1,13 @@
+-- Allergens and diet flags are stored as sorted JSON arrays of the values
+-- known to internal/domain/dietary. Recipes derive theirs from their
+-- ingredients when read.
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS allergens JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS diet_flags JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_foods_allergens ON foods USING GIN (allergens);
+CREATE INDEX IF NOT EXISTS idx_foods_diet_flags ON foods USING GIN (diet_flags jsonb_path_ops);
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS avoid_allergens JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS required_diet_flags JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,14 @@
+ALTER TABLE meals
+    DROP COLUMN IF EXISTS thumbnail_url,
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS image_key;
+
+ALTER TABLE recipes
+    DROP COLUMN IF EXISTS thumbnail_url,
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS image_key;
+
+ALTER TABLE foods
+    DROP COLUMN IF EXISTS thumbnail_url,
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS image_key;
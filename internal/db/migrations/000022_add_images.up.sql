@@ -0,0 +1,16 @@
+-- image_key locates the display image in the object store; its thumbnail is
+-- stored next to it. The URLs are resolved when the image is uploaded.
+ALTER TABLE foods
+    ADD COLUMN IF NOT EXISTS image_key TEXT,
+    ADD COLUMN IF NOT EXISTS image_url TEXT,
+    ADD COLUMN IF NOT EXISTS thumbnail_url TEXT;
+
+ALTER TABLE recipes
+    ADD COLUMN IF NOT EXISTS image_key TEXT,
+    ADD COLUMN IF NOT EXISTS image_url TEXT,
+    ADD COLUMN IF NOT EXISTS thumbnail_url TEXT;
+
+ALTER TABLE meals
+    ADD COLUMN IF NOT EXISTS image_key TEXT,
+    ADD COLUMN IF NOT EXISTS image_url TEXT,
+    ADD COLUMN IF NOT EXISTS thumbnail_url TEXT;
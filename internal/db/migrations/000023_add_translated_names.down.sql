@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS languages;
+
+DROP TABLE IF EXISTS recipe_names;
+DROP TABLE IF EXISTS food_names;
@@ -0,0 +1,32 @@
+-- Translated names and synonyms of foods and recipes, one row per name.
+-- search holds the name stemmed with the text search configuration of the
+-- row's language; the application fills it on insert because the
+-- configuration depends on locale.
+CREATE TABLE IF NOT EXISTS food_names (
+    id BIGSERIAL PRIMARY KEY,
+    food_id BIGINT NOT NULL REFERENCES foods(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    synonym BOOLEAN NOT NULL DEFAULT FALSE,
+    search TSVECTOR NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_food_names_food_id ON food_names(food_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_food_names_primary ON food_names(food_id, locale) WHERE NOT synonym;
+CREATE INDEX IF NOT EXISTS idx_food_names_search ON food_names USING GIN (search);
+
+CREATE TABLE IF NOT EXISTS recipe_names (
+    id BIGSERIAL PRIMARY KEY,
+    recipe_id BIGINT NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    name TEXT NOT NULL,
+    synonym BOOLEAN NOT NULL DEFAULT FALSE,
+    search TSVECTOR NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_names_recipe_id ON recipe_names(recipe_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_names_primary ON recipe_names(recipe_id, locale) WHERE NOT synonym;
+CREATE INDEX IF NOT EXISTS idx_recipe_names_search ON recipe_names USING GIN (search);
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS languages JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_recipe_ingredients_food_id;
+DROP INDEX IF EXISTS idx_recipes_stale;
+
+ALTER TABLE recipes
+    DROP COLUMN IF EXISTS stale;
@@ -0,0 +1,22 @@
+-- stale marks recipes whose per-100g values no longer match their ingredient
+-- foods, because a food's nutrition changed after the last recalculation.
+ALTER TABLE recipes
+    ADD COLUMN IF NOT EXISTS stale BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_stale ON recipes(id) WHERE stale;
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_food_id ON recipe_ingredients(food_id);
+
+-- Recipes computed from an older food version with different values, or
+-- from an unknown version, are stale already.
+UPDATE recipes SET stale = TRUE
+WHERE EXISTS (
+    SELECT 1 FROM recipe_ingredients ri
+    JOIN foods f ON f.id = ri.food_id
+    LEFT JOIN food_versions v ON v.food_id = ri.food_id AND v.version = ri.food_version
+    WHERE ri.recipe_id = recipes.id
+    AND (v.id IS NULL
+        OR v.kcal_per_100g <> f.kcal_per_100g
+        OR v.protein_per_100g <> f.protein_per_100g
+        OR v.carbs_per_100g <> f.carbs_per_100g
+        OR v.fat_per_100g <> f.fat_per_100g)
+);
@@ -0,0 +1,9 @@
+DELETE FROM recipe_ingredients WHERE sub_recipe_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_recipe_ingredients_sub_recipe_id;
+
+ALTER TABLE recipe_ingredients
+    DROP CONSTRAINT IF EXISTS recipe_ingredients_not_self_check,
+    DROP CONSTRAINT IF EXISTS recipe_ingredients_source_check,
+    DROP COLUMN IF EXISTS sub_recipe_id,
+    ALTER COLUMN food_id SET NOT NULL;
@@ -0,0 +1,9 @@
+-- An ingredient is either a food or another recipe, used by the gram like a
+-- food through the sub-recipe's per-100g values.
+ALTER TABLE recipe_ingredients
+    ALTER COLUMN food_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS sub_recipe_id BIGINT REFERENCES recipes(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT recipe_ingredients_source_check CHECK ((food_id IS NULL) <> (sub_recipe_id IS NULL)),
+    ADD CONSTRAINT recipe_ingredients_not_self_check CHECK (sub_recipe_id <> recipe_id);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_sub_recipe_id ON recipe_ingredients(sub_recipe_id);
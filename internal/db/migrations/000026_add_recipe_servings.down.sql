@@ -0,0 +1,8 @@
+ALTER TABLE meal_items
+    DROP CONSTRAINT IF EXISTS meal_items_servings_check,
+    DROP COLUMN IF EXISTS servings;
+
+ALTER TABLE recipes
+    DROP CONSTRAINT IF EXISTS recipes_servings_check,
+    DROP COLUMN IF EXISTS serving_name,
+    DROP COLUMN IF EXISTS servings;
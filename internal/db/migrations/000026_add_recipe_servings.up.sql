@@ -0,0 +1,11 @@
+-- servings splits a recipe's yield into equal portions named serving_name.
+ALTER TABLE recipes
+    ADD COLUMN IF NOT EXISTS servings INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS serving_name TEXT NOT NULL DEFAULT 'serving',
+    ADD CONSTRAINT recipes_servings_check CHECK (servings > 0 AND servings <= 1000);
+
+-- Meal items logged in servings of a recipe keep the count; weight_g holds
+-- the grams it converted to.
+ALTER TABLE meal_items
+    ADD COLUMN IF NOT EXISTS servings NUMERIC(12,4),
+    ADD CONSTRAINT meal_items_servings_check CHECK (servings IS NULL OR (servings > 0 AND recipe_id IS NOT NULL));
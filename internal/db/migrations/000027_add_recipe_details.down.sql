@@ -0,0 +1,13 @@
+ALTER TABLE recipe_ingredients
+    DROP COLUMN IF EXISTS note;
+
+DROP INDEX IF EXISTS idx_recipes_tags;
+
+ALTER TABLE recipes
+    DROP CONSTRAINT IF EXISTS recipes_cook_time_min_check,
+    DROP CONSTRAINT IF EXISTS recipes_prep_time_min_check,
+    DROP COLUMN IF EXISTS instructions,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS cook_time_min,
+    DROP COLUMN IF EXISTS prep_time_min,
+    DROP COLUMN IF EXISTS description;
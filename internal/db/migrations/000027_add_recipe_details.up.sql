@@ -0,0 +1,15 @@
+-- Kitchen details of a recipe. Times are in minutes with 0 for not given;
+-- tags and instructions are JSON string lists, instructions in cooking order.
+ALTER TABLE recipes
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS prep_time_min INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS cook_time_min INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS instructions JSONB NOT NULL DEFAULT '[]',
+    ADD CONSTRAINT recipes_prep_time_min_check CHECK (prep_time_min >= 0 AND prep_time_min <= 10080),
+    ADD CONSTRAINT recipes_cook_time_min_check CHECK (cook_time_min >= 0 AND cook_time_min <= 10080);
+
+CREATE INDEX IF NOT EXISTS idx_recipes_tags ON recipes USING GIN (tags jsonb_path_ops);
+
+ALTER TABLE recipe_ingredients
+    ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_recipes_forked_from_id;
+
+ALTER TABLE recipes
+    DROP COLUMN IF EXISTS forked_from_id;
@@ -0,0 +1,6 @@
+-- forked_from_id is the recipe a fork was copied from. Recipes are archived
+-- rather than deleted, so the link normally outlives the original.
+ALTER TABLE recipes
+    ADD COLUMN IF NOT EXISTS forked_from_id BIGINT REFERENCES recipes(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_recipes_forked_from_id ON recipes(forked_from_id);
@@ -0,0 +1,13 @@
+ALTER TABLE meal_items
+    DROP CONSTRAINT IF EXISTS meal_items_cooked_weight_check,
+    DROP COLUMN IF EXISTS cooking_method_id,
+    DROP COLUMN IF EXISTS cooked_weight_g;
+
+ALTER TABLE recipes
+    DROP COLUMN IF EXISTS yield_estimated;
+
+ALTER TABLE recipe_ingredients
+    DROP COLUMN IF EXISTS cooking_method_id;
+
+DROP TABLE IF EXISTS cooking_yield_factors;
+DROP TABLE IF EXISTS cooking_methods;
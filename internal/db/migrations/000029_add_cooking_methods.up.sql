@@ -0,0 +1,93 @@
+CREATE TABLE IF NOT EXISTS cooking_methods (
+    id BIGSERIAL PRIMARY KEY,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- How food changes when cooked a given way. yield_factor is cooked weight
+-- over raw weight, and moisture_retention the share of the raw water still
+-- there afterwards, above 1 for foods that take up water. A NULL category is
+-- the method's default for foods no category factor covers.
+CREATE TABLE IF NOT EXISTS cooking_yield_factors (
+    id BIGSERIAL PRIMARY KEY,
+    cooking_method_id BIGINT NOT NULL REFERENCES cooking_methods(id) ON DELETE CASCADE,
+    category_id BIGINT REFERENCES food_categories(id) ON DELETE CASCADE,
+    yield_factor NUMERIC(6,3) NOT NULL CHECK (yield_factor > 0),
+    moisture_retention NUMERIC(6,3) NOT NULL CHECK (moisture_retention >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cooking_yield_factors_method_category
+    ON cooking_yield_factors(cooking_method_id, COALESCE(category_id, 0));
+
+INSERT INTO cooking_methods (slug, name) VALUES
+    ('boiled', 'Boiled'),
+    ('steamed', 'Steamed'),
+    ('grilled', 'Grilled'),
+    ('roasted', 'Roasted'),
+    ('pan-fried', 'Pan-fried'),
+    ('baked', 'Baked')
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO cooking_yield_factors (cooking_method_id, category_id, yield_factor, moisture_retention)
+SELECT m.id, c.id, f.yield_factor, f.moisture_retention
+FROM (VALUES
+    ('boiled', NULL, 1.0, 1.0),
+    ('boiled', 'cereals', 2.5, 16.0),
+    ('boiled', 'pasta', 2.2, 13.0),
+    ('boiled', 'rice', 2.8, 16.0),
+    ('boiled', 'legumes', 2.4, 15.0),
+    ('boiled', 'vegetables', 0.95, 0.97),
+    ('boiled', 'meat', 0.7, 0.6),
+    ('boiled', 'poultry', 0.75, 0.65),
+    ('boiled', 'fish-seafood', 0.85, 0.8),
+    ('boiled', 'eggs', 1.0, 1.0),
+    ('steamed', NULL, 0.95, 0.95),
+    ('steamed', 'rice', 2.5, 13.5),
+    ('steamed', 'vegetables', 0.95, 0.97),
+    ('steamed', 'fish-seafood', 0.85, 0.82),
+    ('grilled', NULL, 0.75, 0.68),
+    ('grilled', 'meat', 0.7, 0.6),
+    ('grilled', 'poultry', 0.72, 0.64),
+    ('grilled', 'processed-meat', 0.8, 0.75),
+    ('grilled', 'fish-seafood', 0.8, 0.75),
+    ('grilled', 'vegetables', 0.8, 0.78),
+    ('roasted', NULL, 0.75, 0.68),
+    ('roasted', 'meat', 0.7, 0.62),
+    ('roasted', 'poultry', 0.7, 0.62),
+    ('roasted', 'vegetables', 0.75, 0.72),
+    ('roasted', 'nuts-seeds', 0.98, 0.6),
+    ('pan-fried', NULL, 0.8, 0.72),
+    ('pan-fried', 'meat', 0.72, 0.6),
+    ('pan-fried', 'poultry', 0.75, 0.65),
+    ('pan-fried', 'fish-seafood', 0.8, 0.75),
+    ('pan-fried', 'eggs', 0.9, 0.88),
+    ('pan-fried', 'vegetables', 0.85, 0.8),
+    ('baked', NULL, 0.85, 0.8),
+    ('baked', 'poultry', 0.75, 0.66),
+    ('baked', 'fish-seafood', 0.8, 0.76),
+    ('baked', 'vegetables', 0.8, 0.78)
+) AS f(method_slug, category_slug, yield_factor, moisture_retention)
+JOIN cooking_methods m ON m.slug = f.method_slug
+LEFT JOIN food_categories c ON c.slug = f.category_slug
+WHERE f.category_slug IS NULL OR c.id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- A recipe ingredient may name how it is cooked, which the yield estimate of
+-- a recipe without a stated yield uses. yield_estimated marks such recipes.
+ALTER TABLE recipe_ingredients
+    ADD COLUMN IF NOT EXISTS cooking_method_id BIGINT REFERENCES cooking_methods(id) ON DELETE RESTRICT;
+
+ALTER TABLE recipes
+    ADD COLUMN IF NOT EXISTS yield_estimated BOOLEAN NOT NULL DEFAULT false;
+
+-- Foods logged by cooked weight keep it with the method; weight_g holds the
+-- raw weight it converted to.
+ALTER TABLE meal_items
+    ADD COLUMN IF NOT EXISTS cooked_weight_g NUMERIC(12,4),
+    ADD COLUMN IF NOT EXISTS cooking_method_id BIGINT REFERENCES cooking_methods(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT meal_items_cooked_weight_check CHECK (
+        (cooked_weight_g IS NULL AND cooking_method_id IS NULL)
+        OR (cooked_weight_g > 0 AND cooking_method_id IS NOT NULL AND food_id IS NOT NULL)
+    );
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS shopping_lists (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shopping_lists_user_id_created_at ON shopping_lists(user_id, created_at);
+
+-- One row per food: the raw weight of it that the recipes or meals the list
+-- was made from need, merged across them.
+CREATE TABLE IF NOT EXISTS shopping_list_items (
+    id BIGSERIAL PRIMARY KEY,
+    shopping_list_id BIGINT NOT NULL REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    food_id BIGINT NOT NULL REFERENCES foods(id) ON DELETE RESTRICT,
+    weight_g NUMERIC(12,4) NOT NULL CHECK (weight_g > 0),
+    checked BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT shopping_list_items_list_food_unique UNIQUE (shopping_list_id, food_id)
+);
@@ -0,0 +1,20 @@
+-- A link that lets anyone holding its token view one recipe or meal without
+-- signing in. Only the SHA-256 of the token is stored, so a leaked database
+-- does not leak working links.
+CREATE TABLE IF NOT EXISTS share_links (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL,
+    recipe_id BIGINT REFERENCES recipes(id) ON DELETE CASCADE,
+    meal_id BIGINT REFERENCES meals(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    access_count BIGINT NOT NULL DEFAULT 0,
+    last_accessed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT share_links_token_hash_unique UNIQUE (token_hash),
+    CONSTRAINT share_links_one_target CHECK ((recipe_id IS NULL) <> (meal_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_user_id_created_at ON share_links(user_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE meal_items
+    DROP CONSTRAINT IF EXISTS meal_items_water_change_check,
+    DROP COLUMN IF EXISTS water_change_g;
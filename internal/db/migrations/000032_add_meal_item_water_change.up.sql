@@ -0,0 +1,7 @@
+-- Water a food logged by its cooked weight took up (or lost, when negative)
+-- while cooking, estimated from its cooking factor's moisture_retention.
+-- Items logged before this column have none.
+ALTER TABLE meal_items
+    ADD COLUMN IF NOT EXISTS water_change_g NUMERIC(12,4),
+    ADD CONSTRAINT meal_items_water_change_check
+        CHECK (water_change_g IS NULL OR cooked_weight_g IS NOT NULL);
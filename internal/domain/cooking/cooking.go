@@ -0,0 +1,52 @@
+package cooking
+
+import "time"
+
+// Method is a way of cooking food, such as boiling or grilling. Its factors
+// say how foods of each category change when cooked that way. The methods
+// and factors are seeded by migrations.
+type Method struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Factors   []Factor  `json:"factors" gorm:"-"`
+}
+
+func (Method) TableName() string {
+	return "cooking_methods"
+}
+
+// Factor is how foods of a category change when cooked by a method.
+// YieldFactor is the cooked weight over the raw weight, and
+// MoistureRetention the share of the raw water still there afterwards,
+// above 1 for foods that take up water, such as pasta. A factor without a
+// category is the method's default.
+type Factor struct {
+	ID                uint    `json:"-" gorm:"primaryKey"`
+	MethodID          uint    `json:"-" gorm:"column:cooking_method_id"`
+	CategoryID        *uint   `json:"category_id" gorm:"column:category_id"`
+	YieldFactor       float64 `json:"yield_factor" gorm:"column:yield_factor"`
+	MoistureRetention float64 `json:"moisture_retention" gorm:"column:moisture_retention"`
+}
+
+func (Factor) TableName() string {
+	return "cooking_yield_factors"
+}
+
+// CookedWeightG is what rawG grams weigh after cooking.
+func (f Factor) CookedWeightG(rawG float64) float64 {
+	return rawG * f.YieldFactor
+}
+
+// RawWeightG is what cookedG grams weighed before cooking.
+func (f Factor) RawWeightG(cookedG float64) float64 {
+	return cookedG / f.YieldFactor
+}
+
+// WaterChangeG estimates the water rawG grams of a food holding
+// waterPer100g grams of water per 100 g take up while cooking; it is
+// negative when they lose water.
+func (f Factor) WaterChangeG(rawG, waterPer100g float64) float64 {
+	return rawG * waterPer100g / 100 * (f.MoistureRetention - 1)
+}
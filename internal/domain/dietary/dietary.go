@@ -0,0 +1,150 @@
+// Package dietary holds the allergen and diet vocabularies shared by foods,
+// recipes and user restrictions.
+package dietary
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The 14 major allergens that EU food labels must declare (Regulation (EU)
+// No 1169/2011, Annex II).
+const (
+	AllergenGluten      = "gluten"
+	AllergenCrustaceans = "crustaceans"
+	AllergenEggs        = "eggs"
+	AllergenFish        = "fish"
+	AllergenPeanuts     = "peanuts"
+	AllergenSoybeans    = "soybeans"
+	AllergenMilk        = "milk"
+	AllergenNuts        = "nuts"
+	AllergenCelery      = "celery"
+	AllergenMustard     = "mustard"
+	AllergenSesame      = "sesame"
+	AllergenSulphites   = "sulphites"
+	AllergenLupin       = "lupin"
+	AllergenMolluscs    = "molluscs"
+)
+
+const (
+	DietVegan      = "vegan"
+	DietVegetarian = "vegetarian"
+	DietGlutenFree = "gluten_free"
+	DietHalal      = "halal"
+)
+
+// Allergens lists every known allergen in alphabetical order.
+var Allergens = []string{
+	AllergenCelery, AllergenCrustaceans, AllergenEggs, AllergenFish, AllergenGluten, AllergenLupin, AllergenMilk,
+	AllergenMolluscs, AllergenMustard, AllergenNuts, AllergenPeanuts, AllergenSesame, AllergenSoybeans, AllergenSulphites,
+}
+
+// DietFlags lists every known diet flag in alphabetical order.
+var DietFlags = []string{DietGlutenFree, DietHalal, DietVegan, DietVegetarian}
+
+// excludedBy names the allergens a diet flag rules out. A food cannot claim
+// the flag while declaring one of them.
+var excludedBy = map[string][]string{
+	DietVegan:      {AllergenCrustaceans, AllergenEggs, AllergenFish, AllergenMilk, AllergenMolluscs},
+	DietVegetarian: {AllergenCrustaceans, AllergenFish, AllergenMolluscs},
+	DietGlutenFree: {AllergenGluten},
+}
+
+const (
+	ConflictAllergen = "allergen"
+	ConflictDiet     = "diet"
+)
+
+// Conflict is one way an item breaks a user's restrictions.
+type Conflict struct {
+	Code    string `json:"code"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+// Restrictions are the allergens a user avoids and the diet flags every item
+// they eat must carry.
+type Restrictions struct {
+	AvoidAllergens    []string
+	RequiredDietFlags []string
+}
+
+func (r Restrictions) Empty() bool {
+	return len(r.AvoidAllergens) == 0 && len(r.RequiredDietFlags) == 0
+}
+
+// NormalizeAllergens lowercases and deduplicates allergens and sorts them. It
+// reports false for unknown values.
+func NormalizeAllergens(raw []string) ([]string, bool) {
+	return normalize(raw, Allergens)
+}
+
+// NormalizeDietFlags lowercases and deduplicates diet flags and sorts them.
+// Vegan implies vegetarian, so the latter is added when missing. It reports
+// false for unknown values.
+func NormalizeDietFlags(raw []string) ([]string, bool) {
+	flags, ok := normalize(raw, DietFlags)
+	if !ok {
+		return nil, false
+	}
+	if slices.Contains(flags, DietVegan) && !slices.Contains(flags, DietVegetarian) {
+		flags = append(flags, DietVegetarian)
+		slices.Sort(flags)
+	}
+	return flags, true
+}
+
+// Consistent reports whether no diet flag is contradicted by a declared
+// allergen, such as vegan milk chocolate. Both lists must be normalized.
+func Consistent(allergens, flags []string) bool {
+	for _, flag := range flags {
+		for _, a := range excludedBy[flag] {
+			if slices.Contains(allergens, a) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Conflicts lists the restrictions an item with the given allergens and diet
+// flags breaks, allergens first. An item lacking a required flag conflicts
+// even if nothing is known about it.
+func Conflicts(r Restrictions, allergens, flags []string) []Conflict {
+	var out []Conflict
+	for _, a := range r.AvoidAllergens {
+		if slices.Contains(allergens, a) {
+			out = append(out, Conflict{
+				Code:    ConflictAllergen,
+				Value:   a,
+				Message: fmt.Sprintf("contains %s, which you avoid", a),
+			})
+		}
+	}
+	for _, flag := range r.RequiredDietFlags {
+		if !slices.Contains(flags, flag) {
+			out = append(out, Conflict{
+				Code:    ConflictDiet,
+				Value:   flag,
+				Message: fmt.Sprintf("not marked %s", strings.ReplaceAll(flag, "_", "-")),
+			})
+		}
+	}
+	return out
+}
+
+func normalize(raw, known []string) ([]string, bool) {
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !slices.Contains(known, v) {
+			return nil, false
+		}
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	slices.Sort(out)
+	return out, true
+}
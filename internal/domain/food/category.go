@@ -0,0 +1,78 @@
+package food
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxTags      = 20
+	MaxTagLength = 32
+)
+
+// Category is a node in the food taxonomy. Top-level categories have no
+// parent. The taxonomy is seeded by migrations.
+type Category struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ParentID  *uint     `json:"parent_id,omitempty" gorm:"column:parent_id"`
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Category) TableName() string {
+	return "food_categories"
+}
+
+// CategoryNode is a category with its subcategories.
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+// CategoryTree nests categories under their parents, keeping the input order
+// among siblings. Categories whose parent is missing are dropped.
+func CategoryTree(categories []Category) []CategoryNode {
+	children := make(map[uint][]Category, len(categories))
+	var roots []Category
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+	var build func([]Category) []CategoryNode
+	build = func(level []Category) []CategoryNode {
+		nodes := make([]CategoryNode, 0, len(level))
+		for _, c := range level {
+			nodes = append(nodes, CategoryNode{Category: c, Children: build(children[c.ID])})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+// NormalizeTags lowercases tags, collapses inner whitespace and drops
+// duplicates, keeping first-seen order. It reports false for empty or overlong
+// tags and for more than MaxTags distinct tags.
+func NormalizeTags(raw []string) ([]string, bool) {
+	tags := make([]string, 0, len(raw))
+	seen := make(map[string]struct{}, len(raw))
+	for _, t := range raw {
+		tag := strings.Join(strings.Fields(strings.ToLower(t)), " ")
+		if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, false
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	if len(tags) > MaxTags {
+		return nil, false
+	}
+	return tags, true
+}
@@ -1,17 +1,89 @@
 package food
 
-import "time"
+import (
+	"time"
+
+	"goal-bite-api/internal/domain/locale"
+)
+
+// Visibility controls who can see a food. Private foods are visible only to
+// their owner; public and verified foods are visible to everyone. Verified
+// foods have been checked by an admin and win barcode lookups.
+type Visibility string
+
+const (
+	VisibilityPrivate  Visibility = "private"
+	VisibilityPublic   Visibility = "public"
+	VisibilityVerified Visibility = "verified"
+)
+
+// SourceUser marks foods entered through the API. Imported foods carry the
+// name of the catalog they came from instead.
+const SourceUser = "user"
+
+func (v Visibility) Valid() bool {
+	switch v {
+	case VisibilityPrivate, VisibilityPublic, VisibilityVerified:
+		return true
+	}
+	return false
+}
 
 type Food struct {
-	ID             uint      `json:"id" gorm:"primaryKey"`
-	UserID         uint      `json:"user_id" gorm:"column:user_id;not null"`
-	Name           string    `json:"name"`
-	BrandName      *string   `json:"brand_name,omitempty" gorm:"column:brand_name"`
-	Barcode        *string   `json:"barcode,omitempty" gorm:"column:barcode"`
-	KcalPer100g    float64   `json:"kcal_per_100g" gorm:"column:kcal_per_100g"`
-	ProteinPer100g float64   `json:"protein_per_100g" gorm:"column:protein_per_100g"`
-	CarbsPer100g   float64   `json:"carbs_per_100g" gorm:"column:carbs_per_100g"`
-	FatPer100g     float64   `json:"fat_per_100g" gorm:"column:fat_per_100g"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"column:user_id;not null"`
+	Name           string     `json:"name"`
+	BrandName      *string    `json:"brand_name,omitempty" gorm:"column:brand_name"`
+	Barcode        *string    `json:"barcode,omitempty" gorm:"column:barcode"`
+	KcalPer100g    float64    `json:"kcal_per_100g" gorm:"column:kcal_per_100g"`
+	ProteinPer100g float64    `json:"protein_per_100g" gorm:"column:protein_per_100g"`
+	CarbsPer100g   float64    `json:"carbs_per_100g" gorm:"column:carbs_per_100g"`
+	FatPer100g     float64    `json:"fat_per_100g" gorm:"column:fat_per_100g"`
+	AlcoholPer100g float64    `json:"alcohol_per_100g" gorm:"column:alcohol_per_100g"`
+	Visibility     Visibility `json:"visibility" gorm:"column:visibility"`
+	CategoryID     *uint      `json:"category_id,omitempty" gorm:"column:category_id"`
+	Tags           []string   `json:"tags" gorm:"column:tags;serializer:json"`
+	Allergens      []string   `json:"allergens" gorm:"column:allergens;serializer:json"`
+	DietFlags      []string   `json:"diet_flags" gorm:"column:diet_flags;serializer:json"`
+	CurrentVersion int        `json:"version" gorm:"column:current_version"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty" gorm:"column:archived_at"`
+	MergedIntoID   *uint      `json:"merged_into_id,omitempty" gorm:"column:merged_into_id"`
+	Source         string     `json:"source" gorm:"column:source;default:user"`
+	SourceRef      *string    `json:"source_ref,omitempty" gorm:"column:source_ref"`
+	ImportedAt     *time.Time `json:"imported_at,omitempty" gorm:"column:imported_at"`
+	ImageKey       *string    `json:"-" gorm:"column:image_key"`
+	ImageURL       *string    `json:"image_url,omitempty" gorm:"column:image_url"`
+	ThumbnailURL   *string    `json:"thumbnail_url,omitempty" gorm:"column:thumbnail_url"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	// Translations are stored in food_names. DisplayName is the name to show
+	// the caller, set by Localize.
+	Translations []locale.Translation `json:"translations" gorm:"-"`
+	DisplayName  string               `json:"display_name" gorm:"-"`
+	// Warnings flags implausible nutrition values on create and update
+	// responses. It is not stored.
+	Warnings []NutritionWarning `json:"warnings,omitempty" gorm:"-"`
+}
+
+// Localize sets DisplayName to the translation in the first of languages
+// the food has one in, falling back to Name.
+func (f *Food) Localize(languages []string) {
+	f.DisplayName = locale.Pick(f.Name, f.Translations, languages)
+}
+
+// VisibleTo reports whether userID may see the food.
+func (f Food) VisibleTo(userID uint) bool {
+	return f.Visibility != VisibilityPrivate || f.UserID == userID
+}
+
+// Archived reports whether the food was removed from lists and search. It
+// still resolves by ID for meal items and recipes that already use it.
+func (f Food) Archived() bool {
+	return f.ArchivedAt != nil
+}
+
+// Imported reports whether the food came from an external catalog rather
+// than a user.
+func (f Food) Imported() bool {
+	return f.Source != "" && f.Source != SourceUser
 }
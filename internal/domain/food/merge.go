@@ -0,0 +1,20 @@
+package food
+
+import "time"
+
+// Merge records one food folded into a surviving food. Meal items and recipe
+// ingredients of the merged food were re-pointed to the survivor, and the
+// merged food was archived with MergedIntoID set.
+type Merge struct {
+	ID                     uint      `json:"id" gorm:"primaryKey"`
+	SurvivorFoodID         uint      `json:"survivor_food_id" gorm:"column:survivor_food_id"`
+	MergedFoodID           uint      `json:"merged_food_id" gorm:"column:merged_food_id"`
+	MergedBy               *uint     `json:"merged_by,omitempty" gorm:"column:merged_by"`
+	MealItemsMoved         int       `json:"meal_items_moved" gorm:"column:meal_items_moved"`
+	RecipeIngredientsMoved int       `json:"recipe_ingredients_moved" gorm:"column:recipe_ingredients_moved"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+func (Merge) TableName() string {
+	return "food_merges"
+}
@@ -0,0 +1,85 @@
+package food
+
+import (
+	"fmt"
+	"math"
+)
+
+// Atwater general factors in kcal per gram.
+const (
+	KcalPerGramProtein = 4
+	KcalPerGramCarbs   = 4
+	KcalPerGramFat     = 9
+	KcalPerGramAlcohol = 7
+)
+
+const (
+	WarningKcalMismatch     = "kcal_mismatch"
+	WarningMacrosExceed100g = "macros_exceed_100g"
+)
+
+const (
+	// Labels round and may count fibre differently, so stated energy may
+	// stray from the Atwater estimate by 20% or 20 kcal, whichever is larger.
+	KcalToleranceRatio = 0.2
+	KcalToleranceMin   = 20
+	// Rounded macro values of nearly pure foods, such as oil, can add up to
+	// slightly more than 100 g.
+	MacroMassTolerance = 1
+)
+
+// NutritionWarning describes per-100g values that contradict each other.
+type NutritionWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Nutrients are the per-100g values a food is checked on.
+type Nutrients struct {
+	Kcal    float64
+	Protein float64
+	Carbs   float64
+	Fat     float64
+	Alcohol float64
+}
+
+func (f Food) Nutrients() Nutrients {
+	return Nutrients{
+		Kcal:    f.KcalPer100g,
+		Protein: f.ProteinPer100g,
+		Carbs:   f.CarbsPer100g,
+		Fat:     f.FatPer100g,
+		Alcohol: f.AlcoholPer100g,
+	}
+}
+
+// EstimatedKcal applies the Atwater factors to the macros.
+func (n Nutrients) EstimatedKcal() float64 {
+	return n.Protein*KcalPerGramProtein + n.Carbs*KcalPerGramCarbs + n.Fat*KcalPerGramFat + n.Alcohol*KcalPerGramAlcohol
+}
+
+// EstimatedWaterPer100g takes whatever 100 g leaves after the macros as
+// water. Fibre and ash count as water too, so it runs high for dry foods.
+func (n Nutrients) EstimatedWaterPer100g() float64 {
+	return math.Max(0, 100-n.Protein-n.Carbs-n.Fat-n.Alcohol)
+}
+
+// CheckNutrition returns a warning for each consistency rule n breaks, or
+// nil when the values are plausible.
+func CheckNutrition(n Nutrients) []NutritionWarning {
+	var out []NutritionWarning
+	estimate := n.EstimatedKcal()
+	if math.Abs(n.Kcal-estimate) > math.Max(KcalToleranceMin, estimate*KcalToleranceRatio) {
+		out = append(out, NutritionWarning{
+			Code:    WarningKcalMismatch,
+			Message: fmt.Sprintf("%.0f kcal stated but macros give about %.0f kcal", n.Kcal, estimate),
+		})
+	}
+	if mass := n.Protein + n.Carbs + n.Fat + n.Alcohol; mass > 100+MacroMassTolerance {
+		out = append(out, NutritionWarning{
+			Code:    WarningMacrosExceed100g,
+			Message: fmt.Sprintf("macros add up to %.1f g per 100 g", mass),
+		})
+	}
+	return out
+}
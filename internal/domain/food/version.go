@@ -0,0 +1,48 @@
+package food
+
+import "time"
+
+// Version is an immutable snapshot of a food, written on creation and on
+// every later change. Food.CurrentVersion points at the latest one.
+type Version struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	FoodID         uint       `json:"food_id" gorm:"column:food_id"`
+	Version        int        `json:"version" gorm:"column:version"`
+	EditedBy       *uint      `json:"edited_by,omitempty" gorm:"column:edited_by"`
+	RestoredFrom   *int       `json:"restored_from,omitempty" gorm:"column:restored_from"`
+	Name           string     `json:"name"`
+	BrandName      *string    `json:"brand_name,omitempty" gorm:"column:brand_name"`
+	Barcode        *string    `json:"barcode,omitempty" gorm:"column:barcode"`
+	KcalPer100g    float64    `json:"kcal_per_100g" gorm:"column:kcal_per_100g"`
+	ProteinPer100g float64    `json:"protein_per_100g" gorm:"column:protein_per_100g"`
+	CarbsPer100g   float64    `json:"carbs_per_100g" gorm:"column:carbs_per_100g"`
+	FatPer100g     float64    `json:"fat_per_100g" gorm:"column:fat_per_100g"`
+	AlcoholPer100g float64    `json:"alcohol_per_100g" gorm:"column:alcohol_per_100g"`
+	Visibility     Visibility `json:"visibility" gorm:"column:visibility"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (Version) TableName() string {
+	return "food_versions"
+}
+
+// Snapshot captures the current state of f as version number n.
+func (f Food) Snapshot(n int, editedBy uint) Version {
+	v := Version{
+		FoodID:         f.ID,
+		Version:        n,
+		Name:           f.Name,
+		BrandName:      f.BrandName,
+		Barcode:        f.Barcode,
+		KcalPer100g:    f.KcalPer100g,
+		ProteinPer100g: f.ProteinPer100g,
+		CarbsPer100g:   f.CarbsPer100g,
+		FatPer100g:     f.FatPer100g,
+		AlcoholPer100g: f.AlcoholPer100g,
+		Visibility:     f.Visibility,
+	}
+	if editedBy != 0 {
+		v.EditedBy = &editedBy
+	}
+	return v
+}
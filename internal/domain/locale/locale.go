@@ -0,0 +1,187 @@
+// Package locale holds the language rules for translated food and recipe
+// names: language codes, Accept-Language parsing and picking the name to
+// show.
+package locale
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxLanguages caps the languages a user can save.
+	MaxLanguages = 5
+	// MaxTranslations caps the languages one food or recipe is named in.
+	MaxTranslations = 20
+	// MaxSynonyms caps the extra search names per language.
+	MaxSynonyms = 10
+	// MaxNameLength caps translated names and synonyms, in characters.
+	MaxNameLength = 200
+)
+
+// Translation is the name of a food or recipe in one language. Synonyms are
+// other names search matches, such as regional variants.
+type Translation struct {
+	Locale   string   `json:"locale"`
+	Name     string   `json:"name"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// Normalize reduces a language tag such as "de-AT" or "PT_br" to its
+// lowercase primary language subtag. Only the language is kept: regional
+// spellings are rare enough in food names to be synonyms instead. It reports
+// false for anything that is not a two- or three-letter language code.
+func Normalize(tag string) (string, bool) {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if len(tag) < 2 || len(tag) > 3 {
+		return "", false
+	}
+	tag = strings.ToLower(tag)
+	for i := 0; i < len(tag); i++ {
+		if tag[i] < 'a' || tag[i] > 'z' {
+			return "", false
+		}
+	}
+	return tag, true
+}
+
+// NormalizeLanguages normalizes saved language preferences, keeping their
+// order and dropping repeats. It reports false for invalid codes and for more
+// than MaxLanguages distinct languages.
+func NormalizeLanguages(raw []string) ([]string, bool) {
+	out := make([]string, 0, len(raw))
+	for _, tag := range raw {
+		language, ok := Normalize(tag)
+		if !ok {
+			return nil, false
+		}
+		if !slices.Contains(out, language) {
+			out = append(out, language)
+		}
+	}
+	if len(out) > MaxLanguages {
+		return nil, false
+	}
+	return out, true
+}
+
+// ParseAcceptLanguage returns the languages of an Accept-Language header,
+// most preferred first. Entries with equal weight keep their order. The
+// wildcard, malformed entries and entries with q=0 are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type entry struct {
+		language string
+		weight   float64
+	}
+	var entries []entry
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		language, ok := Normalize(tag)
+		if !ok {
+			continue
+		}
+		weight := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if weight <= 0 {
+			continue
+		}
+		entries = append(entries, entry{language: language, weight: weight})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].weight > entries[j].weight })
+
+	var out []string
+	for _, e := range entries {
+		if !slices.Contains(out, e.language) {
+			out = append(out, e.language)
+		}
+	}
+	return out
+}
+
+// Preferred orders the languages names are shown and searched in: a user's
+// saved languages first, then the ones their client accepts.
+func Preferred(saved, accepted []string) []string {
+	out := slices.Clone(saved)
+	for _, language := range accepted {
+		if !slices.Contains(out, language) {
+			out = append(out, language)
+		}
+	}
+	return out
+}
+
+// NormalizeTranslations trims names and synonyms, normalizes locales and
+// sorts the result by locale. Synonyms repeating the name or each other are
+// dropped. It reports false for empty or overlong names, invalid or repeated
+// locales, and more than MaxTranslations languages or MaxSynonyms synonyms.
+func NormalizeTranslations(raw []Translation) ([]Translation, bool) {
+	if len(raw) > MaxTranslations {
+		return nil, false
+	}
+	out := make([]Translation, 0, len(raw))
+	for _, t := range raw {
+		language, ok := Normalize(t.Locale)
+		if !ok {
+			return nil, false
+		}
+		for _, existing := range out {
+			if existing.Locale == language {
+				return nil, false
+			}
+		}
+		name, ok := normalizeName(t.Name)
+		if !ok {
+			return nil, false
+		}
+		synonyms := make([]string, 0, len(t.Synonyms))
+		for _, raw := range t.Synonyms {
+			synonym, ok := normalizeName(raw)
+			if !ok {
+				return nil, false
+			}
+			if strings.EqualFold(synonym, name) || slices.ContainsFunc(synonyms, func(s string) bool { return strings.EqualFold(s, synonym) }) {
+				continue
+			}
+			synonyms = append(synonyms, synonym)
+		}
+		if len(synonyms) > MaxSynonyms {
+			return nil, false
+		}
+		out = append(out, Translation{Locale: language, Name: name, Synonyms: synonyms})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Locale < out[j].Locale })
+	return out, true
+}
+
+// Pick returns the translated name for the first of languages that has one,
+// or fallback when none does.
+func Pick(fallback string, translations []Translation, languages []string) string {
+	for _, language := range languages {
+		for _, t := range translations {
+			if t.Locale == language {
+				return t.Name
+			}
+		}
+	}
+	return fallback
+}
+
+func normalizeName(raw string) (string, bool) {
+	name := strings.Join(strings.Fields(raw), " ")
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return "", false
+	}
+	return name, true
+}
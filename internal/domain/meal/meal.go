@@ -16,11 +16,14 @@ const (
 )
 
 type Meal struct {
-	ID        uint                `json:"id" gorm:"primaryKey"`
-	UserID    uint                `json:"user_id" gorm:"column:user_id"`
-	MealType  MealType            `json:"meal_type" gorm:"column:meal_type"`
-	EatenAt   time.Time           `json:"eaten_at" gorm:"column:eaten_at"`
-	CreatedAt time.Time           `json:"created_at"`
-	UpdatedAt time.Time           `json:"updated_at"`
-	Items     []mealitem.MealItem `json:"items,omitempty" gorm:"-"`
+	ID           uint                `json:"id" gorm:"primaryKey"`
+	UserID       uint                `json:"user_id" gorm:"column:user_id"`
+	MealType     MealType            `json:"meal_type" gorm:"column:meal_type"`
+	EatenAt      time.Time           `json:"eaten_at" gorm:"column:eaten_at"`
+	ImageKey     *string             `json:"-" gorm:"column:image_key"`
+	ImageURL     *string             `json:"image_url,omitempty" gorm:"column:image_url"`
+	ThumbnailURL *string             `json:"thumbnail_url,omitempty" gorm:"column:thumbnail_url"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	Items        []mealitem.MealItem `json:"items,omitempty" gorm:"-"`
 }
@@ -1,17 +1,35 @@
 package mealitem
 
-import "time"
+import (
+	"time"
+
+	"goal-bite-api/internal/domain/dietary"
+)
 
 type MealItem struct {
-	ID             uint      `json:"id" gorm:"primaryKey"`
-	MealID         uint      `json:"meal_id" gorm:"column:meal_id"`
-	FoodID         *uint     `json:"food_id,omitempty" gorm:"column:food_id"`
-	RecipeID       *uint     `json:"recipe_id,omitempty" gorm:"column:recipe_id"`
-	WeightG        float64   `json:"weight_g" gorm:"column:weight_g"`
+	ID          uint    `json:"id" gorm:"primaryKey"`
+	MealID      uint    `json:"meal_id" gorm:"column:meal_id"`
+	FoodID      *uint   `json:"food_id,omitempty" gorm:"column:food_id"`
+	FoodVersion *int    `json:"food_version,omitempty" gorm:"column:food_version"`
+	RecipeID    *uint   `json:"recipe_id,omitempty" gorm:"column:recipe_id"`
+	WeightG     float64 `json:"weight_g" gorm:"column:weight_g"`
+	// Servings is set when a recipe item was logged in servings rather
+	// than grams; WeightG is what it converted to.
+	Servings *float64 `json:"servings,omitempty" gorm:"column:servings"`
+	// CookedWeightG is set when a food was logged by its cooked weight,
+	// cooked by CookingMethodID; WeightG is the raw weight it converted to.
+	CookedWeightG   *float64 `json:"cooked_weight_g,omitempty" gorm:"column:cooked_weight_g"`
+	CookingMethodID *uint    `json:"cooking_method_id,omitempty" gorm:"column:cooking_method_id"`
+	// WaterChangeG estimates the water a cooked item took up while
+	// cooking, negative when it lost water.
+	WaterChangeG   *float64  `json:"water_change_g,omitempty" gorm:"column:water_change_g"`
 	KcalPer100g    float64   `json:"kcal_per_100g" gorm:"column:kcal_per_100g"`
 	ProteinPer100g float64   `json:"protein_per_100g" gorm:"column:protein_per_100g"`
 	CarbsPer100g   float64   `json:"carbs_per_100g" gorm:"column:carbs_per_100g"`
 	FatPer100g     float64   `json:"fat_per_100g" gorm:"column:fat_per_100g"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	// Warnings lists the user's dietary restrictions the item breaks when it
+	// is logged. It is not stored.
+	Warnings []dietary.Conflict `json:"warnings,omitempty" gorm:"-"`
 }
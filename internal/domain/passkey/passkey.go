@@ -0,0 +1,16 @@
+package passkey
+
+import "time"
+
+type Passkey struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"column:user_id;not null"`
+	Name         string     `json:"name"`
+	CredentialID string     `json:"credential_id" gorm:"column:credential_id"`
+	PublicKey    []byte     `json:"-" gorm:"column:public_key"`
+	SignCount    uint32     `json:"sign_count" gorm:"column:sign_count"`
+	Transports   []string   `json:"transports" gorm:"column:transports;serializer:json"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty" gorm:"column:last_used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -3,19 +3,135 @@ package recipe
 import (
 	"time"
 
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/domain/recipeingredient"
 )
 
+// Recipe is a dish built from ingredient foods. Allergens and DietFlags are
+// not stored: they are derived from the current ingredient foods whenever the
+// recipe is read, as the union of their allergens and the diet flags all of
+// them share. Stale is set when an ingredient food's nutrition changed after
+// the per-100g values were last calculated. The yield is split into Servings
+// portions of equal weight, called ServingName. Times are in minutes, with 0
+// meaning not given, and Instructions are the steps in cooking order.
+// ForkedFromID is the recipe this one was copied from, if any.
+// YieldEstimated is set when YieldWeightG was not stated but estimated from
+// the raw ingredient weights and how they are cooked.
 type Recipe struct {
 	ID             uint                                `json:"id" gorm:"primaryKey"`
 	UserID         uint                                `json:"user_id" gorm:"column:user_id;not null"`
+	ForkedFromID   *uint                               `json:"forked_from_id,omitempty" gorm:"column:forked_from_id"`
 	Name           string                              `json:"name"`
 	YieldWeightG   float64                             `json:"yield_weight_g" gorm:"column:yield_weight_g"`
+	YieldEstimated bool                                `json:"yield_estimated" gorm:"column:yield_estimated"`
+	Servings       int                                 `json:"servings" gorm:"column:servings"`
+	ServingName    string                              `json:"serving_name" gorm:"column:serving_name"`
+	Description    string                              `json:"description" gorm:"column:description"`
+	PrepTimeMin    int                                 `json:"prep_time_min" gorm:"column:prep_time_min"`
+	CookTimeMin    int                                 `json:"cook_time_min" gorm:"column:cook_time_min"`
+	Tags           []string                            `json:"tags" gorm:"column:tags;serializer:json"`
+	Instructions   []string                            `json:"instructions" gorm:"column:instructions;serializer:json"`
 	KcalPer100g    float64                             `json:"kcal_per_100g" gorm:"column:kcal_per_100g"`
 	ProteinPer100g float64                             `json:"protein_per_100g" gorm:"column:protein_per_100g"`
 	CarbsPer100g   float64                             `json:"carbs_per_100g" gorm:"column:carbs_per_100g"`
 	FatPer100g     float64                             `json:"fat_per_100g" gorm:"column:fat_per_100g"`
+	Allergens      []string                            `json:"allergens" gorm:"column:allergens;->;serializer:json"`
+	DietFlags      []string                            `json:"diet_flags" gorm:"column:diet_flags;->;serializer:json"`
+	Stale          bool                                `json:"stale" gorm:"column:stale"`
+	ArchivedAt     *time.Time                          `json:"archived_at,omitempty" gorm:"column:archived_at"`
+	ImageKey       *string                             `json:"-" gorm:"column:image_key"`
+	ImageURL       *string                             `json:"image_url,omitempty" gorm:"column:image_url"`
+	ThumbnailURL   *string                             `json:"thumbnail_url,omitempty" gorm:"column:thumbnail_url"`
 	CreatedAt      time.Time                           `json:"created_at"`
 	UpdatedAt      time.Time                           `json:"updated_at"`
 	Ingredients    []recipeingredient.RecipeIngredient `json:"ingredients,omitempty" gorm:"-"`
+	// Translations are stored in recipe_names. DisplayName is the name to
+	// show the caller, set by Localize.
+	Translations []locale.Translation `json:"translations" gorm:"-"`
+	DisplayName  string               `json:"display_name" gorm:"-"`
+	// PerServing and PerBatch are the nutrition of one serving and of the
+	// whole yield, set by SetPortions.
+	PerServing Portion `json:"per_serving" gorm:"-"`
+	PerBatch   Portion `json:"per_batch" gorm:"-"`
+}
+
+// Portion is the nutrition of an amount of a recipe.
+type Portion struct {
+	WeightG  float64 `json:"weight_g"`
+	Kcal     float64 `json:"kcal"`
+	ProteinG float64 `json:"protein_g"`
+	CarbsG   float64 `json:"carbs_g"`
+	FatG     float64 `json:"fat_g"`
+}
+
+const (
+	// MaxServings caps how many servings a recipe is split into.
+	MaxServings = 1000
+	// MaxServingNameLength caps serving names, in characters.
+	MaxServingNameLength = 50
+	// DefaultServingName names servings when the recipe does not.
+	DefaultServingName = "serving"
+	// MaxDescriptionLength caps descriptions, in characters.
+	MaxDescriptionLength = 2000
+	// MaxInstructions caps the steps of a recipe, and MaxInstructionLength
+	// each step, in characters.
+	MaxInstructions      = 50
+	MaxInstructionLength = 2000
+	// MaxTimeMin caps prep and cook times at a week, enough for ferments.
+	MaxTimeMin = 7 * 24 * 60
+)
+
+// ServingWeightG is the weight of one serving.
+func (r Recipe) ServingWeightG() float64 {
+	if r.Servings <= 0 {
+		return r.YieldWeightG
+	}
+	return r.YieldWeightG / float64(r.Servings)
+}
+
+// PortionOf returns the nutrition of weightG grams of the recipe.
+func (r Recipe) PortionOf(weightG float64) Portion {
+	return Portion{
+		WeightG:  weightG,
+		Kcal:     r.KcalPer100g * weightG / 100,
+		ProteinG: r.ProteinPer100g * weightG / 100,
+		CarbsG:   r.CarbsPer100g * weightG / 100,
+		FatG:     r.FatPer100g * weightG / 100,
+	}
+}
+
+// SetPortions sets PerServing and PerBatch from the yield and the per-100g
+// values.
+func (r *Recipe) SetPortions() {
+	r.PerServing = r.PortionOf(r.ServingWeightG())
+	r.PerBatch = r.PortionOf(r.YieldWeightG)
+}
+
+// Scaled returns the recipe resized to servings portions: the yield and
+// every ingredient weight change proportionally, while the per-100g values
+// stay the same.
+func (r Recipe) Scaled(servings int) Recipe {
+	factor := float64(servings) / float64(max(r.Servings, 1))
+	out := r
+	out.Servings = servings
+	out.YieldWeightG = r.YieldWeightG * factor
+	out.Ingredients = make([]recipeingredient.RecipeIngredient, len(r.Ingredients))
+	for i, ingredient := range r.Ingredients {
+		ingredient.RawWeightG *= factor
+		out.Ingredients[i] = ingredient
+	}
+	out.SetPortions()
+	return out
+}
+
+// Localize sets DisplayName to the translation in the first of languages
+// the recipe has one in, falling back to Name.
+func (r *Recipe) Localize(languages []string) {
+	r.DisplayName = locale.Pick(r.Name, r.Translations, languages)
+}
+
+// Archived reports whether the recipe was removed from lists and search. It
+// still resolves by ID for meal items that already use it.
+func (r Recipe) Archived() bool {
+	return r.ArchivedAt != nil
 }
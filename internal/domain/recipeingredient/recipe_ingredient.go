@@ -2,12 +2,27 @@ package recipeingredient
 
 import "time"
 
+// MaxDepth caps how many levels of sub-recipes a recipe may nest below
+// itself, so nutrition lookups stay bounded.
+const MaxDepth = 3
+
+// MaxNoteLength caps ingredient notes, in characters.
+const MaxNoteLength = 200
+
+// RecipeIngredient is an amount of either a food or a sub-recipe; exactly
+// one of FoodID and SubRecipeID is set. FoodVersion is only recorded for
+// foods. Note says how the ingredient is prepared, such as "diced", and
+// CookingMethodID how a food is cooked, which estimated yields follow.
 type RecipeIngredient struct {
-	ID         uint      `json:"id" gorm:"primaryKey"`
-	RecipeID   uint      `json:"recipe_id" gorm:"column:recipe_id"`
-	FoodID     uint      `json:"food_id" gorm:"column:food_id"`
-	RawWeightG float64   `json:"raw_weight_g" gorm:"column:raw_weight_g"`
-	Position   *int      `json:"position,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	RecipeID        uint      `json:"recipe_id" gorm:"column:recipe_id"`
+	FoodID          *uint     `json:"food_id,omitempty" gorm:"column:food_id"`
+	SubRecipeID     *uint     `json:"sub_recipe_id,omitempty" gorm:"column:sub_recipe_id"`
+	FoodVersion     *int      `json:"food_version,omitempty" gorm:"column:food_version"`
+	RawWeightG      float64   `json:"raw_weight_g" gorm:"column:raw_weight_g"`
+	Position        *int      `json:"position,omitempty"`
+	Note            string    `json:"note,omitempty" gorm:"column:note"`
+	CookingMethodID *uint     `json:"cooking_method_id,omitempty" gorm:"column:cooking_method_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -0,0 +1,42 @@
+package sharelink
+
+import "time"
+
+// Kind is what a link shares.
+type Kind string
+
+const (
+	KindRecipe Kind = "recipe"
+	KindMeal   Kind = "meal"
+)
+
+// ShareLink lets anyone holding its token view one recipe or meal, read
+// only, without signing in. Exactly one of RecipeID and MealID is set. Only
+// a hash of the token is stored; Token is set just once, when the link is
+// created. AccessCount counts the times the link was opened.
+type ShareLink struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"column:user_id"`
+	TokenHash      string     `json:"-" gorm:"column:token_hash"`
+	Token          string     `json:"token,omitempty" gorm:"-"`
+	RecipeID       *uint      `json:"recipe_id,omitempty" gorm:"column:recipe_id"`
+	MealID         *uint      `json:"meal_id,omitempty" gorm:"column:meal_id"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" gorm:"column:expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" gorm:"column:revoked_at"`
+	AccessCount    int64      `json:"access_count" gorm:"column:access_count"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty" gorm:"column:last_accessed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (ShareLink) TableName() string {
+	return "share_links"
+}
+
+// Kind reports whether the link shares a recipe or a meal.
+func (l ShareLink) Kind() Kind {
+	if l.MealID != nil {
+		return KindMeal
+	}
+	return KindRecipe
+}
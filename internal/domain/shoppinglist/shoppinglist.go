@@ -0,0 +1,85 @@
+package shoppinglist
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"goal-bite-api/internal/domain/locale"
+)
+
+const (
+	// MaxNameLength caps list names, in characters.
+	MaxNameLength = 100
+	// DefaultName names lists the user did not name.
+	DefaultName = "Shopping list"
+)
+
+// ShoppingList is what to buy for a set of recipes or for the meals planned
+// over some days: the raw weight of each ingredient food they need, merged
+// across them. It is a snapshot and does not follow later recipe changes.
+type ShoppingList struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"column:user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Items     []Item    `json:"items,omitempty" gorm:"-"`
+}
+
+func (ShoppingList) TableName() string {
+	return "shopping_lists"
+}
+
+// Item is the raw weight of one food to buy. Name is left empty for foods the
+// owner may no longer see.
+type Item struct {
+	ID             uint                 `json:"id" gorm:"primaryKey"`
+	ShoppingListID uint                 `json:"-" gorm:"column:shopping_list_id"`
+	FoodID         uint                 `json:"food_id" gorm:"column:food_id"`
+	Name           string               `json:"name" gorm:"-"`
+	DisplayName    string               `json:"display_name" gorm:"-"`
+	Translations   []locale.Translation `json:"-" gorm:"-"`
+	WeightG        float64              `json:"weight_g" gorm:"column:weight_g"`
+	Checked        bool                 `json:"checked" gorm:"column:checked"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+func (Item) TableName() string {
+	return "shopping_list_items"
+}
+
+// Localize sets each item's DisplayName to its translation in the first of
+// languages it has one in, falling back to Name.
+func (l *ShoppingList) Localize(languages []string) {
+	for i := range l.Items {
+		l.Items[i].DisplayName = locale.Pick(l.Items[i].Name, l.Items[i].Translations, languages)
+	}
+}
+
+// Text renders the list as plain text: its name, then one line per item
+// with a checkbox and the weight rounded to a tenth of a gram.
+func (l ShoppingList) Text() string {
+	var b strings.Builder
+	b.WriteString(l.Name)
+	b.WriteString("\n\n")
+	for _, item := range l.Items {
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+		name := item.DisplayName
+		if name == "" {
+			name = item.Name
+		}
+		if name == "" {
+			name = fmt.Sprintf("Food #%d", item.FoodID)
+		}
+		weight := strconv.FormatFloat(math.Round(item.WeightG*10)/10, 'f', -1, 64)
+		fmt.Fprintf(&b, "%s %s — %s g\n", box, name, weight)
+	}
+	return b.String()
+}
@@ -10,7 +10,15 @@ type User struct {
 	BirthDate     *time.Time `json:"birth_date,omitempty" gorm:"column:birth_date"`
 	HeightCM      *float64   `json:"height_cm,omitempty" gorm:"column:height_cm"`
 	ActivityLevel *string    `json:"activity_level,omitempty" gorm:"column:activity_level"`
-	PasswordHash  string     `json:"-" gorm:"column:password_hash"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	// AvoidAllergens and RequiredDietFlags are dietary restrictions.
+	// Languages lists the languages food and recipe names are shown and
+	// searched in, most preferred first. All three start out empty through
+	// the column defaults and change only through profile updates.
+	AvoidAllergens    []string  `json:"avoid_allergens" gorm:"column:avoid_allergens;serializer:json;<-:update"`
+	RequiredDietFlags []string  `json:"required_diet_flags" gorm:"column:required_diet_flags;serializer:json;<-:update"`
+	Languages         []string  `json:"languages" gorm:"column:languages;serializer:json;<-:update"`
+	PasswordHash      string    `json:"-" gorm:"column:password_hash"`
+	IsAdmin           bool      `json:"-" gorm:"column:is_admin"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
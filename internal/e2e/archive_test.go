@@ -0,0 +1,74 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestArchiveFoodsAndRecipesE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	foodID := createFood(t, env.BaseURL, env.Token, "Rice", 130, 2.7, 28, 0.3)
+	recipeID := createRecipe(t, env.BaseURL, env.Token, foodID)
+	mealID := createMealWithFoodItem(t, env.BaseURL, foodID, env.Token)
+
+	type entry struct {
+		ID         uint    `json:"id"`
+		ArchivedAt *string `json:"archived_at"`
+	}
+
+	// Deleting a food that meals and recipes reference archives it.
+	doJSONWithToken(t, http.MethodDelete, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, foodID), nil, env.Token, http.StatusNoContent, nil)
+	var foods []entry
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods", nil, env.Token, http.StatusOK, &foods)
+	if len(foods) != 0 {
+		t.Fatalf("expected archived food hidden from list, got %+v", foods)
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods?include_archived=true", nil, env.Token, http.StatusOK, &foods)
+	if len(foods) != 1 || foods[0].ArchivedAt == nil {
+		t.Fatalf("expected archived food with include_archived, got %+v", foods)
+	}
+
+	// History keeps resolving.
+	var archivedFood entry
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, foodID), nil, env.Token, http.StatusOK, &archivedFood)
+	if archivedFood.ArchivedAt == nil {
+		t.Fatal("expected archived_at on archived food")
+	}
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/meals/%d", env.BaseURL, mealID), nil, env.Token, http.StatusOK, nil)
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, recipeID), map[string]any{"yield_weight_g": 250.0}, env.Token, http.StatusOK, nil)
+
+	// New uses are rejected with a clear error.
+	mealPayload := map[string]any{
+		"meal_type": "dinner",
+		"eaten_at":  "2026-02-17T19:00:00Z",
+		"items":     []map[string]any{{"food_id": foodID, "weight_g": 100.0}},
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/meals", mealPayload, env.Token, http.StatusConflict, nil)
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, foodID), map[string]any{"name": "Brown Rice"}, env.Token, http.StatusConflict, nil)
+
+	var restored entry
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/foods/%d/restore", env.BaseURL, foodID), nil, env.Token, http.StatusOK, &restored)
+	if restored.ArchivedAt != nil {
+		t.Fatalf("expected restored food without archived_at, got %+v", restored)
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/meals", mealPayload, env.Token, http.StatusCreated, nil)
+
+	// Recipes archive the same way.
+	doJSONWithToken(t, http.MethodDelete, fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, recipeID), nil, env.Token, http.StatusNoContent, nil)
+	var recipes []entry
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/recipes", nil, env.Token, http.StatusOK, &recipes)
+	if len(recipes) != 0 {
+		t.Fatalf("expected archived recipe hidden from list, got %+v", recipes)
+	}
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/meals/%d/items", env.BaseURL, mealID), map[string]any{"recipe_id": recipeID, "weight_g": 120.0}, env.Token, http.StatusConflict, nil)
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/recipes/%d/restore", env.BaseURL, recipeID), nil, env.Token, http.StatusOK, nil)
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/recipes", nil, env.Token, http.StatusOK, &recipes)
+	if len(recipes) != 1 {
+		t.Fatalf("expected restored recipe listed, got %+v", recipes)
+	}
+}
@@ -5,6 +5,9 @@ package e2e_test
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 	"time"
 )
@@ -65,10 +68,15 @@ func TestAuthRegisterLoginE2E(t *testing.T) {
 
 	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/refresh", map[string]any{"refresh_token": refreshOut.RefreshToken}, http.StatusUnauthorized, nil)
 
+	// Access tokens die with their session: logout and refresh rotation both
+	// revoke the session the token was issued for.
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/auth/me", nil, refreshOut.Token, http.StatusUnauthorized, nil)
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/auth/me", nil, loginOut.Token, http.StatusUnauthorized, nil)
+
 	var healthOut struct {
 		Status string `json:"status"`
 	}
-	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/health", nil, refreshOut.Token, http.StatusOK, &healthOut)
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/health", nil, registerOut.Token, http.StatusOK, &healthOut)
 	if healthOut.Status != "ok" {
 		t.Fatalf("expected status ok, got %q", healthOut.Status)
 	}
@@ -77,8 +85,63 @@ func TestAuthRegisterLoginE2E(t *testing.T) {
 		ID    uint   `json:"id"`
 		Email string `json:"email"`
 	}
-	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/auth/me", nil, refreshOut.Token, http.StatusOK, &meOut)
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/auth/me", nil, registerOut.Token, http.StatusOK, &meOut)
 	if meOut.ID == 0 || meOut.Email == "" {
 		t.Fatalf("expected current user payload, got %+v", meOut)
 	}
 }
+
+func TestAuthMagicLinkE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	// Both requests answer alike; mail goes out in the background, and only
+	// for the known email.
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/magic-link", map[string]any{"email": "nobody@example.com"}, http.StatusAccepted, nil)
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/magic-link", map[string]any{"email": "e2e@example.com"}, http.StatusAccepted, nil)
+	entries := waitForMail(t, env.MailDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected one mail, got %d", len(entries))
+	}
+	raw, err := os.ReadFile(filepath.Join(env.MailDir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("read mail: %v", err)
+	}
+	match := regexp.MustCompile(`token=([A-Za-z0-9_-]+)`).FindStringSubmatch(string(raw))
+	if len(match) != 2 {
+		t.Fatalf("expected magic link token in mail: %q", raw)
+	}
+
+	var consumeOut struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+		User         struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/magic-link/consume", map[string]any{"token": match[1]}, http.StatusOK, &consumeOut)
+	if consumeOut.Token == "" || consumeOut.RefreshToken == "" {
+		t.Fatalf("expected session tokens")
+	}
+	if consumeOut.User.ID != env.UserID {
+		t.Fatalf("expected user %d, got %d", env.UserID, consumeOut.User.ID)
+	}
+
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/magic-link/consume", map[string]any{"token": match[1]}, http.StatusUnauthorized, nil)
+}
+
+// waitForMail polls dir until the background mailer has written something.
+func waitForMail(t *testing.T, dir string) []os.DirEntry {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("read mail dir: %v", err)
+		}
+		if len(entries) > 0 || time.Now().After(deadline) {
+			return entries
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,46 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestBarcodeLookupImportsCatalogProductsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+	_, otherToken := env.newUser(t, "Other User", "other@example.com", false)
+
+	type foodOut struct {
+		ID         uint    `json:"id"`
+		Name       string  `json:"name"`
+		Visibility string  `json:"visibility"`
+		Source     string  `json:"source"`
+		SourceRef  *string `json:"source_ref"`
+		ImportedAt *string `json:"imported_at"`
+	}
+	var imported foodOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/by-barcode/5200435000027", nil, env.Token, http.StatusOK, &imported)
+	if imported.Name != "Total Greek Yoghurt" || imported.Visibility != "public" || imported.Source != "file" || imported.SourceRef == nil || imported.ImportedAt == nil {
+		t.Fatalf("unexpected imported food: %+v", imported)
+	}
+
+	// The import is a shared catalog food: later scans resolve locally.
+	var again foodOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/by-barcode/5200435000027", nil, otherToken, http.StatusOK, &again)
+	if again.ID != imported.ID {
+		t.Fatalf("expected the imported food %d, got %+v", imported.ID, again)
+	}
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, imported.ID), map[string]any{"name": "Yoghurt"}, env.Token, http.StatusForbidden, nil)
+
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/by-barcode/4000000000000", nil, env.Token, http.StatusNotFound, nil)
+	var misses int64
+	if err := env.db.Table("barcode_lookup_misses").Where("source = ? AND barcode = ?", "file", "4000000000000").Count(&misses).Error; err != nil {
+		t.Fatalf("count misses: %v", err)
+	}
+	if misses != 1 {
+		t.Fatalf("expected cached miss, got %d", misses)
+	}
+}
@@ -0,0 +1,135 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestCookingMethodsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	var methods []struct {
+		ID      uint   `json:"id"`
+		Slug    string `json:"slug"`
+		Factors []struct {
+			CategoryID  *uint   `json:"category_id"`
+			YieldFactor float64 `json:"yield_factor"`
+		} `json:"factors"`
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/cooking-methods", nil, env.Token, http.StatusOK, &methods)
+	methodIDs := map[string]uint{}
+	for _, m := range methods {
+		methodIDs[m.Slug] = m.ID
+		if len(m.Factors) == 0 || m.Factors[0].CategoryID != nil {
+			t.Fatalf("expected %s to list its default factor first, got %+v", m.Slug, m.Factors)
+		}
+	}
+	if methodIDs["boiled"] == 0 || methodIDs["grilled"] == 0 {
+		t.Fatalf("expected seeded cooking methods, got %+v", methods)
+	}
+
+	type categoryNode struct {
+		ID       uint           `json:"id"`
+		Slug     string         `json:"slug"`
+		Children []categoryNode `json:"children"`
+	}
+	var tree []categoryNode
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/food-categories", nil, env.Token, http.StatusOK, &tree)
+	categoryIDs := map[string]uint{}
+	var walk func([]categoryNode)
+	walk = func(nodes []categoryNode) {
+		for _, n := range nodes {
+			categoryIDs[n.Slug] = n.ID
+			walk(n.Children)
+		}
+	}
+	walk(tree)
+
+	createFoodIn := func(name, category string, kcal float64) uint {
+		var out struct {
+			ID uint `json:"id"`
+		}
+		payload := map[string]any{"name": name, "kcal_per_100g": kcal, "protein_per_100g": 10.0, "carbs_per_100g": 10.0, "fat_per_100g": 5.0, "category_id": categoryIDs[category]}
+		doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", payload, env.Token, http.StatusCreated, &out)
+		return out.ID
+	}
+	spaghetti := createFoodIn("Spaghetti", "pasta", 350)
+	steak := createFoodIn("Sirloin steak", "red-meat", 200)
+	sauce := createFood(t, env.BaseURL, env.Token, "Tomato sauce", 50, 1, 8, 1)
+
+	type recipeOut struct {
+		ID             uint    `json:"id"`
+		YieldWeightG   float64 `json:"yield_weight_g"`
+		YieldEstimated bool    `json:"yield_estimated"`
+		Ingredients    []struct {
+			CookingMethodID *uint `json:"cooking_method_id"`
+		} `json:"ingredients"`
+	}
+	var created recipeOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes", map[string]any{
+		"name": "Spaghetti al pomodoro",
+		"ingredients": []map[string]any{
+			{"food_id": spaghetti, "raw_weight_g": 100.0, "cooking_method_id": methodIDs["boiled"]},
+			{"food_id": sauce, "raw_weight_g": 150.0},
+		},
+	}, env.Token, http.StatusCreated, &created)
+	if !created.YieldEstimated || math.Abs(created.YieldWeightG-370) > 0.01 {
+		t.Fatalf("expected 220g cooked pasta and 150g sauce, got %+v", created)
+	}
+	if created.Ingredients[0].CookingMethodID == nil || *created.Ingredients[0].CookingMethodID != methodIDs["boiled"] {
+		t.Fatalf("expected the pasta to keep its cooking method, got %+v", created.Ingredients)
+	}
+
+	var updated recipeOut
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, created.ID), map[string]any{"yield_weight_g": 400.0}, env.Token, http.StatusOK, &updated)
+	if updated.YieldEstimated || updated.YieldWeightG != 400 {
+		t.Fatalf("expected the stated yield, got %+v", updated)
+	}
+
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes", map[string]any{
+		"name":        "Mystery",
+		"ingredients": []map[string]any{{"food_id": sauce, "raw_weight_g": 100.0, "cooking_method_id": 999999}},
+	}, env.Token, http.StatusBadRequest, nil)
+
+	mealID := createMealWithFoodItem(t, env.BaseURL, sauce, env.Token)
+	var item struct {
+		ID              uint     `json:"id"`
+		WeightG         float64  `json:"weight_g"`
+		CookedWeightG   *float64 `json:"cooked_weight_g"`
+		CookingMethodID *uint    `json:"cooking_method_id"`
+	}
+	// Red meat has no factor of its own and falls back to meat's.
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/meals/%d/items", env.BaseURL, mealID), map[string]any{
+		"food_id":           steak,
+		"cooked_weight_g":   140.0,
+		"cooking_method_id": methodIDs["grilled"],
+	}, env.Token, http.StatusCreated, &item)
+	if math.Abs(item.WeightG-200) > 0.01 || item.CookedWeightG == nil || *item.CookedWeightG != 140 {
+		t.Fatalf("expected 140g grilled steak to log as 200g raw, got %+v", item)
+	}
+
+	var totals struct {
+		TotalKcal float64 `json:"total_kcal"`
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/daily-totals?date=2026-02-17", nil, env.Token, http.StatusOK, &totals)
+	if math.Abs(totals.TotalKcal-(75+400)) > 0.01 {
+		t.Fatalf("expected the raw-equivalent steak in the totals, got %v", totals.TotalKcal)
+	}
+
+	var patched map[string]any
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/meals/%d/items/%d", env.BaseURL, mealID, item.ID), map[string]any{"weight_g": 180.0}, env.Token, http.StatusOK, &patched)
+	_, hasCooked := patched["cooked_weight_g"]
+	if patched["weight_g"] != 180.0 || hasCooked {
+		t.Fatalf("expected a weight to replace the cooked weight, got %+v", patched)
+	}
+
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/meals/%d/items", env.BaseURL, mealID), map[string]any{
+		"food_id":         steak,
+		"cooked_weight_g": 140.0,
+	}, env.Token, http.StatusBadRequest, nil)
+}
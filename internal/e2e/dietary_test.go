@@ -0,0 +1,99 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDietaryRestrictionsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	type idOut struct {
+		ID uint `json:"id"`
+	}
+	newFood := func(name string, allergens, flags []string) uint {
+		t.Helper()
+		payload := map[string]any{
+			"name": name, "kcal_per_100g": 100.0, "protein_per_100g": 5.0, "carbs_per_100g": 15.0, "fat_per_100g": 2.0,
+			"allergens": allergens, "diet_flags": flags,
+		}
+		var out idOut
+		doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", payload, env.Token, http.StatusCreated, &out)
+		return out.ID
+	}
+
+	contradiction := map[string]any{
+		"name": "Milk chocolate", "kcal_per_100g": 535.0, "protein_per_100g": 7.7, "carbs_per_100g": 59.4, "fat_per_100g": 29.7,
+		"allergens": []string{"milk"}, "diet_flags": []string{"vegan"},
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", contradiction, env.Token, http.StatusBadRequest, nil)
+
+	oats := newFood("Oats", []string{"gluten"}, []string{"vegan"})
+	satay := newFood("Satay sauce", []string{"peanuts", "soybeans"}, []string{"vegan", "gluten_free"})
+	_ = newFood("Rice", nil, []string{"vegan", "gluten_free", "halal"})
+
+	recipePayload := map[string]any{
+		"name":           "Oats with satay",
+		"yield_weight_g": 200.0,
+		"ingredients": []map[string]any{
+			{"food_id": oats, "raw_weight_g": 100.0},
+			{"food_id": satay, "raw_weight_g": 100.0},
+		},
+	}
+	var recipeOut struct {
+		ID        uint     `json:"id"`
+		Allergens []string `json:"allergens"`
+		DietFlags []string `json:"diet_flags"`
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes", recipePayload, env.Token, http.StatusCreated, &recipeOut)
+	if fmt.Sprint(recipeOut.Allergens) != "[gluten peanuts soybeans]" || fmt.Sprint(recipeOut.DietFlags) != "[vegan vegetarian]" {
+		t.Fatalf("unexpected inherited dietary info: %+v", recipeOut)
+	}
+
+	restrictions := map[string]any{"avoid_allergens": []string{"Peanuts"}, "required_diet_flags": []string{"vegetarian"}}
+	var profile struct {
+		AvoidAllergens    []string `json:"avoid_allergens"`
+		RequiredDietFlags []string `json:"required_diet_flags"`
+	}
+	doJSONWithToken(t, http.MethodPatch, env.BaseURL+"/api/v1/users/me", restrictions, env.Token, http.StatusOK, &profile)
+	if fmt.Sprint(profile.AvoidAllergens) != "[peanuts]" || fmt.Sprint(profile.RequiredDietFlags) != "[vegetarian]" {
+		t.Fatalf("unexpected restrictions: %+v", profile)
+	}
+
+	var foods []idOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods?exclude_conflicts=true", nil, env.Token, http.StatusOK, &foods)
+	if len(foods) != 2 || foods[0].ID != oats {
+		t.Fatalf("expected satay sauce to be excluded, got %+v", foods)
+	}
+	var recipes []idOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/recipes?exclude_conflicts=true", nil, env.Token, http.StatusOK, &recipes)
+	if len(recipes) != 0 {
+		t.Fatalf("expected conflicting recipe to be excluded, got %+v", recipes)
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/recipes", nil, env.Token, http.StatusOK, &recipes)
+	if len(recipes) != 1 {
+		t.Fatalf("expected recipe without exclude_conflicts, got %+v", recipes)
+	}
+
+	mealID := createMealWithFoodItem(t, env.BaseURL, oats, env.Token)
+	type itemOut struct {
+		Warnings []struct {
+			Code  string `json:"code"`
+			Value string `json:"value"`
+		} `json:"warnings"`
+	}
+	var conflicting itemOut
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/meals/%d/items", env.BaseURL, mealID), map[string]any{"recipe_id": recipeOut.ID, "weight_g": 150.0}, env.Token, http.StatusCreated, &conflicting)
+	if len(conflicting.Warnings) != 1 || conflicting.Warnings[0].Code != "allergen" || conflicting.Warnings[0].Value != "peanuts" {
+		t.Fatalf("expected peanut warning, got %+v", conflicting.Warnings)
+	}
+	var clean itemOut
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/meals/%d/items", env.BaseURL, mealID), map[string]any{"food_id": oats, "weight_g": 50.0}, env.Token, http.StatusCreated, &clean)
+	if len(clean.Warnings) != 0 {
+		t.Fatalf("expected no warnings for oats, got %+v", clean.Warnings)
+	}
+}
@@ -0,0 +1,96 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestFoodCategoriesAndTagsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	type categoryNode struct {
+		ID       uint           `json:"id"`
+		Slug     string         `json:"slug"`
+		Children []categoryNode `json:"children"`
+	}
+	var tree []categoryNode
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/food-categories", nil, env.Token, http.StatusOK, &tree)
+	ids := map[string]uint{}
+	var walk func([]categoryNode)
+	walk = func(nodes []categoryNode) {
+		for _, n := range nodes {
+			ids[n.Slug] = n.ID
+			walk(n.Children)
+		}
+	}
+	walk(tree)
+	if ids["dairy"] == 0 || ids["yogurt"] == 0 || ids["bread"] == 0 {
+		t.Fatalf("expected seeded taxonomy, got %+v", tree)
+	}
+
+	type foodOut struct {
+		ID         uint     `json:"id"`
+		CategoryID *uint    `json:"category_id"`
+		Tags       []string `json:"tags"`
+	}
+	create := func(name string, category uint, tags ...string) foodOut {
+		var out foodOut
+		payload := map[string]any{"name": name, "kcal_per_100g": 97.0, "protein_per_100g": 9.0, "carbs_per_100g": 3.9, "fat_per_100g": 5.0, "category_id": category, "tags": tags}
+		doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", payload, env.Token, http.StatusCreated, &out)
+		return out
+	}
+	yogurt := create("Greek yogurt", ids["yogurt"], "Breakfast", "high protein")
+	if len(yogurt.Tags) != 2 || yogurt.Tags[0] != "breakfast" {
+		t.Fatalf("expected normalized tags, got %+v", yogurt.Tags)
+	}
+	create("Skyr", ids["yogurt"], "breakfast")
+	create("Rye bread", ids["bread"], "breakfast")
+
+	var dairy []foodOut
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods?category_id=%d", env.BaseURL, ids["dairy"]), nil, env.Token, http.StatusOK, &dairy)
+	if len(dairy) != 2 {
+		t.Fatalf("expected subcategory foods under dairy, got %+v", dairy)
+	}
+
+	var tagged []foodOut
+	query := url.Values{"tag": {"breakfast", "high protein"}}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods?"+query.Encode(), nil, env.Token, http.StatusOK, &tagged)
+	if len(tagged) != 1 || tagged[0].ID != yogurt.ID {
+		t.Fatalf("expected only the food with both tags, got %+v", tagged)
+	}
+
+	var search struct {
+		Items  []foodOut `json:"items"`
+		Facets struct {
+			Categories []struct {
+				Slug  string `json:"slug"`
+				Count int64  `json:"count"`
+			} `json:"categories"`
+			Tags []struct {
+				Tag   string `json:"tag"`
+				Count int64  `json:"count"`
+			} `json:"tags"`
+		} `json:"facets"`
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/search?tag=breakfast&limit=1", nil, env.Token, http.StatusOK, &search)
+	if len(search.Items) != 1 {
+		t.Fatalf("expected one item on the page, got %+v", search.Items)
+	}
+	if len(search.Facets.Categories) != 2 || search.Facets.Categories[0].Slug != "yogurt" || search.Facets.Categories[0].Count != 2 {
+		t.Fatalf("unexpected category facets: %+v", search.Facets.Categories)
+	}
+	if len(search.Facets.Tags) != 2 || search.Facets.Tags[0].Tag != "breakfast" || search.Facets.Tags[0].Count != 3 {
+		t.Fatalf("unexpected tag facets: %+v", search.Facets.Tags)
+	}
+
+	var imported foodOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/by-barcode/5200435000027", nil, env.Token, http.StatusOK, &imported)
+	if imported.CategoryID == nil || *imported.CategoryID != ids["yogurt"] {
+		t.Fatalf("expected imported food in yogurt, got %+v", imported.CategoryID)
+	}
+}
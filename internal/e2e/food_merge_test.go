@@ -0,0 +1,126 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFoodDuplicatesAndMergeE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+	_, adminToken := env.newUser(t, "Admin User", "admin@example.com", true)
+
+	createPublic := func(name string, kcal float64) uint {
+		var out struct {
+			ID uint `json:"id"`
+		}
+		payload := map[string]any{"name": name, "kcal_per_100g": kcal, "protein_per_100g": 10.0, "carbs_per_100g": 4.0, "fat_per_100g": 2.0, "visibility": "public"}
+		doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", payload, env.Token, http.StatusCreated, &out)
+		return out.ID
+	}
+	survivorID := createPublic("Greek yogurt", 100)
+	duplicateID := createPublic("Yogurt, greek", 80)
+	createPublic("Rye bread", 250)
+
+	var duplicates []struct {
+		Food struct {
+			ID uint `json:"id"`
+		} `json:"food"`
+		Score float64 `json:"score"`
+	}
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods/%d/duplicates", env.BaseURL, survivorID), nil, env.Token, http.StatusOK, &duplicates)
+	if len(duplicates) != 1 || duplicates[0].Food.ID != duplicateID {
+		t.Fatalf("unexpected duplicates: %+v", duplicates)
+	}
+
+	recipeID := createRecipe(t, env.BaseURL, env.Token, duplicateID)
+	mealID := createMealWithFoodItem(t, env.BaseURL, duplicateID, env.Token)
+
+	// One list needs 200 g of each food, the other 200 g of the duplicate only.
+	type listOut struct {
+		ID    uint `json:"id"`
+		Items []struct {
+			ID      uint    `json:"id"`
+			FoodID  uint    `json:"food_id"`
+			WeightG float64 `json:"weight_g"`
+			Checked bool    `json:"checked"`
+		} `json:"items"`
+	}
+	survivorRecipeID := createRecipe(t, env.BaseURL, env.Token, survivorID)
+	var bothList, duplicateList listOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/shopping-lists", map[string]any{
+		"recipes": []map[string]any{{"recipe_id": recipeID}, {"recipe_id": survivorRecipeID}},
+	}, env.Token, http.StatusCreated, &bothList)
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/shopping-lists", map[string]any{
+		"recipes": []map[string]any{{"recipe_id": recipeID}},
+	}, env.Token, http.StatusCreated, &duplicateList)
+	for _, item := range bothList.Items {
+		if item.FoodID == survivorID {
+			doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/shopping-lists/%d/items/%d", env.BaseURL, bothList.ID, item.ID), map[string]any{"checked": true}, env.Token, http.StatusOK, nil)
+		}
+	}
+
+	mergePayload := map[string]any{"food_ids": []uint{duplicateID}}
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/foods/%d/merge", env.BaseURL, survivorID), mergePayload, env.Token, http.StatusForbidden, nil)
+
+	var merged struct {
+		Merges []struct {
+			MergedFoodID           uint `json:"merged_food_id"`
+			MealItemsMoved         int  `json:"meal_items_moved"`
+			RecipeIngredientsMoved int  `json:"recipe_ingredients_moved"`
+		} `json:"merges"`
+		RecalculatedRecipeIDs []uint `json:"recalculated_recipe_ids"`
+	}
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/foods/%d/merge", env.BaseURL, survivorID), mergePayload, adminToken, http.StatusOK, &merged)
+	if len(merged.Merges) != 1 || merged.Merges[0].MealItemsMoved != 1 || merged.Merges[0].RecipeIngredientsMoved != 1 {
+		t.Fatalf("unexpected merge log: %+v", merged)
+	}
+	if len(merged.RecalculatedRecipeIDs) != 1 || merged.RecalculatedRecipeIDs[0] != recipeID {
+		t.Fatalf("expected recipe %d recalculated, got %+v", recipeID, merged.RecalculatedRecipeIDs)
+	}
+
+	var recipeOut struct {
+		KcalPer100g float64 `json:"kcal_per_100g"`
+		Ingredients []struct {
+			FoodID uint `json:"food_id"`
+		} `json:"ingredients"`
+	}
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, recipeID), nil, env.Token, http.StatusOK, &recipeOut)
+	if recipeOut.KcalPer100g != 100 || len(recipeOut.Ingredients) != 1 || recipeOut.Ingredients[0].FoodID != survivorID {
+		t.Fatalf("expected recipe to use survivor values, got %+v", recipeOut)
+	}
+
+	var mealOut struct {
+		Items []struct {
+			FoodID *uint `json:"food_id"`
+		} `json:"items"`
+	}
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/meals/%d", env.BaseURL, mealID), nil, env.Token, http.StatusOK, &mealOut)
+	if len(mealOut.Items) != 1 || mealOut.Items[0].FoodID == nil || *mealOut.Items[0].FoodID != survivorID {
+		t.Fatalf("expected meal item re-pointed to survivor, got %+v", mealOut)
+	}
+
+	// Items of the merged food join the survivor's item in the same list.
+	for _, tc := range []struct {
+		listID  uint
+		weightG float64
+	}{{bothList.ID, 400}, {duplicateList.ID, 200}} {
+		var list listOut
+		doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/shopping-lists/%d", env.BaseURL, tc.listID), nil, env.Token, http.StatusOK, &list)
+		if len(list.Items) != 1 || list.Items[0].FoodID != survivorID || list.Items[0].WeightG != tc.weightG || list.Items[0].Checked {
+			t.Fatalf("expected one unchecked survivor item of %v g, got %+v", tc.weightG, list.Items)
+		}
+	}
+
+	var archived struct {
+		ArchivedAt   *string `json:"archived_at"`
+		MergedIntoID *uint   `json:"merged_into_id"`
+	}
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, duplicateID), nil, env.Token, http.StatusOK, &archived)
+	if archived.ArchivedAt == nil || archived.MergedIntoID == nil || *archived.MergedIntoID != survivorID {
+		t.Fatalf("expected merged food archived into survivor, got %+v", archived)
+	}
+}
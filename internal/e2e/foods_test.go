@@ -73,3 +73,149 @@ func TestFoodsE2E(t *testing.T) {
 
 	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", barcodePayload, env.Token, http.StatusConflict, nil)
 }
+
+func TestFoodVisibilityE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	_, otherToken := env.newUser(t, "Other User", "other@example.com", false)
+	_, adminToken := env.newUser(t, "Admin User", "admin@example.com", true)
+
+	const barcode = "5901234123457"
+	foodPayload := func(name, visibility string) map[string]any {
+		return map[string]any{
+			"name":             name,
+			"barcode":          barcode,
+			"kcal_per_100g":    410.0,
+			"protein_per_100g": 30.0,
+			"carbs_per_100g":   40.0,
+			"fat_per_100g":     12.0,
+			"visibility":       visibility,
+		}
+	}
+	type foodOut struct {
+		ID         uint   `json:"id"`
+		Visibility string `json:"visibility"`
+	}
+
+	// Foods default to private and stay hidden from other users.
+	var private foodOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", map[string]any{"name": "Secret Stew"}, env.Token, http.StatusCreated, &private)
+	if private.Visibility != "private" {
+		t.Fatalf("expected private visibility by default, got %q", private.Visibility)
+	}
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, private.ID), nil, otherToken, http.StatusNotFound, nil)
+	var otherList []foodOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods?q=stew", nil, otherToken, http.StatusOK, &otherList)
+	if len(otherList) != 0 {
+		t.Fatalf("expected private food hidden from search, got %+v", otherList)
+	}
+
+	// Barcodes are unique per scope: a private entry does not block a public
+	// one from another user, and each user's lookup prefers what they can see.
+	var mine foodOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", foodPayload("My Bar", "private"), env.Token, http.StatusCreated, &mine)
+	var shared foodOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", foodPayload("Shared Bar", "public"), otherToken, http.StatusCreated, &shared)
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", foodPayload("Another Bar", "public"), env.Token, http.StatusConflict, nil)
+
+	var lookup foodOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/by-barcode/"+barcode, nil, env.Token, http.StatusOK, &lookup)
+	if lookup.ID != mine.ID {
+		t.Fatalf("expected own private entry %d, got %d", mine.ID, lookup.ID)
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/by-barcode/"+barcode, nil, adminToken, http.StatusOK, &lookup)
+	if lookup.ID != shared.ID {
+		t.Fatalf("expected public entry %d, got %d", shared.ID, lookup.ID)
+	}
+
+	// Only admins verify, and verified entries win lookups for everyone.
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/foods/%d/verify", env.BaseURL, shared.ID), nil, otherToken, http.StatusForbidden, nil)
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", foodPayload("Fake Verified", "verified"), otherToken, http.StatusForbidden, nil)
+	var verified foodOut
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/foods/%d/verify", env.BaseURL, shared.ID), nil, adminToken, http.StatusOK, &verified)
+	if verified.Visibility != "verified" {
+		t.Fatalf("expected verified visibility, got %q", verified.Visibility)
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/by-barcode/"+barcode, nil, env.Token, http.StatusOK, &lookup)
+	if lookup.ID != shared.ID {
+		t.Fatalf("expected verified entry %d to win, got %d", shared.ID, lookup.ID)
+	}
+
+	// The owner can no longer edit a verified food.
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, shared.ID), map[string]any{"name": "Renamed"}, otherToken, http.StatusForbidden, nil)
+
+	// Other users' private foods cannot be logged.
+	mealPayload := map[string]any{
+		"meal_type": "lunch",
+		"eaten_at":  "2026-02-17T12:00:00Z",
+		"items":     []map[string]any{{"food_id": private.ID, "weight_g": 100.0}},
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/meals", mealPayload, otherToken, http.StatusBadRequest, nil)
+}
+
+func TestFoodVersionsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	type foodOut struct {
+		ID          uint    `json:"id"`
+		Name        string  `json:"name"`
+		KcalPer100g float64 `json:"kcal_per_100g"`
+		Version     int     `json:"version"`
+	}
+	var created foodOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", map[string]any{"name": "Oats", "kcal_per_100g": 389.0}, env.Token, http.StatusCreated, &created)
+	if created.Version != 1 {
+		t.Fatalf("expected version 1 on create, got %d", created.Version)
+	}
+
+	var updated foodOut
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, created.ID), map[string]any{"name": "Rolled Oats", "kcal_per_100g": 400.0}, env.Token, http.StatusOK, &updated)
+	if updated.Version != 2 {
+		t.Fatalf("expected version 2 after update, got %d", updated.Version)
+	}
+
+	var versions []struct {
+		Version      int     `json:"version"`
+		EditedBy     *uint   `json:"edited_by"`
+		RestoredFrom *int    `json:"restored_from"`
+		Name         string  `json:"name"`
+		KcalPer100g  float64 `json:"kcal_per_100g"`
+	}
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods/%d/versions", env.BaseURL, created.ID), nil, env.Token, http.StatusOK, &versions)
+	if len(versions) != 2 || versions[0].Version != 2 || versions[1].Version != 1 {
+		t.Fatalf("unexpected versions: %+v", versions)
+	}
+	if versions[0].EditedBy == nil || *versions[0].EditedBy != env.UserID {
+		t.Fatalf("expected editor %d, got %+v", env.UserID, versions[0].EditedBy)
+	}
+
+	// Meal items remember the version they were logged against.
+	mealPayload := map[string]any{
+		"meal_type": "breakfast",
+		"eaten_at":  "2026-02-17T08:00:00Z",
+		"items":     []map[string]any{{"food_id": created.ID, "weight_g": 50.0}},
+	}
+	var meal struct {
+		Items []struct {
+			FoodVersion *int `json:"food_version"`
+		} `json:"items"`
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/meals", mealPayload, env.Token, http.StatusCreated, &meal)
+	if len(meal.Items) != 1 || meal.Items[0].FoodVersion == nil || *meal.Items[0].FoodVersion != 2 {
+		t.Fatalf("expected meal item to record food version 2, got %+v", meal.Items)
+	}
+
+	// Rolling back appends a new version with the old values.
+	var rolledBack foodOut
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/foods/%d/versions/1/rollback", env.BaseURL, created.ID), nil, env.Token, http.StatusOK, &rolledBack)
+	if rolledBack.Version != 3 || rolledBack.Name != "Oats" || rolledBack.KcalPer100g != 389 {
+		t.Fatalf("unexpected rollback result: %+v", rolledBack)
+	}
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods/%d/versions", env.BaseURL, created.ID), nil, env.Token, http.StatusOK, &versions)
+	if len(versions) != 3 || versions[0].RestoredFrom == nil || *versions[0].RestoredFrom != 1 {
+		t.Fatalf("expected latest version restored from 1, got %+v", versions)
+	}
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/foods/%d/versions/9/rollback", env.BaseURL, created.ID), nil, env.Token, http.StatusNotFound, nil)
+}
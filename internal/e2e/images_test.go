@@ -0,0 +1,134 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+)
+
+type imageURLs struct {
+	ImageURL     *string `json:"image_url"`
+	ThumbnailURL *string `json:"thumbnail_url"`
+}
+
+func uploadImage(t *testing.T, url, token string, data []byte, expectedStatus int) imageURLs {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("image", "photo.png")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatalf("close form: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, &body)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != expectedStatus {
+		t.Fatalf("expected status %d got %d body=%s", expectedStatus, resp.StatusCode, string(raw))
+	}
+	var out imageURLs
+	if expectedStatus == http.StatusOK {
+		if err := json.Unmarshal(raw, &out); err != nil {
+			t.Fatalf("decode response: %v body=%s", err, string(raw))
+		}
+	}
+	return out
+}
+
+func fetchMedia(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("fetch media: %v", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, resp.Header.Get("Content-Type")
+}
+
+func TestImagesE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	var photo bytes.Buffer
+	if err := png.Encode(&photo, image.NewGray(image.Rect(0, 0, 2400, 1200))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+
+	foodID := createFood(t, env.BaseURL, env.Token, "Rice", 130, 2.7, 28, 0.3)
+	foodImageURL := fmt.Sprintf("%s/api/v1/foods/%d/image", env.BaseURL, foodID)
+	first := uploadImage(t, foodImageURL, env.Token, photo.Bytes(), http.StatusOK)
+	if first.ImageURL == nil || first.ThumbnailURL == nil {
+		t.Fatalf("expected image urls, got %+v", first)
+	}
+	if status, contentType := fetchMedia(t, env.BaseURL+*first.ImageURL); status != http.StatusOK || contentType != "image/png" {
+		t.Fatalf("expected stored png, got %d %s", status, contentType)
+	}
+	if status, contentType := fetchMedia(t, env.BaseURL+*first.ThumbnailURL); status != http.StatusOK || contentType != "image/jpeg" {
+		t.Fatalf("expected stored jpeg thumbnail, got %d %s", status, contentType)
+	}
+
+	// Replacing the image removes the previous renditions.
+	second := uploadImage(t, foodImageURL, env.Token, photo.Bytes(), http.StatusOK)
+	if second.ImageURL == nil || *second.ImageURL == *first.ImageURL {
+		t.Fatalf("expected a new image url, got %+v", second)
+	}
+	if status, _ := fetchMedia(t, env.BaseURL+*first.ImageURL); status != http.StatusNotFound {
+		t.Fatalf("expected previous image to be deleted, got %d", status)
+	}
+
+	var fetched imageURLs
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, foodID), nil, env.Token, http.StatusOK, &fetched)
+	if fetched.ImageURL == nil || *fetched.ImageURL != *second.ImageURL {
+		t.Fatalf("expected food to carry the new image, got %+v", fetched)
+	}
+
+	uploadImage(t, foodImageURL, env.Token, []byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), http.StatusUnsupportedMediaType)
+
+	doJSONWithToken(t, http.MethodDelete, foodImageURL, nil, env.Token, http.StatusNoContent, nil)
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, foodID), nil, env.Token, http.StatusOK, &fetched)
+	if fetched.ImageURL != nil || fetched.ThumbnailURL != nil {
+		t.Fatalf("expected image to be removed, got %+v", fetched)
+	}
+	if status, _ := fetchMedia(t, env.BaseURL+*second.ThumbnailURL); status != http.StatusNotFound {
+		t.Fatalf("expected thumbnail to be deleted, got %d", status)
+	}
+
+	recipeID := createRecipe(t, env.BaseURL, env.Token, foodID)
+	recipeImage := uploadImage(t, fmt.Sprintf("%s/api/v1/recipes/%d/image", env.BaseURL, recipeID), env.Token, photo.Bytes(), http.StatusOK)
+	if recipeImage.ThumbnailURL == nil {
+		t.Fatalf("expected recipe thumbnail, got %+v", recipeImage)
+	}
+	_, otherToken := env.newUser(t, "Other", "other-images@example.com", false)
+	uploadImage(t, fmt.Sprintf("%s/api/v1/recipes/%d/image", env.BaseURL, recipeID), otherToken, photo.Bytes(), http.StatusForbidden)
+
+	mealID := createMealWithFoodItem(t, env.BaseURL, foodID, env.Token)
+	mealImage := uploadImage(t, fmt.Sprintf("%s/api/v1/meals/%d/image", env.BaseURL, mealID), env.Token, photo.Bytes(), http.StatusOK)
+	if mealImage.ImageURL == nil {
+		t.Fatalf("expected meal image, got %+v", mealImage)
+	}
+	uploadImage(t, fmt.Sprintf("%s/api/v1/meals/%d/image", env.BaseURL, mealID), otherToken, photo.Bytes(), http.StatusNotFound)
+}
@@ -0,0 +1,56 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNestedRecipesE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	rice := createFood(t, env.BaseURL, env.Token, "Rice", 130, 2.7, 28, 0.3)
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, rice), map[string]any{"allergens": []string{"sesame"}}, env.Token, http.StatusOK, nil)
+	// 200 g of rice cooked to 200 g: 130 kcal/100g.
+	limeRice := createRecipe(t, env.BaseURL, env.Token, rice)
+
+	var bowl struct {
+		ID          uint     `json:"id"`
+		KcalPer100g float64  `json:"kcal_per_100g"`
+		Allergens   []string `json:"allergens"`
+	}
+	payload := map[string]any{
+		"name":           "Burrito bowl",
+		"yield_weight_g": 100.0,
+		"ingredients":    []map[string]any{{"sub_recipe_id": limeRice, "raw_weight_g": 50.0}},
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes", payload, env.Token, http.StatusCreated, &bowl)
+	if bowl.KcalPer100g != 65 {
+		t.Fatalf("expected 65 kcal per 100g, got %+v", bowl)
+	}
+	if len(bowl.Allergens) != 1 || bowl.Allergens[0] != "sesame" {
+		t.Fatalf("expected allergens from the sub-recipe, got %+v", bowl)
+	}
+
+	// The sub-recipe cannot use the bowl that contains it.
+	limeRiceURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, limeRice)
+	cycle := map[string]any{"ingredients": []map[string]any{{"sub_recipe_id": bowl.ID, "raw_weight_g": 10.0}}}
+	doJSONWithToken(t, http.MethodPatch, limeRiceURL, cycle, env.Token, http.StatusBadRequest, nil)
+
+	// Halving the sub-recipe's yield doubles its density and the bowl's.
+	doJSONWithToken(t, http.MethodPatch, limeRiceURL, map[string]any{"yield_weight_g": 100.0}, env.Token, http.StatusOK, nil)
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, bowl.ID), nil, env.Token, http.StatusOK, &bowl)
+	if bowl.KcalPer100g != 130 {
+		t.Fatalf("expected parent to follow its sub-recipe, got %+v", bowl)
+	}
+
+	// A food change reaches recipes through their sub-recipes.
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, rice), map[string]any{"kcal_per_100g": 65.0}, env.Token, http.StatusOK, nil)
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, bowl.ID), nil, env.Token, http.StatusOK, &bowl)
+	if bowl.KcalPer100g != 65 {
+		t.Fatalf("expected parent to follow the food, got %+v", bowl)
+	}
+}
@@ -0,0 +1,90 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+)
+
+type nutritionBreakdown struct {
+	Breakdown struct {
+		Items []struct {
+			FoodID       *uint  `json:"food_id"`
+			RecipeID     *uint  `json:"recipe_id"`
+			Name         string `json:"name"`
+			Contribution struct {
+				Kcal float64 `json:"kcal"`
+				FatG float64 `json:"fat_g"`
+			} `json:"contribution"`
+			Share struct {
+				KcalPct float64 `json:"kcal_pct"`
+				FatPct  float64 `json:"fat_pct"`
+			} `json:"share"`
+		} `json:"items"`
+		Total struct {
+			Kcal float64 `json:"kcal"`
+		} `json:"total"`
+		CalorieSplit struct {
+			ProteinPct float64 `json:"protein_pct"`
+			CarbsPct   float64 `json:"carbs_pct"`
+			FatPct     float64 `json:"fat_pct"`
+		} `json:"calorie_split"`
+	} `json:"breakdown"`
+}
+
+func TestNutritionBreakdownE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	rice := createFood(t, env.BaseURL, env.Token, "Rice", 130, 2.7, 28, 0.3)
+	oil := createFood(t, env.BaseURL, env.Token, "Olive oil", 884, 0, 0, 100)
+	var created struct {
+		ID uint `json:"id"`
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes", map[string]any{
+		"name":           "Fried rice",
+		"yield_weight_g": 210.0,
+		"ingredients": []map[string]any{
+			{"food_id": rice, "raw_weight_g": 200.0},
+			{"food_id": oil, "raw_weight_g": 10.0},
+		},
+	}, env.Token, http.StatusCreated, &created)
+	recipeURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, created.ID)
+
+	var plain map[string]any
+	doJSONWithToken(t, http.MethodGet, recipeURL, nil, env.Token, http.StatusOK, &plain)
+	if _, ok := plain["breakdown"]; ok {
+		t.Fatalf("expected no breakdown unless asked for")
+	}
+
+	var recipeOut nutritionBreakdown
+	doJSONWithToken(t, http.MethodGet, recipeURL+"?breakdown=true", nil, env.Token, http.StatusOK, &recipeOut)
+	items := recipeOut.Breakdown.Items
+	if len(items) != 2 || items[0].Name != "Rice" || items[1].Name != "Olive oil" {
+		t.Fatalf("unexpected recipe breakdown %+v", recipeOut.Breakdown)
+	}
+	if items[0].Contribution.Kcal != 260 || items[1].Contribution.FatG != 10 || items[1].Share.FatPct < 94 {
+		t.Fatalf("unexpected contributions %+v", items)
+	}
+	split := recipeOut.Breakdown.CalorieSplit
+	if math.Abs(split.ProteinPct+split.CarbsPct+split.FatPct-100) > 0.01 || split.FatPct < 25 {
+		t.Fatalf("unexpected calorie split %+v", split)
+	}
+
+	mealID := createMealWithFoodItem(t, env.BaseURL, oil, env.Token)
+	addMealItemRecipe(t, env.BaseURL, mealID, created.ID, env.Token)
+	var mealOut nutritionBreakdown
+	doJSONWithToken(t, http.MethodGet, fmt.Sprintf("%s/api/v1/meals/%d?breakdown=true", env.BaseURL, mealID), nil, env.Token, http.StatusOK, &mealOut)
+	items = mealOut.Breakdown.Items
+	if len(items) != 2 || items[0].Name != "Olive oil" || items[1].RecipeID == nil || items[1].Name != "Fried rice" {
+		t.Fatalf("unexpected meal breakdown %+v", mealOut.Breakdown)
+	}
+	if math.Abs(items[0].Share.KcalPct+items[1].Share.KcalPct-100) > 0.01 || mealOut.Breakdown.Total.Kcal <= items[0].Contribution.Kcal {
+		t.Fatalf("unexpected meal shares %+v", mealOut.Breakdown)
+	}
+
+	doJSONWithToken(t, http.MethodGet, recipeURL+"?breakdown=maybe", nil, env.Token, http.StatusBadRequest, nil)
+}
@@ -0,0 +1,60 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFoodNutritionChecksE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	type foodOut struct {
+		ID       uint `json:"id"`
+		Warnings []struct {
+			Code string `json:"code"`
+		} `json:"warnings"`
+	}
+
+	oil := map[string]any{"name": "Olive oil", "kcal_per_100g": 50.0, "protein_per_100g": 0.0, "carbs_per_100g": 0.0, "fat_per_100g": 40.0}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods?validation=strict", oil, env.Token, http.StatusBadRequest, nil)
+
+	var lenient foodOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", oil, env.Token, http.StatusCreated, &lenient)
+	if len(lenient.Warnings) != 1 || lenient.Warnings[0].Code != "kcal_mismatch" {
+		t.Fatalf("expected kcal_mismatch warning, got %+v", lenient.Warnings)
+	}
+
+	wine := map[string]any{"name": "Red wine", "kcal_per_100g": 83.0, "protein_per_100g": 0.0, "carbs_per_100g": 2.6, "fat_per_100g": 0.0, "alcohol_per_100g": 10.3}
+	var wineOut foodOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods?validation=strict", wine, env.Token, http.StatusCreated, &wineOut)
+	if len(wineOut.Warnings) != 0 {
+		t.Fatalf("expected no warnings for wine, got %+v", wineOut.Warnings)
+	}
+
+	var report []struct {
+		Food struct {
+			ID uint `json:"id"`
+		} `json:"food"`
+		EstimatedKcal float64 `json:"estimated_kcal"`
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/nutrition-report", nil, env.Token, http.StatusOK, &report)
+	if len(report) != 1 || report[0].Food.ID != lenient.ID || report[0].EstimatedKcal != 360 {
+		t.Fatalf("unexpected nutrition report: %+v", report)
+	}
+
+	fix := map[string]any{"kcal_per_100g": 360.0}
+	var fixed foodOut
+	doJSONWithToken(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/foods/%d?validation=strict", env.BaseURL, lenient.ID), fix, env.Token, http.StatusOK, &fixed)
+	if len(fixed.Warnings) != 0 {
+		t.Fatalf("expected no warnings after fix, got %+v", fixed.Warnings)
+	}
+
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/foods/nutrition-report", nil, env.Token, http.StatusOK, &report)
+	if len(report) != 0 {
+		t.Fatalf("expected empty report after fix, got %+v", report)
+	}
+}
@@ -0,0 +1,71 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"net/http"
+	"testing"
+
+	"goal-bite-api/internal/oidc/oidctest"
+)
+
+func TestOIDCLoginE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	var providers []string
+	doJSON(t, http.MethodGet, env.BaseURL+"/api/v1/auth/oidc/providers", nil, http.StatusOK, &providers)
+	if len(providers) != 1 || providers[0] != "acme" {
+		t.Fatalf("unexpected providers: %v", providers)
+	}
+
+	type authOut struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		User         struct {
+			ID    uint   `json:"id"`
+			Email string `json:"email"`
+		} `json:"user"`
+	}
+	login := func(expectedStatus int, out any) {
+		t.Helper()
+		var start struct {
+			AuthorizationURL string `json:"authorization_url"`
+		}
+		doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/oidc/acme/begin", nil, http.StatusOK, &start)
+		code, state, err := env.OIDC.Authorize(start.AuthorizationURL)
+		if err != nil {
+			t.Fatalf("authorize: %v", err)
+		}
+		doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/oidc/acme/finish", map[string]any{
+			"code":  code,
+			"state": state,
+		}, expectedStatus, out)
+	}
+
+	// Verified email of the seeded user links to that account.
+	env.OIDC.SetUser(oidctest.User{Subject: "acme-1", Email: "e2e@example.com", EmailVerified: true})
+	var linked authOut
+	login(http.StatusOK, &linked)
+	if linked.User.ID != env.UserID || linked.AccessToken == "" || linked.RefreshToken == "" {
+		t.Fatalf("expected login as seeded user, got %+v", linked)
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/auth/me", nil, linked.AccessToken, http.StatusOK, nil)
+
+	// Unknown verified email creates a new account.
+	env.OIDC.SetUser(oidctest.User{Subject: "acme-2", Email: "someone.new@example.com", EmailVerified: true, Name: "Someone New"})
+	var created authOut
+	login(http.StatusOK, &created)
+	if created.User.ID == 0 || created.User.ID == env.UserID || created.User.Email != "someone.new@example.com" {
+		t.Fatalf("expected new account, got %+v", created)
+	}
+
+	// Unverified emails are never linked.
+	env.OIDC.SetUser(oidctest.User{Subject: "acme-3", Email: "e2e@example.com", EmailVerified: false})
+	login(http.StatusForbidden, nil)
+
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/oidc/acme/finish", map[string]any{
+		"code":  "forged",
+		"state": "forged",
+	}, http.StatusUnauthorized, nil)
+}
@@ -0,0 +1,85 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"goal-bite-api/internal/webauthn"
+	"goal-bite-api/internal/webauthn/webauthntest"
+)
+
+func TestPasskeysE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	authenticator, err := webauthntest.New(testWebAuthnConfig.RPID, testWebAuthnConfig.Origins[0])
+	if err != nil {
+		t.Fatalf("new authenticator: %v", err)
+	}
+
+	var creationOptions webauthn.CreationOptions
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/auth/passkeys/register/begin", nil, env.Token, http.StatusOK, &creationOptions)
+	registration, err := authenticator.Create(creationOptions)
+	if err != nil {
+		t.Fatalf("create credential: %v", err)
+	}
+
+	var created struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/auth/passkeys/register/finish", map[string]any{
+		"name":       "Laptop",
+		"credential": registration,
+	}, env.Token, http.StatusCreated, &created)
+	if created.ID == 0 || created.Name != "Laptop" {
+		t.Fatalf("unexpected passkey: %+v", created)
+	}
+
+	// The registration challenge is single use.
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/auth/passkeys/register/finish", map[string]any{
+		"name":       "Laptop",
+		"credential": registration,
+	}, env.Token, http.StatusBadRequest, nil)
+
+	var listed []struct {
+		ID uint `json:"id"`
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/auth/passkeys", nil, env.Token, http.StatusOK, &listed)
+	if len(listed) != 1 || listed[0].ID != created.ID {
+		t.Fatalf("unexpected passkey list: %+v", listed)
+	}
+
+	var requestOptions webauthn.RequestOptions
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/passkeys/login/begin", nil, http.StatusOK, &requestOptions)
+	assertion, err := authenticator.Get(requestOptions)
+	if err != nil {
+		t.Fatalf("get assertion: %v", err)
+	}
+	var loginOut struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+		User         struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/passkeys/login/finish", map[string]any{"credential": assertion}, http.StatusOK, &loginOut)
+	if loginOut.Token == "" || loginOut.RefreshToken == "" || loginOut.User.ID != env.UserID {
+		t.Fatalf("unexpected login result: %+v", loginOut)
+	}
+
+	// Replaying the same assertion fails because its challenge was consumed.
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/passkeys/login/finish", map[string]any{"credential": assertion}, http.StatusUnauthorized, nil)
+
+	doJSONWithToken(t, http.MethodDelete, fmt.Sprintf("%s/api/v1/auth/passkeys/%d", env.BaseURL, created.ID), nil, env.Token, http.StatusNoContent, nil)
+
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/passkeys/login/begin", nil, http.StatusOK, &requestOptions)
+	assertion, err = authenticator.Get(requestOptions)
+	if err != nil {
+		t.Fatalf("get assertion: %v", err)
+	}
+	doJSON(t, http.MethodPost, env.BaseURL+"/api/v1/auth/passkeys/login/finish", map[string]any{"credential": assertion}, http.StatusUnauthorized, nil)
+}
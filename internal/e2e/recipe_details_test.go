@@ -0,0 +1,77 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type detailedRecipe struct {
+	ID           uint     `json:"id"`
+	Description  string   `json:"description"`
+	PrepTimeMin  int      `json:"prep_time_min"`
+	CookTimeMin  int      `json:"cook_time_min"`
+	Tags         []string `json:"tags"`
+	Instructions []string `json:"instructions"`
+	Ingredients  []struct {
+		Note string `json:"note"`
+	} `json:"ingredients"`
+}
+
+func TestRecipeDetailsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	beef := createFood(t, env.BaseURL, env.Token, "Beef", 250, 26, 0, 15)
+	var created detailedRecipe
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes", map[string]any{
+		"name":           "Goulash",
+		"yield_weight_g": 1000.0,
+		"description":    "Hearty stew.",
+		"prep_time_min":  20,
+		"cook_time_min":  90,
+		"tags":           []string{"Dinner", "Hungarian"},
+		"instructions":   []string{"Brown the beef.", "Simmer for 90 minutes."},
+		"ingredients": []map[string]any{
+			{"food_id": beef, "raw_weight_g": 500.0, "note": "cubed"},
+		},
+	}, env.Token, http.StatusCreated, &created)
+	if created.Description != "Hearty stew." || created.PrepTimeMin != 20 || created.CookTimeMin != 90 {
+		t.Fatalf("unexpected details %+v", created)
+	}
+	if len(created.Tags) != 2 || created.Tags[0] != "dinner" || len(created.Instructions) != 2 || created.Ingredients[0].Note != "cubed" {
+		t.Fatalf("unexpected tags, steps or notes %+v", created)
+	}
+	quick := createRecipe(t, env.BaseURL, env.Token, beef)
+	quickURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, quick)
+	doJSONWithToken(t, http.MethodPatch, quickURL, map[string]any{"prep_time_min": 10, "tags": []string{"dinner", "quick"}}, env.Token, http.StatusOK, nil)
+
+	listed := func(query string) []uint {
+		var out []detailedRecipe
+		doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/recipes?"+query, nil, env.Token, http.StatusOK, &out)
+		ids := make([]uint, 0, len(out))
+		for _, r := range out {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+	if ids := listed("tag=dinner"); len(ids) != 2 {
+		t.Fatalf("expected both dinner recipes, got %v", ids)
+	}
+	if ids := listed("tag=dinner&tag=hungarian"); len(ids) != 1 || ids[0] != created.ID {
+		t.Fatalf("expected only goulash, got %v", ids)
+	}
+	if ids := listed("max_total_time=30"); len(ids) != 1 || ids[0] != quick {
+		t.Fatalf("expected only the quick recipe, got %v", ids)
+	}
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/recipes?max_total_time=-1", nil, env.Token, http.StatusBadRequest, nil)
+
+	var updated detailedRecipe
+	recipeURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, created.ID)
+	doJSONWithToken(t, http.MethodPatch, recipeURL, map[string]any{"cook_time_min": 0, "instructions": []string{}}, env.Token, http.StatusOK, &updated)
+	if updated.CookTimeMin != 0 || updated.PrepTimeMin != 20 || len(updated.Instructions) != 0 || updated.Ingredients[0].Note != "cubed" {
+		t.Fatalf("expected cook time and steps cleared, got %+v", updated)
+	}
+}
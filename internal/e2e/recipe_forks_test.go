@@ -0,0 +1,77 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type forkedRecipe struct {
+	ID           uint    `json:"id"`
+	UserID       uint    `json:"user_id"`
+	ForkedFromID *uint   `json:"forked_from_id"`
+	Name         string  `json:"name"`
+	KcalPer100g  float64 `json:"kcal_per_100g"`
+}
+
+type recipeChanges struct {
+	OriginID    uint     `json:"origin_id"`
+	Fields      []string `json:"fields"`
+	Ingredients []struct {
+		Change       string   `json:"change"`
+		FoodID       *uint    `json:"food_id"`
+		ToRawWeightG *float64 `json:"to_raw_weight_g"`
+	} `json:"ingredients"`
+}
+
+func TestRecipeForksE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+	otherID, otherToken := env.newUser(t, "Other User", "other@example.com", false)
+
+	rice := createFood(t, env.BaseURL, env.Token, "Rice", 130, 2.7, 28, 0.3)
+	recipeID := createRecipe(t, env.BaseURL, env.Token, rice)
+	recipeURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, recipeID)
+
+	// Only the owner may edit, but anyone may fork.
+	doJSONWithToken(t, http.MethodPatch, recipeURL, map[string]any{"name": "Mine"}, otherToken, http.StatusForbidden, nil)
+	var fork forkedRecipe
+	doJSONWithToken(t, http.MethodPost, recipeURL+"/fork", nil, otherToken, http.StatusCreated, &fork)
+	if fork.UserID != otherID || fork.ForkedFromID == nil || *fork.ForkedFromID != recipeID || fork.KcalPer100g != 130 {
+		t.Fatalf("unexpected fork %+v", fork)
+	}
+
+	forkURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, fork.ID)
+	beans := createFood(t, env.BaseURL, otherToken, "Beans", 340, 21, 60, 1.2)
+	doJSONWithToken(t, http.MethodPatch, forkURL, map[string]any{
+		"name": "Rice and beans",
+		"ingredients": []map[string]any{
+			{"food_id": rice, "raw_weight_g": 200.0},
+			{"food_id": beans, "raw_weight_g": 50.0},
+		},
+	}, otherToken, http.StatusOK, nil)
+
+	var changes recipeChanges
+	doJSONWithToken(t, http.MethodGet, forkURL+"/changes", nil, otherToken, http.StatusOK, &changes)
+	if changes.OriginID != recipeID || len(changes.Fields) != 1 || changes.Fields[0] != "name" {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if len(changes.Ingredients) != 1 || changes.Ingredients[0].Change != "added" || *changes.Ingredients[0].FoodID != beans {
+		t.Fatalf("expected beans added, got %+v", changes.Ingredients)
+	}
+	doJSONWithToken(t, http.MethodGet, recipeURL+"/changes", nil, env.Token, http.StatusNotFound, nil)
+
+	var forks []struct {
+		Recipe  forkedRecipe  `json:"recipe"`
+		Changes recipeChanges `json:"changes"`
+	}
+	doJSONWithToken(t, http.MethodGet, recipeURL+"/forks", nil, env.Token, http.StatusOK, &forks)
+	if len(forks) != 1 || forks[0].Recipe.ID != fork.ID || forks[0].Recipe.Name != "Rice and beans" {
+		t.Fatalf("unexpected forks %+v", forks)
+	}
+
+	doJSONWithToken(t, http.MethodDelete, recipeURL, nil, env.Token, http.StatusNoContent, nil)
+	doJSONWithToken(t, http.MethodPost, recipeURL+"/fork", nil, otherToken, http.StatusConflict, nil)
+}
@@ -0,0 +1,63 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+type recipeDraft struct {
+	Name         string  `json:"name"`
+	YieldWeightG float64 `json:"yield_weight_g"`
+	Servings     int     `json:"servings"`
+	Ingredients  []struct {
+		Name       string   `json:"name"`
+		RawWeightG *float64 `json:"raw_weight_g"`
+		FoodID     *uint    `json:"food_id"`
+		Matches    []struct {
+			Food struct {
+				ID uint `json:"id"`
+			} `json:"food"`
+			Confidence float64 `json:"confidence"`
+		} `json:"matches"`
+	} `json:"ingredients"`
+}
+
+func TestRecipeImportE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	chicken := createFood(t, env.BaseURL, env.Token, "Chicken breast", 165, 31, 0, 3.6)
+	rice := createFood(t, env.BaseURL, env.Token, "Rice", 130, 2.7, 28, 0.3)
+	importURL := env.BaseURL + "/api/v1/recipes/import"
+
+	var draft recipeDraft
+	doJSONWithToken(t, http.MethodPost, importURL, map[string]any{
+		"content": "200 g chicken breast\n1 cup rice\n2 tbsp olive oil",
+	}, env.Token, http.StatusOK, &draft)
+	if len(draft.Ingredients) != 3 || draft.YieldWeightG != 470 {
+		t.Fatalf("unexpected draft %+v", draft)
+	}
+	if got := draft.Ingredients[0].FoodID; got == nil || *got != chicken {
+		t.Fatalf("expected chicken breast matched to %d, got %+v", chicken, draft.Ingredients[0])
+	}
+	if got := draft.Ingredients[1].FoodID; got == nil || *got != rice {
+		t.Fatalf("expected rice matched to %d, got %+v", rice, draft.Ingredients[1])
+	}
+	if draft.Ingredients[2].FoodID != nil {
+		t.Fatalf("expected olive oil left for the user to pick, got %+v", draft.Ingredients[2])
+	}
+
+	page := `<html><head><script type="application/ld+json">
+{"@context":"https://schema.org","@type":"Recipe","name":"Chicken rice","recipeYield":"2 servings",
+ "recipeIngredient":["150 g chicken breast","100 g rice"]}
+</script></head></html>`
+	doJSONWithToken(t, http.MethodPost, importURL, map[string]any{"content": page}, env.Token, http.StatusOK, &draft)
+	if draft.Name != "Chicken rice" || draft.Servings != 2 || len(draft.Ingredients) != 2 {
+		t.Fatalf("unexpected draft from page %+v", draft)
+	}
+
+	doJSONWithToken(t, http.MethodPost, importURL, map[string]any{"content": "<html><body>hello</body></html>"}, env.Token, http.StatusBadRequest, nil)
+	doJSONWithToken(t, http.MethodPost, importURL, map[string]any{"content": ""}, env.Token, http.StatusBadRequest, nil)
+}
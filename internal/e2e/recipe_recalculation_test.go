@@ -0,0 +1,51 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type recipeNutrition struct {
+	ID             uint    `json:"id"`
+	ProteinPer100g float64 `json:"protein_per_100g"`
+	Stale          bool    `json:"stale"`
+}
+
+func TestRecipeRecalculationE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	chicken := createFood(t, env.BaseURL, env.Token, "Chicken breast", 165, 20, 0, 3.6)
+	recipeID := createRecipe(t, env.BaseURL, env.Token, chicken)
+	recipeURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, recipeID)
+	foodURL := fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, chicken)
+
+	// Few dependents: the recipe follows the food before the update returns.
+	doJSONWithToken(t, http.MethodPatch, foodURL, map[string]any{"protein_per_100g": 31.0}, env.Token, http.StatusOK, nil)
+	var got recipeNutrition
+	doJSONWithToken(t, http.MethodGet, recipeURL, nil, env.Token, http.StatusOK, &got)
+	if got.ProteinPer100g != 31 || got.Stale {
+		t.Fatalf("expected recalculated recipe, got %+v", got)
+	}
+
+	// Past the sync limit every dependent recipe is left stale.
+	for i := 0; i < 20; i++ {
+		createRecipe(t, env.BaseURL, env.Token, chicken)
+	}
+	doJSONWithToken(t, http.MethodPatch, foodURL, map[string]any{"protein_per_100g": 25.0}, env.Token, http.StatusOK, nil)
+	doJSONWithToken(t, http.MethodGet, recipeURL, nil, env.Token, http.StatusOK, &got)
+	if got.ProteinPer100g != 31 || !got.Stale {
+		t.Fatalf("expected stale recipe, got %+v", got)
+	}
+
+	_, otherToken := env.newUser(t, "Other", "other-recalc@example.com", false)
+	doJSONWithToken(t, http.MethodPost, recipeURL+"/recalculate", nil, otherToken, http.StatusForbidden, nil)
+	doJSONWithToken(t, http.MethodPost, recipeURL+"/recalculate", nil, env.Token, http.StatusOK, &got)
+	if got.ProteinPer100g != 25 || got.Stale {
+		t.Fatalf("expected refreshed recipe, got %+v", got)
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes/999999/recalculate", nil, env.Token, http.StatusNotFound, nil)
+}
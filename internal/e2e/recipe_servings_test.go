@@ -0,0 +1,79 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type recipePortion struct {
+	WeightG float64 `json:"weight_g"`
+	Kcal    float64 `json:"kcal"`
+}
+
+type servedRecipe struct {
+	ID           uint          `json:"id"`
+	YieldWeightG float64       `json:"yield_weight_g"`
+	Servings     int           `json:"servings"`
+	ServingName  string        `json:"serving_name"`
+	PerServing   recipePortion `json:"per_serving"`
+	PerBatch     recipePortion `json:"per_batch"`
+	Ingredients  []struct {
+		RawWeightG float64 `json:"raw_weight_g"`
+	} `json:"ingredients"`
+}
+
+func TestRecipeServingsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	rice := createFood(t, env.BaseURL, env.Token, "Rice", 130, 2.7, 28, 0.3)
+	// 200 g of rice cooked to 200 g: 130 kcal/100g, one 200 g serving.
+	recipeID := createRecipe(t, env.BaseURL, env.Token, rice)
+	recipeURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, recipeID)
+
+	var out servedRecipe
+	doJSONWithToken(t, http.MethodGet, recipeURL, nil, env.Token, http.StatusOK, &out)
+	if out.Servings != 1 || out.ServingName != "serving" || out.PerServing.Kcal != 260 {
+		t.Fatalf("expected one default serving, got %+v", out)
+	}
+
+	doJSONWithToken(t, http.MethodPatch, recipeURL, map[string]any{"servings": 2, "serving_name": "bowl"}, env.Token, http.StatusOK, &out)
+	if out.PerServing.WeightG != 100 || out.PerServing.Kcal != 130 || out.PerBatch.Kcal != 260 {
+		t.Fatalf("expected two 100 g bowls, got %+v", out)
+	}
+
+	var scaled servedRecipe
+	doJSONWithToken(t, http.MethodGet, recipeURL+"/scaled?servings=6", nil, env.Token, http.StatusOK, &scaled)
+	if scaled.Servings != 6 || scaled.YieldWeightG != 600 || len(scaled.Ingredients) != 1 || scaled.Ingredients[0].RawWeightG != 600 {
+		t.Fatalf("expected recipe tripled, got %+v", scaled)
+	}
+	doJSONWithToken(t, http.MethodGet, recipeURL+"/scaled?servings=0", nil, env.Token, http.StatusBadRequest, nil)
+	// Scaling does not save anything.
+	doJSONWithToken(t, http.MethodGet, recipeURL, nil, env.Token, http.StatusOK, &out)
+	if out.Servings != 2 || out.YieldWeightG != 200 {
+		t.Fatalf("expected stored recipe unchanged, got %+v", out)
+	}
+
+	mealID := createMealWithFoodItem(t, env.BaseURL, rice, env.Token)
+	var item struct {
+		ID       uint     `json:"id"`
+		WeightG  float64  `json:"weight_g"`
+		Servings *float64 `json:"servings"`
+	}
+	itemsURL := fmt.Sprintf("%s/api/v1/meals/%d/items", env.BaseURL, mealID)
+	doJSONWithToken(t, http.MethodPost, itemsURL, map[string]any{"recipe_id": recipeID, "servings": 1.5}, env.Token, http.StatusCreated, &item)
+	if item.WeightG != 150 || item.Servings == nil || *item.Servings != 1.5 {
+		t.Fatalf("expected 1.5 bowls logged as 150 g, got %+v", item)
+	}
+	doJSONWithToken(t, http.MethodPost, itemsURL, map[string]any{"food_id": rice, "servings": 1.0}, env.Token, http.StatusBadRequest, nil)
+
+	itemURL := fmt.Sprintf("%s/%d", itemsURL, item.ID)
+	item.Servings = nil
+	doJSONWithToken(t, http.MethodPatch, itemURL, map[string]any{"weight_g": 80.0}, env.Token, http.StatusOK, &item)
+	if item.WeightG != 80 || item.Servings != nil {
+		t.Fatalf("expected weight to replace servings, got %+v", item)
+	}
+}
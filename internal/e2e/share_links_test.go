@@ -0,0 +1,144 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// assertNoIDs fails when any object in a shared view carries an ID field,
+// which would let the viewer look up the owner, recipe, meal or foods
+// behind it.
+func assertNoIDs(t *testing.T, value any, path string) {
+	t.Helper()
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if key == "id" || strings.HasSuffix(key, "_id") {
+				t.Fatalf("expected no IDs in the shared view, got %s.%s = %v", path, key, item)
+			}
+			assertNoIDs(t, item, path+"."+key)
+		}
+	case []any:
+		for i, item := range v {
+			assertNoIDs(t, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// assertImageKeyWithoutID fails when a shared image URL still carries an
+// ID path segment below prefix.
+func assertImageKeyWithoutID(t *testing.T, url any, prefix string) {
+	t.Helper()
+	value, _ := url.(string)
+	_, key, ok := strings.Cut(value, prefix)
+	if !ok || strings.Contains(key, "/") {
+		t.Fatalf("expected an image below %s without an ID, got %v", prefix, url)
+	}
+}
+
+func TestShareLinksE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	rice := createFood(t, env.BaseURL, env.Token, "Rice", 130, 2.7, 28, 0.3)
+	var bowl struct {
+		ID uint `json:"id"`
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes", map[string]any{
+		"name":           "Rice bowl",
+		"yield_weight_g": 200.0,
+		"servings":       2,
+		"ingredients":    []map[string]any{{"food_id": rice, "raw_weight_g": 200.0}},
+	}, env.Token, http.StatusCreated, &bowl)
+
+	var photo bytes.Buffer
+	if err := png.Encode(&photo, image.NewGray(image.Rect(0, 0, 40, 40))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	uploadImage(t, fmt.Sprintf("%s/api/v1/recipes/%d/image", env.BaseURL, bowl.ID), env.Token, photo.Bytes(), http.StatusOK)
+
+	type linkOut struct {
+		ID          uint   `json:"id"`
+		Token       string `json:"token"`
+		AccessCount int64  `json:"access_count"`
+	}
+	var recipeLink linkOut
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/recipes/%d/share-links", env.BaseURL, bowl.ID), map[string]any{}, env.Token, http.StatusCreated, &recipeLink)
+	if len(recipeLink.Token) < 40 {
+		t.Fatalf("expected a long random token, got %q", recipeLink.Token)
+	}
+
+	// Anyone holding the token can open the link without signing in.
+	var view map[string]any
+	sharedURL := env.BaseURL + "/api/v1/public/shared/" + recipeLink.Token
+	doJSON(t, http.MethodGet, sharedURL, nil, http.StatusOK, &view)
+	if view["kind"] != "recipe" {
+		t.Fatalf("expected a shared recipe, got %v", view)
+	}
+	recipeView, _ := view["recipe"].(map[string]any)
+	if recipeView["name"] != "Rice bowl" {
+		t.Fatalf("expected the recipe name, got %v", recipeView)
+	}
+	// Neither the payload nor the image URLs lead back to the recipe, its
+	// foods or its owner.
+	assertNoIDs(t, view, "view")
+	assertImageKeyWithoutID(t, recipeView["image_url"], "/media/recipes/")
+	assertImageKeyWithoutID(t, recipeView["thumbnail_url"], "/media/recipes/")
+	nutrition, _ := view["nutrition"].(map[string]any)
+	total, _ := nutrition["total"].(map[string]any)
+	if kcal, _ := total["kcal"].(float64); math.Abs(kcal-260) > 1e-6 {
+		t.Fatalf("expected 260 kcal for the batch, got %v", total)
+	}
+	doJSON(t, http.MethodGet, sharedURL, nil, http.StatusOK, nil)
+
+	// Only the owner can share a recipe.
+	_, otherToken := env.newUser(t, "Other", "other-sharer@example.com", false)
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/recipes/%d/share-links", env.BaseURL, bowl.ID), map[string]any{}, otherToken, http.StatusForbidden, nil)
+
+	mealID := createMealWithFoodItem(t, env.BaseURL, rice, env.Token)
+	var mealLink linkOut
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/meals/%d/share-links", env.BaseURL, mealID), map[string]any{
+		"expires_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	}, env.Token, http.StatusCreated, &mealLink)
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/meals/%d/share-links", env.BaseURL, mealID), map[string]any{}, otherToken, http.StatusNotFound, nil)
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/meals/%d/share-links", env.BaseURL, mealID), map[string]any{
+		"expires_at": "2020-01-01T00:00:00Z",
+	}, env.Token, http.StatusBadRequest, nil)
+
+	var mealView map[string]any
+	doJSON(t, http.MethodGet, env.BaseURL+"/api/v1/public/shared/"+mealLink.Token, nil, http.StatusOK, &mealView)
+	if mealView["kind"] != "meal" {
+		t.Fatalf("expected a shared meal, got %v", mealView)
+	}
+	assertNoIDs(t, mealView, "view")
+
+	var links []linkOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/share-links", nil, env.Token, http.StatusOK, &links)
+	if len(links) != 2 || links[0].ID != mealLink.ID || links[1].AccessCount != 2 || links[1].Token != "" {
+		t.Fatalf("expected both links newest first with access counts and no tokens, got %+v", links)
+	}
+
+	// Revoked links stop opening; other users cannot revoke.
+	revokeURL := fmt.Sprintf("%s/api/v1/share-links/%d", env.BaseURL, recipeLink.ID)
+	doJSONWithToken(t, http.MethodDelete, revokeURL, nil, otherToken, http.StatusNotFound, nil)
+	doJSONWithToken(t, http.MethodDelete, revokeURL, nil, env.Token, http.StatusNoContent, nil)
+	doJSON(t, http.MethodGet, sharedURL, nil, http.StatusNotFound, nil)
+	doJSON(t, http.MethodGet, env.BaseURL+"/api/v1/public/shared/not-a-real-token", nil, http.StatusNotFound, nil)
+
+	// Deleting a recipe only archives it, and its links stop opening.
+	var liveLink linkOut
+	doJSONWithToken(t, http.MethodPost, fmt.Sprintf("%s/api/v1/recipes/%d/share-links", env.BaseURL, bowl.ID), map[string]any{}, env.Token, http.StatusCreated, &liveLink)
+	liveURL := env.BaseURL + "/api/v1/public/shared/" + liveLink.Token
+	doJSON(t, http.MethodGet, liveURL, nil, http.StatusOK, nil)
+	doJSONWithToken(t, http.MethodDelete, fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, bowl.ID), nil, env.Token, http.StatusNoContent, nil)
+	doJSON(t, http.MethodGet, liveURL, nil, http.StatusNotFound, nil)
+}
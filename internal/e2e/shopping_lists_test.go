@@ -0,0 +1,131 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestShoppingListsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	pasta := createFood(t, env.BaseURL, env.Token, "Spaghetti", 350, 12, 70, 1.5)
+	tomato := createFood(t, env.BaseURL, env.Token, "Tomato", 18, 0.9, 3.9, 0.2)
+
+	createRecipeFrom := func(payload map[string]any) uint {
+		var out struct {
+			ID uint `json:"id"`
+		}
+		doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/recipes", payload, env.Token, http.StatusCreated, &out)
+		return out.ID
+	}
+	sauce := createRecipeFrom(map[string]any{
+		"name":           "Tomato sauce",
+		"yield_weight_g": 400.0,
+		"ingredients":    []map[string]any{{"food_id": tomato, "raw_weight_g": 400.0}},
+	})
+	dinner := createRecipeFrom(map[string]any{
+		"name":           "Spaghetti al pomodoro",
+		"yield_weight_g": 400.0,
+		"servings":       2,
+		"ingredients": []map[string]any{
+			{"food_id": pasta, "raw_weight_g": 200.0},
+			{"sub_recipe_id": sauce, "raw_weight_g": 200.0},
+		},
+	})
+
+	type listOut struct {
+		ID    uint   `json:"id"`
+		Name  string `json:"name"`
+		Items []struct {
+			ID      uint    `json:"id"`
+			FoodID  uint    `json:"food_id"`
+			Name    string  `json:"name"`
+			WeightG float64 `json:"weight_g"`
+			Checked bool    `json:"checked"`
+		} `json:"items"`
+	}
+	weights := func(l listOut) map[uint]float64 {
+		out := map[uint]float64{}
+		for _, item := range l.Items {
+			out[item.FoodID] = item.WeightG
+		}
+		return out
+	}
+
+	// Four servings double the dinner; the extra sauce batch adds its tomatoes.
+	var fromRecipes listOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/shopping-lists", map[string]any{
+		"name":    "Dinner party",
+		"recipes": []map[string]any{{"recipe_id": dinner, "servings": 4}, {"recipe_id": sauce}},
+	}, env.Token, http.StatusCreated, &fromRecipes)
+	got := weights(fromRecipes)
+	if len(fromRecipes.Items) != 2 || got[pasta] != 400 || got[tomato] != 800 {
+		t.Fatalf("expected 400 g pasta and 800 g tomato, got %+v", fromRecipes)
+	}
+	if fromRecipes.Items[0].Name != "Spaghetti" {
+		t.Fatalf("expected food names on the items, got %+v", fromRecipes.Items)
+	}
+
+	listURL := fmt.Sprintf("%s/api/v1/shopping-lists/%d", env.BaseURL, fromRecipes.ID)
+	itemURL := fmt.Sprintf("%s/items/%d", listURL, fromRecipes.Items[0].ID)
+	doJSONWithToken(t, http.MethodPatch, itemURL, map[string]any{"checked": true}, env.Token, http.StatusOK, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL+"?format=text", nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+env.Token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("export shopping list: %v", err)
+	}
+	defer resp.Body.Close()
+	text, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	want := "Dinner party\n\n[x] Spaghetti — 400 g\n[ ] Tomato — 800 g\n"
+	if resp.StatusCode != http.StatusOK || string(text) != want {
+		t.Fatalf("expected text export %q, got %d %q", want, resp.StatusCode, text)
+	}
+
+	// The planned lunch has 150 g of pasta and 120 g of sauce.
+	mealID := createMealWithFoodItem(t, env.BaseURL, pasta, env.Token)
+	addMealItemRecipe(t, env.BaseURL, mealID, sauce, env.Token)
+	var fromMeals listOut
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/shopping-lists", map[string]any{
+		"from": "2026-02-17",
+		"to":   "2026-02-17",
+	}, env.Token, http.StatusCreated, &fromMeals)
+	got = weights(fromMeals)
+	if fromMeals.Name != "Shopping list" || got[pasta] != 150 || got[tomato] != 120 {
+		t.Fatalf("expected the planned meals' ingredients, got %+v", fromMeals)
+	}
+
+	var lists []listOut
+	doJSONWithToken(t, http.MethodGet, env.BaseURL+"/api/v1/shopping-lists", nil, env.Token, http.StatusOK, &lists)
+	if len(lists) != 2 || lists[0].ID != fromMeals.ID || len(lists[0].Items) != 0 {
+		t.Fatalf("expected both lists newest first without items, got %+v", lists)
+	}
+
+	_, otherToken := env.newUser(t, "Other", "other-shopper@example.com", false)
+	doJSONWithToken(t, http.MethodGet, listURL, nil, otherToken, http.StatusNotFound, nil)
+	doJSONWithToken(t, http.MethodPatch, itemURL, map[string]any{"checked": false}, otherToken, http.StatusNotFound, nil)
+
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/shopping-lists", map[string]any{
+		"from": "2026-01-01",
+		"to":   "2026-03-01",
+	}, env.Token, http.StatusBadRequest, nil)
+
+	doJSONWithToken(t, http.MethodDelete, listURL, nil, env.Token, http.StatusNoContent, nil)
+	doJSONWithToken(t, http.MethodGet, listURL, nil, env.Token, http.StatusNotFound, nil)
+}
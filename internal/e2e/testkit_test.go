@@ -17,11 +17,17 @@ import (
 	"time"
 
 	"goal-bite-api/internal/auth"
+	"goal-bite-api/internal/catalog"
 	"goal-bite-api/internal/db"
 	httpapi "goal-bite-api/internal/http"
 	"goal-bite-api/internal/http/handlers"
+	"goal-bite-api/internal/mail"
+	"goal-bite-api/internal/oidc"
+	"goal-bite-api/internal/oidc/oidctest"
 	"goal-bite-api/internal/repository"
 	"goal-bite-api/internal/service"
+	"goal-bite-api/internal/storage"
+	"goal-bite-api/internal/webauthn"
 
 	"gorm.io/gorm"
 )
@@ -30,6 +36,10 @@ type testEnv struct {
 	BaseURL string
 	UserID  uint
 	Token   string
+	MailDir string
+	OIDC    *oidctest.Provider
+	db      *gorm.DB
+	jwt     *auth.JWTManager
 	close   func()
 }
 
@@ -47,6 +57,17 @@ func (c testDBReadinessChecker) Ready(ctx context.Context) error {
 
 const testJWTSecret = "e2e-test-secret"
 
+const (
+	testOIDCClientID     = "goal-bite-e2e"
+	testOIDCClientSecret = "e2e-client-secret"
+)
+
+var testWebAuthnConfig = webauthn.Config{
+	RPID:    "localhost",
+	RPName:  "Goal Bite",
+	Origins: []string{"http://localhost:3000"},
+}
+
 func setupTestEnv(t *testing.T) testEnv {
 	t.Helper()
 	testDatabaseURL := os.Getenv("TEST_DATABASE_URL")
@@ -61,21 +82,34 @@ func setupTestEnv(t *testing.T) testEnv {
 	database := openTestDB(t, testDatabaseURL)
 	migrateUp(t, testDatabaseURL)
 	truncateAll(t, database)
-	userID := createUser(t, database, "E2E User")
+	userID := createUser(t, database, "E2E User", "e2e@example.com")
+	sessionID := createSession(t, database, userID)
 	jwtManager := auth.NewJWTManager(testJWTSecret)
-	token, err := jwtManager.Generate(userID)
+	token, err := jwtManager.Generate(userID, sessionID)
 	if err != nil {
 		t.Fatalf("generate jwt: %v", err)
 	}
 
-	router := buildRouter(database, jwtManager)
+	mailDir := t.TempDir()
+	idp, err := oidctest.New(testOIDCClientID, testOIDCClientSecret)
+	if err != nil {
+		t.Fatalf("start fake oidc provider: %v", err)
+	}
+	router := buildRouter(t, database, jwtManager, mailDir, idp)
 	server := httptest.NewServer(router)
 
 	return testEnv{
 		BaseURL: server.URL,
 		UserID:  userID,
 		Token:   token,
-		close:   server.Close,
+		MailDir: mailDir,
+		OIDC:    idp,
+		db:      database,
+		jwt:     jwtManager,
+		close: func() {
+			server.Close()
+			idp.Close()
+		},
 	}
 }
 
@@ -110,7 +144,16 @@ func truncateAll(t *testing.T, database *gorm.DB) {
 	sql := `
 TRUNCATE TABLE
 	auth_sessions,
+	barcode_lookup_misses,
+	magic_link_tokens,
+	passkeys,
+	webauthn_challenges,
+	oidc_identities,
+	oidc_login_states,
 	body_weight_logs,
+	share_links,
+	shopping_list_items,
+	shopping_lists,
 	meal_items,
 	meals,
 	recipe_ingredients,
@@ -125,33 +168,120 @@ RESTART IDENTITY CASCADE;
 	}
 }
 
-func createUser(t *testing.T, database *gorm.DB, name string) uint {
+func createUser(t *testing.T, database *gorm.DB, name, email string) uint {
 	t.Helper()
 	var id uint
-	row := database.Raw(`INSERT INTO users (name, email, password_hash) VALUES (?, ?, ?) RETURNING id`, name, "e2e@example.com", "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy").Row()
+	row := database.Raw(`INSERT INTO users (name, email, password_hash) VALUES (?, ?, ?) RETURNING id`, name, email, "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy").Row()
 	if err := row.Scan(&id); err != nil {
 		t.Fatalf("insert user: %v", err)
 	}
 	return id
 }
 
-func buildRouter(database *gorm.DB, jwtManager *auth.JWTManager) http.Handler {
+// newUser adds another signed-in user to env and returns its ID and access
+// token. Admins can verify foods.
+func (e testEnv) newUser(t *testing.T, name, email string, admin bool) (uint, string) {
+	t.Helper()
+	userID := createUser(t, e.db, name, email)
+	if admin {
+		if err := e.db.Exec(`UPDATE users SET is_admin = TRUE WHERE id = ?`, userID).Error; err != nil {
+			t.Fatalf("promote user to admin: %v", err)
+		}
+	}
+	token, err := e.jwt.Generate(userID, createSession(t, e.db, userID))
+	if err != nil {
+		t.Fatalf("generate jwt: %v", err)
+	}
+	return userID, token
+}
+
+func createSession(t *testing.T, database *gorm.DB, userID uint) uint {
+	t.Helper()
+	session, err := repository.NewAuthSessionRepository(database).Create(context.Background(), repository.CreateAuthSessionInput{
+		UserID:    userID,
+		TokenHash: fmt.Sprintf("e2e-session-%d", time.Now().UnixNano()),
+		ExpiresAt: time.Now().UTC().Add(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("insert auth session: %v", err)
+	}
+	return session.ID
+}
+
+func buildRouter(t *testing.T, database *gorm.DB, jwtManager *auth.JWTManager, mailDir string, idp *oidctest.Provider) http.Handler {
+	t.Helper()
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	userRepository := repository.NewUserRepository(database)
 	userService := service.NewUserService(userRepository)
 	authSessionRepository := repository.NewAuthSessionRepository(database)
 	authService := service.NewAuthService(userRepository, jwtManager, authSessionRepository)
+	magicLinkService := service.NewMagicLinkService(
+		userRepository,
+		repository.NewMagicLinkTokenRepository(database),
+		authSessionRepository,
+		jwtManager,
+		mail.NewFileMailer(mailDir),
+		service.MagicLinkConfig{BaseURL: "http://localhost:3000/auth/magic-link", TTL: 15 * time.Minute},
+	)
+	passkeyService := service.NewPasskeyService(
+		userRepository,
+		repository.NewPasskeyRepository(database),
+		repository.NewWebAuthnChallengeRepository(database),
+		authSessionRepository,
+		jwtManager,
+		testWebAuthnConfig,
+	)
+	oidcService := service.NewOIDCService(
+		userRepository,
+		repository.NewOIDCIdentityRepository(database),
+		repository.NewOIDCLoginStateRepository(database),
+		authSessionRepository,
+		jwtManager,
+		oidc.NewProvider(oidc.ProviderConfig{
+			Name:         "acme",
+			Issuer:       idp.Issuer(),
+			ClientID:     testOIDCClientID,
+			ClientSecret: testOIDCClientSecret,
+			RedirectURL:  "http://localhost:3000/auth/oidc/acme/callback",
+		}, nil),
+	)
 	foodRepository := repository.NewFoodRepository(database)
-	foodService := service.NewFoodService(foodRepository)
 	recipeRepository := repository.NewRecipeRepository(database)
+	cookingRepository := repository.NewCookingRepository(database)
+	// Image URLs stay relative to the test server, which serves them below /media.
+	media := storage.NewLocalStore(t.TempDir(), "/media")
+	images := service.ImageUploads{Store: media}
 	recipeService := service.NewRecipeService(recipeRepository, foodRepository)
+	recipeService.SetRestrictionReader(userRepository)
+	recipeService.SetImageUploads(images)
+	recipeService.SetCookingFactors(cookingRepository)
+	products, err := catalog.NewFileProvider(filepath.Join("testdata", "barcode_products.json"))
+	if err != nil {
+		t.Fatalf("open barcode product fixture: %v", err)
+	}
+	foodService := service.NewFoodService(foodRepository)
+	foodService.SetAdminReader(userRepository)
+	foodService.SetRestrictionReader(userRepository)
+	foodService.SetRecipeRecalculator(recipeService)
+	foodService.SetBarcodeLookup(service.BarcodeLookup{
+		Provider: products,
+		Misses:   repository.NewBarcodeLookupMissRepository(database),
+		MissTTL:  time.Hour,
+	})
+	foodService.SetImageUploads(images)
+	foodService.SetCookingFactors(cookingRepository)
 	mealRepository := repository.NewMealRepository(database)
 	mealService := service.NewMealService(mealRepository, foodRepository, recipeRepository)
+	mealService.SetRestrictionReader(userRepository)
+	mealService.SetImageUploads(images)
+	mealService.SetCookingFactors(cookingRepository)
 	bodyWeightLogRepository := repository.NewBodyWeightLogRepository(database)
 	bodyWeightLogService := service.NewBodyWeightLogService(bodyWeightLogRepository)
 	userGoalRepository := repository.NewUserGoalRepository(database)
 	userGoalService := service.NewUserGoalService(userGoalRepository, mealRepository)
 	energyService := service.NewEnergyService(userRepository, bodyWeightLogRepository, mealRepository)
+	shoppingListService := service.NewShoppingListService(repository.NewShoppingListRepository(database), recipeRepository, foodRepository, mealRepository)
+	shareLinkService := service.NewShareLinkService(repository.NewShareLinkRepository(database), recipeService, mealService)
 	handler := handlers.New(
 		userService,
 		authService,
@@ -162,8 +292,15 @@ func buildRouter(database *gorm.DB, jwtManager *auth.JWTManager) http.Handler {
 		userGoalService,
 		energyService,
 		testDBReadinessChecker{db: database},
+		magicLinkService,
+		passkeyService,
+		oidcService,
+		shoppingListService,
+		shareLinkService,
 	)
-	return httpapi.NewRouter(handler, logger, jwtManager)
+	// No session cache so revocation is observable on the next request.
+	sessionChecker := service.NewCachedSessionChecker(authSessionRepository, 0)
+	return httpapi.NewRouter(handler, logger, jwtManager, sessionChecker, media)
 }
 
 func createFood(t *testing.T, baseURL, token, name string, kcal, protein, carbs, fat float64) uint {
@@ -0,0 +1,112 @@
+//go:build integration
+
+package e2e_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type localizedName struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+func getLocalized(t *testing.T, target, token, acceptLanguage string, out any) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("execute request: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200 got %d body=%s", resp.StatusCode, string(raw))
+	}
+	if resp.Header.Get("Vary") != "Accept-Language" {
+		t.Fatalf("expected Vary: Accept-Language, got %q", resp.Header.Get("Vary"))
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		t.Fatalf("decode response: %v body=%s", err, string(raw))
+	}
+}
+
+func TestTranslationsE2E(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.Close()
+
+	payload := map[string]any{
+		"name": "Apple", "kcal_per_100g": 52.0, "protein_per_100g": 0.3, "carbs_per_100g": 14.0, "fat_per_100g": 0.2,
+		"translations": []map[string]any{
+			{"locale": "de-DE", "name": "Apfel", "synonyms": []string{"Äpfel"}},
+			{"locale": "hr", "name": "Jabuka"},
+		},
+	}
+	var apple localizedName
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", payload, env.Token, http.StatusCreated, &apple)
+
+	invalid := map[string]any{
+		"name": "Pear", "kcal_per_100g": 57.0, "protein_per_100g": 0.4, "carbs_per_100g": 15.0, "fat_per_100g": 0.1,
+		"translations": []map[string]any{{"locale": "de", "name": "Birne"}, {"locale": "DE", "name": "Birne"}},
+	}
+	doJSONWithToken(t, http.MethodPost, env.BaseURL+"/api/v1/foods", invalid, env.Token, http.StatusBadRequest, nil)
+
+	foodURL := fmt.Sprintf("%s/api/v1/foods/%d", env.BaseURL, apple.ID)
+	var fetched localizedName
+	getLocalized(t, foodURL, env.Token, "", &fetched)
+	if fetched.DisplayName != "Apple" {
+		t.Fatalf("expected base name without languages, got %+v", fetched)
+	}
+	getLocalized(t, foodURL, env.Token, "fr;q=0.9, hr;q=0.5", &fetched)
+	if fetched.DisplayName != "Jabuka" {
+		t.Fatalf("expected croatian name, got %+v", fetched)
+	}
+
+	// Saved languages win over the ones the client accepts.
+	doJSONWithToken(t, http.MethodPatch, env.BaseURL+"/api/v1/users/me", map[string]any{"languages": []string{"de"}}, env.Token, http.StatusOK, nil)
+	getLocalized(t, foodURL, env.Token, "hr", &fetched)
+	if fetched.DisplayName != "Apfel" {
+		t.Fatalf("expected german name, got %+v", fetched)
+	}
+
+	var found []localizedName
+	getLocalized(t, env.BaseURL+"/api/v1/foods?q="+url.QueryEscape("äpfel"), env.Token, "", &found)
+	if len(found) != 1 || found[0].ID != apple.ID {
+		t.Fatalf("expected synonym search to find the apple, got %+v", found)
+	}
+	getLocalized(t, env.BaseURL+"/api/v1/foods?q=jabuk", env.Token, "", &found)
+	if len(found) != 0 {
+		t.Fatalf("expected languages outside the caller's to be ignored, got %+v", found)
+	}
+	getLocalized(t, env.BaseURL+"/api/v1/foods?q=jabuk", env.Token, "hr", &found)
+	if len(found) != 1 {
+		t.Fatalf("expected accepted language to be searched, got %+v", found)
+	}
+
+	recipeID := createRecipe(t, env.BaseURL, env.Token, apple.ID)
+	recipeURL := fmt.Sprintf("%s/api/v1/recipes/%d", env.BaseURL, recipeID)
+	update := map[string]any{"translations": []map[string]any{{"locale": "de", "name": "Apfelschale"}}}
+	doJSONWithToken(t, http.MethodPatch, recipeURL, update, env.Token, http.StatusOK, nil)
+	var recipeOut localizedName
+	getLocalized(t, recipeURL, env.Token, "", &recipeOut)
+	if recipeOut.DisplayName != "Apfelschale" || recipeOut.Name != "Rice Bowl" {
+		t.Fatalf("unexpected recipe names %+v", recipeOut)
+	}
+	getLocalized(t, env.BaseURL+"/api/v1/recipes?q=apfelschal", env.Token, "", &found)
+	if len(found) != 1 || found[0].ID != recipeID {
+		t.Fatalf("expected translated recipe name search, got %+v", found)
+	}
+}
@@ -13,6 +13,7 @@ var (
 	ErrInvalidEmail        = errors.New("invalid email")
 	ErrInvalidPassword     = errors.New("invalid password")
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrInvalidMagicLink    = errors.New("invalid magic link token")
 )
 
 type RegisterRequest struct {
@@ -106,3 +107,25 @@ func (r *RefreshTokenRequest) Validate() error {
 	}
 	return nil
 }
+
+type MagicLinkRequest struct {
+	Email string `json:"email" example:"john@gmail.com"`
+}
+
+func (r *MagicLinkRequest) Validate() error {
+	if strings.TrimSpace(r.Email) == "" {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+type MagicLinkConsumeRequest struct {
+	Token string `json:"token" example:"Qm9uYXBwZXRpdA..."`
+}
+
+func (r *MagicLinkConsumeRequest) Validate() error {
+	if strings.TrimSpace(r.Token) == "" {
+		return ErrInvalidMagicLink
+	}
+	return nil
+}
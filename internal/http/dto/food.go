@@ -4,6 +4,8 @@ import (
 	"errors"
 	"strings"
 
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/service"
 )
 
@@ -11,6 +13,7 @@ var (
 	ErrInvalidName      = errors.New("invalid name")
 	ErrInvalidNutrition = errors.New("invalid nutrition values")
 	ErrNoFieldsToUpdate = errors.New("no fields to update")
+	ErrInvalidFoodMerge = errors.New("invalid food merge")
 )
 
 type CreateFoodRequest struct {
@@ -28,19 +31,37 @@ type CreateFoodRequest struct {
 	CarbsPer100g float64 `json:"carbs_per_100g" example:"28"`
 	// Fat grams per 100g.
 	FatPer100g float64 `json:"fat_per_100g" example:"0.3"`
+	// Optional alcohol grams per 100g; defaults to 0.
+	AlcoholPer100g float64 `json:"alcohol_per_100g,omitempty" example:"0"`
+	// Optional visibility; defaults to private. Only admins may create verified foods.
+	Visibility *string `json:"visibility,omitempty" enums:"private,public,verified" example:"public"`
+	// Optional category ID from GET /food-categories.
+	CategoryID *uint `json:"category_id,omitempty" example:"16"`
+	// Optional free-form tags; stored lowercased without duplicates.
+	Tags []string `json:"tags,omitempty" example:"breakfast,high protein"`
+	// Optional EU major allergens the food contains.
+	Allergens []string `json:"allergens,omitempty" example:"milk"`
+	// Optional diet flags; vegan implies vegetarian.
+	DietFlags []string `json:"diet_flags,omitempty" example:"vegetarian,gluten_free"`
+	// Optional names in other languages, with synonyms search also matches.
+	Translations []locale.Translation `json:"translations,omitempty"`
 }
 
 func (r *CreateFoodRequest) Validate() error {
 	if strings.TrimSpace(r.Name) == "" {
 		return ErrInvalidName
 	}
-	if r.KcalPer100g < 0 || r.ProteinPer100g < 0 || r.CarbsPer100g < 0 || r.FatPer100g < 0 {
+	if r.KcalPer100g < 0 || r.ProteinPer100g < 0 || r.CarbsPer100g < 0 || r.FatPer100g < 0 || r.AlcoholPer100g < 0 {
 		return ErrInvalidNutrition
 	}
 	return nil
 }
 
 func (r *CreateFoodRequest) ToServiceInput() service.CreateFoodInput {
+	var visibility food.Visibility
+	if v := visibilityPtr(r.Visibility); v != nil {
+		visibility = *v
+	}
 	return service.CreateFoodInput{
 		Name:           r.Name,
 		BrandName:      r.BrandName,
@@ -49,6 +70,13 @@ func (r *CreateFoodRequest) ToServiceInput() service.CreateFoodInput {
 		ProteinPer100g: r.ProteinPer100g,
 		CarbsPer100g:   r.CarbsPer100g,
 		FatPer100g:     r.FatPer100g,
+		AlcoholPer100g: r.AlcoholPer100g,
+		Visibility:     visibility,
+		CategoryID:     r.CategoryID,
+		Tags:           r.Tags,
+		Allergens:      r.Allergens,
+		DietFlags:      r.DietFlags,
+		Translations:   r.Translations,
 	}
 }
 
@@ -67,10 +95,24 @@ type UpdateFoodRequest struct {
 	CarbsPer100g *float64 `json:"carbs_per_100g" example:"28"`
 	// Optional fat grams per 100g.
 	FatPer100g *float64 `json:"fat_per_100g" example:"0.3"`
+	// Optional alcohol grams per 100g.
+	AlcoholPer100g *float64 `json:"alcohol_per_100g,omitempty" example:"0"`
+	// Optional visibility. Only admins may set verified.
+	Visibility *string `json:"visibility,omitempty" enums:"private,public,verified" example:"public"`
+	// Optional category ID; 0 removes the food from its category.
+	CategoryID *uint `json:"category_id,omitempty" example:"16"`
+	// Optional tags replacing the current ones; an empty list removes all tags.
+	Tags *[]string `json:"tags,omitempty" example:"breakfast,high protein"`
+	// Optional allergens replacing the current ones.
+	Allergens *[]string `json:"allergens,omitempty" example:"milk"`
+	// Optional diet flags replacing the current ones.
+	DietFlags *[]string `json:"diet_flags,omitempty" example:"vegetarian,gluten_free"`
+	// Optional translations replacing the current ones; an empty list removes them.
+	Translations *[]locale.Translation `json:"translations,omitempty"`
 }
 
 func (r *UpdateFoodRequest) Validate() error {
-	if r.Name == nil && r.BrandName == nil && r.Barcode == nil && r.KcalPer100g == nil && r.ProteinPer100g == nil && r.CarbsPer100g == nil && r.FatPer100g == nil {
+	if r.Name == nil && r.BrandName == nil && r.Barcode == nil && r.KcalPer100g == nil && r.ProteinPer100g == nil && r.CarbsPer100g == nil && r.FatPer100g == nil && r.AlcoholPer100g == nil && r.Visibility == nil && r.CategoryID == nil && r.Tags == nil && r.Allergens == nil && r.DietFlags == nil && r.Translations == nil {
 		return ErrNoFieldsToUpdate
 	}
 	if r.Name != nil && strings.TrimSpace(*r.Name) == "" {
@@ -79,7 +121,8 @@ func (r *UpdateFoodRequest) Validate() error {
 	if (r.KcalPer100g != nil && *r.KcalPer100g < 0) ||
 		(r.ProteinPer100g != nil && *r.ProteinPer100g < 0) ||
 		(r.CarbsPer100g != nil && *r.CarbsPer100g < 0) ||
-		(r.FatPer100g != nil && *r.FatPer100g < 0) {
+		(r.FatPer100g != nil && *r.FatPer100g < 0) ||
+		(r.AlcoholPer100g != nil && *r.AlcoholPer100g < 0) {
 		return ErrInvalidNutrition
 	}
 	return nil
@@ -94,5 +137,37 @@ func (r *UpdateFoodRequest) ToServiceInput() service.UpdateFoodInput {
 		ProteinPer100g: r.ProteinPer100g,
 		CarbsPer100g:   r.CarbsPer100g,
 		FatPer100g:     r.FatPer100g,
+		AlcoholPer100g: r.AlcoholPer100g,
+		Visibility:     visibilityPtr(r.Visibility),
+		CategoryID:     r.CategoryID,
+		Tags:           r.Tags,
+		Allergens:      r.Allergens,
+		DietFlags:      r.DietFlags,
+		Translations:   r.Translations,
+	}
+}
+
+type MergeFoodsRequest struct {
+	// Foods folded into the food in the path. They are archived after the merge.
+	FoodIDs []uint `json:"food_ids" example:"12,15"`
+}
+
+func (r *MergeFoodsRequest) Validate() error {
+	if len(r.FoodIDs) == 0 {
+		return ErrInvalidFoodMerge
+	}
+	for _, id := range r.FoodIDs {
+		if id == 0 {
+			return ErrInvalidFoodMerge
+		}
+	}
+	return nil
+}
+
+func visibilityPtr(value *string) *food.Visibility {
+	if value == nil {
+		return nil
 	}
+	v := food.Visibility(strings.TrimSpace(*value))
+	return &v
 }
@@ -9,14 +9,16 @@ import (
 )
 
 var (
-	ErrInvalidUserID     = errors.New("invalid user id")
-	ErrInvalidPagination = errors.New("invalid pagination")
-	ErrInvalidDateRange  = errors.New("invalid date range")
-	ErrInvalidMealType   = errors.New("invalid meal type")
-	ErrInvalidEatenAt    = errors.New("invalid eaten_at")
-	ErrInvalidDate       = errors.New("invalid date")
-	ErrInvalidSourceXOR  = errors.New("invalid source xor")
-	ErrInvalidItemWeight = errors.New("invalid item weight")
+	ErrInvalidUserID       = errors.New("invalid user id")
+	ErrInvalidPagination   = errors.New("invalid pagination")
+	ErrInvalidDateRange    = errors.New("invalid date range")
+	ErrInvalidMealType     = errors.New("invalid meal type")
+	ErrInvalidEatenAt      = errors.New("invalid eaten_at")
+	ErrInvalidDate         = errors.New("invalid date")
+	ErrInvalidSourceXOR    = errors.New("invalid source xor")
+	ErrInvalidItemWeight   = errors.New("invalid item weight")
+	ErrInvalidItemServings = errors.New("invalid item servings")
+	ErrInvalidCookedWeight = errors.New("invalid cooked weight")
 )
 
 type CreateMealRequest struct {
@@ -87,8 +89,14 @@ type AddMealItemRequest struct {
 	FoodID *uint `json:"food_id" example:"1"`
 	// Recipe source ID (mutually exclusive with food_id).
 	RecipeID *uint `json:"recipe_id" example:"1"`
-	// Item consumed weight in grams.
-	WeightG float64 `json:"weight_g" example:"150"`
+	// Item consumed weight in grams (mutually exclusive with servings and cooked_weight_g).
+	WeightG float64 `json:"weight_g,omitempty" example:"150"`
+	// Consumed servings of the recipe, converted to grams through its yield.
+	Servings *float64 `json:"servings,omitempty" example:"1.5"`
+	// Consumed weight of the food after cooking, converted back to its raw weight; requires cooking_method_id.
+	CookedWeightG *float64 `json:"cooked_weight_g,omitempty" example:"220"`
+	// Cooking method the food was cooked by.
+	CookingMethodID *uint `json:"cooking_method_id,omitempty" example:"1"`
 }
 
 func (r *AddMealItemRequest) Validate() error {
@@ -97,6 +105,18 @@ func (r *AddMealItemRequest) Validate() error {
 	if foodSet == recipeSet {
 		return ErrInvalidSourceXOR
 	}
+	if r.CookedWeightG != nil || r.CookingMethodID != nil {
+		if !foodSet || r.CookedWeightG == nil || *r.CookedWeightG <= 0 || r.CookingMethodID == nil || r.WeightG != 0 || r.Servings != nil {
+			return ErrInvalidCookedWeight
+		}
+		return nil
+	}
+	if r.Servings != nil {
+		if !recipeSet || r.WeightG != 0 || *r.Servings <= 0 {
+			return ErrInvalidItemServings
+		}
+		return nil
+	}
 	if r.WeightG <= 0 {
 		return ErrInvalidItemWeight
 	}
@@ -104,7 +124,14 @@ func (r *AddMealItemRequest) Validate() error {
 }
 
 func (r *AddMealItemRequest) ToServiceInput() service.AddMealItemInput {
-	return service.AddMealItemInput{FoodID: r.FoodID, RecipeID: r.RecipeID, WeightG: r.WeightG}
+	return service.AddMealItemInput{
+		FoodID:          r.FoodID,
+		RecipeID:        r.RecipeID,
+		WeightG:         r.WeightG,
+		Servings:        r.Servings,
+		CookedWeightG:   r.CookedWeightG,
+		CookingMethodID: r.CookingMethodID,
+	}
 }
 
 type UpdateMealRequest struct {
@@ -151,12 +178,19 @@ type UpdateMealItemRequest struct {
 	FoodID *uint `json:"food_id,omitempty" example:"1"`
 	// Optional recipe source ID (mutually exclusive with food_id).
 	RecipeID *uint `json:"recipe_id,omitempty" example:"1"`
-	// Optional consumed weight in grams.
+	// Optional consumed weight in grams (mutually exclusive with servings).
 	WeightG *float64 `json:"weight_g,omitempty" example:"180"`
+	// Optional consumed servings of the item's recipe.
+	Servings *float64 `json:"servings,omitempty" example:"2"`
+	// Optional consumed weight of the item's food after cooking.
+	CookedWeightG *float64 `json:"cooked_weight_g,omitempty" example:"250"`
+	// Optional cooking method the item's food was cooked by.
+	CookingMethodID *uint `json:"cooking_method_id,omitempty" example:"1"`
 }
 
 func (r *UpdateMealItemRequest) Validate() error {
-	if r.FoodID == nil && r.RecipeID == nil && r.WeightG == nil {
+	cooked := r.CookedWeightG != nil || r.CookingMethodID != nil
+	if r.FoodID == nil && r.RecipeID == nil && r.WeightG == nil && r.Servings == nil && !cooked {
 		return service.ErrNoFieldsToUpdate
 	}
 	if r.FoodID != nil && r.RecipeID != nil {
@@ -165,13 +199,22 @@ func (r *UpdateMealItemRequest) Validate() error {
 	if r.WeightG != nil && *r.WeightG <= 0 {
 		return ErrInvalidItemWeight
 	}
+	if r.Servings != nil && (*r.Servings <= 0 || r.WeightG != nil || r.FoodID != nil) {
+		return ErrInvalidItemServings
+	}
+	if cooked && (r.RecipeID != nil || r.WeightG != nil || r.Servings != nil || (r.CookedWeightG != nil && *r.CookedWeightG <= 0)) {
+		return ErrInvalidCookedWeight
+	}
 	return nil
 }
 
 func (r *UpdateMealItemRequest) ToServiceInput() service.UpdateMealItemInput {
 	return service.UpdateMealItemInput{
-		FoodID:   r.FoodID,
-		RecipeID: r.RecipeID,
-		WeightG:  r.WeightG,
+		FoodID:          r.FoodID,
+		RecipeID:        r.RecipeID,
+		WeightG:         r.WeightG,
+		Servings:        r.Servings,
+		CookedWeightG:   r.CookedWeightG,
+		CookingMethodID: r.CookingMethodID,
 	}
 }
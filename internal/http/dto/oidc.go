@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidOIDCCallback = errors.New("invalid oidc callback")
+
+type OIDCFinishRequest struct {
+	// `code` query parameter the provider sent to the redirect URL.
+	Code string `json:"code" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	// `state` query parameter the provider sent to the redirect URL.
+	State string `json:"state" example:"af0ifjsldkj"`
+}
+
+func (r *OIDCFinishRequest) Validate() error {
+	if strings.TrimSpace(r.Code) == "" || strings.TrimSpace(r.State) == "" {
+		return ErrInvalidOIDCCallback
+	}
+	return nil
+}
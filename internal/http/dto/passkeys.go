@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+
+	"goal-bite-api/internal/service"
+	"goal-bite-api/internal/webauthn"
+)
+
+var (
+	ErrInvalidPasskeyName       = errors.New("invalid passkey name")
+	ErrInvalidPasskeyCredential = errors.New("invalid passkey credential")
+)
+
+type PasskeyRegistrationRequest struct {
+	// Label shown in the passkey list.
+	Name string `json:"name" example:"MacBook Touch ID"`
+	// PublicKeyCredential.toJSON() result of navigator.credentials.create().
+	Credential webauthn.RegistrationResponse `json:"credential"`
+}
+
+func (r *PasskeyRegistrationRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return ErrInvalidPasskeyName
+	}
+	if strings.TrimSpace(r.Credential.RawID) == "" || r.Credential.Response.AttestationObject == "" || r.Credential.Response.ClientDataJSON == "" {
+		return ErrInvalidPasskeyCredential
+	}
+	return nil
+}
+
+func (r *PasskeyRegistrationRequest) ToServiceInput() service.FinishPasskeyRegistrationInput {
+	return service.FinishPasskeyRegistrationInput{
+		Name:       strings.TrimSpace(r.Name),
+		Credential: r.Credential,
+	}
+}
+
+type PasskeyLoginRequest struct {
+	// PublicKeyCredential.toJSON() result of navigator.credentials.get().
+	Credential webauthn.AssertionResponse `json:"credential"`
+}
+
+func (r *PasskeyLoginRequest) Validate() error {
+	c := r.Credential
+	if strings.TrimSpace(c.RawID) == "" || c.Response.ClientDataJSON == "" || c.Response.AuthenticatorData == "" || c.Response.Signature == "" {
+		return ErrInvalidPasskeyCredential
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"strings"
 
+	"goal-bite-api/internal/domain/locale"
+	"goal-bite-api/internal/domain/recipe"
 	"goal-bite-api/internal/service"
 )
 
@@ -11,38 +13,79 @@ var (
 	ErrInvalidRecipeName        = errors.New("invalid recipe name")
 	ErrInvalidRecipeYieldWeight = errors.New("invalid recipe yield weight")
 	ErrInvalidRecipeIngredients = errors.New("invalid recipe ingredients")
+	ErrInvalidRecipeServings    = errors.New("invalid recipe servings")
+	ErrInvalidRecipeTime        = errors.New("invalid recipe time")
+	ErrInvalidRecipeImport      = errors.New("invalid recipe import")
 )
 
+// MaxRecipeImportBytes caps the content of a recipe import, which may be a
+// whole web page.
+const MaxRecipeImportBytes = 1 << 20
+
+// RecipeIngredientRequest names either a food or another recipe as the
+// ingredient source.
 type RecipeIngredientRequest struct {
 	// Existing food ID used as ingredient source.
-	FoodID uint `json:"food_id" example:"1"`
+	FoodID uint `json:"food_id,omitempty" example:"1"`
+	// Existing recipe ID used as ingredient source instead of a food.
+	SubRecipeID uint `json:"sub_recipe_id,omitempty" example:"2"`
 	// Raw ingredient weight in grams.
 	RawWeightG float64 `json:"raw_weight_g" example:"200"`
 	// Optional ordering position.
 	Position *int `json:"position,omitempty" example:"1"`
+	// Optional preparation note, up to 200 characters.
+	Note string `json:"note,omitempty" example:"diced"`
+	// Optional cooking method the food is cooked by; not for sub-recipes.
+	CookingMethodID uint `json:"cooking_method_id,omitempty" example:"1"`
+}
+
+func (r RecipeIngredientRequest) valid() bool {
+	return (r.FoodID == 0) != (r.SubRecipeID == 0) && r.RawWeightG > 0 && (r.CookingMethodID == 0 || r.SubRecipeID == 0)
 }
 
 type CreateRecipeRequest struct {
 	// Human-readable recipe name.
 	Name string `json:"name" example:"Rice Bowl"`
-	// Final cooked yield weight in grams.
-	YieldWeightG float64 `json:"yield_weight_g" example:"200"`
+	// Final cooked yield weight in grams; 0 or omitted estimates it from the ingredients and their cooking methods.
+	YieldWeightG float64 `json:"yield_weight_g,omitempty" example:"200"`
+	// Optional number of equal servings the yield makes; defaults to 1.
+	Servings int `json:"servings,omitempty" example:"2"`
+	// Optional name of one serving; defaults to "serving".
+	ServingName string `json:"serving_name,omitempty" example:"bowl"`
+	// Optional description, up to 2000 characters.
+	Description string `json:"description,omitempty" example:"Hearty Hungarian beef stew."`
+	// Optional preparation time in minutes, up to a week.
+	PrepTimeMin int `json:"prep_time_min,omitempty" example:"20"`
+	// Optional cooking time in minutes, up to a week.
+	CookTimeMin int `json:"cook_time_min,omitempty" example:"90"`
+	// Optional free-form tags; stored lowercased without duplicates.
+	Tags []string `json:"tags,omitempty" example:"dinner,hungarian"`
+	// Optional instruction steps in cooking order, up to 50.
+	Instructions []string `json:"instructions,omitempty" example:"Brown the beef.,Add paprika and stock and simmer for 90 minutes."`
 	// Ingredient list used for nutrition calculation.
 	Ingredients []RecipeIngredientRequest `json:"ingredients"`
+	// Optional names in other languages, with synonyms search also matches.
+	Translations []locale.Translation `json:"translations,omitempty"`
 }
 
 func (r *CreateRecipeRequest) Validate() error {
 	if strings.TrimSpace(r.Name) == "" {
 		return ErrInvalidRecipeName
 	}
-	if r.YieldWeightG <= 0 {
+	if r.YieldWeightG < 0 {
 		return ErrInvalidRecipeYieldWeight
 	}
+	if r.Servings < 0 || r.Servings > recipe.MaxServings {
+		return ErrInvalidRecipeServings
+	}
+	if !validRecipeTime(r.PrepTimeMin) || !validRecipeTime(r.CookTimeMin) {
+		return ErrInvalidRecipeTime
+	}
 	if len(r.Ingredients) == 0 {
 		return ErrInvalidRecipeIngredients
 	}
 	for _, item := range r.Ingredients {
-		if item.FoodID == 0 || item.RawWeightG <= 0 {
+		if !item.valid() {
 			return ErrInvalidRecipeIngredients
 		}
 	}
@@ -53,35 +96,65 @@ func (r *CreateRecipeRequest) ToServiceInput() service.CreateRecipeInput {
 	return service.CreateRecipeInput{
 		Name:         r.Name,
 		YieldWeightG: r.YieldWeightG,
+		Servings:     r.Servings,
+		ServingName:  r.ServingName,
+		Description:  r.Description,
+		PrepTimeMin:  r.PrepTimeMin,
+		CookTimeMin:  r.CookTimeMin,
+		Tags:         r.Tags,
+		Instructions: r.Instructions,
 		Ingredients:  toServiceRecipeIngredients(r.Ingredients),
+		Translations: r.Translations,
 	}
 }
 
 type UpdateRecipeRequest struct {
 	// Optional recipe name.
 	Name *string `json:"name" example:"Updated Rice Bowl"`
-	// Optional final cooked yield weight in grams.
+	// Optional final cooked yield weight in grams; 0 estimates it from the ingredients.
 	YieldWeightG *float64 `json:"yield_weight_g" example:"210"`
+	// Optional number of equal servings the yield makes.
+	Servings *int `json:"servings,omitempty" example:"3"`
+	// Optional serving name; an empty one resets it to "serving".
+	ServingName *string `json:"serving_name,omitempty" example:"bowl"`
+	// Optional description; an empty one removes it.
+	Description *string `json:"description,omitempty" example:"Hearty Hungarian beef stew."`
+	// Optional preparation time in minutes; 0 removes it.
+	PrepTimeMin *int `json:"prep_time_min,omitempty" example:"20"`
+	// Optional cooking time in minutes; 0 removes it.
+	CookTimeMin *int `json:"cook_time_min,omitempty" example:"90"`
+	// Optional tags replacing the current ones; an empty list removes them.
+	Tags *[]string `json:"tags,omitempty" example:"dinner,hungarian"`
+	// Optional instruction steps replacing the current ones; an empty list removes them.
+	Instructions *[]string `json:"instructions,omitempty" example:"Brown the beef.,Add paprika and stock and simmer for 90 minutes."`
 	// Optional full replacement of ingredient list.
 	Ingredients *[]RecipeIngredientRequest `json:"ingredients"`
+	// Optional translations replacing the current ones; an empty list removes them.
+	Translations *[]locale.Translation `json:"translations,omitempty"`
 }
 
 func (r *UpdateRecipeRequest) Validate() error {
-	if r.Name == nil && r.YieldWeightG == nil && r.Ingredients == nil {
+	if r.Name == nil && r.YieldWeightG == nil && r.Servings == nil && r.ServingName == nil && r.Description == nil && r.PrepTimeMin == nil && r.CookTimeMin == nil && r.Tags == nil && r.Instructions == nil && r.Ingredients == nil && r.Translations == nil {
 		return ErrNoFieldsToUpdate
 	}
 	if r.Name != nil && strings.TrimSpace(*r.Name) == "" {
 		return ErrInvalidRecipeName
 	}
-	if r.YieldWeightG != nil && *r.YieldWeightG <= 0 {
+	if r.YieldWeightG != nil && *r.YieldWeightG < 0 {
 		return ErrInvalidRecipeYieldWeight
 	}
+	if r.Servings != nil && (*r.Servings <= 0 || *r.Servings > recipe.MaxServings) {
+		return ErrInvalidRecipeServings
+	}
+	if (r.PrepTimeMin != nil && !validRecipeTime(*r.PrepTimeMin)) || (r.CookTimeMin != nil && !validRecipeTime(*r.CookTimeMin)) {
+		return ErrInvalidRecipeTime
+	}
 	if r.Ingredients != nil {
 		if len(*r.Ingredients) == 0 {
 			return ErrInvalidRecipeIngredients
 		}
 		for _, item := range *r.Ingredients {
-			if item.FoodID == 0 || item.RawWeightG <= 0 {
+			if !item.valid() {
 				return ErrInvalidRecipeIngredients
 			}
 		}
@@ -93,6 +166,14 @@ func (r *UpdateRecipeRequest) ToServiceInput() service.UpdateRecipeInput {
 	input := service.UpdateRecipeInput{
 		Name:         r.Name,
 		YieldWeightG: r.YieldWeightG,
+		Servings:     r.Servings,
+		ServingName:  r.ServingName,
+		Description:  r.Description,
+		PrepTimeMin:  r.PrepTimeMin,
+		CookTimeMin:  r.CookTimeMin,
+		Tags:         r.Tags,
+		Instructions: r.Instructions,
+		Translations: r.Translations,
 	}
 	if r.Ingredients != nil {
 		mapped := toServiceRecipeIngredients(*r.Ingredients)
@@ -101,13 +182,32 @@ func (r *UpdateRecipeRequest) ToServiceInput() service.UpdateRecipeInput {
 	return input
 }
 
+func validRecipeTime(minutes int) bool {
+	return minutes >= 0 && minutes <= recipe.MaxTimeMin
+}
+
+type ImportRecipeRequest struct {
+	// Web page HTML, schema.org Recipe JSON-LD, or an ingredient list with one ingredient per line.
+	Content string `json:"content" example:"200 g chicken breast\n1 cup rice\n2 tbsp olive oil"`
+}
+
+func (r *ImportRecipeRequest) Validate() error {
+	if strings.TrimSpace(r.Content) == "" || len(r.Content) > MaxRecipeImportBytes {
+		return ErrInvalidRecipeImport
+	}
+	return nil
+}
+
 func toServiceRecipeIngredients(items []RecipeIngredientRequest) []service.RecipeIngredientInput {
 	out := make([]service.RecipeIngredientInput, 0, len(items))
 	for _, item := range items {
 		out = append(out, service.RecipeIngredientInput{
-			FoodID:     item.FoodID,
-			RawWeightG: item.RawWeightG,
-			Position:   item.Position,
+			FoodID:          item.FoodID,
+			SubRecipeID:     item.SubRecipeID,
+			RawWeightG:      item.RawWeightG,
+			Position:        item.Position,
+			Note:            item.Note,
+			CookingMethodID: item.CookingMethodID,
 		})
 	}
 	return out
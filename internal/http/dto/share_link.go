@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"goal-bite-api/internal/service"
+)
+
+var (
+	ErrInvalidShareLinkExpiry = errors.New("invalid share link expiry")
+)
+
+type CreateShareLinkRequest struct {
+	// When the link stops opening, in RFC3339 UTC. The link never expires when omitted.
+	ExpiresAt string `json:"expires_at,omitempty" example:"2026-03-01T00:00:00Z"`
+}
+
+func (r *CreateShareLinkRequest) Validate() error {
+	if r.ExpiresAt == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, r.ExpiresAt); err != nil {
+		return ErrInvalidShareLinkExpiry
+	}
+	return nil
+}
+
+func (r *CreateShareLinkRequest) ToServiceInput() service.CreateShareLinkInput {
+	if r.ExpiresAt == "" {
+		return service.CreateShareLinkInput{}
+	}
+	expiresAt, err := time.Parse(time.RFC3339, r.ExpiresAt)
+	if err != nil {
+		return service.CreateShareLinkInput{}
+	}
+	expiresAt = expiresAt.UTC()
+	return service.CreateShareLinkInput{ExpiresAt: &expiresAt}
+}
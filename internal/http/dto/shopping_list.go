@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"goal-bite-api/internal/service"
+)
+
+var (
+	ErrInvalidShoppingListSource = errors.New("invalid shopping list source")
+	ErrMissingChecked            = errors.New("missing checked")
+)
+
+type CreateShoppingListRequest struct {
+	// Optional list name, "Shopping list" when omitted.
+	Name string `json:"name,omitempty" example:"Weekend cooking"`
+	// Recipes to shop for. Leave empty to use planned meals instead.
+	Recipes []ShoppingListRecipeRequest `json:"recipes,omitempty"`
+	// First day of planned meals to shop for (YYYY-MM-DD), with to.
+	From string `json:"from,omitempty" example:"2026-02-16"`
+	// Last day of planned meals to shop for (YYYY-MM-DD), included.
+	To string `json:"to,omitempty" example:"2026-02-22"`
+}
+
+type ShoppingListRecipeRequest struct {
+	RecipeID uint `json:"recipe_id" example:"1"`
+	// Servings to cook, one batch when omitted.
+	Servings float64 `json:"servings,omitempty" example:"6"`
+}
+
+func (r *CreateShoppingListRequest) Validate() error {
+	byRange := r.From != "" || r.To != ""
+	if byRange == (len(r.Recipes) > 0) {
+		return ErrInvalidShoppingListSource
+	}
+	if byRange {
+		if _, err := time.Parse("2006-01-02", r.From); err != nil {
+			return ErrInvalidDateRange
+		}
+		if _, err := time.Parse("2006-01-02", r.To); err != nil {
+			return ErrInvalidDateRange
+		}
+	}
+	for _, item := range r.Recipes {
+		if item.RecipeID == 0 || item.Servings < 0 {
+			return ErrInvalidShoppingListSource
+		}
+	}
+	return nil
+}
+
+func (r *CreateShoppingListRequest) ToServiceInput() service.CreateShoppingListInput {
+	recipes := make([]service.ShoppingListRecipeInput, 0, len(r.Recipes))
+	for _, item := range r.Recipes {
+		recipes = append(recipes, service.ShoppingListRecipeInput{RecipeID: item.RecipeID, Servings: item.Servings})
+	}
+	return service.CreateShoppingListInput{Name: r.Name, Recipes: recipes, From: r.From, To: r.To}
+}
+
+type UpdateShoppingListItemRequest struct {
+	// Whether the item was bought.
+	Checked *bool `json:"checked" example:"true"`
+}
+
+func (r *UpdateShoppingListItemRequest) Validate() error {
+	if r.Checked == nil {
+		return ErrMissingChecked
+	}
+	return nil
+}
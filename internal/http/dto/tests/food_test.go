@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/http/dto"
 )
 
@@ -24,6 +25,14 @@ func TestCreateFoodRequestValidate(t *testing.T) {
 		}
 	})
 
+	t.Run("negative alcohol", func(t *testing.T) {
+		req := dto.CreateFoodRequest{Name: "Wine", KcalPer100g: 83, AlcoholPer100g: -1}
+		err := req.Validate()
+		if !errors.Is(err, dto.ErrInvalidNutrition) {
+			t.Fatalf("expected ErrInvalidNutrition, got %v", err)
+		}
+	})
+
 	t.Run("valid", func(t *testing.T) {
 		req := dto.CreateFoodRequest{Name: "Rice", KcalPer100g: 130, ProteinPer100g: 2.7, CarbsPer100g: 28, FatPer100g: 0.3}
 		if err := req.Validate(); err != nil {
@@ -58,4 +67,46 @@ func TestUpdateFoodRequestValidate(t *testing.T) {
 			t.Fatalf("expected ErrInvalidNutrition, got %v", err)
 		}
 	})
+
+	t.Run("alcohol only", func(t *testing.T) {
+		alcohol := 10.3
+		req := dto.UpdateFoodRequest{AlcoholPer100g: &alcohol}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected valid request, got %v", err)
+		}
+	})
+
+	t.Run("allergens only", func(t *testing.T) {
+		allergens := []string{}
+		req := dto.UpdateFoodRequest{Allergens: &allergens}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected valid request, got %v", err)
+		}
+	})
+
+	t.Run("translations only", func(t *testing.T) {
+		translations := []locale.Translation{}
+		req := dto.UpdateFoodRequest{Translations: &translations}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected valid request, got %v", err)
+		}
+	})
+}
+
+func TestMergeFoodsRequestValidate(t *testing.T) {
+	for name, ids := range map[string][]uint{"empty": nil, "zero id": {3, 0}} {
+		t.Run(name, func(t *testing.T) {
+			req := dto.MergeFoodsRequest{FoodIDs: ids}
+			if err := req.Validate(); !errors.Is(err, dto.ErrInvalidFoodMerge) {
+				t.Fatalf("expected ErrInvalidFoodMerge, got %v", err)
+			}
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		req := dto.MergeFoodsRequest{FoodIDs: []uint{3, 4}}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected valid request, got %v", err)
+		}
+	})
 }
@@ -2,6 +2,7 @@ package dto_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"goal-bite-api/internal/http/dto"
@@ -21,6 +22,53 @@ func TestCreateRecipeRequestValidate(t *testing.T) {
 			t.Fatalf("expected ErrInvalidRecipeIngredients, got %v", err)
 		}
 	})
+
+	t.Run("ingredient must name one source", func(t *testing.T) {
+		req := dto.CreateRecipeRequest{Name: "Burrito bowl", YieldWeightG: 500, Ingredients: []dto.RecipeIngredientRequest{{FoodID: 1, SubRecipeID: 2, RawWeightG: 100}}}
+		if err := req.Validate(); !errors.Is(err, dto.ErrInvalidRecipeIngredients) {
+			t.Fatalf("expected ErrInvalidRecipeIngredients, got %v", err)
+		}
+		req.Ingredients[0].FoodID = 0
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected sub-recipe ingredient to be valid, got %v", err)
+		}
+	})
+
+	t.Run("invalid servings", func(t *testing.T) {
+		req := dto.CreateRecipeRequest{Name: "Chili", YieldWeightG: 1600, Servings: -1, Ingredients: []dto.RecipeIngredientRequest{{FoodID: 1, RawWeightG: 100}}}
+		if err := req.Validate(); !errors.Is(err, dto.ErrInvalidRecipeServings) {
+			t.Fatalf("expected ErrInvalidRecipeServings, got %v", err)
+		}
+		req.Servings = 0
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected missing servings to default, got %v", err)
+		}
+	})
+
+	t.Run("estimated yield", func(t *testing.T) {
+		req := dto.CreateRecipeRequest{Name: "Pasta", Ingredients: []dto.RecipeIngredientRequest{{FoodID: 1, RawWeightG: 100, CookingMethodID: 1}}}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected a missing yield to be estimated, got %v", err)
+		}
+		req.YieldWeightG = -1
+		if err := req.Validate(); !errors.Is(err, dto.ErrInvalidRecipeYieldWeight) {
+			t.Fatalf("expected ErrInvalidRecipeYieldWeight, got %v", err)
+		}
+	})
+
+	t.Run("cooking method only for foods", func(t *testing.T) {
+		req := dto.CreateRecipeRequest{Name: "Lasagne", Ingredients: []dto.RecipeIngredientRequest{{SubRecipeID: 2, RawWeightG: 300, CookingMethodID: 6}}}
+		if err := req.Validate(); !errors.Is(err, dto.ErrInvalidRecipeIngredients) {
+			t.Fatalf("expected ErrInvalidRecipeIngredients, got %v", err)
+		}
+	})
+
+	t.Run("invalid times", func(t *testing.T) {
+		req := dto.CreateRecipeRequest{Name: "Chili", YieldWeightG: 1600, PrepTimeMin: -5, Ingredients: []dto.RecipeIngredientRequest{{FoodID: 1, RawWeightG: 100}}}
+		if err := req.Validate(); !errors.Is(err, dto.ErrInvalidRecipeTime) {
+			t.Fatalf("expected ErrInvalidRecipeTime, got %v", err)
+		}
+	})
 }
 
 func TestUpdateRecipeRequestValidate(t *testing.T) {
@@ -32,10 +80,44 @@ func TestUpdateRecipeRequestValidate(t *testing.T) {
 	})
 
 	t.Run("invalid yield", func(t *testing.T) {
-		y := 0.0
+		y := -1.0
 		req := dto.UpdateRecipeRequest{YieldWeightG: &y}
 		if err := req.Validate(); !errors.Is(err, dto.ErrInvalidRecipeYieldWeight) {
 			t.Fatalf("expected ErrInvalidRecipeYieldWeight, got %v", err)
 		}
+		y = 0
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected a yield of 0 to ask for an estimate, got %v", err)
+		}
 	})
+
+	t.Run("details only", func(t *testing.T) {
+		tags := []string{}
+		req := dto.UpdateRecipeRequest{Tags: &tags}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected clearing tags to be valid, got %v", err)
+		}
+	})
+
+	t.Run("invalid servings", func(t *testing.T) {
+		servings := 0
+		req := dto.UpdateRecipeRequest{Servings: &servings}
+		if err := req.Validate(); !errors.Is(err, dto.ErrInvalidRecipeServings) {
+			t.Fatalf("expected ErrInvalidRecipeServings, got %v", err)
+		}
+	})
+}
+
+func TestImportRecipeRequestValidate(t *testing.T) {
+	for _, content := range []string{"", " \n\t", strings.Repeat("a", dto.MaxRecipeImportBytes+1)} {
+		req := dto.ImportRecipeRequest{Content: content}
+		if err := req.Validate(); !errors.Is(err, dto.ErrInvalidRecipeImport) {
+			t.Fatalf("expected ErrInvalidRecipeImport for %d bytes, got %v", len(content), err)
+		}
+	}
+
+	req := dto.ImportRecipeRequest{Content: "200 g chicken breast"}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
 }
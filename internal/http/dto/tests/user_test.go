@@ -0,0 +1,61 @@
+package dto_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"goal-bite-api/internal/http/dto"
+)
+
+func TestUpdateMeRequestDietaryRestrictions(t *testing.T) {
+	t.Run("null clears restrictions", func(t *testing.T) {
+		var req dto.UpdateMeRequest
+		if err := json.Unmarshal([]byte(`{"avoid_allergens":null}`), &req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("expected valid request, got %v", err)
+		}
+		in, err := req.ToServiceInput()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if in.AvoidAllergens == nil || len(*in.AvoidAllergens) != 0 {
+			t.Fatalf("expected empty allergen list, got %v", in.AvoidAllergens)
+		}
+		if in.RequiredDietFlags != nil {
+			t.Fatalf("expected diet flags untouched, got %v", *in.RequiredDietFlags)
+		}
+	})
+
+	t.Run("passes lists through", func(t *testing.T) {
+		var req dto.UpdateMeRequest
+		if err := json.Unmarshal([]byte(`{"required_diet_flags":["vegan"]}`), &req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		in, err := req.ToServiceInput()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if in.RequiredDietFlags == nil || len(*in.RequiredDietFlags) != 1 || (*in.RequiredDietFlags)[0] != "vegan" {
+			t.Fatalf("unexpected diet flags %v", in.RequiredDietFlags)
+		}
+	})
+}
+
+func TestUpdateMeRequestLanguages(t *testing.T) {
+	var req dto.UpdateMeRequest
+	if err := json.Unmarshal([]byte(`{"languages":null}`), &req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+	in, err := req.ToServiceInput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if in.Languages == nil || len(*in.Languages) != 0 {
+		t.Fatalf("expected empty language list, got %v", in.Languages)
+	}
+}
@@ -8,17 +8,23 @@ import (
 )
 
 type UpdateMeRequest struct {
-	Name          *string  `json:"name,omitempty" example:"John Doe"`
-	Sex           *string  `json:"sex,omitempty" example:"male"`
-	BirthDate     *string  `json:"birth_date,omitempty" example:"1994-05-18"`
-	HeightCM      *float64 `json:"height_cm,omitempty" example:"178"`
-	ActivityLevel *string  `json:"activity_level,omitempty" example:"moderate"`
+	Name              *string   `json:"name,omitempty" example:"John Doe"`
+	Sex               *string   `json:"sex,omitempty" example:"male"`
+	BirthDate         *string   `json:"birth_date,omitempty" example:"1994-05-18"`
+	HeightCM          *float64  `json:"height_cm,omitempty" example:"178"`
+	ActivityLevel     *string   `json:"activity_level,omitempty" example:"moderate"`
+	AvoidAllergens    *[]string `json:"avoid_allergens,omitempty" example:"peanuts,milk"`
+	RequiredDietFlags *[]string `json:"required_diet_flags,omitempty" example:"vegetarian"`
+	Languages         *[]string `json:"languages,omitempty" example:"de,en"`
 
-	nameSet          bool
-	sexSet           bool
-	birthDateSet     bool
-	heightCMSet      bool
-	activityLevelSet bool
+	nameSet              bool
+	sexSet               bool
+	birthDateSet         bool
+	heightCMSet          bool
+	activityLevelSet     bool
+	avoidAllergensSet    bool
+	requiredDietFlagsSet bool
+	languagesSet         bool
 }
 
 func (r *UpdateMeRequest) UnmarshalJSON(data []byte) error {
@@ -79,11 +85,41 @@ func (r *UpdateMeRequest) UnmarshalJSON(data []byte) error {
 			r.ActivityLevel = &parsed
 		}
 	}
+	if v, ok := raw["avoid_allergens"]; ok {
+		r.avoidAllergensSet = true
+		parsed := []string{}
+		if string(v) != "null" {
+			if err := json.Unmarshal(v, &parsed); err != nil {
+				return err
+			}
+		}
+		r.AvoidAllergens = &parsed
+	}
+	if v, ok := raw["required_diet_flags"]; ok {
+		r.requiredDietFlagsSet = true
+		parsed := []string{}
+		if string(v) != "null" {
+			if err := json.Unmarshal(v, &parsed); err != nil {
+				return err
+			}
+		}
+		r.RequiredDietFlags = &parsed
+	}
+	if v, ok := raw["languages"]; ok {
+		r.languagesSet = true
+		parsed := []string{}
+		if string(v) != "null" {
+			if err := json.Unmarshal(v, &parsed); err != nil {
+				return err
+			}
+		}
+		r.Languages = &parsed
+	}
 	return nil
 }
 
 func (r *UpdateMeRequest) Validate() error {
-	if !r.nameSet && !r.sexSet && !r.birthDateSet && !r.heightCMSet && !r.activityLevelSet {
+	if !r.nameSet && !r.sexSet && !r.birthDateSet && !r.heightCMSet && !r.activityLevelSet && !r.avoidAllergensSet && !r.requiredDietFlagsSet && !r.languagesSet {
 		return service.ErrNoFieldsToUpdate
 	}
 	if r.nameSet && r.Name == nil {
@@ -116,13 +152,16 @@ func (r *UpdateMeRequest) Validate() error {
 
 func (r *UpdateMeRequest) ToServiceInput() (service.UpdateUserInput, error) {
 	out := service.UpdateUserInput{
-		Name:             r.Name,
-		SexSet:           r.sexSet,
-		Sex:              r.Sex,
-		HeightCMSet:      r.heightCMSet,
-		HeightCM:         r.HeightCM,
-		ActivityLevelSet: r.activityLevelSet,
-		ActivityLevel:    r.ActivityLevel,
+		Name:              r.Name,
+		SexSet:            r.sexSet,
+		Sex:               r.Sex,
+		HeightCMSet:       r.heightCMSet,
+		HeightCM:          r.HeightCM,
+		ActivityLevelSet:  r.activityLevelSet,
+		ActivityLevel:     r.ActivityLevel,
+		AvoidAllergens:    r.AvoidAllergens,
+		RequiredDietFlags: r.RequiredDietFlags,
+		Languages:         r.Languages,
 	}
 	if r.birthDateSet {
 		out.BirthDateSet = true
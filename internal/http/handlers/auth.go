@@ -48,6 +48,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		mapServiceError(service.ErrInvalidName, http.StatusBadRequest, "invalid_register_payload", "invalid register payload"),
 		mapServiceError(service.ErrInvalidEmail, http.StatusBadRequest, "invalid_register_payload", "invalid register payload"),
 		mapServiceError(service.ErrInvalidPassword, http.StatusBadRequest, "invalid_password_policy", "password does not meet policy"),
+		mapServiceError(service.ErrPasswordCompromised, http.StatusBadRequest, "password_compromised", "password has appeared in a data breach"),
 		mapServiceError(service.ErrInvalidProfile, http.StatusBadRequest, "invalid_register_payload", "invalid register payload"),
 		mapServiceError(service.ErrEmailAlreadyExists, http.StatusConflict, "email_already_exists", "email already exists"),
 	) {
@@ -176,3 +177,81 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// RequestMagicLink godoc
+// @Summary Request magic login link
+// @Description Always returns 202 for a well-formed email, whether or not an account exists.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param payload body dto.MagicLinkRequest true "Magic link payload"
+// @Success 202
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 429 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/magic-link [post]
+func (h *Handler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req dto.MagicLinkRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_magic_link_payload", "invalid magic link payload")
+		return
+	}
+
+	err := h.magicLinkService.RequestMagicLink(r.Context(), req.Email)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidEmail, http.StatusBadRequest, "invalid_magic_link_payload", "invalid magic link payload"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ConsumeMagicLink godoc
+// @Summary Exchange magic link token for session tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param payload body dto.MagicLinkConsumeRequest true "Magic link token payload"
+// @Success 200 {object} service.AuthResult
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 429 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/magic-link/consume [post]
+func (h *Handler) ConsumeMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req dto.MagicLinkConsumeRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_magic_link_payload", "invalid magic link payload")
+		return
+	}
+
+	result, err := h.magicLinkService.ConsumeMagicLink(r.Context(), req.Token)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidMagicLinkToken, http.StatusUnauthorized, "invalid_magic_link_token", "invalid or expired magic link"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
@@ -2,11 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
-	"goal-bite-api/internal/domain/food"
 	"goal-bite-api/internal/http/dto"
 	"goal-bite-api/internal/service"
 
@@ -15,12 +15,16 @@ import (
 
 // CreateFood godoc
 // @Summary Create food
+// @Description Energy is checked against the Atwater factors (4/4/9/7 kcal per g of protein/carbs/fat/alcohol) and macro mass against 100 g. In lenient mode failed checks are returned as warnings; in strict mode the food is rejected with inconsistent_nutrition.
 // @Tags foods
 // @Accept json
 // @Produce json
+// @Param validation query string false "Nutrition validation mode" Enums(lenient, strict)
 // @Param payload body dto.CreateFoodRequest true "Food payload"
 // @Success 201 {object} FoodResponse
 // @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
 // @Failure 409 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
 // @Router /foods [post]
@@ -41,14 +45,30 @@ func (h *Handler) CreateFood(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_food_payload", "invalid food payload")
 		return
 	}
+	strict, ok := parseStrictNutrition(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_validation_mode", "invalid validation mode")
+		return
+	}
+	in := req.ToServiceInput()
+	in.StrictNutrition = strict
 
-	value, err := h.foodService.Create(r.Context(), userID, req.ToServiceInput())
+	value, err := h.foodService.Create(r.Context(), userID, in)
+	if writeNutritionError(w, err) {
+		return
+	}
 	if writeMappedServiceError(w, err,
 		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
 		mapServiceError(service.ErrInvalidFoodName, http.StatusBadRequest, "invalid_food_payload", "invalid food payload"),
 		mapServiceError(service.ErrInvalidFoodBarcode, http.StatusBadRequest, "invalid_food_payload", "invalid food payload"),
 		mapServiceError(service.ErrFoodBarcodeExists, http.StatusConflict, "food_barcode_already_exists", "food barcode already exists"),
 		mapServiceError(service.ErrInvalidNutritionData, http.StatusBadRequest, "invalid_food_payload", "invalid food payload"),
+		mapServiceError(service.ErrInvalidFoodVisibility, http.StatusBadRequest, "invalid_food_visibility", "invalid food visibility"),
+		mapServiceError(service.ErrFoodCategoryNotFound, http.StatusBadRequest, "invalid_food_category", "invalid food category"),
+		mapServiceError(service.ErrInvalidFoodTags, http.StatusBadRequest, "invalid_food_tags", "invalid food tags"),
+		mapServiceError(service.ErrInvalidDietaryInfo, http.StatusBadRequest, "invalid_dietary_info", "invalid allergens or diet flags"),
+		mapServiceError(service.ErrInvalidTranslations, http.StatusBadRequest, "invalid_translations", "invalid translations"),
+		mapServiceError(service.ErrFoodVerificationForbidden, http.StatusForbidden, "food_verification_forbidden", "only admins can verify foods"),
 	) {
 		return
 	}
@@ -57,27 +77,35 @@ func (h *Handler) CreateFood(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	value.Localize(h.nameLanguages(w, r))
 	writeJSON(w, http.StatusCreated, value)
 }
 
 // GetFoodByID godoc
 // @Summary Get food by ID
+// @Description Other users' private foods are reported as not found.
 // @Tags foods
 // @Produce json
 // @Param id path int true "Food ID"
+// @Param Accept-Language header string false "Languages to show names in, after the caller's saved languages"
 // @Success 200 {object} FoodResponse
 // @Failure 400 {object} ErrorEnvelope
 // @Failure 404 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
 // @Router /foods/{id} [get]
 func (h *Handler) GetFoodByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
 	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
 	if !ok {
 		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
 		return
 	}
 
-	value, err := h.foodService.GetByID(r.Context(), id)
+	value, err := h.foodService.GetByID(r.Context(), userID, id)
 	if writeMappedServiceError(w, err,
 		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
 	) {
@@ -88,11 +116,13 @@ func (h *Handler) GetFoodByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	value.Localize(h.nameLanguages(w, r))
 	writeJSON(w, http.StatusOK, value)
 }
 
 // GetFoodByBarcode godoc
 // @Summary Get food by barcode
+// @Description Resolves among foods visible to the caller. Verified entries win, then the caller's private entry, then public ones. Unknown barcodes are looked up in the configured external catalog; found products are imported as public catalog foods, and misses are cached.
 // @Tags foods
 // @Produce json
 // @Param barcode path string true "Food barcode"
@@ -100,18 +130,25 @@ func (h *Handler) GetFoodByID(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 {object} ErrorEnvelope
 // @Failure 404 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
+// @Failure 502 {object} ErrorEnvelope
 // @Router /foods/by-barcode/{barcode} [get]
 func (h *Handler) GetFoodByBarcode(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
 	barcode := strings.TrimSpace(chi.URLParam(r, "barcode"))
 	if barcode == "" {
 		writeError(w, http.StatusBadRequest, "invalid_food_barcode", "invalid food barcode")
 		return
 	}
 
-	value, err := h.foodService.GetByBarcode(r.Context(), barcode)
+	value, err := h.foodService.GetByBarcode(r.Context(), userID, barcode)
 	if writeMappedServiceError(w, err,
 		mapServiceError(service.ErrInvalidFoodBarcode, http.StatusBadRequest, "invalid_food_barcode", "invalid food barcode"),
 		mapServiceError(service.ErrFoodBarcodeNotFound, http.StatusNotFound, "food_barcode_not_found", "food barcode not found"),
+		mapServiceError(service.ErrBarcodeLookupUnavailable, http.StatusBadGateway, "barcode_lookup_unavailable", "barcode lookup unavailable"),
 	) {
 		return
 	}
@@ -120,14 +157,21 @@ func (h *Handler) GetFoodByBarcode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	value.Localize(h.nameLanguages(w, r))
 	writeJSON(w, http.StatusOK, value)
 }
 
 // ListFoods godoc
 // @Summary List foods
+// @Description Returns public and verified foods plus the caller's private foods. Archived foods are skipped unless include_archived is set. display_name holds the name in the first of the caller's saved languages, then Accept-Language languages, that the food is translated into.
 // @Tags foods
 // @Produce json
-// @Param q query string false "Search by food name (case-insensitive, partial match)"
+// @Param q query string false "Search by food name, or by translated name or synonym in the caller's languages (case-insensitive, partial match)"
+// @Param Accept-Language header string false "Languages to show and search names in, after the caller's saved languages"
+// @Param category_id query int false "Only foods in this category or its subcategories"
+// @Param tag query []string false "Only foods carrying every given tag" collectionFormat(multi)
+// @Param include_archived query bool false "Include archived foods"
+// @Param exclude_conflicts query bool false "Skip foods conflicting with the caller's allergens and diet flags"
 // @Param limit query int false "Page size (default 20, max 100)"
 // @Param offset query int false "Page offset (default 0)"
 // @Success 200 {array} FoodResponse
@@ -135,27 +179,78 @@ func (h *Handler) GetFoodByBarcode(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} ErrorEnvelope
 // @Router /foods [get]
 func (h *Handler) ListFoods(w http.ResponseWriter, r *http.Request) {
-	limit, offset, ok := parsePagination(r)
+	userID, ok := requireAuthUserID(w, r)
 	if !ok {
-		writeError(w, http.StatusBadRequest, "invalid_pagination", "invalid pagination")
 		return
 	}
-	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	in, ok := parseFoodListInput(w, r)
+	if !ok {
+		return
+	}
+	in.Languages = h.nameLanguages(w, r)
 
-	var (
-		values []food.Food
-		err    error
-	)
-	if query == "" {
-		values, err = h.foodService.List(r.Context(), limit, offset)
-	} else {
-		values, err = h.foodService.Search(r.Context(), query, limit, offset)
+	values, err := h.foodService.List(r.Context(), userID, in)
+	if writeFoodListError(w, err) {
+		return
 	}
-	if writeMappedServiceError(w, err,
-		mapServiceError(service.ErrInvalidPagination, http.StatusBadRequest, "invalid_pagination", "invalid pagination"),
-	) {
+
+	localizeFoods(values, in.Languages)
+	writeJSON(w, http.StatusOK, values)
+}
+
+// SearchFoods godoc
+// @Summary Search foods with facets
+// @Description Takes the same filters as GET /foods and also returns per-category and per-tag counts over all matches, for narrowing results.
+// @Tags foods
+// @Produce json
+// @Param q query string false "Search by food name, or by translated name or synonym in the caller's languages (case-insensitive, partial match)"
+// @Param Accept-Language header string false "Languages to show and search names in, after the caller's saved languages"
+// @Param category_id query int false "Only foods in this category or its subcategories"
+// @Param tag query []string false "Only foods carrying every given tag" collectionFormat(multi)
+// @Param include_archived query bool false "Include archived foods"
+// @Param exclude_conflicts query bool false "Skip foods conflicting with the caller's allergens and diet flags"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Page offset (default 0)"
+// @Success 200 {object} FoodSearchResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/search [get]
+func (h *Handler) SearchFoods(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+	in, ok := parseFoodListInput(w, r)
+	if !ok {
+		return
+	}
+	in.Languages = h.nameLanguages(w, r)
+
+	value, err := h.foodService.Search(r.Context(), userID, in)
+	if writeFoodListError(w, err) {
+		return
+	}
+
+	localizeFoods(value.Items, in.Languages)
+	writeJSON(w, http.StatusOK, value)
+}
+
+// ListFoodCategories godoc
+// @Summary List food categories
+// @Description Returns the food category taxonomy as a tree, sorted by name.
+// @Tags foods
+// @Produce json
+// @Success 200 {array} FoodCategoryResponse
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /food-categories [get]
+func (h *Handler) ListFoodCategories(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireAuthUserID(w, r); !ok {
 		return
 	}
+
+	values, err := h.foodService.Categories(r.Context())
 	if err != nil {
 		writeDatabaseError(w)
 		return
@@ -164,15 +259,91 @@ func (h *Handler) ListFoods(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, values)
 }
 
+// ListCookingMethods godoc
+// @Summary List cooking methods
+// @Description Returns the cooking methods with their yield and moisture-retention factors per food category. A factor without a category is the method's default.
+// @Tags foods
+// @Produce json
+// @Success 200 {array} CookingMethodResponse
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /cooking-methods [get]
+func (h *Handler) ListCookingMethods(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireAuthUserID(w, r); !ok {
+		return
+	}
+
+	values, err := h.foodService.CookingMethods(r.Context())
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, values)
+}
+
+func parseFoodListInput(w http.ResponseWriter, r *http.Request) (service.FoodListInput, bool) {
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_pagination", "invalid pagination")
+		return service.FoodListInput{}, false
+	}
+	includeArchived, ok := parseIncludeArchived(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_include_archived", "invalid include_archived")
+		return service.FoodListInput{}, false
+	}
+	excludeConflicts, ok := parseExcludeConflicts(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_exclude_conflicts", "invalid exclude_conflicts")
+		return service.FoodListInput{}, false
+	}
+	in := service.FoodListInput{
+		Query:            strings.TrimSpace(r.URL.Query().Get("q")),
+		IncludeArchived:  includeArchived,
+		Tags:             r.URL.Query()["tag"],
+		ExcludeConflicts: excludeConflicts,
+		Limit:            limit,
+		Offset:           offset,
+	}
+	if raw := r.URL.Query().Get("category_id"); raw != "" {
+		categoryID, ok := parseIDFromPath(raw)
+		if !ok || categoryID == 0 {
+			writeError(w, http.StatusBadRequest, "invalid_food_category", "invalid food category")
+			return service.FoodListInput{}, false
+		}
+		in.CategoryID = &categoryID
+	}
+	return in, true
+}
+
+func writeFoodListError(w http.ResponseWriter, err error) bool {
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidPagination, http.StatusBadRequest, "invalid_pagination", "invalid pagination"),
+		mapServiceError(service.ErrInvalidFoodTags, http.StatusBadRequest, "invalid_food_tags", "invalid food tags"),
+	) {
+		return true
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return true
+	}
+	return false
+}
+
 // UpdateFood godoc
 // @Summary Update food
+// @Description When nutrition values change, the resulting food is checked like on create: warnings in lenient mode, inconsistent_nutrition in strict mode.
 // @Tags foods
 // @Accept json
 // @Produce json
 // @Param id path int true "Food ID"
+// @Param validation query string false "Nutrition validation mode" Enums(lenient, strict)
 // @Param payload body dto.UpdateFoodRequest true "Food update payload"
 // @Success 200 {object} FoodResponse
 // @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
 // @Failure 404 {object} ErrorEnvelope
 // @Failure 409 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
@@ -200,17 +371,34 @@ func (h *Handler) UpdateFood(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_food_payload", "invalid food payload")
 		return
 	}
+	strict, ok := parseStrictNutrition(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_validation_mode", "invalid validation mode")
+		return
+	}
+	in := req.ToServiceInput()
+	in.StrictNutrition = strict
 
-	value, err := h.foodService.Update(r.Context(), userID, id, req.ToServiceInput())
+	value, err := h.foodService.Update(r.Context(), userID, id, in)
+	if writeNutritionError(w, err) {
+		return
+	}
 	if writeMappedServiceError(w, err,
 		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
 		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
+		mapServiceError(service.ErrFoodArchived, http.StatusConflict, "food_archived", "food is archived"),
 		mapServiceError(service.ErrFoodForbidden, http.StatusForbidden, "forbidden", "forbidden"),
 		mapServiceError(service.ErrInvalidFoodBarcode, http.StatusBadRequest, "invalid_food_payload", "invalid food payload"),
 		mapServiceError(service.ErrFoodBarcodeExists, http.StatusConflict, "food_barcode_already_exists", "food barcode already exists"),
 		mapServiceError(service.ErrInvalidFoodName, http.StatusBadRequest, "invalid_food_payload", "invalid food payload"),
 		mapServiceError(service.ErrInvalidNutritionData, http.StatusBadRequest, "invalid_food_payload", "invalid food payload"),
 		mapServiceError(service.ErrNoFieldsToUpdate, http.StatusBadRequest, "invalid_food_payload", "invalid food payload"),
+		mapServiceError(service.ErrInvalidFoodVisibility, http.StatusBadRequest, "invalid_food_visibility", "invalid food visibility"),
+		mapServiceError(service.ErrFoodCategoryNotFound, http.StatusBadRequest, "invalid_food_category", "invalid food category"),
+		mapServiceError(service.ErrInvalidFoodTags, http.StatusBadRequest, "invalid_food_tags", "invalid food tags"),
+		mapServiceError(service.ErrInvalidDietaryInfo, http.StatusBadRequest, "invalid_dietary_info", "invalid allergens or diet flags"),
+		mapServiceError(service.ErrInvalidTranslations, http.StatusBadRequest, "invalid_translations", "invalid translations"),
+		mapServiceError(service.ErrFoodVerificationForbidden, http.StatusForbidden, "food_verification_forbidden", "only admins can verify foods"),
 	) {
 		return
 	}
@@ -219,11 +407,13 @@ func (h *Handler) UpdateFood(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	value.Localize(h.nameLanguages(w, r))
 	writeJSON(w, http.StatusOK, value)
 }
 
 // DeleteFood godoc
 // @Summary Delete food
+// @Description Archives the food. It disappears from lists, search and barcode lookups but still resolves by ID for meal items and recipes that use it.
 // @Tags foods
 // @Produce json
 // @Param id path int true "Food ID"
@@ -262,6 +452,338 @@ func (h *Handler) DeleteFood(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RestoreFood godoc
+// @Summary Restore archived food
+// @Tags foods
+// @Produce json
+// @Param id path int true "Food ID"
+// @Success 200 {object} FoodResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/{id}/restore [post]
+func (h *Handler) RestoreFood(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
+		return
+	}
+
+	value, err := h.foodService.Restore(r.Context(), userID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
+		mapServiceError(service.ErrFoodForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+		mapServiceError(service.ErrFoodBarcodeExists, http.StatusConflict, "food_barcode_already_exists", "food barcode already exists"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+// VerifyFood godoc
+// @Summary Verify food
+// @Description Admin only. Marks a visible food as verified so it wins barcode lookups.
+// @Tags foods
+// @Produce json
+// @Param id path int true "Food ID"
+// @Success 200 {object} FoodResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/{id}/verify [post]
+func (h *Handler) VerifyFood(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
+		return
+	}
+
+	value, err := h.foodService.Verify(r.Context(), userID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrFoodVerificationForbidden, http.StatusForbidden, "food_verification_forbidden", "only admins can verify foods"),
+		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
+		mapServiceError(service.ErrFoodArchived, http.StatusConflict, "food_archived", "food is archived"),
+		mapServiceError(service.ErrFoodBarcodeExists, http.StatusConflict, "food_barcode_already_exists", "food barcode already exists"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+// ListFoodVersions godoc
+// @Summary List food versions
+// @Description Returns the edit history of a visible food, newest first.
+// @Tags foods
+// @Produce json
+// @Param id path int true "Food ID"
+// @Success 200 {array} FoodVersionResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/{id}/versions [get]
+func (h *Handler) ListFoodVersions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
+		return
+	}
+
+	values, err := h.foodService.ListVersions(r.Context(), userID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, values)
+}
+
+// RollbackFood godoc
+// @Summary Roll back food to a version
+// @Description Restores name, brand, barcode and nutrition values from an earlier version. The rollback is recorded as a new version; visibility is unchanged.
+// @Tags foods
+// @Produce json
+// @Param id path int true "Food ID"
+// @Param version path int true "Version to restore"
+// @Success 200 {object} FoodResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/{id}/versions/{version}/rollback [post]
+func (h *Handler) RollbackFood(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
+		return
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(chi.URLParam(r, "version")))
+	if err != nil || version <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid_food_version", "invalid food version")
+		return
+	}
+
+	value, err := h.foodService.Rollback(r.Context(), userID, id, version)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
+		mapServiceError(service.ErrFoodArchived, http.StatusConflict, "food_archived", "food is archived"),
+		mapServiceError(service.ErrFoodVersionNotFound, http.StatusNotFound, "food_version_not_found", "food version not found"),
+		mapServiceError(service.ErrFoodForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+		mapServiceError(service.ErrFoodBarcodeExists, http.StatusConflict, "food_barcode_already_exists", "food barcode already exists"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+// FindFoodDuplicates godoc
+// @Summary Find duplicate foods
+// @Description Scores visible foods against this one by normalized name, brand, barcode and nutrition similarity. Returns up to 20 candidates scoring at least 0.6, best first.
+// @Tags foods
+// @Produce json
+// @Param id path int true "Food ID"
+// @Success 200 {array} FoodDuplicateResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/{id}/duplicates [get]
+func (h *Handler) FindFoodDuplicates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
+		return
+	}
+
+	values, err := h.foodService.FindDuplicates(r.Context(), userID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	languages := h.nameLanguages(w, r)
+	for i := range values {
+		values[i].Food.Localize(languages)
+	}
+	writeJSON(w, http.StatusOK, values)
+}
+
+// MergeFoods godoc
+// @Summary Merge foods into this one
+// @Description Admin only. Re-points meal items, recipe ingredients and shopping list items of the listed foods to the food in the path in one transaction, archives the merged foods and recalculates affected recipes. Recipes that fail to recalculate stay stale for the background pass. Each merged food is logged.
+// @Tags foods
+// @Accept json
+// @Produce json
+// @Param id path int true "Surviving food ID"
+// @Param payload body dto.MergeFoodsRequest true "Foods to merge"
+// @Success 200 {object} FoodMergeResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/{id}/merge [post]
+func (h *Handler) MergeFoods(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
+		return
+	}
+
+	var req dto.MergeFoodsRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_food_merge", "invalid food merge")
+		return
+	}
+
+	value, err := h.foodService.Merge(r.Context(), userID, id, req.FoodIDs)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrFoodMergeForbidden, http.StatusForbidden, "food_merge_forbidden", "only admins can merge foods"),
+		mapServiceError(service.ErrInvalidFoodMerge, http.StatusBadRequest, "invalid_food_merge", "invalid food merge"),
+		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
+		mapServiceError(service.ErrFoodArchived, http.StatusConflict, "food_archived", "food is archived"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Survivor.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+// GetFoodNutritionReport godoc
+// @Summary List foods with inconsistent nutrition
+// @Description Returns active foods visible to the caller whose energy does not match the Atwater estimate or whose macros add up to more than 100 g, with the failed checks.
+// @Tags foods
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Page offset (default 0)"
+// @Success 200 {array} FoodNutritionIssueResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/nutrition-report [get]
+func (h *Handler) GetFoodNutritionReport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_pagination", "invalid pagination")
+		return
+	}
+
+	values, err := h.foodService.NutritionReport(r.Context(), userID, limit, offset)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidPagination, http.StatusBadRequest, "invalid_pagination", "invalid pagination"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	languages := h.nameLanguages(w, r)
+	for i := range values {
+		values[i].Food.Localize(languages)
+	}
+	writeJSON(w, http.StatusOK, values)
+}
+
+// writeNutritionError reports the failed checks when strict validation
+// rejects a food.
+func writeNutritionError(w http.ResponseWriter, err error) bool {
+	var nutritionErr *service.NutritionError
+	if !errors.As(err, &nutritionErr) {
+		return false
+	}
+	writeError(w, http.StatusBadRequest, "inconsistent_nutrition", nutritionErr.Error())
+	return true
+}
+
 func parseIDFromPath(idPart string) (uint, bool) {
 	idPart = strings.TrimSpace(idPart)
 	if idPart == "" {
@@ -275,3 +797,96 @@ func parseIDFromPath(idPart string) (uint, bool) {
 
 	return uint(id), true
 }
+
+// UploadFoodImage godoc
+// @Summary Upload food image
+// @Description Stores a JPEG, PNG or GIF as the food's image, replacing any previous one, and returns the food with `image_url` and `thumbnail_url`. The same edit rules as PATCH apply.
+// @Tags foods
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Food ID"
+// @Param image formData file true "Image file"
+// @Success 200 {object} FoodResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 413 {object} ErrorEnvelope
+// @Failure 415 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Failure 503 {object} ErrorEnvelope
+// @Router /foods/{id}/image [put]
+func (h *Handler) UploadFoodImage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
+		return
+	}
+	data, ok := readImageUpload(w, r)
+	if !ok {
+		return
+	}
+
+	value, err := h.foodService.SetImage(r.Context(), userID, id, data)
+	if writeImageError(w, err) || writeFoodImageError(w, err) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+// DeleteFoodImage godoc
+// @Summary Remove food image
+// @Tags foods
+// @Param id path int true "Food ID"
+// @Success 204
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /foods/{id}/image [delete]
+func (h *Handler) DeleteFoodImage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_food_id", "invalid food id")
+		return
+	}
+
+	_, err := h.foodService.RemoveImage(r.Context(), userID, id)
+	if writeFoodImageError(w, err) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeFoodImageError(w http.ResponseWriter, err error) bool {
+	return writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrFoodNotFound, http.StatusNotFound, "food_not_found", "food not found"),
+		mapServiceError(service.ErrFoodArchived, http.StatusConflict, "food_archived", "food is archived"),
+		mapServiceError(service.ErrFoodForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+	)
+}
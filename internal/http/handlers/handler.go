@@ -4,13 +4,18 @@ import (
 	"context"
 
 	"goal-bite-api/internal/domain/bodyweightlog"
+	"goal-bite-api/internal/domain/cooking"
 	"goal-bite-api/internal/domain/food"
 	"goal-bite-api/internal/domain/meal"
 	"goal-bite-api/internal/domain/mealitem"
+	"goal-bite-api/internal/domain/passkey"
 	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/sharelink"
+	"goal-bite-api/internal/domain/shoppinglist"
 	"goal-bite-api/internal/domain/user"
 	"goal-bite-api/internal/domain/usergoal"
 	"goal-bite-api/internal/service"
+	"goal-bite-api/internal/webauthn"
 )
 
 type Handler struct {
@@ -23,6 +28,11 @@ type Handler struct {
 	mealService          MealService
 	bodyWeightLogService BodyWeightLogService
 	userGoalService      UserGoalService
+	magicLinkService     MagicLinkService
+	passkeyService       PasskeyService
+	oidcService          OIDCService
+	shoppingListService  ShoppingListService
+	shareLinkService     ShareLinkService
 }
 
 type UserService interface {
@@ -37,23 +47,113 @@ type AuthService interface {
 	Logout(ctx context.Context, refreshToken string) error
 }
 
+type MagicLinkService interface {
+	RequestMagicLink(ctx context.Context, email string) error
+	ConsumeMagicLink(ctx context.Context, token string) (service.AuthResult, error)
+}
+
+type noopMagicLinkService struct{}
+
+func (noopMagicLinkService) RequestMagicLink(_ context.Context, _ string) error {
+	return nil
+}
+
+func (noopMagicLinkService) ConsumeMagicLink(_ context.Context, _ string) (service.AuthResult, error) {
+	return service.AuthResult{}, service.ErrInvalidMagicLinkToken
+}
+
+type PasskeyService interface {
+	BeginRegistration(ctx context.Context, userID uint) (webauthn.CreationOptions, error)
+	FinishRegistration(ctx context.Context, userID uint, in service.FinishPasskeyRegistrationInput) (passkey.Passkey, error)
+	BeginLogin(ctx context.Context) (webauthn.RequestOptions, error)
+	FinishLogin(ctx context.Context, assertion webauthn.AssertionResponse) (service.AuthResult, error)
+	List(ctx context.Context, userID uint) ([]passkey.Passkey, error)
+	Delete(ctx context.Context, userID, id uint) error
+}
+
+type noopPasskeyService struct{}
+
+func (noopPasskeyService) BeginRegistration(_ context.Context, _ uint) (webauthn.CreationOptions, error) {
+	return webauthn.CreationOptions{}, service.ErrInvalidUserID
+}
+
+func (noopPasskeyService) FinishRegistration(_ context.Context, _ uint, _ service.FinishPasskeyRegistrationInput) (passkey.Passkey, error) {
+	return passkey.Passkey{}, service.ErrInvalidPasskeyResponse
+}
+
+func (noopPasskeyService) BeginLogin(_ context.Context) (webauthn.RequestOptions, error) {
+	return webauthn.RequestOptions{}, service.ErrPasskeyLoginFailed
+}
+
+func (noopPasskeyService) FinishLogin(_ context.Context, _ webauthn.AssertionResponse) (service.AuthResult, error) {
+	return service.AuthResult{}, service.ErrPasskeyLoginFailed
+}
+
+func (noopPasskeyService) List(_ context.Context, _ uint) ([]passkey.Passkey, error) {
+	return []passkey.Passkey{}, nil
+}
+
+func (noopPasskeyService) Delete(_ context.Context, _, _ uint) error {
+	return service.ErrPasskeyNotFound
+}
+
+type OIDCService interface {
+	Providers() []string
+	BeginLogin(ctx context.Context, provider string) (service.OIDCLoginStart, error)
+	FinishLogin(ctx context.Context, provider, code, state string) (service.AuthResult, error)
+}
+
+type noopOIDCService struct{}
+
+func (noopOIDCService) Providers() []string {
+	return []string{}
+}
+
+func (noopOIDCService) BeginLogin(_ context.Context, _ string) (service.OIDCLoginStart, error) {
+	return service.OIDCLoginStart{}, service.ErrOIDCProviderNotFound
+}
+
+func (noopOIDCService) FinishLogin(_ context.Context, _, _, _ string) (service.AuthResult, error) {
+	return service.AuthResult{}, service.ErrOIDCProviderNotFound
+}
+
 type FoodService interface {
 	Create(ctx context.Context, userID uint, in service.CreateFoodInput) (food.Food, error)
-	GetByID(ctx context.Context, id uint) (food.Food, error)
-	GetByBarcode(ctx context.Context, barcode string) (food.Food, error)
-	List(ctx context.Context, limit, offset int) ([]food.Food, error)
-	Search(ctx context.Context, query string, limit, offset int) ([]food.Food, error)
+	GetByID(ctx context.Context, userID, id uint) (food.Food, error)
+	GetByBarcode(ctx context.Context, userID uint, barcode string) (food.Food, error)
+	List(ctx context.Context, userID uint, in service.FoodListInput) ([]food.Food, error)
+	Search(ctx context.Context, userID uint, in service.FoodListInput) (service.FoodSearchResult, error)
+	Categories(ctx context.Context) ([]food.CategoryNode, error)
+	CookingMethods(ctx context.Context) ([]cooking.Method, error)
 	Update(ctx context.Context, userID, id uint, in service.UpdateFoodInput) (food.Food, error)
 	Delete(ctx context.Context, userID, id uint) error
+	Restore(ctx context.Context, userID, id uint) (food.Food, error)
+	Verify(ctx context.Context, userID, id uint) (food.Food, error)
+	ListVersions(ctx context.Context, userID, id uint) ([]food.Version, error)
+	Rollback(ctx context.Context, userID, id uint, version int) (food.Food, error)
+	FindDuplicates(ctx context.Context, userID, id uint) ([]service.FoodDuplicate, error)
+	Merge(ctx context.Context, userID, survivorID uint, foodIDs []uint) (service.FoodMergeResult, error)
+	NutritionReport(ctx context.Context, userID uint, limit, offset int) ([]service.FoodNutritionIssue, error)
+	SetImage(ctx context.Context, userID, id uint, data []byte) (food.Food, error)
+	RemoveImage(ctx context.Context, userID, id uint) (food.Food, error)
 }
 
 type RecipeService interface {
 	Create(ctx context.Context, userID uint, in service.CreateRecipeInput) (recipe.Recipe, error)
 	GetByID(ctx context.Context, id uint) (recipe.Recipe, error)
-	List(ctx context.Context, limit, offset int) ([]recipe.Recipe, error)
-	Search(ctx context.Context, query string, limit, offset int) ([]recipe.Recipe, error)
+	Breakdown(ctx context.Context, userID, id uint) (recipe.Recipe, service.NutritionBreakdown, error)
+	List(ctx context.Context, userID uint, in service.RecipeListInput) ([]recipe.Recipe, error)
 	Update(ctx context.Context, userID, id uint, in service.UpdateRecipeInput) (recipe.Recipe, error)
 	Delete(ctx context.Context, userID, id uint) error
+	Restore(ctx context.Context, userID, id uint) (recipe.Recipe, error)
+	Refresh(ctx context.Context, userID, id uint) (recipe.Recipe, error)
+	Scale(ctx context.Context, id uint, servings int) (recipe.Recipe, error)
+	Import(ctx context.Context, userID uint, content string) (service.RecipeDraft, error)
+	Fork(ctx context.Context, userID, id uint) (recipe.Recipe, error)
+	Forks(ctx context.Context, id uint, limit, offset int) ([]service.RecipeFork, error)
+	Changes(ctx context.Context, id uint) (service.RecipeChanges, error)
+	SetImage(ctx context.Context, userID, id uint, data []byte) (recipe.Recipe, error)
+	RemoveImage(ctx context.Context, userID, id uint) (recipe.Recipe, error)
 }
 
 type MealService interface {
@@ -61,11 +161,14 @@ type MealService interface {
 	Update(ctx context.Context, userID, mealID uint, in service.UpdateMealInput) (meal.Meal, error)
 	Delete(ctx context.Context, userID, mealID uint) error
 	GetByID(ctx context.Context, userID, id uint) (meal.Meal, error)
+	Breakdown(ctx context.Context, userID, id uint) (meal.Meal, service.NutritionBreakdown, error)
 	List(ctx context.Context, in service.ListMealsInput) ([]meal.Meal, error)
 	AddItem(ctx context.Context, userID, mealID uint, in service.AddMealItemInput) (mealitem.MealItem, error)
 	UpdateItem(ctx context.Context, userID, mealID, itemID uint, in service.UpdateMealItemInput) (mealitem.MealItem, error)
 	DeleteItem(ctx context.Context, userID, mealID, itemID uint) error
 	GetDailyTotals(ctx context.Context, userID uint, date string) (service.DailyTotalsOutput, error)
+	SetImage(ctx context.Context, userID, mealID uint, data []byte) (meal.Meal, error)
+	RemoveImage(ctx context.Context, userID, mealID uint) (meal.Meal, error)
 }
 
 type BodyWeightLogService interface {
@@ -114,6 +217,66 @@ func (noopUserGoalService) GetDailyProgress(_ context.Context, _ uint, _ string)
 	return service.DailyProgressOutput{}, service.ErrUserGoalNotFound
 }
 
+type ShoppingListService interface {
+	Create(ctx context.Context, userID uint, in service.CreateShoppingListInput) (shoppinglist.ShoppingList, error)
+	GetByID(ctx context.Context, userID, id uint) (shoppinglist.ShoppingList, error)
+	List(ctx context.Context, userID uint, limit, offset int) ([]shoppinglist.ShoppingList, error)
+	SetItemChecked(ctx context.Context, userID, listID, itemID uint, checked bool) (shoppinglist.Item, error)
+	Delete(ctx context.Context, userID, id uint) error
+}
+
+type noopShoppingListService struct{}
+
+func (noopShoppingListService) Create(_ context.Context, _ uint, _ service.CreateShoppingListInput) (shoppinglist.ShoppingList, error) {
+	return shoppinglist.ShoppingList{}, service.ErrInvalidShoppingListSource
+}
+
+func (noopShoppingListService) GetByID(_ context.Context, _, _ uint) (shoppinglist.ShoppingList, error) {
+	return shoppinglist.ShoppingList{}, service.ErrShoppingListNotFound
+}
+
+func (noopShoppingListService) List(_ context.Context, _ uint, _, _ int) ([]shoppinglist.ShoppingList, error) {
+	return []shoppinglist.ShoppingList{}, nil
+}
+
+func (noopShoppingListService) SetItemChecked(_ context.Context, _, _, _ uint, _ bool) (shoppinglist.Item, error) {
+	return shoppinglist.Item{}, service.ErrShoppingListItemNotFound
+}
+
+func (noopShoppingListService) Delete(_ context.Context, _, _ uint) error {
+	return service.ErrShoppingListNotFound
+}
+
+type ShareLinkService interface {
+	ShareRecipe(ctx context.Context, userID, recipeID uint, in service.CreateShareLinkInput) (sharelink.ShareLink, error)
+	ShareMeal(ctx context.Context, userID, mealID uint, in service.CreateShareLinkInput) (sharelink.ShareLink, error)
+	List(ctx context.Context, userID uint, limit, offset int) ([]sharelink.ShareLink, error)
+	Revoke(ctx context.Context, userID, id uint) error
+	Open(ctx context.Context, token string) (service.SharedView, error)
+}
+
+type noopShareLinkService struct{}
+
+func (noopShareLinkService) ShareRecipe(_ context.Context, _, _ uint, _ service.CreateShareLinkInput) (sharelink.ShareLink, error) {
+	return sharelink.ShareLink{}, service.ErrRecipeNotFound
+}
+
+func (noopShareLinkService) ShareMeal(_ context.Context, _, _ uint, _ service.CreateShareLinkInput) (sharelink.ShareLink, error) {
+	return sharelink.ShareLink{}, service.ErrMealNotFound
+}
+
+func (noopShareLinkService) List(_ context.Context, _ uint, _, _ int) ([]sharelink.ShareLink, error) {
+	return []sharelink.ShareLink{}, nil
+}
+
+func (noopShareLinkService) Revoke(_ context.Context, _, _ uint) error {
+	return service.ErrShareLinkNotFound
+}
+
+func (noopShareLinkService) Open(_ context.Context, _ string) (service.SharedView, error) {
+	return service.SharedView{}, service.ErrShareLinkNotFound
+}
+
 func New(
 	userService UserService,
 	authService AuthService,
@@ -126,6 +289,11 @@ func New(
 	energyService := EnergyService(noopEnergyService{})
 	userGoalService := UserGoalService(noopUserGoalService{})
 	readinessChecker := ReadinessChecker(noopReadinessChecker{})
+	magicLinkService := MagicLinkService(noopMagicLinkService{})
+	passkeyService := PasskeyService(noopPasskeyService{})
+	oidcService := OIDCService(noopOIDCService{})
+	shoppingListService := ShoppingListService(noopShoppingListService{})
+	shareLinkService := ShareLinkService(noopShareLinkService{})
 	for _, opt := range opts {
 		switch v := opt.(type) {
 		case EnergyService:
@@ -140,6 +308,26 @@ func New(
 			if v != nil {
 				readinessChecker = v
 			}
+		case MagicLinkService:
+			if v != nil {
+				magicLinkService = v
+			}
+		case PasskeyService:
+			if v != nil {
+				passkeyService = v
+			}
+		case OIDCService:
+			if v != nil {
+				oidcService = v
+			}
+		case ShoppingListService:
+			if v != nil {
+				shoppingListService = v
+			}
+		case ShareLinkService:
+			if v != nil {
+				shareLinkService = v
+			}
 		}
 	}
 
@@ -153,5 +341,10 @@ func New(
 		mealService:          mealService,
 		bodyWeightLogService: bodyWeightLogService,
 		userGoalService:      userGoalService,
+		magicLinkService:     magicLinkService,
+		passkeyService:       passkeyService,
+		oidcService:          oidcService,
+		shoppingListService:  shoppingListService,
+		shareLinkService:     shareLinkService,
 	}
 }
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"goal-bite-api/internal/service"
+)
+
+// maxImageRequestBytes caps the whole multipart body of an image upload so
+// oversized requests are cut off while reading. The services enforce the
+// configured per-image limit, which is lower.
+const maxImageRequestBytes = 32 << 20
+
+// readImageUpload reads the "image" file of a multipart/form-data request.
+// It writes the error response itself and reports false on failure.
+func readImageUpload(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageRequestBytes)
+	if err := r.ParseMultipartForm(maxImageRequestBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "image_too_large", "image too large")
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, "invalid_image_upload", "expected multipart/form-data with an image file")
+		return nil, false
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_image_upload", "expected multipart/form-data with an image file")
+		return nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil || len(data) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_image_upload", "expected multipart/form-data with an image file")
+		return nil, false
+	}
+	return data, true
+}
+
+// writeImageError maps the upload failures shared by every image endpoint.
+func writeImageError(w http.ResponseWriter, err error) bool {
+	return writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidImage, http.StatusBadRequest, "invalid_image", "image could not be decoded"),
+		mapServiceError(service.ErrUnsupportedImageType, http.StatusUnsupportedMediaType, "unsupported_image_type", "only JPEG, PNG and GIF images are supported"),
+		mapServiceError(service.ErrImageTooLarge, http.StatusRequestEntityTooLarge, "image_too_large", "image too large"),
+		mapServiceError(service.ErrImageStorageUnavailable, http.StatusServiceUnavailable, "image_storage_unavailable", "image storage unavailable"),
+	)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
+	"goal-bite-api/internal/domain/recipe"
+	httpmiddleware "goal-bite-api/internal/http/middleware"
+)
+
+// nameLanguages returns the languages food and recipe names are shown and
+// searched in: the caller's saved languages, then the ones the request
+// accepts. A profile that cannot be read only loses the saved languages,
+// since names always fall back to the base name.
+func (h *Handler) nameLanguages(w http.ResponseWriter, r *http.Request) []string {
+	w.Header().Add("Vary", "Accept-Language")
+	accepted := locale.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	userID, ok := httpmiddleware.UserIDFromContext(r.Context())
+	if !ok {
+		return accepted
+	}
+	value, err := h.userService.GetByID(r.Context(), userID)
+	if err != nil {
+		return accepted
+	}
+	return locale.Preferred(value.Languages, accepted)
+}
+
+func localizeFoods(values []food.Food, languages []string) {
+	for i := range values {
+		values[i].Localize(languages)
+	}
+}
+
+func localizeRecipes(values []recipe.Recipe, languages []string) {
+	for i := range values {
+		values[i].Localize(languages)
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"goal-bite-api/internal/domain/meal"
 	"goal-bite-api/internal/domain/mealitem"
+	"goal-bite-api/internal/service"
 )
 
 type mealResponse struct {
@@ -11,6 +12,8 @@ type mealResponse struct {
 	TotalProteinG float64 `json:"total_protein_g"`
 	TotalCarbsG   float64 `json:"total_carbs_g"`
 	TotalFatG     float64 `json:"total_fat_g"`
+	// Breakdown is only set when asked for.
+	Breakdown *service.NutritionBreakdown `json:"breakdown,omitempty"`
 }
 
 func toMealResponse(value meal.Meal) mealResponse {
@@ -18,6 +18,7 @@ import (
 // @Param payload body dto.CreateMealRequest true "Meal payload"
 // @Success 201 {object} MealResponse
 // @Failure 400 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
 // @Router /meals [post]
 func (h *Handler) CreateMeal(w http.ResponseWriter, r *http.Request) {
@@ -51,8 +52,13 @@ func (h *Handler) CreateMeal(w http.ResponseWriter, r *http.Request) {
 		mapServiceError(service.ErrInvalidEatenAt, http.StatusBadRequest, "invalid_meal_payload", "invalid meal payload"),
 		mapServiceError(service.ErrInvalidItemSource, http.StatusBadRequest, "invalid_meal_payload", "invalid meal payload"),
 		mapServiceError(service.ErrInvalidItemWeight, http.StatusBadRequest, "invalid_meal_payload", "invalid meal payload"),
+		mapServiceError(service.ErrInvalidItemServings, http.StatusBadRequest, "invalid_meal_payload", "invalid meal payload"),
+		mapServiceError(service.ErrInvalidCookedWeight, http.StatusBadRequest, "invalid_meal_payload", "invalid meal payload"),
+		mapServiceError(service.ErrCookingMethodNotFound, http.StatusBadRequest, "invalid_cooking_method", "cooking method not found"),
 		mapServiceError(service.ErrFoodNotFound, http.StatusBadRequest, "food_not_found", "food not found"),
 		mapServiceError(service.ErrRecipeSourceNotFound, http.StatusBadRequest, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrFoodArchived, http.StatusConflict, "food_archived", "food is archived"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
 	) {
 		return
 	}
@@ -66,9 +72,13 @@ func (h *Handler) CreateMeal(w http.ResponseWriter, r *http.Request) {
 
 // GetMealByID godoc
 // @Summary Get meal by ID
+// @Description With breakdown=true the response adds what each item contributes, its share of each total and the
+// @Description share of calories from protein, carbs and fat.
 // @Tags meals
 // @Produce json
 // @Param id path int true "Meal ID"
+// @Param breakdown query bool false "Add per-item nutrition"
+// @Param Accept-Language header string false "Languages to show names in, after the caller's saved languages"
 // @Success 200 {object} MealResponse
 // @Failure 400 {object} ErrorEnvelope
 // @Failure 404 {object} ErrorEnvelope
@@ -85,6 +95,15 @@ func (h *Handler) GetMealByID(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_meal_id", "invalid meal id")
 		return
 	}
+	breakdown, ok := parseBreakdown(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_breakdown", "invalid breakdown")
+		return
+	}
+	if breakdown {
+		h.getMealBreakdown(w, r, authUserID, id)
+		return
+	}
 
 	value, err := h.mealService.GetByID(r.Context(), authUserID, id)
 	if writeMappedServiceError(w, err,
@@ -100,6 +119,24 @@ func (h *Handler) GetMealByID(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, toMealResponse(value))
 }
 
+func (h *Handler) getMealBreakdown(w http.ResponseWriter, r *http.Request, authUserID, id uint) {
+	value, breakdown, err := h.mealService.Breakdown(r.Context(), authUserID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrMealNotFound, http.StatusNotFound, "meal_not_found", "meal not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	breakdown.Localize(h.nameLanguages(w, r))
+	out := toMealResponse(value)
+	out.Breakdown = &breakdown
+	writeJSON(w, http.StatusOK, out)
+}
+
 // ListMeals godoc
 // @Summary List meals by user and date
 // @Tags meals
@@ -151,11 +188,13 @@ func (h *Handler) ListMeals(w http.ResponseWriter, r *http.Request) {
 // @Tags meals
 // @Accept json
 // @Produce json
+// @Description Items that break the user's dietary restrictions are still logged; the response lists the conflicts as warnings.
 // @Param id path int true "Meal ID"
 // @Param payload body dto.AddMealItemRequest true "Meal item payload"
 // @Success 201 {object} MealItemResponse
 // @Failure 400 {object} ErrorEnvelope
 // @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
 // @Router /meals/{id}/items [post]
 func (h *Handler) AddMealItem(w http.ResponseWriter, r *http.Request) {
@@ -186,9 +225,14 @@ func (h *Handler) AddMealItem(w http.ResponseWriter, r *http.Request) {
 	if writeMappedServiceError(w, err,
 		mapServiceError(service.ErrInvalidItemSource, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
 		mapServiceError(service.ErrInvalidItemWeight, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
+		mapServiceError(service.ErrInvalidItemServings, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
+		mapServiceError(service.ErrInvalidCookedWeight, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
+		mapServiceError(service.ErrCookingMethodNotFound, http.StatusBadRequest, "invalid_cooking_method", "cooking method not found"),
 		mapServiceError(service.ErrMealNotFound, http.StatusNotFound, "meal_not_found", "meal not found"),
 		mapServiceError(service.ErrFoodNotFound, http.StatusBadRequest, "food_not_found", "food not found"),
 		mapServiceError(service.ErrRecipeSourceNotFound, http.StatusBadRequest, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrFoodArchived, http.StatusConflict, "food_archived", "food is archived"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
 	) {
 		return
 	}
@@ -305,6 +349,7 @@ func (h *Handler) DeleteMeal(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} MealItemResponse
 // @Failure 400 {object} ErrorEnvelope
 // @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
 // @Router /meals/{meal_id}/items/{item_id} [patch]
 func (h *Handler) UpdateMealItem(w http.ResponseWriter, r *http.Request) {
@@ -341,9 +386,14 @@ func (h *Handler) UpdateMealItem(w http.ResponseWriter, r *http.Request) {
 		mapServiceError(service.ErrNoFieldsToUpdate, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
 		mapServiceError(service.ErrInvalidItemSource, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
 		mapServiceError(service.ErrInvalidItemWeight, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
+		mapServiceError(service.ErrInvalidItemServings, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
+		mapServiceError(service.ErrInvalidCookedWeight, http.StatusBadRequest, "invalid_meal_item_payload", "invalid meal item payload"),
+		mapServiceError(service.ErrCookingMethodNotFound, http.StatusBadRequest, "invalid_cooking_method", "cooking method not found"),
 		mapServiceError(service.ErrMealItemNotFound, http.StatusNotFound, "meal_item_not_found", "meal item not found"),
 		mapServiceError(service.ErrFoodNotFound, http.StatusBadRequest, "food_not_found", "food not found"),
 		mapServiceError(service.ErrRecipeSourceNotFound, http.StatusBadRequest, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrFoodArchived, http.StatusConflict, "food_archived", "food is archived"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
 	) {
 		return
 	}
@@ -426,3 +476,84 @@ func (h *Handler) GetDailyTotals(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, value)
 }
+
+// UploadMealImage godoc
+// @Summary Upload meal photo
+// @Description Stores a JPEG, PNG or GIF as a photo of the meal, replacing any previous one, and returns the meal with `image_url` and `thumbnail_url`.
+// @Tags meals
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Meal ID"
+// @Param image formData file true "Image file"
+// @Success 200 {object} MealResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 413 {object} ErrorEnvelope
+// @Failure 415 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Failure 503 {object} ErrorEnvelope
+// @Router /meals/{id}/image [put]
+func (h *Handler) UploadMealImage(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	mealID, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_meal_id", "invalid meal id")
+		return
+	}
+	data, ok := readImageUpload(w, r)
+	if !ok {
+		return
+	}
+
+	value, err := h.mealService.SetImage(r.Context(), authUserID, mealID, data)
+	if writeImageError(w, err) || writeMappedServiceError(w, err,
+		mapServiceError(service.ErrMealNotFound, http.StatusNotFound, "meal_not_found", "meal not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toMealResponse(value))
+}
+
+// DeleteMealImage godoc
+// @Summary Remove meal photo
+// @Tags meals
+// @Param id path int true "Meal ID"
+// @Success 204
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /meals/{id}/image [delete]
+func (h *Handler) DeleteMealImage(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	mealID, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_meal_id", "invalid meal id")
+		return
+	}
+
+	_, err := h.mealService.RemoveImage(r.Context(), authUserID, mealID)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrMealNotFound, http.StatusNotFound, "meal_not_found", "meal not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"goal-bite-api/internal/http/dto"
+	"goal-bite-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListOIDCProviders godoc
+// @Summary List external sign-in providers
+// @Tags auth
+// @Produce json
+// @Success 200 {array} string
+// @Router /auth/oidc/providers [get]
+func (h *Handler) ListOIDCProviders(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.oidcService.Providers())
+}
+
+// BeginOIDCLogin godoc
+// @Summary Start external provider sign-in
+// @Description Returns the provider authorization URL (authorization code flow with PKCE). The provider redirects back to the configured client page with `code` and `state`.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} service.OIDCLoginStart
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 429 {object} ErrorEnvelope
+// @Failure 502 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/oidc/{provider}/begin [post]
+func (h *Handler) BeginOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	start, err := h.oidcService.BeginLogin(r.Context(), chi.URLParam(r, "provider"))
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrOIDCProviderNotFound, http.StatusNotFound, "oidc_provider_not_found", "oidc provider not found"),
+		mapServiceError(service.ErrOIDCProviderUnavailable, http.StatusBadGateway, "oidc_provider_unavailable", "oidc provider unavailable"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, start)
+}
+
+// FinishOIDCLogin godoc
+// @Summary Complete external provider sign-in
+// @Description Exchanges the callback code for session tokens. Links the identity to the account with the same verified email, or creates one.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param payload body dto.OIDCFinishRequest true "Provider callback parameters"
+// @Success 200 {object} service.AuthResult
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 429 {object} ErrorEnvelope
+// @Failure 502 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/oidc/{provider}/finish [post]
+func (h *Handler) FinishOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	var req dto.OIDCFinishRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_oidc_payload", "invalid oidc payload")
+		return
+	}
+
+	result, err := h.oidcService.FinishLogin(r.Context(), chi.URLParam(r, "provider"), req.Code, req.State)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrOIDCProviderNotFound, http.StatusNotFound, "oidc_provider_not_found", "oidc provider not found"),
+		mapServiceError(service.ErrInvalidOIDCState, http.StatusUnauthorized, "invalid_oidc_state", "invalid or expired oidc state"),
+		mapServiceError(service.ErrOIDCLoginFailed, http.StatusUnauthorized, "oidc_login_failed", "oidc login failed"),
+		mapServiceError(service.ErrOIDCEmailNotVerified, http.StatusForbidden, "oidc_email_not_verified", "provider did not verify the email address"),
+		mapServiceError(service.ErrOIDCProviderUnavailable, http.StatusBadGateway, "oidc_provider_unavailable", "oidc provider unavailable"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
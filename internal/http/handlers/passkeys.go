@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"goal-bite-api/internal/http/dto"
+	"goal-bite-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// BeginPasskeyRegistration godoc
+// @Summary Start passkey registration
+// @Description Returns options for navigator.credentials.create(). The challenge is valid for one registration.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} webauthn.CreationOptions
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/passkeys/register/begin [post]
+func (h *Handler) BeginPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	options, err := h.passkeyService.BeginRegistration(r.Context(), userID)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrUserNotFound, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, options)
+}
+
+// FinishPasskeyRegistration godoc
+// @Summary Complete passkey registration
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param payload body dto.PasskeyRegistrationRequest true "Passkey registration payload"
+// @Success 201 {object} PasskeyResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/passkeys/register/finish [post]
+func (h *Handler) FinishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.PasskeyRegistrationRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_passkey_payload", "invalid passkey payload")
+		return
+	}
+
+	value, err := h.passkeyService.FinishRegistration(r.Context(), userID, req.ToServiceInput())
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrInvalidPasskeyName, http.StatusBadRequest, "invalid_passkey_payload", "invalid passkey payload"),
+		mapServiceError(service.ErrInvalidPasskeyResponse, http.StatusBadRequest, "passkey_verification_failed", "passkey verification failed"),
+		mapServiceError(service.ErrPasskeyAlreadyRegistered, http.StatusConflict, "passkey_already_registered", "passkey already registered"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, value)
+}
+
+// ListPasskeys godoc
+// @Summary List my passkeys
+// @Tags auth
+// @Produce json
+// @Success 200 {array} PasskeyResponse
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/passkeys [get]
+func (h *Handler) ListPasskeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	values, err := h.passkeyService.List(r.Context(), userID)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, values)
+}
+
+// DeletePasskey godoc
+// @Summary Remove passkey
+// @Tags auth
+// @Param id path int true "Passkey ID"
+// @Success 204
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/passkeys/{id} [delete]
+func (h *Handler) DeletePasskey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_passkey_id", "invalid passkey id")
+		return
+	}
+
+	err := h.passkeyService.Delete(r.Context(), userID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrPasskeyNotFound, http.StatusNotFound, "passkey_not_found", "passkey not found"),
+		mapServiceError(service.ErrPasskeyForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BeginPasskeyLogin godoc
+// @Summary Start passkey login
+// @Description Returns options for navigator.credentials.get() using discoverable credentials.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} webauthn.RequestOptions
+// @Failure 429 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/passkeys/login/begin [post]
+func (h *Handler) BeginPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	options, err := h.passkeyService.BeginLogin(r.Context())
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, options)
+}
+
+// FinishPasskeyLogin godoc
+// @Summary Complete passkey login
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param payload body dto.PasskeyLoginRequest true "Passkey assertion payload"
+// @Success 200 {object} service.AuthResult
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 429 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /auth/passkeys/login/finish [post]
+func (h *Handler) FinishPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	var req dto.PasskeyLoginRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_passkey_payload", "invalid passkey payload")
+		return
+	}
+
+	result, err := h.passkeyService.FinishLogin(r.Context(), req.Credential)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrPasskeyLoginFailed, http.StatusUnauthorized, "invalid_credentials", "invalid credentials"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// parseIncludeArchived reads the include_archived flag. A missing value means
+// false.
+func parseIncludeArchived(r *http.Request) (bool, bool) {
+	return parseBoolQuery(r, "include_archived")
+}
+
+// parseExcludeConflicts reads the exclude_conflicts flag, which hides items
+// that break the caller's dietary restrictions. A missing value means false.
+func parseExcludeConflicts(r *http.Request) (bool, bool) {
+	return parseBoolQuery(r, "exclude_conflicts")
+}
+
+func parseBoolQuery(r *http.Request, name string) (bool, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get(name))
+	if raw == "" {
+		return false, true
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, false
+	}
+	return v, true
+}
+
+// parseBreakdown reads the breakdown flag, which adds per-ingredient or
+// per-item nutrition to a recipe or meal. A missing value means false.
+func parseBreakdown(r *http.Request) (bool, bool) {
+	return parseBoolQuery(r, "breakdown")
+}
+
+// parseStrictNutrition reads the validation mode for food writes: "lenient"
+// (the default) returns nutrition warnings, "strict" rejects the write.
+func parseStrictNutrition(r *http.Request) (bool, bool) {
+	switch strings.TrimSpace(r.URL.Query().Get("validation")) {
+	case "", "lenient":
+		return false, true
+	case "strict":
+		return true, true
+	}
+	return false, false
+}
+
+// parseServings reads the required servings count of a scaled recipe.
+// Range checks are left to the service.
+func parseServings(r *http.Request) (int, bool) {
+	v, err := strconv.Atoi(strings.TrimSpace(r.URL.Query().Get("servings")))
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// parseMaxTotalTime reads the optional max_total_time recipe filter in
+// minutes. A missing value means no limit.
+func parseMaxTotalTime(r *http.Request) (int, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("max_total_time"))
+	if raw == "" {
+		return 0, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+// parseTextFormat reads the format of an exported shopping list: "json" (the
+// default) or "text".
+func parseTextFormat(r *http.Request) (bool, bool) {
+	switch strings.TrimSpace(r.URL.Query().Get("format")) {
+	case "", "json":
+		return false, true
+	case "text":
+		return true, true
+	}
+	return false, false
+}
@@ -2,8 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strings"
 
 	"goal-bite-api/internal/domain/recipe"
 	"goal-bite-api/internal/http/dto"
@@ -12,6 +12,10 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+// maxRecipeImportRequestBytes caps the JSON body of a recipe import. It
+// leaves room for escaping on top of dto.MaxRecipeImportBytes of content.
+const maxRecipeImportRequestBytes = 2 * dto.MaxRecipeImportBytes
+
 // CreateRecipe godoc
 // @Summary Create recipe
 // @Tags recipes
@@ -20,6 +24,7 @@ import (
 // @Param payload body dto.CreateRecipeRequest true "Recipe payload"
 // @Success 201 {object} RecipeResponse
 // @Failure 400 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
 // @Router /recipes [post]
 func (h *Handler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
@@ -44,9 +49,21 @@ func (h *Handler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	if writeMappedServiceError(w, err,
 		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
 		mapServiceError(service.ErrInvalidRecipeName, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidTranslations, http.StatusBadRequest, "invalid_translations", "invalid translations"),
 		mapServiceError(service.ErrInvalidYieldWeight, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidServings, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidServingName, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidRecipeDescription, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidRecipeTime, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidInstructions, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidRecipeTags, http.StatusBadRequest, "invalid_recipe_tags", "invalid recipe tags"),
 		mapServiceError(service.ErrInvalidRecipeIngredients, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrCookingMethodNotFound, http.StatusBadRequest, "invalid_cooking_method", "cooking method not found"),
 		mapServiceError(service.ErrIngredientFoodNotFound, http.StatusBadRequest, "ingredient_food_not_found", "ingredient food not found"),
+		mapServiceError(service.ErrIngredientFoodArchived, http.StatusConflict, "ingredient_food_archived", "ingredient food is archived"),
+		mapServiceError(service.ErrIngredientRecipeNotFound, http.StatusBadRequest, "ingredient_recipe_not_found", "ingredient recipe not found"),
+		mapServiceError(service.ErrIngredientRecipeArchived, http.StatusConflict, "ingredient_recipe_archived", "ingredient recipe is archived"),
+		mapServiceError(service.ErrRecipeTooDeep, http.StatusBadRequest, "recipe_too_deep", "sub-recipes are nested too deep"),
 	) {
 		return
 	}
@@ -55,14 +72,87 @@ func (h *Handler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	value.Localize(h.nameLanguages(w, r))
 	writeJSON(w, http.StatusCreated, value)
 }
 
+// ImportRecipe godoc
+// @Summary Import recipe draft
+// @Description Parses a web page with schema.org Recipe JSON-LD, the JSON-LD itself, or a pasted ingredient list with one
+// @Description ingredient per line, such as "200 g chicken breast" or "2 tbsp olive oil". Each ingredient gets up to three
+// @Description catalog foods with a confidence between 0 and 1, and `food_id` when the best one is confident enough.
+// @Description Volumes are converted to grams as water, so `approximate_weight` marks them for review. Nothing is saved:
+// @Description the confirmed draft is sent to `POST /recipes`.
+// @Tags recipes
+// @Accept json
+// @Produce json
+// @Param payload body dto.ImportRecipeRequest true "Recipe import payload"
+// @Param Accept-Language header string false "Languages to show food names in, after the caller's saved languages"
+// @Success 200 {object} RecipeDraftResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/import [post]
+func (h *Handler) ImportRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.ImportRecipeRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRecipeImportRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusBadRequest, "invalid_recipe_import", "invalid recipe import")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_import", "invalid recipe import")
+		return
+	}
+
+	value, err := h.recipeService.Import(r.Context(), userID, req.Content)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrInvalidRecipeImport, http.StatusBadRequest, "invalid_recipe_import", "no recipe ingredients found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	languages := h.nameLanguages(w, r)
+	for i := range value.Ingredients {
+		for j := range value.Ingredients[i].Matches {
+			value.Ingredients[i].Matches[j].Food.Localize(languages)
+		}
+	}
+	writeJSON(w, http.StatusOK, value)
+}
+
+// recipeBreakdownResponse is a recipe with the nutrition each ingredient
+// contributes.
+type recipeBreakdownResponse struct {
+	recipe.Recipe
+	Breakdown service.NutritionBreakdown `json:"breakdown"`
+}
+
 // GetRecipeByID godoc
 // @Summary Get recipe by ID
+// @Description With breakdown=true the response adds what each ingredient contributes to the whole batch, its share of
+// @Description each total and the share of calories from protein, carbs and fat.
 // @Tags recipes
 // @Produce json
 // @Param id path int true "Recipe ID"
+// @Param breakdown query bool false "Add per-ingredient nutrition"
+// @Param Accept-Language header string false "Languages to show names in, after the caller's saved languages"
 // @Success 200 {object} RecipeResponse
 // @Failure 400 {object} ErrorEnvelope
 // @Failure 404 {object} ErrorEnvelope
@@ -74,6 +164,15 @@ func (h *Handler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
 		return
 	}
+	breakdown, ok := parseBreakdown(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_breakdown", "invalid breakdown")
+		return
+	}
+	if breakdown {
+		h.getRecipeBreakdown(w, r, id)
+		return
+	}
 
 	value, err := h.recipeService.GetByID(r.Context(), id)
 	if writeMappedServiceError(w, err,
@@ -86,6 +185,72 @@ func (h *Handler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+func (h *Handler) getRecipeBreakdown(w http.ResponseWriter, r *http.Request, id uint) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	value, breakdown, err := h.recipeService.Breakdown(r.Context(), authUserID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	languages := h.nameLanguages(w, r)
+	value.Localize(languages)
+	breakdown.Localize(languages)
+	writeJSON(w, http.StatusOK, recipeBreakdownResponse{Recipe: value, Breakdown: breakdown})
+}
+
+// ScaleRecipe godoc
+// @Summary Scale recipe to servings
+// @Description Returns the recipe resized to the given number of servings: the yield, ingredient weights and per-batch
+// @Description nutrition scale proportionally while per-100g and per-serving values stay the same. Nothing is saved.
+// @Tags recipes
+// @Produce json
+// @Param id path int true "Recipe ID"
+// @Param servings query int true "Number of servings (1-1000)"
+// @Param Accept-Language header string false "Languages to show names in, after the caller's saved languages"
+// @Success 200 {object} RecipeResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/{id}/scaled [get]
+func (h *Handler) ScaleRecipe(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+	servings, ok := parseServings(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_servings", "invalid servings")
+		return
+	}
+
+	value, err := h.recipeService.Scale(r.Context(), id, servings)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidServings, http.StatusBadRequest, "invalid_servings", "invalid servings"),
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
 	writeJSON(w, http.StatusOK, value)
 }
 
@@ -93,7 +258,14 @@ func (h *Handler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 // @Summary List recipes
 // @Tags recipes
 // @Produce json
-// @Param q query string false "Search by recipe name (case-insensitive, partial match)"
+// @Description Archived recipes are skipped unless include_archived is set. With exclude_conflicts, recipes whose
+// @Description ingredients break the caller's dietary restrictions are skipped too.
+// @Param q query string false "Search by recipe name, or by translated name or synonym in the caller's languages (case-insensitive, partial match)"
+// @Param tag query []string false "Only recipes carrying every given tag" collectionFormat(multi)
+// @Param max_total_time query int false "Only recipes whose stated prep and cook times add up to at most this many minutes"
+// @Param Accept-Language header string false "Languages to show and search names in, after the caller's saved languages"
+// @Param include_archived query bool false "Include archived recipes"
+// @Param exclude_conflicts query bool false "Skip recipes conflicting with the caller's allergens and diet flags"
 // @Param limit query int false "Page size (default 20, max 100)"
 // @Param offset query int false "Page offset (default 0)"
 // @Success 200 {array} RecipeResponse
@@ -101,24 +273,47 @@ func (h *Handler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} ErrorEnvelope
 // @Router /recipes [get]
 func (h *Handler) ListRecipes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
 	limit, offset, ok := parsePagination(r)
 	if !ok {
 		writeError(w, http.StatusBadRequest, "invalid_pagination", "invalid pagination")
 		return
 	}
-	query := strings.TrimSpace(r.URL.Query().Get("q"))
-
-	var (
-		values []recipe.Recipe
-		err    error
-	)
-	if query == "" {
-		values, err = h.recipeService.List(r.Context(), limit, offset)
-	} else {
-		values, err = h.recipeService.Search(r.Context(), query, limit, offset)
+	includeArchived, ok := parseIncludeArchived(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_include_archived", "invalid include_archived")
+		return
+	}
+	excludeConflicts, ok := parseExcludeConflicts(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_exclude_conflicts", "invalid exclude_conflicts")
+		return
+	}
+	maxTotalTime, ok := parseMaxTotalTime(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_max_total_time", "invalid max_total_time")
+		return
 	}
+
+	languages := h.nameLanguages(w, r)
+
+	values, err := h.recipeService.List(r.Context(), userID, service.RecipeListInput{
+		Query:            r.URL.Query().Get("q"),
+		Languages:        languages,
+		Tags:             r.URL.Query()["tag"],
+		MaxTotalTimeMin:  maxTotalTime,
+		IncludeArchived:  includeArchived,
+		ExcludeConflicts: excludeConflicts,
+		Limit:            limit,
+		Offset:           offset,
+	})
 	if writeMappedServiceError(w, err,
 		mapServiceError(service.ErrInvalidPagination, http.StatusBadRequest, "invalid_pagination", "invalid pagination"),
+		mapServiceError(service.ErrInvalidRecipeTags, http.StatusBadRequest, "invalid_recipe_tags", "invalid recipe tags"),
+		mapServiceError(service.ErrInvalidRecipeTime, http.StatusBadRequest, "invalid_max_total_time", "invalid max_total_time"),
 	) {
 		return
 	}
@@ -127,6 +322,7 @@ func (h *Handler) ListRecipes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	localizeRecipes(values, languages)
 	writeJSON(w, http.StatusOK, values)
 }
 
@@ -140,6 +336,7 @@ func (h *Handler) ListRecipes(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} RecipeResponse
 // @Failure 400 {object} ErrorEnvelope
 // @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
 // @Failure 500 {object} ErrorEnvelope
 // @Router /recipes/{id} [patch]
 func (h *Handler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
@@ -171,11 +368,25 @@ func (h *Handler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
 		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
 		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
 		mapServiceError(service.ErrRecipeForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
 		mapServiceError(service.ErrInvalidRecipeName, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidTranslations, http.StatusBadRequest, "invalid_translations", "invalid translations"),
 		mapServiceError(service.ErrInvalidYieldWeight, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidServings, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidServingName, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidRecipeDescription, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidRecipeTime, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidInstructions, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrInvalidRecipeTags, http.StatusBadRequest, "invalid_recipe_tags", "invalid recipe tags"),
 		mapServiceError(service.ErrInvalidRecipeIngredients, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
+		mapServiceError(service.ErrCookingMethodNotFound, http.StatusBadRequest, "invalid_cooking_method", "cooking method not found"),
 		mapServiceError(service.ErrNoFieldsToUpdate, http.StatusBadRequest, "invalid_recipe_payload", "invalid recipe payload"),
 		mapServiceError(service.ErrIngredientFoodNotFound, http.StatusBadRequest, "ingredient_food_not_found", "ingredient food not found"),
+		mapServiceError(service.ErrIngredientFoodArchived, http.StatusConflict, "ingredient_food_archived", "ingredient food is archived"),
+		mapServiceError(service.ErrIngredientRecipeNotFound, http.StatusBadRequest, "ingredient_recipe_not_found", "ingredient recipe not found"),
+		mapServiceError(service.ErrIngredientRecipeArchived, http.StatusConflict, "ingredient_recipe_archived", "ingredient recipe is archived"),
+		mapServiceError(service.ErrRecipeCycle, http.StatusBadRequest, "recipe_cycle", "recipe cannot contain itself"),
+		mapServiceError(service.ErrRecipeTooDeep, http.StatusBadRequest, "recipe_too_deep", "sub-recipes are nested too deep"),
 	) {
 		return
 	}
@@ -184,11 +395,13 @@ func (h *Handler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	value.Localize(h.nameLanguages(w, r))
 	writeJSON(w, http.StatusOK, value)
 }
 
 // DeleteRecipe godoc
 // @Summary Delete recipe
+// @Description Archives the recipe. It disappears from lists and search but still resolves by ID for meal items that use it.
 // @Tags recipes
 // @Produce json
 // @Param id path int true "Recipe ID"
@@ -226,3 +439,304 @@ func (h *Handler) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// RestoreRecipe godoc
+// @Summary Restore archived recipe
+// @Tags recipes
+// @Produce json
+// @Param id path int true "Recipe ID"
+// @Success 200 {object} RecipeResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/{id}/restore [post]
+func (h *Handler) RestoreRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+
+	value, err := h.recipeService.Restore(r.Context(), userID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrRecipeForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+// RecalculateRecipe godoc
+// @Summary Recalculate recipe
+// @Description Recomputes the recipe's per-100g values from the current values of its ingredient foods and clears `stale`. Only the owner may recalculate it.
+// @Tags recipes
+// @Produce json
+// @Param id path int true "Recipe ID"
+// @Success 200 {object} RecipeResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/{id}/recalculate [post]
+func (h *Handler) RecalculateRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+
+	value, err := h.recipeService.Refresh(r.Context(), userID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrRecipeForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+// ForkRecipe godoc
+// @Summary Fork recipe
+// @Description Copies the recipe, with its ingredients, details and translations, into a new recipe owned by the caller,
+// @Description which records the original in `forked_from_id`. The copy keeps every ingredient of the original and is
+// @Description recalculated from their current values. Images are not copied.
+// @Tags recipes
+// @Produce json
+// @Param id path int true "Recipe ID"
+// @Success 201 {object} RecipeResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/{id}/fork [post]
+func (h *Handler) ForkRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+
+	value, err := h.recipeService.Fork(r.Context(), userID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusCreated, value)
+}
+
+// ListRecipeForks godoc
+// @Summary List recipe forks
+// @Description Lists the active recipes forked from this one, oldest first, each with what it changed relative to it.
+// @Tags recipes
+// @Produce json
+// @Param id path int true "Recipe ID"
+// @Param Accept-Language header string false "Languages to show names in, after the caller's saved languages"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Page offset (default 0)"
+// @Success 200 {array} RecipeForkResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/{id}/forks [get]
+func (h *Handler) ListRecipeForks(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_pagination", "invalid pagination")
+		return
+	}
+
+	values, err := h.recipeService.Forks(r.Context(), id, limit, offset)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidPagination, http.StatusBadRequest, "invalid_pagination", "invalid pagination"),
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	languages := h.nameLanguages(w, r)
+	for i := range values {
+		values[i].Recipe.Localize(languages)
+	}
+	writeJSON(w, http.StatusOK, values)
+}
+
+// GetRecipeChanges godoc
+// @Summary Compare fork with original
+// @Description Shows what a forked recipe changed relative to the recipe it was copied from: changed fields, added,
+// @Description removed and changed ingredients, and the difference in per-100g values.
+// @Tags recipes
+// @Produce json
+// @Param id path int true "Recipe ID"
+// @Success 200 {object} RecipeChangesResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/{id}/changes [get]
+func (h *Handler) GetRecipeChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+
+	value, err := h.recipeService.Changes(r.Context(), id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrRecipeNotForked, http.StatusNotFound, "recipe_not_forked", "recipe is not a fork"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, value)
+}
+
+// UploadRecipeImage godoc
+// @Summary Upload recipe image
+// @Description Stores a JPEG, PNG or GIF as the recipe's image, replacing any previous one, and returns the recipe with `image_url` and `thumbnail_url`. Only the owner may change it.
+// @Tags recipes
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Recipe ID"
+// @Param image formData file true "Image file"
+// @Success 200 {object} RecipeResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 413 {object} ErrorEnvelope
+// @Failure 415 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Failure 503 {object} ErrorEnvelope
+// @Router /recipes/{id}/image [put]
+func (h *Handler) UploadRecipeImage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+	data, ok := readImageUpload(w, r)
+	if !ok {
+		return
+	}
+
+	value, err := h.recipeService.SetImage(r.Context(), userID, id, data)
+	if writeImageError(w, err) || writeRecipeImageError(w, err) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusOK, value)
+}
+
+// DeleteRecipeImage godoc
+// @Summary Remove recipe image
+// @Tags recipes
+// @Param id path int true "Recipe ID"
+// @Success 204
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 401 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/{id}/image [delete]
+func (h *Handler) DeleteRecipeImage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+
+	_, err := h.recipeService.RemoveImage(r.Context(), userID, id)
+	if writeRecipeImageError(w, err) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeRecipeImageError(w http.ResponseWriter, err error) bool {
+	return writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusUnauthorized, "unauthorized", "unauthorized"),
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrRecipeForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
+	)
+}
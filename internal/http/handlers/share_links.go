@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"goal-bite-api/internal/http/dto"
+	"goal-bite-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ShareRecipe godoc
+// @Summary Share recipe
+// @Tags share-links
+// @Accept json
+// @Produce json
+// @Description Makes a link that shows the recipe and its nutrition to anyone holding the token, without signing in. The token is only returned here. Only the recipe's owner can share it.
+// @Param id path int true "Recipe ID"
+// @Param payload body dto.CreateShareLinkRequest false "Share link payload"
+// @Success 201 {object} ShareLinkResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 403 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /recipes/{id}/share-links [post]
+func (h *Handler) ShareRecipe(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_recipe_id", "invalid recipe id")
+		return
+	}
+	req, ok := decodeShareLinkRequest(w, r)
+	if !ok {
+		return
+	}
+
+	value, err := h.shareLinkService.ShareRecipe(r.Context(), authUserID, id, req.ToServiceInput())
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusBadRequest, "invalid_share_link_payload", "invalid share link payload"),
+		mapServiceError(service.ErrInvalidShareLinkExpiry, http.StatusBadRequest, "invalid_share_link_payload", "invalid share link payload"),
+		mapServiceError(service.ErrRecipeNotFound, http.StatusNotFound, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrRecipeForbidden, http.StatusForbidden, "forbidden", "forbidden"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, value)
+}
+
+// ShareMeal godoc
+// @Summary Share meal
+// @Tags share-links
+// @Accept json
+// @Produce json
+// @Description Makes a link that shows the meal and its nutrition to anyone holding the token, without signing in. The token is only returned here.
+// @Param id path int true "Meal ID"
+// @Param payload body dto.CreateShareLinkRequest false "Share link payload"
+// @Success 201 {object} ShareLinkResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /meals/{id}/share-links [post]
+func (h *Handler) ShareMeal(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_meal_id", "invalid meal id")
+		return
+	}
+	req, ok := decodeShareLinkRequest(w, r)
+	if !ok {
+		return
+	}
+
+	value, err := h.shareLinkService.ShareMeal(r.Context(), authUserID, id, req.ToServiceInput())
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusBadRequest, "invalid_share_link_payload", "invalid share link payload"),
+		mapServiceError(service.ErrInvalidShareLinkExpiry, http.StatusBadRequest, "invalid_share_link_payload", "invalid share link payload"),
+		mapServiceError(service.ErrMealNotFound, http.StatusNotFound, "meal_not_found", "meal not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, value)
+}
+
+// decodeShareLinkRequest reads the optional share link payload; an empty
+// body asks for a link that never expires.
+func decodeShareLinkRequest(w http.ResponseWriter, r *http.Request) (dto.CreateShareLinkRequest, bool) {
+	var req dto.CreateShareLinkRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return dto.CreateShareLinkRequest{}, false
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_share_link_payload", "invalid share link payload")
+		return dto.CreateShareLinkRequest{}, false
+	}
+	return req, true
+}
+
+// ListShareLinks godoc
+// @Summary List share links
+// @Tags share-links
+// @Produce json
+// @Description Newest first, revoked and expired links included, with how often each was opened. Tokens are not returned.
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Page offset (default 0)"
+// @Success 200 {array} ShareLinkResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /share-links [get]
+func (h *Handler) ListShareLinks(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_pagination", "invalid pagination")
+		return
+	}
+
+	values, err := h.shareLinkService.List(r.Context(), authUserID, limit, offset)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusBadRequest, "invalid_share_link_query", "invalid share link query"),
+		mapServiceError(service.ErrInvalidPagination, http.StatusBadRequest, "invalid_pagination", "invalid pagination"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, values)
+}
+
+// RevokeShareLink godoc
+// @Summary Revoke share link
+// @Tags share-links
+// @Description The link stops opening at once. It stays in the list, with its access count.
+// @Param id path int true "Share link ID"
+// @Success 204
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /share-links/{id} [delete]
+func (h *Handler) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_share_link_id", "invalid share link id")
+		return
+	}
+
+	err := h.shareLinkService.Revoke(r.Context(), authUserID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrShareLinkNotFound, http.StatusNotFound, "share_link_not_found", "share link not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSharedContent godoc
+// @Summary Open share link
+// @Tags share-links
+// @Produce json
+// @Description Public, no sign-in needed. Shows the shared recipe or meal read-only with the nutrition of each ingredient or item, and nothing about its owner. Each call that opens the link counts as an access. Unknown, revoked and expired links, and links to deleted recipes or meals, are all not found.
+// @Param token path string true "Share link token"
+// @Param Accept-Language header string false "Languages to show names in"
+// @Success 200 {object} SharedViewResponse
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /public/shared/{token} [get]
+func (h *Handler) GetSharedContent(w http.ResponseWriter, r *http.Request) {
+	value, err := h.shareLinkService.Open(r.Context(), chi.URLParam(r, "token"))
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrShareLinkNotFound, http.StatusNotFound, "share_link_not_found", "share link not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	// Every open is counted, so caches must not answer for the API.
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, value)
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"goal-bite-api/internal/http/dto"
+	"goal-bite-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CreateShoppingList godoc
+// @Summary Create shopping list
+// @Tags shopping-lists
+// @Accept json
+// @Produce json
+// @Description Adds up the raw ingredient weights of either the given recipes, scaled to the requested servings, or the meals eaten from `from` to `to` (at most 31 days). Sub-recipes are expanded into their foods, and each food appears once.
+// @Param payload body dto.CreateShoppingListRequest true "Shopping list payload"
+// @Success 201 {object} ShoppingListResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 409 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /shopping-lists [post]
+func (h *Handler) CreateShoppingList(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateShoppingListRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_shopping_list_payload", "invalid shopping list payload")
+		return
+	}
+
+	value, err := h.shoppingListService.Create(r.Context(), authUserID, req.ToServiceInput())
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusBadRequest, "invalid_shopping_list_payload", "invalid shopping list payload"),
+		mapServiceError(service.ErrInvalidShoppingListName, http.StatusBadRequest, "invalid_shopping_list_payload", "invalid shopping list payload"),
+		mapServiceError(service.ErrInvalidShoppingListSource, http.StatusBadRequest, "invalid_shopping_list_payload", "invalid shopping list payload"),
+		mapServiceError(service.ErrInvalidDateRange, http.StatusBadRequest, "invalid_shopping_list_payload", "invalid shopping list payload"),
+		mapServiceError(service.ErrRecipeSourceNotFound, http.StatusBadRequest, "recipe_not_found", "recipe not found"),
+		mapServiceError(service.ErrRecipeTooDeep, http.StatusBadRequest, "recipe_too_deep", "sub-recipes are nested too deep"),
+		mapServiceError(service.ErrRecipeArchived, http.StatusConflict, "recipe_archived", "recipe is archived"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	writeJSON(w, http.StatusCreated, value)
+}
+
+// ListShoppingLists godoc
+// @Summary List shopping lists
+// @Tags shopping-lists
+// @Produce json
+// @Description Newest first, without their items.
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Page offset (default 0)"
+// @Success 200 {array} ShoppingListResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /shopping-lists [get]
+func (h *Handler) ListShoppingLists(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := parsePagination(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_pagination", "invalid pagination")
+		return
+	}
+
+	values, err := h.shoppingListService.List(r.Context(), authUserID, limit, offset)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusBadRequest, "invalid_shopping_list_query", "invalid shopping list query"),
+		mapServiceError(service.ErrInvalidPagination, http.StatusBadRequest, "invalid_pagination", "invalid pagination"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, values)
+}
+
+// GetShoppingList godoc
+// @Summary Get shopping list
+// @Tags shopping-lists
+// @Produce json
+// @Produce plain
+// @Description With format=text the list is exported as plain text, one line per item with a checkbox and the weight in grams.
+// @Param id path int true "Shopping list ID"
+// @Param format query string false "Response format" Enums(json, text)
+// @Success 200 {object} ShoppingListResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /shopping-lists/{id} [get]
+func (h *Handler) GetShoppingList(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_shopping_list_id", "invalid shopping list id")
+		return
+	}
+	text, ok := parseTextFormat(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_shopping_list_query", "invalid shopping list query")
+		return
+	}
+
+	value, err := h.shoppingListService.GetByID(r.Context(), authUserID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusBadRequest, "invalid_shopping_list_query", "invalid shopping list query"),
+		mapServiceError(service.ErrShoppingListNotFound, http.StatusNotFound, "shopping_list_not_found", "shopping list not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	value.Localize(h.nameLanguages(w, r))
+	if text {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(value.Text()))
+		return
+	}
+	writeJSON(w, http.StatusOK, value)
+}
+
+// UpdateShoppingListItem godoc
+// @Summary Check or uncheck shopping list item
+// @Tags shopping-lists
+// @Accept json
+// @Produce json
+// @Param id path int true "Shopping list ID"
+// @Param item_id path int true "Shopping list item ID"
+// @Param payload body dto.UpdateShoppingListItemRequest true "Shopping list item update payload"
+// @Success 200 {object} ShoppingListItemResponse
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /shopping-lists/{id}/items/{item_id} [patch]
+func (h *Handler) UpdateShoppingListItem(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_shopping_list_id", "invalid shopping list id")
+		return
+	}
+	itemID, ok := parseIDFromPath(chi.URLParam(r, "item_id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_shopping_list_item_id", "invalid shopping list item id")
+		return
+	}
+
+	var req dto.UpdateShoppingListItemRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_body", "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_shopping_list_item_payload", "invalid shopping list item payload")
+		return
+	}
+
+	value, err := h.shoppingListService.SetItemChecked(r.Context(), authUserID, listID, itemID, *req.Checked)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrInvalidUserID, http.StatusBadRequest, "invalid_shopping_list_item_payload", "invalid shopping list item payload"),
+		mapServiceError(service.ErrShoppingListItemNotFound, http.StatusNotFound, "shopping_list_item_not_found", "shopping list item not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, value)
+}
+
+// DeleteShoppingList godoc
+// @Summary Delete shopping list
+// @Tags shopping-lists
+// @Param id path int true "Shopping list ID"
+// @Success 204
+// @Failure 400 {object} ErrorEnvelope
+// @Failure 404 {object} ErrorEnvelope
+// @Failure 500 {object} ErrorEnvelope
+// @Router /shopping-lists/{id} [delete]
+func (h *Handler) DeleteShoppingList(w http.ResponseWriter, r *http.Request) {
+	authUserID, ok := requireAuthUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDFromPath(chi.URLParam(r, "id"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_shopping_list_id", "invalid shopping list id")
+		return
+	}
+
+	err := h.shoppingListService.Delete(r.Context(), authUserID, id)
+	if writeMappedServiceError(w, err,
+		mapServiceError(service.ErrShoppingListNotFound, http.StatusNotFound, "shopping_list_not_found", "shopping list not found"),
+	) {
+		return
+	}
+	if err != nil {
+		writeDatabaseError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
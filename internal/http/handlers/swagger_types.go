@@ -24,6 +24,33 @@ type UserResponse struct {
 	HeightCM *float64 `json:"height_cm,omitempty" example:"178"`
 	// Optional activity level.
 	ActivityLevel *string `json:"activity_level,omitempty" example:"moderate"`
+	// Allergens the user avoids.
+	AvoidAllergens []string `json:"avoid_allergens" example:"peanuts"`
+	// Diet flags every logged item should carry.
+	RequiredDietFlags []string `json:"required_diet_flags" example:"vegetarian"`
+	// Languages food and recipe names are shown in, most preferred first.
+	Languages []string `json:"languages" example:"de,en"`
+	// Creation timestamp in RFC3339 UTC.
+	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
+	// Last update timestamp in RFC3339 UTC.
+	UpdatedAt time.Time `json:"updated_at" example:"2026-02-17T12:00:00Z"`
+}
+
+type PasskeyResponse struct {
+	// Passkey ID.
+	ID uint `json:"id" example:"1"`
+	// Owner user ID.
+	UserID uint `json:"user_id" example:"1"`
+	// Label chosen at registration.
+	Name string `json:"name" example:"MacBook Touch ID"`
+	// WebAuthn credential ID (base64url).
+	CredentialID string `json:"credential_id" example:"q2xKcL8zTQ6m0mC3b3Vx1g"`
+	// Last signature counter reported by the authenticator.
+	SignCount uint32 `json:"sign_count" example:"4"`
+	// Transports reported by the browser at registration.
+	Transports []string `json:"transports" example:"internal,hybrid"`
+	// Last successful login timestamp in RFC3339 UTC.
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2026-02-17T12:00:00Z"`
 	// Creation timestamp in RFC3339 UTC.
 	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
 	// Last update timestamp in RFC3339 UTC.
@@ -35,6 +62,10 @@ type FoodResponse struct {
 	ID uint `json:"id" example:"1"`
 	// Food name.
 	Name string `json:"name" example:"Rice"`
+	// Name in the first of the caller's languages the food is translated into, else name.
+	DisplayName string `json:"display_name" example:"Reis"`
+	// Names in other languages, sorted by locale.
+	Translations []TranslationResponse `json:"translations"`
 	// Optional brand name.
 	BrandName *string `json:"brand_name,omitempty" example:"Fage"`
 	// Optional product barcode.
@@ -47,10 +78,208 @@ type FoodResponse struct {
 	CarbsPer100g float64 `json:"carbs_per_100g" example:"28"`
 	// Fat grams per 100g.
 	FatPer100g float64 `json:"fat_per_100g" example:"0.3"`
+	// Alcohol grams per 100g.
+	AlcoholPer100g float64 `json:"alcohol_per_100g" example:"0"`
+	// Who can see the food.
+	Visibility string `json:"visibility" enums:"private,public,verified" example:"public"`
+	// Category from GET /food-categories.
+	CategoryID *uint `json:"category_id,omitempty" example:"16"`
+	// Free-form lowercase tags.
+	Tags []string `json:"tags" example:"breakfast,high protein"`
+	// EU major allergens the food contains, sorted.
+	Allergens []string `json:"allergens" example:"milk"`
+	// Diet flags, sorted; vegan implies vegetarian.
+	DietFlags []string `json:"diet_flags" example:"gluten_free,vegetarian"`
+	// Current version number; increases on every edit.
+	Version int `json:"version" example:"3"`
+	// Set when the food was archived; archived foods are hidden from lists and search.
+	ArchivedAt *time.Time `json:"archived_at,omitempty" example:"2026-02-18T09:00:00Z"`
+	// Set when the food was merged into another one.
+	MergedIntoID *uint `json:"merged_into_id,omitempty" example:"4"`
+	// Where the food came from: user for API entries, otherwise the external catalog it was imported from.
+	Source string `json:"source" example:"open_food_facts"`
+	// Link to the entry in the source catalog.
+	SourceRef *string `json:"source_ref,omitempty" example:"https://world.openfoodfacts.org/product/5901234123457"`
+	// When the food was imported from an external catalog.
+	ImportedAt *time.Time `json:"imported_at,omitempty" example:"2026-02-17T12:00:00Z"`
+	// Uploaded image, shrunk to at most 1600px on its longest edge.
+	ImageURL *string `json:"image_url,omitempty" example:"http://localhost:8080/media/foods/1/5f2c9a0e7b1d4c3a8e6f0b21.jpg"`
+	// 320px JPEG thumbnail of the image.
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" example:"http://localhost:8080/media/foods/1/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"`
 	// Creation timestamp in RFC3339 UTC.
 	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
 	// Last update timestamp in RFC3339 UTC.
 	UpdatedAt time.Time `json:"updated_at" example:"2026-02-17T12:00:00Z"`
+	// Failed nutrition checks; only returned by create and update in lenient mode.
+	Warnings []NutritionWarningResponse `json:"warnings,omitempty"`
+}
+
+type TranslationResponse struct {
+	// Lowercase ISO 639 language code.
+	Locale string `json:"locale" example:"de"`
+	// Name in that language.
+	Name string `json:"name" example:"Reis"`
+	// Other names search matches in that language.
+	Synonyms []string `json:"synonyms" example:"Langkornreis"`
+}
+
+type FoodSearchResponse struct {
+	// Page of matching foods.
+	Items []FoodResponse `json:"items"`
+	// Counts over all matches, not just this page.
+	Facets FoodFacetsResponse `json:"facets"`
+}
+
+type FoodFacetsResponse struct {
+	// Matches per category, largest first. Uncategorized foods are not counted.
+	Categories []CategoryFacetResponse `json:"categories"`
+	// Matches per tag for the 20 most used tags, largest first.
+	Tags []TagFacetResponse `json:"tags"`
+}
+
+type CategoryFacetResponse struct {
+	// Category ID.
+	CategoryID uint `json:"category_id" example:"16"`
+	// Category slug.
+	Slug string `json:"slug" example:"yogurt"`
+	// Category name.
+	Name string `json:"name" example:"Yogurt"`
+	// Number of matching foods directly in this category.
+	Count int64 `json:"count" example:"12"`
+}
+
+type TagFacetResponse struct {
+	// Tag.
+	Tag string `json:"tag" example:"breakfast"`
+	// Number of matching foods with this tag.
+	Count int64 `json:"count" example:"7"`
+}
+
+type FoodCategoryResponse struct {
+	// Category ID.
+	ID uint `json:"id" example:"16"`
+	// Parent category; omitted for top-level categories.
+	ParentID *uint `json:"parent_id,omitempty" example:"1"`
+	// Stable identifier.
+	Slug string `json:"slug" example:"yogurt"`
+	// Display name.
+	Name string `json:"name" example:"Yogurt"`
+	// Creation timestamp in RFC3339 UTC.
+	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
+	// Subcategories.
+	Children []FoodCategoryResponse `json:"children"`
+}
+
+type CookingMethodResponse struct {
+	// Cooking method ID.
+	ID uint `json:"id" example:"1"`
+	// Stable identifier.
+	Slug string `json:"slug" example:"boiled"`
+	// Display name.
+	Name string `json:"name" example:"Boiled"`
+	// Creation timestamp in RFC3339 UTC.
+	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
+	// Factors per food category, the method's default first.
+	Factors []CookingFactorResponse `json:"factors"`
+}
+
+type CookingFactorResponse struct {
+	// Food category the factor applies to, with its subcategories; null for the method's default.
+	CategoryID *uint `json:"category_id" example:"21"`
+	// Cooked weight over raw weight.
+	YieldFactor float64 `json:"yield_factor" example:"2.2"`
+	// Share of the raw water still there after cooking; above 1 for foods that take up water.
+	MoistureRetention float64 `json:"moisture_retention" example:"13"`
+}
+
+type NutritionWarningResponse struct {
+	// Machine-readable check that failed.
+	Code string `json:"code" enums:"kcal_mismatch,macros_exceed_100g" example:"kcal_mismatch"`
+	// Human-readable explanation with the values involved.
+	Message string `json:"message" example:"50 kcal stated but macros give about 360 kcal"`
+}
+
+type FoodNutritionIssueResponse struct {
+	// Food with inconsistent nutrition.
+	Food FoodResponse `json:"food"`
+	// Energy estimated from macros with the Atwater factors.
+	EstimatedKcal float64 `json:"estimated_kcal" example:"360"`
+	// Checks the food fails.
+	Warnings []NutritionWarningResponse `json:"warnings"`
+}
+
+type FoodDuplicateResponse struct {
+	// Candidate duplicate.
+	Food FoodResponse `json:"food"`
+	// Combined similarity between 0 and 1.
+	Score float64 `json:"score" example:"0.87"`
+	// Name similarity between 0 and 1.
+	NameScore float64 `json:"name_score" example:"0.82"`
+	// Brand similarity; omitted unless both foods have a brand.
+	BrandScore *float64 `json:"brand_score,omitempty" example:"1"`
+	// Nutrition similarity between 0 and 1.
+	NutritionScore float64 `json:"nutrition_score" example:"0.95"`
+	// True when both foods share a barcode.
+	BarcodeMatch bool `json:"barcode_match" example:"false"`
+}
+
+type FoodMergeLogResponse struct {
+	// Merge log ID.
+	ID uint `json:"id" example:"1"`
+	// Food that was kept.
+	SurvivorFoodID uint `json:"survivor_food_id" example:"4"`
+	// Food that was folded in and archived.
+	MergedFoodID uint `json:"merged_food_id" example:"12"`
+	// Admin who ran the merge; omitted if that user was deleted.
+	MergedBy *uint `json:"merged_by,omitempty" example:"1"`
+	// Meal items moved to the survivor.
+	MealItemsMoved int `json:"meal_items_moved" example:"3"`
+	// Recipe ingredients moved to the survivor.
+	RecipeIngredientsMoved int `json:"recipe_ingredients_moved" example:"1"`
+	// When the merge ran, in RFC3339 UTC.
+	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
+}
+
+type FoodMergeResponse struct {
+	// Food that was kept.
+	Survivor FoodResponse `json:"survivor"`
+	// One log entry per merged food.
+	Merges []FoodMergeLogResponse `json:"merges"`
+	// Recipes recalculated because their ingredients moved; ones that failed stay stale.
+	RecalculatedRecipeIDs []uint `json:"recalculated_recipe_ids" example:"2,5"`
+}
+
+type FoodVersionResponse struct {
+	// Version row ID.
+	ID uint `json:"id" example:"7"`
+	// Food ID.
+	FoodID uint `json:"food_id" example:"1"`
+	// Version number, starting at 1.
+	Version int `json:"version" example:"2"`
+	// User who made the change; omitted if that user was deleted.
+	EditedBy *uint `json:"edited_by,omitempty" example:"1"`
+	// Set when this version restored an earlier one.
+	RestoredFrom *int `json:"restored_from,omitempty" example:"1"`
+	// Food name.
+	Name string `json:"name" example:"Oats"`
+	// Optional brand name.
+	BrandName *string `json:"brand_name,omitempty" example:"Fage"`
+	// Optional product barcode.
+	Barcode *string `json:"barcode,omitempty" example:"5901234123457"`
+	// Energy in kcal per 100g.
+	KcalPer100g float64 `json:"kcal_per_100g" example:"389"`
+	// Protein grams per 100g.
+	ProteinPer100g float64 `json:"protein_per_100g" example:"16.9"`
+	// Carbohydrate grams per 100g.
+	CarbsPer100g float64 `json:"carbs_per_100g" example:"66.3"`
+	// Fat grams per 100g.
+	FatPer100g float64 `json:"fat_per_100g" example:"6.9"`
+	// Alcohol grams per 100g.
+	AlcoholPer100g float64 `json:"alcohol_per_100g" example:"0"`
+	// Visibility at this version.
+	Visibility string `json:"visibility" enums:"private,public,verified" example:"public"`
+	// When the version was recorded, in RFC3339 UTC.
+	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
 }
 
 type RecipeIngredientResponse struct {
@@ -58,12 +287,20 @@ type RecipeIngredientResponse struct {
 	ID uint `json:"id" example:"1"`
 	// Parent recipe ID.
 	RecipeID uint `json:"recipe_id" example:"1"`
-	// Referenced food ID.
-	FoodID uint `json:"food_id" example:"1"`
+	// Referenced food ID; absent for sub-recipes.
+	FoodID *uint `json:"food_id,omitempty" example:"1"`
+	// Referenced recipe ID when another recipe is used as the ingredient.
+	SubRecipeID *uint `json:"sub_recipe_id,omitempty" example:"2"`
+	// Food version the recipe totals were computed from.
+	FoodVersion *int `json:"food_version,omitempty" example:"2"`
 	// Raw ingredient weight in grams.
 	RawWeightG float64 `json:"raw_weight_g" example:"200"`
 	// Optional ordering position.
 	Position *int `json:"position,omitempty" example:"1"`
+	// Preparation note; omitted when empty.
+	Note string `json:"note,omitempty" example:"diced"`
+	// Cooking method the food is cooked by; omitted when not given.
+	CookingMethodID *uint `json:"cooking_method_id,omitempty" example:"1"`
 	// Creation timestamp in RFC3339 UTC.
 	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
 	// Last update timestamp in RFC3339 UTC.
@@ -75,8 +312,32 @@ type RecipeResponse struct {
 	ID uint `json:"id" example:"1"`
 	// Recipe name.
 	Name string `json:"name" example:"Rice Bowl"`
+	// Owner user ID.
+	UserID uint `json:"user_id" example:"7"`
+	// Recipe this one was forked from; absent for originals.
+	ForkedFromID *uint `json:"forked_from_id,omitempty" example:"3"`
+	// Name in the first of the caller's languages the recipe is translated into, else name.
+	DisplayName string `json:"display_name" example:"Reisschüssel"`
+	// Names in other languages, sorted by locale.
+	Translations []TranslationResponse `json:"translations"`
 	// Final cooked yield weight in grams.
 	YieldWeightG float64 `json:"yield_weight_g" example:"200"`
+	// Whether the yield was estimated from the ingredients and their cooking methods rather than stated.
+	YieldEstimated bool `json:"yield_estimated" example:"false"`
+	// Number of equal servings the yield makes.
+	Servings int `json:"servings" example:"2"`
+	// Name of one serving.
+	ServingName string `json:"serving_name" example:"bowl"`
+	// Description; empty when not given.
+	Description string `json:"description" example:"Hearty Hungarian beef stew."`
+	// Preparation time in minutes; 0 when not given.
+	PrepTimeMin int `json:"prep_time_min" example:"20"`
+	// Cooking time in minutes; 0 when not given.
+	CookTimeMin int `json:"cook_time_min" example:"90"`
+	// Free-form tags, lowercased.
+	Tags []string `json:"tags" example:"dinner,hungarian"`
+	// Instruction steps in cooking order.
+	Instructions []string `json:"instructions" example:"Brown the beef.,Add paprika and stock and simmer for 90 minutes."`
 	// Energy in kcal per 100g.
 	KcalPer100g float64 `json:"kcal_per_100g" example:"130"`
 	// Protein grams per 100g.
@@ -85,11 +346,125 @@ type RecipeResponse struct {
 	CarbsPer100g float64 `json:"carbs_per_100g" example:"28"`
 	// Fat grams per 100g.
 	FatPer100g float64 `json:"fat_per_100g" example:"0.3"`
+	// Nutrition of one serving.
+	PerServing RecipePortionResponse `json:"per_serving"`
+	// Nutrition of the whole yield.
+	PerBatch RecipePortionResponse `json:"per_batch"`
+	// Allergens of any ingredient, derived from the current ingredient foods.
+	Allergens []string `json:"allergens" example:"milk"`
+	// Diet flags all ingredients carry, derived from the current ingredient foods.
+	DietFlags []string `json:"diet_flags" example:"vegetarian"`
+	// True when an ingredient food's nutrition changed and the per-100g values await recalculation.
+	Stale bool `json:"stale" example:"false"`
+	// Set when the recipe was archived; archived recipes are hidden from lists and search.
+	ArchivedAt *time.Time `json:"archived_at,omitempty" example:"2026-02-18T09:00:00Z"`
+	// Uploaded image, shrunk to at most 1600px on its longest edge.
+	ImageURL *string `json:"image_url,omitempty" example:"http://localhost:8080/media/recipes/5f2c9a0e7b1d4c3a8e6f0b21.jpg"`
+	// 320px JPEG thumbnail of the image.
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" example:"http://localhost:8080/media/recipes/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"`
 	// Creation timestamp in RFC3339 UTC.
 	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
 	// Last update timestamp in RFC3339 UTC.
 	UpdatedAt   time.Time                  `json:"updated_at" example:"2026-02-17T12:00:00Z"`
 	Ingredients []RecipeIngredientResponse `json:"ingredients,omitempty"`
+	// Contribution of each ingredient to the whole batch; only with breakdown=true.
+	Breakdown *NutritionBreakdownResponse `json:"breakdown,omitempty"`
+}
+
+type RecipePortionResponse struct {
+	// Portion weight in grams.
+	WeightG float64 `json:"weight_g" example:"100"`
+	// Energy in kcal.
+	Kcal float64 `json:"kcal" example:"130"`
+	// Protein grams.
+	ProteinG float64 `json:"protein_g" example:"2.7"`
+	// Carbohydrate grams.
+	CarbsG float64 `json:"carbs_g" example:"28"`
+	// Fat grams.
+	FatG float64 `json:"fat_g" example:"0.3"`
+}
+
+type RecipeForkResponse struct {
+	// The fork.
+	Recipe RecipeResponse `json:"recipe"`
+	// What the fork changed relative to the original.
+	Changes RecipeChangesResponse `json:"changes"`
+}
+
+type RecipeChangesResponse struct {
+	// Recipe the fork was copied from.
+	OriginID uint `json:"origin_id" example:"3"`
+	// Changed recipe fields by JSON name.
+	Fields []string `json:"fields" example:"name,cook_time_min"`
+	// Added, removed and changed ingredients.
+	Ingredients []IngredientChangeResponse `json:"ingredients"`
+	// Fork per-100g values minus the original's.
+	Per100g NutritionDeltaResponse `json:"per_100g"`
+}
+
+type IngredientChangeResponse struct {
+	// Kind of change.
+	Change string `json:"change" enums:"added,removed,changed" example:"changed"`
+	// Ingredient food ID; absent for sub-recipes.
+	FoodID *uint `json:"food_id,omitempty" example:"1"`
+	// Ingredient recipe ID when another recipe is used.
+	SubRecipeID *uint `json:"sub_recipe_id,omitempty" example:"2"`
+	// Original raw weight in grams; absent for added ingredients.
+	FromRawWeightG *float64 `json:"from_raw_weight_g,omitempty" example:"500"`
+	// Fork raw weight in grams; absent for removed ingredients.
+	ToRawWeightG *float64 `json:"to_raw_weight_g,omitempty" example:"400"`
+	// Original note, when there was one.
+	FromNote string `json:"from_note,omitempty" example:"cubed"`
+	// Fork note, when there is one.
+	ToNote string `json:"to_note,omitempty" example:"minced"`
+}
+
+type NutritionDeltaResponse struct {
+	// Energy difference in kcal.
+	Kcal float64 `json:"kcal" example:"-25"`
+	// Protein difference in grams.
+	ProteinG float64 `json:"protein_g" example:"-2.6"`
+	// Carbohydrate difference in grams.
+	CarbsG float64 `json:"carbs_g" example:"0"`
+	// Fat difference in grams.
+	FatG float64 `json:"fat_g" example:"-1.5"`
+}
+
+type RecipeDraftResponse struct {
+	// Recipe name from the source; empty for a pasted list.
+	Name string `json:"name" example:"Chicken rice bowl"`
+	// Sum of the known ingredient weights in grams, to replace with the cooked weight.
+	YieldWeightG float64 `json:"yield_weight_g" example:"470"`
+	// Servings stated by the source, or 1.
+	Servings int `json:"servings" example:"2"`
+	// Parsed ingredient lines in source order.
+	Ingredients []DraftIngredientResponse `json:"ingredients"`
+}
+
+type DraftIngredientResponse struct {
+	// Ingredient line as given.
+	Text string `json:"text" example:"1 cup rice"`
+	// Ingredient name without quantity, unit or preparation notes.
+	Name string `json:"name" example:"rice"`
+	// Parsed quantity; omitted when the line has none.
+	Quantity *float64 `json:"quantity,omitempty" example:"1"`
+	// Canonical unit; omitted for counted items such as "2 eggs".
+	Unit string `json:"unit,omitempty" example:"cup"`
+	// Weight in grams; omitted when the unit does not convert to grams.
+	RawWeightG *float64 `json:"raw_weight_g,omitempty" example:"240"`
+	// True when the weight was converted from a volume as water.
+	ApproximateWeight bool `json:"approximate_weight" example:"true"`
+	// Best matching food, set when its confidence reaches 0.6.
+	FoodID *uint `json:"food_id,omitempty" example:"3"`
+	// Up to three candidate foods, best first.
+	Matches []FoodMatchResponse `json:"matches"`
+}
+
+type FoodMatchResponse struct {
+	// Candidate food.
+	Food FoodResponse `json:"food"`
+	// Name similarity between 0 and 1.
+	Confidence float64 `json:"confidence" example:"0.92"`
 }
 
 type MealItemResponse struct {
@@ -99,10 +474,20 @@ type MealItemResponse struct {
 	MealID uint `json:"meal_id" example:"1"`
 	// Optional food source ID.
 	FoodID *uint `json:"food_id,omitempty" example:"1"`
+	// Food version the nutrition snapshot was taken from.
+	FoodVersion *int `json:"food_version,omitempty" example:"2"`
 	// Optional recipe source ID.
 	RecipeID *uint `json:"recipe_id,omitempty" example:"1"`
 	// Consumed weight in grams.
 	WeightG float64 `json:"weight_g" example:"150"`
+	// Servings of the recipe the item was logged in; weight_g is what they converted to.
+	Servings *float64 `json:"servings,omitempty" example:"1.5"`
+	// Weight of the food after cooking it was logged by; weight_g is the raw weight it converted to.
+	CookedWeightG *float64 `json:"cooked_weight_g,omitempty" example:"280"`
+	// Cooking method the cooked weight is for.
+	CookingMethodID *uint `json:"cooking_method_id,omitempty" example:"1"`
+	// Estimated water the food took up while cooking, negative when it lost water.
+	WaterChangeG *float64 `json:"water_change_g,omitempty" example:"180"`
 	// Energy snapshot in kcal per 100g at log time.
 	KcalPer100g float64 `json:"kcal_per_100g" example:"130"`
 	// Protein snapshot in g per 100g at log time.
@@ -115,6 +500,17 @@ type MealItemResponse struct {
 	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
 	// Last update timestamp in RFC3339 UTC.
 	UpdatedAt time.Time `json:"updated_at" example:"2026-02-17T12:00:00Z"`
+	// Dietary restrictions of the user the item breaks; only returned when the item is added.
+	Warnings []DietaryConflictResponse `json:"warnings,omitempty"`
+}
+
+type DietaryConflictResponse struct {
+	// Kind of restriction broken.
+	Code string `json:"code" enums:"allergen,diet" example:"allergen"`
+	// Allergen contained or diet flag missing.
+	Value string `json:"value" example:"peanuts"`
+	// Human-readable explanation.
+	Message string `json:"message" example:"contains peanuts, which you avoid"`
 }
 
 type MealResponse struct {
@@ -126,6 +522,10 @@ type MealResponse struct {
 	MealType string `json:"meal_type" example:"lunch"`
 	// Meal timestamp in RFC3339 UTC.
 	EatenAt time.Time `json:"eaten_at" example:"2026-02-17T12:00:00Z"`
+	// Uploaded photo, shrunk to at most 1600px on its longest edge.
+	ImageURL *string `json:"image_url,omitempty" example:"http://localhost:8080/media/meals/5f2c9a0e7b1d4c3a8e6f0b21.jpg"`
+	// 320px JPEG thumbnail of the photo.
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" example:"http://localhost:8080/media/meals/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"`
 	// Creation timestamp in RFC3339 UTC.
 	CreatedAt time.Time `json:"created_at" example:"2026-02-17T12:00:00Z"`
 	// Last update timestamp in RFC3339 UTC.
@@ -139,6 +539,67 @@ type MealResponse struct {
 	TotalCarbsG float64 `json:"total_carbs_g" example:"75"`
 	// Aggregated fat grams for this meal.
 	TotalFatG float64 `json:"total_fat_g" example:"2.1"`
+	// Contribution of each item; only with breakdown=true.
+	Breakdown *NutritionBreakdownResponse `json:"breakdown,omitempty"`
+}
+
+type NutritionBreakdownResponse struct {
+	// One entry per recipe ingredient or meal item, in order.
+	Items []BreakdownItemResponse `json:"items"`
+	// Sum of the contributions.
+	Total NutrientAmountsResponse `json:"total"`
+	// Share of the energy from macros that each macro provides, by the Atwater factors.
+	CalorieSplit MacroSplitResponse `json:"calorie_split"`
+}
+
+type BreakdownItemResponse struct {
+	// Recipe ingredient or meal item ID.
+	ID uint `json:"id" example:"4"`
+	// Food ID; absent for recipes.
+	FoodID *uint `json:"food_id,omitempty" example:"1"`
+	// Recipe ID when a recipe is used.
+	RecipeID *uint `json:"recipe_id,omitempty" example:"2"`
+	// Food or recipe name; empty for foods the caller may not see.
+	Name string `json:"name" example:"Olive oil"`
+	// Name in the first of the caller's languages it is translated into, else name.
+	DisplayName string `json:"display_name" example:"Olivenöl"`
+	// Weight in grams, raw for recipe ingredients.
+	WeightG float64 `json:"weight_g" example:"10"`
+	// Nutrients this item adds.
+	Contribution NutrientAmountsResponse `json:"contribution"`
+	// Percentage of each total this item adds.
+	Share NutrientSharesResponse `json:"share"`
+}
+
+type NutrientAmountsResponse struct {
+	// Energy in kcal.
+	Kcal float64 `json:"kcal" example:"88.4"`
+	// Protein grams.
+	ProteinG float64 `json:"protein_g" example:"0"`
+	// Carbohydrate grams.
+	CarbsG float64 `json:"carbs_g" example:"0"`
+	// Fat grams.
+	FatG float64 `json:"fat_g" example:"10"`
+}
+
+type NutrientSharesResponse struct {
+	// Percentage of the energy total.
+	KcalPct float64 `json:"kcal_pct" example:"25.4"`
+	// Percentage of the protein total.
+	ProteinPct float64 `json:"protein_pct" example:"0"`
+	// Percentage of the carbohydrate total.
+	CarbsPct float64 `json:"carbs_pct" example:"0"`
+	// Percentage of the fat total.
+	FatPct float64 `json:"fat_pct" example:"94.3"`
+}
+
+type MacroSplitResponse struct {
+	// Percentage of calories from protein.
+	ProteinPct float64 `json:"protein_pct" example:"6.3"`
+	// Percentage of calories from carbohydrates.
+	CarbsPct float64 `json:"carbs_pct" example:"65.7"`
+	// Percentage of calories from fat.
+	FatPct float64 `json:"fat_pct" example:"28"`
 }
 
 type DailyTotalsResponse struct {
@@ -231,3 +692,141 @@ type EnergyProgressResponse struct {
 	RecommendedTDEEKcal  float64  `json:"recommended_tdee_kcal" example:"2460"`
 	DataQualityScore     float64  `json:"data_quality_score" example:"0.78"`
 }
+
+type ShoppingListResponse struct {
+	// Shopping list ID.
+	ID uint `json:"id" example:"1"`
+	// Owner user ID.
+	UserID uint `json:"user_id" example:"1"`
+	// List name.
+	Name string `json:"name" example:"Weekend cooking"`
+	// Creation timestamp in RFC3339 UTC.
+	CreatedAt time.Time `json:"created_at" example:"2026-02-17T08:00:00Z"`
+	// Last update timestamp in RFC3339 UTC.
+	UpdatedAt time.Time `json:"updated_at" example:"2026-02-17T08:00:00Z"`
+	// One item per food, in the order the foods first came up; omitted in lists.
+	Items []ShoppingListItemResponse `json:"items,omitempty"`
+}
+
+type ShoppingListItemResponse struct {
+	// Shopping list item ID.
+	ID uint `json:"id" example:"1"`
+	// Food to buy.
+	FoodID uint `json:"food_id" example:"1"`
+	// Food name; empty for foods the caller may no longer see.
+	Name string `json:"name" example:"Spaghetti"`
+	// Food name in the caller's language.
+	DisplayName string `json:"display_name" example:"Spaghetti"`
+	// Raw weight needed in grams, merged across recipes and meals.
+	WeightG float64 `json:"weight_g" example:"300"`
+	// Whether the item was bought.
+	Checked bool `json:"checked" example:"false"`
+	// Creation timestamp in RFC3339 UTC.
+	CreatedAt time.Time `json:"created_at" example:"2026-02-17T08:00:00Z"`
+	// Last update timestamp in RFC3339 UTC.
+	UpdatedAt time.Time `json:"updated_at" example:"2026-02-17T08:00:00Z"`
+}
+
+type ShareLinkResponse struct {
+	// Share link ID.
+	ID uint `json:"id" example:"1"`
+	// Owner user ID.
+	UserID uint `json:"user_id" example:"1"`
+	// Token to open the link with at /public/shared/{token}; only returned when the link is created.
+	Token string `json:"token,omitempty" example:"q3X0b9mJ4r1vF8kT2zY6wN5sL7pC0dHa"`
+	// Shared recipe; absent for meals.
+	RecipeID *uint `json:"recipe_id,omitempty" example:"1"`
+	// Shared meal; absent for recipes.
+	MealID *uint `json:"meal_id,omitempty" example:"2"`
+	// When the link stops opening; absent when it never expires.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-03-01T00:00:00Z"`
+	// Set when the owner revoked the link.
+	RevokedAt *time.Time `json:"revoked_at,omitempty" example:"2026-02-20T09:00:00Z"`
+	// Number of times the link was opened.
+	AccessCount int64 `json:"access_count" example:"3"`
+	// When the link was last opened.
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty" example:"2026-02-19T18:30:00Z"`
+	// Creation timestamp in RFC3339 UTC.
+	CreatedAt time.Time `json:"created_at" example:"2026-02-17T08:00:00Z"`
+	// Last update timestamp in RFC3339 UTC.
+	UpdatedAt time.Time `json:"updated_at" example:"2026-02-17T08:00:00Z"`
+}
+
+type SharedViewResponse struct {
+	// What the link shares.
+	Kind string `json:"kind" enums:"recipe,meal" example:"recipe"`
+	// Shared recipe; absent for meals.
+	Recipe *SharedRecipeResponse `json:"recipe,omitempty"`
+	// Shared meal; absent for recipes.
+	Meal *SharedMealResponse `json:"meal,omitempty"`
+	// Contribution of each recipe ingredient, to the whole batch, or of each meal item.
+	Nutrition SharedNutritionResponse `json:"nutrition"`
+}
+
+type SharedNutritionResponse struct {
+	// One entry per recipe ingredient or meal item, in order.
+	Items []SharedNutritionItemResponse `json:"items"`
+	// Sum of the contributions.
+	Total NutrientAmountsResponse `json:"total"`
+	// Share of the energy from macros that each macro provides, by the Atwater factors.
+	CalorieSplit MacroSplitResponse `json:"calorie_split"`
+}
+
+type SharedNutritionItemResponse struct {
+	// Food or recipe name; empty for other users' private foods.
+	Name string `json:"name" example:"Olive oil"`
+	// Name in the first of the caller's languages it is translated into, else name.
+	DisplayName string `json:"display_name" example:"Olivenöl"`
+	// Weight in grams, raw for recipe ingredients.
+	WeightG float64 `json:"weight_g" example:"10"`
+	// Nutrients this item adds.
+	Contribution NutrientAmountsResponse `json:"contribution"`
+	// Percentage of each total this item adds.
+	Share NutrientSharesResponse `json:"share"`
+}
+
+type SharedRecipeResponse struct {
+	// Recipe name.
+	Name string `json:"name" example:"Rice Bowl"`
+	// Name in the first of the caller's languages the recipe is translated into, else name.
+	DisplayName string `json:"display_name" example:"Reisschüssel"`
+	// Description; empty when not given.
+	Description string `json:"description" example:"Hearty Hungarian beef stew."`
+	// Final cooked yield weight in grams.
+	YieldWeightG float64 `json:"yield_weight_g" example:"200"`
+	// Number of equal servings the yield makes.
+	Servings int `json:"servings" example:"2"`
+	// Name of one serving.
+	ServingName string `json:"serving_name" example:"bowl"`
+	// Preparation time in minutes; 0 when not given.
+	PrepTimeMin int `json:"prep_time_min" example:"20"`
+	// Cooking time in minutes; 0 when not given.
+	CookTimeMin int `json:"cook_time_min" example:"90"`
+	// Free-form tags, lowercased.
+	Tags []string `json:"tags" example:"dinner,hungarian"`
+	// Instruction steps in cooking order.
+	Instructions []string `json:"instructions" example:"Brown the beef.,Add paprika and stock and simmer for 90 minutes."`
+	// Allergens of any ingredient.
+	Allergens []string `json:"allergens" example:"milk"`
+	// Diet flags all ingredients carry.
+	DietFlags []string `json:"diet_flags" example:"vegetarian"`
+	// Nutrition of one serving.
+	PerServing RecipePortionResponse `json:"per_serving"`
+	// Nutrition of the whole yield.
+	PerBatch RecipePortionResponse `json:"per_batch"`
+	// Uploaded image, shrunk to at most 1600px on its longest edge.
+	ImageURL *string `json:"image_url,omitempty" example:"http://localhost:8080/media/recipes/5f2c9a0e7b1d4c3a8e6f0b21.jpg"`
+	// 320px JPEG thumbnail of the image.
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" example:"http://localhost:8080/media/recipes/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"`
+}
+
+type SharedMealResponse struct {
+	// Meal type.
+	MealType string `json:"meal_type" example:"lunch"`
+	// Meal timestamp in RFC3339 UTC.
+	EatenAt time.Time `json:"eaten_at" example:"2026-02-17T12:00:00Z"`
+	// Uploaded photo, shrunk to at most 1600px on its longest edge.
+	ImageURL *string `json:"image_url,omitempty" example:"http://localhost:8080/media/meals/5f2c9a0e7b1d4c3a8e6f0b21.jpg"`
+	// 320px JPEG thumbnail of the photo.
+	ThumbnailURL *string `json:"thumbnail_url,omitempty" example:"http://localhost:8080/media/meals/5f2c9a0e7b1d4c3a8e6f0b21_thumb.jpg"`
+}
@@ -21,6 +21,8 @@ func TestAuthHandlers(t *testing.T) {
 		r.Post("/api/v1/auth/login", h.Login)
 		r.Post("/api/v1/auth/refresh", h.Refresh)
 		r.Post("/api/v1/auth/logout", h.Logout)
+		r.Post("/api/v1/auth/magic-link", h.RequestMagicLink)
+		r.Post("/api/v1/auth/magic-link/consume", h.ConsumeMagicLink)
 		return r
 	}
 
@@ -42,6 +44,103 @@ func TestAuthHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("register compromised password returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{
+			registerFn: func(_ context.Context, _ service.RegisterInput) (service.AuthResult, error) {
+				return service.AuthResult{}, service.ErrPasswordCompromised
+			},
+		}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		r := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", strings.NewReader(`{"name":"A","email":"a@example.com","password":"Password123!"}`))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		var payload handlers.ErrorEnvelope
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if payload.Error.Code != "password_compromised" {
+			t.Fatalf("expected password_compromised, got %q", payload.Error.Code)
+		}
+	})
+
+	t.Run("magic link request returns 202", func(t *testing.T) {
+		called := false
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{}, fakeMagicLinkService{
+			requestFn: func(_ context.Context, email string) error {
+				called = email == "a@example.com"
+				return nil
+			},
+		})
+		r := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/magic-link", strings.NewReader(`{"email":"a@example.com"}`))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected %d, got %d", http.StatusAccepted, rec.Code)
+		}
+		if !called {
+			t.Fatalf("expected magic link service to be called")
+		}
+	})
+
+	t.Run("magic link request missing email returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{}, fakeMagicLinkService{})
+		r := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/magic-link", strings.NewReader(`{}`))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		var payload handlers.ErrorEnvelope
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if payload.Error.Code != "invalid_magic_link_payload" {
+			t.Fatalf("expected invalid_magic_link_payload, got %q", payload.Error.Code)
+		}
+	})
+
+	t.Run("magic link consume invalid token returns 401", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{}, fakeMagicLinkService{
+			consumeFn: func(_ context.Context, _ string) (service.AuthResult, error) {
+				return service.AuthResult{}, service.ErrInvalidMagicLinkToken
+			},
+		})
+		r := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/magic-link/consume", strings.NewReader(`{"token":"used"}`))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+		var payload handlers.ErrorEnvelope
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if payload.Error.Code != "invalid_magic_link_token" {
+			t.Fatalf("expected invalid_magic_link_token, got %q", payload.Error.Code)
+		}
+	})
+
+	t.Run("magic link consume returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{}, fakeMagicLinkService{
+			consumeFn: func(_ context.Context, _ string) (service.AuthResult, error) {
+				return service.AuthResult{Token: "a", AccessToken: "a", RefreshToken: "r"}, nil
+			},
+		})
+		r := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/magic-link/consume", strings.NewReader(`{"token":"abc"}`))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
 	t.Run("refresh returns 200", func(t *testing.T) {
 		h := handlers.New(noopUserService{}, fakeAuthService{
 			refreshFn: func(_ context.Context, _ string) (service.AuthResult, error) {
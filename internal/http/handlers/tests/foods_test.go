@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"goal-bite-api/internal/domain/cooking"
 	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/domain/user"
 	"goal-bite-api/internal/http/handlers"
 	httpmiddleware "goal-bite-api/internal/http/middleware"
@@ -35,9 +38,21 @@ func TestFoodHandlers(t *testing.T) {
 		r.Post("/api/v1/foods", h.CreateFood)
 		r.Get("/api/v1/foods", h.ListFoods)
 		r.Get("/api/v1/foods/by-barcode/{barcode}", h.GetFoodByBarcode)
+		r.Get("/api/v1/foods/nutrition-report", h.GetFoodNutritionReport)
+		r.Get("/api/v1/foods/search", h.SearchFoods)
+		r.Get("/api/v1/food-categories", h.ListFoodCategories)
+		r.Get("/api/v1/cooking-methods", h.ListCookingMethods)
 		r.Get("/api/v1/foods/{id}", h.GetFoodByID)
 		r.Patch("/api/v1/foods/{id}", h.UpdateFood)
 		r.Delete("/api/v1/foods/{id}", h.DeleteFood)
+		r.Post("/api/v1/foods/{id}/restore", h.RestoreFood)
+		r.Post("/api/v1/foods/{id}/verify", h.VerifyFood)
+		r.Get("/api/v1/foods/{id}/versions", h.ListFoodVersions)
+		r.Post("/api/v1/foods/{id}/versions/{version}/rollback", h.RollbackFood)
+		r.Get("/api/v1/foods/{id}/duplicates", h.FindFoodDuplicates)
+		r.Post("/api/v1/foods/{id}/merge", h.MergeFoods)
+		r.Put("/api/v1/foods/{id}/image", h.UploadFoodImage)
+		r.Delete("/api/v1/foods/{id}/image", h.DeleteFoodImage)
 		return r
 	}
 
@@ -69,6 +84,7 @@ func TestFoodHandlers(t *testing.T) {
 		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
 		router := newRouter(h)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods?limit=bad", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
 		rec := httptest.NewRecorder()
 
 		router.ServeHTTP(rec, req)
@@ -79,11 +95,12 @@ func TestFoodHandlers(t *testing.T) {
 	})
 
 	t.Run("get food not found returns 404", func(t *testing.T) {
-		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{getFn: func(_ context.Context, _, _ uint) (food.Food, error) {
 			return food.Food{}, service.ErrFoodNotFound
 		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
 		router := newRouter(h)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/10", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
 		rec := httptest.NewRecorder()
 
 		router.ServeHTTP(rec, req)
@@ -96,14 +113,15 @@ func TestFoodHandlers(t *testing.T) {
 	t.Run("get food by barcode returns 200", func(t *testing.T) {
 		now := time.Now().UTC()
 		barcode := "5901234123457"
-		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{getBarcodeFn: func(_ context.Context, in string) (food.Food, error) {
-			if in != barcode {
-				return food.Food{}, errors.New("unexpected barcode")
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{getBarcodeFn: func(_ context.Context, userID uint, in string) (food.Food, error) {
+			if userID != 7 || in != barcode {
+				return food.Food{}, errors.New("unexpected args")
 			}
 			return food.Food{ID: 2, Name: "Yogurt", Barcode: &barcode, KcalPer100g: 80, ProteinPer100g: 4, CarbsPer100g: 10, FatPer100g: 2, CreatedAt: now, UpdatedAt: now}, nil
 		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
 		router := newRouter(h)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/by-barcode/5901234123457", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
 		rec := httptest.NewRecorder()
 		router.ServeHTTP(rec, req)
 		if rec.Code != http.StatusOK {
@@ -112,11 +130,12 @@ func TestFoodHandlers(t *testing.T) {
 	})
 
 	t.Run("get food by barcode not found returns 404", func(t *testing.T) {
-		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{getBarcodeFn: func(_ context.Context, _ string) (food.Food, error) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{getBarcodeFn: func(_ context.Context, _ uint, _ string) (food.Food, error) {
 			return food.Food{}, service.ErrFoodBarcodeNotFound
 		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
 		router := newRouter(h)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/by-barcode/5901234123457", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
 		rec := httptest.NewRecorder()
 		router.ServeHTTP(rec, req)
 		if rec.Code != http.StatusNotFound {
@@ -177,14 +196,15 @@ func TestFoodHandlers(t *testing.T) {
 
 	t.Run("list foods returns 200 with payload", func(t *testing.T) {
 		now := time.Now().UTC()
-		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{listFn: func(_ context.Context, limit, offset int) ([]food.Food, error) {
-			if limit != 20 || offset != 0 {
-				return nil, errors.New("unexpected pagination")
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{listFn: func(_ context.Context, userID uint, in service.FoodListInput) ([]food.Food, error) {
+			if userID != 7 || in.IncludeArchived || in.Limit != 20 || in.Offset != 0 {
+				return nil, errors.New("unexpected args")
 			}
 			return []food.Food{{ID: 1, Name: "Egg", KcalPer100g: 155, ProteinPer100g: 13, CarbsPer100g: 1.1, FatPer100g: 11, CreatedAt: now, UpdatedAt: now}}, nil
 		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
 		router := newRouter(h)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
 		rec := httptest.NewRecorder()
 		router.ServeHTTP(rec, req)
 
@@ -201,24 +221,681 @@ func TestFoodHandlers(t *testing.T) {
 		}
 	})
 
-	t.Run("list foods with q uses search", func(t *testing.T) {
+	t.Run("list foods passes q", func(t *testing.T) {
 		now := time.Now().UTC()
-		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{searchFn: func(_ context.Context, query string, limit, offset int) ([]food.Food, error) {
-			if query != "egg" {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{listFn: func(_ context.Context, _ uint, in service.FoodListInput) ([]food.Food, error) {
+			if in.Query != "egg" {
 				return nil, errors.New("unexpected query")
 			}
-			if limit != 20 || offset != 0 {
+			if in.Limit != 20 || in.Offset != 0 {
 				return nil, errors.New("unexpected pagination")
 			}
 			return []food.Food{{ID: 1, Name: "Egg", CreatedAt: now, UpdatedAt: now}}, nil
 		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
 		router := newRouter(h)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods?q=egg", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("list foods requires auth", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("create verified food as non admin returns 403", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{createFn: func(_ context.Context, _ uint, in service.CreateFoodInput) (food.Food, error) {
+			if in.Visibility != food.VisibilityVerified {
+				return food.Food{}, errors.New("unexpected visibility")
+			}
+			return food.Food{}, service.ErrFoodVerificationForbidden
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		body := `{"name":"Rice","kcal_per_100g":130,"protein_per_100g":2.7,"carbs_per_100g":28,"fat_per_100g":0.3,"visibility":"verified"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusForbidden, "food_verification_forbidden")
+	})
+
+	t.Run("create food with unknown visibility returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{createFn: func(_ context.Context, _ uint, _ service.CreateFoodInput) (food.Food, error) {
+			return food.Food{}, service.ErrInvalidFoodVisibility
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		body := `{"name":"Rice","kcal_per_100g":130,"protein_per_100g":2.7,"carbs_per_100g":28,"fat_per_100g":0.3,"visibility":"friends"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_food_visibility")
+	})
+
+	t.Run("verify food returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{verifyFn: func(_ context.Context, userID, id uint) (food.Food, error) {
+			if userID != 7 || id != 3 {
+				return food.Food{}, errors.New("unexpected args")
+			}
+			return food.Food{ID: 3, Name: "Egg", Visibility: food.VisibilityVerified}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/3/verify", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var payload food.Food
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if payload.Visibility != food.VisibilityVerified {
+			t.Fatalf("expected verified food, got %q", payload.Visibility)
+		}
+	})
+
+	t.Run("verify food as non admin returns 403", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{verifyFn: func(_ context.Context, _, _ uint) (food.Food, error) {
+			return food.Food{}, service.ErrFoodVerificationForbidden
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/3/verify", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusForbidden, "food_verification_forbidden")
+	})
+
+	t.Run("list food versions returns 200", func(t *testing.T) {
+		editor := uint(7)
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{versionsFn: func(_ context.Context, userID, id uint) ([]food.Version, error) {
+			if userID != 7 || id != 1 {
+				return nil, errors.New("unexpected args")
+			}
+			return []food.Version{
+				{ID: 2, FoodID: 1, Version: 2, EditedBy: &editor, Name: "Oats", KcalPer100g: 400},
+				{ID: 1, FoodID: 1, Version: 1, EditedBy: &editor, Name: "Oats", KcalPer100g: 389},
+			}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/1/versions", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
 		rec := httptest.NewRecorder()
 		router.ServeHTTP(rec, req)
 
 		if rec.Code != http.StatusOK {
 			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
 		}
+		var payload []food.Version
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(payload) != 2 || payload[0].Version != 2 || payload[0].EditedBy == nil {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+	})
+
+	t.Run("rollback food with invalid version returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/1/versions/zero/rollback", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_food_version")
+	})
+
+	t.Run("rollback food to missing version returns 404", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{rollbackFn: func(_ context.Context, _, _ uint, version int) (food.Food, error) {
+			if version != 5 {
+				return food.Food{}, errors.New("unexpected version")
+			}
+			return food.Food{}, service.ErrFoodVersionNotFound
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/1/versions/5/rollback", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusNotFound, "food_version_not_found")
+	})
+
+	t.Run("list foods with include_archived passes flag", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{listFn: func(_ context.Context, _ uint, in service.FoodListInput) ([]food.Food, error) {
+			if !in.IncludeArchived {
+				return nil, errors.New("expected include archived")
+			}
+			return []food.Food{}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods?include_archived=true", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("list foods with invalid include_archived returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods?include_archived=maybe", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_include_archived")
+	})
+
+	t.Run("restore food returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{restoreFn: func(_ context.Context, userID, id uint) (food.Food, error) {
+			if userID != 7 || id != 1 {
+				return food.Food{}, errors.New("unexpected args")
+			}
+			return food.Food{ID: 1, Name: "Oats"}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/1/restore", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("restore food with taken barcode returns 409", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{restoreFn: func(_ context.Context, _, _ uint) (food.Food, error) {
+			return food.Food{}, service.ErrFoodBarcodeExists
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/1/restore", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusConflict, "food_barcode_already_exists")
+	})
+
+	t.Run("update archived food returns 409", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{updateFn: func(_ context.Context, _, _ uint, _ service.UpdateFoodInput) (food.Food, error) {
+			return food.Food{}, service.ErrFoodArchived
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/foods/1", strings.NewReader(`{"name":"Oats"}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusConflict, "food_archived")
+	})
+
+	t.Run("find food duplicates returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{duplicatesFn: func(_ context.Context, userID, id uint) ([]service.FoodDuplicate, error) {
+			if userID != 7 || id != 1 {
+				return nil, errors.New("unexpected args")
+			}
+			return []service.FoodDuplicate{{Food: food.Food{ID: 2, Name: "Greek yogurt"}, Score: 0.9}}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/1/duplicates", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var payload []struct {
+			Food  food.Food `json:"food"`
+			Score float64   `json:"score"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if len(payload) != 1 || payload[0].Food.ID != 2 || payload[0].Score != 0.9 {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+	})
+
+	t.Run("merge foods returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{mergeFn: func(_ context.Context, userID, survivorID uint, foodIDs []uint) (service.FoodMergeResult, error) {
+			if userID != 7 || survivorID != 1 || len(foodIDs) != 2 || foodIDs[0] != 2 || foodIDs[1] != 3 {
+				return service.FoodMergeResult{}, errors.New("unexpected args")
+			}
+			return service.FoodMergeResult{Survivor: food.Food{ID: 1}, RecalculatedRecipeIDs: []uint{4}}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/1/merge", strings.NewReader(`{"food_ids":[2,3]}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("merge foods with empty list returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/1/merge", strings.NewReader(`{"food_ids":[]}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_food_merge")
+	})
+
+	t.Run("merge foods by non-admin returns 403", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{mergeFn: func(_ context.Context, _, _ uint, _ []uint) (service.FoodMergeResult, error) {
+			return service.FoodMergeResult{}, service.ErrFoodMergeForbidden
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods/1/merge", strings.NewReader(`{"food_ids":[2]}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusForbidden, "food_merge_forbidden")
+	})
+
+	t.Run("get food by barcode when catalog is unavailable returns 502", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{getBarcodeFn: func(_ context.Context, _ uint, _ string) (food.Food, error) {
+			return food.Food{}, fmt.Errorf("%w: timeout", service.ErrBarcodeLookupUnavailable)
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/by-barcode/5901234123457", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadGateway, "barcode_lookup_unavailable")
+	})
+
+	t.Run("create food in strict mode with inconsistent nutrition returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{createFn: func(_ context.Context, _ uint, in service.CreateFoodInput) (food.Food, error) {
+			if !in.StrictNutrition {
+				return food.Food{}, errors.New("expected strict nutrition")
+			}
+			return food.Food{}, &service.NutritionError{Warnings: []food.NutritionWarning{{Code: food.WarningKcalMismatch}}}
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		body := `{"name":"Oil","kcal_per_100g":50,"protein_per_100g":0,"carbs_per_100g":0,"fat_per_100g":40}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods?validation=strict", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "inconsistent_nutrition")
+	})
+
+	t.Run("update food with unknown validation mode returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/foods/1?validation=loose", strings.NewReader(`{"kcal_per_100g":100}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_validation_mode")
+	})
+
+	t.Run("nutrition report returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{reportFn: func(_ context.Context, userID uint, limit, offset int) ([]service.FoodNutritionIssue, error) {
+			if userID != 7 || limit != 20 || offset != 0 {
+				return nil, errors.New("unexpected arguments")
+			}
+			return []service.FoodNutritionIssue{{
+				Food:          food.Food{ID: 3, Name: "Oil", KcalPer100g: 50, FatPer100g: 40},
+				EstimatedKcal: 360,
+				Warnings:      []food.NutritionWarning{{Code: food.WarningKcalMismatch}},
+			}}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/nutrition-report", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var got []struct {
+			Food struct {
+				ID uint `json:"id"`
+			} `json:"food"`
+			Warnings []struct {
+				Code string `json:"code"`
+			} `json:"warnings"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if len(got) != 1 || got[0].Food.ID != 3 || len(got[0].Warnings) != 1 || got[0].Warnings[0].Code != "kcal_mismatch" {
+			t.Fatalf("unexpected report: %s", rec.Body.String())
+		}
+	})
+
+	t.Run("list foods passes category and tags", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{listFn: func(_ context.Context, _ uint, in service.FoodListInput) ([]food.Food, error) {
+			if in.CategoryID == nil || *in.CategoryID != 1 || len(in.Tags) != 2 || in.Tags[0] != "breakfast" || in.Tags[1] != "vegan" {
+				return nil, errors.New("unexpected filters")
+			}
+			return []food.Food{}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods?category_id=1&tag=breakfast&tag=vegan", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("list foods with invalid category returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods?category_id=dairy", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_food_category")
+	})
+
+	t.Run("search foods returns items and facets", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{searchFn: func(_ context.Context, _ uint, in service.FoodListInput) (service.FoodSearchResult, error) {
+			if in.Query != "yog" {
+				return service.FoodSearchResult{}, errors.New("unexpected query")
+			}
+			return service.FoodSearchResult{
+				Items:  []food.Food{{ID: 3, Name: "Yogurt"}},
+				Facets: service.FoodFacets{Categories: []service.CategoryFacet{{CategoryID: 16, Slug: "yogurt", Name: "Yogurt", Count: 1}}, Tags: []service.TagFacet{}},
+			}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/search?q=yog", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var got struct {
+			Items  []food.Food `json:"items"`
+			Facets struct {
+				Categories []struct {
+					Slug  string `json:"slug"`
+					Count int64  `json:"count"`
+				} `json:"categories"`
+			} `json:"facets"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if len(got.Items) != 1 || len(got.Facets.Categories) != 1 || got.Facets.Categories[0].Slug != "yogurt" {
+			t.Fatalf("unexpected response: %s", rec.Body.String())
+		}
+	})
+
+	t.Run("search foods with invalid tags returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{searchFn: func(_ context.Context, _ uint, _ service.FoodListInput) (service.FoodSearchResult, error) {
+			return service.FoodSearchResult{}, service.ErrInvalidFoodTags
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/search?tag=", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_food_tags")
+	})
+
+	t.Run("create food with unknown category returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{createFn: func(_ context.Context, _ uint, _ service.CreateFoodInput) (food.Food, error) {
+			return food.Food{}, service.ErrFoodCategoryNotFound
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		body := `{"name":"Rice","kcal_per_100g":130,"protein_per_100g":2.7,"carbs_per_100g":28,"fat_per_100g":0.3,"category_id":999}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_food_category")
+	})
+
+	t.Run("create food with contradicting diet flags returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{createFn: func(_ context.Context, _ uint, in service.CreateFoodInput) (food.Food, error) {
+			if len(in.Allergens) != 1 || len(in.DietFlags) != 1 {
+				t.Fatalf("unexpected input %+v", in)
+			}
+			return food.Food{}, service.ErrInvalidDietaryInfo
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		body := `{"name":"Milk chocolate","kcal_per_100g":535,"protein_per_100g":7.7,"carbs_per_100g":59.4,"fat_per_100g":29.7,"allergens":["milk"],"diet_flags":["vegan"]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_dietary_info")
+	})
+
+	t.Run("list foods passes exclude_conflicts", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{listFn: func(_ context.Context, _ uint, in service.FoodListInput) ([]food.Food, error) {
+			if !in.ExcludeConflicts {
+				t.Fatalf("expected exclude conflicts to be set")
+			}
+			return []food.Food{}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods?exclude_conflicts=true", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("get food shows the name in the caller's languages", func(t *testing.T) {
+		h := handlers.New(fakeUserService{result: user.User{ID: 7, Languages: []string{"fr"}}}, fakeAuthService{}, fakeFoodService{getFn: func(_ context.Context, _, id uint) (food.Food, error) {
+			return food.Food{ID: id, Name: "Rice", Translations: []locale.Translation{{Locale: "de", Name: "Reis"}, {Locale: "it", Name: "Riso"}}}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/1", nil)
+		req.Header.Set("Accept-Language", "de;q=0.5, it-IT, *;q=0.1")
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if got := rec.Header().Get("Vary"); got != "Accept-Language" {
+			t.Fatalf("expected Vary: Accept-Language, got %q", got)
+		}
+		var payload food.Food
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if payload.DisplayName != "Riso" || payload.Name != "Rice" {
+			t.Fatalf("unexpected names %q %q", payload.DisplayName, payload.Name)
+		}
+	})
+
+	t.Run("search foods passes the caller's languages", func(t *testing.T) {
+		h := handlers.New(fakeUserService{result: user.User{ID: 7, Languages: []string{"de"}}}, fakeAuthService{}, fakeFoodService{searchFn: func(_ context.Context, _ uint, in service.FoodListInput) (service.FoodSearchResult, error) {
+			if in.Query != "reis" || len(in.Languages) != 2 || in.Languages[0] != "de" || in.Languages[1] != "en" {
+				t.Fatalf("unexpected input %+v", in)
+			}
+			return service.FoodSearchResult{Items: []food.Food{{ID: 1, Name: "Rice"}}}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/foods/search?q=reis", nil)
+		req.Header.Set("Accept-Language", "en-GB,de;q=0.7")
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var payload service.FoodSearchResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(payload.Items) != 1 || payload.Items[0].DisplayName != "Rice" {
+			t.Fatalf("expected untranslated food to fall back to its name, got %+v", payload.Items)
+		}
+	})
+
+	t.Run("create food with invalid translations returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{createFn: func(_ context.Context, _ uint, in service.CreateFoodInput) (food.Food, error) {
+			if len(in.Translations) != 1 || in.Translations[0].Locale != "german" {
+				t.Fatalf("unexpected input %+v", in)
+			}
+			return food.Food{}, service.ErrInvalidTranslations
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		body := `{"name":"Rice","kcal_per_100g":130,"protein_per_100g":2.7,"carbs_per_100g":28,"fat_per_100g":0.3,"translations":[{"locale":"german","name":"Reis"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/foods", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_translations")
+	})
+
+	t.Run("list food categories returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{categoriesFn: func(_ context.Context) ([]food.CategoryNode, error) {
+			return []food.CategoryNode{{Category: food.Category{ID: 1, Slug: "dairy", Name: "Dairy"}}}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/food-categories", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"slug":"dairy"`) {
+			t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("list cooking methods returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{cookingFn: func(_ context.Context) ([]cooking.Method, error) {
+			category := uint(4)
+			return []cooking.Method{{ID: 1, Slug: "boiled", Name: "Boiled", Factors: []cooking.Factor{{CategoryID: &category, YieldFactor: 2.2, MoistureRetention: 13}}}}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/cooking-methods", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"slug":"boiled"`) || !strings.Contains(rec.Body.String(), `"yield_factor":2.2`) {
+			t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("upload food image returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{imageFn: func(_ context.Context, userID, id uint, data []byte) (food.Food, error) {
+			if userID != 7 || id != 3 || string(data) != "image-bytes" {
+				return food.Food{}, errors.New("unexpected upload")
+			}
+			url := "http://localhost:8080/media/foods/3/a.png"
+			return food.Food{ID: 3, Name: "Oats", ImageURL: &url}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, imageUploadRequest(t, "/api/v1/foods/3/image", []byte("image-bytes")))
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"image_url":"http://localhost:8080/media/foods/3/a.png"`) {
+			t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("upload food image without file returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/foods/3/image", strings.NewReader(`{"image":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_image_upload")
+	})
+
+	t.Run("upload food image maps image errors", func(t *testing.T) {
+		cases := []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{service.ErrInvalidImage, http.StatusBadRequest, "invalid_image"},
+			{service.ErrUnsupportedImageType, http.StatusUnsupportedMediaType, "unsupported_image_type"},
+			{service.ErrImageTooLarge, http.StatusRequestEntityTooLarge, "image_too_large"},
+			{service.ErrImageStorageUnavailable, http.StatusServiceUnavailable, "image_storage_unavailable"},
+			{service.ErrFoodArchived, http.StatusConflict, "food_archived"},
+			{service.ErrFoodForbidden, http.StatusForbidden, "forbidden"},
+		}
+		for _, tc := range cases {
+			h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{imageFn: func(_ context.Context, _, _ uint, _ []byte) (food.Food, error) {
+				return food.Food{}, tc.err
+			}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+			router := newRouter(h)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, imageUploadRequest(t, "/api/v1/foods/3/image", []byte("image-bytes")))
+
+			assertErrorCode(t, rec, tc.status, tc.code)
+		}
+	})
+
+	t.Run("delete food image returns 204", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{imageFn: func(_ context.Context, _, id uint, data []byte) (food.Food, error) {
+			if id != 3 || data != nil {
+				return food.Food{}, errors.New("unexpected removal")
+			}
+			return food.Food{ID: 3}, nil
+		}}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/foods/3/image", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+		}
 	})
 }
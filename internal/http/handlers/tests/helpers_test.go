@@ -1,25 +1,48 @@
 package handlers_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
 
 	"goal-bite-api/internal/domain/bodyweightlog"
+	"goal-bite-api/internal/domain/cooking"
 	"goal-bite-api/internal/domain/food"
 	"goal-bite-api/internal/domain/meal"
 	"goal-bite-api/internal/domain/mealitem"
+	"goal-bite-api/internal/domain/passkey"
 	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/sharelink"
+	"goal-bite-api/internal/domain/shoppinglist"
 	"goal-bite-api/internal/domain/usergoal"
+	"goal-bite-api/internal/http/handlers"
+	httpmiddleware "goal-bite-api/internal/http/middleware"
 	"goal-bite-api/internal/service"
+	"goal-bite-api/internal/webauthn"
 )
 
 type fakeFoodService struct {
 	createFn     func(ctx context.Context, userID uint, in service.CreateFoodInput) (food.Food, error)
-	getFn        func(ctx context.Context, id uint) (food.Food, error)
-	getBarcodeFn func(ctx context.Context, barcode string) (food.Food, error)
-	listFn       func(ctx context.Context, limit, offset int) ([]food.Food, error)
-	searchFn     func(ctx context.Context, query string, limit, offset int) ([]food.Food, error)
+	getFn        func(ctx context.Context, userID, id uint) (food.Food, error)
+	getBarcodeFn func(ctx context.Context, userID uint, barcode string) (food.Food, error)
+	listFn       func(ctx context.Context, userID uint, in service.FoodListInput) ([]food.Food, error)
+	searchFn     func(ctx context.Context, userID uint, in service.FoodListInput) (service.FoodSearchResult, error)
+	categoriesFn func(ctx context.Context) ([]food.CategoryNode, error)
+	cookingFn    func(ctx context.Context) ([]cooking.Method, error)
 	updateFn     func(ctx context.Context, userID, id uint, in service.UpdateFoodInput) (food.Food, error)
 	deleteFn     func(ctx context.Context, userID, id uint) error
+	restoreFn    func(ctx context.Context, userID, id uint) (food.Food, error)
+	verifyFn     func(ctx context.Context, userID, id uint) (food.Food, error)
+	versionsFn   func(ctx context.Context, userID, id uint) ([]food.Version, error)
+	rollbackFn   func(ctx context.Context, userID, id uint, version int) (food.Food, error)
+	duplicatesFn func(ctx context.Context, userID, id uint) ([]service.FoodDuplicate, error)
+	mergeFn      func(ctx context.Context, userID, survivorID uint, foodIDs []uint) (service.FoodMergeResult, error)
+	reportFn     func(ctx context.Context, userID uint, limit, offset int) ([]service.FoodNutritionIssue, error)
+	imageFn      func(ctx context.Context, userID, id uint, data []byte) (food.Food, error)
 }
 
 func (f fakeFoodService) Create(ctx context.Context, userID uint, in service.CreateFoodInput) (food.Food, error) {
@@ -29,32 +52,46 @@ func (f fakeFoodService) Create(ctx context.Context, userID uint, in service.Cre
 	return f.createFn(ctx, userID, in)
 }
 
-func (f fakeFoodService) GetByID(ctx context.Context, id uint) (food.Food, error) {
+func (f fakeFoodService) GetByID(ctx context.Context, userID, id uint) (food.Food, error) {
 	if f.getFn == nil {
 		return food.Food{}, nil
 	}
-	return f.getFn(ctx, id)
+	return f.getFn(ctx, userID, id)
 }
 
-func (f fakeFoodService) GetByBarcode(ctx context.Context, barcode string) (food.Food, error) {
+func (f fakeFoodService) GetByBarcode(ctx context.Context, userID uint, barcode string) (food.Food, error) {
 	if f.getBarcodeFn == nil {
 		return food.Food{}, nil
 	}
-	return f.getBarcodeFn(ctx, barcode)
+	return f.getBarcodeFn(ctx, userID, barcode)
 }
 
-func (f fakeFoodService) List(ctx context.Context, limit, offset int) ([]food.Food, error) {
+func (f fakeFoodService) List(ctx context.Context, userID uint, in service.FoodListInput) ([]food.Food, error) {
 	if f.listFn == nil {
 		return nil, nil
 	}
-	return f.listFn(ctx, limit, offset)
+	return f.listFn(ctx, userID, in)
 }
 
-func (f fakeFoodService) Search(ctx context.Context, query string, limit, offset int) ([]food.Food, error) {
+func (f fakeFoodService) Search(ctx context.Context, userID uint, in service.FoodListInput) (service.FoodSearchResult, error) {
 	if f.searchFn == nil {
+		return service.FoodSearchResult{}, nil
+	}
+	return f.searchFn(ctx, userID, in)
+}
+
+func (f fakeFoodService) Categories(ctx context.Context) ([]food.CategoryNode, error) {
+	if f.categoriesFn == nil {
 		return nil, nil
 	}
-	return f.searchFn(ctx, query, limit, offset)
+	return f.categoriesFn(ctx)
+}
+
+func (f fakeFoodService) CookingMethods(ctx context.Context) ([]cooking.Method, error) {
+	if f.cookingFn == nil {
+		return nil, nil
+	}
+	return f.cookingFn(ctx)
 }
 
 func (f fakeFoodService) Update(ctx context.Context, userID, id uint, in service.UpdateFoodInput) (food.Food, error) {
@@ -71,13 +108,98 @@ func (f fakeFoodService) Delete(ctx context.Context, userID, id uint) error {
 	return f.deleteFn(ctx, userID, id)
 }
 
+func (f fakeFoodService) Restore(ctx context.Context, userID, id uint) (food.Food, error) {
+	if f.restoreFn == nil {
+		return food.Food{}, nil
+	}
+	return f.restoreFn(ctx, userID, id)
+}
+
+func (f fakeRecipeService) SetImage(ctx context.Context, userID, id uint, data []byte) (recipe.Recipe, error) {
+	if f.imageFn == nil {
+		return recipe.Recipe{}, nil
+	}
+	return f.imageFn(ctx, userID, id, data)
+}
+
+func (f fakeRecipeService) RemoveImage(ctx context.Context, userID, id uint) (recipe.Recipe, error) {
+	if f.imageFn == nil {
+		return recipe.Recipe{}, nil
+	}
+	return f.imageFn(ctx, userID, id, nil)
+}
+
+func (f fakeFoodService) Verify(ctx context.Context, userID, id uint) (food.Food, error) {
+	if f.verifyFn == nil {
+		return food.Food{}, nil
+	}
+	return f.verifyFn(ctx, userID, id)
+}
+
+func (f fakeFoodService) ListVersions(ctx context.Context, userID, id uint) ([]food.Version, error) {
+	if f.versionsFn == nil {
+		return nil, nil
+	}
+	return f.versionsFn(ctx, userID, id)
+}
+
+func (f fakeFoodService) Rollback(ctx context.Context, userID, id uint, version int) (food.Food, error) {
+	if f.rollbackFn == nil {
+		return food.Food{}, nil
+	}
+	return f.rollbackFn(ctx, userID, id, version)
+}
+
+func (f fakeFoodService) FindDuplicates(ctx context.Context, userID, id uint) ([]service.FoodDuplicate, error) {
+	if f.duplicatesFn == nil {
+		return nil, nil
+	}
+	return f.duplicatesFn(ctx, userID, id)
+}
+
+func (f fakeFoodService) Merge(ctx context.Context, userID, survivorID uint, foodIDs []uint) (service.FoodMergeResult, error) {
+	if f.mergeFn == nil {
+		return service.FoodMergeResult{}, nil
+	}
+	return f.mergeFn(ctx, userID, survivorID, foodIDs)
+}
+
+func (f fakeFoodService) NutritionReport(ctx context.Context, userID uint, limit, offset int) ([]service.FoodNutritionIssue, error) {
+	if f.reportFn == nil {
+		return nil, nil
+	}
+	return f.reportFn(ctx, userID, limit, offset)
+}
+
+func (f fakeFoodService) SetImage(ctx context.Context, userID, id uint, data []byte) (food.Food, error) {
+	if f.imageFn == nil {
+		return food.Food{}, nil
+	}
+	return f.imageFn(ctx, userID, id, data)
+}
+
+func (f fakeFoodService) RemoveImage(ctx context.Context, userID, id uint) (food.Food, error) {
+	if f.imageFn == nil {
+		return food.Food{}, nil
+	}
+	return f.imageFn(ctx, userID, id, nil)
+}
+
 type fakeRecipeService struct {
-	createFn func(ctx context.Context, userID uint, in service.CreateRecipeInput) (recipe.Recipe, error)
-	getFn    func(ctx context.Context, id uint) (recipe.Recipe, error)
-	listFn   func(ctx context.Context, limit, offset int) ([]recipe.Recipe, error)
-	searchFn func(ctx context.Context, query string, limit, offset int) ([]recipe.Recipe, error)
-	updateFn func(ctx context.Context, userID, id uint, in service.UpdateRecipeInput) (recipe.Recipe, error)
-	deleteFn func(ctx context.Context, userID, id uint) error
+	createFn    func(ctx context.Context, userID uint, in service.CreateRecipeInput) (recipe.Recipe, error)
+	getFn       func(ctx context.Context, id uint) (recipe.Recipe, error)
+	breakdownFn func(ctx context.Context, userID, id uint) (recipe.Recipe, service.NutritionBreakdown, error)
+	listFn      func(ctx context.Context, userID uint, in service.RecipeListInput) ([]recipe.Recipe, error)
+	updateFn    func(ctx context.Context, userID, id uint, in service.UpdateRecipeInput) (recipe.Recipe, error)
+	deleteFn    func(ctx context.Context, userID, id uint) error
+	restoreFn   func(ctx context.Context, userID, id uint) (recipe.Recipe, error)
+	refreshFn   func(ctx context.Context, userID, id uint) (recipe.Recipe, error)
+	scaleFn     func(ctx context.Context, id uint, servings int) (recipe.Recipe, error)
+	importFn    func(ctx context.Context, userID uint, content string) (service.RecipeDraft, error)
+	forkFn      func(ctx context.Context, userID, id uint) (recipe.Recipe, error)
+	forksFn     func(ctx context.Context, id uint, limit, offset int) ([]service.RecipeFork, error)
+	changesFn   func(ctx context.Context, id uint) (service.RecipeChanges, error)
+	imageFn     func(ctx context.Context, userID, id uint, data []byte) (recipe.Recipe, error)
 }
 
 func (f fakeRecipeService) Create(ctx context.Context, userID uint, in service.CreateRecipeInput) (recipe.Recipe, error) {
@@ -94,18 +216,18 @@ func (f fakeRecipeService) GetByID(ctx context.Context, id uint) (recipe.Recipe,
 	return f.getFn(ctx, id)
 }
 
-func (f fakeRecipeService) List(ctx context.Context, limit, offset int) ([]recipe.Recipe, error) {
-	if f.listFn == nil {
-		return nil, nil
+func (f fakeRecipeService) Breakdown(ctx context.Context, userID, id uint) (recipe.Recipe, service.NutritionBreakdown, error) {
+	if f.breakdownFn == nil {
+		return recipe.Recipe{}, service.NutritionBreakdown{}, nil
 	}
-	return f.listFn(ctx, limit, offset)
+	return f.breakdownFn(ctx, userID, id)
 }
 
-func (f fakeRecipeService) Search(ctx context.Context, query string, limit, offset int) ([]recipe.Recipe, error) {
-	if f.searchFn == nil {
+func (f fakeRecipeService) List(ctx context.Context, userID uint, in service.RecipeListInput) ([]recipe.Recipe, error) {
+	if f.listFn == nil {
 		return nil, nil
 	}
-	return f.searchFn(ctx, query, limit, offset)
+	return f.listFn(ctx, userID, in)
 }
 
 func (f fakeRecipeService) Update(ctx context.Context, userID, id uint, in service.UpdateRecipeInput) (recipe.Recipe, error) {
@@ -122,16 +244,67 @@ func (f fakeRecipeService) Delete(ctx context.Context, userID, id uint) error {
 	return f.deleteFn(ctx, userID, id)
 }
 
+func (f fakeRecipeService) Restore(ctx context.Context, userID, id uint) (recipe.Recipe, error) {
+	if f.restoreFn == nil {
+		return recipe.Recipe{}, nil
+	}
+	return f.restoreFn(ctx, userID, id)
+}
+
+func (f fakeRecipeService) Refresh(ctx context.Context, userID, id uint) (recipe.Recipe, error) {
+	if f.refreshFn == nil {
+		return recipe.Recipe{}, nil
+	}
+	return f.refreshFn(ctx, userID, id)
+}
+
+func (f fakeRecipeService) Scale(ctx context.Context, id uint, servings int) (recipe.Recipe, error) {
+	if f.scaleFn == nil {
+		return recipe.Recipe{}, nil
+	}
+	return f.scaleFn(ctx, id, servings)
+}
+
+func (f fakeRecipeService) Import(ctx context.Context, userID uint, content string) (service.RecipeDraft, error) {
+	if f.importFn == nil {
+		return service.RecipeDraft{}, nil
+	}
+	return f.importFn(ctx, userID, content)
+}
+
+func (f fakeRecipeService) Fork(ctx context.Context, userID, id uint) (recipe.Recipe, error) {
+	if f.forkFn == nil {
+		return recipe.Recipe{}, nil
+	}
+	return f.forkFn(ctx, userID, id)
+}
+
+func (f fakeRecipeService) Forks(ctx context.Context, id uint, limit, offset int) ([]service.RecipeFork, error) {
+	if f.forksFn == nil {
+		return nil, nil
+	}
+	return f.forksFn(ctx, id, limit, offset)
+}
+
+func (f fakeRecipeService) Changes(ctx context.Context, id uint) (service.RecipeChanges, error) {
+	if f.changesFn == nil {
+		return service.RecipeChanges{}, nil
+	}
+	return f.changesFn(ctx, id)
+}
+
 type fakeMealService struct {
 	createFn     func(ctx context.Context, in service.CreateMealInput) (meal.Meal, error)
 	updateFn     func(ctx context.Context, userID, mealID uint, in service.UpdateMealInput) (meal.Meal, error)
 	deleteFn     func(ctx context.Context, userID, mealID uint) error
 	getFn        func(ctx context.Context, userID, id uint) (meal.Meal, error)
+	breakdownFn  func(ctx context.Context, userID, id uint) (meal.Meal, service.NutritionBreakdown, error)
 	listFn       func(ctx context.Context, in service.ListMealsInput) ([]meal.Meal, error)
 	addItemFn    func(ctx context.Context, userID, mealID uint, in service.AddMealItemInput) (mealitem.MealItem, error)
 	updateItemFn func(ctx context.Context, userID, mealID, itemID uint, in service.UpdateMealItemInput) (mealitem.MealItem, error)
 	deleteItemFn func(ctx context.Context, userID, mealID, itemID uint) error
 	dailyFn      func(ctx context.Context, userID uint, date string) (service.DailyTotalsOutput, error)
+	imageFn      func(ctx context.Context, userID, mealID uint, data []byte) (meal.Meal, error)
 }
 
 func (f fakeMealService) Create(ctx context.Context, in service.CreateMealInput) (meal.Meal, error) {
@@ -162,6 +335,13 @@ func (f fakeMealService) GetByID(ctx context.Context, userID, id uint) (meal.Mea
 	return f.getFn(ctx, userID, id)
 }
 
+func (f fakeMealService) Breakdown(ctx context.Context, userID, id uint) (meal.Meal, service.NutritionBreakdown, error) {
+	if f.breakdownFn == nil {
+		return meal.Meal{}, service.NutritionBreakdown{}, nil
+	}
+	return f.breakdownFn(ctx, userID, id)
+}
+
 func (f fakeMealService) List(ctx context.Context, in service.ListMealsInput) ([]meal.Meal, error) {
 	if f.listFn == nil {
 		return nil, nil
@@ -197,6 +377,20 @@ func (f fakeMealService) GetDailyTotals(ctx context.Context, userID uint, date s
 	return f.dailyFn(ctx, userID, date)
 }
 
+func (f fakeMealService) SetImage(ctx context.Context, userID, mealID uint, data []byte) (meal.Meal, error) {
+	if f.imageFn == nil {
+		return meal.Meal{}, nil
+	}
+	return f.imageFn(ctx, userID, mealID, data)
+}
+
+func (f fakeMealService) RemoveImage(ctx context.Context, userID, mealID uint) (meal.Meal, error) {
+	if f.imageFn == nil {
+		return meal.Meal{}, nil
+	}
+	return f.imageFn(ctx, userID, mealID, nil)
+}
+
 type fakeBodyWeightLogService struct {
 	createFn func(ctx context.Context, in service.CreateBodyWeightLogInput) (bodyweightlog.BodyWeightLog, error)
 	listFn   func(ctx context.Context, in service.ListBodyWeightLogsInput) ([]bodyweightlog.BodyWeightLog, error)
@@ -259,6 +453,103 @@ func (f fakeAuthService) Logout(ctx context.Context, refreshToken string) error
 	return f.logoutFn(ctx, refreshToken)
 }
 
+type fakeMagicLinkService struct {
+	requestFn func(ctx context.Context, email string) error
+	consumeFn func(ctx context.Context, token string) (service.AuthResult, error)
+}
+
+func (f fakeMagicLinkService) RequestMagicLink(ctx context.Context, email string) error {
+	if f.requestFn == nil {
+		return nil
+	}
+	return f.requestFn(ctx, email)
+}
+
+func (f fakeMagicLinkService) ConsumeMagicLink(ctx context.Context, token string) (service.AuthResult, error) {
+	if f.consumeFn == nil {
+		return service.AuthResult{}, nil
+	}
+	return f.consumeFn(ctx, token)
+}
+
+type fakePasskeyService struct {
+	beginRegistrationFn  func(ctx context.Context, userID uint) (webauthn.CreationOptions, error)
+	finishRegistrationFn func(ctx context.Context, userID uint, in service.FinishPasskeyRegistrationInput) (passkey.Passkey, error)
+	beginLoginFn         func(ctx context.Context) (webauthn.RequestOptions, error)
+	finishLoginFn        func(ctx context.Context, assertion webauthn.AssertionResponse) (service.AuthResult, error)
+	listFn               func(ctx context.Context, userID uint) ([]passkey.Passkey, error)
+	deleteFn             func(ctx context.Context, userID, id uint) error
+}
+
+func (f fakePasskeyService) BeginRegistration(ctx context.Context, userID uint) (webauthn.CreationOptions, error) {
+	if f.beginRegistrationFn == nil {
+		return webauthn.CreationOptions{}, nil
+	}
+	return f.beginRegistrationFn(ctx, userID)
+}
+
+func (f fakePasskeyService) FinishRegistration(ctx context.Context, userID uint, in service.FinishPasskeyRegistrationInput) (passkey.Passkey, error) {
+	if f.finishRegistrationFn == nil {
+		return passkey.Passkey{}, nil
+	}
+	return f.finishRegistrationFn(ctx, userID, in)
+}
+
+func (f fakePasskeyService) BeginLogin(ctx context.Context) (webauthn.RequestOptions, error) {
+	if f.beginLoginFn == nil {
+		return webauthn.RequestOptions{}, nil
+	}
+	return f.beginLoginFn(ctx)
+}
+
+func (f fakePasskeyService) FinishLogin(ctx context.Context, assertion webauthn.AssertionResponse) (service.AuthResult, error) {
+	if f.finishLoginFn == nil {
+		return service.AuthResult{}, nil
+	}
+	return f.finishLoginFn(ctx, assertion)
+}
+
+func (f fakePasskeyService) List(ctx context.Context, userID uint) ([]passkey.Passkey, error) {
+	if f.listFn == nil {
+		return []passkey.Passkey{}, nil
+	}
+	return f.listFn(ctx, userID)
+}
+
+func (f fakePasskeyService) Delete(ctx context.Context, userID, id uint) error {
+	if f.deleteFn == nil {
+		return nil
+	}
+	return f.deleteFn(ctx, userID, id)
+}
+
+type fakeOIDCService struct {
+	providersFn func() []string
+	beginFn     func(ctx context.Context, provider string) (service.OIDCLoginStart, error)
+	finishFn    func(ctx context.Context, provider, code, state string) (service.AuthResult, error)
+}
+
+func (f fakeOIDCService) Providers() []string {
+	if f.providersFn == nil {
+		return []string{}
+	}
+	return f.providersFn()
+}
+
+func (f fakeOIDCService) BeginLogin(ctx context.Context, provider string) (service.OIDCLoginStart, error) {
+	if f.beginFn == nil {
+		return service.OIDCLoginStart{}, nil
+	}
+	return f.beginFn(ctx, provider)
+}
+
+func (f fakeOIDCService) FinishLogin(ctx context.Context, provider, code, state string) (service.AuthResult, error) {
+	if f.finishFn == nil {
+		return service.AuthResult{}, nil
+	}
+	return f.finishFn(ctx, provider, code, state)
+}
+
 type fakeUserGoalService struct {
 	upsertFn   func(ctx context.Context, in service.UpsertUserGoalInput) (usergoal.UserGoal, error)
 	getFn      func(ctx context.Context, userID uint) (usergoal.UserGoal, error)
@@ -296,3 +587,124 @@ func (f fakeEnergyService) GetProgress(ctx context.Context, in service.EnergyPro
 	}
 	return f.progressFn(ctx, in)
 }
+
+type fakeShoppingListService struct {
+	createFn  func(ctx context.Context, userID uint, in service.CreateShoppingListInput) (shoppinglist.ShoppingList, error)
+	getFn     func(ctx context.Context, userID, id uint) (shoppinglist.ShoppingList, error)
+	listFn    func(ctx context.Context, userID uint, limit, offset int) ([]shoppinglist.ShoppingList, error)
+	checkedFn func(ctx context.Context, userID, listID, itemID uint, checked bool) (shoppinglist.Item, error)
+	deleteFn  func(ctx context.Context, userID, id uint) error
+}
+
+func (f fakeShoppingListService) Create(ctx context.Context, userID uint, in service.CreateShoppingListInput) (shoppinglist.ShoppingList, error) {
+	if f.createFn == nil {
+		return shoppinglist.ShoppingList{}, nil
+	}
+	return f.createFn(ctx, userID, in)
+}
+
+func (f fakeShoppingListService) GetByID(ctx context.Context, userID, id uint) (shoppinglist.ShoppingList, error) {
+	if f.getFn == nil {
+		return shoppinglist.ShoppingList{}, nil
+	}
+	return f.getFn(ctx, userID, id)
+}
+
+func (f fakeShoppingListService) List(ctx context.Context, userID uint, limit, offset int) ([]shoppinglist.ShoppingList, error) {
+	if f.listFn == nil {
+		return []shoppinglist.ShoppingList{}, nil
+	}
+	return f.listFn(ctx, userID, limit, offset)
+}
+
+func (f fakeShoppingListService) SetItemChecked(ctx context.Context, userID, listID, itemID uint, checked bool) (shoppinglist.Item, error) {
+	if f.checkedFn == nil {
+		return shoppinglist.Item{}, nil
+	}
+	return f.checkedFn(ctx, userID, listID, itemID, checked)
+}
+
+func (f fakeShoppingListService) Delete(ctx context.Context, userID, id uint) error {
+	if f.deleteFn == nil {
+		return nil
+	}
+	return f.deleteFn(ctx, userID, id)
+}
+
+type fakeShareLinkService struct {
+	shareRecipeFn func(ctx context.Context, userID, recipeID uint, in service.CreateShareLinkInput) (sharelink.ShareLink, error)
+	shareMealFn   func(ctx context.Context, userID, mealID uint, in service.CreateShareLinkInput) (sharelink.ShareLink, error)
+	listFn        func(ctx context.Context, userID uint, limit, offset int) ([]sharelink.ShareLink, error)
+	revokeFn      func(ctx context.Context, userID, id uint) error
+	openFn        func(ctx context.Context, token string) (service.SharedView, error)
+}
+
+func (f fakeShareLinkService) ShareRecipe(ctx context.Context, userID, recipeID uint, in service.CreateShareLinkInput) (sharelink.ShareLink, error) {
+	if f.shareRecipeFn == nil {
+		return sharelink.ShareLink{}, nil
+	}
+	return f.shareRecipeFn(ctx, userID, recipeID, in)
+}
+
+func (f fakeShareLinkService) ShareMeal(ctx context.Context, userID, mealID uint, in service.CreateShareLinkInput) (sharelink.ShareLink, error) {
+	if f.shareMealFn == nil {
+		return sharelink.ShareLink{}, nil
+	}
+	return f.shareMealFn(ctx, userID, mealID, in)
+}
+
+func (f fakeShareLinkService) List(ctx context.Context, userID uint, limit, offset int) ([]sharelink.ShareLink, error) {
+	if f.listFn == nil {
+		return []sharelink.ShareLink{}, nil
+	}
+	return f.listFn(ctx, userID, limit, offset)
+}
+
+func (f fakeShareLinkService) Revoke(ctx context.Context, userID, id uint) error {
+	if f.revokeFn == nil {
+		return nil
+	}
+	return f.revokeFn(ctx, userID, id)
+}
+
+func (f fakeShareLinkService) Open(ctx context.Context, token string) (service.SharedView, error) {
+	if f.openFn == nil {
+		return service.SharedView{}, service.ErrShareLinkNotFound
+	}
+	return f.openFn(ctx, token)
+}
+
+func assertErrorCode(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("expected status %d, got %d", status, rec.Code)
+	}
+	var payload handlers.ErrorEnvelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Error.Code != code {
+		t.Fatalf("expected %s, got %q", code, payload.Error.Code)
+	}
+}
+
+// imageUploadRequest builds a multipart request carrying data in the "image"
+// field, authenticated as user 7.
+func imageUploadRequest(t *testing.T, target string, data []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("image", "photo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPut, target, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+}
@@ -28,6 +28,8 @@ func TestMealHandlers(t *testing.T) {
 		r.Post("/api/v1/meals/{id}/items", h.AddMealItem)
 		r.Patch("/api/v1/meals/{meal_id}/items/{item_id}", h.UpdateMealItem)
 		r.Delete("/api/v1/meals/{meal_id}/items/{item_id}", h.DeleteMealItem)
+		r.Put("/api/v1/meals/{id}/image", h.UploadMealImage)
+		r.Delete("/api/v1/meals/{id}/image", h.DeleteMealImage)
 		return r
 	}
 
@@ -61,6 +63,46 @@ func TestMealHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("add meal item by cooked weight passes it on", func(t *testing.T) {
+		fid, method := uint(1), uint(2)
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{addItemFn: func(_ context.Context, _ uint, _ uint, in service.AddMealItemInput) (mealitem.MealItem, error) {
+			if in.CookedWeightG == nil || *in.CookedWeightG != 220 || in.CookingMethodID == nil || *in.CookingMethodID != method {
+				t.Fatalf("unexpected input %+v", in)
+			}
+			return mealitem.MealItem{ID: 1, MealID: 1, FoodID: &fid, WeightG: 100, CookedWeightG: in.CookedWeightG, CookingMethodID: in.CookingMethodID}, nil
+		}}, fakeBodyWeightLogService{})
+		r := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/meals/1/items", strings.NewReader(`{"food_id":1,"cooked_weight_g":220,"cooking_method_id":2}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 1))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"cooked_weight_g":220`) {
+			t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("add meal item with unknown cooking method returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{addItemFn: func(_ context.Context, _ uint, _ uint, _ service.AddMealItemInput) (mealitem.MealItem, error) {
+			return mealitem.MealItem{}, service.ErrCookingMethodNotFound
+		}}, fakeBodyWeightLogService{})
+		r := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/meals/1/items", strings.NewReader(`{"food_id":1,"cooked_weight_g":220,"cooking_method_id":99}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 1))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_cooking_method")
+	})
+
+	t.Run("add meal item with cooked weight and grams returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		r := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/meals/1/items", strings.NewReader(`{"food_id":1,"weight_g":100,"cooked_weight_g":220,"cooking_method_id":2}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 1))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_meal_item_payload")
+	})
+
 	t.Run("create meal with invalid nested item returns 400", func(t *testing.T) {
 		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
 		r := newRouter(h)
@@ -170,4 +212,73 @@ func TestMealHandlers(t *testing.T) {
 			t.Fatalf("expected %d, got %d", http.StatusNoContent, rec.Code)
 		}
 	})
+
+	t.Run("upload meal image returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{imageFn: func(_ context.Context, userID, mealID uint, _ []byte) (meal.Meal, error) {
+			url := "http://localhost:8080/media/meals/5/a.jpg"
+			return meal.Meal{ID: mealID, UserID: userID, MealType: meal.MealTypeLunch, ImageURL: &url}, nil
+		}}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, imageUploadRequest(t, "/api/v1/meals/5/image", []byte("image-bytes")))
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"image_url":"http://localhost:8080/media/meals/5/a.jpg"`) {
+			t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("upload image to missing meal returns 404", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{imageFn: func(_ context.Context, _, _ uint, _ []byte) (meal.Meal, error) {
+			return meal.Meal{}, service.ErrMealNotFound
+		}}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, imageUploadRequest(t, "/api/v1/meals/5/image", []byte("image-bytes")))
+
+		assertErrorCode(t, rec, http.StatusNotFound, "meal_not_found")
+	})
+
+	t.Run("delete meal image returns 204", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{imageFn: func(_ context.Context, _, mealID uint, _ []byte) (meal.Meal, error) {
+			return meal.Meal{ID: mealID}, nil
+		}}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/meals/5/image", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected %d, got %d", http.StatusNoContent, rec.Code)
+		}
+	})
+
+	t.Run("get meal with breakdown returns the items' contributions", func(t *testing.T) {
+		fid := uint(3)
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{breakdownFn: func(_ context.Context, userID, id uint) (meal.Meal, service.NutritionBreakdown, error) {
+			return meal.Meal{ID: id, UserID: userID, MealType: meal.MealTypeLunch}, service.NutritionBreakdown{
+				Items: []service.BreakdownItem{{ID: 2, FoodID: &fid, Name: "Olive oil", WeightG: 10, Contribution: service.NutrientAmounts{Kcal: 88, FatG: 10}}},
+			}, nil
+		}}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/meals/5?breakdown=true", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"display_name":"Olive oil"`) || !strings.Contains(rec.Body.String(), `"calorie_split"`) {
+			t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("get meal with invalid breakdown returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/meals/5?breakdown=maybe", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_breakdown")
+	})
 }
@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"goal-bite-api/internal/domain/user"
+	"goal-bite-api/internal/http/handlers"
+	"goal-bite-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestOIDCHandlers(t *testing.T) {
+	newRouter := func(svc fakeOIDCService) http.Handler {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{}, svc)
+		r := chi.NewRouter()
+		r.Get("/api/v1/auth/oidc/providers", h.ListOIDCProviders)
+		r.Post("/api/v1/auth/oidc/{provider}/begin", h.BeginOIDCLogin)
+		r.Post("/api/v1/auth/oidc/{provider}/finish", h.FinishOIDCLogin)
+		return r
+	}
+
+	t.Run("providers are listed", func(t *testing.T) {
+		r := newRouter(fakeOIDCService{providersFn: func() []string { return []string{"acme"} }})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/providers", nil))
+		if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `["acme"]` {
+			t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("begin returns authorization url", func(t *testing.T) {
+		r := newRouter(fakeOIDCService{beginFn: func(_ context.Context, provider string) (service.OIDCLoginStart, error) {
+			if provider != "acme" {
+				t.Fatalf("expected provider acme, got %q", provider)
+			}
+			return service.OIDCLoginStart{AuthorizationURL: "https://idp.example.com/authorize?state=x"}, nil
+		}})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/acme/begin", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var out service.OIDCLoginStart
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if out.AuthorizationURL == "" {
+			t.Fatalf("expected authorization url")
+		}
+	})
+
+	t.Run("unknown provider returns 404", func(t *testing.T) {
+		r := newRouter(fakeOIDCService{beginFn: func(_ context.Context, _ string) (service.OIDCLoginStart, error) {
+			return service.OIDCLoginStart{}, service.ErrOIDCProviderNotFound
+		}})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/nope/begin", nil))
+		assertErrorCode(t, rec, http.StatusNotFound, "oidc_provider_not_found")
+	})
+
+	t.Run("finish without state returns 400", func(t *testing.T) {
+		r := newRouter(fakeOIDCService{})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/acme/finish", strings.NewReader(`{"code":"c"}`)))
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_oidc_payload")
+	})
+
+	t.Run("finish error mapping", func(t *testing.T) {
+		cases := []struct {
+			err    error
+			status int
+			code   string
+		}{
+			{service.ErrInvalidOIDCState, http.StatusUnauthorized, "invalid_oidc_state"},
+			{service.ErrOIDCLoginFailed, http.StatusUnauthorized, "oidc_login_failed"},
+			{service.ErrOIDCEmailNotVerified, http.StatusForbidden, "oidc_email_not_verified"},
+			{service.ErrOIDCProviderUnavailable, http.StatusBadGateway, "oidc_provider_unavailable"},
+		}
+		for _, tc := range cases {
+			r := newRouter(fakeOIDCService{finishFn: func(_ context.Context, _, _, _ string) (service.AuthResult, error) {
+				return service.AuthResult{}, tc.err
+			}})
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/acme/finish", strings.NewReader(`{"code":"c","state":"s"}`)))
+			assertErrorCode(t, rec, tc.status, tc.code)
+		}
+	})
+
+	t.Run("finish returns auth result", func(t *testing.T) {
+		r := newRouter(fakeOIDCService{finishFn: func(_ context.Context, provider, code, state string) (service.AuthResult, error) {
+			if provider != "acme" || code != "c" || state != "s" {
+				t.Fatalf("unexpected args %q %q %q", provider, code, state)
+			}
+			return service.AuthResult{Token: "t", AccessToken: "t", RefreshToken: "r", User: user.User{ID: 3}}, nil
+		}})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/acme/finish", strings.NewReader(`{"code":"c","state":"s"}`)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"goal-bite-api/internal/domain/passkey"
+	"goal-bite-api/internal/http/handlers"
+	httpmiddleware "goal-bite-api/internal/http/middleware"
+	"goal-bite-api/internal/service"
+	"goal-bite-api/internal/webauthn"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestPasskeyHandlers(t *testing.T) {
+	newRouter := func(svc fakePasskeyService) http.Handler {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{}, svc)
+		r := chi.NewRouter()
+		r.Post("/api/v1/auth/passkeys/register/begin", h.BeginPasskeyRegistration)
+		r.Post("/api/v1/auth/passkeys/register/finish", h.FinishPasskeyRegistration)
+		r.Get("/api/v1/auth/passkeys", h.ListPasskeys)
+		r.Delete("/api/v1/auth/passkeys/{id}", h.DeletePasskey)
+		r.Post("/api/v1/auth/passkeys/login/begin", h.BeginPasskeyLogin)
+		r.Post("/api/v1/auth/passkeys/login/finish", h.FinishPasskeyLogin)
+		return r
+	}
+
+	t.Run("register begin returns creation options", func(t *testing.T) {
+		r := newRouter(fakePasskeyService{
+			beginRegistrationFn: func(_ context.Context, userID uint) (webauthn.CreationOptions, error) {
+				if userID != 7 {
+					t.Fatalf("expected user 7, got %d", userID)
+				}
+				return webauthn.CreationOptions{Challenge: "abc", Attestation: "none"}, nil
+			},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/register/begin", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var out webauthn.CreationOptions
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if out.Challenge != "abc" {
+			t.Fatalf("expected challenge abc, got %q", out.Challenge)
+		}
+	})
+
+	t.Run("register finish without name returns 400", func(t *testing.T) {
+		r := newRouter(fakePasskeyService{})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/register/finish", strings.NewReader(`{"credential":{"id":"a","rawId":"a","type":"public-key","response":{"clientDataJSON":"x","attestationObject":"y"}}}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		var payload handlers.ErrorEnvelope
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if payload.Error.Code != "invalid_passkey_payload" {
+			t.Fatalf("expected invalid_passkey_payload, got %q", payload.Error.Code)
+		}
+	})
+
+	t.Run("register finish verification failure returns 400", func(t *testing.T) {
+		r := newRouter(fakePasskeyService{
+			finishRegistrationFn: func(_ context.Context, _ uint, _ service.FinishPasskeyRegistrationInput) (passkey.Passkey, error) {
+				return passkey.Passkey{}, service.ErrInvalidPasskeyResponse
+			},
+		})
+		body := `{"name":"Laptop","credential":{"id":"a","rawId":"a","type":"public-key","authenticatorAttachment":"platform","clientExtensionResults":{},"response":{"clientDataJSON":"x","attestationObject":"y","transports":["internal"],"publicKeyAlgorithm":-7}}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/register/finish", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		var payload handlers.ErrorEnvelope
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if payload.Error.Code != "passkey_verification_failed" {
+			t.Fatalf("expected passkey_verification_failed, got %q", payload.Error.Code)
+		}
+	})
+
+	t.Run("list omits public key", func(t *testing.T) {
+		r := newRouter(fakePasskeyService{
+			listFn: func(_ context.Context, _ uint) ([]passkey.Passkey, error) {
+				return []passkey.Passkey{{ID: 1, UserID: 7, Name: "Laptop", PublicKey: []byte{1, 2, 3}}}, nil
+			},
+		})
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/passkeys", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "public_key") {
+			t.Fatalf("expected public key to be omitted: %s", rec.Body.String())
+		}
+	})
+
+	t.Run("delete foreign passkey returns 403", func(t *testing.T) {
+		r := newRouter(fakePasskeyService{
+			deleteFn: func(_ context.Context, _, _ uint) error {
+				return service.ErrPasskeyForbidden
+			},
+		})
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/auth/passkeys/3", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+		}
+	})
+
+	t.Run("login finish failure returns 401", func(t *testing.T) {
+		r := newRouter(fakePasskeyService{
+			finishLoginFn: func(_ context.Context, _ webauthn.AssertionResponse) (service.AuthResult, error) {
+				return service.AuthResult{}, service.ErrPasskeyLoginFailed
+			},
+		})
+		body := `{"credential":{"id":"a","rawId":"a","type":"public-key","response":{"clientDataJSON":"x","authenticatorData":"y","signature":"z","userHandle":"u"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/login/finish", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+		}
+		var payload handlers.ErrorEnvelope
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if payload.Error.Code != "invalid_credentials" {
+			t.Fatalf("expected invalid_credentials, got %q", payload.Error.Code)
+		}
+	})
+
+	t.Run("login finish returns auth result", func(t *testing.T) {
+		r := newRouter(fakePasskeyService{
+			finishLoginFn: func(_ context.Context, _ webauthn.AssertionResponse) (service.AuthResult, error) {
+				return service.AuthResult{Token: "a", AccessToken: "a", RefreshToken: "r"}, nil
+			},
+		})
+		body := `{"credential":{"id":"a","rawId":"a","type":"public-key","response":{"clientDataJSON":"x","authenticatorData":"y","signature":"z"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/passkeys/login/finish", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+}
@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/domain/recipe"
 	"goal-bite-api/internal/domain/recipeingredient"
 	"goal-bite-api/internal/http/handlers"
@@ -24,9 +26,18 @@ func TestRecipeHandlers(t *testing.T) {
 		r := chi.NewRouter()
 		r.Post("/api/v1/recipes", h.CreateRecipe)
 		r.Get("/api/v1/recipes", h.ListRecipes)
+		r.Post("/api/v1/recipes/import", h.ImportRecipe)
 		r.Get("/api/v1/recipes/{id}", h.GetRecipeByID)
+		r.Get("/api/v1/recipes/{id}/scaled", h.ScaleRecipe)
 		r.Patch("/api/v1/recipes/{id}", h.UpdateRecipe)
 		r.Delete("/api/v1/recipes/{id}", h.DeleteRecipe)
+		r.Post("/api/v1/recipes/{id}/restore", h.RestoreRecipe)
+		r.Post("/api/v1/recipes/{id}/recalculate", h.RecalculateRecipe)
+		r.Post("/api/v1/recipes/{id}/fork", h.ForkRecipe)
+		r.Get("/api/v1/recipes/{id}/forks", h.ListRecipeForks)
+		r.Get("/api/v1/recipes/{id}/changes", h.GetRecipeChanges)
+		r.Put("/api/v1/recipes/{id}/image", h.UploadRecipeImage)
+		r.Delete("/api/v1/recipes/{id}/image", h.DeleteRecipeImage)
 		return r
 	}
 
@@ -70,8 +81,9 @@ func TestRecipeHandlers(t *testing.T) {
 
 	t.Run("get recipe returns 200", func(t *testing.T) {
 		now := time.Now().UTC()
+		foodID := uint(1)
 		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{getFn: func(_ context.Context, _ uint) (recipe.Recipe, error) {
-			return recipe.Recipe{ID: 1, Name: "Goulash", YieldWeightG: 1200, KcalPer100g: 120, ProteinPer100g: 8, CarbsPer100g: 10, FatPer100g: 5, Ingredients: []recipeingredient.RecipeIngredient{{ID: 1, RecipeID: 1, FoodID: 1, RawWeightG: 500}}, CreatedAt: now, UpdatedAt: now}, nil
+			return recipe.Recipe{ID: 1, Name: "Goulash", YieldWeightG: 1200, KcalPer100g: 120, ProteinPer100g: 8, CarbsPer100g: 10, FatPer100g: 5, Ingredients: []recipeingredient.RecipeIngredient{{ID: 1, RecipeID: 1, FoodID: &foodID, RawWeightG: 500}}, CreatedAt: now, UpdatedAt: now}, nil
 		}}, fakeMealService{}, fakeBodyWeightLogService{})
 		router := newRouter(h)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes/1", nil)
@@ -92,19 +104,67 @@ func TestRecipeHandlers(t *testing.T) {
 		}
 	})
 
-	t.Run("list recipes with q uses search", func(t *testing.T) {
+	t.Run("get recipe with breakdown returns the ingredients' contributions", func(t *testing.T) {
+		foodID := uint(1)
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{breakdownFn: func(_ context.Context, userID, id uint) (recipe.Recipe, service.NutritionBreakdown, error) {
+			if userID != 7 || id != 1 {
+				return recipe.Recipe{}, service.NutritionBreakdown{}, errors.New("unexpected call")
+			}
+			return recipe.Recipe{ID: 1, Name: "Goulash"}, service.NutritionBreakdown{
+				Items:        []service.BreakdownItem{{ID: 4, FoodID: &foodID, Name: "Beef", WeightG: 500, Share: service.NutrientShares{FatPct: 80}}},
+				CalorieSplit: service.MacroSplit{ProteinPct: 40, CarbsPct: 10, FatPct: 50},
+			}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes/1?breakdown=true", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var payload struct {
+			Name      string                     `json:"name"`
+			Breakdown service.NutritionBreakdown `json:"breakdown"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if payload.Name != "Goulash" || len(payload.Breakdown.Items) != 1 || payload.Breakdown.Items[0].DisplayName != "Beef" || payload.Breakdown.CalorieSplit.FatPct != 50 {
+			t.Fatalf("unexpected payload: %s", rec.Body.String())
+		}
+	})
+
+	t.Run("get recipe with breakdown of missing recipe returns 404", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{breakdownFn: func(_ context.Context, _, _ uint) (recipe.Recipe, service.NutritionBreakdown, error) {
+			return recipe.Recipe{}, service.NutritionBreakdown{}, service.ErrRecipeNotFound
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes/1?breakdown=1", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusNotFound, "recipe_not_found")
+	})
+
+	t.Run("list recipes passes q to list", func(t *testing.T) {
 		now := time.Now().UTC()
-		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{searchFn: func(_ context.Context, query string, limit, offset int) ([]recipe.Recipe, error) {
-			if query != "gou" {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{listFn: func(_ context.Context, userID uint, in service.RecipeListInput) ([]recipe.Recipe, error) {
+			if userID != 7 || in.Query != "gou" || in.ExcludeConflicts {
 				return nil, errors.New("unexpected query")
 			}
-			if limit != 20 || offset != 0 {
+			if in.Limit != 20 || in.Offset != 0 {
 				return nil, errors.New("unexpected pagination")
 			}
 			return []recipe.Recipe{{ID: 1, Name: "Goulash", CreatedAt: now, UpdatedAt: now}}, nil
 		}}, fakeMealService{}, fakeBodyWeightLogService{})
 		router := newRouter(h)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes?q=gou", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
 		rec := httptest.NewRecorder()
 		router.ServeHTTP(rec, req)
 
@@ -113,6 +173,105 @@ func TestRecipeHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("list recipes shows names in the requested language", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{listFn: func(_ context.Context, _ uint, in service.RecipeListInput) ([]recipe.Recipe, error) {
+			if len(in.Languages) != 1 || in.Languages[0] != "hu" {
+				t.Fatalf("unexpected languages %q", in.Languages)
+			}
+			return []recipe.Recipe{{ID: 1, Name: "Goulash", Translations: []locale.Translation{{Locale: "hu", Name: "Gulyás"}}}}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes?q=guly", nil)
+		req.Header.Set("Accept-Language", "hu-HU")
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var payload []recipe.Recipe
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(payload) != 1 || payload[0].DisplayName != "Gulyás" {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+	})
+
+	t.Run("list recipes rejects invalid exclude_conflicts", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes?exclude_conflicts=maybe", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_exclude_conflicts")
+	})
+
+	t.Run("list recipes passes tag and time filters", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{listFn: func(_ context.Context, _ uint, in service.RecipeListInput) ([]recipe.Recipe, error) {
+			if len(in.Tags) != 2 || in.Tags[0] != "dinner" || in.Tags[1] != "quick" || in.MaxTotalTimeMin != 30 {
+				t.Fatalf("unexpected filters %+v", in)
+			}
+			return []recipe.Recipe{}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes?tag=dinner&tag=quick&max_total_time=30", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("list recipes rejects invalid max_total_time", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		for _, target := range []string{"/api/v1/recipes?max_total_time=0", "/api/v1/recipes?max_total_time=soon"} {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assertErrorCode(t, rec, http.StatusBadRequest, "invalid_max_total_time")
+		}
+	})
+
+	t.Run("create recipe rejects invalid tags", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{createFn: func(_ context.Context, _ uint, _ service.CreateRecipeInput) (recipe.Recipe, error) {
+			return recipe.Recipe{}, service.ErrInvalidRecipeTags
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		body := `{"name":"Goulash","yield_weight_g":1200,"tags":[""],"ingredients":[{"food_id":1,"raw_weight_g":500}]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_recipe_tags")
+	})
+
+	t.Run("create recipe with unknown cooking method returns 400", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{createFn: func(_ context.Context, _ uint, in service.CreateRecipeInput) (recipe.Recipe, error) {
+			if in.YieldWeightG != 0 || in.Ingredients[0].CookingMethodID != 99 {
+				return recipe.Recipe{}, errors.New("unexpected input")
+			}
+			return recipe.Recipe{}, service.ErrCookingMethodNotFound
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		body := `{"name":"Pasta","ingredients":[{"food_id":1,"raw_weight_g":100,"cooking_method_id":99}]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes", strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_cooking_method")
+	})
+
 	t.Run("update recipe forbidden returns 403", func(t *testing.T) {
 		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{updateFn: func(_ context.Context, _, _ uint, _ service.UpdateRecipeInput) (recipe.Recipe, error) {
 			return recipe.Recipe{}, service.ErrRecipeForbidden
@@ -145,4 +304,264 @@ func TestRecipeHandlers(t *testing.T) {
 			t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
 		}
 	})
+
+	t.Run("restore recipe returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{restoreFn: func(_ context.Context, userID, id uint) (recipe.Recipe, error) {
+			if userID != 7 || id != 1 {
+				return recipe.Recipe{}, errors.New("unexpected args")
+			}
+			return recipe.Recipe{ID: 1, Name: "Goulash"}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes/1/restore", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("recalculate recipe returns refreshed values", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{refreshFn: func(_ context.Context, userID, id uint) (recipe.Recipe, error) {
+			if userID != 7 || id != 1 {
+				return recipe.Recipe{}, errors.New("unexpected args")
+			}
+			return recipe.Recipe{ID: 1, Name: "Goulash", ProteinPer100g: 24}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes/1/recalculate", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `"stale":false`) {
+			t.Fatalf("expected stale flag in response, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("recalculate foreign recipe returns 403", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{refreshFn: func(_ context.Context, _, _ uint) (recipe.Recipe, error) {
+			return recipe.Recipe{}, service.ErrRecipeForbidden
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes/1/recalculate", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 8))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusForbidden, "forbidden")
+	})
+
+	t.Run("scale recipe passes servings", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{scaleFn: func(_ context.Context, id uint, servings int) (recipe.Recipe, error) {
+			if id != 1 || servings != 4 {
+				return recipe.Recipe{}, errors.New("unexpected args")
+			}
+			return recipe.Recipe{ID: 1, Name: "Goulash", Servings: 4}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes/1/scaled?servings=4", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `"servings":4`) {
+			t.Fatalf("expected scaled servings in response, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("scale recipe rejects invalid servings", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{scaleFn: func(_ context.Context, _ uint, _ int) (recipe.Recipe, error) {
+			return recipe.Recipe{}, service.ErrInvalidServings
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		for _, target := range []string{"/api/v1/recipes/1/scaled", "/api/v1/recipes/1/scaled?servings=two", "/api/v1/recipes/1/scaled?servings=0"} {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assertErrorCode(t, rec, http.StatusBadRequest, "invalid_servings")
+		}
+	})
+
+	t.Run("import recipe returns draft", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{importFn: func(_ context.Context, userID uint, content string) (service.RecipeDraft, error) {
+			if userID != 7 || content != "200 g rice" {
+				return service.RecipeDraft{}, errors.New("unexpected import")
+			}
+			weight := 200.0
+			foodID := uint(3)
+			return service.RecipeDraft{Servings: 1, YieldWeightG: 200, Ingredients: []service.DraftIngredient{{
+				Text: "200 g rice", Name: "rice", RawWeightG: &weight, FoodID: &foodID,
+				Matches: []service.FoodMatch{{Food: food.Food{ID: 3, Name: "Rice"}, Confidence: 1}},
+			}}}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes/import", strings.NewReader(`{"content":"200 g rice"}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), `"food_id":3`) || !strings.Contains(rec.Body.String(), `"display_name":"Rice"`) {
+			t.Fatalf("expected matched food in draft, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("import recipe rejects content without ingredients", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{importFn: func(_ context.Context, _ uint, _ string) (service.RecipeDraft, error) {
+			return service.RecipeDraft{}, service.ErrInvalidRecipeImport
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		for _, body := range []string{`{"content":"  "}`, `{"content":"<html></html>"}`} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes/import", strings.NewReader(body))
+			req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assertErrorCode(t, rec, http.StatusBadRequest, "invalid_recipe_import")
+		}
+	})
+
+	t.Run("fork recipe returns 201", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{forkFn: func(_ context.Context, userID, id uint) (recipe.Recipe, error) {
+			if userID != 7 || id != 3 {
+				return recipe.Recipe{}, errors.New("unexpected fork")
+			}
+			origin := uint(3)
+			return recipe.Recipe{ID: 9, UserID: 7, Name: "Goulash", ForkedFromID: &origin}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes/3/fork", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `"forked_from_id":3`) {
+			t.Fatalf("expected origin in response, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("fork archived recipe returns 409", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{forkFn: func(_ context.Context, _, _ uint) (recipe.Recipe, error) {
+			return recipe.Recipe{}, service.ErrRecipeArchived
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/recipes/3/fork", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusConflict, "recipe_archived")
+	})
+
+	t.Run("list recipe forks returns changes", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{forksFn: func(_ context.Context, id uint, limit, offset int) ([]service.RecipeFork, error) {
+			if id != 3 || limit != 20 || offset != 0 {
+				return nil, errors.New("unexpected args")
+			}
+			return []service.RecipeFork{{
+				Recipe:  recipe.Recipe{ID: 9, Name: "Goulash"},
+				Changes: service.RecipeChanges{OriginID: 3, Fields: []string{"name"}, Ingredients: []service.IngredientChange{}},
+			}}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes/3/forks", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `"display_name":"Goulash"`) || !strings.Contains(rec.Body.String(), `"fields":["name"]`) {
+			t.Fatalf("unexpected body %s", rec.Body.String())
+		}
+	})
+
+	t.Run("changes of an original recipe returns 404", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{changesFn: func(_ context.Context, _ uint) (service.RecipeChanges, error) {
+			return service.RecipeChanges{}, service.ErrRecipeNotForked
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes/3/changes", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusNotFound, "recipe_not_forked")
+	})
+
+	t.Run("update archived recipe returns 409", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{updateFn: func(_ context.Context, _, _ uint, _ service.UpdateRecipeInput) (recipe.Recipe, error) {
+			return recipe.Recipe{}, service.ErrRecipeArchived
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/recipes/1", strings.NewReader(`{"name":"x"}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assertErrorCode(t, rec, http.StatusConflict, "recipe_archived")
+	})
+
+	t.Run("upload recipe image returns 200", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{imageFn: func(_ context.Context, userID, id uint, data []byte) (recipe.Recipe, error) {
+			if userID != 7 || id != 1 || string(data) != "image-bytes" {
+				return recipe.Recipe{}, errors.New("unexpected upload")
+			}
+			thumb := "http://localhost:8080/media/recipes/1/a_thumb.jpg"
+			return recipe.Recipe{ID: 1, UserID: 7, Name: "Bowl", ThumbnailURL: &thumb}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, imageUploadRequest(t, "/api/v1/recipes/1/image", []byte("image-bytes")))
+
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"thumbnail_url":"http://localhost:8080/media/recipes/1/a_thumb.jpg"`) {
+			t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("upload image to another user's recipe returns 403", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{imageFn: func(_ context.Context, _, _ uint, _ []byte) (recipe.Recipe, error) {
+			return recipe.Recipe{}, service.ErrRecipeForbidden
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, imageUploadRequest(t, "/api/v1/recipes/1/image", []byte("image-bytes")))
+
+		assertErrorCode(t, rec, http.StatusForbidden, "forbidden")
+	})
+
+	t.Run("delete recipe image returns 204", func(t *testing.T) {
+		h := handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{imageFn: func(_ context.Context, _, _ uint, data []byte) (recipe.Recipe, error) {
+			if data != nil {
+				return recipe.Recipe{}, errors.New("unexpected upload")
+			}
+			return recipe.Recipe{ID: 1}, nil
+		}}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/recipes/1/image", nil)
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 7))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+		}
+	})
 }
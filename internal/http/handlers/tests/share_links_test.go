@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/domain/sharelink"
+	"goal-bite-api/internal/http/handlers"
+	httpmiddleware "goal-bite-api/internal/http/middleware"
+	"goal-bite-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestShareLinkHandlers(t *testing.T) {
+	newRouter := func(h *handlers.Handler) http.Handler {
+		r := chi.NewRouter()
+		r.Post("/api/v1/recipes/{id}/share-links", h.ShareRecipe)
+		r.Post("/api/v1/meals/{id}/share-links", h.ShareMeal)
+		r.Get("/api/v1/share-links", h.ListShareLinks)
+		r.Delete("/api/v1/share-links/{id}", h.RevokeShareLink)
+		r.Get("/api/v1/public/shared/{token}", h.GetSharedContent)
+		return r
+	}
+	newHandler := func(links fakeShareLinkService) *handlers.Handler {
+		return handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{}, links)
+	}
+	serve := func(h *handlers.Handler, method, target, body string, userID uint) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if userID != 0 {
+			req = req.WithContext(httpmiddleware.WithUserID(req.Context(), userID))
+		}
+		rec := httptest.NewRecorder()
+		newRouter(h).ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("share recipe returns 201 with the token", func(t *testing.T) {
+		var got service.CreateShareLinkInput
+		h := newHandler(fakeShareLinkService{shareRecipeFn: func(_ context.Context, _, recipeID uint, in service.CreateShareLinkInput) (sharelink.ShareLink, error) {
+			got = in
+			return sharelink.ShareLink{ID: 1, RecipeID: &recipeID, Token: "secret-token"}, nil
+		}})
+		rec := serve(h, http.MethodPost, "/api/v1/recipes/3/share-links", `{"expires_at":"2026-03-01T00:00:00Z"}`, 1)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected %d, got %d", http.StatusCreated, rec.Code)
+		}
+		if got.ExpiresAt == nil || !got.ExpiresAt.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("expected the expiry to reach the service, got %+v", got)
+		}
+		if !strings.Contains(rec.Body.String(), `"token":"secret-token"`) {
+			t.Fatalf("expected the token in the response, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("share meal accepts an empty body", func(t *testing.T) {
+		h := newHandler(fakeShareLinkService{shareMealFn: func(_ context.Context, _, _ uint, in service.CreateShareLinkInput) (sharelink.ShareLink, error) {
+			if in.ExpiresAt != nil {
+				t.Fatalf("expected no expiry, got %v", in.ExpiresAt)
+			}
+			return sharelink.ShareLink{ID: 1}, nil
+		}})
+		rec := serve(h, http.MethodPost, "/api/v1/meals/2/share-links", "", 1)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected %d, got %d", http.StatusCreated, rec.Code)
+		}
+	})
+
+	t.Run("share rejects a bad expiry", func(t *testing.T) {
+		rec := serve(newHandler(fakeShareLinkService{}), http.MethodPost, "/api/v1/recipes/3/share-links", `{"expires_at":"tomorrow"}`, 1)
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_share_link_payload")
+	})
+
+	t.Run("share maps another user's recipe to 403", func(t *testing.T) {
+		h := newHandler(fakeShareLinkService{shareRecipeFn: func(_ context.Context, _, _ uint, _ service.CreateShareLinkInput) (sharelink.ShareLink, error) {
+			return sharelink.ShareLink{}, service.ErrRecipeForbidden
+		}})
+		rec := serve(h, http.MethodPost, "/api/v1/recipes/3/share-links", `{}`, 1)
+		assertErrorCode(t, rec, http.StatusForbidden, "forbidden")
+	})
+
+	t.Run("share maps a missing meal to 404", func(t *testing.T) {
+		h := newHandler(fakeShareLinkService{shareMealFn: func(_ context.Context, _, _ uint, _ service.CreateShareLinkInput) (sharelink.ShareLink, error) {
+			return sharelink.ShareLink{}, service.ErrMealNotFound
+		}})
+		rec := serve(h, http.MethodPost, "/api/v1/meals/2/share-links", `{}`, 1)
+		assertErrorCode(t, rec, http.StatusNotFound, "meal_not_found")
+	})
+
+	t.Run("revoke returns 404 when missing", func(t *testing.T) {
+		h := newHandler(fakeShareLinkService{revokeFn: func(_ context.Context, _, _ uint) error {
+			return service.ErrShareLinkNotFound
+		}})
+		rec := serve(h, http.MethodDelete, "/api/v1/share-links/5", "", 1)
+		assertErrorCode(t, rec, http.StatusNotFound, "share_link_not_found")
+	})
+
+	t.Run("open needs no user", func(t *testing.T) {
+		var gotToken string
+		h := newHandler(fakeShareLinkService{openFn: func(_ context.Context, token string) (service.SharedView, error) {
+			gotToken = token
+			return service.SharedView{Kind: sharelink.KindRecipe, Recipe: &service.SharedRecipe{Name: "Rice bowl"}}, nil
+		}})
+		rec := serve(h, http.MethodGet, "/api/v1/public/shared/abc123", "", 0)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+		}
+		if gotToken != "abc123" || rec.Header().Get("Cache-Control") != "no-store" {
+			t.Fatalf("expected token abc123 and no caching, got %q and %q", gotToken, rec.Header().Get("Cache-Control"))
+		}
+		if !strings.Contains(rec.Body.String(), `"display_name":"Rice bowl"`) {
+			t.Fatalf("expected the localized recipe, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("open returns 404 for unknown links", func(t *testing.T) {
+		rec := serve(newHandler(fakeShareLinkService{}), http.MethodGet, "/api/v1/public/shared/abc123", "", 0)
+		assertErrorCode(t, rec, http.StatusNotFound, "share_link_not_found")
+	})
+}
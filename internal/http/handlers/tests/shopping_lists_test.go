@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"goal-bite-api/internal/domain/shoppinglist"
+	"goal-bite-api/internal/http/handlers"
+	httpmiddleware "goal-bite-api/internal/http/middleware"
+	"goal-bite-api/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestShoppingListHandlers(t *testing.T) {
+	newRouter := func(h *handlers.Handler) http.Handler {
+		r := chi.NewRouter()
+		r.Post("/api/v1/shopping-lists", h.CreateShoppingList)
+		r.Get("/api/v1/shopping-lists", h.ListShoppingLists)
+		r.Get("/api/v1/shopping-lists/{id}", h.GetShoppingList)
+		r.Delete("/api/v1/shopping-lists/{id}", h.DeleteShoppingList)
+		r.Patch("/api/v1/shopping-lists/{id}/items/{item_id}", h.UpdateShoppingListItem)
+		return r
+	}
+	newHandler := func(lists fakeShoppingListService) *handlers.Handler {
+		return handlers.New(noopUserService{}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{}, lists)
+	}
+	serve := func(h *handlers.Handler, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 1))
+		rec := httptest.NewRecorder()
+		newRouter(h).ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("create from recipes returns 201", func(t *testing.T) {
+		var got service.CreateShoppingListInput
+		h := newHandler(fakeShoppingListService{createFn: func(_ context.Context, _ uint, in service.CreateShoppingListInput) (shoppinglist.ShoppingList, error) {
+			got = in
+			return shoppinglist.ShoppingList{ID: 3, Name: "Dinner"}, nil
+		}})
+		rec := serve(h, http.MethodPost, "/api/v1/shopping-lists", `{"name":"Dinner","recipes":[{"recipe_id":1,"servings":4}]}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("expected %d, got %d", http.StatusCreated, rec.Code)
+		}
+		if len(got.Recipes) != 1 || got.Recipes[0].RecipeID != 1 || got.Recipes[0].Servings != 4 {
+			t.Fatalf("expected the recipe to reach the service, got %+v", got)
+		}
+	})
+
+	t.Run("create needs exactly one source", func(t *testing.T) {
+		h := newHandler(fakeShoppingListService{})
+		for _, body := range []string{`{}`, `{"recipes":[{"recipe_id":1}],"from":"2026-02-16","to":"2026-02-22"}`, `{"from":"2026-02-16"}`} {
+			rec := serve(h, http.MethodPost, "/api/v1/shopping-lists", body)
+			assertErrorCode(t, rec, http.StatusBadRequest, "invalid_shopping_list_payload")
+		}
+	})
+
+	t.Run("create maps archived recipe to 409", func(t *testing.T) {
+		h := newHandler(fakeShoppingListService{createFn: func(_ context.Context, _ uint, _ service.CreateShoppingListInput) (shoppinglist.ShoppingList, error) {
+			return shoppinglist.ShoppingList{}, service.ErrRecipeArchived
+		}})
+		rec := serve(h, http.MethodPost, "/api/v1/shopping-lists", `{"recipes":[{"recipe_id":1}]}`)
+		assertErrorCode(t, rec, http.StatusConflict, "recipe_archived")
+	})
+
+	t.Run("get exports plain text", func(t *testing.T) {
+		h := newHandler(fakeShoppingListService{getFn: func(_ context.Context, _, _ uint) (shoppinglist.ShoppingList, error) {
+			return shoppinglist.ShoppingList{ID: 3, Name: "Dinner", Items: []shoppinglist.Item{{FoodID: 10, Name: "Spaghetti", WeightG: 300}}}, nil
+		}})
+		rec := serve(h, http.MethodGet, "/api/v1/shopping-lists/3?format=text", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+		}
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+			t.Fatalf("expected plain text, got %q", rec.Header().Get("Content-Type"))
+		}
+		if body := rec.Body.String(); body != "Dinner\n\n[ ] Spaghetti — 300 g\n" {
+			t.Fatalf("unexpected text export %q", body)
+		}
+	})
+
+	t.Run("get rejects unknown format", func(t *testing.T) {
+		rec := serve(newHandler(fakeShoppingListService{}), http.MethodGet, "/api/v1/shopping-lists/3?format=csv", "")
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_shopping_list_query")
+	})
+
+	t.Run("get returns 404 when missing", func(t *testing.T) {
+		h := newHandler(fakeShoppingListService{getFn: func(_ context.Context, _, _ uint) (shoppinglist.ShoppingList, error) {
+			return shoppinglist.ShoppingList{}, service.ErrShoppingListNotFound
+		}})
+		rec := serve(h, http.MethodGet, "/api/v1/shopping-lists/3", "")
+		assertErrorCode(t, rec, http.StatusNotFound, "shopping_list_not_found")
+	})
+
+	t.Run("check item requires checked", func(t *testing.T) {
+		rec := serve(newHandler(fakeShoppingListService{}), http.MethodPatch, "/api/v1/shopping-lists/3/items/4", `{}`)
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_shopping_list_item_payload")
+	})
+
+	t.Run("check item returns 200", func(t *testing.T) {
+		h := newHandler(fakeShoppingListService{checkedFn: func(_ context.Context, _, listID, itemID uint, checked bool) (shoppinglist.Item, error) {
+			if listID != 3 || itemID != 4 || !checked {
+				t.Fatalf("unexpected check of %d/%d to %v", listID, itemID, checked)
+			}
+			return shoppinglist.Item{ID: 4, Checked: true}, nil
+		}})
+		rec := serve(h, http.MethodPatch, "/api/v1/shopping-lists/3/items/4", `{"checked":true}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("delete returns 204", func(t *testing.T) {
+		rec := serve(newHandler(fakeShoppingListService{}), http.MethodDelete, "/api/v1/shopping-lists/3", "")
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected %d, got %d", http.StatusNoContent, rec.Code)
+		}
+	})
+}
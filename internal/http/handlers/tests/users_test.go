@@ -203,4 +203,21 @@ func TestUpdateMeHandler(t *testing.T) {
 			t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
 		}
 	})
+
+	t.Run("returns 400 for invalid languages", func(t *testing.T) {
+		h := handlers.New(fakeUserService{
+			updateFn: func(_ context.Context, _ uint, in service.UpdateUserInput) (user.User, error) {
+				if in.Languages == nil || len(*in.Languages) != 1 || (*in.Languages)[0] != "klingon" {
+					t.Fatalf("unexpected languages %v", in.Languages)
+				}
+				return user.User{}, service.ErrInvalidUserProfile
+			},
+		}, fakeAuthService{}, fakeFoodService{}, fakeRecipeService{}, fakeMealService{}, fakeBodyWeightLogService{})
+		router := newRouter(h)
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/users/me", strings.NewReader(`{"languages":["klingon"]}`))
+		req = req.WithContext(httpmiddleware.WithUserID(req.Context(), 1))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assertErrorCode(t, rec, http.StatusBadRequest, "invalid_user_payload")
+	})
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"goal-bite-api/internal/auth"
 
@@ -13,7 +14,16 @@ import (
 
 type contextKey string
 
-const userIDContextKey contextKey = "auth_user_id"
+const (
+	userIDContextKey    contextKey = "auth_user_id"
+	sessionIDContextKey contextKey = "auth_session_id"
+)
+
+// SessionChecker reports whether the auth session an access token is bound to
+// is still active.
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID uint, now time.Time) (bool, error)
+}
 
 func WithUserID(ctx context.Context, userID uint) context.Context {
 	return context.WithValue(ctx, userIDContextKey, userID)
@@ -25,7 +35,17 @@ func UserIDFromContext(ctx context.Context) (uint, bool) {
 	return id, ok && id > 0
 }
 
-func RequireAuth(jwtManager *auth.JWTManager) func(next http.Handler) http.Handler {
+func WithSessionID(ctx context.Context, sessionID uint) context.Context {
+	return context.WithValue(ctx, sessionIDContextKey, sessionID)
+}
+
+func SessionIDFromContext(ctx context.Context) (uint, bool) {
+	v := ctx.Value(sessionIDContextKey)
+	id, ok := v.(uint)
+	return id, ok && id > 0
+}
+
+func RequireAuth(jwtManager *auth.JWTManager, sessions SessionChecker) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			raw := strings.TrimSpace(r.Header.Get("Authorization"))
@@ -34,13 +54,23 @@ func RequireAuth(jwtManager *auth.JWTManager) func(next http.Handler) http.Handl
 				return
 			}
 			token := strings.TrimSpace(strings.TrimPrefix(raw, "Bearer "))
-			userID, err := jwtManager.Parse(token)
+			claims, err := jwtManager.Parse(token)
 			if err != nil {
 				writeUnauthorized(w)
 				return
 			}
+			active, err := sessions.IsSessionActive(r.Context(), claims.SessionID, time.Now().UTC())
+			if err != nil {
+				writeServiceUnavailable(w)
+				return
+			}
+			if !active {
+				writeUnauthorized(w)
+				return
+			}
 
-			ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+			ctx := WithUserID(r.Context(), claims.UserID)
+			ctx = WithSessionID(ctx, claims.SessionID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -57,3 +87,15 @@ func writeUnauthorized(w http.ResponseWriter) {
 		},
 	})
 }
+
+func writeServiceUnavailable(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]string{
+			"code":       "service_unavailable",
+			"message":    "service unavailable",
+			"request_id": w.Header().Get(chimw.RequestIDHeader),
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package httpmiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/auth"
+)
+
+type sessionCheckerFunc func(ctx context.Context, sessionID uint, now time.Time) (bool, error)
+
+func (f sessionCheckerFunc) IsSessionActive(ctx context.Context, sessionID uint, now time.Time) (bool, error) {
+	return f(ctx, sessionID, now)
+}
+
+func TestRequireAuthChecksSession(t *testing.T) {
+	jwtManager := auth.NewJWTManager("secret")
+	token, err := jwtManager.Generate(3, 11)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		active bool
+		err    error
+		want   int
+	}{
+		{name: "active session", active: true, want: http.StatusNoContent},
+		{name: "revoked session", active: false, want: http.StatusUnauthorized},
+		{name: "checker failure", err: errors.New("db down"), want: http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := sessionCheckerFunc(func(_ context.Context, sessionID uint, _ time.Time) (bool, error) {
+				if sessionID != 11 {
+					t.Fatalf("expected session id 11, got %d", sessionID)
+				}
+				return tc.active, tc.err
+			})
+			handler := RequireAuth(jwtManager, checker)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, _ := UserIDFromContext(r.Context())
+				sessionID, _ := SessionIDFromContext(r.Context())
+				if userID != 3 || sessionID != 11 {
+					t.Fatalf("unexpected context ids: user=%d session=%d", userID, sessionID)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, rec.Code)
+			}
+		})
+	}
+}
@@ -14,7 +14,9 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
-func NewRouter(handler *handlers.Handler, logger *slog.Logger, jwtManager *auth.JWTManager) http.Handler {
+// NewRouter builds the API routes. media, when not nil, serves uploaded
+// images below /media for stores that cannot serve them themselves.
+func NewRouter(handler *handlers.Handler, logger *slog.Logger, jwtManager *auth.JWTManager, sessions httpmiddleware.SessionChecker, media http.Handler) http.Handler {
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
@@ -39,11 +41,19 @@ func NewRouter(handler *handlers.Handler, logger *slog.Logger, jwtManager *auth.
 	router.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
+	if media != nil {
+		router.Handle("/media/*", http.StripPrefix("/media", media))
+	}
 
 	router.Route("/api/v1", func(r chi.Router) {
 		registerLimiter := httpmiddleware.NewIPRateLimiter(5, time.Minute)
 		loginLimiter := httpmiddleware.NewIPRateLimiter(10, time.Minute)
 		refreshLimiter := httpmiddleware.NewIPRateLimiter(20, time.Minute)
+		magicLinkLimiter := httpmiddleware.NewIPRateLimiter(5, time.Minute)
+		magicLinkConsumeLimiter := httpmiddleware.NewIPRateLimiter(10, time.Minute)
+		passkeyLoginLimiter := httpmiddleware.NewIPRateLimiter(20, time.Minute)
+		oidcLimiter := httpmiddleware.NewIPRateLimiter(20, time.Minute)
+		sharedLimiter := httpmiddleware.NewIPRateLimiter(60, time.Minute)
 
 		r.Get("/health/live", handler.HealthLive)
 		r.Get("/health/ready", handler.HealthReady)
@@ -52,30 +62,67 @@ func NewRouter(handler *handlers.Handler, logger *slog.Logger, jwtManager *auth.
 		r.With(loginLimiter.Middleware).Post("/auth/login", handler.Login)
 		r.With(refreshLimiter.Middleware).Post("/auth/refresh", handler.Refresh)
 		r.Post("/auth/logout", handler.Logout)
+		r.With(magicLinkLimiter.Middleware).Post("/auth/magic-link", handler.RequestMagicLink)
+		r.With(magicLinkConsumeLimiter.Middleware).Post("/auth/magic-link/consume", handler.ConsumeMagicLink)
+		r.With(passkeyLoginLimiter.Middleware).Post("/auth/passkeys/login/begin", handler.BeginPasskeyLogin)
+		r.With(passkeyLoginLimiter.Middleware).Post("/auth/passkeys/login/finish", handler.FinishPasskeyLogin)
+		r.Get("/auth/oidc/providers", handler.ListOIDCProviders)
+		r.With(oidcLimiter.Middleware).Post("/auth/oidc/{provider}/begin", handler.BeginOIDCLogin)
+		r.With(oidcLimiter.Middleware).Post("/auth/oidc/{provider}/finish", handler.FinishOIDCLogin)
+		r.With(sharedLimiter.Middleware).Get("/public/shared/{token}", handler.GetSharedContent)
 
 		r.Group(func(pr chi.Router) {
-			pr.Use(httpmiddleware.RequireAuth(jwtManager))
+			pr.Use(httpmiddleware.RequireAuth(jwtManager, sessions))
 			pr.Get("/auth/me", handler.Me)
+			pr.Post("/auth/passkeys/register/begin", handler.BeginPasskeyRegistration)
+			pr.Post("/auth/passkeys/register/finish", handler.FinishPasskeyRegistration)
+			pr.Get("/auth/passkeys", handler.ListPasskeys)
+			pr.Delete("/auth/passkeys/{id}", handler.DeletePasskey)
 			pr.Get("/health", handler.Health)
 			pr.Patch("/users/me", handler.UpdateMe)
 			pr.Get("/users/{id}", handler.GetUserByID)
 			pr.Post("/foods", handler.CreateFood)
 			pr.Get("/foods", handler.ListFoods)
 			pr.Get("/foods/by-barcode/{barcode}", handler.GetFoodByBarcode)
+			pr.Get("/foods/nutrition-report", handler.GetFoodNutritionReport)
+			pr.Get("/foods/search", handler.SearchFoods)
+			pr.Get("/food-categories", handler.ListFoodCategories)
+			pr.Get("/cooking-methods", handler.ListCookingMethods)
 			pr.Get("/foods/{id}", handler.GetFoodByID)
 			pr.Patch("/foods/{id}", handler.UpdateFood)
 			pr.Delete("/foods/{id}", handler.DeleteFood)
+			pr.Post("/foods/{id}/restore", handler.RestoreFood)
+			pr.Post("/foods/{id}/verify", handler.VerifyFood)
+			pr.Get("/foods/{id}/versions", handler.ListFoodVersions)
+			pr.Post("/foods/{id}/versions/{version}/rollback", handler.RollbackFood)
+			pr.Get("/foods/{id}/duplicates", handler.FindFoodDuplicates)
+			pr.Post("/foods/{id}/merge", handler.MergeFoods)
+			pr.Put("/foods/{id}/image", handler.UploadFoodImage)
+			pr.Delete("/foods/{id}/image", handler.DeleteFoodImage)
 			pr.Post("/recipes", handler.CreateRecipe)
 			pr.Get("/recipes", handler.ListRecipes)
+			pr.Post("/recipes/import", handler.ImportRecipe)
 			pr.Get("/recipes/{id}", handler.GetRecipeByID)
+			pr.Get("/recipes/{id}/scaled", handler.ScaleRecipe)
 			pr.Patch("/recipes/{id}", handler.UpdateRecipe)
 			pr.Delete("/recipes/{id}", handler.DeleteRecipe)
+			pr.Post("/recipes/{id}/restore", handler.RestoreRecipe)
+			pr.Post("/recipes/{id}/recalculate", handler.RecalculateRecipe)
+			pr.Post("/recipes/{id}/fork", handler.ForkRecipe)
+			pr.Get("/recipes/{id}/forks", handler.ListRecipeForks)
+			pr.Get("/recipes/{id}/changes", handler.GetRecipeChanges)
+			pr.Post("/recipes/{id}/share-links", handler.ShareRecipe)
+			pr.Put("/recipes/{id}/image", handler.UploadRecipeImage)
+			pr.Delete("/recipes/{id}/image", handler.DeleteRecipeImage)
 			pr.Post("/meals", handler.CreateMeal)
 			pr.Get("/meals", handler.ListMeals)
 			pr.Get("/meals/{id}", handler.GetMealByID)
 			pr.Patch("/meals/{id}", handler.UpdateMeal)
 			pr.Delete("/meals/{id}", handler.DeleteMeal)
 			pr.Post("/meals/{id}/items", handler.AddMealItem)
+			pr.Post("/meals/{id}/share-links", handler.ShareMeal)
+			pr.Put("/meals/{id}/image", handler.UploadMealImage)
+			pr.Delete("/meals/{id}/image", handler.DeleteMealImage)
 			pr.Patch("/meals/{meal_id}/items/{item_id}", handler.UpdateMealItem)
 			pr.Delete("/meals/{meal_id}/items/{item_id}", handler.DeleteMealItem)
 			pr.Get("/daily-totals", handler.GetDailyTotals)
@@ -86,6 +133,13 @@ func NewRouter(handler *handlers.Handler, logger *slog.Logger, jwtManager *auth.
 			pr.Post("/body-weight-logs", handler.CreateBodyWeightLog)
 			pr.Get("/body-weight-logs", handler.ListBodyWeightLogs)
 			pr.Get("/body-weight-logs/latest", handler.GetLatestBodyWeightLog)
+			pr.Post("/shopping-lists", handler.CreateShoppingList)
+			pr.Get("/shopping-lists", handler.ListShoppingLists)
+			pr.Get("/shopping-lists/{id}", handler.GetShoppingList)
+			pr.Delete("/shopping-lists/{id}", handler.DeleteShoppingList)
+			pr.Patch("/shopping-lists/{id}/items/{item_id}", handler.UpdateShoppingListItem)
+			pr.Get("/share-links", handler.ListShareLinks)
+			pr.Delete("/share-links/{id}", handler.RevokeShareLink)
 		})
 	})
 
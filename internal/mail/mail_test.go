@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFileMailerWritesMessage(t *testing.T) {
+	dir := t.TempDir()
+	m := NewFileMailer(dir)
+	if err := m.Send(context.Background(), Message{To: "a@example.com", Subject: "Hi", Body: "hello"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(entries))
+	}
+	raw, err := os.ReadFile(dir + "/" + entries[0].Name())
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	content := string(raw)
+	if !strings.Contains(content, "To: a@example.com\r\n") || !strings.HasSuffix(content, "\r\n\r\nhello") {
+		t.Fatalf("unexpected message content: %q", content)
+	}
+}
@@ -0,0 +1,172 @@
+// Package media validates uploaded images and prepares the renditions that
+// are stored for them.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	// Registers the GIF decoder with image.Decode.
+	_ "image/gif"
+)
+
+var (
+	// ErrUnsupportedType is returned for content that does not sniff as a
+	// JPEG, PNG or GIF image, whatever the client claimed it was.
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooLarge        = errors.New("image too large")
+	ErrInvalid         = errors.New("invalid image")
+)
+
+const (
+	// DefaultMaxBytes bounds the size of an upload.
+	DefaultMaxBytes = 5 << 20
+	// MaxPixels bounds decoded dimensions so a small, highly compressed file
+	// cannot expand into gigabytes of memory.
+	MaxPixels = 40_000_000
+	// DisplaySize and ThumbnailSize are the longest edges of the stored
+	// renditions. Smaller images are never upscaled.
+	DisplaySize   = 1600
+	ThumbnailSize = 320
+
+	jpegQuality = 85
+)
+
+var sniffed = map[string]string{
+	"image/jpeg": "image/jpeg",
+	"image/png":  "image/png",
+	// GIFs keep only their first frame and are stored as PNG.
+	"image/gif": "image/png",
+}
+
+// Rendition is one encoded image ready to be stored.
+type Rendition struct {
+	ContentType string
+	Ext         string
+	Data        []byte
+	Width       int
+	Height      int
+}
+
+// Processed holds the display image and its thumbnail.
+type Processed struct {
+	Image     Rendition
+	Thumbnail Rendition
+}
+
+// Process checks an upload and re-encodes it. The display image keeps the
+// upload's format and is shrunk to DisplaySize; the thumbnail is always a
+// JPEG. Re-encoding also drops embedded metadata such as EXIF locations.
+func Process(data []byte, maxBytes int) (Processed, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if len(data) > maxBytes {
+		return Processed{}, ErrTooLarge
+	}
+	contentType, ok := sniffed[http.DetectContentType(data)]
+	if !ok {
+		return Processed{}, ErrUnsupportedType
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Processed{}, ErrInvalid
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return Processed{}, ErrInvalid
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return Processed{}, ErrTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Processed{}, ErrInvalid
+	}
+
+	shrunk := Fit(src, DisplaySize)
+	display, err := encode(shrunk, contentType)
+	if err != nil {
+		return Processed{}, err
+	}
+	thumbnail, err := encode(flatten(Fit(shrunk, ThumbnailSize)), "image/jpeg")
+	if err != nil {
+		return Processed{}, err
+	}
+	return Processed{Image: display, Thumbnail: thumbnail}, nil
+}
+
+// Fit scales img down so its longest edge is at most size, keeping the
+// aspect ratio. Each target pixel averages the source pixels it covers,
+// which avoids the aliasing of nearest-neighbour sampling.
+func Fit(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+	tw, th := size, size
+	if w >= h {
+		th = max(1, h*size/w)
+	} else {
+		tw = max(1, w*size/h)
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					bl += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// flatten draws img onto white so transparent areas do not turn black when
+// encoded as JPEG.
+func flatten(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+func encode(img image.Image, contentType string) (Rendition, error) {
+	var buf bytes.Buffer
+	ext := ".png"
+	var err error
+	if contentType == "image/jpeg" {
+		ext = ".jpg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return Rendition{}, err
+	}
+	b := img.Bounds()
+	return Rendition{ContentType: contentType, Ext: ext, Data: buf.Bytes(), Width: b.Dx(), Height: b.Dy()}, nil
+}
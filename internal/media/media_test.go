@@ -0,0 +1,106 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func TestProcess(t *testing.T) {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, testImage(2000, 1000)); err != nil {
+		t.Fatal(err)
+	}
+	var jpegData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, testImage(300, 600), nil); err != nil {
+		t.Fatal(err)
+	}
+	var gifData bytes.Buffer
+	if err := gif.Encode(&gifData, testImage(40, 40), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("shrinks png and keeps its format", func(t *testing.T) {
+		out, err := Process(pngData.Bytes(), 0)
+		if err != nil {
+			t.Fatalf("process: %v", err)
+		}
+		if out.Image.ContentType != "image/png" || out.Image.Ext != ".png" || out.Image.Width != 1600 || out.Image.Height != 800 {
+			t.Fatalf("unexpected image: %+v", out.Image)
+		}
+		if out.Thumbnail.ContentType != "image/jpeg" || out.Thumbnail.Width != 320 || out.Thumbnail.Height != 160 {
+			t.Fatalf("unexpected thumbnail: %+v", out.Thumbnail)
+		}
+		if _, err := jpeg.Decode(bytes.NewReader(out.Thumbnail.Data)); err != nil {
+			t.Fatalf("thumbnail is not a jpeg: %v", err)
+		}
+	})
+
+	t.Run("does not upscale small images", func(t *testing.T) {
+		out, err := Process(jpegData.Bytes(), 0)
+		if err != nil {
+			t.Fatalf("process: %v", err)
+		}
+		if out.Image.Ext != ".jpg" || out.Image.Width != 300 || out.Image.Height != 600 {
+			t.Fatalf("unexpected image: %+v", out.Image)
+		}
+		if out.Thumbnail.Width != 160 || out.Thumbnail.Height != 320 {
+			t.Fatalf("unexpected thumbnail: %+v", out.Thumbnail)
+		}
+	})
+
+	t.Run("stores gifs as png", func(t *testing.T) {
+		out, err := Process(gifData.Bytes(), 0)
+		if err != nil {
+			t.Fatalf("process: %v", err)
+		}
+		if out.Image.ContentType != "image/png" || out.Image.Width != 40 {
+			t.Fatalf("unexpected image: %+v", out.Image)
+		}
+	})
+
+	t.Run("rejects content by sniffing, not by claims", func(t *testing.T) {
+		for _, data := range [][]byte{[]byte("<html><body>not an image</body></html>"), []byte("RIFF\x00\x00\x00\x00WEBPVP8 ")} {
+			if _, err := Process(data, 0); !errors.Is(err, ErrUnsupportedType) {
+				t.Fatalf("expected ErrUnsupportedType, got %v", err)
+			}
+		}
+	})
+
+	t.Run("rejects truncated images", func(t *testing.T) {
+		if _, err := Process(pngData.Bytes()[:64], 0); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("rejects oversized uploads", func(t *testing.T) {
+		if _, err := Process(pngData.Bytes(), 100); !errors.Is(err, ErrTooLarge) {
+			t.Fatalf("expected ErrTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("rejects oversized dimensions before decoding", func(t *testing.T) {
+		var huge bytes.Buffer
+		if err := png.Encode(&huge, image.NewGray(image.Rect(0, 0, 10000, 5000))); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Process(huge.Bytes(), 0); !errors.Is(err, ErrTooLarge) {
+			t.Fatalf("expected ErrTooLarge, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,290 @@
+// Package oidc is a minimal OpenID Connect relying-party client for the
+// authorization code flow with PKCE (RFC 7636).
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrProviderUnavailable wraps discovery, JWKS and transport failures.
+	ErrProviderUnavailable = errors.New("oidc provider unavailable")
+	// ErrExchangeRejected means the token endpoint refused the authorization code.
+	ErrExchangeRejected = errors.New("oidc code exchange rejected")
+	ErrInvalidIDToken   = errors.New("invalid id token")
+)
+
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+type ProviderConfig struct {
+	// Name identifies the provider in routes and linked identities.
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the client page that receives `code` and `state`.
+	RedirectURL string
+	Scopes      []string
+}
+
+// Claims are the verified ID token claims used for account linking.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type Provider struct {
+	cfg    ProviderConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]any
+	keysFetchedAt time.Time
+}
+
+func NewProvider(cfg ProviderConfig, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = DefaultScopes
+	}
+	cfg.Issuer = strings.TrimRight(cfg.Issuer, "/")
+	return &Provider{cfg: cfg, client: client}
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthCodeURL builds the authorization endpoint URL the user is sent to.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	endpoint, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: bad authorization endpoint", ErrProviderUnavailable)
+	}
+	q := endpoint.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	endpoint.RawQuery = q.Encode()
+	return endpoint.String(), nil
+}
+
+// Exchange redeems an authorization code and returns the raw ID token.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+		"client_id":     {p.cfg.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	switch {
+	case resp.StatusCode >= 500:
+		return "", fmt.Errorf("%w: token endpoint returned %d", ErrProviderUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("%w: token endpoint returned %d", ErrExchangeRejected, resp.StatusCode)
+	}
+	var out struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil || out.IDToken == "" {
+		return "", fmt.Errorf("%w: token response has no id_token", ErrExchangeRejected)
+	}
+	return out.IDToken, nil
+}
+
+// VerifyIDToken checks signature, issuer, audience, expiry and nonce.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (Claims, error) {
+	doc, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+	var keyErr error
+	parsed, err := jwt.Parse(rawIDToken, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := p.key(ctx, doc.JWKSURI, kid)
+		if err != nil {
+			keyErr = err
+		}
+		return key, err
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if keyErr != nil && errors.Is(keyErr, ErrProviderUnavailable) {
+		return Claims{}, keyErr
+	}
+	if err != nil || !parsed.Valid {
+		return Claims{}, ErrInvalidIDToken
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return Claims{}, ErrInvalidIDToken
+	}
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return Claims{}, ErrInvalidIDToken
+	}
+	sub, _ := claims.GetSubject()
+	if sub == "" {
+		return Claims{}, ErrInvalidIDToken
+	}
+	out := Claims{Subject: sub}
+	out.Email, _ = claims["email"].(string)
+	out.Name, _ = claims["name"].(string)
+	// Some providers send email_verified as the string "true".
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		out.EmailVerified = v
+	case string:
+		out.EmailVerified = v == "true"
+	}
+	return out, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	cached := p.discovery
+	p.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var doc discoveryDocument
+	if err := p.getJSON(ctx, p.cfg.Issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, err
+	}
+	if strings.TrimRight(doc.Issuer, "/") != p.cfg.Issuer || doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document does not match issuer", ErrProviderUnavailable)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.discovery = &doc
+	return &doc, nil
+}
+
+// key returns the JWKS key for kid, refetching the set at most once a minute
+// so rotated keys are picked up.
+func (p *Provider) key(ctx context.Context, jwksURI, kid string) (any, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	stale := time.Since(p.keysFetchedAt) > time.Minute
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if !stale {
+		return nil, ErrInvalidIDToken
+	}
+
+	var set jwkSet
+	if err := p.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, err
+	}
+	keys := set.publicKeys()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrInvalidIDToken
+}
+
+func (p *Provider) getJSON(ctx context.Context, target string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", ErrProviderUnavailable, target, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	return nil
+}
+
+// NewPKCEVerifier returns a high-entropy code_verifier.
+func NewPKCEVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// PKCEChallenge derives the S256 code_challenge for a verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
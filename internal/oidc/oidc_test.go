@@ -0,0 +1,132 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/oidc"
+	"goal-bite-api/internal/oidc/oidctest"
+)
+
+func newTestProvider(t *testing.T) (*oidctest.Provider, *oidc.Provider) {
+	t.Helper()
+	idp, err := oidctest.New("goal-bite", "client-secret")
+	if err != nil {
+		t.Fatalf("new fake provider: %v", err)
+	}
+	t.Cleanup(idp.Close)
+	return idp, oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "acme",
+		Issuer:       idp.Issuer(),
+		ClientID:     "goal-bite",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:3000/auth/oidc/acme/callback",
+	}, nil)
+}
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	ctx := context.Background()
+	idp, provider := newTestProvider(t)
+	idp.SetUser(oidctest.User{Subject: "u-1", Email: "a@example.com", EmailVerified: true, Name: "A"})
+
+	verifier, err := oidc.NewPKCEVerifier()
+	if err != nil {
+		t.Fatalf("verifier: %v", err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.PKCEChallenge(verifier))
+	if err != nil {
+		t.Fatalf("auth url: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	if parsed.Query().Get("code_challenge_method") != "S256" || parsed.Query().Get("scope") != "openid email profile" {
+		t.Fatalf("unexpected auth url: %s", authURL)
+	}
+
+	code, state, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if state != "state-1" {
+		t.Fatalf("expected state to round-trip, got %q", state)
+	}
+	idToken, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, idToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.Subject != "u-1" || claims.Email != "a@example.com" || !claims.EmailVerified || claims.Name != "A" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := provider.Exchange(ctx, code, verifier); !errors.Is(err, oidc.ErrExchangeRejected) {
+		t.Fatalf("expected reused code to be rejected, got %v", err)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	ctx := context.Background()
+	idp, provider := newTestProvider(t)
+	verifier, _ := oidc.NewPKCEVerifier()
+	authURL, _ := provider.AuthCodeURL(ctx, "s", "n", oidc.PKCEChallenge(verifier))
+	code, _, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	other, _ := oidc.NewPKCEVerifier()
+	if _, err := provider.Exchange(ctx, code, other); !errors.Is(err, oidc.ErrExchangeRejected) {
+		t.Fatalf("expected ErrExchangeRejected, got %v", err)
+	}
+}
+
+func TestVerifyIDTokenRejections(t *testing.T) {
+	ctx := context.Background()
+	idp, provider := newTestProvider(t)
+	user := oidctest.User{Subject: "u-1", Email: "a@example.com", EmailVerified: true}
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		token, _ := idp.SignIDToken(user, "nonce-1", time.Hour)
+		if _, err := provider.VerifyIDToken(ctx, token, "nonce-2"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+			t.Fatalf("expected ErrInvalidIDToken, got %v", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, _ := idp.SignIDToken(user, "nonce-1", -time.Hour)
+		if _, err := provider.VerifyIDToken(ctx, token, "nonce-1"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+			t.Fatalf("expected ErrInvalidIDToken, got %v", err)
+		}
+	})
+
+	t.Run("other audience", func(t *testing.T) {
+		other := oidc.NewProvider(oidc.ProviderConfig{Name: "acme", Issuer: idp.Issuer(), ClientID: "someone-else"}, nil)
+		token, _ := idp.SignIDToken(user, "nonce-1", time.Hour)
+		if _, err := other.VerifyIDToken(ctx, token, "nonce-1"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+			t.Fatalf("expected ErrInvalidIDToken, got %v", err)
+		}
+	})
+
+	t.Run("foreign signer", func(t *testing.T) {
+		foreign, err := oidctest.New("goal-bite", "client-secret")
+		if err != nil {
+			t.Fatalf("new fake provider: %v", err)
+		}
+		defer foreign.Close()
+		token, _ := foreign.SignIDToken(user, "nonce-1", time.Hour)
+		if _, err := provider.VerifyIDToken(ctx, token, "nonce-1"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+			t.Fatalf("expected ErrInvalidIDToken, got %v", err)
+		}
+	})
+}
+
+func TestProviderUnavailable(t *testing.T) {
+	provider := oidc.NewProvider(oidc.ProviderConfig{Name: "down", Issuer: "http://127.0.0.1:1", ClientID: "x"}, nil)
+	if _, err := provider.AuthCodeURL(context.Background(), "s", "n", "c"); !errors.Is(err, oidc.ErrProviderUnavailable) {
+		t.Fatalf("expected ErrProviderUnavailable, got %v", err)
+	}
+}
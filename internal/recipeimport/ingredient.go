@@ -0,0 +1,136 @@
+package recipeimport
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Ingredient is one parsed ingredient line. Quantity is nil when the line
+// has none, such as "salt to taste", and Unit is empty for counted items
+// such as "2 eggs". WeightG is set when the unit converts to grams.
+type Ingredient struct {
+	Text     string
+	Quantity *float64
+	Unit     string
+	Name     string
+	WeightG  *float64
+	// Approximate is set when WeightG was converted from a volume, which
+	// assumes the density of water.
+	Approximate bool
+}
+
+type unit struct {
+	name   string
+	grams  float64
+	volume bool
+}
+
+// units maps the spellings of supported units, without a trailing period,
+// to their canonical name and weight. Spellings are lowercase apart from
+// single letters. Volumes use US kitchen measures.
+var units = unitAliases()
+
+func unitAliases() map[string]unit {
+	units := map[string]unit{}
+	add := func(u unit, aliases ...string) {
+		for _, alias := range aliases {
+			units[alias] = u
+		}
+	}
+	add(unit{name: "g", grams: 1}, "g", "gr", "gram", "grams", "gramm", "gramme", "grammes")
+	add(unit{name: "kg", grams: 1000}, "kg", "kgs", "kilo", "kilos", "kilogram", "kilograms")
+	add(unit{name: "mg", grams: 0.001}, "mg", "milligram", "milligrams")
+	add(unit{name: "oz", grams: 28.349523125}, "oz", "ounce", "ounces")
+	add(unit{name: "lb", grams: 453.59237}, "lb", "lbs", "pound", "pounds")
+	add(unit{name: "ml", grams: 1, volume: true}, "ml", "milliliter", "milliliters", "millilitre", "millilitres")
+	add(unit{name: "cl", grams: 10, volume: true}, "cl", "centiliter", "centiliters", "centilitre", "centilitres")
+	add(unit{name: "dl", grams: 100, volume: true}, "dl", "deciliter", "deciliters", "decilitre", "decilitres")
+	add(unit{name: "l", grams: 1000, volume: true}, "l", "L", "liter", "liters", "litre", "litres")
+	add(unit{name: "cup", grams: 240, volume: true}, "cup", "cups", "c")
+	add(unit{name: "tbsp", grams: 15, volume: true}, "tbsp", "tbsps", "tbs", "tbl", "tablespoon", "tablespoons", "T")
+	add(unit{name: "tsp", grams: 5, volume: true}, "tsp", "tsps", "teaspoon", "teaspoons", "t")
+	return units
+}
+
+var (
+	// quantityPattern matches a leading amount: a fraction, or a whole,
+	// decimal or mixed number, optionally followed by the upper end of a
+	// range. A unit may follow without a space, as in "200g".
+	quantityPattern = regexp.MustCompile(`^(\d+/\d+|\d+(?:[.,]\d+)?(?:\s+\d+/\d+)?)(?:\s*(?:-|–|to)\s*(?:\d+/\d+|\d+(?:[.,]\d+)?(?:\s+\d+/\d+)?))?\s*`)
+	unitPattern     = regexp.MustCompile(`^([A-Za-z]+)\.?(?:\s+|$)`)
+	parenthetical   = regexp.MustCompile(`\([^)]*\)`)
+)
+
+var vulgarFractions = strings.NewReplacer(
+	"½", " 1/2", "⅓", " 1/3", "⅔", " 2/3", "¼", " 1/4", "¾", " 3/4",
+	"⅕", " 1/5", "⅛", " 1/8", "⅜", " 3/8", "⅝", " 5/8", "⅞", " 7/8",
+	"⁄", "/",
+)
+
+// ParseIngredient splits an ingredient line such as "1 ½ cups of rice,
+// rinsed" into quantity, unit and name. Ranges such as "2-3 cloves" use
+// their lower bound. The name drops parenthetical notes and anything after
+// the first comma, which usually describes preparation.
+func ParseIngredient(line string) Ingredient {
+	out := Ingredient{Text: strings.Join(strings.Fields(line), " ")}
+	rest := strings.TrimSpace(vulgarFractions.Replace(out.Text))
+
+	if m := quantityPattern.FindStringSubmatch(rest); m != nil {
+		if q, ok := parseAmount(m[1]); ok {
+			out.Quantity = &q
+			rest = rest[len(m[0]):]
+			if u := unitPattern.FindStringSubmatch(rest); u != nil {
+				if known, ok := lookupUnit(u[1]); ok {
+					out.Unit = known.name
+					weight := q * known.grams
+					out.WeightG = &weight
+					out.Approximate = known.volume
+					rest = strings.TrimPrefix(rest[len(u[0]):], "of ")
+				}
+			}
+		}
+	}
+
+	name := parenthetical.ReplaceAllString(rest, "")
+	if i := strings.Index(name, ","); i >= 0 {
+		name = name[:i]
+	}
+	out.Name = strings.Join(strings.Fields(name), " ")
+	return out
+}
+
+// lookupUnit finds a unit by spelling. Single letters are case-sensitive,
+// since "T" and "t" stand for tablespoons and teaspoons.
+func lookupUnit(raw string) (unit, bool) {
+	if u, ok := units[raw]; ok {
+		return u, true
+	}
+	if len(raw) == 1 {
+		return unit{}, false
+	}
+	u, ok := units[strings.ToLower(raw)]
+	return u, ok
+}
+
+// parseAmount reads "2", "1.5", "1,5", "3/4" or "1 1/2".
+func parseAmount(raw string) (float64, bool) {
+	total := 0.0
+	for _, part := range strings.Fields(raw) {
+		if num, den, ok := strings.Cut(part, "/"); ok {
+			n, err1 := strconv.ParseFloat(num, 64)
+			d, err2 := strconv.ParseFloat(den, 64)
+			if err1 != nil || err2 != nil || d == 0 {
+				return 0, false
+			}
+			total += n / d
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.Replace(part, ",", ".", 1), 64)
+		if err != nil {
+			return 0, false
+		}
+		total += v
+	}
+	return total, total > 0
+}
@@ -0,0 +1,178 @@
+// Package recipeimport reads recipes from web pages, schema.org Recipe
+// JSON-LD and pasted ingredient lists, turning each ingredient line into a
+// quantity, unit and name.
+package recipeimport
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNoRecipe is returned when the content holds no recipe or no
+	// ingredients.
+	ErrNoRecipe           = errors.New("no recipe found")
+	ErrTooManyIngredients = errors.New("too many ingredients")
+
+	errNoSchemaRecipe = errors.New("no schema.org recipe")
+)
+
+var (
+	jsonLDScript = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+	firstNumber  = regexp.MustCompile(`\d+`)
+	// listMarker matches bullets and numbering in front of pasted lines.
+	listMarker = regexp.MustCompile(`^(?:[-*•·▢□]+|\d+[.)])\s+`)
+)
+
+// MaxIngredients caps the ingredients of one imported recipe.
+const MaxIngredients = 100
+
+// Recipe is an imported recipe. Name and Servings are zero when the source
+// does not state them.
+type Recipe struct {
+	Name        string
+	Servings    int
+	Ingredients []Ingredient
+}
+
+// Parse reads a recipe from content: an HTML page carrying schema.org Recipe
+// JSON-LD, the JSON-LD itself, or a plain ingredient list with one
+// ingredient per line.
+func Parse(content string) (Recipe, error) {
+	content = strings.TrimSpace(content)
+	var out Recipe
+	switch {
+	case strings.HasPrefix(content, "<"):
+		found := false
+		for _, match := range jsonLDScript.FindAllStringSubmatch(content, -1) {
+			if r, err := parseJSONLD(match[1]); err == nil {
+				out, found = r, true
+				break
+			}
+		}
+		if !found {
+			return Recipe{}, ErrNoRecipe
+		}
+	case strings.HasPrefix(content, "{") || strings.HasPrefix(content, "["):
+		r, err := parseJSONLD(content)
+		if err != nil {
+			return Recipe{}, ErrNoRecipe
+		}
+		out = r
+	default:
+		out = Recipe{Ingredients: parseLines(strings.Split(content, "\n"))}
+	}
+
+	if len(out.Ingredients) == 0 {
+		return Recipe{}, ErrNoRecipe
+	}
+	if len(out.Ingredients) > MaxIngredients {
+		return Recipe{}, ErrTooManyIngredients
+	}
+	return out, nil
+}
+
+func parseLines(lines []string) []Ingredient {
+	var out []Ingredient
+	for _, line := range lines {
+		line = listMarker.ReplaceAllString(strings.TrimSpace(html.UnescapeString(line)), "")
+		if line == "" {
+			continue
+		}
+		out = append(out, ParseIngredient(line))
+	}
+	return out
+}
+
+// parseJSONLD finds the first schema.org Recipe in a JSON-LD document,
+// looking through arrays and @graph lists.
+func parseJSONLD(raw string) (Recipe, error) {
+	var doc any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &doc); err != nil {
+		return Recipe{}, err
+	}
+	node, ok := findRecipe(doc)
+	if !ok {
+		return Recipe{}, errNoSchemaRecipe
+	}
+
+	name, _ := node["name"].(string)
+	out := Recipe{
+		Name:     strings.TrimSpace(html.UnescapeString(name)),
+		Servings: parseYield(node["recipeYield"]),
+	}
+	ingredients := node["recipeIngredient"]
+	if ingredients == nil {
+		// Older markup used the plain ingredients property.
+		ingredients = node["ingredients"]
+	}
+	out.Ingredients = parseLines(stringList(ingredients))
+	return out, nil
+}
+
+func findRecipe(node any) (map[string]any, bool) {
+	switch v := node.(type) {
+	case []any:
+		for _, item := range v {
+			if found, ok := findRecipe(item); ok {
+				return found, true
+			}
+		}
+	case map[string]any:
+		if isRecipe(v["@type"]) {
+			return v, true
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findRecipe(graph)
+		}
+	}
+	return nil, false
+}
+
+func isRecipe(t any) bool {
+	for _, name := range stringList(t) {
+		if name == "Recipe" || strings.HasSuffix(name, "/Recipe") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseYield reads the servings count from recipeYield, which sites give as
+// a number, text such as "4 servings", or a list of both.
+func parseYield(v any) int {
+	if n, ok := v.(float64); ok {
+		return int(n)
+	}
+	for _, text := range stringList(v) {
+		if digits := firstNumber.FindString(text); digits != "" {
+			n, err := strconv.Atoi(digits)
+			if err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// stringList returns a JSON string or the strings of a JSON array, skipping
+// other values.
+func stringList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
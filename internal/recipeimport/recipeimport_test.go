@@ -0,0 +1,113 @@
+package recipeimport
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseIngredient(t *testing.T) {
+	cases := []struct {
+		line        string
+		quantity    float64
+		unit        string
+		name        string
+		weightG     float64
+		approximate bool
+	}{
+		{line: "200 g chicken breast", quantity: 200, unit: "g", name: "chicken breast", weightG: 200},
+		{line: "200g chicken breast, diced", quantity: 200, unit: "g", name: "chicken breast", weightG: 200},
+		{line: "1 cup rice", quantity: 1, unit: "cup", name: "rice", weightG: 240, approximate: true},
+		{line: "2 tbsp olive oil", quantity: 2, unit: "tbsp", name: "olive oil", weightG: 30, approximate: true},
+		{line: "1 ½ cups of milk", quantity: 1.5, unit: "cup", name: "milk", weightG: 360, approximate: true},
+		{line: "3/4 tsp. salt", quantity: 0.75, unit: "tsp", name: "salt", weightG: 3.75, approximate: true},
+		{line: "1,5 kg potatoes (peeled)", quantity: 1.5, unit: "kg", name: "potatoes", weightG: 1500},
+		{line: "1 T butter", quantity: 1, unit: "tbsp", name: "butter", weightG: 15, approximate: true},
+		{line: "2-3 cloves garlic", quantity: 2, name: "cloves garlic"},
+		{line: "1 tomato", quantity: 1, name: "tomato"},
+		{line: "2 large eggs", quantity: 2, name: "large eggs"},
+	}
+	for _, c := range cases {
+		got := ParseIngredient(c.line)
+		if got.Quantity == nil || math.Abs(*got.Quantity-c.quantity) > 1e-9 {
+			t.Fatalf("%q: expected quantity %v, got %v", c.line, c.quantity, got.Quantity)
+		}
+		if got.Unit != c.unit || got.Name != c.name || got.Approximate != c.approximate {
+			t.Fatalf("%q: unexpected ingredient %+v", c.line, got)
+		}
+		if c.weightG == 0 {
+			if got.WeightG != nil {
+				t.Fatalf("%q: expected no weight, got %v", c.line, *got.WeightG)
+			}
+			continue
+		}
+		if got.WeightG == nil || math.Abs(*got.WeightG-c.weightG) > 1e-9 {
+			t.Fatalf("%q: expected %v g, got %v", c.line, c.weightG, got.WeightG)
+		}
+	}
+
+	salt := ParseIngredient("Salt to taste")
+	if salt.Quantity != nil || salt.WeightG != nil || salt.Name != "Salt to taste" {
+		t.Fatalf("expected a line without quantity to keep its name, got %+v", salt)
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Run("html page with json-ld", func(t *testing.T) {
+		page := `<!doctype html><html><head>
+<script type="application/ld+json">{"@context":"https://schema.org","@type":"WebSite","name":"Cooking"}</script>
+<script type="application/ld+json">
+{"@context":"https://schema.org","@graph":[
+  {"@type":"BreadcrumbList"},
+  {"@type":["Recipe","NewsArticle"],"name":"Mac &amp; cheese","recipeYield":["4","4 servings"],
+   "recipeIngredient":["250 g macaroni","2 cups milk","100g cheddar, grated"]}
+]}
+</script></head><body></body></html>`
+		got, err := Parse(page)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		if got.Name != "Mac & cheese" || got.Servings != 4 || len(got.Ingredients) != 3 {
+			t.Fatalf("unexpected recipe %+v", got)
+		}
+		if got.Ingredients[2].Name != "cheddar" {
+			t.Fatalf("unexpected ingredient %+v", got.Ingredients[2])
+		}
+	})
+
+	t.Run("json-ld document", func(t *testing.T) {
+		got, err := Parse(`[{"@type":"Recipe","name":"Toast","recipeYield":2,"recipeIngredient":["2 slices bread"]}]`)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		if got.Name != "Toast" || got.Servings != 2 || got.Ingredients[0].Name != "slices bread" {
+			t.Fatalf("unexpected recipe %+v", got)
+		}
+	})
+
+	t.Run("pasted list", func(t *testing.T) {
+		got, err := Parse("- 200 g chicken breast\n\n* 1 cup rice\n3. 2 tbsp olive oil\n")
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		if got.Name != "" || len(got.Ingredients) != 3 || got.Ingredients[2].Name != "olive oil" {
+			t.Fatalf("unexpected recipe %+v", got)
+		}
+	})
+
+	t.Run("nothing to import", func(t *testing.T) {
+		for _, content := range []string{"", "  \n ", `<html><body>No recipe here</body></html>`, `{"@type":"Person"}`, `{"@type":"Recipe","name":"Empty"}`} {
+			if _, err := Parse(content); !errors.Is(err, ErrNoRecipe) {
+				t.Fatalf("%q: expected ErrNoRecipe, got %v", content, err)
+			}
+		}
+	})
+
+	t.Run("too many ingredients", func(t *testing.T) {
+		content := strings.Repeat("1 egg\n", MaxIngredients+1)
+		if _, err := Parse(content); !errors.Is(err, ErrTooManyIngredients) {
+			t.Fatalf("expected ErrTooManyIngredients, got %v", err)
+		}
+	})
+}
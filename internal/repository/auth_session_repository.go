@@ -72,6 +72,22 @@ func (r *AuthSessionRepository) GetActiveByTokenHash(ctx context.Context, tokenH
 	return value, nil
 }
 
+// IsActive reports whether the session exists, is not revoked and has not
+// expired at now.
+func (r *AuthSessionRepository) IsActive(ctx context.Context, id uint, now time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&AuthSession{}).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", now.UTC()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *AuthSessionRepository) RevokeByID(ctx context.Context, id uint, at time.Time) error {
 	result := r.db.WithContext(ctx).
 		Model(&AuthSession{}).
@@ -100,8 +116,9 @@ func (r *AuthSessionRepository) RevokeByTokenHash(ctx context.Context, tokenHash
 	return nil
 }
 
-func (r *AuthSessionRepository) Rotate(ctx context.Context, in RotateAuthSessionInput) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+func (r *AuthSessionRepository) Rotate(ctx context.Context, in RotateAuthSessionInput) (AuthSession, error) {
+	var value AuthSession
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		revoke := tx.Model(&AuthSession{}).
 			Where("token_hash = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", in.CurrentTokenHash, in.UserID, in.Now.UTC()).
 			Update("revoked_at", in.Now.UTC())
@@ -112,7 +129,7 @@ func (r *AuthSessionRepository) Rotate(ctx context.Context, in RotateAuthSession
 			return ErrNotFound
 		}
 
-		value := AuthSession{
+		value = AuthSession{
 			UserID:    in.UserID,
 			TokenHash: in.NewTokenHash,
 			ExpiresAt: in.ExpiresAt.UTC(),
@@ -122,4 +139,8 @@ func (r *AuthSessionRepository) Rotate(ctx context.Context, in RotateAuthSession
 		}
 		return nil
 	})
+	if err != nil {
+		return AuthSession{}, err
+	}
+	return value, nil
 }
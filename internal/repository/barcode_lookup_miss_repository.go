@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BarcodeLookupMiss remembers that a catalog did not know a barcode, so the
+// catalog is not asked again until the entry expires.
+type BarcodeLookupMiss struct {
+	Source    string    `gorm:"column:source;primaryKey"`
+	Barcode   string    `gorm:"column:barcode;primaryKey"`
+	CheckedAt time.Time `gorm:"column:checked_at"`
+}
+
+func (BarcodeLookupMiss) TableName() string {
+	return "barcode_lookup_misses"
+}
+
+type BarcodeLookupMissRepository struct {
+	db *gorm.DB
+}
+
+func NewBarcodeLookupMissRepository(database *gorm.DB) *BarcodeLookupMissRepository {
+	return &BarcodeLookupMissRepository{db: database}
+}
+
+// HasMiss reports whether source missed barcode at or after since.
+func (r *BarcodeLookupMissRepository) HasMiss(ctx context.Context, source, barcode string, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&BarcodeLookupMiss{}).
+		Where("source = ? AND barcode = ? AND checked_at >= ?", source, barcode, since.UTC()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *BarcodeLookupMissRepository) RecordMiss(ctx context.Context, source, barcode string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source"}, {Name: "barcode"}},
+			DoUpdates: clause.AssignmentColumns([]string{"checked_at"}),
+		}).
+		Create(&BarcodeLookupMiss{Source: source, Barcode: barcode, CheckedAt: at.UTC()}).Error
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"goal-bite-api/internal/domain/cooking"
+
+	"gorm.io/gorm"
+)
+
+type CookingRepository struct {
+	db *gorm.DB
+}
+
+func NewCookingRepository(database *gorm.DB) *CookingRepository {
+	return &CookingRepository{db: database}
+}
+
+// ListMethods returns the cooking methods with their factors, defaults
+// first.
+func (r *CookingRepository) ListMethods(ctx context.Context) ([]cooking.Method, error) {
+	var out []cooking.Method
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	var factors []cooking.Factor
+	if err := r.db.WithContext(ctx).
+		Order("cooking_method_id ASC, category_id ASC NULLS FIRST").
+		Find(&factors).Error; err != nil {
+		return nil, err
+	}
+	byMethod := make(map[uint][]cooking.Factor, len(out))
+	for _, f := range factors {
+		byMethod[f.MethodID] = append(byMethod[f.MethodID], f)
+	}
+	for i := range out {
+		out[i].Factors = byMethod[out[i].ID]
+		if out[i].Factors == nil {
+			out[i].Factors = []cooking.Factor{}
+		}
+	}
+	return out, nil
+}
+
+// YieldFactor returns how foods of categoryID change when cooked by
+// methodID: the factor of the category, else of its nearest ancestor with
+// one, else the method's default. A nil category gets the default.
+func (r *CookingRepository) YieldFactor(ctx context.Context, methodID uint, categoryID *uint) (cooking.Factor, error) {
+	var category uint
+	if categoryID != nil {
+		category = *categoryID
+	}
+	var out []cooking.Factor
+	err := r.db.WithContext(ctx).Raw(`
+WITH RECURSIVE lineage AS (
+    SELECT id, parent_id, 0 AS level FROM food_categories WHERE id = ?
+    UNION ALL
+    SELECT c.id, c.parent_id, lineage.level + 1 FROM food_categories c JOIN lineage ON c.id = lineage.parent_id
+)
+SELECT f.*
+FROM cooking_yield_factors f
+LEFT JOIN lineage ON lineage.id = f.category_id
+WHERE f.cooking_method_id = ? AND (f.category_id IS NULL OR lineage.id IS NOT NULL)
+ORDER BY lineage.level ASC NULLS LAST
+LIMIT 1`, category, methodID).Scan(&out).Error
+	if err != nil {
+		return cooking.Factor{}, err
+	}
+	if len(out) == 0 {
+		return cooking.Factor{}, ErrNotFound
+	}
+	return out[0], nil
+}
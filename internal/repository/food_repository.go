@@ -2,12 +2,19 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
+	"goal-bite-api/internal/domain/mealitem"
+	"goal-bite-api/internal/domain/recipeingredient"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type FoodRepository struct {
@@ -22,14 +29,120 @@ type FoodUpdate struct {
 	ProteinPer100g *float64
 	CarbsPer100g   *float64
 	FatPer100g     *float64
+	AlcoholPer100g *float64
+	Visibility     *food.Visibility
+	CategoryID     *uint
+	Tags           *[]string
+	Allergens      *[]string
+	DietFlags      *[]string
+	// Translations replaces all translated names. Like tags they are not
+	// part of version history.
+	Translations *[]locale.Translation
+	// ClearBrandName, ClearBarcode and ClearCategory set the column to NULL,
+	// which a nil pointer cannot express.
+	ClearBrandName bool
+	ClearBarcode   bool
+	ClearCategory  bool
+	// EditedBy is recorded on the version the update creates.
+	EditedBy uint
+	// RestoredFrom marks the new version as a rollback to an older one.
+	RestoredFrom *int
+}
+
+// FoodListQuery selects the foods UserID may see. Query matches names,
+// including translated names and synonyms in Languages. CategoryID includes
+// its subcategories and a food must carry every tag in Tags. Foods declaring
+// any of AvoidAllergens or lacking one of RequiredDietFlags are left out.
+type FoodListQuery struct {
+	UserID            uint
+	Query             string
+	Languages         []string
+	IncludeArchived   bool
+	CategoryID        *uint
+	Tags              []string
+	AvoidAllergens    []string
+	RequiredDietFlags []string
+	Limit             int
+	Offset            int
+}
+
+// FoodFacets counts the foods matching a FoodListQuery per category and per
+// tag, ignoring pagination.
+type FoodFacets struct {
+	Categories []FoodCategoryCount
+	Tags       []FoodTagCount
+}
+
+type FoodCategoryCount struct {
+	CategoryID uint
+	Slug       string
+	Name       string
+	Count      int64
+}
+
+type FoodTagCount struct {
+	Tag   string
+	Count int64
+}
+
+// maxTagFacets caps the tag counts returned with search results.
+const maxTagFacets = 20
+
+// DuplicateCandidateQuery selects foods that might duplicate another one:
+// those sharing its barcode or any of its name terms.
+type DuplicateCandidateQuery struct {
+	UserID    uint
+	ExcludeID uint
+	Barcode   *string
+	NameTerms []string
+	Limit     int
+}
+
+// FoodMerge folds MergedIDs into SurvivorID.
+type FoodMerge struct {
+	SurvivorID uint
+	MergedIDs  []uint
+	MergedBy   uint
+	At         time.Time
+}
+
+type FoodMergeResult struct {
+	Merges []food.Merge
+	// RecipeIDs lists recipes whose ingredients now point at the survivor.
+	RecipeIDs []uint
 }
 
 func NewFoodRepository(database *gorm.DB) *FoodRepository {
 	return &FoodRepository{db: database}
 }
 
+// Create inserts the food together with its first version and its
+// translated names.
 func (r *FoodRepository) Create(ctx context.Context, value food.Food) (food.Food, error) {
-	if err := r.db.WithContext(ctx).Create(&value).Error; err != nil {
+	value.CurrentVersion = 1
+	if value.Tags == nil {
+		value.Tags = []string{}
+	}
+	if value.Allergens == nil {
+		value.Allergens = []string{}
+	}
+	if value.DietFlags == nil {
+		value.DietFlags = []string{}
+	}
+	if value.Translations == nil {
+		value.Translations = []locale.Translation{}
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&value).Error; err != nil {
+			return err
+		}
+		if err := foodNames.replace(tx, value.ID, value.Translations); err != nil {
+			return err
+		}
+		version := value.Snapshot(1, value.UserID)
+		return tx.Create(&version).Error
+	})
+	if err != nil {
 		return food.Food{}, err
 	}
 	return value, nil
@@ -45,12 +158,57 @@ func (r *FoodRepository) GetByID(ctx context.Context, id uint) (food.Food, error
 		return food.Food{}, err
 	}
 
-	return f, nil
+	return r.withName(ctx, f)
 }
 
-func (r *FoodRepository) GetByBarcode(ctx context.Context, barcode string) (food.Food, error) {
+// ListByIDs returns the foods with the given IDs, archived ones included, in
+// no particular order. Unknown IDs are skipped.
+func (r *FoodRepository) ListByIDs(ctx context.Context, ids []uint) ([]food.Food, error) {
+	var foods []food.Food
+	if len(ids) == 0 {
+		return foods, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&foods).Error; err != nil {
+		return nil, err
+	}
+	if err := r.withNames(ctx, foods); err != nil {
+		return nil, err
+	}
+	return foods, nil
+}
+
+// GetByBarcode returns the food userID should see for a barcode. Verified
+// entries win, then the caller's own private entry, then a public one.
+// Archived foods are skipped.
+func (r *FoodRepository) GetByBarcode(ctx context.Context, userID uint, barcode string) (food.Food, error) {
 	var f food.Food
-	err := r.db.WithContext(ctx).Where("barcode = ?", barcode).First(&f).Error
+	err := r.db.WithContext(ctx).
+		Where("barcode = ?", barcode).
+		Scopes(visibleTo(userID), notArchived(false)).
+		Order("CASE visibility WHEN 'verified' THEN 0 WHEN 'private' THEN 1 ELSE 2 END, id ASC").
+		First(&f).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return food.Food{}, ErrNotFound
+	}
+	if err != nil {
+		return food.Food{}, err
+	}
+	return r.withName(ctx, f)
+}
+
+// GetByBarcodeInScope returns the food holding barcode within the uniqueness
+// scope of visibility: the owner's private foods, or all foods sharing the
+// public or verified level. Archived foods no longer hold their barcode.
+func (r *FoodRepository) GetByBarcodeInScope(ctx context.Context, userID uint, visibility food.Visibility, barcode string) (food.Food, error) {
+	query := r.db.WithContext(ctx).
+		Where("barcode = ? AND visibility = ?", barcode, visibility).
+		Scopes(notArchived(false))
+	if visibility == food.VisibilityPrivate {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var f food.Food
+	err := query.First(&f).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return food.Food{}, ErrNotFound
 	}
@@ -60,31 +218,231 @@ func (r *FoodRepository) GetByBarcode(ctx context.Context, barcode string) (food
 	return f, nil
 }
 
-func (r *FoodRepository) List(ctx context.Context, limit, offset int) ([]food.Food, error) {
+func (r *FoodRepository) List(ctx context.Context, q FoodListQuery) ([]food.Food, error) {
 	var foods []food.Food
 	err := r.db.WithContext(ctx).
-		Select("id, user_id, name, brand_name, barcode, kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, created_at, updated_at").
+		Select("id, user_id, name, brand_name, barcode, kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, alcohol_per_100g, visibility, category_id, tags, allergens, diet_flags, current_version, archived_at, merged_into_id, source, source_ref, imported_at, image_key, image_url, thumbnail_url, created_at, updated_at").
+		Scopes(matchingList(q)).
 		Order("id ASC").
-		Limit(limit).
-		Offset(offset).
+		Limit(q.Limit).
+		Offset(q.Offset).
 		Find(&foods).Error
 	if err != nil {
 		return nil, err
 	}
 
+	if err := r.withNames(ctx, foods); err != nil {
+		return nil, err
+	}
 	return foods, nil
 }
 
-func (r *FoodRepository) SearchByName(ctx context.Context, query string, limit, offset int) ([]food.Food, error) {
-	q := strings.TrimSpace(query)
-	if q == "" {
-		return r.List(ctx, limit, offset)
+// Facets counts every food matching q by category and by tag, largest
+// counts first. Uncategorized foods are left out of the category counts and
+// only the maxTagFacets most used tags are returned.
+func (r *FoodRepository) Facets(ctx context.Context, q FoodListQuery) (FoodFacets, error) {
+	var out FoodFacets
+	perCategory := r.db.Model(&food.Food{}).
+		Select("category_id, COUNT(*) AS count").
+		Where("category_id IS NOT NULL").
+		Scopes(matchingList(q)).
+		Group("category_id")
+	err := r.db.WithContext(ctx).
+		Table("(?) AS m", perCategory).
+		Select("c.id AS category_id, c.slug, c.name, m.count").
+		Joins("JOIN food_categories c ON c.id = m.category_id").
+		Order("m.count DESC, c.id ASC").
+		Scan(&out.Categories).Error
+	if err != nil {
+		return FoodFacets{}, err
+	}
+
+	tags := r.db.Model(&food.Food{}).
+		Select("jsonb_array_elements_text(tags) AS tag").
+		Scopes(matchingList(q))
+	err = r.db.WithContext(ctx).
+		Table("(?) AS t", tags).
+		Select("tag, COUNT(*) AS count").
+		Group("tag").
+		Order("count DESC, tag ASC").
+		Limit(maxTagFacets).
+		Scan(&out.Tags).Error
+	if err != nil {
+		return FoodFacets{}, err
+	}
+	return out, nil
+}
+
+// Update applies updates and records the result as the next version, so the
+// history always ends with the current state.
+func (r *FoodRepository) Update(ctx context.Context, id uint, updates FoodUpdate) (food.Food, error) {
+	var f food.Food
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&f, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		changes := map[string]any{"current_version": f.CurrentVersion + 1}
+		if updates.Name != nil {
+			changes["name"] = *updates.Name
+		}
+		if updates.BrandName != nil {
+			changes["brand_name"] = *updates.BrandName
+		} else if updates.ClearBrandName {
+			changes["brand_name"] = nil
+		}
+		if updates.Barcode != nil {
+			changes["barcode"] = *updates.Barcode
+		} else if updates.ClearBarcode {
+			changes["barcode"] = nil
+		}
+		if updates.KcalPer100g != nil {
+			changes["kcal_per_100g"] = *updates.KcalPer100g
+		}
+		if updates.ProteinPer100g != nil {
+			changes["protein_per_100g"] = *updates.ProteinPer100g
+		}
+		if updates.CarbsPer100g != nil {
+			changes["carbs_per_100g"] = *updates.CarbsPer100g
+		}
+		if updates.FatPer100g != nil {
+			changes["fat_per_100g"] = *updates.FatPer100g
+		}
+		if updates.AlcoholPer100g != nil {
+			changes["alcohol_per_100g"] = *updates.AlcoholPer100g
+		}
+		if updates.Visibility != nil {
+			changes["visibility"] = *updates.Visibility
+		}
+		if updates.CategoryID != nil {
+			changes["category_id"] = *updates.CategoryID
+		} else if updates.ClearCategory {
+			changes["category_id"] = nil
+		}
+		if updates.Tags != nil {
+			tags, err := json.Marshal(*updates.Tags)
+			if err != nil {
+				return err
+			}
+			changes["tags"] = string(tags)
+		}
+		if updates.Allergens != nil {
+			allergens, err := json.Marshal(*updates.Allergens)
+			if err != nil {
+				return err
+			}
+			changes["allergens"] = string(allergens)
+		}
+		if updates.DietFlags != nil {
+			flags, err := json.Marshal(*updates.DietFlags)
+			if err != nil {
+				return err
+			}
+			changes["diet_flags"] = string(flags)
+		}
+		if updates.Translations != nil {
+			if err := foodNames.replace(tx, id, *updates.Translations); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&f).Updates(changes).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&f, id).Error; err != nil {
+			return err
+		}
+
+		version := f.Snapshot(f.CurrentVersion, updates.EditedBy)
+		version.RestoredFrom = updates.RestoredFrom
+		return tx.Create(&version).Error
+	})
+	if err != nil {
+		return food.Food{}, err
+	}
+
+	return r.withName(ctx, f)
+}
+
+// SetImage records a newly stored image, or clears it when img is nil.
+// Images are not part of version history.
+func (r *FoodRepository) SetImage(ctx context.Context, id uint, img *Image) (food.Food, error) {
+	res := r.db.WithContext(ctx).Model(&food.Food{}).Where("id = ?", id).Updates(imageChanges(img))
+	if res.Error != nil {
+		return food.Food{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return food.Food{}, ErrNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+// ListVersions returns the history of a food, newest first.
+func (r *FoodRepository) ListVersions(ctx context.Context, foodID uint) ([]food.Version, error) {
+	var out []food.Version
+	err := r.db.WithContext(ctx).
+		Where("food_id = ?", foodID).
+		Order("version DESC").
+		Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *FoodRepository) GetVersion(ctx context.Context, foodID uint, version int) (food.Version, error) {
+	var out food.Version
+	err := r.db.WithContext(ctx).Where("food_id = ? AND version = ?", foodID, version).First(&out).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return food.Version{}, ErrNotFound
+	}
+	if err != nil {
+		return food.Version{}, err
+	}
+	return out, nil
+}
+
+// ListDuplicateCandidates returns active foods visible to the caller that
+// match the query loosely. Scoring is left to the caller.
+func (r *FoodRepository) ListDuplicateCandidates(ctx context.Context, q DuplicateCandidateQuery) ([]food.Food, error) {
+	match := r.db.Where("1 = 0")
+	if q.Barcode != nil {
+		match = match.Or("barcode = ?", *q.Barcode)
+	}
+	for _, term := range q.NameTerms {
+		match = match.Or("name ILIKE ?", "%"+term+"%")
 	}
 
 	var foods []food.Food
 	err := r.db.WithContext(ctx).
-		Select("id, user_id, name, brand_name, barcode, kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, created_at, updated_at").
-		Where("name ILIKE ?", "%"+q+"%").
+		Where("id <> ?", q.ExcludeID).
+		Where(match).
+		Scopes(visibleTo(q.UserID), notArchived(false)).
+		Order("id ASC").
+		Limit(q.Limit).
+		Find(&foods).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.withNames(ctx, foods); err != nil {
+		return nil, err
+	}
+	return foods, nil
+}
+
+// ListNutritionSuspects returns active foods visible to userID whose values
+// fail food.CheckNutrition, using the same factors and tolerances in SQL.
+func (r *FoodRepository) ListNutritionSuspects(ctx context.Context, userID uint, limit, offset int) ([]food.Food, error) {
+	estimate := fmt.Sprintf("(protein_per_100g * %d + carbs_per_100g * %d + fat_per_100g * %d + alcohol_per_100g * %d)",
+		food.KcalPerGramProtein, food.KcalPerGramCarbs, food.KcalPerGramFat, food.KcalPerGramAlcohol)
+	var foods []food.Food
+	err := r.db.WithContext(ctx).
+		Where("ABS(kcal_per_100g - "+estimate+") > GREATEST(?, "+estimate+" * ?) OR protein_per_100g + carbs_per_100g + fat_per_100g + alcohol_per_100g > ?",
+			food.KcalToleranceMin, food.KcalToleranceRatio, 100+food.MacroMassTolerance).
+		Scopes(visibleTo(userID), notArchived(false)).
 		Order("id ASC").
 		Limit(limit).
 		Offset(offset).
@@ -92,62 +450,266 @@ func (r *FoodRepository) SearchByName(ctx context.Context, query string, limit,
 	if err != nil {
 		return nil, err
 	}
-
+	if err := r.withNames(ctx, foods); err != nil {
+		return nil, err
+	}
 	return foods, nil
 }
 
-func (r *FoodRepository) Update(ctx context.Context, id uint, updates FoodUpdate) (food.Food, error) {
-	var f food.Food
-	if err := r.db.WithContext(ctx).First(&f, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return food.Food{}, ErrNotFound
+// Merge re-points meal items, recipe ingredients and shopping list items from
+// the merged foods to the survivor, marks the recipes that used them stale,
+// archives the merged foods and logs each merge, all in one transaction.
+// Moved rows lose their food_version because version numbers belong to the
+// food they were recorded against.
+func (r *FoodRepository) Merge(ctx context.Context, in FoodMerge) (FoodMergeResult, error) {
+	var out FoodMergeResult
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := append([]uint{in.SurvivorID}, in.MergedIDs...)
+		var locked []food.Food
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND archived_at IS NULL", ids).
+			Find(&locked).Error; err != nil {
+			return err
 		}
-		return food.Food{}, err
+		if len(locked) != len(ids) {
+			return ErrNotFound
+		}
+
+		if err := tx.Model(&recipeingredient.RecipeIngredient{}).
+			Distinct("recipe_id").
+			Where("food_id IN ?", in.MergedIDs).
+			Order("recipe_id ASC").
+			Pluck("recipe_id", &out.RecipeIDs).Error; err != nil {
+			return err
+		}
+
+		var mergedBy *uint
+		if in.MergedBy != 0 {
+			mergedBy = &in.MergedBy
+		}
+		for _, id := range in.MergedIDs {
+			// Marked before the move, while the ingredients still name the
+			// merged food, so a failed recalculation later is retried.
+			if _, err := markRecipesStale(tx, "food_id", id); err != nil {
+				return err
+			}
+			moved := tx.Model(&mealitem.MealItem{}).
+				Where("food_id = ?", id).
+				Updates(map[string]any{"food_id": in.SurvivorID, "food_version": nil})
+			if moved.Error != nil {
+				return moved.Error
+			}
+			movedIngredients := tx.Model(&recipeingredient.RecipeIngredient{}).
+				Where("food_id = ?", id).
+				Updates(map[string]any{"food_id": in.SurvivorID, "food_version": nil})
+			if movedIngredients.Error != nil {
+				return movedIngredients.Error
+			}
+			if err := moveShoppingListItems(tx, id, in.SurvivorID, in.At); err != nil {
+				return err
+			}
+
+			entry := food.Merge{
+				SurvivorFoodID:         in.SurvivorID,
+				MergedFoodID:           id,
+				MergedBy:               mergedBy,
+				MealItemsMoved:         int(moved.RowsAffected),
+				RecipeIngredientsMoved: int(movedIngredients.RowsAffected),
+				CreatedAt:              in.At,
+			}
+			if err := tx.Create(&entry).Error; err != nil {
+				return err
+			}
+			out.Merges = append(out.Merges, entry)
+		}
+
+		return tx.Model(&food.Food{}).
+			Where("id IN ?", in.MergedIDs).
+			Updates(map[string]any{"archived_at": in.At, "merged_into_id": in.SurvivorID}).Error
+	})
+	if err != nil {
+		return FoodMergeResult{}, err
+	}
+	return out, nil
+}
+
+// moveShoppingListItems re-points shopping list items from one food to
+// another. A list holds each food once, so where it already has the target
+// food the weights are added up and the combined item stays checked only if
+// both were.
+func moveShoppingListItems(tx *gorm.DB, fromID, toID uint, at time.Time) error {
+	if err := tx.Exec(`UPDATE shopping_list_items AS target
+		SET weight_g = target.weight_g + merged.weight_g,
+			checked = target.checked AND merged.checked,
+			updated_at = ?
+		FROM shopping_list_items AS merged
+		WHERE target.food_id = ? AND merged.food_id = ? AND merged.shopping_list_id = target.shopping_list_id`,
+		at, toID, fromID).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM shopping_list_items AS merged
+		WHERE merged.food_id = ? AND EXISTS (
+			SELECT 1 FROM shopping_list_items AS target
+			WHERE target.shopping_list_id = merged.shopping_list_id AND target.food_id = ?
+		)`, fromID, toID).Error; err != nil {
+		return err
+	}
+	return tx.Table("shopping_list_items").
+		Where("food_id = ?", fromID).
+		Updates(map[string]any{"food_id": toID, "updated_at": at}).Error
+}
+
+// Archive hides the food from lists, search and barcode lookups. The row is
+// kept because meal items and recipes reference it.
+func (r *FoodRepository) Archive(ctx context.Context, id uint, at time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&food.Food{}).
+		Where("id = ? AND archived_at IS NULL", id).
+		Update("archived_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
 	}
+	return nil
+}
 
-	changes := map[string]any{}
-	if updates.Name != nil {
-		changes["name"] = *updates.Name
+func (r *FoodRepository) Restore(ctx context.Context, id uint) (food.Food, error) {
+	res := r.db.WithContext(ctx).
+		Model(&food.Food{}).
+		Where("id = ?", id).
+		Update("archived_at", nil)
+	if res.Error != nil {
+		return food.Food{}, res.Error
 	}
-	if updates.BrandName != nil {
-		changes["brand_name"] = *updates.BrandName
+	if res.RowsAffected == 0 {
+		return food.Food{}, ErrNotFound
 	}
-	if updates.Barcode != nil {
-		changes["barcode"] = *updates.Barcode
+	return r.GetByID(ctx, id)
+}
+
+func (r *FoodRepository) ListCategories(ctx context.Context) ([]food.Category, error) {
+	var out []food.Category
+	if err := r.db.WithContext(ctx).Order("name ASC, id ASC").Find(&out).Error; err != nil {
+		return nil, err
 	}
-	if updates.KcalPer100g != nil {
-		changes["kcal_per_100g"] = *updates.KcalPer100g
+	return out, nil
+}
+
+func (r *FoodRepository) GetCategory(ctx context.Context, id uint) (food.Category, error) {
+	var out food.Category
+	err := r.db.WithContext(ctx).First(&out, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return food.Category{}, ErrNotFound
 	}
-	if updates.ProteinPer100g != nil {
-		changes["protein_per_100g"] = *updates.ProteinPer100g
+	if err != nil {
+		return food.Category{}, err
 	}
-	if updates.CarbsPer100g != nil {
-		changes["carbs_per_100g"] = *updates.CarbsPer100g
+	return out, nil
+}
+
+// CategoryByAlias maps category identifiers from an external catalog onto
+// the taxonomy. When several match, the deepest category wins, so a product
+// tagged both as dairy and as yogurt lands in yogurt.
+func (r *FoodRepository) CategoryByAlias(ctx context.Context, aliases []string) (food.Category, error) {
+	if len(aliases) == 0 {
+		return food.Category{}, ErrNotFound
 	}
-	if updates.FatPer100g != nil {
-		changes["fat_per_100g"] = *updates.FatPer100g
+	var out food.Category
+	err := r.db.WithContext(ctx).Raw(`
+WITH RECURSIVE depth AS (
+    SELECT id, 0 AS level FROM food_categories WHERE parent_id IS NULL
+    UNION ALL
+    SELECT c.id, depth.level + 1 FROM food_categories c JOIN depth ON c.parent_id = depth.id
+)
+SELECT c.*
+FROM food_category_aliases a
+JOIN food_categories c ON c.id = a.category_id
+JOIN depth ON depth.id = c.id
+WHERE a.alias IN ?
+ORDER BY depth.level DESC, c.id ASC
+LIMIT 1`, aliases).Scan(&out).Error
+	if err != nil {
+		return food.Category{}, err
 	}
+	if out.ID == 0 {
+		return food.Category{}, ErrNotFound
+	}
+	return out, nil
+}
 
-	if len(changes) > 0 {
-		if err := r.db.WithContext(ctx).Model(&f).Updates(changes).Error; err != nil {
-			return food.Food{}, err
-		}
+// withNames fills in the translated names of foods.
+func (r *FoodRepository) withNames(ctx context.Context, foods []food.Food) error {
+	ids := make([]uint, 0, len(foods))
+	for _, f := range foods {
+		ids = append(ids, f.ID)
+	}
+	names, err := foodNames.load(ctx, r.db, ids)
+	if err != nil {
+		return err
 	}
+	for i := range foods {
+		foods[i].Translations = names[foods[i].ID]
+	}
+	return nil
+}
 
-	if err := r.db.WithContext(ctx).First(&f, id).Error; err != nil {
+func (r *FoodRepository) withName(ctx context.Context, f food.Food) (food.Food, error) {
+	foods := []food.Food{f}
+	if err := r.withNames(ctx, foods); err != nil {
 		return food.Food{}, err
 	}
+	return foods[0], nil
+}
 
-	return f, nil
+// matchingList applies the filters of a FoodListQuery, leaving ordering and
+// pagination to the caller.
+func matchingList(q FoodListQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if term := strings.TrimSpace(q.Query); term != "" {
+			condition, args := foodNames.matching(term, q.Languages)
+			db = db.Where(condition, args...)
+		}
+		if q.CategoryID != nil {
+			db = db.Where(`category_id IN (
+WITH RECURSIVE subtree AS (
+    SELECT id FROM food_categories WHERE id = ?
+    UNION ALL
+    SELECT c.id FROM food_categories c JOIN subtree ON c.parent_id = subtree.id
+)
+SELECT id FROM subtree)`, *q.CategoryID)
+		}
+		if len(q.Tags) > 0 {
+			// Marshalling a []string cannot fail.
+			tags, _ := json.Marshal(q.Tags)
+			db = db.Where("tags @> ?::jsonb", string(tags))
+		}
+		if len(q.AvoidAllergens) > 0 {
+			db = db.Where("NOT jsonb_exists_any(allergens, ARRAY[?])", q.AvoidAllergens)
+		}
+		if len(q.RequiredDietFlags) > 0 {
+			db = db.Where("diet_flags @> ?::jsonb", jsonList(q.RequiredDietFlags))
+		}
+		return db.Scopes(visibleTo(q.UserID), notArchived(q.IncludeArchived))
+	}
 }
 
-func (r *FoodRepository) Delete(ctx context.Context, id uint) error {
-	res := r.db.WithContext(ctx).Delete(&food.Food{}, id)
-	if res.Error != nil {
-		return res.Error
+// visibleTo limits a food query to public and verified foods plus userID's
+// private ones.
+func visibleTo(userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(visibility <> ? OR user_id = ?)", food.VisibilityPrivate, userID)
 	}
-	if res.RowsAffected == 0 {
-		return ErrNotFound
+}
+
+// notArchived hides archived rows unless includeArchived is set. It is shared
+// by foods and recipes, which archive the same way.
+func notArchived(includeArchived bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if includeArchived {
+			return db
+		}
+		return db.Where("archived_at IS NULL")
 	}
-	return nil
 }
@@ -0,0 +1,17 @@
+package repository
+
+// Image records where an uploaded image was stored. The thumbnail key is
+// derived from Key, so only the URLs of both renditions are kept.
+type Image struct {
+	Key          string
+	URL          string
+	ThumbnailURL string
+}
+
+// imageChanges maps img onto the image columns; nil clears them.
+func imageChanges(img *Image) map[string]any {
+	if img == nil {
+		return map[string]any{"image_key": nil, "image_url": nil, "thumbnail_url": nil}
+	}
+	return map[string]any{"image_key": img.Key, "image_url": img.URL, "thumbnail_url": img.ThumbnailURL}
+}
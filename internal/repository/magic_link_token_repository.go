@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MagicLinkToken struct {
+	ID         uint       `gorm:"primaryKey"`
+	UserID     uint       `gorm:"column:user_id"`
+	TokenHash  string     `gorm:"column:token_hash"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at"`
+	ConsumedAt *time.Time `gorm:"column:consumed_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+}
+
+func (MagicLinkToken) TableName() string {
+	return "magic_link_tokens"
+}
+
+type MagicLinkTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewMagicLinkTokenRepository(database *gorm.DB) *MagicLinkTokenRepository {
+	return &MagicLinkTokenRepository{db: database}
+}
+
+type CreateMagicLinkTokenInput struct {
+	UserID    uint
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+func (r *MagicLinkTokenRepository) Create(ctx context.Context, in CreateMagicLinkTokenInput) (MagicLinkToken, error) {
+	value := MagicLinkToken{
+		UserID:    in.UserID,
+		TokenHash: in.TokenHash,
+		ExpiresAt: in.ExpiresAt.UTC(),
+	}
+	if err := r.db.WithContext(ctx).Create(&value).Error; err != nil {
+		return MagicLinkToken{}, err
+	}
+	return value, nil
+}
+
+// Consume marks an unexpired, unused token as consumed and returns it.
+// A token can be consumed at most once.
+func (r *MagicLinkTokenRepository) Consume(ctx context.Context, tokenHash string, now time.Time) (MagicLinkToken, error) {
+	var value MagicLinkToken
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", tokenHash).
+			Where("consumed_at IS NULL").
+			Where("expires_at > ?", now.UTC()).
+			First(&value).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		consumedAt := now.UTC()
+		if err := tx.Model(&MagicLinkToken{}).
+			Where("id = ?", value.ID).
+			Update("consumed_at", consumedAt).Error; err != nil {
+			return err
+		}
+		value.ConsumedAt = &consumedAt
+		return nil
+	})
+	if err != nil {
+		return MagicLinkToken{}, err
+	}
+	return value, nil
+}
@@ -22,13 +22,18 @@ type CreateMealInput struct {
 }
 
 type AddMealItemInput struct {
-	FoodID         *uint
-	RecipeID       *uint
-	WeightG        float64
-	KcalPer100g    float64
-	ProteinPer100g float64
-	CarbsPer100g   float64
-	FatPer100g     float64
+	FoodID          *uint
+	FoodVersion     *int
+	RecipeID        *uint
+	WeightG         float64
+	Servings        *float64
+	CookedWeightG   *float64
+	CookingMethodID *uint
+	WaterChangeG    *float64
+	KcalPer100g     float64
+	ProteinPer100g  float64
+	CarbsPer100g    float64
+	FatPer100g      float64
 }
 
 type UpdateMealInput struct {
@@ -66,14 +71,19 @@ func (r *MealRepository) CreateWithItems(ctx context.Context, in CreateMealInput
 		dbItems := make([]mealitem.MealItem, 0, len(items))
 		for _, inItem := range items {
 			dbItems = append(dbItems, mealitem.MealItem{
-				MealID:         out.ID,
-				FoodID:         inItem.FoodID,
-				RecipeID:       inItem.RecipeID,
-				WeightG:        inItem.WeightG,
-				KcalPer100g:    inItem.KcalPer100g,
-				ProteinPer100g: inItem.ProteinPer100g,
-				CarbsPer100g:   inItem.CarbsPer100g,
-				FatPer100g:     inItem.FatPer100g,
+				MealID:          out.ID,
+				FoodID:          inItem.FoodID,
+				FoodVersion:     inItem.FoodVersion,
+				RecipeID:        inItem.RecipeID,
+				WeightG:         inItem.WeightG,
+				Servings:        inItem.Servings,
+				CookedWeightG:   inItem.CookedWeightG,
+				CookingMethodID: inItem.CookingMethodID,
+				WaterChangeG:    inItem.WaterChangeG,
+				KcalPer100g:     inItem.KcalPer100g,
+				ProteinPer100g:  inItem.ProteinPer100g,
+				CarbsPer100g:    inItem.CarbsPer100g,
+				FatPer100g:      inItem.FatPer100g,
 			})
 		}
 
@@ -137,7 +147,7 @@ func (r *MealRepository) ListByUserAndDate(ctx context.Context, userID uint, dat
 
 	var out []meal.Meal
 	err := r.db.WithContext(ctx).
-		Select("id, user_id, meal_type, eaten_at, created_at, updated_at").
+		Select("id, user_id, meal_type, eaten_at, image_key, image_url, thumbnail_url, created_at, updated_at").
 		Where("user_id = ?", userID).
 		Where("eaten_at >= ? AND eaten_at < ?", start, end).
 		Order("eaten_at ASC, id ASC").
@@ -148,33 +158,60 @@ func (r *MealRepository) ListByUserAndDate(ctx context.Context, userID uint, dat
 		return nil, err
 	}
 
-	if len(out) == 0 {
-		return out, nil
+	if err := r.withItems(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListByUserBetween returns all of the user's meals eaten in [from, to) with
+// their items, oldest first.
+func (r *MealRepository) ListByUserBetween(ctx context.Context, userID uint, from, to time.Time) ([]meal.Meal, error) {
+	var out []meal.Meal
+	err := r.db.WithContext(ctx).
+		Select("id, user_id, meal_type, eaten_at, image_key, image_url, thumbnail_url, created_at, updated_at").
+		Where("user_id = ?", userID).
+		Where("eaten_at >= ? AND eaten_at < ?", from, to).
+		Order("eaten_at ASC, id ASC").
+		Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withItems(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// withItems loads the items of meals in one query.
+func (r *MealRepository) withItems(ctx context.Context, meals []meal.Meal) error {
+	if len(meals) == 0 {
+		return nil
 	}
 
-	mealIDs := make([]uint, 0, len(out))
-	for _, m := range out {
+	mealIDs := make([]uint, 0, len(meals))
+	for _, m := range meals {
 		mealIDs = append(mealIDs, m.ID)
 	}
 
 	var items []mealitem.MealItem
 	if err := r.db.WithContext(ctx).
-		Select("id, meal_id, food_id, recipe_id, weight_g, kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, created_at, updated_at").
+		Select("id, meal_id, food_id, recipe_id, weight_g, servings, cooked_weight_g, cooking_method_id, water_change_g, kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, created_at, updated_at").
 		Where("meal_id IN ?", mealIDs).
 		Order("id ASC").
 		Find(&items).Error; err != nil {
-		return nil, err
+		return err
 	}
 
-	itemsByMealID := make(map[uint][]mealitem.MealItem, len(out))
+	itemsByMealID := make(map[uint][]mealitem.MealItem, len(meals))
 	for _, item := range items {
 		itemsByMealID[item.MealID] = append(itemsByMealID[item.MealID], item)
 	}
-	for i := range out {
-		out[i].Items = itemsByMealID[out[i].ID]
+	for i := range meals {
+		meals[i].Items = itemsByMealID[meals[i].ID]
 	}
-
-	return out, nil
+	return nil
 }
 
 func (r *MealRepository) AddItem(ctx context.Context, mealID uint, in AddMealItemInput) (mealitem.MealItem, error) {
@@ -187,14 +224,19 @@ func (r *MealRepository) AddItem(ctx context.Context, mealID uint, in AddMealIte
 	}
 
 	item := mealitem.MealItem{
-		MealID:         mealID,
-		FoodID:         in.FoodID,
-		RecipeID:       in.RecipeID,
-		WeightG:        in.WeightG,
-		KcalPer100g:    in.KcalPer100g,
-		ProteinPer100g: in.ProteinPer100g,
-		CarbsPer100g:   in.CarbsPer100g,
-		FatPer100g:     in.FatPer100g,
+		MealID:          mealID,
+		FoodID:          in.FoodID,
+		FoodVersion:     in.FoodVersion,
+		RecipeID:        in.RecipeID,
+		WeightG:         in.WeightG,
+		Servings:        in.Servings,
+		CookedWeightG:   in.CookedWeightG,
+		CookingMethodID: in.CookingMethodID,
+		WaterChangeG:    in.WaterChangeG,
+		KcalPer100g:     in.KcalPer100g,
+		ProteinPer100g:  in.ProteinPer100g,
+		CarbsPer100g:    in.CarbsPer100g,
+		FatPer100g:      in.FatPer100g,
 	}
 	if err := r.db.WithContext(ctx).Create(&item).Error; err != nil {
 		return mealitem.MealItem{}, err
@@ -213,14 +255,19 @@ func (r *MealRepository) AddItemForUser(ctx context.Context, userID, mealID uint
 	}
 
 	item := mealitem.MealItem{
-		MealID:         mealID,
-		FoodID:         in.FoodID,
-		RecipeID:       in.RecipeID,
-		WeightG:        in.WeightG,
-		KcalPer100g:    in.KcalPer100g,
-		ProteinPer100g: in.ProteinPer100g,
-		CarbsPer100g:   in.CarbsPer100g,
-		FatPer100g:     in.FatPer100g,
+		MealID:          mealID,
+		FoodID:          in.FoodID,
+		FoodVersion:     in.FoodVersion,
+		RecipeID:        in.RecipeID,
+		WeightG:         in.WeightG,
+		Servings:        in.Servings,
+		CookedWeightG:   in.CookedWeightG,
+		CookingMethodID: in.CookingMethodID,
+		WaterChangeG:    in.WaterChangeG,
+		KcalPer100g:     in.KcalPer100g,
+		ProteinPer100g:  in.ProteinPer100g,
+		CarbsPer100g:    in.CarbsPer100g,
+		FatPer100g:      in.FatPer100g,
 	}
 	if err := r.db.WithContext(ctx).Create(&item).Error; err != nil {
 		return mealitem.MealItem{}, err
@@ -262,6 +309,19 @@ func (r *MealRepository) UpdateForUser(ctx context.Context, userID, mealID uint,
 	return r.GetByIDForUser(ctx, userID, mealID)
 }
 
+// SetImageForUser records a newly stored photo of the meal, or clears it
+// when img is nil.
+func (r *MealRepository) SetImageForUser(ctx context.Context, userID, mealID uint, img *Image) (meal.Meal, error) {
+	res := r.db.WithContext(ctx).Model(&meal.Meal{}).Where("id = ? AND user_id = ?", mealID, userID).Updates(imageChanges(img))
+	if res.Error != nil {
+		return meal.Meal{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return meal.Meal{}, ErrNotFound
+	}
+	return r.GetByIDForUser(ctx, userID, mealID)
+}
+
 func (r *MealRepository) DeleteForUser(ctx context.Context, userID, mealID uint) error {
 	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", mealID, userID).Delete(&meal.Meal{})
 	if result.Error != nil {
@@ -303,13 +363,18 @@ func (r *MealRepository) UpdateItemForUser(ctx context.Context, userID, mealID,
 		}
 
 		updates := map[string]any{
-			"food_id":          in.FoodID,
-			"recipe_id":        in.RecipeID,
-			"weight_g":         in.WeightG,
-			"kcal_per_100g":    in.KcalPer100g,
-			"protein_per_100g": in.ProteinPer100g,
-			"carbs_per_100g":   in.CarbsPer100g,
-			"fat_per_100g":     in.FatPer100g,
+			"food_id":           in.FoodID,
+			"food_version":      in.FoodVersion,
+			"recipe_id":         in.RecipeID,
+			"weight_g":          in.WeightG,
+			"servings":          in.Servings,
+			"cooked_weight_g":   in.CookedWeightG,
+			"cooking_method_id": in.CookingMethodID,
+			"water_change_g":    in.WaterChangeG,
+			"kcal_per_100g":     in.KcalPer100g,
+			"protein_per_100g":  in.ProteinPer100g,
+			"carbs_per_100g":    in.CarbsPer100g,
+			"fat_per_100g":      in.FatPer100g,
 		}
 		if err := tx.Model(&mealitem.MealItem{}).Where("id = ?", itemID).Updates(updates).Error; err != nil {
 			return err
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"goal-bite-api/internal/domain/locale"
+
+	"gorm.io/gorm"
+)
+
+// searchConfigs maps languages to the PostgreSQL text search configuration
+// that stems them. Other languages use simple, which only lowercases.
+// Changing an entry only affects names stored afterwards.
+var searchConfigs = map[string]string{
+	"ar": "arabic",
+	"ca": "catalan",
+	"da": "danish",
+	"de": "german",
+	"el": "greek",
+	"en": "english",
+	"es": "spanish",
+	"eu": "basque",
+	"fi": "finnish",
+	"fr": "french",
+	"ga": "irish",
+	"hi": "hindi",
+	"hu": "hungarian",
+	"hy": "armenian",
+	"id": "indonesian",
+	"it": "italian",
+	"lt": "lithuanian",
+	"nb": "norwegian",
+	"ne": "nepali",
+	"nl": "dutch",
+	"nn": "norwegian",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sr": "serbian",
+	"sv": "swedish",
+	"ta": "tamil",
+	"tr": "turkish",
+	"yi": "yiddish",
+}
+
+func searchConfig(language string) string {
+	if config, ok := searchConfigs[language]; ok {
+		return config
+	}
+	return "simple"
+}
+
+// nameTable describes where the translated names of foods or recipes live:
+// the names table, its owner column and the owner table.
+type nameTable struct {
+	table string
+	owner string
+	of    string
+}
+
+var (
+	foodNames   = nameTable{table: "food_names", owner: "food_id", of: "foods"}
+	recipeNames = nameTable{table: "recipe_names", owner: "recipe_id", of: "recipes"}
+)
+
+type nameRow struct {
+	OwnerID uint
+	Locale  string
+	Name    string
+	Synonym bool
+}
+
+// replace swaps all names of ownerID for translations. Each translation is
+// stored as its name plus one row per synonym.
+func (n nameTable) replace(tx *gorm.DB, ownerID uint, translations []locale.Translation) error {
+	if err := tx.Exec("DELETE FROM "+n.table+" WHERE "+n.owner+" = ?", ownerID).Error; err != nil {
+		return err
+	}
+	var values []string
+	var args []any
+	add := func(language, name string, synonym bool) {
+		values = append(values, "(?, ?, ?, ?, to_tsvector(?::regconfig, ?))")
+		args = append(args, ownerID, language, name, synonym, searchConfig(language), name)
+	}
+	for _, t := range translations {
+		add(t.Locale, t.Name, false)
+		for _, synonym := range t.Synonyms {
+			add(t.Locale, synonym, true)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return tx.Exec("INSERT INTO "+n.table+" ("+n.owner+", locale, name, synonym, search) VALUES "+strings.Join(values, ", "), args...).Error
+}
+
+// load returns the translations of each owner, sorted by locale. Owners
+// without names get an empty list.
+func (n nameTable) load(ctx context.Context, db *gorm.DB, ownerIDs []uint) (map[uint][]locale.Translation, error) {
+	out := make(map[uint][]locale.Translation, len(ownerIDs))
+	if len(ownerIDs) == 0 {
+		return out, nil
+	}
+	var rows []nameRow
+	err := db.WithContext(ctx).
+		Table(n.table).
+		Select(n.owner+" AS owner_id, locale, name, synonym").
+		Where(n.owner+" IN ?", ownerIDs).
+		Order(n.owner + ", locale, synonym, id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ownerIDs {
+		out[id] = []locale.Translation{}
+	}
+	for _, row := range rows {
+		list := out[row.OwnerID]
+		// Rows are ordered so a language's name comes before its synonyms.
+		if !row.Synonym {
+			out[row.OwnerID] = append(list, locale.Translation{Locale: row.Locale, Name: row.Name, Synonyms: []string{}})
+			continue
+		}
+		if last := len(list) - 1; last >= 0 && list[last].Locale == row.Locale {
+			list[last].Synonyms = append(list[last].Synonyms, row.Name)
+		}
+	}
+	return out, nil
+}
+
+// matching returns a condition on the owner table that matches term against
+// the base name, and against the names and synonyms in languages. Those are
+// compared as prefixes with each language's stemming, so in German "apfel"
+// finds both "Äpfel" and "Apfelmus". A plain substring match keeps partial
+// input working for languages without stemming.
+func (n nameTable) matching(term string, languages []string) (string, []any) {
+	condition := n.of + ".name ILIKE ?"
+	args := []any{"%" + term + "%"}
+	if len(languages) == 0 {
+		return condition, args
+	}
+
+	query := prefixQuery(term)
+	perLanguage := make([]string, 0, len(languages))
+	for _, language := range languages {
+		if query == "" {
+			perLanguage = append(perLanguage, "(n.locale = ? AND n.name ILIKE ?)")
+			args = append(args, language, "%"+term+"%")
+			continue
+		}
+		perLanguage = append(perLanguage, "(n.locale = ? AND (n.search @@ to_tsquery(?::regconfig, ?) OR n.name ILIKE ?))")
+		args = append(args, language, searchConfig(language), query, "%"+term+"%")
+	}
+	condition = "(" + condition + " OR EXISTS (SELECT 1 FROM " + n.table + " n WHERE n." + n.owner + " = " + n.of + ".id AND (" +
+		strings.Join(perLanguage, " OR ") + ")))"
+	return condition, args
+}
+
+// prefixQuery turns free text into a tsquery matching every word as a
+// prefix. Punctuation is dropped, so the result is always valid tsquery
+// syntax; it is empty when term has no words.
+func prefixQuery(term string) string {
+	words := strings.FieldsFunc(term, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
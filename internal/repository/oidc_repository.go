@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OIDCIdentity links an external provider subject to a local user.
+type OIDCIdentity struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"column:user_id"`
+	Provider  string    `gorm:"column:provider"`
+	Subject   string    `gorm:"column:subject"`
+	Email     string    `gorm:"column:email"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (OIDCIdentity) TableName() string {
+	return "oidc_identities"
+}
+
+// OIDCLoginState is the server-side half of an in-flight authorization
+// request: the PKCE verifier and nonce bound to a state value.
+type OIDCLoginState struct {
+	ID           uint      `gorm:"primaryKey"`
+	StateHash    string    `gorm:"column:state_hash"`
+	Provider     string    `gorm:"column:provider"`
+	CodeVerifier string    `gorm:"column:code_verifier"`
+	Nonce        string    `gorm:"column:nonce"`
+	ExpiresAt    time.Time `gorm:"column:expires_at"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+type OIDCIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCIdentityRepository(database *gorm.DB) *OIDCIdentityRepository {
+	return &OIDCIdentityRepository{db: database}
+}
+
+type CreateOIDCIdentityInput struct {
+	UserID   uint
+	Provider string
+	Subject  string
+	Email    string
+}
+
+// Create links a provider subject to a user, or returns ErrConflict when
+// the subject is linked already.
+func (r *OIDCIdentityRepository) Create(ctx context.Context, in CreateOIDCIdentityInput) (OIDCIdentity, error) {
+	value := OIDCIdentity{
+		UserID:   in.UserID,
+		Provider: in.Provider,
+		Subject:  in.Subject,
+		Email:    in.Email,
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "provider"}, {Name: "subject"}}, DoNothing: true}).
+		Create(&value)
+	if result.Error != nil {
+		return OIDCIdentity{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return OIDCIdentity{}, ErrConflict
+	}
+	return value, nil
+}
+
+func (r *OIDCIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (OIDCIdentity, error) {
+	var value OIDCIdentity
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&value).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return OIDCIdentity{}, ErrNotFound
+	}
+	if err != nil {
+		return OIDCIdentity{}, err
+	}
+	return value, nil
+}
+
+type OIDCLoginStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCLoginStateRepository(database *gorm.DB) *OIDCLoginStateRepository {
+	return &OIDCLoginStateRepository{db: database}
+}
+
+type CreateOIDCLoginStateInput struct {
+	StateHash    string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+}
+
+func (r *OIDCLoginStateRepository) Create(ctx context.Context, in CreateOIDCLoginStateInput) (OIDCLoginState, error) {
+	value := OIDCLoginState{
+		StateHash:    in.StateHash,
+		Provider:     in.Provider,
+		CodeVerifier: in.CodeVerifier,
+		Nonce:        in.Nonce,
+		ExpiresAt:    in.ExpiresAt.UTC(),
+	}
+	if err := r.db.WithContext(ctx).Create(&value).Error; err != nil {
+		return OIDCLoginState{}, err
+	}
+	return value, nil
+}
+
+// Consume deletes and returns an unexpired state issued for provider.
+func (r *OIDCLoginStateRepository) Consume(ctx context.Context, stateHash, provider string, now time.Time) (OIDCLoginState, error) {
+	var values []OIDCLoginState
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ? AND provider = ? AND expires_at > ?", stateHash, provider, now.UTC()).
+		Delete(&values)
+	if result.Error != nil {
+		return OIDCLoginState{}, result.Error
+	}
+	if result.RowsAffected == 0 || len(values) == 0 {
+		return OIDCLoginState{}, ErrNotFound
+	}
+	return values[0], nil
+}
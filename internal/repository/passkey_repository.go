@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"goal-bite-api/internal/domain/passkey"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasskeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPasskeyRepository(database *gorm.DB) *PasskeyRepository {
+	return &PasskeyRepository{db: database}
+}
+
+func (r *PasskeyRepository) Create(ctx context.Context, value passkey.Passkey) (passkey.Passkey, error) {
+	if value.Transports == nil {
+		value.Transports = []string{}
+	}
+	if err := r.db.WithContext(ctx).Create(&value).Error; err != nil {
+		return passkey.Passkey{}, err
+	}
+	return value, nil
+}
+
+func (r *PasskeyRepository) GetByID(ctx context.Context, id uint) (passkey.Passkey, error) {
+	var value passkey.Passkey
+	err := r.db.WithContext(ctx).First(&value, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return passkey.Passkey{}, ErrNotFound
+	}
+	if err != nil {
+		return passkey.Passkey{}, err
+	}
+	return value, nil
+}
+
+func (r *PasskeyRepository) GetByCredentialID(ctx context.Context, credentialID string) (passkey.Passkey, error) {
+	var value passkey.Passkey
+	err := r.db.WithContext(ctx).Where("credential_id = ?", credentialID).First(&value).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return passkey.Passkey{}, ErrNotFound
+	}
+	if err != nil {
+		return passkey.Passkey{}, err
+	}
+	return value, nil
+}
+
+func (r *PasskeyRepository) ListByUserID(ctx context.Context, userID uint) ([]passkey.Passkey, error) {
+	var values []passkey.Passkey
+	err := r.db.WithContext(ctx).
+		Select("id, user_id, name, credential_id, sign_count, transports, last_used_at, created_at, updated_at").
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&values).Error
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// RecordUse stores the authenticator counter from a successful assertion.
+// The update only applies while the stored counter is still the one the
+// assertion was verified against, so concurrent replays cannot both succeed.
+func (r *PasskeyRepository) RecordUse(ctx context.Context, id uint, previousCount, signCount uint32, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&passkey.Passkey{}).
+		Where("id = ? AND sign_count = ?", id, previousCount).
+		Updates(map[string]any{
+			"sign_count":   signCount,
+			"last_used_at": at.UTC(),
+			"updated_at":   at.UTC(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PasskeyRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&passkey.Passkey{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type WebAuthnChallenge struct {
+	ID        uint      `gorm:"primaryKey"`
+	Challenge string    `gorm:"column:challenge"`
+	Purpose   string    `gorm:"column:purpose"`
+	UserID    *uint     `gorm:"column:user_id"`
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (WebAuthnChallenge) TableName() string {
+	return "webauthn_challenges"
+}
+
+type WebAuthnChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewWebAuthnChallengeRepository(database *gorm.DB) *WebAuthnChallengeRepository {
+	return &WebAuthnChallengeRepository{db: database}
+}
+
+type CreateWebAuthnChallengeInput struct {
+	Challenge string
+	Purpose   string
+	UserID    *uint
+	ExpiresAt time.Time
+}
+
+func (r *WebAuthnChallengeRepository) Create(ctx context.Context, in CreateWebAuthnChallengeInput) (WebAuthnChallenge, error) {
+	value := WebAuthnChallenge{
+		Challenge: in.Challenge,
+		Purpose:   in.Purpose,
+		UserID:    in.UserID,
+		ExpiresAt: in.ExpiresAt.UTC(),
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ?", time.Now().UTC()).Delete(&WebAuthnChallenge{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&value).Error
+	})
+	if err != nil {
+		return WebAuthnChallenge{}, err
+	}
+	return value, nil
+}
+
+// Consume deletes and returns an unexpired challenge issued for purpose.
+func (r *WebAuthnChallengeRepository) Consume(ctx context.Context, challenge, purpose string, now time.Time) (WebAuthnChallenge, error) {
+	var values []WebAuthnChallenge
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("challenge = ? AND purpose = ? AND expires_at > ?", challenge, purpose, now.UTC()).
+		Delete(&values)
+	if result.Error != nil {
+		return WebAuthnChallenge{}, result.Error
+	}
+	if result.RowsAffected == 0 || len(values) == 0 {
+		return WebAuthnChallenge{}, ErrNotFound
+	}
+	return values[0], nil
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"goal-bite-api/internal/domain/dietary"
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/domain/recipe"
 	"goal-bite-api/internal/domain/recipeingredient"
 
@@ -15,33 +20,103 @@ type RecipeRepository struct {
 	db *gorm.DB
 }
 
+// RecipeIngredientInput is a food or a sub-recipe; exactly one of FoodID and
+// SubRecipeID is set.
 type RecipeIngredientInput struct {
-	FoodID     uint
-	RawWeightG float64
-	Position   *int
+	FoodID          *uint
+	SubRecipeID     *uint
+	FoodVersion     *int
+	RawWeightG      float64
+	Position        *int
+	Note            string
+	CookingMethodID *uint
 }
 
 type RecipeCreate struct {
 	UserID         uint
+	ForkedFromID   *uint
 	Name           string
 	YieldWeightG   float64
+	YieldEstimated bool
+	Servings       int
+	ServingName    string
+	Description    string
+	PrepTimeMin    int
+	CookTimeMin    int
+	Tags           []string
+	Instructions   []string
 	KcalPer100g    float64
 	ProteinPer100g float64
 	CarbsPer100g   float64
 	FatPer100g     float64
 	Ingredients    []RecipeIngredientInput
+	Translations   []locale.Translation
 }
 
 type RecipeUpdate struct {
 	Name           *string
 	YieldWeightG   *float64
+	YieldEstimated *bool
+	Servings       *int
+	ServingName    *string
+	Description    *string
+	PrepTimeMin    *int
+	CookTimeMin    *int
+	Tags           *[]string
+	Instructions   *[]string
 	KcalPer100g    *float64
 	ProteinPer100g *float64
 	CarbsPer100g   *float64
 	FatPer100g     *float64
 	Ingredients    *[]RecipeIngredientInput
+	// Translations replaces all translated names.
+	Translations *[]locale.Translation
+	Stale        *bool
 }
 
+// RecipeListQuery selects recipes for lists and search. Query matches names,
+// including translated names and synonyms in Languages. Recipes with an
+// ingredient declaring any of AvoidAllergens or lacking one of
+// RequiredDietFlags are left out. A recipe must carry every tag in Tags, and
+// with MaxTotalTimeMin set, state prep or cook times adding up to at most it.
+type RecipeListQuery struct {
+	Query             string
+	Languages         []string
+	Tags              []string
+	MaxTotalTimeMin   int
+	IncludeArchived   bool
+	AvoidAllergens    []string
+	RequiredDietFlags []string
+	Limit             int
+	Offset            int
+}
+
+// recipeFoods selects the ingredient foods of recipes.id, including those of
+// its sub-recipes at any depth. UNION stops the walk at recipes already seen.
+const recipeFoods = `WITH RECURSIVE tree(id) AS (
+    SELECT recipes.id
+    UNION
+    SELECT ri.sub_recipe_id FROM recipe_ingredients ri JOIN tree ON ri.recipe_id = tree.id
+    WHERE ri.sub_recipe_id IS NOT NULL)
+SELECT f.* FROM tree
+JOIN recipe_ingredients ri ON ri.recipe_id = tree.id
+JOIN foods f ON f.id = ri.food_id`
+
+// recipeColumns adds the allergens and diet flags a recipe inherits from its
+// ingredient foods, through sub-recipes too: every allergen of any
+// ingredient, and the diet flags all ingredients carry.
+var recipeColumns = fmt.Sprintf(`recipes.*,
+(SELECT COALESCE(jsonb_agg(DISTINCT a.value ORDER BY a.value), '[]'::jsonb)
+    FROM (%[1]s) f
+    CROSS JOIN LATERAL jsonb_array_elements_text(f.allergens) AS a(value)) AS allergens,
+(SELECT COALESCE(jsonb_agg(d.flag ORDER BY d.flag), '[]'::jsonb)
+    FROM jsonb_array_elements_text('%[2]s'::jsonb) AS d(flag)
+    WHERE EXISTS (%[1]s)
+    AND NOT EXISTS (
+        SELECT 1 FROM (%[1]s) f
+        WHERE NOT f.diet_flags @> jsonb_build_array(d.flag))) AS diet_flags`,
+	recipeFoods, jsonList(dietary.DietFlags))
+
 func NewRecipeRepository(database *gorm.DB) *RecipeRepository {
 	return &RecipeRepository{db: database}
 }
@@ -51,13 +126,28 @@ func (r *RecipeRepository) Create(ctx context.Context, in RecipeCreate) (recipe.
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		value := recipe.Recipe{
 			UserID:         in.UserID,
+			ForkedFromID:   in.ForkedFromID,
 			Name:           in.Name,
 			YieldWeightG:   in.YieldWeightG,
+			YieldEstimated: in.YieldEstimated,
+			Servings:       in.Servings,
+			ServingName:    in.ServingName,
+			Description:    in.Description,
+			PrepTimeMin:    in.PrepTimeMin,
+			CookTimeMin:    in.CookTimeMin,
+			Tags:           in.Tags,
+			Instructions:   in.Instructions,
 			KcalPer100g:    in.KcalPer100g,
 			ProteinPer100g: in.ProteinPer100g,
 			CarbsPer100g:   in.CarbsPer100g,
 			FatPer100g:     in.FatPer100g,
 		}
+		if value.Tags == nil {
+			value.Tags = []string{}
+		}
+		if value.Instructions == nil {
+			value.Instructions = []string{}
+		}
 		if err := tx.Create(&value).Error; err != nil {
 			return err
 		}
@@ -65,10 +155,14 @@ func (r *RecipeRepository) Create(ctx context.Context, in RecipeCreate) (recipe.
 		ingredients := make([]recipeingredient.RecipeIngredient, 0, len(in.Ingredients))
 		for _, item := range in.Ingredients {
 			ingredients = append(ingredients, recipeingredient.RecipeIngredient{
-				RecipeID:   value.ID,
-				FoodID:     item.FoodID,
-				RawWeightG: item.RawWeightG,
-				Position:   item.Position,
+				RecipeID:        value.ID,
+				FoodID:          item.FoodID,
+				SubRecipeID:     item.SubRecipeID,
+				FoodVersion:     item.FoodVersion,
+				RawWeightG:      item.RawWeightG,
+				Position:        item.Position,
+				Note:            item.Note,
+				CookingMethodID: item.CookingMethodID,
 			})
 		}
 		if len(ingredients) > 0 {
@@ -76,8 +170,10 @@ func (r *RecipeRepository) Create(ctx context.Context, in RecipeCreate) (recipe.
 				return err
 			}
 		}
+		if err := recipeNames.replace(tx, value.ID, in.Translations); err != nil {
+			return err
+		}
 
-		value.Ingredients = ingredients
 		out = value
 		return nil
 	})
@@ -85,12 +181,12 @@ func (r *RecipeRepository) Create(ctx context.Context, in RecipeCreate) (recipe.
 		return recipe.Recipe{}, err
 	}
 
-	return out, nil
+	return r.GetByID(ctx, out.ID)
 }
 
 func (r *RecipeRepository) GetByID(ctx context.Context, id uint) (recipe.Recipe, error) {
 	var out recipe.Recipe
-	if err := r.db.WithContext(ctx).First(&out, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Select(recipeColumns).First(&out, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return recipe.Recipe{}, ErrNotFound
 		}
@@ -106,33 +202,74 @@ func (r *RecipeRepository) GetByID(ctx context.Context, id uint) (recipe.Recipe,
 	}
 	out.Ingredients = ingredients
 
+	names, err := recipeNames.load(ctx, r.db, []uint{id})
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	out.Translations = names[id]
+	out.SetPortions()
+
 	return out, nil
 }
 
-func (r *RecipeRepository) List(ctx context.Context, limit, offset int) ([]recipe.Recipe, error) {
+func (r *RecipeRepository) List(ctx context.Context, q RecipeListQuery) ([]recipe.Recipe, error) {
 	var out []recipe.Recipe
 	err := r.db.WithContext(ctx).
-		Select("id, user_id, name, yield_weight_g, kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, created_at, updated_at").
+		Select(recipeColumns).
+		Scopes(matchingRecipes(q)).
 		Order("id ASC").
-		Limit(limit).
-		Offset(offset).
+		Limit(q.Limit).
+		Offset(q.Offset).
 		Find(&out).Error
 	if err != nil {
 		return nil, err
 	}
+
+	ids := make([]uint, 0, len(out))
+	for _, value := range out {
+		ids = append(ids, value.ID)
+	}
+	names, err := recipeNames.load(ctx, r.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Translations = names[out[i].ID]
+		out[i].SetPortions()
+	}
 	return out, nil
 }
 
-func (r *RecipeRepository) SearchByName(ctx context.Context, query string, limit, offset int) ([]recipe.Recipe, error) {
-	q := strings.TrimSpace(query)
-	if q == "" {
-		return r.List(ctx, limit, offset)
+// ListByIDs returns the recipes with the given IDs without their
+// ingredients, archived ones included, in no particular order. Unknown IDs
+// are skipped.
+func (r *RecipeRepository) ListByIDs(ctx context.Context, ids []uint) ([]recipe.Recipe, error) {
+	var out []recipe.Recipe
+	if len(ids) == 0 {
+		return out, nil
+	}
+	if err := r.db.WithContext(ctx).Select(recipeColumns).Where("recipes.id IN ?", ids).Find(&out).Error; err != nil {
+		return nil, err
+	}
+	names, err := recipeNames.load(ctx, r.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Translations = names[out[i].ID]
+		out[i].SetPortions()
 	}
+	return out, nil
+}
 
+// ListForks returns the active recipes copied from id with their
+// ingredients, oldest first.
+func (r *RecipeRepository) ListForks(ctx context.Context, id uint, limit, offset int) ([]recipe.Recipe, error) {
 	var out []recipe.Recipe
 	err := r.db.WithContext(ctx).
-		Select("id, user_id, name, yield_weight_g, kcal_per_100g, protein_per_100g, carbs_per_100g, fat_per_100g, created_at, updated_at").
-		Where("name ILIKE ?", "%"+q+"%").
+		Select(recipeColumns).
+		Where("recipes.forked_from_id = ?", id).
+		Scopes(notArchived(false)).
 		Order("id ASC").
 		Limit(limit).
 		Offset(offset).
@@ -140,6 +277,34 @@ func (r *RecipeRepository) SearchByName(ctx context.Context, query string, limit
 	if err != nil {
 		return nil, err
 	}
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	ids := make([]uint, 0, len(out))
+	for _, value := range out {
+		ids = append(ids, value.ID)
+	}
+	var ingredients []recipeingredient.RecipeIngredient
+	if err := r.db.WithContext(ctx).
+		Where("recipe_id IN ?", ids).
+		Order("position ASC NULLS LAST, id ASC").
+		Find(&ingredients).Error; err != nil {
+		return nil, err
+	}
+	byRecipe := make(map[uint][]recipeingredient.RecipeIngredient, len(out))
+	for _, item := range ingredients {
+		byRecipe[item.RecipeID] = append(byRecipe[item.RecipeID], item)
+	}
+	names, err := recipeNames.load(ctx, r.db, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Ingredients = byRecipe[out[i].ID]
+		out[i].Translations = names[out[i].ID]
+		out[i].SetPortions()
+	}
 	return out, nil
 }
 
@@ -160,6 +325,30 @@ func (r *RecipeRepository) Update(ctx context.Context, id uint, in RecipeUpdate)
 		if in.YieldWeightG != nil {
 			changes["yield_weight_g"] = *in.YieldWeightG
 		}
+		if in.YieldEstimated != nil {
+			changes["yield_estimated"] = *in.YieldEstimated
+		}
+		if in.Servings != nil {
+			changes["servings"] = *in.Servings
+		}
+		if in.ServingName != nil {
+			changes["serving_name"] = *in.ServingName
+		}
+		if in.Description != nil {
+			changes["description"] = *in.Description
+		}
+		if in.PrepTimeMin != nil {
+			changes["prep_time_min"] = *in.PrepTimeMin
+		}
+		if in.CookTimeMin != nil {
+			changes["cook_time_min"] = *in.CookTimeMin
+		}
+		if in.Tags != nil {
+			changes["tags"] = jsonList(*in.Tags)
+		}
+		if in.Instructions != nil {
+			changes["instructions"] = jsonList(*in.Instructions)
+		}
 		if in.KcalPer100g != nil {
 			changes["kcal_per_100g"] = *in.KcalPer100g
 		}
@@ -172,6 +361,9 @@ func (r *RecipeRepository) Update(ctx context.Context, id uint, in RecipeUpdate)
 		if in.FatPer100g != nil {
 			changes["fat_per_100g"] = *in.FatPer100g
 		}
+		if in.Stale != nil {
+			changes["stale"] = *in.Stale
+		}
 
 		if len(changes) > 0 {
 			if err := tx.Model(&out).Updates(changes).Error; err != nil {
@@ -187,10 +379,14 @@ func (r *RecipeRepository) Update(ctx context.Context, id uint, in RecipeUpdate)
 			ingredients := make([]recipeingredient.RecipeIngredient, 0, len(*in.Ingredients))
 			for _, item := range *in.Ingredients {
 				ingredients = append(ingredients, recipeingredient.RecipeIngredient{
-					RecipeID:   id,
-					FoodID:     item.FoodID,
-					RawWeightG: item.RawWeightG,
-					Position:   item.Position,
+					RecipeID:        id,
+					FoodID:          item.FoodID,
+					SubRecipeID:     item.SubRecipeID,
+					FoodVersion:     item.FoodVersion,
+					RawWeightG:      item.RawWeightG,
+					Position:        item.Position,
+					Note:            item.Note,
+					CookingMethodID: item.CookingMethodID,
 				})
 			}
 			if len(ingredients) > 0 {
@@ -199,6 +395,11 @@ func (r *RecipeRepository) Update(ctx context.Context, id uint, in RecipeUpdate)
 				}
 			}
 		}
+		if in.Translations != nil {
+			if err := recipeNames.replace(tx, id, *in.Translations); err != nil {
+				return err
+			}
+		}
 
 		return nil
 	})
@@ -212,8 +413,25 @@ func (r *RecipeRepository) Update(ctx context.Context, id uint, in RecipeUpdate)
 	return r.GetByID(ctx, id)
 }
 
-func (r *RecipeRepository) Delete(ctx context.Context, id uint) error {
-	res := r.db.WithContext(ctx).Delete(&recipe.Recipe{}, id)
+// SetImage records a newly stored image, or clears it when img is nil.
+func (r *RecipeRepository) SetImage(ctx context.Context, id uint, img *Image) (recipe.Recipe, error) {
+	res := r.db.WithContext(ctx).Model(&recipe.Recipe{}).Where("id = ?", id).Updates(imageChanges(img))
+	if res.Error != nil {
+		return recipe.Recipe{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return recipe.Recipe{}, ErrNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Archive hides the recipe from lists and search. The row is kept because
+// meal items reference it.
+func (r *RecipeRepository) Archive(ctx context.Context, id uint, at time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&recipe.Recipe{}).
+		Where("id = ? AND archived_at IS NULL", id).
+		Update("archived_at", at)
 	if res.Error != nil {
 		return res.Error
 	}
@@ -222,3 +440,124 @@ func (r *RecipeRepository) Delete(ctx context.Context, id uint) error {
 	}
 	return nil
 }
+
+func (r *RecipeRepository) Restore(ctx context.Context, id uint) (recipe.Recipe, error) {
+	res := r.db.WithContext(ctx).
+		Model(&recipe.Recipe{}).
+		Where("id = ?", id).
+		Update("archived_at", nil)
+	if res.Error != nil {
+		return recipe.Recipe{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return recipe.Recipe{}, ErrNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+// MarkStaleByFood flags every recipe using foodID as an ingredient as stale,
+// directly or through sub-recipes, archived ones included, and returns their
+// IDs.
+func (r *RecipeRepository) MarkStaleByFood(ctx context.Context, foodID uint) ([]uint, error) {
+	return r.markStale(ctx, "food_id", foodID)
+}
+
+// MarkStaleByRecipe flags every recipe using id as a sub-recipe as stale,
+// directly or through other sub-recipes, and returns their IDs.
+func (r *RecipeRepository) MarkStaleByRecipe(ctx context.Context, id uint) ([]uint, error) {
+	return r.markStale(ctx, "sub_recipe_id", id)
+}
+
+func (r *RecipeRepository) markStale(ctx context.Context, column string, id uint) ([]uint, error) {
+	return markRecipesStale(r.db.WithContext(ctx), column, id)
+}
+
+// markRecipesStale flags the recipes with an ingredient whose column is id,
+// and every recipe above them, through db so callers can run it in their
+// own transaction.
+func markRecipesStale(db *gorm.DB, column string, id uint) ([]uint, error) {
+	var ids []uint
+	err := db.
+		Raw(`WITH RECURSIVE affected(id) AS (
+    SELECT recipe_id FROM recipe_ingredients WHERE `+column+` = ?
+    UNION
+    SELECT ri.recipe_id FROM recipe_ingredients ri JOIN affected ON ri.sub_recipe_id = affected.id)
+UPDATE recipes SET stale = TRUE
+WHERE id IN (SELECT id FROM affected)
+RETURNING id`, id).
+		Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ParentDepth returns how many levels of recipes use id as a sub-recipe,
+// counting at most limit levels; 0 means no recipe uses it.
+func (r *RecipeRepository) ParentDepth(ctx context.Context, id uint, limit int) (int, error) {
+	var depth int
+	err := r.db.WithContext(ctx).
+		Raw(`WITH RECURSIVE parents(id, depth) AS (
+    SELECT recipe_id, 1 FROM recipe_ingredients WHERE sub_recipe_id = ?
+    UNION
+    SELECT ri.recipe_id, parents.depth + 1 FROM recipe_ingredients ri JOIN parents ON ri.sub_recipe_id = parents.id
+    WHERE parents.depth < ?)
+SELECT COALESCE(MAX(depth), 0) FROM parents`, id, limit).
+		Scan(&depth).Error
+	if err != nil {
+		return 0, err
+	}
+	return depth, nil
+}
+
+// ListStale returns the IDs of up to limit stale recipes above afterID in ID
+// order.
+func (r *RecipeRepository) ListStale(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&recipe.Recipe{}).
+		Where("stale AND id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// matchingRecipes applies the filters of a RecipeListQuery, leaving ordering
+// and pagination to the caller.
+func matchingRecipes(q RecipeListQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if term := strings.TrimSpace(q.Query); term != "" {
+			condition, args := recipeNames.matching(term, q.Languages)
+			db = db.Where(condition, args...)
+		}
+		if len(q.Tags) > 0 {
+			db = db.Where("recipes.tags @> ?::jsonb", jsonList(q.Tags))
+		}
+		if q.MaxTotalTimeMin > 0 {
+			// Recipes without times are left out rather than counted as instant.
+			db = db.Where("recipes.prep_time_min + recipes.cook_time_min BETWEEN 1 AND ?", q.MaxTotalTimeMin)
+		}
+		if len(q.AvoidAllergens) > 0 {
+			db = db.Where(`NOT EXISTS (
+SELECT 1 FROM (`+recipeFoods+`) f
+WHERE jsonb_exists_any(f.allergens, ARRAY[?]))`, q.AvoidAllergens)
+		}
+		if len(q.RequiredDietFlags) > 0 {
+			db = db.Where(`NOT EXISTS (
+SELECT 1 FROM (`+recipeFoods+`) f
+WHERE NOT f.diet_flags @> ?::jsonb)`, jsonList(q.RequiredDietFlags))
+		}
+		return db.Scopes(notArchived(q.IncludeArchived))
+	}
+}
+
+// jsonList encodes a string list for comparison with a JSONB column.
+// Marshalling a []string cannot fail.
+func jsonList(values []string) string {
+	raw, _ := json.Marshal(values)
+	return string(raw)
+}
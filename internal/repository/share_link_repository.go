@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"goal-bite-api/internal/domain/sharelink"
+
+	"gorm.io/gorm"
+)
+
+type ShareLinkRepository struct {
+	db *gorm.DB
+}
+
+// ShareLinkCreate is a new link to either RecipeID or MealID.
+type ShareLinkCreate struct {
+	UserID    uint
+	TokenHash string
+	RecipeID  *uint
+	MealID    *uint
+	ExpiresAt *time.Time
+}
+
+func NewShareLinkRepository(database *gorm.DB) *ShareLinkRepository {
+	return &ShareLinkRepository{db: database}
+}
+
+func (r *ShareLinkRepository) Create(ctx context.Context, in ShareLinkCreate) (sharelink.ShareLink, error) {
+	value := sharelink.ShareLink{
+		UserID:    in.UserID,
+		TokenHash: in.TokenHash,
+		RecipeID:  in.RecipeID,
+		MealID:    in.MealID,
+		ExpiresAt: in.ExpiresAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&value).Error; err != nil {
+		return sharelink.ShareLink{}, err
+	}
+	return value, nil
+}
+
+// ListByUser returns the user's links, revoked and expired ones included,
+// newest first.
+func (r *ShareLinkRepository) ListByUser(ctx context.Context, userID uint, limit, offset int) ([]sharelink.ShareLink, error) {
+	var out []sharelink.ShareLink
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Revoke stops one of the user's links from opening. Links that were
+// already revoked are not found.
+func (r *ShareLinkRepository) Revoke(ctx context.Context, userID, id uint, now time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&sharelink.ShareLink{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", now.UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetActive returns the link with tokenHash as long as it is neither
+// revoked nor expired at now.
+func (r *ShareLinkRepository) GetActive(ctx context.Context, tokenHash string, now time.Time) (sharelink.ShareLink, error) {
+	var out sharelink.ShareLink
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		Where("expires_at IS NULL OR expires_at > ?", now.UTC()).
+		First(&out).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return sharelink.ShareLink{}, ErrNotFound
+	}
+	if err != nil {
+		return sharelink.ShareLink{}, err
+	}
+	return out, nil
+}
+
+// RecordAccess counts an access to the link with id at now.
+func (r *ShareLinkRepository) RecordAccess(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&sharelink.ShareLink{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"access_count":     gorm.Expr("access_count + 1"),
+			"last_accessed_at": now.UTC(),
+		}).Error
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"goal-bite-api/internal/domain/shoppinglist"
+
+	"gorm.io/gorm"
+)
+
+type ShoppingListRepository struct {
+	db *gorm.DB
+}
+
+// ShoppingListCreate is a new list with its items, one per food, in the order
+// they are listed.
+type ShoppingListCreate struct {
+	UserID uint
+	Name   string
+	Items  []ShoppingListItemCreate
+}
+
+type ShoppingListItemCreate struct {
+	FoodID  uint
+	WeightG float64
+}
+
+func NewShoppingListRepository(database *gorm.DB) *ShoppingListRepository {
+	return &ShoppingListRepository{db: database}
+}
+
+func (r *ShoppingListRepository) Create(ctx context.Context, in ShoppingListCreate) (shoppinglist.ShoppingList, error) {
+	var out shoppinglist.ShoppingList
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		out = shoppinglist.ShoppingList{UserID: in.UserID, Name: in.Name}
+		if err := tx.Create(&out).Error; err != nil {
+			return err
+		}
+		if len(in.Items) == 0 {
+			out.Items = []shoppinglist.Item{}
+			return nil
+		}
+
+		items := make([]shoppinglist.Item, 0, len(in.Items))
+		for _, item := range in.Items {
+			items = append(items, shoppinglist.Item{ShoppingListID: out.ID, FoodID: item.FoodID, WeightG: item.WeightG})
+		}
+		if err := tx.Create(&items).Error; err != nil {
+			return err
+		}
+		out.Items = items
+		return nil
+	})
+	if err != nil {
+		return shoppinglist.ShoppingList{}, err
+	}
+	return out, nil
+}
+
+func (r *ShoppingListRepository) GetForUser(ctx context.Context, userID, id uint) (shoppinglist.ShoppingList, error) {
+	var out shoppinglist.ShoppingList
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&out).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return shoppinglist.ShoppingList{}, ErrNotFound
+		}
+		return shoppinglist.ShoppingList{}, err
+	}
+
+	var items []shoppinglist.Item
+	if err := r.db.WithContext(ctx).
+		Where("shopping_list_id = ?", id).
+		Order("id ASC").
+		Find(&items).Error; err != nil {
+		return shoppinglist.ShoppingList{}, err
+	}
+	out.Items = items
+	return out, nil
+}
+
+// ListByUser returns the user's lists without their items, newest first.
+func (r *ShoppingListRepository) ListByUser(ctx context.Context, userID uint, limit, offset int) ([]shoppinglist.ShoppingList, error) {
+	var out []shoppinglist.ShoppingList
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetItemChecked ticks an item of the user's list on or off.
+func (r *ShoppingListRepository) SetItemChecked(ctx context.Context, userID, listID, itemID uint, checked bool) (shoppinglist.Item, error) {
+	res := r.db.WithContext(ctx).
+		Model(&shoppinglist.Item{}).
+		Where("id = ? AND shopping_list_id = ?", itemID, listID).
+		Where("EXISTS (SELECT 1 FROM shopping_lists l WHERE l.id = shopping_list_items.shopping_list_id AND l.user_id = ?)", userID).
+		Update("checked", checked)
+	if res.Error != nil {
+		return shoppinglist.Item{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return shoppinglist.Item{}, ErrNotFound
+	}
+
+	var out shoppinglist.Item
+	if err := r.db.WithContext(ctx).First(&out, itemID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return shoppinglist.Item{}, ErrNotFound
+		}
+		return shoppinglist.Item{}, err
+	}
+	return out, nil
+}
+
+func (r *ShoppingListRepository) DeleteForUser(ctx context.Context, userID, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&shoppinglist.ShoppingList{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -2,30 +2,40 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
 	"goal-bite-api/internal/domain/user"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-var ErrNotFound = errors.New("record not found")
+var (
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is returned when a new record clashes with a stored one
+	// on a unique key, as when two requests create it at once.
+	ErrConflict = errors.New("record already exists")
+)
 
 type UserRepository struct {
 	db *gorm.DB
 }
 
 type UserUpdate struct {
-	Name             *string
-	SexSet           bool
-	Sex              *string
-	BirthDateSet     bool
-	BirthDate        *time.Time
-	HeightCMSet      bool
-	HeightCM         *float64
-	ActivityLevelSet bool
-	ActivityLevel    *string
+	Name              *string
+	SexSet            bool
+	Sex               *string
+	BirthDateSet      bool
+	BirthDate         *time.Time
+	HeightCMSet       bool
+	HeightCM          *float64
+	ActivityLevelSet  bool
+	ActivityLevel     *string
+	AvoidAllergens    *[]string
+	RequiredDietFlags *[]string
+	Languages         *[]string
 }
 
 func NewUserRepository(database *gorm.DB) *UserRepository {
@@ -57,19 +67,28 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (user.Use
 	return u, nil
 }
 
+// Create stores a new user, or returns ErrConflict when one with the same
+// email exists.
 func (r *UserRepository) Create(ctx context.Context, value user.User) (user.User, error) {
-	if err := r.db.WithContext(ctx).Create(&value).Error; err != nil {
-		return user.User{}, err
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "email"}}, DoNothing: true}).
+		Create(&value)
+	if result.Error != nil {
+		return user.User{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user.User{}, ErrConflict
 	}
 	return value, nil
 }
 
-func (r *UserRepository) CreateWithSession(ctx context.Context, value user.User, session CreateAuthSessionInput) (user.User, error) {
+func (r *UserRepository) CreateWithSession(ctx context.Context, value user.User, session CreateAuthSessionInput) (user.User, AuthSession, error) {
+	var record AuthSession
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(&value).Error; err != nil {
 			return err
 		}
-		record := AuthSession{
+		record = AuthSession{
 			UserID:    value.ID,
 			TokenHash: session.TokenHash,
 			ExpiresAt: session.ExpiresAt.UTC(),
@@ -80,9 +99,9 @@ func (r *UserRepository) CreateWithSession(ctx context.Context, value user.User,
 		return nil
 	})
 	if err != nil {
-		return user.User{}, err
+		return user.User{}, AuthSession{}, err
 	}
-	return value, nil
+	return value, record, nil
 }
 
 func (r *UserRepository) Update(ctx context.Context, id uint, updates UserUpdate) (user.User, error) {
@@ -119,6 +138,28 @@ func (r *UserRepository) Update(ctx context.Context, id uint, updates UserUpdate
 		}
 	}
 
+	if updates.AvoidAllergens != nil {
+		allergens, err := json.Marshal(*updates.AvoidAllergens)
+		if err != nil {
+			return user.User{}, err
+		}
+		values["avoid_allergens"] = string(allergens)
+	}
+	if updates.RequiredDietFlags != nil {
+		flags, err := json.Marshal(*updates.RequiredDietFlags)
+		if err != nil {
+			return user.User{}, err
+		}
+		values["required_diet_flags"] = string(flags)
+	}
+	if updates.Languages != nil {
+		languages, err := json.Marshal(*updates.Languages)
+		if err != nil {
+			return user.User{}, err
+		}
+		values["languages"] = string(languages)
+	}
+
 	result := r.db.WithContext(ctx).Model(&user.User{}).Where("id = ?", id).Updates(values)
 	if result.Error != nil {
 		return user.User{}, result.Error
@@ -24,17 +24,19 @@ var (
 	ErrInvalidName          = errors.New("invalid name")
 	ErrInvalidProfile       = errors.New("invalid profile fields")
 	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
+	ErrPasswordCompromised  = errors.New("password found in breach corpus")
 )
 
 type UserAuthStore interface {
 	GetByID(ctx context.Context, id uint) (user.User, error)
 	GetByEmail(ctx context.Context, email string) (user.User, error)
 	Create(ctx context.Context, value user.User) (user.User, error)
-	CreateWithSession(ctx context.Context, value user.User, session repository.CreateAuthSessionInput) (user.User, error)
+	CreateWithSession(ctx context.Context, value user.User, session repository.CreateAuthSessionInput) (user.User, repository.AuthSession, error)
 }
 
+// TokenIssuer signs access tokens bound to an auth session.
 type TokenIssuer interface {
-	Generate(userID uint) (string, error)
+	Generate(userID, sessionID uint) (string, error)
 }
 
 type AuthSessionStore interface {
@@ -42,7 +44,13 @@ type AuthSessionStore interface {
 	GetActiveByTokenHash(ctx context.Context, tokenHash string, now time.Time) (repository.AuthSession, error)
 	RevokeByID(ctx context.Context, id uint, at time.Time) error
 	RevokeByTokenHash(ctx context.Context, tokenHash string, at time.Time) error
-	Rotate(ctx context.Context, in repository.RotateAuthSessionInput) error
+	Rotate(ctx context.Context, in repository.RotateAuthSessionInput) (repository.AuthSession, error)
+}
+
+// BreachedPasswordChecker reports whether a plaintext password is known to
+// have appeared in a public breach.
+type BreachedPasswordChecker interface {
+	IsBreached(password string) (bool, error)
 }
 
 type AuthService struct {
@@ -50,6 +58,7 @@ type AuthService struct {
 	tokens   TokenIssuer
 	sessions AuthSessionStore
 	attempts LoginAttemptTracker
+	breached BreachedPasswordChecker
 }
 
 type RegisterInput struct {
@@ -77,6 +86,12 @@ func NewAuthService(users UserAuthStore, tokens TokenIssuer, sessions AuthSessio
 	return &AuthService{users: users, tokens: tokens, sessions: sessions, attempts: tracker}
 }
 
+// SetBreachedPasswordChecker makes registration reject passwords checker
+// knows from a breach. Without one, passwords are not screened.
+func (s *AuthService) SetBreachedPasswordChecker(checker BreachedPasswordChecker) {
+	s.breached = checker
+}
+
 func (s *AuthService) Register(ctx context.Context, in RegisterInput) (AuthResult, error) {
 	name := strings.TrimSpace(in.Name)
 	if name == "" {
@@ -89,6 +104,9 @@ func (s *AuthService) Register(ctx context.Context, in RegisterInput) (AuthResul
 	if !auth.ValidatePasswordPolicy(in.Password) {
 		return AuthResult{}, ErrInvalidPassword
 	}
+	if err := s.screenPassword(in.Password); err != nil {
+		return AuthResult{}, err
+	}
 	if in.Sex != nil {
 		switch *in.Sex {
 		case "male", "female":
@@ -125,7 +143,7 @@ func (s *AuthService) Register(ctx context.Context, in RegisterInput) (AuthResul
 		return AuthResult{}, err
 	}
 
-	created, err := s.users.CreateWithSession(ctx, user.User{
+	created, session, err := s.users.CreateWithSession(ctx, user.User{
 		Name:          name,
 		Email:         email,
 		Sex:           in.Sex,
@@ -141,7 +159,7 @@ func (s *AuthService) Register(ctx context.Context, in RegisterInput) (AuthResul
 		return AuthResult{}, err
 	}
 
-	token, err := s.tokens.Generate(created.ID)
+	token, err := s.tokens.Generate(created.ID, session.ID)
 	if err != nil {
 		return AuthResult{}, err
 	}
@@ -185,28 +203,7 @@ func (s *AuthService) Login(ctx context.Context, emailRaw, password string) (Aut
 	}
 	s.attempts.Reset(email)
 
-	token, err := s.tokens.Generate(u.ID)
-	if err != nil {
-		return AuthResult{}, err
-	}
-	refreshToken, err := generateRefreshToken()
-	if err != nil {
-		return AuthResult{}, err
-	}
-	if _, err := s.sessions.Create(ctx, repository.CreateAuthSessionInput{
-		UserID:    u.ID,
-		TokenHash: hashRefreshToken(refreshToken),
-		ExpiresAt: time.Now().UTC().Add(30 * 24 * time.Hour),
-	}); err != nil {
-		return AuthResult{}, err
-	}
-	u.PasswordHash = ""
-	return AuthResult{
-		Token:        token,
-		AccessToken:  token,
-		RefreshToken: refreshToken,
-		User:         u,
-	}, nil
+	return issueAuthResult(ctx, s.tokens, s.sessions, u)
 }
 
 func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (AuthResult, error) {
@@ -232,25 +229,27 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (AuthRes
 		return AuthResult{}, err
 	}
 
-	accessToken, err := s.tokens.Generate(session.UserID)
-	if err != nil {
-		return AuthResult{}, err
-	}
 	newRefreshToken, err := generateRefreshToken()
 	if err != nil {
 		return AuthResult{}, err
 	}
 
-	if err := s.sessions.Rotate(ctx, repository.RotateAuthSessionInput{
+	rotated, err := s.sessions.Rotate(ctx, repository.RotateAuthSessionInput{
 		CurrentTokenHash: hashRefreshToken(token),
 		NewTokenHash:     hashRefreshToken(newRefreshToken),
 		UserID:           session.UserID,
 		Now:              now,
 		ExpiresAt:        now.Add(30 * 24 * time.Hour),
-	}); err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			return AuthResult{}, ErrInvalidRefreshToken
-		}
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return AuthResult{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return AuthResult{}, err
+	}
+
+	accessToken, err := s.tokens.Generate(session.UserID, rotated.ID)
+	if err != nil {
 		return AuthResult{}, err
 	}
 
@@ -275,6 +274,50 @@ func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	return err
 }
 
+// issueAuthResult starts a new refresh session for an already authenticated
+// user and returns the access/refresh pair.
+func issueAuthResult(ctx context.Context, tokens TokenIssuer, sessions AuthSessionStore, u user.User) (AuthResult, error) {
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return AuthResult{}, err
+	}
+	session, err := sessions.Create(ctx, repository.CreateAuthSessionInput{
+		UserID:    u.ID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: time.Now().UTC().Add(30 * 24 * time.Hour),
+	})
+	if err != nil {
+		return AuthResult{}, err
+	}
+	token, err := tokens.Generate(u.ID, session.ID)
+	if err != nil {
+		return AuthResult{}, err
+	}
+	u.PasswordHash = ""
+	return AuthResult{
+		Token:        token,
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+		User:         u,
+	}, nil
+}
+
+// screenPassword rejects passwords present in the configured breach corpus.
+// Every path that sets a password must call it.
+func (s *AuthService) screenPassword(password string) error {
+	if s.breached == nil {
+		return nil
+	}
+	breached, err := s.breached.IsBreached(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		return ErrPasswordCompromised
+	}
+	return nil
+}
+
 func generateRefreshToken() (string, error) {
 	buf := make([]byte, 32)
 	if _, err := rand.Read(buf); err != nil {
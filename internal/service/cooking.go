@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"goal-bite-api/internal/domain/cooking"
+	"goal-bite-api/internal/repository"
+)
+
+var (
+	ErrCookingMethodNotFound = errors.New("cooking method not found")
+	ErrInvalidCookedWeight   = errors.New("invalid cooked weight")
+)
+
+// CookingFactors reads the cooking methods and how foods change when cooked
+// by them.
+type CookingFactors interface {
+	ListMethods(ctx context.Context) ([]cooking.Method, error)
+	YieldFactor(ctx context.Context, methodID uint, categoryID *uint) (cooking.Factor, error)
+}
+
+// CookingMethods lists the cooking methods with their yield factors.
+func (s *FoodService) CookingMethods(ctx context.Context) ([]cooking.Method, error) {
+	if s.cooking == nil {
+		return []cooking.Method{}, nil
+	}
+	return s.cooking.ListMethods(ctx)
+}
+
+// yieldFactor returns how a food of categoryID changes when cooked by
+// methodID. An unknown method, or no factors to read, is
+// ErrCookingMethodNotFound.
+func yieldFactor(ctx context.Context, factors CookingFactors, methodID uint, categoryID *uint) (cooking.Factor, error) {
+	if factors == nil || methodID == 0 {
+		return cooking.Factor{}, ErrCookingMethodNotFound
+	}
+	factor, err := factors.YieldFactor(ctx, methodID, categoryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return cooking.Factor{}, ErrCookingMethodNotFound
+	}
+	if err != nil {
+		return cooking.Factor{}, err
+	}
+	return factor, nil
+}
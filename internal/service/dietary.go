@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"goal-bite-api/internal/domain/dietary"
+	"goal-bite-api/internal/domain/user"
+	"goal-bite-api/internal/repository"
+)
+
+// RestrictionReader loads the allergens and diet flags a user declared on
+// their profile. Services without one treat every user as unrestricted.
+type RestrictionReader interface {
+	GetByID(ctx context.Context, id uint) (user.User, error)
+}
+
+func loadRestrictions(ctx context.Context, users RestrictionReader, userID uint) (dietary.Restrictions, error) {
+	if users == nil {
+		return dietary.Restrictions{}, nil
+	}
+	u, err := users.GetByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return dietary.Restrictions{}, nil
+	}
+	if err != nil {
+		return dietary.Restrictions{}, err
+	}
+	return dietary.Restrictions{AvoidAllergens: u.AvoidAllergens, RequiredDietFlags: u.RequiredDietFlags}, nil
+}
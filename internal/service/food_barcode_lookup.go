@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goal-bite-api/internal/catalog"
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/repository"
+)
+
+var ErrBarcodeLookupUnavailable = errors.New("barcode lookup unavailable")
+
+// BarcodeProvider finds products in an external catalog. Name is recorded as
+// the source of imported foods.
+type BarcodeProvider interface {
+	Name() string
+	Lookup(ctx context.Context, barcode string) (catalog.Product, error)
+}
+
+type BarcodeMissCache interface {
+	HasMiss(ctx context.Context, source, barcode string, since time.Time) (bool, error)
+	RecordMiss(ctx context.Context, source, barcode string, at time.Time) error
+}
+
+// BarcodeLookup lets FoodService.GetByBarcode fall back to an external
+// catalog. Misses are cached for MissTTL so unknown barcodes do not hit the
+// catalog on every scan; without Misses or a positive MissTTL nothing is
+// cached.
+type BarcodeLookup struct {
+	Provider BarcodeProvider
+	Misses   BarcodeMissCache
+	MissTTL  time.Duration
+}
+
+// importBarcode asks the catalog for a barcode no visible food has. Found
+// products are stored as public foods owned by the caller, marked with the
+// catalog as their source and filed under the category their catalog
+// categories map to; only admins may edit them afterwards.
+func (s *FoodService) importBarcode(ctx context.Context, userID uint, barcode string) (food.Food, error) {
+	if s.lookup == nil {
+		return food.Food{}, ErrFoodBarcodeNotFound
+	}
+	source := s.lookup.Provider.Name()
+	now := time.Now().UTC()
+	cacheMisses := s.lookup.Misses != nil && s.lookup.MissTTL > 0
+	if cacheMisses {
+		missed, err := s.lookup.Misses.HasMiss(ctx, source, barcode, now.Add(-s.lookup.MissTTL))
+		if err != nil {
+			return food.Food{}, err
+		}
+		if missed {
+			return food.Food{}, ErrFoodBarcodeNotFound
+		}
+	}
+
+	product, err := s.lookup.Provider.Lookup(ctx, barcode)
+	if err == nil && !importable(product) {
+		err = catalog.ErrNotFound
+	}
+	if errors.Is(err, catalog.ErrNotFound) {
+		if cacheMisses {
+			if err := s.lookup.Misses.RecordMiss(ctx, source, barcode, now); err != nil {
+				return food.Food{}, err
+			}
+		}
+		return food.Food{}, ErrFoodBarcodeNotFound
+	}
+	if err != nil {
+		return food.Food{}, fmt.Errorf("%w: %v", ErrBarcodeLookupUnavailable, err)
+	}
+
+	value := food.Food{
+		UserID:         userID,
+		Name:           strings.TrimSpace(product.Name),
+		BrandName:      product.BrandName,
+		Barcode:        &barcode,
+		KcalPer100g:    product.KcalPer100g,
+		ProteinPer100g: product.ProteinPer100g,
+		CarbsPer100g:   product.CarbsPer100g,
+		FatPer100g:     product.FatPer100g,
+		Visibility:     food.VisibilityPublic,
+		Source:         source,
+		ImportedAt:     &now,
+	}
+	if ref := strings.TrimSpace(product.Ref); ref != "" {
+		value.SourceRef = &ref
+	}
+	category, err := s.repo.CategoryByAlias(ctx, product.Categories)
+	if err == nil {
+		value.CategoryID = &category.ID
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return food.Food{}, err
+	}
+	created, err := s.repo.Create(ctx, value)
+	if err != nil {
+		// A concurrent scan may have imported the product first.
+		if existing, lookupErr := s.repo.GetByBarcode(ctx, userID, barcode); lookupErr == nil {
+			return existing, nil
+		}
+		return food.Food{}, err
+	}
+	return created, nil
+}
+
+func importable(p catalog.Product) bool {
+	return strings.TrimSpace(p.Name) != "" && !hasNegative(p.KcalPer100g, p.ProteinPer100g, p.CarbsPer100g, p.FatPer100g)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/repository"
+)
+
+// FoodListInput filters food lists and searches. Query matches names and
+// the translated names in Languages, CategoryID includes its subcategories
+// and a food must carry every tag in Tags. ExcludeConflicts drops foods that
+// break the caller's dietary restrictions.
+type FoodListInput struct {
+	Query            string
+	Languages        []string
+	IncludeArchived  bool
+	CategoryID       *uint
+	Tags             []string
+	ExcludeConflicts bool
+	Limit            int
+	Offset           int
+}
+
+// FoodSearchResult is a page of foods with facet counts over every match.
+type FoodSearchResult struct {
+	Items  []food.Food `json:"items"`
+	Facets FoodFacets  `json:"facets"`
+}
+
+type FoodFacets struct {
+	Categories []CategoryFacet `json:"categories"`
+	Tags       []TagFacet      `json:"tags"`
+}
+
+type CategoryFacet struct {
+	CategoryID uint   `json:"category_id"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	Count      int64  `json:"count"`
+}
+
+type TagFacet struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+func (s *FoodService) List(ctx context.Context, userID uint, in FoodListInput) ([]food.Food, error) {
+	q, err := s.listQuery(ctx, userID, in)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, q)
+}
+
+// Search lists foods like List and adds per-category and per-tag counts for
+// all matches, not just the returned page.
+func (s *FoodService) Search(ctx context.Context, userID uint, in FoodListInput) (FoodSearchResult, error) {
+	q, err := s.listQuery(ctx, userID, in)
+	if err != nil {
+		return FoodSearchResult{}, err
+	}
+	items, err := s.repo.List(ctx, q)
+	if err != nil {
+		return FoodSearchResult{}, err
+	}
+	facets, err := s.repo.Facets(ctx, q)
+	if err != nil {
+		return FoodSearchResult{}, err
+	}
+
+	out := FoodSearchResult{
+		Items: items,
+		Facets: FoodFacets{
+			Categories: make([]CategoryFacet, 0, len(facets.Categories)),
+			Tags:       make([]TagFacet, 0, len(facets.Tags)),
+		},
+	}
+	for _, c := range facets.Categories {
+		out.Facets.Categories = append(out.Facets.Categories, CategoryFacet{CategoryID: c.CategoryID, Slug: c.Slug, Name: c.Name, Count: c.Count})
+	}
+	for _, t := range facets.Tags {
+		out.Facets.Tags = append(out.Facets.Tags, TagFacet{Tag: t.Tag, Count: t.Count})
+	}
+	return out, nil
+}
+
+// Categories returns the food taxonomy as a tree.
+func (s *FoodService) Categories(ctx context.Context) ([]food.CategoryNode, error) {
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return food.CategoryTree(categories), nil
+}
+
+func (s *FoodService) listQuery(ctx context.Context, userID uint, in FoodListInput) (repository.FoodListQuery, error) {
+	if !IsValidPagination(in.Limit, in.Offset) {
+		return repository.FoodListQuery{}, ErrInvalidPagination
+	}
+	tags, ok := food.NormalizeTags(in.Tags)
+	if !ok {
+		return repository.FoodListQuery{}, ErrInvalidFoodTags
+	}
+	q := repository.FoodListQuery{
+		UserID:          userID,
+		Query:           strings.TrimSpace(in.Query),
+		Languages:       in.Languages,
+		IncludeArchived: in.IncludeArchived,
+		CategoryID:      in.CategoryID,
+		Tags:            tags,
+		Limit:           in.Limit,
+		Offset:          in.Offset,
+	}
+	if in.ExcludeConflicts {
+		restrictions, err := loadRestrictions(ctx, s.users, userID)
+		if err != nil {
+			return repository.FoodListQuery{}, err
+		}
+		q.AvoidAllergens = restrictions.AvoidAllergens
+		q.RequiredDietFlags = restrictions.RequiredDietFlags
+	}
+	return q, nil
+}
+
+func (s *FoodService) ensureCategory(ctx context.Context, id uint) error {
+	if id == 0 {
+		return ErrFoodCategoryNotFound
+	}
+	_, err := s.repo.GetCategory(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrFoodCategoryNotFound
+	}
+	return err
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/repository"
+)
+
+var (
+	ErrInvalidFoodMerge   = errors.New("invalid food merge")
+	ErrFoodMergeForbidden = errors.New("food merge forbidden")
+)
+
+const (
+	// DuplicateScoreThreshold is the lowest score reported as a duplicate.
+	DuplicateScoreThreshold = 0.6
+	maxDuplicateResults     = 20
+	duplicateCandidateLimit = 200
+	minDuplicateTermLength  = 3
+)
+
+// RecipeRecalculator recomputes recipes after their ingredient foods changed.
+// FoodChanged handles every recipe using a food whose nutrition changed, and
+// RecipeChanged the recipes using a recipe as a sub-recipe.
+type RecipeRecalculator interface {
+	Recalculate(ctx context.Context, id uint) (recipe.Recipe, error)
+	FoodChanged(ctx context.Context, foodID uint) error
+	RecipeChanged(ctx context.Context, id uint) error
+}
+
+// FoodDuplicate is a food that likely describes the same product as another
+// one. Score is between 0 and 1; the other fields show where it came from.
+type FoodDuplicate struct {
+	Food           food.Food `json:"food"`
+	Score          float64   `json:"score"`
+	NameScore      float64   `json:"name_score"`
+	BrandScore     *float64  `json:"brand_score,omitempty"`
+	NutritionScore float64   `json:"nutrition_score"`
+	BarcodeMatch   bool      `json:"barcode_match"`
+}
+
+type FoodMergeResult struct {
+	Survivor food.Food    `json:"survivor"`
+	Merges   []food.Merge `json:"merges"`
+	// RecalculatedRecipeIDs lists recipes whose ingredients moved to the
+	// survivor and were recalculated with its values. The merge leaves the
+	// others stale for the background pass.
+	RecalculatedRecipeIDs []uint `json:"recalculated_recipe_ids"`
+}
+
+// FindDuplicates scores foods visible to userID against the food id and
+// returns likely duplicates, best first.
+func (s *FoodService) FindDuplicates(ctx context.Context, userID, id uint) ([]FoodDuplicate, error) {
+	target, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var terms []string
+	for _, token := range nameTokens(target.Name) {
+		if len([]rune(token)) >= minDuplicateTermLength {
+			terms = append(terms, token)
+		}
+	}
+	out := []FoodDuplicate{}
+	if len(terms) == 0 && target.Barcode == nil {
+		return out, nil
+	}
+
+	candidates, err := s.repo.ListDuplicateCandidates(ctx, repository.DuplicateCandidateQuery{
+		UserID:    userID,
+		ExcludeID: target.ID,
+		Barcode:   target.Barcode,
+		NameTerms: terms,
+		Limit:     duplicateCandidateLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		d := scoreDuplicate(target, candidate)
+		if d.Score >= DuplicateScoreThreshold {
+			out = append(out, d)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Score > out[j].Score
+	})
+	if len(out) > maxDuplicateResults {
+		out = out[:maxDuplicateResults]
+	}
+	return out, nil
+}
+
+// Merge folds foodIDs into survivorID. Meal items and recipe ingredients move
+// to the survivor in one transaction, the merged foods are archived, and
+// every affected recipe is recalculated. Only admins may merge.
+//
+// A private survivor only accepts private foods of the same owner, so a merge
+// never points other users' logs at a food they cannot see.
+func (s *FoodService) Merge(ctx context.Context, userID, survivorID uint, foodIDs []uint) (FoodMergeResult, error) {
+	if userID == 0 {
+		return FoodMergeResult{}, ErrInvalidUserID
+	}
+	err := s.requireAdmin(ctx, userID)
+	if errors.Is(err, ErrFoodVerificationForbidden) {
+		return FoodMergeResult{}, ErrFoodMergeForbidden
+	}
+	if err != nil {
+		return FoodMergeResult{}, err
+	}
+	if len(foodIDs) == 0 {
+		return FoodMergeResult{}, ErrInvalidFoodMerge
+	}
+	seen := map[uint]bool{survivorID: true}
+	for _, id := range foodIDs {
+		if id == 0 || seen[id] {
+			return FoodMergeResult{}, ErrInvalidFoodMerge
+		}
+		seen[id] = true
+	}
+
+	survivor, err := s.GetByID(ctx, userID, survivorID)
+	if err != nil {
+		return FoodMergeResult{}, err
+	}
+	if survivor.Archived() {
+		return FoodMergeResult{}, ErrFoodArchived
+	}
+	for _, id := range foodIDs {
+		merged, err := s.GetByID(ctx, userID, id)
+		if err != nil {
+			return FoodMergeResult{}, err
+		}
+		if merged.Archived() {
+			return FoodMergeResult{}, ErrFoodArchived
+		}
+		if survivor.Visibility == food.VisibilityPrivate &&
+			(merged.Visibility != food.VisibilityPrivate || merged.UserID != survivor.UserID) {
+			return FoodMergeResult{}, ErrInvalidFoodMerge
+		}
+	}
+
+	merged, err := s.repo.Merge(ctx, repository.FoodMerge{
+		SurvivorID: survivorID,
+		MergedIDs:  foodIDs,
+		MergedBy:   userID,
+		At:         time.Now().UTC(),
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		// One of the foods was archived or removed after validation.
+		return FoodMergeResult{}, ErrFoodArchived
+	}
+	if err != nil {
+		return FoodMergeResult{}, err
+	}
+
+	// The merge has committed and marked the affected recipes stale, so a
+	// recipe that fails to recalculate here is left to RecalculateStale
+	// rather than failing the merge.
+	result := FoodMergeResult{Survivor: survivor, Merges: merged.Merges, RecalculatedRecipeIDs: []uint{}}
+	if s.recipes != nil {
+		for _, recipeID := range merged.RecipeIDs {
+			if _, err := s.recipes.Recalculate(ctx, recipeID); err != nil {
+				continue
+			}
+			result.RecalculatedRecipeIDs = append(result.RecalculatedRecipeIDs, recipeID)
+			_ = s.recipes.RecipeChanged(ctx, recipeID)
+		}
+	}
+	return result, nil
+}
+
+// scoreDuplicate weighs name, nutrition and, when both foods have one, brand
+// similarity. A shared barcode is conclusive; different barcodes halve the
+// score because they usually mean different products.
+func scoreDuplicate(target, candidate food.Food) FoodDuplicate {
+	d := FoodDuplicate{
+		Food:           candidate,
+		NameScore:      nameSimilarity(target.Name, candidate.Name),
+		NutritionScore: nutritionSimilarity(target, candidate),
+	}
+
+	weighted := d.NameScore*0.5 + d.NutritionScore*0.3
+	weights := 0.8
+	if target.BrandName != nil && candidate.BrandName != nil &&
+		strings.TrimSpace(*target.BrandName) != "" && strings.TrimSpace(*candidate.BrandName) != "" {
+		brand := nameSimilarity(*target.BrandName, *candidate.BrandName)
+		d.BrandScore = &brand
+		weighted += brand * 0.2
+		weights += 0.2
+	}
+	d.Score = weighted / weights
+
+	if target.Barcode != nil && candidate.Barcode != nil {
+		if *target.Barcode == *candidate.Barcode {
+			d.BarcodeMatch = true
+			d.Score = 1
+		} else {
+			d.Score /= 2
+		}
+	}
+	d.Score = math.Round(d.Score*1000) / 1000
+	return d
+}
+
+// nameTokens lowercases a name and splits it into unique words, so "Yogurt,
+// greek" and "Greek yogurt" yield the same set.
+func nameTokens(name string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(fields))
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// nameSimilarity takes the better of word overlap and character bigram
+// overlap. The first ignores word order, the second tolerates small
+// additions such as "2%" and typos.
+func nameSimilarity(a, b string) float64 {
+	ta, tb := nameTokens(a), nameTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	return math.Max(jaccard(ta, tb), bigramDice(strings.Join(ta, " "), strings.Join(tb, " ")))
+}
+
+func jaccard(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, v := range a {
+		set[v] = true
+	}
+	shared := 0
+	for _, v := range b {
+		if set[v] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+func bigramDice(a, b string) float64 {
+	ba, bb := bigrams(a), bigrams(b)
+	if len(ba) == 0 || len(bb) == 0 {
+		if a == b {
+			return 1
+		}
+		return 0
+	}
+	counts := make(map[string]int, len(ba))
+	for _, g := range ba {
+		counts[g]++
+	}
+	shared := 0
+	for _, g := range bb {
+		if counts[g] > 0 {
+			counts[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ba)+len(bb))
+}
+
+func bigrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return nil
+	}
+	out := make([]string, 0, len(runes)-1)
+	for i := 0; i < len(runes)-1; i++ {
+		out = append(out, string(runes[i:i+2]))
+	}
+	return out
+}
+
+// nutritionSimilarity averages how close the four per-100g values are, each
+// as 1 - |a-b| / max(a, b).
+func nutritionSimilarity(a, b food.Food) float64 {
+	pairs := [][2]float64{
+		{a.KcalPer100g, b.KcalPer100g},
+		{a.ProteinPer100g, b.ProteinPer100g},
+		{a.CarbsPer100g, b.CarbsPer100g},
+		{a.FatPer100g, b.FatPer100g},
+	}
+	var total float64
+	for _, p := range pairs {
+		high := math.Max(p[0], p[1])
+		if high == 0 {
+			total++
+			continue
+		}
+		total += 1 - math.Abs(p[0]-p[1])/high
+	}
+	return total / float64(len(pairs))
+}
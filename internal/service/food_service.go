@@ -3,36 +3,91 @@ package service
 import (
 	"context"
 	"errors"
+	"math"
 	"strings"
+	"time"
 
+	"goal-bite-api/internal/domain/dietary"
 	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
+	"goal-bite-api/internal/domain/user"
 	"goal-bite-api/internal/repository"
 )
 
 var (
-	ErrFoodNotFound         = errors.New("food not found")
-	ErrFoodBarcodeNotFound  = errors.New("food barcode not found")
-	ErrFoodBarcodeExists    = errors.New("food barcode already exists")
-	ErrFoodForbidden        = errors.New("food forbidden")
-	ErrInvalidFoodBarcode   = errors.New("invalid food barcode")
-	ErrInvalidFoodName      = errors.New("invalid food name")
-	ErrInvalidNutritionData = errors.New("invalid nutrition values")
-	ErrInvalidPagination    = errors.New("invalid pagination")
-	ErrNoFieldsToUpdate     = errors.New("no fields to update")
+	ErrFoodNotFound              = errors.New("food not found")
+	ErrFoodBarcodeNotFound       = errors.New("food barcode not found")
+	ErrFoodBarcodeExists         = errors.New("food barcode already exists")
+	ErrFoodForbidden             = errors.New("food forbidden")
+	ErrInvalidFoodBarcode        = errors.New("invalid food barcode")
+	ErrInvalidFoodName           = errors.New("invalid food name")
+	ErrInvalidNutritionData      = errors.New("invalid nutrition values")
+	ErrInvalidPagination         = errors.New("invalid pagination")
+	ErrNoFieldsToUpdate          = errors.New("no fields to update")
+	ErrInvalidFoodVisibility     = errors.New("invalid food visibility")
+	ErrFoodVerificationForbidden = errors.New("food verification forbidden")
+	ErrFoodVersionNotFound       = errors.New("food version not found")
+	ErrFoodArchived              = errors.New("food archived")
+	ErrInconsistentNutrition     = errors.New("inconsistent nutrition values")
+	ErrInvalidFoodTags           = errors.New("invalid food tags")
+	ErrFoodCategoryNotFound      = errors.New("food category not found")
+	ErrInvalidDietaryInfo        = errors.New("invalid allergens or diet flags")
+	ErrInvalidTranslations       = errors.New("invalid translations")
 )
 
+// NutritionError rejects a food in strict mode. It wraps
+// ErrInconsistentNutrition and carries the failed checks.
+type NutritionError struct {
+	Warnings []food.NutritionWarning
+}
+
+func (e *NutritionError) Error() string {
+	codes := make([]string, 0, len(e.Warnings))
+	for _, w := range e.Warnings {
+		codes = append(codes, w.Code)
+	}
+	return ErrInconsistentNutrition.Error() + ": " + strings.Join(codes, ", ")
+}
+
+func (e *NutritionError) Unwrap() error {
+	return ErrInconsistentNutrition
+}
+
 type FoodStore interface {
 	Create(ctx context.Context, value food.Food) (food.Food, error)
 	GetByID(ctx context.Context, id uint) (food.Food, error)
-	GetByBarcode(ctx context.Context, barcode string) (food.Food, error)
-	List(ctx context.Context, limit, offset int) ([]food.Food, error)
-	SearchByName(ctx context.Context, query string, limit, offset int) ([]food.Food, error)
+	GetByBarcode(ctx context.Context, userID uint, barcode string) (food.Food, error)
+	GetByBarcodeInScope(ctx context.Context, userID uint, visibility food.Visibility, barcode string) (food.Food, error)
+	List(ctx context.Context, q repository.FoodListQuery) ([]food.Food, error)
+	Facets(ctx context.Context, q repository.FoodListQuery) (repository.FoodFacets, error)
+	ListCategories(ctx context.Context) ([]food.Category, error)
+	GetCategory(ctx context.Context, id uint) (food.Category, error)
+	CategoryByAlias(ctx context.Context, aliases []string) (food.Category, error)
 	Update(ctx context.Context, id uint, updates repository.FoodUpdate) (food.Food, error)
-	Delete(ctx context.Context, id uint) error
+	Archive(ctx context.Context, id uint, at time.Time) error
+	Restore(ctx context.Context, id uint) (food.Food, error)
+	ListVersions(ctx context.Context, foodID uint) ([]food.Version, error)
+	GetVersion(ctx context.Context, foodID uint, version int) (food.Version, error)
+	ListDuplicateCandidates(ctx context.Context, q repository.DuplicateCandidateQuery) ([]food.Food, error)
+	Merge(ctx context.Context, in repository.FoodMerge) (repository.FoodMergeResult, error)
+	ListNutritionSuspects(ctx context.Context, userID uint, limit, offset int) ([]food.Food, error)
+	SetImage(ctx context.Context, id uint, img *repository.Image) (food.Food, error)
+}
+
+// FoodAdminReader resolves whether a user may verify foods. Without one no
+// user is an admin.
+type FoodAdminReader interface {
+	GetByID(ctx context.Context, id uint) (user.User, error)
 }
 
 type FoodService struct {
-	repo FoodStore
+	repo    FoodStore
+	admins  FoodAdminReader
+	users   RestrictionReader
+	recipes RecipeRecalculator
+	lookup  *BarcodeLookup
+	images  *ImageUploads
+	cooking CookingFactors
 }
 
 type CreateFoodInput struct {
@@ -43,6 +98,19 @@ type CreateFoodInput struct {
 	ProteinPer100g float64
 	CarbsPer100g   float64
 	FatPer100g     float64
+	AlcoholPer100g float64
+	// Visibility defaults to private.
+	Visibility food.Visibility
+	CategoryID *uint
+	Tags       []string
+	Allergens  []string
+	DietFlags  []string
+	// Translations name the food in other languages, with synonyms search
+	// also matches.
+	Translations []locale.Translation
+	// StrictNutrition rejects values that fail the consistency checks
+	// instead of returning them as warnings.
+	StrictNutrition bool
 }
 
 type UpdateFoodInput struct {
@@ -53,12 +121,62 @@ type UpdateFoodInput struct {
 	ProteinPer100g *float64
 	CarbsPer100g   *float64
 	FatPer100g     *float64
+	AlcoholPer100g *float64
+	Visibility     *food.Visibility
+	// CategoryID moves the food to another category; 0 removes it from its
+	// category.
+	CategoryID *uint
+	// Tags replaces all tags; an empty slice removes them.
+	Tags *[]string
+	// Allergens and DietFlags replace the stored lists the same way.
+	Allergens *[]string
+	DietFlags *[]string
+	// Translations replaces all translated names; an empty slice removes
+	// them.
+	Translations *[]locale.Translation
+	// StrictNutrition rejects nutrition changes that fail the consistency
+	// checks instead of returning them as warnings.
+	StrictNutrition bool
 }
 
 func NewFoodService(repo FoodStore) *FoodService {
 	return &FoodService{repo: repo}
 }
 
+// SetAdminReader lets admins verify and merge foods. Without one, nobody is
+// an admin.
+func (s *FoodService) SetAdminReader(admins FoodAdminReader) { s.admins = admins }
+
+// SetRestrictionReader lets lists leave out foods that break the caller's
+// dietary restrictions.
+func (s *FoodService) SetRestrictionReader(users RestrictionReader) { s.users = users }
+
+// SetRecipeRecalculator makes food changes and merges refresh the recipes
+// that use the food.
+func (s *FoodService) SetRecipeRecalculator(recipes RecipeRecalculator) { s.recipes = recipes }
+
+// SetBarcodeLookup makes barcode lookups fall back to lookup.Provider for
+// barcodes no stored food has.
+func (s *FoodService) SetBarcodeLookup(lookup BarcodeLookup) {
+	if lookup.Provider == nil {
+		s.lookup = nil
+		return
+	}
+	s.lookup = &lookup
+}
+
+// SetImageUploads enables food images.
+func (s *FoodService) SetImageUploads(images ImageUploads) {
+	if images.Store == nil {
+		s.images = nil
+		return
+	}
+	s.images = &images
+}
+
+// SetCookingFactors enables the cooking methods and their yield factors.
+func (s *FoodService) SetCookingFactors(factors CookingFactors) { s.cooking = factors }
+
 func (s *FoodService) Create(ctx context.Context, userID uint, in CreateFoodInput) (food.Food, error) {
 	if userID == 0 {
 		return food.Food{}, ErrInvalidUserID
@@ -74,18 +192,55 @@ func (s *FoodService) Create(ctx context.Context, userID uint, in CreateFoodInpu
 			brandName = &trimmed
 		}
 	}
-	if hasNegative(in.KcalPer100g, in.ProteinPer100g, in.CarbsPer100g, in.FatPer100g) {
+	if hasNegative(in.KcalPer100g, in.ProteinPer100g, in.CarbsPer100g, in.FatPer100g, in.AlcoholPer100g) {
 		return food.Food{}, ErrInvalidNutritionData
 	}
+	warnings := food.CheckNutrition(food.Nutrients{
+		Kcal:    in.KcalPer100g,
+		Protein: in.ProteinPer100g,
+		Carbs:   in.CarbsPer100g,
+		Fat:     in.FatPer100g,
+		Alcohol: in.AlcoholPer100g,
+	})
+	if in.StrictNutrition && len(warnings) > 0 {
+		return food.Food{}, &NutritionError{Warnings: warnings}
+	}
+	visibility := in.Visibility
+	if visibility == "" {
+		visibility = food.VisibilityPrivate
+	}
+	if !visibility.Valid() {
+		return food.Food{}, ErrInvalidFoodVisibility
+	}
+	if visibility == food.VisibilityVerified {
+		if err := s.requireAdmin(ctx, userID); err != nil {
+			return food.Food{}, err
+		}
+	}
+	tags, ok := food.NormalizeTags(in.Tags)
+	if !ok {
+		return food.Food{}, ErrInvalidFoodTags
+	}
+	allergens, dietFlags, err := normalizeDietary(in.Allergens, in.DietFlags)
+	if err != nil {
+		return food.Food{}, err
+	}
+	translations, ok := locale.NormalizeTranslations(in.Translations)
+	if !ok {
+		return food.Food{}, ErrInvalidTranslations
+	}
+	if in.CategoryID != nil {
+		if err := s.ensureCategory(ctx, *in.CategoryID); err != nil {
+			return food.Food{}, err
+		}
+	}
 	var barcode *string
 	if in.Barcode != nil {
 		normalized, ok := normalizeBarcode(*in.Barcode)
 		if !ok {
 			return food.Food{}, ErrInvalidFoodBarcode
 		}
-		if _, err := s.repo.GetByBarcode(ctx, normalized); err == nil {
-			return food.Food{}, ErrFoodBarcodeExists
-		} else if !errors.Is(err, repository.ErrNotFound) {
+		if err := s.ensureBarcodeFree(ctx, userID, 0, visibility, normalized); err != nil {
 			return food.Food{}, err
 		}
 		barcode = &normalized
@@ -100,16 +255,27 @@ func (s *FoodService) Create(ctx context.Context, userID uint, in CreateFoodInpu
 		ProteinPer100g: in.ProteinPer100g,
 		CarbsPer100g:   in.CarbsPer100g,
 		FatPer100g:     in.FatPer100g,
+		AlcoholPer100g: in.AlcoholPer100g,
+		Visibility:     visibility,
+		CategoryID:     in.CategoryID,
+		Tags:           tags,
+		Allergens:      allergens,
+		DietFlags:      dietFlags,
+		Translations:   translations,
+		Source:         food.SourceUser,
 	}
 
 	created, err := s.repo.Create(ctx, value)
 	if err != nil {
 		return food.Food{}, err
 	}
+	created.Warnings = warnings
 	return created, nil
 }
 
-func (s *FoodService) GetByID(ctx context.Context, id uint) (food.Food, error) {
+// GetByID returns the food when userID may see it. Other users' private foods
+// are reported as not found so their existence does not leak.
+func (s *FoodService) GetByID(ctx context.Context, userID, id uint) (food.Food, error) {
 	value, err := s.repo.GetByID(ctx, id)
 	if errors.Is(err, repository.ErrNotFound) {
 		return food.Food{}, ErrFoodNotFound
@@ -117,17 +283,24 @@ func (s *FoodService) GetByID(ctx context.Context, id uint) (food.Food, error) {
 	if err != nil {
 		return food.Food{}, err
 	}
+	if !value.VisibleTo(userID) {
+		return food.Food{}, ErrFoodNotFound
+	}
 	return value, nil
 }
 
-func (s *FoodService) GetByBarcode(ctx context.Context, barcodeRaw string) (food.Food, error) {
+// GetByBarcode resolves a barcode among the foods userID may see, preferring
+// verified entries over the caller's private entry over public ones. On a
+// miss the configured barcode provider is consulted and a found product is
+// imported.
+func (s *FoodService) GetByBarcode(ctx context.Context, userID uint, barcodeRaw string) (food.Food, error) {
 	barcode, ok := normalizeBarcode(barcodeRaw)
 	if !ok {
 		return food.Food{}, ErrInvalidFoodBarcode
 	}
-	value, err := s.repo.GetByBarcode(ctx, barcode)
+	value, err := s.repo.GetByBarcode(ctx, userID, barcode)
 	if errors.Is(err, repository.ErrNotFound) {
-		return food.Food{}, ErrFoodBarcodeNotFound
+		return s.importBarcode(ctx, userID, barcode)
 	}
 	if err != nil {
 		return food.Food{}, err
@@ -135,43 +308,23 @@ func (s *FoodService) GetByBarcode(ctx context.Context, barcodeRaw string) (food
 	return value, nil
 }
 
-func (s *FoodService) List(ctx context.Context, limit, offset int) ([]food.Food, error) {
-	if !IsValidPagination(limit, offset) {
-		return nil, ErrInvalidPagination
-	}
-	return s.repo.List(ctx, limit, offset)
-}
-
-func (s *FoodService) Search(ctx context.Context, query string, limit, offset int) ([]food.Food, error) {
-	if !IsValidPagination(limit, offset) {
-		return nil, ErrInvalidPagination
-	}
-	q := strings.TrimSpace(query)
-	if q == "" {
-		return s.repo.List(ctx, limit, offset)
-	}
-	return s.repo.SearchByName(ctx, q, limit, offset)
-}
-
 func (s *FoodService) Update(ctx context.Context, userID, id uint, in UpdateFoodInput) (food.Food, error) {
 	if userID == 0 {
 		return food.Food{}, ErrInvalidUserID
 	}
-	if in.Name == nil && in.BrandName == nil && in.Barcode == nil && in.KcalPer100g == nil && in.ProteinPer100g == nil && in.CarbsPer100g == nil && in.FatPer100g == nil {
+	nutritionChanged := in.KcalPer100g != nil || in.ProteinPer100g != nil || in.CarbsPer100g != nil || in.FatPer100g != nil || in.AlcoholPer100g != nil
+	if in.Name == nil && in.BrandName == nil && in.Barcode == nil && !nutritionChanged && in.Visibility == nil && in.CategoryID == nil && in.Tags == nil && in.Allergens == nil && in.DietFlags == nil && in.Translations == nil {
 		return food.Food{}, ErrNoFieldsToUpdate
 	}
-	existing, err := s.repo.GetByID(ctx, id)
-	if errors.Is(err, repository.ErrNotFound) {
-		return food.Food{}, ErrFoodNotFound
-	}
+	existing, err := s.getEditable(ctx, userID, id)
 	if err != nil {
 		return food.Food{}, err
 	}
-	if existing.UserID != userID {
-		return food.Food{}, ErrFoodForbidden
+	if existing.Archived() {
+		return food.Food{}, ErrFoodArchived
 	}
 
-	updates := repository.FoodUpdate{}
+	updates := repository.FoodUpdate{EditedBy: userID}
 	if in.Name != nil {
 		trimmed := strings.TrimSpace(*in.Name)
 		if trimmed == "" {
@@ -207,19 +360,83 @@ func (s *FoodService) Update(ctx context.Context, userID, id uint, in UpdateFood
 		}
 		updates.FatPer100g = in.FatPer100g
 	}
+	if in.AlcoholPer100g != nil {
+		if *in.AlcoholPer100g < 0 {
+			return food.Food{}, ErrInvalidNutritionData
+		}
+		updates.AlcoholPer100g = in.AlcoholPer100g
+	}
+	if nutritionChanged && in.StrictNutrition {
+		if warnings := food.CheckNutrition(applyNutrientUpdates(existing, updates).Nutrients()); len(warnings) > 0 {
+			return food.Food{}, &NutritionError{Warnings: warnings}
+		}
+	}
+	if in.CategoryID != nil {
+		if *in.CategoryID == 0 {
+			updates.ClearCategory = true
+		} else {
+			if err := s.ensureCategory(ctx, *in.CategoryID); err != nil {
+				return food.Food{}, err
+			}
+			updates.CategoryID = in.CategoryID
+		}
+	}
+	if in.Tags != nil {
+		tags, ok := food.NormalizeTags(*in.Tags)
+		if !ok {
+			return food.Food{}, ErrInvalidFoodTags
+		}
+		updates.Tags = &tags
+	}
+	if in.Allergens != nil || in.DietFlags != nil {
+		allergens, dietFlags := existing.Allergens, existing.DietFlags
+		if in.Allergens != nil {
+			allergens = *in.Allergens
+		}
+		if in.DietFlags != nil {
+			dietFlags = *in.DietFlags
+		}
+		allergens, dietFlags, err := normalizeDietary(allergens, dietFlags)
+		if err != nil {
+			return food.Food{}, err
+		}
+		updates.Allergens = &allergens
+		updates.DietFlags = &dietFlags
+	}
+	if in.Translations != nil {
+		translations, ok := locale.NormalizeTranslations(*in.Translations)
+		if !ok {
+			return food.Food{}, ErrInvalidTranslations
+		}
+		updates.Translations = &translations
+	}
+	visibility := existing.Visibility
+	if in.Visibility != nil {
+		if !in.Visibility.Valid() {
+			return food.Food{}, ErrInvalidFoodVisibility
+		}
+		if *in.Visibility == food.VisibilityVerified && existing.Visibility != food.VisibilityVerified {
+			if err := s.requireAdmin(ctx, userID); err != nil {
+				return food.Food{}, err
+			}
+		}
+		visibility = *in.Visibility
+		updates.Visibility = &visibility
+	}
+	barcode := existing.Barcode
 	if in.Barcode != nil {
 		normalized, ok := normalizeBarcode(*in.Barcode)
 		if !ok {
 			return food.Food{}, ErrInvalidFoodBarcode
 		}
-		if existingBarcode, err := s.repo.GetByBarcode(ctx, normalized); err == nil {
-			if existingBarcode.ID != id {
-				return food.Food{}, ErrFoodBarcodeExists
-			}
-		} else if !errors.Is(err, repository.ErrNotFound) {
+		barcode = &normalized
+		updates.Barcode = &normalized
+	}
+	// Moving to another scope can collide just like a new barcode can.
+	if barcode != nil && (in.Barcode != nil || visibility != existing.Visibility) {
+		if err := s.ensureBarcodeFree(ctx, existing.UserID, id, visibility, *barcode); err != nil {
 			return food.Food{}, err
 		}
-		updates.Barcode = &normalized
 	}
 
 	value, err := s.repo.Update(ctx, id, updates)
@@ -229,30 +446,288 @@ func (s *FoodService) Update(ctx context.Context, userID, id uint, in UpdateFood
 	if err != nil {
 		return food.Food{}, err
 	}
+	if nutritionChanged {
+		if err := s.recipesChanged(ctx, id); err != nil {
+			return food.Food{}, err
+		}
+		value.Warnings = food.CheckNutrition(value.Nutrients())
+	}
 	return value, nil
 }
 
+// recipesChanged lets the recipes using the food follow its new nutrition.
+func (s *FoodService) recipesChanged(ctx context.Context, id uint) error {
+	if s.recipes == nil {
+		return nil
+	}
+	return s.recipes.FoodChanged(ctx, id)
+}
+
+// applyNutrientUpdates returns existing with the nutrition changes in
+// updates applied, for checking values before they are stored.
+func applyNutrientUpdates(existing food.Food, updates repository.FoodUpdate) food.Food {
+	for _, field := range []struct {
+		dst *float64
+		src *float64
+	}{
+		{&existing.KcalPer100g, updates.KcalPer100g},
+		{&existing.ProteinPer100g, updates.ProteinPer100g},
+		{&existing.CarbsPer100g, updates.CarbsPer100g},
+		{&existing.FatPer100g, updates.FatPer100g},
+		{&existing.AlcoholPer100g, updates.AlcoholPer100g},
+	} {
+		if field.src != nil {
+			*field.dst = *field.src
+		}
+	}
+	return existing
+}
+
+// Delete archives the food. Meal items and recipes keep pointing at it, so
+// the row stays and only disappears from lists, search and barcode lookups.
+// Deleting an archived food is a no-op.
 func (s *FoodService) Delete(ctx context.Context, userID, id uint) error {
 	if userID == 0 {
 		return ErrInvalidUserID
 	}
 
-	existing, err := s.repo.GetByID(ctx, id)
+	existing, err := s.getEditable(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if existing.Archived() {
+		return nil
+	}
+
+	err = s.repo.Archive(ctx, id, time.Now().UTC())
 	if errors.Is(err, repository.ErrNotFound) {
-		return ErrFoodNotFound
+		// Archived concurrently; the outcome is the same.
+		return nil
+	}
+	return err
+}
+
+// Restore brings an archived food back into lists and search. Its barcode
+// may have been taken in the meantime, in which case the restore conflicts.
+func (s *FoodService) Restore(ctx context.Context, userID, id uint) (food.Food, error) {
+	if userID == 0 {
+		return food.Food{}, ErrInvalidUserID
 	}
+	existing, err := s.getEditable(ctx, userID, id)
 	if err != nil {
-		return err
+		return food.Food{}, err
+	}
+	if !existing.Archived() {
+		return existing, nil
+	}
+	if existing.Barcode != nil {
+		if err := s.ensureBarcodeFree(ctx, existing.UserID, id, existing.Visibility, *existing.Barcode); err != nil {
+			return food.Food{}, err
+		}
+	}
+
+	value, err := s.repo.Restore(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return food.Food{}, ErrFoodNotFound
+	}
+	if err != nil {
+		return food.Food{}, err
+	}
+	return value, nil
+}
+
+// Verify promotes a food to verified. Only admins may verify, and the food
+// must be visible to them.
+func (s *FoodService) Verify(ctx context.Context, userID, id uint) (food.Food, error) {
+	if userID == 0 {
+		return food.Food{}, ErrInvalidUserID
+	}
+	if err := s.requireAdmin(ctx, userID); err != nil {
+		return food.Food{}, err
+	}
+	existing, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return food.Food{}, err
+	}
+	if existing.Archived() {
+		return food.Food{}, ErrFoodArchived
+	}
+	if existing.Visibility == food.VisibilityVerified {
+		return existing, nil
+	}
+	if existing.Barcode != nil {
+		if err := s.ensureBarcodeFree(ctx, existing.UserID, id, food.VisibilityVerified, *existing.Barcode); err != nil {
+			return food.Food{}, err
+		}
+	}
+
+	visibility := food.VisibilityVerified
+	value, err := s.repo.Update(ctx, id, repository.FoodUpdate{Visibility: &visibility, EditedBy: userID})
+	if errors.Is(err, repository.ErrNotFound) {
+		return food.Food{}, ErrFoodNotFound
+	}
+	if err != nil {
+		return food.Food{}, err
+	}
+	return value, nil
+}
+
+// FoodNutritionIssue is a food whose label values contradict each other.
+type FoodNutritionIssue struct {
+	Food          food.Food               `json:"food"`
+	EstimatedKcal float64                 `json:"estimated_kcal"`
+	Warnings      []food.NutritionWarning `json:"warnings"`
+}
+
+// NutritionReport lists active foods visible to userID that fail the
+// nutrition consistency checks, oldest first.
+func (s *FoodService) NutritionReport(ctx context.Context, userID uint, limit, offset int) ([]FoodNutritionIssue, error) {
+	if !IsValidPagination(limit, offset) {
+		return nil, ErrInvalidPagination
+	}
+	foods, err := s.repo.ListNutritionSuspects(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FoodNutritionIssue, 0, len(foods))
+	for _, f := range foods {
+		warnings := food.CheckNutrition(f.Nutrients())
+		if len(warnings) == 0 {
+			// SQL and Go rounding can disagree right at a tolerance edge.
+			continue
+		}
+		out = append(out, FoodNutritionIssue{
+			Food:          f,
+			EstimatedKcal: math.Round(f.Nutrients().EstimatedKcal()*10) / 10,
+			Warnings:      warnings,
+		})
+	}
+	return out, nil
+}
+
+// ListVersions returns the edit history of a food visible to userID, newest
+// first.
+func (s *FoodService) ListVersions(ctx context.Context, userID, id uint) ([]food.Version, error) {
+	if _, err := s.GetByID(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVersions(ctx, id)
+}
+
+// Rollback restores the name, brand, barcode and nutrition values of an
+// earlier version. The restore is itself recorded as a new version, so the
+// history stays append-only. Visibility is left as it is; it has its own
+// rules and is changed through Update and Verify.
+func (s *FoodService) Rollback(ctx context.Context, userID, id uint, version int) (food.Food, error) {
+	if userID == 0 {
+		return food.Food{}, ErrInvalidUserID
+	}
+	existing, err := s.getEditable(ctx, userID, id)
+	if err != nil {
+		return food.Food{}, err
+	}
+	if existing.Archived() {
+		return food.Food{}, ErrFoodArchived
+	}
+	if version <= 0 {
+		return food.Food{}, ErrFoodVersionNotFound
+	}
+	target, err := s.repo.GetVersion(ctx, id, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return food.Food{}, ErrFoodVersionNotFound
+	}
+	if err != nil {
+		return food.Food{}, err
+	}
+	if target.Barcode != nil {
+		if err := s.ensureBarcodeFree(ctx, existing.UserID, id, existing.Visibility, *target.Barcode); err != nil {
+			return food.Food{}, err
+		}
+	}
+
+	value, err := s.repo.Update(ctx, id, repository.FoodUpdate{
+		Name:           &target.Name,
+		BrandName:      target.BrandName,
+		ClearBrandName: target.BrandName == nil,
+		Barcode:        target.Barcode,
+		ClearBarcode:   target.Barcode == nil,
+		KcalPer100g:    &target.KcalPer100g,
+		ProteinPer100g: &target.ProteinPer100g,
+		CarbsPer100g:   &target.CarbsPer100g,
+		FatPer100g:     &target.FatPer100g,
+		AlcoholPer100g: &target.AlcoholPer100g,
+		EditedBy:       userID,
+		RestoredFrom:   &target.Version,
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return food.Food{}, ErrFoodNotFound
+	}
+	if err != nil {
+		return food.Food{}, err
+	}
+	if err := s.recipesChanged(ctx, id); err != nil {
+		return food.Food{}, err
+	}
+	value.Warnings = food.CheckNutrition(value.Nutrients())
+	return value, nil
+}
+
+// getEditable loads a food userID may change. Owners edit their private and
+// public foods; verified and imported catalog foods are curated and only
+// admins may change them.
+func (s *FoodService) getEditable(ctx context.Context, userID, id uint) (food.Food, error) {
+	existing, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return food.Food{}, err
+	}
+	if existing.Visibility == food.VisibilityVerified || existing.Imported() {
+		err := s.requireAdmin(ctx, userID)
+		if errors.Is(err, ErrFoodVerificationForbidden) {
+			return food.Food{}, ErrFoodForbidden
+		}
+		if err != nil {
+			return food.Food{}, err
+		}
+		return existing, nil
 	}
 	if existing.UserID != userID {
-		return ErrFoodForbidden
+		return food.Food{}, ErrFoodForbidden
 	}
+	return existing, nil
+}
 
-	err = s.repo.Delete(ctx, id)
+func (s *FoodService) requireAdmin(ctx context.Context, userID uint) error {
+	if s.admins == nil {
+		return ErrFoodVerificationForbidden
+	}
+	u, err := s.admins.GetByID(ctx, userID)
 	if errors.Is(err, repository.ErrNotFound) {
-		return ErrFoodNotFound
+		return ErrFoodVerificationForbidden
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	if !u.IsAdmin {
+		return ErrFoodVerificationForbidden
+	}
+	return nil
+}
+
+// ensureBarcodeFree checks barcode uniqueness within the scope a food with
+// the given owner and visibility lives in. foodID is excluded so a food does
+// not collide with itself.
+func (s *FoodService) ensureBarcodeFree(ctx context.Context, ownerID, foodID uint, visibility food.Visibility, barcode string) error {
+	found, err := s.repo.GetByBarcodeInScope(ctx, ownerID, visibility, barcode)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if found.ID != foodID {
+		return ErrFoodBarcodeExists
+	}
+	return nil
 }
 
 func hasNegative(values ...float64) bool {
@@ -264,6 +739,23 @@ func hasNegative(values ...float64) bool {
 	return false
 }
 
+// normalizeDietary validates allergens and diet flags together, since a flag
+// such as vegan is contradicted by some allergens.
+func normalizeDietary(allergens, dietFlags []string) ([]string, []string, error) {
+	normalizedAllergens, ok := dietary.NormalizeAllergens(allergens)
+	if !ok {
+		return nil, nil, ErrInvalidDietaryInfo
+	}
+	normalizedFlags, ok := dietary.NormalizeDietFlags(dietFlags)
+	if !ok {
+		return nil, nil, ErrInvalidDietaryInfo
+	}
+	if !dietary.Consistent(normalizedAllergens, normalizedFlags) {
+		return nil, nil, ErrInvalidDietaryInfo
+	}
+	return normalizedAllergens, normalizedFlags, nil
+}
+
 func normalizeBarcode(raw string) (string, bool) {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
@@ -290,3 +782,12 @@ func normalizeBarcode(raw string) (string, bool) {
 	}
 	return string(buf), true
 }
+
+// versionRef returns a pointer to a recorded food version, or nil when the
+// version is unknown.
+func versionRef(version int) *int {
+	if version <= 0 {
+		return nil
+	}
+	return &version
+}
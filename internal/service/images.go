@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/meal"
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/media"
+	"goal-bite-api/internal/repository"
+)
+
+var (
+	ErrInvalidImage            = errors.New("invalid image")
+	ErrUnsupportedImageType    = errors.New("unsupported image type")
+	ErrImageTooLarge           = errors.New("image too large")
+	ErrImageStorageUnavailable = errors.New("image storage unavailable")
+)
+
+// BlobStore keeps uploaded files under keys and tells where clients can
+// fetch them.
+type BlobStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// ImageUploads lets foods, recipes and meals carry an image. Uploads larger
+// than MaxBytes are rejected; zero means media.DefaultMaxBytes. Services
+// without a Store reject uploads with ErrImageStorageUnavailable.
+type ImageUploads struct {
+	Store    BlobStore
+	MaxBytes int
+}
+
+// Recipe and meal images are stored without the recipe or meal ID in their
+// keys, because share links hand their URLs to anyone holding the link.
+const (
+	recipeImagePrefix = "recipes"
+	mealImagePrefix   = "meals"
+)
+
+// shareableImage returns url and thumbnailURL unless key is still one of the
+// legacyPrefix keys images were once stored under, which carry the ID of the
+// recipe or meal they belong to.
+func shareableImage(key, url, thumbnailURL *string, legacyPrefix string) (*string, *string) {
+	if key == nil || strings.HasPrefix(*key, legacyPrefix) {
+		return nil, nil
+	}
+	return url, thumbnailURL
+}
+
+// replaceImage stores data as the image of the entity under prefix, or
+// removes the image when data is nil, and records the change with save.
+// Renditions are written before save runs, and the ones they replace are
+// deleted only after it succeeded, so a failure never leaves the entity
+// pointing at a missing file. Deletion failures are ignored: an orphaned
+// file is harmless, a failed upload after a successful save is not.
+func replaceImage[T any](ctx context.Context, uploads *ImageUploads, prefix string, current *string, data []byte, save func(*repository.Image) (T, error)) (T, error) {
+	var zero T
+	if data == nil {
+		value, err := save(nil)
+		if err != nil {
+			return zero, err
+		}
+		if uploads != nil {
+			discardImage(ctx, uploads.Store, current)
+		}
+		return value, nil
+	}
+	if uploads == nil {
+		return zero, ErrImageStorageUnavailable
+	}
+
+	processed, err := media.Process(data, uploads.MaxBytes)
+	switch {
+	case errors.Is(err, media.ErrUnsupportedType):
+		return zero, ErrUnsupportedImageType
+	case errors.Is(err, media.ErrTooLarge):
+		return zero, ErrImageTooLarge
+	case errors.Is(err, media.ErrInvalid):
+		return zero, ErrInvalidImage
+	case err != nil:
+		return zero, err
+	}
+
+	token := make([]byte, 12)
+	if _, err := rand.Read(token); err != nil {
+		return zero, err
+	}
+	key := prefix + "/" + hex.EncodeToString(token) + processed.Image.Ext
+	thumbKey := thumbnailKey(key)
+	if err := uploads.Store.Put(ctx, key, processed.Image.ContentType, processed.Image.Data); err != nil {
+		return zero, fmt.Errorf("%w: %v", ErrImageStorageUnavailable, err)
+	}
+	if err := uploads.Store.Put(ctx, thumbKey, processed.Thumbnail.ContentType, processed.Thumbnail.Data); err != nil {
+		discardImage(ctx, uploads.Store, &key)
+		return zero, fmt.Errorf("%w: %v", ErrImageStorageUnavailable, err)
+	}
+
+	value, err := save(&repository.Image{
+		Key:          key,
+		URL:          uploads.Store.URL(key),
+		ThumbnailURL: uploads.Store.URL(thumbKey),
+	})
+	if err != nil {
+		discardImage(ctx, uploads.Store, &key)
+		return zero, err
+	}
+	discardImage(ctx, uploads.Store, current)
+	return value, nil
+}
+
+// discardImage deletes an image and its thumbnail, ignoring failures.
+func discardImage(ctx context.Context, store BlobStore, key *string) {
+	if store == nil || key == nil || *key == "" {
+		return
+	}
+	_ = store.Delete(ctx, *key)
+	_ = store.Delete(ctx, thumbnailKey(*key))
+}
+
+// thumbnailKey names the thumbnail stored next to the image under key.
+// Thumbnails are always JPEGs.
+func thumbnailKey(key string) string {
+	if i := strings.LastIndexByte(key, '.'); i > strings.LastIndexByte(key, '/') {
+		key = key[:i]
+	}
+	return key + "_thumb.jpg"
+}
+
+// SetImage stores data as the food's image, replacing any previous one. The
+// edit rules of Update apply.
+func (s *FoodService) SetImage(ctx context.Context, userID, id uint, data []byte) (food.Food, error) {
+	return s.replaceImage(ctx, userID, id, data)
+}
+
+func (s *FoodService) RemoveImage(ctx context.Context, userID, id uint) (food.Food, error) {
+	return s.replaceImage(ctx, userID, id, nil)
+}
+
+func (s *FoodService) replaceImage(ctx context.Context, userID, id uint, data []byte) (food.Food, error) {
+	if userID == 0 {
+		return food.Food{}, ErrInvalidUserID
+	}
+	existing, err := s.getEditable(ctx, userID, id)
+	if err != nil {
+		return food.Food{}, err
+	}
+	if existing.Archived() {
+		return food.Food{}, ErrFoodArchived
+	}
+	if data == nil && existing.ImageKey == nil {
+		return existing, nil
+	}
+	value, err := replaceImage(ctx, s.images, fmt.Sprintf("foods/%d", id), existing.ImageKey, data, func(img *repository.Image) (food.Food, error) {
+		return s.repo.SetImage(ctx, id, img)
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return food.Food{}, ErrFoodNotFound
+	}
+	return value, err
+}
+
+// SetImage stores data as the recipe's image, replacing any previous one.
+// Only the owner may change it.
+func (s *RecipeService) SetImage(ctx context.Context, userID, id uint, data []byte) (recipe.Recipe, error) {
+	return s.replaceImage(ctx, userID, id, data)
+}
+
+func (s *RecipeService) RemoveImage(ctx context.Context, userID, id uint) (recipe.Recipe, error) {
+	return s.replaceImage(ctx, userID, id, nil)
+}
+
+func (s *RecipeService) replaceImage(ctx context.Context, userID, id uint, data []byte) (recipe.Recipe, error) {
+	if userID == 0 {
+		return recipe.Recipe{}, ErrInvalidUserID
+	}
+	existing, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeNotFound
+	}
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	if existing.UserID != userID {
+		return recipe.Recipe{}, ErrRecipeForbidden
+	}
+	if existing.Archived() {
+		return recipe.Recipe{}, ErrRecipeArchived
+	}
+	if data == nil && existing.ImageKey == nil {
+		return existing, nil
+	}
+	value, err := replaceImage(ctx, s.images, recipeImagePrefix, existing.ImageKey, data, func(img *repository.Image) (recipe.Recipe, error) {
+		return s.repo.SetImage(ctx, id, img)
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeNotFound
+	}
+	return value, err
+}
+
+// SetImage stores data as a photo of the meal, replacing any previous one.
+func (s *MealService) SetImage(ctx context.Context, userID, mealID uint, data []byte) (meal.Meal, error) {
+	return s.replaceImage(ctx, userID, mealID, data)
+}
+
+func (s *MealService) RemoveImage(ctx context.Context, userID, mealID uint) (meal.Meal, error) {
+	return s.replaceImage(ctx, userID, mealID, nil)
+}
+
+func (s *MealService) replaceImage(ctx context.Context, userID, mealID uint, data []byte) (meal.Meal, error) {
+	existing, err := s.GetByID(ctx, userID, mealID)
+	if err != nil {
+		return meal.Meal{}, err
+	}
+	if data == nil && existing.ImageKey == nil {
+		return existing, nil
+	}
+	value, err := replaceImage(ctx, s.images, mealImagePrefix, existing.ImageKey, data, func(img *repository.Image) (meal.Meal, error) {
+		return s.repo.SetImageForUser(ctx, userID, mealID, img)
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return meal.Meal{}, ErrMealNotFound
+	}
+	return value, err
+}
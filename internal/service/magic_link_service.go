@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"goal-bite-api/internal/auth"
+	"goal-bite-api/internal/domain/user"
+	"goal-bite-api/internal/mail"
+	"goal-bite-api/internal/repository"
+)
+
+var ErrInvalidMagicLinkToken = errors.New("invalid magic link token")
+
+type MagicLinkUserStore interface {
+	GetByID(ctx context.Context, id uint) (user.User, error)
+	GetByEmail(ctx context.Context, email string) (user.User, error)
+}
+
+type MagicLinkStore interface {
+	Create(ctx context.Context, in repository.CreateMagicLinkTokenInput) (repository.MagicLinkToken, error)
+	Consume(ctx context.Context, tokenHash string, now time.Time) (repository.MagicLinkToken, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg mail.Message) error
+}
+
+type MagicLinkConfig struct {
+	// BaseURL is the client page that receives the token as the `token` query parameter.
+	BaseURL string
+	TTL     time.Duration
+	// Cooldown is how long further requests for an email are ignored after
+	// one was accepted. Defaults to a minute.
+	Cooldown time.Duration
+	// MaxPendingSends bounds the sends running at once; requests beyond it
+	// are dropped and logged. Defaults to 16.
+	MaxPendingSends int
+	// Logger receives mail failures, which callers never see. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// magicLinkSendTimeout bounds one background lookup, insert and send.
+const magicLinkSendTimeout = 30 * time.Second
+
+type MagicLinkService struct {
+	users    MagicLinkUserStore
+	links    MagicLinkStore
+	sessions AuthSessionStore
+	tokens   TokenIssuer
+	mailer   Mailer
+	cfg      MagicLinkConfig
+	pending  sync.WaitGroup
+	// slots holds one entry per send in flight.
+	slots chan struct{}
+
+	mu sync.Mutex
+	// requested is when each email last had a request accepted, for emails
+	// still in their cooldown; swept is when expired entries were last
+	// removed.
+	requested map[string]time.Time
+	swept     time.Time
+}
+
+func NewMagicLinkService(users MagicLinkUserStore, links MagicLinkStore, sessions AuthSessionStore, tokens TokenIssuer, mailer Mailer, cfg MagicLinkConfig) *MagicLinkService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 15 * time.Minute
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = time.Minute
+	}
+	if cfg.MaxPendingSends <= 0 {
+		cfg.MaxPendingSends = 16
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &MagicLinkService{
+		users:     users,
+		links:     links,
+		sessions:  sessions,
+		tokens:    tokens,
+		mailer:    mailer,
+		cfg:       cfg,
+		slots:     make(chan struct{}, cfg.MaxPendingSends),
+		requested: map[string]time.Time{},
+	}
+}
+
+// RequestMagicLink emails a one-time login link. Only the email's format is
+// checked before returning; the account lookup, token insert and send run in
+// the background, so known and unknown emails answer alike in both outcome
+// and timing. Failures are logged, never returned. Repeated requests for an
+// email within the cooldown, and requests while MaxPendingSends sends are
+// running, are accepted the same way but send nothing.
+func (s *MagicLinkService) RequestMagicLink(ctx context.Context, emailRaw string) error {
+	email, err := auth.NormalizeEmail(emailRaw)
+	if err != nil {
+		return ErrInvalidEmail
+	}
+	if !s.claim(email, time.Now()) {
+		return nil
+	}
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		// The email did not get a link, so it may ask again right away.
+		s.unclaim(email)
+		s.cfg.Logger.Warn("magic link sends saturated, request dropped", "max_pending", s.cfg.MaxPendingSends)
+		return nil
+	}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		defer func() { <-s.slots }()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), magicLinkSendTimeout)
+		defer cancel()
+		if err := s.sendMagicLink(ctx, email); err != nil {
+			s.cfg.Logger.Error("send magic link", "error", err)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until every magic link requested so far has been sent or has
+// failed.
+func (s *MagicLinkService) Wait() {
+	s.pending.Wait()
+}
+
+// claim records a request for email at now unless one was accepted within
+// the cooldown. Expired entries are swept once per cooldown, so the map only
+// holds emails requested recently.
+func (s *MagicLinkService) claim(email string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.requested[email]; ok && now.Sub(last) < s.cfg.Cooldown {
+		return false
+	}
+	if now.Sub(s.swept) >= s.cfg.Cooldown {
+		for key, last := range s.requested {
+			if now.Sub(last) >= s.cfg.Cooldown {
+				delete(s.requested, key)
+			}
+		}
+		s.swept = now
+	}
+	s.requested[email] = now
+	return true
+}
+
+func (s *MagicLinkService) unclaim(email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.requested, email)
+}
+
+func (s *MagicLinkService) sendMagicLink(ctx context.Context, email string) error {
+	u, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := generateRefreshToken()
+	if err != nil {
+		return err
+	}
+	if _, err := s.links.Create(ctx, repository.CreateMagicLinkTokenInput{
+		UserID:    u.ID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: time.Now().UTC().Add(s.cfg.TTL),
+	}); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      u.Email,
+		Subject: "Your Goal Bite sign-in link",
+		Body: fmt.Sprintf(
+			"Hi %s,\r\n\r\nUse the link below to sign in. It expires in %d minutes and can only be used once.\r\n\r\n%s\r\n\r\nIf you did not request this, you can ignore this email.\r\n",
+			u.Name,
+			int(s.cfg.TTL.Minutes()),
+			s.linkURL(token),
+		),
+	})
+}
+
+func (s *MagicLinkService) ConsumeMagicLink(ctx context.Context, tokenRaw string) (AuthResult, error) {
+	token := strings.TrimSpace(tokenRaw)
+	if token == "" {
+		return AuthResult{}, ErrInvalidMagicLinkToken
+	}
+
+	link, err := s.links.Consume(ctx, hashRefreshToken(token), time.Now().UTC())
+	if errors.Is(err, repository.ErrNotFound) {
+		return AuthResult{}, ErrInvalidMagicLinkToken
+	}
+	if err != nil {
+		return AuthResult{}, err
+	}
+
+	u, err := s.users.GetByID(ctx, link.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return AuthResult{}, ErrInvalidMagicLinkToken
+	}
+	if err != nil {
+		return AuthResult{}, err
+	}
+	return issueAuthResult(ctx, s.tokens, s.sessions, u)
+}
+
+func (s *MagicLinkService) linkURL(token string) string {
+	base, err := url.Parse(s.cfg.BaseURL)
+	if err != nil || s.cfg.BaseURL == "" {
+		return token
+	}
+	q := base.Query()
+	q.Set("token", token)
+	base.RawQuery = q.Encode()
+	return base.String()
+}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"math"
 	"time"
 
+	"goal-bite-api/internal/domain/dietary"
 	"goal-bite-api/internal/domain/meal"
 	"goal-bite-api/internal/domain/mealitem"
 	"goal-bite-api/internal/domain/recipe"
@@ -21,6 +23,7 @@ var (
 	ErrInvalidItemWeight    = errors.New("invalid item weight")
 	ErrInvalidDate          = errors.New("invalid date")
 	ErrRecipeSourceNotFound = errors.New("recipe source not found")
+	ErrInvalidItemServings  = errors.New("invalid item servings")
 )
 
 type MealStore interface {
@@ -37,16 +40,21 @@ type MealStore interface {
 	UpdateItemForUser(ctx context.Context, userID, mealID, itemID uint, in repository.AddMealItemInput) (mealitem.MealItem, error)
 	DeleteItemForUser(ctx context.Context, userID, mealID, itemID uint) error
 	GetDailyTotals(ctx context.Context, userID uint, date time.Time) (repository.DailyTotals, error)
+	SetImageForUser(ctx context.Context, userID, mealID uint, img *repository.Image) (meal.Meal, error)
 }
 
 type RecipeReader interface {
 	GetByID(ctx context.Context, id uint) (recipe.Recipe, error)
+	ListByIDs(ctx context.Context, ids []uint) ([]recipe.Recipe, error)
 }
 
 type MealService struct {
 	repo         MealStore
 	foodReader   FoodReader
 	recipeReader RecipeReader
+	users        RestrictionReader
+	images       *ImageUploads
+	cooking      CookingFactors
 }
 
 type CreateMealInput struct {
@@ -56,10 +64,17 @@ type CreateMealInput struct {
 	Items    []AddMealItemInput
 }
 
+// AddMealItemInput is an amount of a food or recipe. A recipe can be given
+// in Servings instead of WeightG, converted to grams through its yield, and
+// a food in CookedWeightG after cooking by CookingMethodID, converted back
+// to its raw weight.
 type AddMealItemInput struct {
-	FoodID   *uint
-	RecipeID *uint
-	WeightG  float64
+	FoodID          *uint
+	RecipeID        *uint
+	WeightG         float64
+	Servings        *float64
+	CookedWeightG   *float64
+	CookingMethodID *uint
 }
 
 type ListMealsInput struct {
@@ -74,10 +89,16 @@ type UpdateMealInput struct {
 	EatenAt  *time.Time
 }
 
+// UpdateMealItemInput changes an item's source or amount. Setting WeightG
+// drops a servings count or cooked weight, while an item logged in servings
+// or by cooked weight keeps it across a change of source.
 type UpdateMealItemInput struct {
-	FoodID   *uint
-	RecipeID *uint
-	WeightG  *float64
+	FoodID          *uint
+	RecipeID        *uint
+	WeightG         *float64
+	Servings        *float64
+	CookedWeightG   *float64
+	CookingMethodID *uint
 }
 
 type DailyTotalsOutput struct {
@@ -92,6 +113,22 @@ func NewMealService(repo MealStore, foodReader FoodReader, recipeReader RecipeRe
 	return &MealService{repo: repo, foodReader: foodReader, recipeReader: recipeReader}
 }
 
+// SetRestrictionReader makes meal items report conflicts with the owner's
+// dietary restrictions.
+func (s *MealService) SetRestrictionReader(users RestrictionReader) { s.users = users }
+
+// SetImageUploads enables meal photos.
+func (s *MealService) SetImageUploads(images ImageUploads) {
+	if images.Store == nil {
+		s.images = nil
+		return
+	}
+	s.images = &images
+}
+
+// SetCookingFactors lets items be logged by cooked weight.
+func (s *MealService) SetCookingFactors(factors CookingFactors) { s.cooking = factors }
+
 func (s *MealService) Create(ctx context.Context, in CreateMealInput) (meal.Meal, error) {
 	if in.UserID == 0 {
 		return meal.Meal{}, ErrInvalidUserID
@@ -106,7 +143,7 @@ func (s *MealService) Create(ctx context.Context, in CreateMealInput) (meal.Meal
 	if len(in.Items) > 0 {
 		snapshots := make([]repository.AddMealItemInput, 0, len(in.Items))
 		for _, item := range in.Items {
-			snapshot, err := s.resolveMealItemSnapshot(ctx, item)
+			snapshot, _, err := s.resolveMealItemSnapshot(ctx, in.UserID, item, false)
 			if err != nil {
 				return meal.Meal{}, err
 			}
@@ -166,7 +203,11 @@ func (s *MealService) AddItem(ctx context.Context, userID, mealID uint, in AddMe
 	if mealID == 0 {
 		return mealitem.MealItem{}, ErrMealNotFound
 	}
-	snapshot, err := s.resolveMealItemSnapshot(ctx, in)
+	snapshot, source, err := s.resolveMealItemSnapshot(ctx, userID, in, false)
+	if err != nil {
+		return mealitem.MealItem{}, err
+	}
+	restrictions, err := loadRestrictions(ctx, s.users, userID)
 	if err != nil {
 		return mealitem.MealItem{}, err
 	}
@@ -178,6 +219,8 @@ func (s *MealService) AddItem(ctx context.Context, userID, mealID uint, in AddMe
 	if err != nil {
 		return mealitem.MealItem{}, err
 	}
+	// Conflicts do not block logging: the user may knowingly eat the item.
+	value.Warnings = dietary.Conflicts(restrictions, source.allergens, source.dietFlags)
 
 	return value, nil
 }
@@ -241,12 +284,19 @@ func (s *MealService) UpdateItem(ctx context.Context, userID, mealID, itemID uin
 	if itemID == 0 {
 		return mealitem.MealItem{}, ErrMealItemNotFound
 	}
-	if in.FoodID == nil && in.RecipeID == nil && in.WeightG == nil {
+	cooked := in.CookedWeightG != nil || in.CookingMethodID != nil
+	if in.FoodID == nil && in.RecipeID == nil && in.WeightG == nil && in.Servings == nil && !cooked {
 		return mealitem.MealItem{}, ErrNoFieldsToUpdate
 	}
 	if in.WeightG != nil && *in.WeightG <= 0 {
 		return mealitem.MealItem{}, ErrInvalidItemWeight
 	}
+	if in.Servings != nil && (*in.Servings <= 0 || in.WeightG != nil) {
+		return mealitem.MealItem{}, ErrInvalidItemServings
+	}
+	if cooked && (in.WeightG != nil || in.Servings != nil || (in.CookedWeightG != nil && *in.CookedWeightG <= 0)) {
+		return mealitem.MealItem{}, ErrInvalidCookedWeight
+	}
 
 	sourceProvided := in.FoodID != nil || in.RecipeID != nil
 	if sourceProvided && (in.FoodID != nil && in.RecipeID != nil) {
@@ -261,9 +311,28 @@ func (s *MealService) UpdateItem(ctx context.Context, userID, mealID, itemID uin
 		return mealitem.MealItem{}, err
 	}
 
-	finalWeight := existing.WeightG
+	// An item logged in servings or by cooked weight keeps them until a
+	// weight replaces them. They are converted again, so its grams follow
+	// the recipe's yield or the cooking factors.
+	amount := AddMealItemInput{Servings: existing.Servings, CookedWeightG: existing.CookedWeightG, CookingMethodID: existing.CookingMethodID}
+	if existing.Servings == nil && existing.CookedWeightG == nil {
+		amount.WeightG = existing.WeightG
+	}
 	if in.WeightG != nil {
-		finalWeight = *in.WeightG
+		amount = AddMealItemInput{WeightG: *in.WeightG}
+	}
+	if in.Servings != nil {
+		amount = AddMealItemInput{Servings: in.Servings}
+	}
+	if cooked {
+		cookedWeight, method := amount.CookedWeightG, amount.CookingMethodID
+		if in.CookedWeightG != nil {
+			cookedWeight = in.CookedWeightG
+		}
+		if in.CookingMethodID != nil {
+			method = in.CookingMethodID
+		}
+		amount = AddMealItemInput{CookedWeightG: cookedWeight, CookingMethodID: method}
 	}
 
 	finalFoodID := existing.FoodID
@@ -273,11 +342,16 @@ func (s *MealService) UpdateItem(ctx context.Context, userID, mealID, itemID uin
 		finalRecipeID = in.RecipeID
 	}
 
-	snapshot, err := s.resolveMealItemSnapshot(ctx, AddMealItemInput{
-		FoodID:   finalFoodID,
-		RecipeID: finalRecipeID,
-		WeightG:  finalWeight,
-	})
+	// An item may keep an archived source it already had, but cannot switch to one.
+	keepArchived := sameID(finalFoodID, existing.FoodID) && sameID(finalRecipeID, existing.RecipeID)
+	snapshot, _, err := s.resolveMealItemSnapshot(ctx, userID, AddMealItemInput{
+		FoodID:          finalFoodID,
+		RecipeID:        finalRecipeID,
+		WeightG:         amount.WeightG,
+		Servings:        amount.Servings,
+		CookedWeightG:   amount.CookedWeightG,
+		CookingMethodID: amount.CookingMethodID,
+	}, keepArchived)
 	if err != nil {
 		return mealitem.MealItem{}, err
 	}
@@ -309,48 +383,111 @@ func (s *MealService) DeleteItem(ctx context.Context, userID, mealID, itemID uin
 	return err
 }
 
-func (s *MealService) resolveMealItemSnapshot(ctx context.Context, in AddMealItemInput) (repository.AddMealItemInput, error) {
-	if in.WeightG <= 0 {
-		return repository.AddMealItemInput{}, ErrInvalidItemWeight
+// itemSource is what the food or recipe behind a meal item declares about
+// allergens and diets.
+type itemSource struct {
+	allergens []string
+	dietFlags []string
+}
+
+// resolveMealItemSnapshot copies the current nutrition of the item's source.
+// Archived foods and recipes are rejected unless keepArchived is set, which
+// lets existing items keep their source when only the weight changes. A
+// cooked weight converts to the raw weight the food's values are for, and
+// the factor's moisture retention estimates the water gained in cooking.
+func (s *MealService) resolveMealItemSnapshot(ctx context.Context, userID uint, in AddMealItemInput, keepArchived bool) (repository.AddMealItemInput, itemSource, error) {
+	cooked := in.CookedWeightG != nil || in.CookingMethodID != nil
+	switch {
+	case cooked:
+		if in.CookedWeightG == nil || *in.CookedWeightG <= 0 || in.CookingMethodID == nil || in.WeightG != 0 || in.Servings != nil {
+			return repository.AddMealItemInput{}, itemSource{}, ErrInvalidCookedWeight
+		}
+	case in.Servings != nil:
+		if *in.Servings <= 0 || in.WeightG != 0 {
+			return repository.AddMealItemInput{}, itemSource{}, ErrInvalidItemServings
+		}
+	case in.WeightG <= 0:
+		return repository.AddMealItemInput{}, itemSource{}, ErrInvalidItemWeight
 	}
 
 	foodSet := in.FoodID != nil
 	recipeSet := in.RecipeID != nil
 	if foodSet == recipeSet {
-		return repository.AddMealItemInput{}, ErrInvalidItemSource
+		return repository.AddMealItemInput{}, itemSource{}, ErrInvalidItemSource
+	}
+	if foodSet && in.Servings != nil {
+		// Foods have no yield to split into servings.
+		return repository.AddMealItemInput{}, itemSource{}, ErrInvalidItemServings
+	}
+	if recipeSet && cooked {
+		// Recipes are weighed as prepared already.
+		return repository.AddMealItemInput{}, itemSource{}, ErrInvalidCookedWeight
 	}
 
+	weight := in.WeightG
 	var kcal, protein, carbs, fat float64
+	var foodVersion *int
+	var waterChange *float64
+	var source itemSource
 	if foodSet {
 		f, err := s.foodReader.GetByID(ctx, *in.FoodID)
 		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, ErrFoodNotFound) {
-			return repository.AddMealItemInput{}, ErrFoodNotFound
+			return repository.AddMealItemInput{}, itemSource{}, ErrFoodNotFound
 		}
 		if err != nil {
-			return repository.AddMealItemInput{}, err
+			return repository.AddMealItemInput{}, itemSource{}, err
+		}
+		if !f.VisibleTo(userID) {
+			return repository.AddMealItemInput{}, itemSource{}, ErrFoodNotFound
+		}
+		if f.Archived() && !keepArchived {
+			return repository.AddMealItemInput{}, itemSource{}, ErrFoodArchived
+		}
+		if cooked {
+			factor, err := yieldFactor(ctx, s.cooking, *in.CookingMethodID, f.CategoryID)
+			if err != nil {
+				return repository.AddMealItemInput{}, itemSource{}, err
+			}
+			weight = factor.RawWeightG(*in.CookedWeightG)
+			water := math.Round(factor.WaterChangeG(weight, f.Nutrients().EstimatedWaterPer100g())*10) / 10
+			waterChange = &water
 		}
 		kcal, protein, carbs, fat = f.KcalPer100g, f.ProteinPer100g, f.CarbsPer100g, f.FatPer100g
+		foodVersion = versionRef(f.CurrentVersion)
+		source = itemSource{allergens: f.Allergens, dietFlags: f.DietFlags}
 	}
 	if recipeSet {
 		rv, err := s.recipeReader.GetByID(ctx, *in.RecipeID)
 		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, ErrRecipeNotFound) {
-			return repository.AddMealItemInput{}, ErrRecipeSourceNotFound
+			return repository.AddMealItemInput{}, itemSource{}, ErrRecipeSourceNotFound
 		}
 		if err != nil {
-			return repository.AddMealItemInput{}, err
+			return repository.AddMealItemInput{}, itemSource{}, err
+		}
+		if rv.Archived() && !keepArchived {
+			return repository.AddMealItemInput{}, itemSource{}, ErrRecipeArchived
 		}
 		kcal, protein, carbs, fat = rv.KcalPer100g, rv.ProteinPer100g, rv.CarbsPer100g, rv.FatPer100g
+		source = itemSource{allergens: rv.Allergens, dietFlags: rv.DietFlags}
+		if in.Servings != nil {
+			weight = *in.Servings * rv.ServingWeightG()
+		}
 	}
 
 	return repository.AddMealItemInput{
-		FoodID:         in.FoodID,
-		RecipeID:       in.RecipeID,
-		WeightG:        in.WeightG,
-		KcalPer100g:    kcal,
-		ProteinPer100g: protein,
-		CarbsPer100g:   carbs,
-		FatPer100g:     fat,
-	}, nil
+		FoodID:          in.FoodID,
+		FoodVersion:     foodVersion,
+		RecipeID:        in.RecipeID,
+		WeightG:         weight,
+		Servings:        in.Servings,
+		CookedWeightG:   in.CookedWeightG,
+		CookingMethodID: in.CookingMethodID,
+		WaterChangeG:    waterChange,
+		KcalPer100g:     kcal,
+		ProteinPer100g:  protein,
+		CarbsPer100g:    carbs,
+		FatPer100g:      fat,
+	}, source, nil
 }
 
 func (s *MealService) GetDailyTotals(ctx context.Context, userID uint, date string) (DailyTotalsOutput, error) {
@@ -384,3 +521,10 @@ func isValidMealType(value meal.MealType) bool {
 		return false
 	}
 }
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
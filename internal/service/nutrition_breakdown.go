@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
+	"goal-bite-api/internal/domain/meal"
+	"goal-bite-api/internal/domain/recipe"
+)
+
+// NutritionBreakdown splits the nutrition of a recipe batch or a meal by
+// ingredient or item. Total is the sum of the items, and CalorieSplit the
+// share of the energy from macros that each macro provides.
+type NutritionBreakdown struct {
+	Items        []BreakdownItem `json:"items"`
+	Total        NutrientAmounts `json:"total"`
+	CalorieSplit MacroSplit      `json:"calorie_split"`
+}
+
+// BreakdownItem is what one recipe ingredient or meal item contributes.
+// ID is the ingredient or meal item, and exactly one of FoodID and RecipeID
+// is set. Name is left empty for foods the caller may not see. Share holds
+// the item's percentage of each total.
+type BreakdownItem struct {
+	ID           uint                 `json:"id"`
+	FoodID       *uint                `json:"food_id,omitempty"`
+	RecipeID     *uint                `json:"recipe_id,omitempty"`
+	Name         string               `json:"name"`
+	DisplayName  string               `json:"display_name"`
+	Translations []locale.Translation `json:"-"`
+	WeightG      float64              `json:"weight_g"`
+	Contribution NutrientAmounts      `json:"contribution"`
+	Share        NutrientShares       `json:"share"`
+}
+
+// NutrientAmounts are the nutrients of an amount of food.
+type NutrientAmounts struct {
+	Kcal     float64 `json:"kcal"`
+	ProteinG float64 `json:"protein_g"`
+	CarbsG   float64 `json:"carbs_g"`
+	FatG     float64 `json:"fat_g"`
+}
+
+// NutrientShares are percentages of each nutrient total.
+type NutrientShares struct {
+	KcalPct    float64 `json:"kcal_pct"`
+	ProteinPct float64 `json:"protein_pct"`
+	CarbsPct   float64 `json:"carbs_pct"`
+	FatPct     float64 `json:"fat_pct"`
+}
+
+// MacroSplit is the percentage of the energy from macros that comes from
+// each of them, using the Atwater factors.
+type MacroSplit struct {
+	ProteinPct float64 `json:"protein_pct"`
+	CarbsPct   float64 `json:"carbs_pct"`
+	FatPct     float64 `json:"fat_pct"`
+}
+
+// Localize sets each item's DisplayName to its translation in the first of
+// languages it has one in, falling back to Name.
+func (b *NutritionBreakdown) Localize(languages []string) {
+	for i := range b.Items {
+		b.Items[i].DisplayName = locale.Pick(b.Items[i].Name, b.Items[i].Translations, languages)
+	}
+}
+
+// Breakdown returns recipe id with the contribution of each ingredient to
+// the whole batch. Contributions come from the current values of the
+// ingredient foods and the stored values of sub-recipes, so they add up to
+// the batch nutrition unless the recipe is stale.
+func (s *RecipeService) Breakdown(ctx context.Context, userID, id uint) (recipe.Recipe, NutritionBreakdown, error) {
+	value, err := s.GetByID(ctx, id)
+	if err != nil {
+		return recipe.Recipe{}, NutritionBreakdown{}, err
+	}
+
+	var foodIDs, recipeIDs []uint
+	for _, item := range value.Ingredients {
+		if item.FoodID != nil {
+			foodIDs = append(foodIDs, *item.FoodID)
+		}
+		if item.SubRecipeID != nil {
+			recipeIDs = append(recipeIDs, *item.SubRecipeID)
+		}
+	}
+	foods, recipes, err := loadSources(ctx, s.foodReader, s.repo, foodIDs, recipeIDs)
+	if err != nil {
+		return recipe.Recipe{}, NutritionBreakdown{}, err
+	}
+
+	items := make([]BreakdownItem, 0, len(value.Ingredients))
+	for _, ingredient := range value.Ingredients {
+		item := BreakdownItem{ID: ingredient.ID, FoodID: ingredient.FoodID, RecipeID: ingredient.SubRecipeID, WeightG: ingredient.RawWeightG}
+		if ingredient.FoodID != nil {
+			if f, ok := foods[*ingredient.FoodID]; ok {
+				item.Contribution = amountOf(f.KcalPer100g, f.ProteinPer100g, f.CarbsPer100g, f.FatPer100g, ingredient.RawWeightG)
+				if f.VisibleTo(userID) {
+					item.Name, item.Translations = f.Name, f.Translations
+				}
+			}
+		}
+		if ingredient.SubRecipeID != nil {
+			if sub, ok := recipes[*ingredient.SubRecipeID]; ok {
+				item.Contribution = amountOf(sub.KcalPer100g, sub.ProteinPer100g, sub.CarbsPer100g, sub.FatPer100g, ingredient.RawWeightG)
+				item.Name, item.Translations = sub.Name, sub.Translations
+			}
+		}
+		items = append(items, item)
+	}
+	return value, newBreakdown(items), nil
+}
+
+// Breakdown returns meal id with the contribution of each item, from the
+// values logged with it.
+func (s *MealService) Breakdown(ctx context.Context, userID, id uint) (meal.Meal, NutritionBreakdown, error) {
+	value, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return meal.Meal{}, NutritionBreakdown{}, err
+	}
+
+	var foodIDs, recipeIDs []uint
+	for _, item := range value.Items {
+		if item.FoodID != nil {
+			foodIDs = append(foodIDs, *item.FoodID)
+		}
+		if item.RecipeID != nil {
+			recipeIDs = append(recipeIDs, *item.RecipeID)
+		}
+	}
+	foods, recipes, err := loadSources(ctx, s.foodReader, s.recipeReader, foodIDs, recipeIDs)
+	if err != nil {
+		return meal.Meal{}, NutritionBreakdown{}, err
+	}
+
+	items := make([]BreakdownItem, 0, len(value.Items))
+	for _, logged := range value.Items {
+		item := BreakdownItem{
+			ID:           logged.ID,
+			FoodID:       logged.FoodID,
+			RecipeID:     logged.RecipeID,
+			WeightG:      logged.WeightG,
+			Contribution: amountOf(logged.KcalPer100g, logged.ProteinPer100g, logged.CarbsPer100g, logged.FatPer100g, logged.WeightG),
+		}
+		if logged.FoodID != nil {
+			if f, ok := foods[*logged.FoodID]; ok && f.VisibleTo(userID) {
+				item.Name, item.Translations = f.Name, f.Translations
+			}
+		}
+		if logged.RecipeID != nil {
+			if r, ok := recipes[*logged.RecipeID]; ok {
+				item.Name, item.Translations = r.Name, r.Translations
+			}
+		}
+		items = append(items, item)
+	}
+	return value, newBreakdown(items), nil
+}
+
+type recipesByID interface {
+	ListByIDs(ctx context.Context, ids []uint) ([]recipe.Recipe, error)
+}
+
+// loadSources fetches the foods and recipes items refer to, in one query
+// each, keyed by ID.
+func loadSources(ctx context.Context, foodReader FoodReader, recipeReader recipesByID, foodIDs, recipeIDs []uint) (map[uint]food.Food, map[uint]recipe.Recipe, error) {
+	foods := make(map[uint]food.Food, len(foodIDs))
+	if len(foodIDs) > 0 {
+		values, err := foodReader.ListByIDs(ctx, foodIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range values {
+			foods[f.ID] = f
+		}
+	}
+	recipes := make(map[uint]recipe.Recipe, len(recipeIDs))
+	if len(recipeIDs) > 0 {
+		values, err := recipeReader.ListByIDs(ctx, recipeIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, r := range values {
+			recipes[r.ID] = r
+		}
+	}
+	return foods, recipes, nil
+}
+
+func amountOf(kcal, protein, carbs, fat, weightG float64) NutrientAmounts {
+	ratio := weightG / 100
+	return NutrientAmounts{Kcal: kcal * ratio, ProteinG: protein * ratio, CarbsG: carbs * ratio, FatG: fat * ratio}
+}
+
+// newBreakdown totals items and sets their shares.
+func newBreakdown(items []BreakdownItem) NutritionBreakdown {
+	var total NutrientAmounts
+	for _, item := range items {
+		total.Kcal += item.Contribution.Kcal
+		total.ProteinG += item.Contribution.ProteinG
+		total.CarbsG += item.Contribution.CarbsG
+		total.FatG += item.Contribution.FatG
+	}
+	for i, item := range items {
+		items[i].Share = NutrientShares{
+			KcalPct:    percentOf(item.Contribution.Kcal, total.Kcal),
+			ProteinPct: percentOf(item.Contribution.ProteinG, total.ProteinG),
+			CarbsPct:   percentOf(item.Contribution.CarbsG, total.CarbsG),
+			FatPct:     percentOf(item.Contribution.FatG, total.FatG),
+		}
+	}
+
+	proteinKcal := total.ProteinG * food.KcalPerGramProtein
+	carbsKcal := total.CarbsG * food.KcalPerGramCarbs
+	fatKcal := total.FatG * food.KcalPerGramFat
+	macroKcal := proteinKcal + carbsKcal + fatKcal
+	return NutritionBreakdown{
+		Items: items,
+		Total: total,
+		CalorieSplit: MacroSplit{
+			ProteinPct: percentOf(proteinKcal, macroKcal),
+			CarbsPct:   percentOf(carbsKcal, macroKcal),
+			FatPct:     percentOf(fatKcal, macroKcal),
+		},
+	}
+}
+
+// percentOf returns part as a percentage of total, 0 when the total is.
+func percentOf(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return part / total * 100
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"goal-bite-api/internal/auth"
+	"goal-bite-api/internal/domain/user"
+	"goal-bite-api/internal/oidc"
+	"goal-bite-api/internal/repository"
+)
+
+var (
+	ErrOIDCProviderNotFound    = errors.New("oidc provider not found")
+	ErrOIDCProviderUnavailable = errors.New("oidc provider unavailable")
+	ErrInvalidOIDCState        = errors.New("invalid oidc state")
+	ErrOIDCLoginFailed         = errors.New("oidc login failed")
+	ErrOIDCEmailNotVerified    = errors.New("oidc email not verified")
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCProvider is an external identity provider speaking the authorization
+// code flow with PKCE.
+type OIDCProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier string) (string, error)
+	VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (oidc.Claims, error)
+}
+
+type OIDCUserStore interface {
+	GetByID(ctx context.Context, id uint) (user.User, error)
+	GetByEmail(ctx context.Context, email string) (user.User, error)
+	Create(ctx context.Context, value user.User) (user.User, error)
+}
+
+type OIDCIdentityStore interface {
+	Create(ctx context.Context, in repository.CreateOIDCIdentityInput) (repository.OIDCIdentity, error)
+	GetByProviderSubject(ctx context.Context, provider, subject string) (repository.OIDCIdentity, error)
+}
+
+type OIDCLoginStateStore interface {
+	Create(ctx context.Context, in repository.CreateOIDCLoginStateInput) (repository.OIDCLoginState, error)
+	Consume(ctx context.Context, stateHash, provider string, now time.Time) (repository.OIDCLoginState, error)
+}
+
+type OIDCLoginStart struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type OIDCService struct {
+	users      OIDCUserStore
+	identities OIDCIdentityStore
+	states     OIDCLoginStateStore
+	sessions   AuthSessionStore
+	tokens     TokenIssuer
+	providers  map[string]OIDCProvider
+}
+
+func NewOIDCService(users OIDCUserStore, identities OIDCIdentityStore, states OIDCLoginStateStore, sessions AuthSessionStore, tokens TokenIssuer, providers ...OIDCProvider) *OIDCService {
+	byName := make(map[string]OIDCProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &OIDCService{
+		users:      users,
+		identities: identities,
+		states:     states,
+		sessions:   sessions,
+		tokens:     tokens,
+		providers:  byName,
+	}
+}
+
+// Providers returns the configured provider names in sorted order.
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginLogin stores a fresh state, nonce and PKCE verifier and returns the
+// provider URL the client should navigate to.
+func (s *OIDCService) BeginLogin(ctx context.Context, providerName string) (OIDCLoginStart, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return OIDCLoginStart{}, ErrOIDCProviderNotFound
+	}
+	state, err := generateRefreshToken()
+	if err != nil {
+		return OIDCLoginStart{}, err
+	}
+	nonce, err := generateRefreshToken()
+	if err != nil {
+		return OIDCLoginStart{}, err
+	}
+	verifier, err := oidc.NewPKCEVerifier()
+	if err != nil {
+		return OIDCLoginStart{}, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.PKCEChallenge(verifier))
+	if errors.Is(err, oidc.ErrProviderUnavailable) {
+		return OIDCLoginStart{}, ErrOIDCProviderUnavailable
+	}
+	if err != nil {
+		return OIDCLoginStart{}, err
+	}
+	if _, err := s.states.Create(ctx, repository.CreateOIDCLoginStateInput{
+		StateHash:    hashRefreshToken(state),
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().UTC().Add(oidcStateTTL),
+	}); err != nil {
+		return OIDCLoginStart{}, err
+	}
+	return OIDCLoginStart{AuthorizationURL: authURL}, nil
+}
+
+// FinishLogin redeems the code returned to the client, resolves the local
+// account and issues the normal token pair. An identity already linked to a
+// user wins; otherwise a verified email links to the matching account or
+// creates a new one.
+func (s *OIDCService) FinishLogin(ctx context.Context, providerName, code, state string) (AuthResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return AuthResult{}, ErrOIDCProviderNotFound
+	}
+	code = strings.TrimSpace(code)
+	state = strings.TrimSpace(state)
+	if code == "" || state == "" {
+		return AuthResult{}, ErrInvalidOIDCState
+	}
+
+	pending, err := s.states.Consume(ctx, hashRefreshToken(state), providerName, time.Now().UTC())
+	if errors.Is(err, repository.ErrNotFound) {
+		return AuthResult{}, ErrInvalidOIDCState
+	}
+	if err != nil {
+		return AuthResult{}, err
+	}
+
+	idToken, err := provider.Exchange(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		return AuthResult{}, mapOIDCProviderError(err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, idToken, pending.Nonce)
+	if err != nil {
+		return AuthResult{}, mapOIDCProviderError(err)
+	}
+
+	u, err := s.resolveUser(ctx, providerName, claims)
+	if err != nil {
+		return AuthResult{}, err
+	}
+	return issueAuthResult(ctx, s.tokens, s.sessions, u)
+}
+
+func (s *OIDCService) resolveUser(ctx context.Context, providerName string, claims oidc.Claims) (user.User, error) {
+	identity, err := s.identities.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		return s.linkedUser(ctx, identity)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return user.User{}, err
+	}
+
+	// Linking by email is only safe when the provider vouches for it.
+	if !claims.EmailVerified {
+		return user.User{}, ErrOIDCEmailNotVerified
+	}
+	email, err := auth.NormalizeEmail(claims.Email)
+	if err != nil {
+		return user.User{}, ErrOIDCEmailNotVerified
+	}
+
+	u, err := s.users.GetByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Accounts created here have no password; they sign in through the
+		// provider, a magic link or a passkey.
+		u, err = s.users.Create(ctx, user.User{
+			Name:  oidcDisplayName(claims, email),
+			Email: email,
+		})
+		if errors.Is(err, repository.ErrConflict) {
+			// A concurrent first login created the account first.
+			u, err = s.users.GetByEmail(ctx, email)
+		}
+	}
+	if err != nil {
+		return user.User{}, err
+	}
+
+	_, err = s.identities.Create(ctx, repository.CreateOIDCIdentityInput{
+		UserID:   u.ID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    email,
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		// A concurrent first login linked the subject first; sign in as
+		// whoever it was linked to.
+		identity, err := s.identities.GetByProviderSubject(ctx, providerName, claims.Subject)
+		if err != nil {
+			return user.User{}, err
+		}
+		return s.linkedUser(ctx, identity)
+	}
+	if err != nil {
+		return user.User{}, err
+	}
+	return u, nil
+}
+
+func (s *OIDCService) linkedUser(ctx context.Context, identity repository.OIDCIdentity) (user.User, error) {
+	u, err := s.users.GetByID(ctx, identity.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return user.User{}, ErrOIDCLoginFailed
+	}
+	return u, err
+}
+
+func mapOIDCProviderError(err error) error {
+	switch {
+	case errors.Is(err, oidc.ErrProviderUnavailable):
+		return ErrOIDCProviderUnavailable
+	case errors.Is(err, oidc.ErrExchangeRejected), errors.Is(err, oidc.ErrInvalidIDToken):
+		return ErrOIDCLoginFailed
+	}
+	return err
+}
+
+func oidcDisplayName(claims oidc.Claims, email string) string {
+	if name := strings.TrimSpace(claims.Name); name != "" {
+		return name
+	}
+	local, _, _ := strings.Cut(email, "@")
+	return local
+}
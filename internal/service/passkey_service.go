@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"goal-bite-api/internal/domain/passkey"
+	"goal-bite-api/internal/domain/user"
+	"goal-bite-api/internal/repository"
+	"goal-bite-api/internal/webauthn"
+)
+
+var (
+	ErrPasskeyNotFound          = errors.New("passkey not found")
+	ErrPasskeyForbidden         = errors.New("passkey forbidden")
+	ErrPasskeyAlreadyRegistered = errors.New("passkey already registered")
+	ErrInvalidPasskeyName       = errors.New("invalid passkey name")
+	ErrInvalidPasskeyResponse   = errors.New("invalid passkey response")
+	ErrPasskeyLoginFailed       = errors.New("passkey login failed")
+)
+
+const (
+	webAuthnPurposeRegistration = "registration"
+	webAuthnPurposeLogin        = "login"
+	maxPasskeyNameLength        = 100
+)
+
+type PasskeyUserStore interface {
+	GetByID(ctx context.Context, id uint) (user.User, error)
+}
+
+type PasskeyStore interface {
+	Create(ctx context.Context, value passkey.Passkey) (passkey.Passkey, error)
+	GetByID(ctx context.Context, id uint) (passkey.Passkey, error)
+	GetByCredentialID(ctx context.Context, credentialID string) (passkey.Passkey, error)
+	ListByUserID(ctx context.Context, userID uint) ([]passkey.Passkey, error)
+	RecordUse(ctx context.Context, id uint, previousCount, signCount uint32, at time.Time) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type WebAuthnChallengeStore interface {
+	Create(ctx context.Context, in repository.CreateWebAuthnChallengeInput) (repository.WebAuthnChallenge, error)
+	Consume(ctx context.Context, challenge, purpose string, now time.Time) (repository.WebAuthnChallenge, error)
+}
+
+type PasskeyService struct {
+	users      PasskeyUserStore
+	passkeys   PasskeyStore
+	challenges WebAuthnChallengeStore
+	sessions   AuthSessionStore
+	tokens     TokenIssuer
+	cfg        webauthn.Config
+}
+
+type FinishPasskeyRegistrationInput struct {
+	Name       string
+	Credential webauthn.RegistrationResponse
+}
+
+func NewPasskeyService(users PasskeyUserStore, passkeys PasskeyStore, challenges WebAuthnChallengeStore, sessions AuthSessionStore, tokens TokenIssuer, cfg webauthn.Config) *PasskeyService {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Minute
+	}
+	return &PasskeyService{
+		users:      users,
+		passkeys:   passkeys,
+		challenges: challenges,
+		sessions:   sessions,
+		tokens:     tokens,
+		cfg:        cfg,
+	}
+}
+
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userID uint) (webauthn.CreationOptions, error) {
+	if userID == 0 {
+		return webauthn.CreationOptions{}, ErrInvalidUserID
+	}
+	u, err := s.users.GetByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return webauthn.CreationOptions{}, ErrUserNotFound
+	}
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+	existing, err := s.passkeys.ListByUserID(ctx, userID)
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+
+	challenge, err := s.issueChallenge(ctx, webAuthnPurposeRegistration, &userID)
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+
+	exclude := make([]webauthn.CredentialDescriptor, 0, len(existing))
+	for _, p := range existing {
+		exclude = append(exclude, webauthn.CredentialDescriptor{
+			Type:       "public-key",
+			ID:         p.CredentialID,
+			Transports: p.Transports,
+		})
+	}
+	return s.cfg.CreationOptions(challenge, webauthn.UserEntity{
+		ID:          webauthn.EncodeBase64URL(passkeyUserHandle(u.ID)),
+		Name:        u.Email,
+		DisplayName: u.Name,
+	}, exclude), nil
+}
+
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID uint, in FinishPasskeyRegistrationInput) (passkey.Passkey, error) {
+	if userID == 0 {
+		return passkey.Passkey{}, ErrInvalidUserID
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len(name) > maxPasskeyNameLength {
+		return passkey.Passkey{}, ErrInvalidPasskeyName
+	}
+
+	challenge, err := s.consumeChallenge(ctx, in.Credential.Response.ClientDataJSON, webAuthnPurposeRegistration)
+	if err != nil {
+		return passkey.Passkey{}, err
+	}
+	if challenge.UserID == nil || *challenge.UserID != userID {
+		return passkey.Passkey{}, ErrInvalidPasskeyResponse
+	}
+	rawChallenge, err := webauthn.DecodeBase64URL(challenge.Challenge)
+	if err != nil {
+		return passkey.Passkey{}, ErrInvalidPasskeyResponse
+	}
+
+	credential, err := s.cfg.VerifyRegistration(in.Credential, rawChallenge)
+	if err != nil {
+		return passkey.Passkey{}, ErrInvalidPasskeyResponse
+	}
+
+	credentialID := webauthn.EncodeBase64URL(credential.ID)
+	if _, err := s.passkeys.GetByCredentialID(ctx, credentialID); err == nil {
+		return passkey.Passkey{}, ErrPasskeyAlreadyRegistered
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return passkey.Passkey{}, err
+	}
+
+	created, err := s.passkeys.Create(ctx, passkey.Passkey{
+		UserID:       userID,
+		Name:         name,
+		CredentialID: credentialID,
+		PublicKey:    credential.PublicKey,
+		SignCount:    credential.SignCount,
+		Transports:   credential.Transports,
+	})
+	if err != nil {
+		return passkey.Passkey{}, err
+	}
+	created.PublicKey = nil
+	return created, nil
+}
+
+func (s *PasskeyService) BeginLogin(ctx context.Context) (webauthn.RequestOptions, error) {
+	challenge, err := s.issueChallenge(ctx, webAuthnPurposeLogin, nil)
+	if err != nil {
+		return webauthn.RequestOptions{}, err
+	}
+	return s.cfg.RequestOptions(challenge), nil
+}
+
+// FinishLogin verifies an assertion and issues the same tokens as password
+// login. Every verification failure maps to ErrPasskeyLoginFailed.
+func (s *PasskeyService) FinishLogin(ctx context.Context, assertion webauthn.AssertionResponse) (AuthResult, error) {
+	challenge, err := s.consumeChallenge(ctx, assertion.Response.ClientDataJSON, webAuthnPurposeLogin)
+	if errors.Is(err, ErrInvalidPasskeyResponse) {
+		return AuthResult{}, ErrPasskeyLoginFailed
+	}
+	if err != nil {
+		return AuthResult{}, err
+	}
+	rawChallenge, err := webauthn.DecodeBase64URL(challenge.Challenge)
+	if err != nil {
+		return AuthResult{}, ErrPasskeyLoginFailed
+	}
+
+	rawID, err := webauthn.DecodeBase64URL(assertion.RawID)
+	if err != nil || len(rawID) == 0 {
+		return AuthResult{}, ErrPasskeyLoginFailed
+	}
+	stored, err := s.passkeys.GetByCredentialID(ctx, webauthn.EncodeBase64URL(rawID))
+	if errors.Is(err, repository.ErrNotFound) {
+		return AuthResult{}, ErrPasskeyLoginFailed
+	}
+	if err != nil {
+		return AuthResult{}, err
+	}
+	if assertion.Response.UserHandle != "" {
+		handle, err := webauthn.DecodeBase64URL(assertion.Response.UserHandle)
+		if err != nil || string(handle) != string(passkeyUserHandle(stored.UserID)) {
+			return AuthResult{}, ErrPasskeyLoginFailed
+		}
+	}
+
+	signCount, err := s.cfg.VerifyAssertion(assertion, rawChallenge, webauthn.Credential{
+		ID:        rawID,
+		PublicKey: stored.PublicKey,
+		SignCount: stored.SignCount,
+	})
+	if err != nil {
+		return AuthResult{}, ErrPasskeyLoginFailed
+	}
+	err = s.passkeys.RecordUse(ctx, stored.ID, stored.SignCount, signCount, time.Now().UTC())
+	if errors.Is(err, repository.ErrNotFound) {
+		return AuthResult{}, ErrPasskeyLoginFailed
+	}
+	if err != nil {
+		return AuthResult{}, err
+	}
+
+	u, err := s.users.GetByID(ctx, stored.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return AuthResult{}, ErrPasskeyLoginFailed
+	}
+	if err != nil {
+		return AuthResult{}, err
+	}
+	return issueAuthResult(ctx, s.tokens, s.sessions, u)
+}
+
+func (s *PasskeyService) List(ctx context.Context, userID uint) ([]passkey.Passkey, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	return s.passkeys.ListByUserID(ctx, userID)
+}
+
+func (s *PasskeyService) Delete(ctx context.Context, userID, id uint) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	existing, err := s.passkeys.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrPasskeyNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if existing.UserID != userID {
+		return ErrPasskeyForbidden
+	}
+	err = s.passkeys.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrPasskeyNotFound
+	}
+	return err
+}
+
+func (s *PasskeyService) issueChallenge(ctx context.Context, purpose string, userID *uint) ([]byte, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.challenges.Create(ctx, repository.CreateWebAuthnChallengeInput{
+		Challenge: webauthn.EncodeBase64URL(challenge),
+		Purpose:   purpose,
+		UserID:    userID,
+		ExpiresAt: time.Now().UTC().Add(s.cfg.Timeout),
+	}); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// consumeChallenge resolves the challenge echoed in clientDataJSON to the one
+// issued by a begin call; each challenge can be used once.
+func (s *PasskeyService) consumeChallenge(ctx context.Context, clientDataJSON, purpose string) (repository.WebAuthnChallenge, error) {
+	clientData, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil || clientData.Challenge == "" {
+		return repository.WebAuthnChallenge{}, ErrInvalidPasskeyResponse
+	}
+	challenge, err := s.challenges.Consume(ctx, strings.TrimRight(clientData.Challenge, "="), purpose, time.Now().UTC())
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.WebAuthnChallenge{}, ErrInvalidPasskeyResponse
+	}
+	if err != nil {
+		return repository.WebAuthnChallenge{}, err
+	}
+	return challenge, nil
+}
+
+// passkeyUserHandle is the opaque WebAuthn user.id for an account.
+func passkeyUserHandle(userID uint) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(userID))
+}
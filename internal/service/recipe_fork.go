@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/recipeingredient"
+	"goal-bite-api/internal/repository"
+)
+
+var ErrRecipeNotForked = errors.New("recipe not forked")
+
+// Ingredient changes of a fork relative to its original.
+const (
+	IngredientAdded   = "added"
+	IngredientRemoved = "removed"
+	IngredientChanged = "changed"
+)
+
+// RecipeFork is a fork of a recipe together with what it changed.
+type RecipeFork struct {
+	Recipe  recipe.Recipe `json:"recipe"`
+	Changes RecipeChanges `json:"changes"`
+}
+
+// RecipeChanges compares a fork with the recipe it was copied from. Fields
+// lists the changed recipe fields by their JSON names, and Per100g holds
+// the fork's per-100g values minus the original's.
+type RecipeChanges struct {
+	OriginID    uint               `json:"origin_id"`
+	Fields      []string           `json:"fields"`
+	Ingredients []IngredientChange `json:"ingredients"`
+	Per100g     NutritionDelta     `json:"per_100g"`
+}
+
+// IngredientChange is an ingredient the fork added, removed or changed.
+// Ingredients are matched by their food or sub-recipe, in order, so a food
+// used twice is compared occurrence by occurrence. From values are the
+// original's and To values the fork's.
+type IngredientChange struct {
+	Change         string   `json:"change"`
+	FoodID         *uint    `json:"food_id,omitempty"`
+	SubRecipeID    *uint    `json:"sub_recipe_id,omitempty"`
+	FromRawWeightG *float64 `json:"from_raw_weight_g,omitempty"`
+	ToRawWeightG   *float64 `json:"to_raw_weight_g,omitempty"`
+	FromNote       string   `json:"from_note,omitempty"`
+	ToNote         string   `json:"to_note,omitempty"`
+}
+
+// NutritionDelta is a difference of per-100g values.
+type NutritionDelta struct {
+	Kcal     float64 `json:"kcal"`
+	ProteinG float64 `json:"protein_g"`
+	CarbsG   float64 `json:"carbs_g"`
+	FatG     float64 `json:"fat_g"`
+}
+
+// Fork copies recipe id with its ingredients and details into userID's
+// ownership, recording where it came from. The copy keeps every ingredient
+// of the original, even archived ones or foods the caller cannot see, and
+// is recalculated from their current values. Images are not copied.
+func (s *RecipeService) Fork(ctx context.Context, userID, id uint) (recipe.Recipe, error) {
+	if userID == 0 {
+		return recipe.Recipe{}, ErrInvalidUserID
+	}
+	source, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeNotFound
+	}
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	if source.Archived() {
+		return recipe.Recipe{}, ErrRecipeArchived
+	}
+
+	nutrition, err := s.calculatePer100g(ctx, userID, 0, statedYield(source), toServiceIngredients(source.Ingredients), sourcesOf(source.Ingredients), recipeingredient.MaxDepth)
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	origin := source.ID
+	return s.repo.Create(ctx, repository.RecipeCreate{
+		UserID:         userID,
+		ForkedFromID:   &origin,
+		Name:           source.Name,
+		YieldWeightG:   nutrition.yieldWeightG,
+		YieldEstimated: nutrition.yieldEstimated,
+		Servings:       source.Servings,
+		ServingName:    source.ServingName,
+		Description:    source.Description,
+		PrepTimeMin:    source.PrepTimeMin,
+		CookTimeMin:    source.CookTimeMin,
+		Tags:           source.Tags,
+		Instructions:   source.Instructions,
+		KcalPer100g:    nutrition.kcal,
+		ProteinPer100g: nutrition.protein,
+		CarbsPer100g:   nutrition.carbs,
+		FatPer100g:     nutrition.fat,
+		Ingredients:    nutrition.ingredients,
+		Translations:   source.Translations,
+	})
+}
+
+// Forks lists the active forks of recipe id, each with its changes.
+func (s *RecipeService) Forks(ctx context.Context, id uint, limit, offset int) ([]RecipeFork, error) {
+	if !IsValidPagination(limit, offset) {
+		return nil, ErrInvalidPagination
+	}
+	origin, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	forks, err := s.repo.ListForks(ctx, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RecipeFork, 0, len(forks))
+	for _, fork := range forks {
+		out = append(out, RecipeFork{Recipe: fork, Changes: compareRecipes(origin, fork)})
+	}
+	return out, nil
+}
+
+// Changes compares fork id with the recipe it was copied from.
+func (s *RecipeService) Changes(ctx context.Context, id uint) (RecipeChanges, error) {
+	fork, err := s.GetByID(ctx, id)
+	if err != nil {
+		return RecipeChanges{}, err
+	}
+	if fork.ForkedFromID == nil {
+		return RecipeChanges{}, ErrRecipeNotForked
+	}
+	origin, err := s.repo.GetByID(ctx, *fork.ForkedFromID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return RecipeChanges{}, ErrRecipeNotForked
+	}
+	if err != nil {
+		return RecipeChanges{}, err
+	}
+	return compareRecipes(origin, fork), nil
+}
+
+func compareRecipes(origin, fork recipe.Recipe) RecipeChanges {
+	out := RecipeChanges{
+		OriginID:    origin.ID,
+		Fields:      []string{},
+		Ingredients: []IngredientChange{},
+		Per100g: NutritionDelta{
+			Kcal:     fork.KcalPer100g - origin.KcalPer100g,
+			ProteinG: fork.ProteinPer100g - origin.ProteinPer100g,
+			CarbsG:   fork.CarbsPer100g - origin.CarbsPer100g,
+			FatG:     fork.FatPer100g - origin.FatPer100g,
+		},
+	}
+	fields := []struct {
+		name    string
+		changed bool
+	}{
+		{"name", fork.Name != origin.Name},
+		{"yield_weight_g", fork.YieldWeightG != origin.YieldWeightG},
+		{"servings", fork.Servings != origin.Servings},
+		{"serving_name", fork.ServingName != origin.ServingName},
+		{"description", fork.Description != origin.Description},
+		{"prep_time_min", fork.PrepTimeMin != origin.PrepTimeMin},
+		{"cook_time_min", fork.CookTimeMin != origin.CookTimeMin},
+		{"tags", !slices.Equal(fork.Tags, origin.Tags)},
+		{"instructions", !slices.Equal(fork.Instructions, origin.Instructions)},
+	}
+	for _, f := range fields {
+		if f.changed {
+			out.Fields = append(out.Fields, f.name)
+		}
+	}
+
+	// Pair each original ingredient with the next unused fork ingredient of
+	// the same source.
+	type source struct {
+		food, recipe uint
+	}
+	key := func(item recipeingredient.RecipeIngredient) source {
+		var k source
+		if item.FoodID != nil {
+			k.food = *item.FoodID
+		}
+		if item.SubRecipeID != nil {
+			k.recipe = *item.SubRecipeID
+		}
+		return k
+	}
+	unmatched := map[source][]int{}
+	for i, item := range fork.Ingredients {
+		unmatched[key(item)] = append(unmatched[key(item)], i)
+	}
+	matched := make([]bool, len(fork.Ingredients))
+	for _, before := range origin.Ingredients {
+		k := key(before)
+		if len(unmatched[k]) == 0 {
+			out.Ingredients = append(out.Ingredients, IngredientChange{
+				Change:         IngredientRemoved,
+				FoodID:         before.FoodID,
+				SubRecipeID:    before.SubRecipeID,
+				FromRawWeightG: &before.RawWeightG,
+				FromNote:       before.Note,
+			})
+			continue
+		}
+		i := unmatched[k][0]
+		unmatched[k] = unmatched[k][1:]
+		matched[i] = true
+		after := fork.Ingredients[i]
+		if after.RawWeightG != before.RawWeightG || after.Note != before.Note {
+			out.Ingredients = append(out.Ingredients, IngredientChange{
+				Change:         IngredientChanged,
+				FoodID:         after.FoodID,
+				SubRecipeID:    after.SubRecipeID,
+				FromRawWeightG: &before.RawWeightG,
+				ToRawWeightG:   &after.RawWeightG,
+				FromNote:       before.Note,
+				ToNote:         after.Note,
+			})
+		}
+	}
+	for i, after := range fork.Ingredients {
+		if !matched[i] {
+			out.Ingredients = append(out.Ingredients, IngredientChange{
+				Change:       IngredientAdded,
+				FoodID:       after.FoodID,
+				SubRecipeID:  after.SubRecipeID,
+				ToRawWeightG: &after.RawWeightG,
+				ToNote:       after.Note,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/recipeimport"
+	"goal-bite-api/internal/repository"
+)
+
+var ErrInvalidRecipeImport = errors.New("invalid recipe import")
+
+const (
+	// ImportMatchThreshold is the lowest confidence at which an imported
+	// ingredient is linked to a food without the user picking one.
+	ImportMatchThreshold = 0.6
+	minImportConfidence  = 0.3
+	maxImportMatches     = 3
+	importCandidateLimit = 50
+)
+
+// FoodCandidateFinder lists foods visible to a user whose names loosely
+// match some terms, leaving scoring to the caller.
+type FoodCandidateFinder interface {
+	ListDuplicateCandidates(ctx context.Context, q repository.DuplicateCandidateQuery) ([]food.Food, error)
+}
+
+// RecipeDraft is an imported recipe for the user to review before creating
+// it. YieldWeightG is the total raw weight of the ingredients with a known
+// weight, to be replaced with the cooked weight.
+type RecipeDraft struct {
+	Name         string            `json:"name"`
+	YieldWeightG float64           `json:"yield_weight_g"`
+	Servings     int               `json:"servings"`
+	Ingredients  []DraftIngredient `json:"ingredients"`
+}
+
+// DraftIngredient is one imported ingredient line. FoodID is the best match
+// when its confidence reaches ImportMatchThreshold; otherwise the user picks
+// one of Matches or searches the catalog. RawWeightG is missing for counted
+// items such as "2 eggs" and approximate for volumes.
+type DraftIngredient struct {
+	Text              string      `json:"text"`
+	Name              string      `json:"name"`
+	Quantity          *float64    `json:"quantity,omitempty"`
+	Unit              string      `json:"unit,omitempty"`
+	RawWeightG        *float64    `json:"raw_weight_g,omitempty"`
+	ApproximateWeight bool        `json:"approximate_weight"`
+	FoodID            *uint       `json:"food_id,omitempty"`
+	Matches           []FoodMatch `json:"matches"`
+}
+
+// FoodMatch is a catalog food an imported ingredient may be, with a
+// confidence between 0 and 1.
+type FoodMatch struct {
+	Food       food.Food `json:"food"`
+	Confidence float64   `json:"confidence"`
+}
+
+// Import parses a web page, schema.org Recipe JSON-LD or a pasted
+// ingredient list into a draft and matches each ingredient against the foods
+// userID can see. Nothing is saved.
+func (s *RecipeService) Import(ctx context.Context, userID uint, content string) (RecipeDraft, error) {
+	if userID == 0 {
+		return RecipeDraft{}, ErrInvalidUserID
+	}
+	parsed, err := recipeimport.Parse(content)
+	if errors.Is(err, recipeimport.ErrNoRecipe) || errors.Is(err, recipeimport.ErrTooManyIngredients) {
+		return RecipeDraft{}, ErrInvalidRecipeImport
+	}
+	if err != nil {
+		return RecipeDraft{}, err
+	}
+
+	out := RecipeDraft{
+		Name:        parsed.Name,
+		Servings:    max(parsed.Servings, 1),
+		Ingredients: make([]DraftIngredient, 0, len(parsed.Ingredients)),
+	}
+	for _, ingredient := range parsed.Ingredients {
+		matches, err := s.matchFoods(ctx, userID, ingredient.Name)
+		if err != nil {
+			return RecipeDraft{}, err
+		}
+		draft := DraftIngredient{
+			Text:              ingredient.Text,
+			Name:              ingredient.Name,
+			Quantity:          ingredient.Quantity,
+			Unit:              ingredient.Unit,
+			RawWeightG:        ingredient.WeightG,
+			ApproximateWeight: ingredient.Approximate,
+			Matches:           matches,
+		}
+		if len(matches) > 0 && matches[0].Confidence >= ImportMatchThreshold {
+			id := matches[0].Food.ID
+			draft.FoodID = &id
+		}
+		if draft.RawWeightG != nil {
+			out.YieldWeightG += *draft.RawWeightG
+		}
+		out.Ingredients = append(out.Ingredients, draft)
+	}
+	return out, nil
+}
+
+// matchFoods scores foods sharing a word with name by name similarity,
+// counting translated names too, and returns the best few.
+func (s *RecipeService) matchFoods(ctx context.Context, userID uint, name string) ([]FoodMatch, error) {
+	out := []FoodMatch{}
+	if s.foodFinder == nil {
+		return out, nil
+	}
+	var terms []string
+	for _, token := range nameTokens(name) {
+		if len([]rune(token)) >= minDuplicateTermLength {
+			terms = append(terms, token)
+		}
+	}
+	if len(terms) == 0 {
+		return out, nil
+	}
+
+	candidates, err := s.foodFinder.ListDuplicateCandidates(ctx, repository.DuplicateCandidateQuery{
+		UserID:    userID,
+		NameTerms: terms,
+		Limit:     importCandidateLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		score := nameSimilarity(name, candidate.Name)
+		for _, t := range candidate.Translations {
+			score = math.Max(score, nameSimilarity(name, t.Name))
+		}
+		if score >= minImportConfidence {
+			out = append(out, FoodMatch{Food: candidate, Confidence: math.Round(score*1000) / 1000})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Confidence > out[j].Confidence
+	})
+	if len(out) > maxImportMatches {
+		out = out[:maxImportMatches]
+	}
+	return out, nil
+}
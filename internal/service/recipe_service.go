@@ -3,9 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/domain/recipe"
 	"goal-bite-api/internal/domain/recipeingredient"
 	"goal-bite-api/internal/repository"
@@ -18,46 +22,150 @@ var (
 	ErrInvalidYieldWeight       = errors.New("invalid yield weight")
 	ErrInvalidRecipeIngredients = errors.New("invalid recipe ingredients")
 	ErrIngredientFoodNotFound   = errors.New("ingredient food not found")
+	ErrIngredientFoodArchived   = errors.New("ingredient food archived")
+	ErrRecipeArchived           = errors.New("recipe archived")
+	ErrIngredientRecipeNotFound = errors.New("ingredient recipe not found")
+	ErrIngredientRecipeArchived = errors.New("ingredient recipe archived")
+	ErrRecipeCycle              = errors.New("recipe cycle")
+	ErrRecipeTooDeep            = errors.New("recipe nested too deep")
+	ErrInvalidServings          = errors.New("invalid servings")
+	ErrInvalidServingName       = errors.New("invalid serving name")
+	ErrInvalidRecipeDescription = errors.New("invalid recipe description")
+	ErrInvalidRecipeTime        = errors.New("invalid recipe time")
+	ErrInvalidRecipeTags        = errors.New("invalid recipe tags")
+	ErrInvalidInstructions      = errors.New("invalid recipe instructions")
 )
 
 type RecipeStore interface {
 	Create(ctx context.Context, in repository.RecipeCreate) (recipe.Recipe, error)
 	GetByID(ctx context.Context, id uint) (recipe.Recipe, error)
-	List(ctx context.Context, limit, offset int) ([]recipe.Recipe, error)
-	SearchByName(ctx context.Context, query string, limit, offset int) ([]recipe.Recipe, error)
+	List(ctx context.Context, q repository.RecipeListQuery) ([]recipe.Recipe, error)
+	ListByIDs(ctx context.Context, ids []uint) ([]recipe.Recipe, error)
+	ListForks(ctx context.Context, id uint, limit, offset int) ([]recipe.Recipe, error)
 	Update(ctx context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error)
-	Delete(ctx context.Context, id uint) error
+	Archive(ctx context.Context, id uint, at time.Time) error
+	Restore(ctx context.Context, id uint) (recipe.Recipe, error)
+	SetImage(ctx context.Context, id uint, img *repository.Image) (recipe.Recipe, error)
+	MarkStaleByFood(ctx context.Context, foodID uint) ([]uint, error)
+	MarkStaleByRecipe(ctx context.Context, id uint) ([]uint, error)
+	ListStale(ctx context.Context, afterID uint, limit int) ([]uint, error)
+	ParentDepth(ctx context.Context, id uint, limit int) (int, error)
 }
 
 type FoodReader interface {
 	GetByID(ctx context.Context, id uint) (food.Food, error)
+	ListByIDs(ctx context.Context, ids []uint) ([]food.Food, error)
 }
 
 type RecipeService struct {
 	repo       RecipeStore
 	foodReader FoodReader
+	users      RestrictionReader
+	images     *ImageUploads
+	foodFinder FoodCandidateFinder
+	cooking    CookingFactors
+	syncLimit  int
 }
 
+// DefaultRecipeSyncLimit is how many dependent recipes a food change
+// recalculates right away unless SetSyncLimit says otherwise.
+const DefaultRecipeSyncLimit = 20
+
+// RecipeIngredientInput is an amount of a food or of another recipe; exactly
+// one of FoodID and SubRecipeID is set. CookingMethodID, only for foods, says
+// how the food is cooked.
 type RecipeIngredientInput struct {
-	FoodID     uint
-	RawWeightG float64
-	Position   *int
+	FoodID          uint
+	SubRecipeID     uint
+	RawWeightG      float64
+	Position        *int
+	Note            string
+	CookingMethodID uint
 }
 
+// CreateRecipeInput describes a new recipe. Servings defaults to 1 and
+// ServingName to recipe.DefaultServingName. Times are in minutes, 0 when not
+// given. A YieldWeightG of 0 estimates the yield from the ingredients.
 type CreateRecipeInput struct {
 	Name         string
 	YieldWeightG float64
+	Servings     int
+	ServingName  string
+	Description  string
+	PrepTimeMin  int
+	CookTimeMin  int
+	Tags         []string
+	Instructions []string
 	Ingredients  []RecipeIngredientInput
+	Translations []locale.Translation
+}
+
+// RecipeListInput filters recipe lists. Query matches names and the
+// translated names in Languages, and ExcludeConflicts drops recipes that
+// break the caller's dietary restrictions. Recipes must carry every tag in
+// Tags, and a positive MaxTotalTimeMin keeps those with stated times adding
+// up to at most it.
+type RecipeListInput struct {
+	Query            string
+	Languages        []string
+	Tags             []string
+	MaxTotalTimeMin  int
+	IncludeArchived  bool
+	ExcludeConflicts bool
+	Limit            int
+	Offset           int
 }
 
+// UpdateRecipeInput changes the given fields of a recipe. A time of 0 and an
+// empty description clear them, and Tags and Instructions replace the current
+// lists. A YieldWeightG of 0 switches to an estimated yield, which follows
+// later changes to the ingredients.
 type UpdateRecipeInput struct {
 	Name         *string
 	YieldWeightG *float64
+	Servings     *int
+	ServingName  *string
+	Description  *string
+	PrepTimeMin  *int
+	CookTimeMin  *int
+	Tags         *[]string
+	Instructions *[]string
 	Ingredients  *[]RecipeIngredientInput
+	// Translations replaces all translated names; an empty slice removes
+	// them.
+	Translations *[]locale.Translation
 }
 
 func NewRecipeService(repo RecipeStore, foodReader FoodReader) *RecipeService {
-	return &RecipeService{repo: repo, foodReader: foodReader}
+	return &RecipeService{repo: repo, foodReader: foodReader, syncLimit: DefaultRecipeSyncLimit}
+}
+
+// SetRestrictionReader lets lists leave out recipes that break the caller's
+// dietary restrictions.
+func (s *RecipeService) SetRestrictionReader(users RestrictionReader) { s.users = users }
+
+// SetImageUploads enables recipe images.
+func (s *RecipeService) SetImageUploads(images ImageUploads) {
+	if images.Store == nil {
+		s.images = nil
+		return
+	}
+	s.images = &images
+}
+
+// SetFoodCandidateFinder lets recipe imports match ingredient lines to foods.
+func (s *RecipeService) SetFoodCandidateFinder(finder FoodCandidateFinder) { s.foodFinder = finder }
+
+// SetCookingFactors lets ingredients cooked by a method estimate the yield.
+func (s *RecipeService) SetCookingFactors(factors CookingFactors) { s.cooking = factors }
+
+// SetSyncLimit sets how many dependent recipes a food change recalculates
+// before it returns; larger fan-outs stay stale until RecalculateStale runs.
+// A limit of 0 leaves every recipe to it, and a negative one is ignored.
+func (s *RecipeService) SetSyncLimit(limit int) {
+	if limit >= 0 {
+		s.syncLimit = limit
+	}
 }
 
 func (s *RecipeService) Create(ctx context.Context, userID uint, in CreateRecipeInput) (recipe.Recipe, error) {
@@ -68,14 +176,44 @@ func (s *RecipeService) Create(ctx context.Context, userID uint, in CreateRecipe
 	if name == "" {
 		return recipe.Recipe{}, ErrInvalidRecipeName
 	}
-	if in.YieldWeightG <= 0 {
+	if in.YieldWeightG < 0 {
 		return recipe.Recipe{}, ErrInvalidYieldWeight
 	}
 	if len(in.Ingredients) == 0 {
 		return recipe.Recipe{}, ErrInvalidRecipeIngredients
 	}
+	servings := in.Servings
+	if servings == 0 {
+		servings = 1
+	}
+	if !validServings(servings) {
+		return recipe.Recipe{}, ErrInvalidServings
+	}
+	servingName, ok := normalizeServingName(in.ServingName)
+	if !ok {
+		return recipe.Recipe{}, ErrInvalidServingName
+	}
+	description, ok := normalizeDescription(in.Description)
+	if !ok {
+		return recipe.Recipe{}, ErrInvalidRecipeDescription
+	}
+	if !validRecipeTime(in.PrepTimeMin) || !validRecipeTime(in.CookTimeMin) {
+		return recipe.Recipe{}, ErrInvalidRecipeTime
+	}
+	tags, ok := food.NormalizeTags(in.Tags)
+	if !ok {
+		return recipe.Recipe{}, ErrInvalidRecipeTags
+	}
+	instructions, ok := normalizeInstructions(in.Instructions)
+	if !ok {
+		return recipe.Recipe{}, ErrInvalidInstructions
+	}
+	translations, ok := locale.NormalizeTranslations(in.Translations)
+	if !ok {
+		return recipe.Recipe{}, ErrInvalidTranslations
+	}
 
-	kcal, protein, carbs, fat, err := s.calculatePer100g(ctx, in.YieldWeightG, in.Ingredients)
+	nutrition, err := s.calculatePer100g(ctx, userID, 0, in.YieldWeightG, in.Ingredients, ingredientSources{}, recipeingredient.MaxDepth)
 	if err != nil {
 		return recipe.Recipe{}, err
 	}
@@ -83,12 +221,21 @@ func (s *RecipeService) Create(ctx context.Context, userID uint, in CreateRecipe
 	created, err := s.repo.Create(ctx, repository.RecipeCreate{
 		UserID:         userID,
 		Name:           name,
-		YieldWeightG:   in.YieldWeightG,
-		KcalPer100g:    kcal,
-		ProteinPer100g: protein,
-		CarbsPer100g:   carbs,
-		FatPer100g:     fat,
-		Ingredients:    toRepoIngredients(in.Ingredients),
+		YieldWeightG:   nutrition.yieldWeightG,
+		YieldEstimated: nutrition.yieldEstimated,
+		Servings:       servings,
+		ServingName:    servingName,
+		Description:    description,
+		PrepTimeMin:    in.PrepTimeMin,
+		CookTimeMin:    in.CookTimeMin,
+		Tags:           tags,
+		Instructions:   instructions,
+		KcalPer100g:    nutrition.kcal,
+		ProteinPer100g: nutrition.protein,
+		CarbsPer100g:   nutrition.carbs,
+		FatPer100g:     nutrition.fat,
+		Ingredients:    nutrition.ingredients,
+		Translations:   translations,
 	})
 	if err != nil {
 		return recipe.Recipe{}, err
@@ -109,29 +256,42 @@ func (s *RecipeService) GetByID(ctx context.Context, id uint) (recipe.Recipe, er
 	return value, nil
 }
 
-func (s *RecipeService) List(ctx context.Context, limit, offset int) ([]recipe.Recipe, error) {
-	if !IsValidPagination(limit, offset) {
+func (s *RecipeService) List(ctx context.Context, userID uint, in RecipeListInput) ([]recipe.Recipe, error) {
+	if !IsValidPagination(in.Limit, in.Offset) {
 		return nil, ErrInvalidPagination
 	}
-	return s.repo.List(ctx, limit, offset)
-}
-
-func (s *RecipeService) Search(ctx context.Context, query string, limit, offset int) ([]recipe.Recipe, error) {
-	if !IsValidPagination(limit, offset) {
-		return nil, ErrInvalidPagination
+	tags, ok := food.NormalizeTags(in.Tags)
+	if !ok {
+		return nil, ErrInvalidRecipeTags
 	}
-	q := strings.TrimSpace(query)
-	if q == "" {
-		return s.repo.List(ctx, limit, offset)
+	if in.MaxTotalTimeMin < 0 {
+		return nil, ErrInvalidRecipeTime
 	}
-	return s.repo.SearchByName(ctx, q, limit, offset)
+	q := repository.RecipeListQuery{
+		Query:           strings.TrimSpace(in.Query),
+		Languages:       in.Languages,
+		Tags:            tags,
+		MaxTotalTimeMin: in.MaxTotalTimeMin,
+		IncludeArchived: in.IncludeArchived,
+		Limit:           in.Limit,
+		Offset:          in.Offset,
+	}
+	if in.ExcludeConflicts {
+		restrictions, err := loadRestrictions(ctx, s.users, userID)
+		if err != nil {
+			return nil, err
+		}
+		q.AvoidAllergens = restrictions.AvoidAllergens
+		q.RequiredDietFlags = restrictions.RequiredDietFlags
+	}
+	return s.repo.List(ctx, q)
 }
 
 func (s *RecipeService) Update(ctx context.Context, userID, id uint, in UpdateRecipeInput) (recipe.Recipe, error) {
 	if userID == 0 {
 		return recipe.Recipe{}, ErrInvalidUserID
 	}
-	if in.Name == nil && in.YieldWeightG == nil && in.Ingredients == nil {
+	if in.Name == nil && in.YieldWeightG == nil && in.Servings == nil && in.ServingName == nil && in.Description == nil && in.PrepTimeMin == nil && in.CookTimeMin == nil && in.Tags == nil && in.Instructions == nil && in.Ingredients == nil && in.Translations == nil {
 		return recipe.Recipe{}, ErrNoFieldsToUpdate
 	}
 
@@ -145,6 +305,9 @@ func (s *RecipeService) Update(ctx context.Context, userID, id uint, in UpdateRe
 	if existing.UserID != userID {
 		return recipe.Recipe{}, ErrRecipeForbidden
 	}
+	if existing.Archived() {
+		return recipe.Recipe{}, ErrRecipeArchived
+	}
 
 	updates := repository.RecipeUpdate{}
 	if in.Name != nil {
@@ -154,22 +317,64 @@ func (s *RecipeService) Update(ctx context.Context, userID, id uint, in UpdateRe
 		}
 		updates.Name = &trimmed
 	}
+	if in.Servings != nil {
+		if !validServings(*in.Servings) {
+			return recipe.Recipe{}, ErrInvalidServings
+		}
+		updates.Servings = in.Servings
+	}
+	if in.ServingName != nil {
+		servingName, ok := normalizeServingName(*in.ServingName)
+		if !ok {
+			return recipe.Recipe{}, ErrInvalidServingName
+		}
+		updates.ServingName = &servingName
+	}
+	if in.Description != nil {
+		description, ok := normalizeDescription(*in.Description)
+		if !ok {
+			return recipe.Recipe{}, ErrInvalidRecipeDescription
+		}
+		updates.Description = &description
+	}
+	if (in.PrepTimeMin != nil && !validRecipeTime(*in.PrepTimeMin)) || (in.CookTimeMin != nil && !validRecipeTime(*in.CookTimeMin)) {
+		return recipe.Recipe{}, ErrInvalidRecipeTime
+	}
+	updates.PrepTimeMin = in.PrepTimeMin
+	updates.CookTimeMin = in.CookTimeMin
+	if in.Tags != nil {
+		tags, ok := food.NormalizeTags(*in.Tags)
+		if !ok {
+			return recipe.Recipe{}, ErrInvalidRecipeTags
+		}
+		updates.Tags = &tags
+	}
+	if in.Instructions != nil {
+		instructions, ok := normalizeInstructions(*in.Instructions)
+		if !ok {
+			return recipe.Recipe{}, ErrInvalidInstructions
+		}
+		updates.Instructions = &instructions
+	}
+	if in.Translations != nil {
+		translations, ok := locale.NormalizeTranslations(*in.Translations)
+		if !ok {
+			return recipe.Recipe{}, ErrInvalidTranslations
+		}
+		updates.Translations = &translations
+	}
 
-	yield := existing.YieldWeightG
+	yield := statedYield(existing)
 	if in.YieldWeightG != nil {
-		if *in.YieldWeightG <= 0 {
+		if *in.YieldWeightG < 0 {
 			return recipe.Recipe{}, ErrInvalidYieldWeight
 		}
 		yield = *in.YieldWeightG
-		updates.YieldWeightG = in.YieldWeightG
 	}
 
 	needRecalc := in.YieldWeightG != nil || in.Ingredients != nil
-	if in.Ingredients != nil {
-		if len(*in.Ingredients) == 0 {
-			return recipe.Recipe{}, ErrInvalidRecipeIngredients
-		}
-		updates.Ingredients = ptrRepoIngredients(*in.Ingredients)
+	if in.Ingredients != nil && len(*in.Ingredients) == 0 {
+		return recipe.Recipe{}, ErrInvalidRecipeIngredients
 	}
 
 	if needRecalc {
@@ -178,14 +383,26 @@ func (s *RecipeService) Update(ctx context.Context, userID, id uint, in UpdateRe
 			ingredients = *in.Ingredients
 		}
 
-		kcal, protein, carbs, fat, err := s.calculatePer100g(ctx, yield, ingredients)
+		// Recipes using this one count toward the nesting limit too.
+		parents, err := s.repo.ParentDepth(ctx, id, recipeingredient.MaxDepth)
+		if err != nil {
+			return recipe.Recipe{}, err
+		}
+		nutrition, err := s.calculatePer100g(ctx, userID, id, yield, ingredients, sourcesOf(existing.Ingredients), recipeingredient.MaxDepth-parents)
 		if err != nil {
 			return recipe.Recipe{}, err
 		}
-		updates.KcalPer100g = &kcal
-		updates.ProteinPer100g = &protein
-		updates.CarbsPer100g = &carbs
-		updates.FatPer100g = &fat
+		updates.YieldWeightG = &nutrition.yieldWeightG
+		updates.YieldEstimated = &nutrition.yieldEstimated
+		updates.KcalPer100g = &nutrition.kcal
+		updates.ProteinPer100g = &nutrition.protein
+		updates.CarbsPer100g = &nutrition.carbs
+		updates.FatPer100g = &nutrition.fat
+		// Ingredients are rewritten on every recalculation so their recorded
+		// food versions match the values the totals were computed from.
+		updates.Ingredients = &nutrition.ingredients
+		stale := false
+		updates.Stale = &stale
 	}
 
 	value, err := s.repo.Update(ctx, id, updates)
@@ -195,10 +412,28 @@ func (s *RecipeService) Update(ctx context.Context, userID, id uint, in UpdateRe
 	if err != nil {
 		return recipe.Recipe{}, err
 	}
+	if needRecalc {
+		if err := s.RecipeChanged(ctx, id); err != nil {
+			return recipe.Recipe{}, err
+		}
+	}
 
 	return value, nil
 }
 
+// Scale returns recipe id resized to servings portions, with its yield and
+// ingredient weights scaled to match. Nothing is saved.
+func (s *RecipeService) Scale(ctx context.Context, id uint, servings int) (recipe.Recipe, error) {
+	if !validServings(servings) {
+		return recipe.Recipe{}, ErrInvalidServings
+	}
+	value, err := s.GetByID(ctx, id)
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	return value.Scaled(servings), nil
+}
+
 func (s *RecipeService) Delete(ctx context.Context, userID, id uint) error {
 	if userID == 0 {
 		return ErrInvalidUserID
@@ -214,76 +449,472 @@ func (s *RecipeService) Delete(ctx context.Context, userID, id uint) error {
 	if existing.UserID != userID {
 		return ErrRecipeForbidden
 	}
+	if existing.Archived() {
+		return nil
+	}
 
-	err = s.repo.Delete(ctx, id)
+	err = s.repo.Archive(ctx, id, time.Now().UTC())
 	if errors.Is(err, repository.ErrNotFound) {
-		return ErrRecipeNotFound
+		// Archived concurrently; the outcome is the same.
+		return nil
 	}
 	return err
 }
 
-func (s *RecipeService) calculatePer100g(ctx context.Context, yieldWeight float64, ingredients []RecipeIngredientInput) (float64, float64, float64, float64, error) {
-	if yieldWeight <= 0 {
-		return 0, 0, 0, 0, ErrInvalidYieldWeight
+// Restore brings an archived recipe back into lists and search.
+func (s *RecipeService) Restore(ctx context.Context, userID, id uint) (recipe.Recipe, error) {
+	if userID == 0 {
+		return recipe.Recipe{}, ErrInvalidUserID
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeNotFound
+	}
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	if existing.UserID != userID {
+		return recipe.Recipe{}, ErrRecipeForbidden
+	}
+	if !existing.Archived() {
+		return existing, nil
+	}
+
+	value, err := s.repo.Restore(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeNotFound
+	}
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	return value, nil
+}
+
+// Refresh recalculates the owner's recipe from the current values of its
+// ingredient foods and clears its stale flag.
+func (s *RecipeService) Refresh(ctx context.Context, userID, id uint) (recipe.Recipe, error) {
+	if userID == 0 {
+		return recipe.Recipe{}, ErrInvalidUserID
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeNotFound
+	}
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	if existing.UserID != userID {
+		return recipe.Recipe{}, ErrRecipeForbidden
+	}
+	if existing.Archived() {
+		return recipe.Recipe{}, ErrRecipeArchived
+	}
+	return s.Recalculate(ctx, id)
+}
+
+// FoodChanged marks the recipes using foodID as stale after its nutrition
+// changed, directly or through sub-recipes, and recalculates them right away
+// when there are at most as many as the sync limit. A recipe that fails to
+// recalculate stays stale for RecalculateStale to retry, so only failing to
+// mark them is an error.
+func (s *RecipeService) FoodChanged(ctx context.Context, foodID uint) error {
+	ids, err := s.repo.MarkStaleByFood(ctx, foodID)
+	if err != nil {
+		return err
+	}
+	s.recalculateMarked(ctx, ids)
+	return nil
+}
+
+// RecipeChanged does what FoodChanged does for the recipes using recipe id
+// as a sub-recipe, after its own values changed.
+func (s *RecipeService) RecipeChanged(ctx context.Context, id uint) error {
+	ids, err := s.repo.MarkStaleByRecipe(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.recalculateMarked(ctx, ids)
+	return nil
+}
+
+// recalculateMarked recalculates recipes just marked stale unless there are
+// more than the sync limit. Each recalculation starts from the foods, so the
+// order of parents and sub-recipes does not matter.
+func (s *RecipeService) recalculateMarked(ctx context.Context, ids []uint) {
+	if len(ids) > s.syncLimit {
+		return
+	}
+	for _, id := range ids {
+		_, _ = s.Recalculate(ctx, id)
+	}
+}
+
+// StaleRecalculation is what one RecalculateStale batch went through.
+type StaleRecalculation struct {
+	// Checked counts the stale recipes the batch loaded.
+	Checked int
+	// Recalculated counts the ones brought up to date.
+	Recalculated int
+	// Failed holds why the others could not be recalculated; they stay
+	// stale and are retried on the next pass.
+	Failed map[uint]error
+	// LastID is the highest recipe ID checked, where the next batch starts.
+	LastID uint
+}
+
+// RecalculateStale recalculates up to limit stale recipes with IDs above
+// afterID. A recipe that fails is skipped so it cannot hold up the others;
+// only failing to list the stale recipes, or ctx ending, is an error.
+func (s *RecipeService) RecalculateStale(ctx context.Context, afterID uint, limit int) (StaleRecalculation, error) {
+	ids, err := s.repo.ListStale(ctx, afterID, limit)
+	if err != nil {
+		return StaleRecalculation{}, err
+	}
+	out := StaleRecalculation{Checked: len(ids)}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		out.LastID = id
+		_, err := s.Recalculate(ctx, id)
+		switch {
+		case err == nil:
+			out.Recalculated++
+		case errors.Is(err, ErrRecipeNotFound):
+		default:
+			if out.Failed == nil {
+				out.Failed = map[uint]error{}
+			}
+			out.Failed[id] = err
+		}
+	}
+	return out, nil
+}
+
+// Recalculate recomputes a recipe from the current values of its ingredient
+// foods, through its sub-recipes. It runs on behalf of the system, for
+// example after foods were merged, so it is not limited to the owner.
+func (s *RecipeService) Recalculate(ctx context.Context, id uint) (recipe.Recipe, error) {
+	existing, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeNotFound
+	}
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+
+	nutrition, err := s.calculatePer100g(ctx, existing.UserID, id, statedYield(existing), toServiceIngredients(existing.Ingredients), sourcesOf(existing.Ingredients), recipeingredient.MaxDepth)
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	stale := false
+	value, err := s.repo.Update(ctx, id, repository.RecipeUpdate{
+		YieldWeightG:   &nutrition.yieldWeightG,
+		YieldEstimated: &nutrition.yieldEstimated,
+		KcalPer100g:    &nutrition.kcal,
+		ProteinPer100g: &nutrition.protein,
+		CarbsPer100g:   &nutrition.carbs,
+		FatPer100g:     &nutrition.fat,
+		Ingredients:    &nutrition.ingredients,
+		Stale:          &stale,
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeNotFound
+	}
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	return value, nil
+}
+
+// recipeNutrition is the per-100g result of a recalculation together with
+// the yield it used and the ingredients stamped with the food versions that
+// produced it.
+type recipeNutrition struct {
+	yieldWeightG   float64
+	yieldEstimated bool
+	kcal           float64
+	protein        float64
+	carbs          float64
+	fat            float64
+	ingredients    []repository.RecipeIngredientInput
+}
+
+// statedYield is the yield a recalculation of r starts from: its yield
+// weight, or 0 to estimate it again when it was estimated.
+func statedYield(r recipe.Recipe) float64 {
+	if r.YieldEstimated {
+		return 0
+	}
+	return r.YieldWeightG
+}
+
+// ingredientSources holds the foods and sub-recipes a recipe already uses.
+type ingredientSources struct {
+	foods   map[uint]bool
+	recipes map[uint]bool
+}
+
+// calculatePer100g resolves the ingredients of recipe id, 0 for a new one,
+// and derives per-100g values. Foods must be visible to userID and not
+// archived, and sub-recipes not archived, except those in current: sources
+// the recipe already used keep working after they were archived or hidden.
+// Sub-recipes may nest at most levels deep and never contain the recipe.
+// A yieldWeight of 0 is estimated as the cooked weight of the ingredients.
+func (s *RecipeService) calculatePer100g(ctx context.Context, userID, id uint, yieldWeight float64, ingredients []RecipeIngredientInput, current ingredientSources, levels int) (recipeNutrition, error) {
+	if yieldWeight < 0 {
+		return recipeNutrition{}, ErrInvalidYieldWeight
 	}
 	if len(ingredients) == 0 {
-		return 0, 0, 0, 0, ErrInvalidRecipeIngredients
+		return recipeNutrition{}, ErrInvalidRecipeIngredients
 	}
+	var path []uint
+	if id != 0 {
+		path = []uint{id}
+	}
+
+	totals, cookedWeight, stamped, err := s.ingredientTotals(ctx, userID, ingredients, current, path, levels)
+	if err != nil {
+		return recipeNutrition{}, err
+	}
+	estimated := yieldWeight == 0
+	if estimated {
+		yieldWeight = cookedWeight
+	}
+	per100g := totals.scaled(100 / yieldWeight)
+	return recipeNutrition{
+		yieldWeightG:   yieldWeight,
+		yieldEstimated: estimated,
+		kcal:           per100g.kcal,
+		protein:        per100g.protein,
+		carbs:          per100g.carbs,
+		fat:            per100g.fat,
+		ingredients:    stamped,
+	}, nil
+}
 
-	var totalKcal float64
-	var totalProtein float64
-	var totalCarbs float64
-	var totalFat float64
+// nutrientTotals are the nutrients of an amount of ingredients.
+type nutrientTotals struct {
+	kcal    float64
+	protein float64
+	carbs   float64
+	fat     float64
+}
+
+func (t nutrientTotals) scaled(factor float64) nutrientTotals {
+	return nutrientTotals{kcal: t.kcal * factor, protein: t.protein * factor, carbs: t.carbs * factor, fat: t.fat * factor}
+}
+
+func (t nutrientTotals) plus(o nutrientTotals) nutrientTotals {
+	return nutrientTotals{kcal: t.kcal + o.kcal, protein: t.protein + o.protein, carbs: t.carbs + o.carbs, fat: t.fat + o.fat}
+}
+
+// ingredientTotals sums the nutrients of ingredients, resolving sub-recipes
+// from their own ingredients, and estimates their cooked weight: foods with
+// a cooking method change by its yield factor for their category, and other
+// ingredients keep their weight. path lists the recipes being resolved, to
+// catch cycles, and levels how many more sub-recipe levels may follow.
+func (s *RecipeService) ingredientTotals(ctx context.Context, userID uint, ingredients []RecipeIngredientInput, current ingredientSources, path []uint, levels int) (nutrientTotals, float64, []repository.RecipeIngredientInput, error) {
+	var totals nutrientTotals
+	var cookedWeight float64
+	stamped := make([]repository.RecipeIngredientInput, 0, len(ingredients))
 
 	for _, item := range ingredients {
-		if item.FoodID == 0 || item.RawWeightG <= 0 {
-			return 0, 0, 0, 0, ErrInvalidRecipeIngredients
+		if (item.FoodID == 0) == (item.SubRecipeID == 0) || item.RawWeightG <= 0 {
+			return nutrientTotals{}, 0, nil, ErrInvalidRecipeIngredients
+		}
+		// Sub-recipes are weighed as prepared.
+		if item.SubRecipeID != 0 && item.CookingMethodID != 0 {
+			return nutrientTotals{}, 0, nil, ErrInvalidRecipeIngredients
+		}
+		note, ok := normalizeNote(item.Note)
+		if !ok {
+			return nutrientTotals{}, 0, nil, ErrInvalidRecipeIngredients
+		}
+		ratio := item.RawWeightG / 100.0
+
+		if item.SubRecipeID != 0 {
+			per100g, err := s.subRecipePer100g(ctx, item.SubRecipeID, current, path, levels)
+			if err != nil {
+				return nutrientTotals{}, 0, nil, err
+			}
+			totals = totals.plus(per100g.scaled(ratio))
+			cookedWeight += item.RawWeightG
+			subRecipeID := item.SubRecipeID
+			stamped = append(stamped, repository.RecipeIngredientInput{
+				SubRecipeID: &subRecipeID,
+				RawWeightG:  item.RawWeightG,
+				Position:    item.Position,
+				Note:        note,
+			})
+			continue
 		}
 
 		f, err := s.foodReader.GetByID(ctx, item.FoodID)
 		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, ErrFoodNotFound) {
-			return 0, 0, 0, 0, ErrIngredientFoodNotFound
+			return nutrientTotals{}, 0, nil, ErrIngredientFoodNotFound
 		}
 		if err != nil {
-			return 0, 0, 0, 0, err
+			return nutrientTotals{}, 0, nil, err
+		}
+		if !current.foods[item.FoodID] {
+			if !f.VisibleTo(userID) {
+				return nutrientTotals{}, 0, nil, ErrIngredientFoodNotFound
+			}
+			if f.Archived() {
+				return nutrientTotals{}, 0, nil, ErrIngredientFoodArchived
+			}
 		}
 
-		ratio := item.RawWeightG / 100.0
-		totalKcal += f.KcalPer100g * ratio
-		totalProtein += f.ProteinPer100g * ratio
-		totalCarbs += f.CarbsPer100g * ratio
-		totalFat += f.FatPer100g * ratio
-	}
-
-	yieldFactor := yieldWeight / 100.0
-	return totalKcal / yieldFactor, totalProtein / yieldFactor, totalCarbs / yieldFactor, totalFat / yieldFactor, nil
-}
+		var methodID *uint
+		if item.CookingMethodID != 0 {
+			factor, err := yieldFactor(ctx, s.cooking, item.CookingMethodID, f.CategoryID)
+			if err != nil {
+				return nutrientTotals{}, 0, nil, err
+			}
+			cookedWeight += factor.CookedWeightG(item.RawWeightG)
+			method := item.CookingMethodID
+			methodID = &method
+		} else {
+			cookedWeight += item.RawWeightG
+		}
 
-func toRepoIngredients(items []RecipeIngredientInput) []repository.RecipeIngredientInput {
-	out := make([]repository.RecipeIngredientInput, 0, len(items))
-	for _, item := range items {
-		out = append(out, repository.RecipeIngredientInput{
-			FoodID:     item.FoodID,
-			RawWeightG: item.RawWeightG,
-			Position:   item.Position,
+		totals = totals.plus(nutrientTotals{kcal: f.KcalPer100g, protein: f.ProteinPer100g, carbs: f.CarbsPer100g, fat: f.FatPer100g}.scaled(ratio))
+		foodID := item.FoodID
+		stamped = append(stamped, repository.RecipeIngredientInput{
+			FoodID:          &foodID,
+			FoodVersion:     versionRef(f.CurrentVersion),
+			RawWeightG:      item.RawWeightG,
+			Position:        item.Position,
+			Note:            note,
+			CookingMethodID: methodID,
 		})
 	}
-	return out
+	return totals, cookedWeight, stamped, nil
 }
 
-func ptrRepoIngredients(items []RecipeIngredientInput) *[]repository.RecipeIngredientInput {
-	mapped := toRepoIngredients(items)
-	return &mapped
+// subRecipePer100g computes a sub-recipe's per-100g values from its current
+// ingredients rather than its stored values, which may be stale. An
+// estimated yield is re-estimated the same way, since it follows the
+// ingredients' cooking factors. Recipes are shared, so any recipe may be
+// used; the sub-recipe's own ingredients are all current to it.
+func (s *RecipeService) subRecipePer100g(ctx context.Context, id uint, current ingredientSources, path []uint, levels int) (nutrientTotals, error) {
+	if slices.Contains(path, id) {
+		return nutrientTotals{}, ErrRecipeCycle
+	}
+	if levels <= 0 {
+		return nutrientTotals{}, ErrRecipeTooDeep
+	}
+	sub, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nutrientTotals{}, ErrIngredientRecipeNotFound
+	}
+	if err != nil {
+		return nutrientTotals{}, err
+	}
+	if sub.Archived() && !current.recipes[id] {
+		return nutrientTotals{}, ErrIngredientRecipeArchived
+	}
+
+	totals, cookedWeight, _, err := s.ingredientTotals(ctx, sub.UserID, toServiceIngredients(sub.Ingredients), sourcesOf(sub.Ingredients), append(slices.Clone(path), id), levels-1)
+	if err != nil {
+		return nutrientTotals{}, err
+	}
+	yieldWeight := sub.YieldWeightG
+	if sub.YieldEstimated {
+		yieldWeight = cookedWeight
+	}
+	if yieldWeight <= 0 {
+		return nutrientTotals{}, ErrInvalidYieldWeight
+	}
+	return totals.scaled(100 / yieldWeight), nil
 }
 
 func toServiceIngredients(items []recipeingredient.RecipeIngredient) []RecipeIngredientInput {
 	out := make([]RecipeIngredientInput, 0, len(items))
 	for _, item := range items {
-		out = append(out, RecipeIngredientInput{
-			FoodID:     item.FoodID,
+		in := RecipeIngredientInput{
 			RawWeightG: item.RawWeightG,
 			Position:   item.Position,
-		})
+			Note:       item.Note,
+		}
+		if item.FoodID != nil {
+			in.FoodID = *item.FoodID
+		}
+		if item.SubRecipeID != nil {
+			in.SubRecipeID = *item.SubRecipeID
+		}
+		if item.CookingMethodID != nil {
+			in.CookingMethodID = *item.CookingMethodID
+		}
+		out = append(out, in)
+	}
+	return out
+}
+
+func sourcesOf(items []recipeingredient.RecipeIngredient) ingredientSources {
+	out := ingredientSources{foods: make(map[uint]bool, len(items)), recipes: make(map[uint]bool)}
+	for _, item := range items {
+		if item.FoodID != nil {
+			out.foods[*item.FoodID] = true
+		}
+		if item.SubRecipeID != nil {
+			out.recipes[*item.SubRecipeID] = true
+		}
 	}
 	return out
 }
+
+func validServings(servings int) bool {
+	return servings > 0 && servings <= recipe.MaxServings
+}
+
+// normalizeServingName trims a serving name, defaulting an empty one. It
+// reports false for names over recipe.MaxServingNameLength characters.
+func normalizeServingName(raw string) (string, bool) {
+	name := strings.Join(strings.Fields(raw), " ")
+	if name == "" {
+		return recipe.DefaultServingName, true
+	}
+	if utf8.RuneCountInString(name) > recipe.MaxServingNameLength {
+		return "", false
+	}
+	return name, true
+}
+
+func validRecipeTime(minutes int) bool {
+	return minutes >= 0 && minutes <= recipe.MaxTimeMin
+}
+
+// normalizeDescription trims a description, keeping its line breaks. It
+// reports false past recipe.MaxDescriptionLength characters.
+func normalizeDescription(raw string) (string, bool) {
+	description := strings.TrimSpace(raw)
+	return description, utf8.RuneCountInString(description) <= recipe.MaxDescriptionLength
+}
+
+// normalizeInstructions trims each step. It reports false for empty or
+// overlong steps and for more than recipe.MaxInstructions of them.
+func normalizeInstructions(raw []string) ([]string, bool) {
+	if len(raw) > recipe.MaxInstructions {
+		return nil, false
+	}
+	steps := make([]string, 0, len(raw))
+	for _, step := range raw {
+		step = strings.TrimSpace(step)
+		if step == "" || utf8.RuneCountInString(step) > recipe.MaxInstructionLength {
+			return nil, false
+		}
+		steps = append(steps, step)
+	}
+	return steps, true
+}
+
+// normalizeNote collapses whitespace in an ingredient note. It reports false
+// past recipeingredient.MaxNoteLength characters.
+func normalizeNote(raw string) (string, bool) {
+	note := strings.Join(strings.Fields(raw), " ")
+	return note, utf8.RuneCountInString(note) <= recipeingredient.MaxNoteLength
+}
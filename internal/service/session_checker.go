@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type SessionActivityStore interface {
+	IsActive(ctx context.Context, id uint, now time.Time) (bool, error)
+}
+
+type sessionCheckEntry struct {
+	active    bool
+	checkedAt time.Time
+}
+
+// CachedSessionChecker answers "is this auth session still live" for access
+// token validation. Results are cached for ttl, so a revoked session stops
+// authenticating requests within ttl instead of at access token expiry.
+type CachedSessionChecker struct {
+	mu    sync.Mutex
+	store SessionActivityStore
+	ttl   time.Duration
+	cache map[uint]sessionCheckEntry
+}
+
+func NewCachedSessionChecker(store SessionActivityStore, ttl time.Duration) *CachedSessionChecker {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return &CachedSessionChecker{
+		store: store,
+		ttl:   ttl,
+		cache: make(map[uint]sessionCheckEntry),
+	}
+}
+
+func (c *CachedSessionChecker) IsSessionActive(ctx context.Context, sessionID uint, now time.Time) (bool, error) {
+	if sessionID == 0 {
+		return false, nil
+	}
+
+	c.mu.Lock()
+	entry, ok := c.cache[sessionID]
+	c.mu.Unlock()
+	if ok && now.Sub(entry.checkedAt) < c.ttl {
+		return entry.active, nil
+	}
+
+	active, err := c.store.IsActive(ctx, sessionID, now)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pruneLocked(now)
+	c.cache[sessionID] = sessionCheckEntry{active: active, checkedAt: now}
+	return active, nil
+}
+
+// pruneLocked drops stale entries once the cache has grown, keeping memory
+// bounded by the number of sessions seen within one ttl.
+func (c *CachedSessionChecker) pruneLocked(now time.Time) {
+	if len(c.cache) < 1024 {
+		return
+	}
+	for id, entry := range c.cache {
+		if now.Sub(entry.checkedAt) >= c.ttl {
+			delete(c.cache, id)
+		}
+	}
+}
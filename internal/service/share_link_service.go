@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goal-bite-api/internal/domain/locale"
+	"goal-bite-api/internal/domain/meal"
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/sharelink"
+	"goal-bite-api/internal/repository"
+)
+
+var (
+	ErrShareLinkNotFound      = errors.New("share link not found")
+	ErrInvalidShareLinkExpiry = errors.New("invalid share link expiry")
+)
+
+type ShareLinkStore interface {
+	Create(ctx context.Context, in repository.ShareLinkCreate) (sharelink.ShareLink, error)
+	ListByUser(ctx context.Context, userID uint, limit, offset int) ([]sharelink.ShareLink, error)
+	Revoke(ctx context.Context, userID, id uint, now time.Time) error
+	GetActive(ctx context.Context, tokenHash string, now time.Time) (sharelink.ShareLink, error)
+	RecordAccess(ctx context.Context, id uint, now time.Time) error
+}
+
+// SharedRecipeSource reads the recipes links share, with the nutrition of
+// their ingredients.
+type SharedRecipeSource interface {
+	GetByID(ctx context.Context, id uint) (recipe.Recipe, error)
+	Breakdown(ctx context.Context, userID, id uint) (recipe.Recipe, NutritionBreakdown, error)
+}
+
+// SharedMealSource reads the meals links share, with the nutrition of their
+// items.
+type SharedMealSource interface {
+	GetByID(ctx context.Context, userID, id uint) (meal.Meal, error)
+	Breakdown(ctx context.Context, userID, id uint) (meal.Meal, NutritionBreakdown, error)
+}
+
+type ShareLinkService struct {
+	repo    ShareLinkStore
+	recipes SharedRecipeSource
+	meals   SharedMealSource
+}
+
+// CreateShareLinkInput sets when the link stops opening. A nil ExpiresAt
+// means never.
+type CreateShareLinkInput struct {
+	ExpiresAt *time.Time
+}
+
+// SharedView is what a share link opens: the recipe or meal without anything
+// that tells who owns it, and the nutrition of each ingredient or item.
+type SharedView struct {
+	Kind      sharelink.Kind  `json:"kind"`
+	Recipe    *SharedRecipe   `json:"recipe,omitempty"`
+	Meal      *SharedMeal     `json:"meal,omitempty"`
+	Nutrition SharedNutrition `json:"nutrition"`
+}
+
+// SharedNutrition is a NutritionBreakdown without any IDs, so a shared view
+// cannot be used to look up the ingredients, items, foods or recipes behind
+// it.
+type SharedNutrition struct {
+	Items        []SharedNutritionItem `json:"items"`
+	Total        NutrientAmounts       `json:"total"`
+	CalorieSplit MacroSplit            `json:"calorie_split"`
+}
+
+// SharedNutritionItem is what one shared ingredient or meal item
+// contributes.
+type SharedNutritionItem struct {
+	Name         string               `json:"name"`
+	DisplayName  string               `json:"display_name"`
+	Translations []locale.Translation `json:"-"`
+	WeightG      float64              `json:"weight_g"`
+	Contribution NutrientAmounts      `json:"contribution"`
+	Share        NutrientShares       `json:"share"`
+}
+
+func sharedNutrition(b NutritionBreakdown) SharedNutrition {
+	items := make([]SharedNutritionItem, 0, len(b.Items))
+	for _, item := range b.Items {
+		items = append(items, SharedNutritionItem{
+			Name:         item.Name,
+			Translations: item.Translations,
+			WeightG:      item.WeightG,
+			Contribution: item.Contribution,
+			Share:        item.Share,
+		})
+	}
+	return SharedNutrition{Items: items, Total: b.Total, CalorieSplit: b.CalorieSplit}
+}
+
+// SharedRecipe is the part of a recipe a share link shows.
+type SharedRecipe struct {
+	Name         string               `json:"name"`
+	DisplayName  string               `json:"display_name"`
+	Translations []locale.Translation `json:"-"`
+	Description  string               `json:"description"`
+	YieldWeightG float64              `json:"yield_weight_g"`
+	Servings     int                  `json:"servings"`
+	ServingName  string               `json:"serving_name"`
+	PrepTimeMin  int                  `json:"prep_time_min"`
+	CookTimeMin  int                  `json:"cook_time_min"`
+	Tags         []string             `json:"tags"`
+	Instructions []string             `json:"instructions"`
+	Allergens    []string             `json:"allergens"`
+	DietFlags    []string             `json:"diet_flags"`
+	PerServing   recipe.Portion       `json:"per_serving"`
+	PerBatch     recipe.Portion       `json:"per_batch"`
+	ImageURL     *string              `json:"image_url,omitempty"`
+	ThumbnailURL *string              `json:"thumbnail_url,omitempty"`
+}
+
+// SharedMeal is the part of a meal a share link shows.
+type SharedMeal struct {
+	MealType     meal.MealType `json:"meal_type"`
+	EatenAt      time.Time     `json:"eaten_at"`
+	ImageURL     *string       `json:"image_url,omitempty"`
+	ThumbnailURL *string       `json:"thumbnail_url,omitempty"`
+}
+
+// Localize sets the display names of the recipe and of the ingredients or
+// items to their translations in the first of languages they have one in.
+func (v *SharedView) Localize(languages []string) {
+	if v.Recipe != nil {
+		v.Recipe.DisplayName = locale.Pick(v.Recipe.Name, v.Recipe.Translations, languages)
+	}
+	for i := range v.Nutrition.Items {
+		item := &v.Nutrition.Items[i]
+		item.DisplayName = locale.Pick(item.Name, item.Translations, languages)
+	}
+}
+
+func NewShareLinkService(repo ShareLinkStore, recipes SharedRecipeSource, meals SharedMealSource) *ShareLinkService {
+	return &ShareLinkService{repo: repo, recipes: recipes, meals: meals}
+}
+
+// ShareRecipe makes a link to one of the user's recipes. The returned link
+// carries the token, which cannot be read back later.
+func (s *ShareLinkService) ShareRecipe(ctx context.Context, userID, recipeID uint, in CreateShareLinkInput) (sharelink.ShareLink, error) {
+	if userID == 0 {
+		return sharelink.ShareLink{}, ErrInvalidUserID
+	}
+	if err := validateShareLinkExpiry(in.ExpiresAt); err != nil {
+		return sharelink.ShareLink{}, err
+	}
+	value, err := s.recipes.GetByID(ctx, recipeID)
+	if err != nil {
+		return sharelink.ShareLink{}, err
+	}
+	if value.UserID != userID {
+		return sharelink.ShareLink{}, ErrRecipeForbidden
+	}
+	if value.Archived() {
+		return sharelink.ShareLink{}, ErrRecipeArchived
+	}
+	return s.create(ctx, repository.ShareLinkCreate{UserID: userID, RecipeID: &value.ID, ExpiresAt: in.ExpiresAt})
+}
+
+// ShareMeal makes a link to one of the user's meals. The returned link
+// carries the token, which cannot be read back later.
+func (s *ShareLinkService) ShareMeal(ctx context.Context, userID, mealID uint, in CreateShareLinkInput) (sharelink.ShareLink, error) {
+	if userID == 0 {
+		return sharelink.ShareLink{}, ErrInvalidUserID
+	}
+	if err := validateShareLinkExpiry(in.ExpiresAt); err != nil {
+		return sharelink.ShareLink{}, err
+	}
+	value, err := s.meals.GetByID(ctx, userID, mealID)
+	if err != nil {
+		return sharelink.ShareLink{}, err
+	}
+	return s.create(ctx, repository.ShareLinkCreate{UserID: userID, MealID: &value.ID, ExpiresAt: in.ExpiresAt})
+}
+
+func (s *ShareLinkService) create(ctx context.Context, in repository.ShareLinkCreate) (sharelink.ShareLink, error) {
+	token, err := generateRefreshToken()
+	if err != nil {
+		return sharelink.ShareLink{}, err
+	}
+	in.TokenHash = hashRefreshToken(token)
+	value, err := s.repo.Create(ctx, in)
+	if err != nil {
+		return sharelink.ShareLink{}, err
+	}
+	value.Token = token
+	return value, nil
+}
+
+// List returns the user's links, newest first, with how often each was
+// opened.
+func (s *ShareLinkService) List(ctx context.Context, userID uint, limit, offset int) ([]sharelink.ShareLink, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if !IsValidPagination(limit, offset) {
+		return nil, ErrInvalidPagination
+	}
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// Revoke stops one of the user's links from opening.
+func (s *ShareLinkService) Revoke(ctx context.Context, userID, id uint) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	err := s.repo.Revoke(ctx, userID, id, time.Now().UTC())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrShareLinkNotFound
+	}
+	return err
+}
+
+// Open returns what the link with token shares and counts an access to it.
+// Unknown, revoked and expired links, and links to deleted recipes or meals,
+// are all not found, so callers cannot tell them apart; only links that
+// opened are counted.
+func (s *ShareLinkService) Open(ctx context.Context, token string) (SharedView, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return SharedView{}, ErrShareLinkNotFound
+	}
+	now := time.Now().UTC()
+	link, err := s.repo.GetActive(ctx, hashRefreshToken(token), now)
+	if errors.Is(err, repository.ErrNotFound) {
+		return SharedView{}, ErrShareLinkNotFound
+	}
+	if err != nil {
+		return SharedView{}, err
+	}
+
+	view, err := s.view(ctx, link)
+	if err != nil {
+		return SharedView{}, err
+	}
+	if err := s.repo.RecordAccess(ctx, link.ID, now); err != nil {
+		return SharedView{}, err
+	}
+	return view, nil
+}
+
+// view reads what link shares.
+func (s *ShareLinkService) view(ctx context.Context, link sharelink.ShareLink) (SharedView, error) {
+	// Breakdowns are read as the owner, so the owner's own foods are named
+	// while other users' private foods stay hidden.
+	if link.Kind() == sharelink.KindMeal {
+		value, breakdown, err := s.meals.Breakdown(ctx, link.UserID, *link.MealID)
+		if errors.Is(err, ErrMealNotFound) {
+			return SharedView{}, ErrShareLinkNotFound
+		}
+		if err != nil {
+			return SharedView{}, err
+		}
+		imageURL, thumbnailURL := shareableImage(value.ImageKey, value.ImageURL, value.ThumbnailURL, fmt.Sprintf("%s/%d/", mealImagePrefix, value.ID))
+		return SharedView{
+			Kind: sharelink.KindMeal,
+			Meal: &SharedMeal{
+				MealType:     value.MealType,
+				EatenAt:      value.EatenAt,
+				ImageURL:     imageURL,
+				ThumbnailURL: thumbnailURL,
+			},
+			Nutrition: sharedNutrition(breakdown),
+		}, nil
+	}
+	value, breakdown, err := s.recipes.Breakdown(ctx, link.UserID, *link.RecipeID)
+	if errors.Is(err, ErrRecipeNotFound) {
+		return SharedView{}, ErrShareLinkNotFound
+	}
+	if err != nil {
+		return SharedView{}, err
+	}
+	// Deleting a recipe only archives it, so the row and its links remain.
+	if value.Archived() {
+		return SharedView{}, ErrShareLinkNotFound
+	}
+	imageURL, thumbnailURL := shareableImage(value.ImageKey, value.ImageURL, value.ThumbnailURL, fmt.Sprintf("%s/%d/", recipeImagePrefix, value.ID))
+	return SharedView{
+		Kind: sharelink.KindRecipe,
+		Recipe: &SharedRecipe{
+			Name:         value.Name,
+			Translations: value.Translations,
+			Description:  value.Description,
+			YieldWeightG: value.YieldWeightG,
+			Servings:     value.Servings,
+			ServingName:  value.ServingName,
+			PrepTimeMin:  value.PrepTimeMin,
+			CookTimeMin:  value.CookTimeMin,
+			Tags:         value.Tags,
+			Instructions: value.Instructions,
+			Allergens:    value.Allergens,
+			DietFlags:    value.DietFlags,
+			PerServing:   value.PerServing,
+			PerBatch:     value.PerBatch,
+			ImageURL:     imageURL,
+			ThumbnailURL: thumbnailURL,
+		},
+		Nutrition: sharedNutrition(breakdown),
+	}, nil
+}
+
+// validateShareLinkExpiry accepts no expiry or one in the future.
+func validateShareLinkExpiry(expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return ErrInvalidShareLinkExpiry
+	}
+	return nil
+}
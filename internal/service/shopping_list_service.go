@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"goal-bite-api/internal/domain/meal"
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/recipeingredient"
+	"goal-bite-api/internal/domain/shoppinglist"
+	"goal-bite-api/internal/repository"
+)
+
+var (
+	ErrShoppingListNotFound      = errors.New("shopping list not found")
+	ErrShoppingListItemNotFound  = errors.New("shopping list item not found")
+	ErrInvalidShoppingListName   = errors.New("invalid shopping list name")
+	ErrInvalidShoppingListSource = errors.New("invalid shopping list source")
+)
+
+// MaxShoppingListDays caps the date range of planned meals a shopping list
+// is made from.
+const MaxShoppingListDays = 31
+
+type ShoppingListStore interface {
+	Create(ctx context.Context, in repository.ShoppingListCreate) (shoppinglist.ShoppingList, error)
+	GetForUser(ctx context.Context, userID, id uint) (shoppinglist.ShoppingList, error)
+	ListByUser(ctx context.Context, userID uint, limit, offset int) ([]shoppinglist.ShoppingList, error)
+	SetItemChecked(ctx context.Context, userID, listID, itemID uint, checked bool) (shoppinglist.Item, error)
+	DeleteForUser(ctx context.Context, userID, id uint) error
+}
+
+// PlannedMealReader reads the meals a user has logged or planned over a
+// range of days.
+type PlannedMealReader interface {
+	ListByUserBetween(ctx context.Context, userID uint, from, to time.Time) ([]meal.Meal, error)
+}
+
+type ShoppingListService struct {
+	repo         ShoppingListStore
+	recipeReader RecipeReader
+	foodReader   FoodReader
+	meals        PlannedMealReader
+}
+
+// ShoppingListRecipeInput asks for Servings portions of a recipe. A Servings
+// of 0 means one batch.
+type ShoppingListRecipeInput struct {
+	RecipeID uint
+	Servings float64
+}
+
+// CreateShoppingListInput makes a list either from Recipes or from the meals
+// eaten between the From and To dates (YYYY-MM-DD), both included. Name
+// defaults to shoppinglist.DefaultName.
+type CreateShoppingListInput struct {
+	Name    string
+	Recipes []ShoppingListRecipeInput
+	From    string
+	To      string
+}
+
+func NewShoppingListService(repo ShoppingListStore, recipeReader RecipeReader, foodReader FoodReader, meals PlannedMealReader) *ShoppingListService {
+	return &ShoppingListService{repo: repo, recipeReader: recipeReader, foodReader: foodReader, meals: meals}
+}
+
+// Create adds up the raw ingredient weights the recipes or planned meals
+// need, expanding sub-recipes, and saves them as a list with one item per
+// food.
+func (s *ShoppingListService) Create(ctx context.Context, userID uint, in CreateShoppingListInput) (shoppinglist.ShoppingList, error) {
+	if userID == 0 {
+		return shoppinglist.ShoppingList{}, ErrInvalidUserID
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		name = shoppinglist.DefaultName
+	}
+	if utf8.RuneCountInString(name) > shoppinglist.MaxNameLength {
+		return shoppinglist.ShoppingList{}, ErrInvalidShoppingListName
+	}
+	byRange := in.From != "" || in.To != ""
+	if byRange == (len(in.Recipes) > 0) {
+		return shoppinglist.ShoppingList{}, ErrInvalidShoppingListSource
+	}
+
+	amounts := newShoppingAmounts()
+	expander := recipeExpander{reader: s.recipeReader, recipes: map[uint]recipe.Recipe{}}
+	if byRange {
+		from, to, err := shoppingRange(in.From, in.To)
+		if err != nil {
+			return shoppinglist.ShoppingList{}, err
+		}
+		meals, err := s.meals.ListByUserBetween(ctx, userID, from, to)
+		if err != nil {
+			return shoppinglist.ShoppingList{}, err
+		}
+		for _, m := range meals {
+			for _, item := range m.Items {
+				if item.FoodID != nil {
+					amounts.add(*item.FoodID, item.WeightG)
+					continue
+				}
+				if item.RecipeID == nil {
+					continue
+				}
+				r, err := expander.get(ctx, *item.RecipeID)
+				if err != nil {
+					return shoppinglist.ShoppingList{}, err
+				}
+				if r.YieldWeightG <= 0 {
+					continue
+				}
+				if err := expander.expand(ctx, amounts, r, item.WeightG/r.YieldWeightG, recipeingredient.MaxDepth); err != nil {
+					return shoppinglist.ShoppingList{}, err
+				}
+			}
+		}
+	}
+	for _, wanted := range in.Recipes {
+		if wanted.RecipeID == 0 || wanted.Servings < 0 {
+			return shoppinglist.ShoppingList{}, ErrInvalidShoppingListSource
+		}
+		r, err := expander.get(ctx, wanted.RecipeID)
+		if err != nil {
+			return shoppinglist.ShoppingList{}, err
+		}
+		if r.Archived() {
+			return shoppinglist.ShoppingList{}, ErrRecipeArchived
+		}
+		factor := 1.0
+		if wanted.Servings > 0 {
+			factor = wanted.Servings / float64(max(r.Servings, 1))
+		}
+		if err := expander.expand(ctx, amounts, r, factor, recipeingredient.MaxDepth); err != nil {
+			return shoppinglist.ShoppingList{}, err
+		}
+	}
+
+	created, err := s.repo.Create(ctx, repository.ShoppingListCreate{UserID: userID, Name: name, Items: amounts.items()})
+	if err != nil {
+		return shoppinglist.ShoppingList{}, err
+	}
+	if err := s.withNames(ctx, userID, &created); err != nil {
+		return shoppinglist.ShoppingList{}, err
+	}
+	return created, nil
+}
+
+func (s *ShoppingListService) GetByID(ctx context.Context, userID, id uint) (shoppinglist.ShoppingList, error) {
+	if userID == 0 {
+		return shoppinglist.ShoppingList{}, ErrInvalidUserID
+	}
+	value, err := s.repo.GetForUser(ctx, userID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return shoppinglist.ShoppingList{}, ErrShoppingListNotFound
+	}
+	if err != nil {
+		return shoppinglist.ShoppingList{}, err
+	}
+	if err := s.withNames(ctx, userID, &value); err != nil {
+		return shoppinglist.ShoppingList{}, err
+	}
+	return value, nil
+}
+
+// List returns the user's lists, newest first, without their items.
+func (s *ShoppingListService) List(ctx context.Context, userID uint, limit, offset int) ([]shoppinglist.ShoppingList, error) {
+	if userID == 0 {
+		return nil, ErrInvalidUserID
+	}
+	if !IsValidPagination(limit, offset) {
+		return nil, ErrInvalidPagination
+	}
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// SetItemChecked ticks an item of the user's list on or off.
+func (s *ShoppingListService) SetItemChecked(ctx context.Context, userID, listID, itemID uint, checked bool) (shoppinglist.Item, error) {
+	if userID == 0 {
+		return shoppinglist.Item{}, ErrInvalidUserID
+	}
+	value, err := s.repo.SetItemChecked(ctx, userID, listID, itemID, checked)
+	if errors.Is(err, repository.ErrNotFound) {
+		return shoppinglist.Item{}, ErrShoppingListItemNotFound
+	}
+	if err != nil {
+		return shoppinglist.Item{}, err
+	}
+	return value, nil
+}
+
+func (s *ShoppingListService) Delete(ctx context.Context, userID, id uint) error {
+	if userID == 0 {
+		return ErrInvalidUserID
+	}
+	err := s.repo.DeleteForUser(ctx, userID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrShoppingListNotFound
+	}
+	return err
+}
+
+// withNames sets the names of the list's foods that the user may see.
+func (s *ShoppingListService) withNames(ctx context.Context, userID uint, value *shoppinglist.ShoppingList) error {
+	foodIDs := make([]uint, 0, len(value.Items))
+	for _, item := range value.Items {
+		foodIDs = append(foodIDs, item.FoodID)
+	}
+	foods, _, err := loadSources(ctx, s.foodReader, s.recipeReader, foodIDs, nil)
+	if err != nil {
+		return err
+	}
+	for i, item := range value.Items {
+		if f, ok := foods[item.FoodID]; ok && f.VisibleTo(userID) {
+			value.Items[i].Name, value.Items[i].Translations = f.Name, f.Translations
+		}
+	}
+	return nil
+}
+
+// shoppingRange parses an inclusive range of dates into [from, to) in UTC.
+func shoppingRange(rawFrom, rawTo string) (time.Time, time.Time, error) {
+	from, err := time.Parse("2006-01-02", rawFrom)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	to, err := time.Parse("2006-01-02", rawTo)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	to = to.Add(24 * time.Hour)
+	if !to.After(from) || to.Sub(from) > MaxShoppingListDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+	return from, to, nil
+}
+
+// shoppingAmounts adds up raw weights per food, remembering the order foods
+// first came up in.
+type shoppingAmounts struct {
+	order   []uint
+	weights map[uint]float64
+}
+
+func newShoppingAmounts() *shoppingAmounts {
+	return &shoppingAmounts{weights: map[uint]float64{}}
+}
+
+func (a *shoppingAmounts) add(foodID uint, weightG float64) {
+	if weightG <= 0 {
+		return
+	}
+	if _, ok := a.weights[foodID]; !ok {
+		a.order = append(a.order, foodID)
+	}
+	a.weights[foodID] += weightG
+}
+
+func (a *shoppingAmounts) items() []repository.ShoppingListItemCreate {
+	out := make([]repository.ShoppingListItemCreate, 0, len(a.order))
+	for _, id := range a.order {
+		out = append(out, repository.ShoppingListItemCreate{FoodID: id, WeightG: a.weights[id]})
+	}
+	return out
+}
+
+// recipeExpander turns amounts of recipes into amounts of their ingredient
+// foods, reading each recipe once.
+type recipeExpander struct {
+	reader  RecipeReader
+	recipes map[uint]recipe.Recipe
+}
+
+func (e recipeExpander) get(ctx context.Context, id uint) (recipe.Recipe, error) {
+	if r, ok := e.recipes[id]; ok {
+		return r, nil
+	}
+	r, err := e.reader.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return recipe.Recipe{}, ErrRecipeSourceNotFound
+	}
+	if err != nil {
+		return recipe.Recipe{}, err
+	}
+	e.recipes[id] = r
+	return r, nil
+}
+
+// expand adds factor times the raw ingredient weights of r to amounts. A
+// sub-recipe contributes the share of its own batch that r uses, down to
+// depth more levels.
+func (e recipeExpander) expand(ctx context.Context, amounts *shoppingAmounts, r recipe.Recipe, factor float64, depth int) error {
+	for _, ingredient := range r.Ingredients {
+		if ingredient.FoodID != nil {
+			amounts.add(*ingredient.FoodID, ingredient.RawWeightG*factor)
+			continue
+		}
+		if ingredient.SubRecipeID == nil {
+			continue
+		}
+		if depth <= 0 {
+			return ErrRecipeTooDeep
+		}
+		sub, err := e.get(ctx, *ingredient.SubRecipeID)
+		if err != nil {
+			return err
+		}
+		if sub.YieldWeightG <= 0 {
+			continue
+		}
+		if err := e.expand(ctx, amounts, sub, factor*ingredient.RawWeightG/sub.YieldWeightG, depth-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -16,7 +16,7 @@ type fakeUserAuthStore struct {
 	getByIDFn           func(ctx context.Context, id uint) (user.User, error)
 	getByEmailFn        func(ctx context.Context, email string) (user.User, error)
 	createFn            func(ctx context.Context, value user.User) (user.User, error)
-	createWithSessionFn func(ctx context.Context, value user.User, session repository.CreateAuthSessionInput) (user.User, error)
+	createWithSessionFn func(ctx context.Context, value user.User, session repository.CreateAuthSessionInput) (user.User, repository.AuthSession, error)
 }
 
 func (f fakeUserAuthStore) GetByID(ctx context.Context, id uint) (user.User, error) {
@@ -41,26 +41,27 @@ func (f fakeUserAuthStore) Create(ctx context.Context, value user.User) (user.Us
 	return f.createFn(ctx, value)
 }
 
-func (f fakeUserAuthStore) CreateWithSession(ctx context.Context, value user.User, session repository.CreateAuthSessionInput) (user.User, error) {
+func (f fakeUserAuthStore) CreateWithSession(ctx context.Context, value user.User, session repository.CreateAuthSessionInput) (user.User, repository.AuthSession, error) {
 	if f.createWithSessionFn == nil {
 		if f.createFn != nil {
-			return f.createFn(ctx, value)
+			created, err := f.createFn(ctx, value)
+			return created, repository.AuthSession{ID: 1, UserID: created.ID}, err
 		}
 		value.ID = 1
-		return value, nil
+		return value, repository.AuthSession{ID: 1, UserID: value.ID}, nil
 	}
 	return f.createWithSessionFn(ctx, value, session)
 }
 
 type fakeTokenIssuer struct {
-	generateFn func(userID uint) (string, error)
+	generateFn func(userID, sessionID uint) (string, error)
 }
 
-func (f fakeTokenIssuer) Generate(userID uint) (string, error) {
+func (f fakeTokenIssuer) Generate(userID, sessionID uint) (string, error) {
 	if f.generateFn == nil {
 		return "access-token", nil
 	}
-	return f.generateFn(userID)
+	return f.generateFn(userID, sessionID)
 }
 
 type fakeAuthSessionStore struct {
@@ -68,7 +69,7 @@ type fakeAuthSessionStore struct {
 	getActiveByHashFn   func(ctx context.Context, tokenHash string, now time.Time) (repository.AuthSession, error)
 	revokeByIDFn        func(ctx context.Context, id uint, at time.Time) error
 	revokeByTokenHashFn func(ctx context.Context, tokenHash string, at time.Time) error
-	rotateFn            func(ctx context.Context, in repository.RotateAuthSessionInput) error
+	rotateFn            func(ctx context.Context, in repository.RotateAuthSessionInput) (repository.AuthSession, error)
 }
 
 type fakeLoginAttemptTracker struct {
@@ -77,6 +78,17 @@ type fakeLoginAttemptTracker struct {
 	resetFn           func(key string)
 }
 
+type fakeBreachedPasswordChecker struct {
+	isBreachedFn func(password string) (bool, error)
+}
+
+func (f fakeBreachedPasswordChecker) IsBreached(password string) (bool, error) {
+	if f.isBreachedFn == nil {
+		return false, nil
+	}
+	return f.isBreachedFn(password)
+}
+
 func (f fakeLoginAttemptTracker) IsBlocked(key string, now time.Time) (bool, time.Duration) {
 	if f.isBlockedFn == nil {
 		return false, 0
@@ -124,9 +136,9 @@ func (f fakeAuthSessionStore) RevokeByTokenHash(ctx context.Context, tokenHash s
 	return f.revokeByTokenHashFn(ctx, tokenHash, at)
 }
 
-func (f fakeAuthSessionStore) Rotate(ctx context.Context, in repository.RotateAuthSessionInput) error {
+func (f fakeAuthSessionStore) Rotate(ctx context.Context, in repository.RotateAuthSessionInput) (repository.AuthSession, error) {
 	if f.rotateFn == nil {
-		return nil
+		return repository.AuthSession{ID: 1, UserID: in.UserID}, nil
 	}
 	return f.rotateFn(ctx, in)
 }
@@ -148,12 +160,17 @@ func TestAuthServiceRefresh(t *testing.T) {
 					return user.User{ID: id, Name: "User", Email: "u@example.com"}, nil
 				},
 			},
-			fakeTokenIssuer{generateFn: func(_ uint) (string, error) { return "new-access", nil }},
+			fakeTokenIssuer{generateFn: func(_, sessionID uint) (string, error) {
+				if sessionID != 8 {
+					t.Fatalf("expected token bound to rotated session 8, got %d", sessionID)
+				}
+				return "new-access", nil
+			}},
 			fakeAuthSessionStore{
 				getActiveByHashFn: func(_ context.Context, _ string, _ time.Time) (repository.AuthSession, error) {
 					return repository.AuthSession{ID: 7, UserID: 1}, nil
 				},
-				rotateFn: func(_ context.Context, in repository.RotateAuthSessionInput) error {
+				rotateFn: func(_ context.Context, in repository.RotateAuthSessionInput) (repository.AuthSession, error) {
 					if in.UserID != 1 {
 						t.Fatalf("expected user id 1, got %d", in.UserID)
 					}
@@ -161,7 +178,7 @@ func TestAuthServiceRefresh(t *testing.T) {
 						t.Fatalf("expected token hashes to be set")
 					}
 					rotated = true
-					return nil
+					return repository.AuthSession{ID: 8, UserID: in.UserID}, nil
 				},
 			},
 		)
@@ -236,7 +253,7 @@ func TestAuthServiceRegisterUsesCreateWithSession(t *testing.T) {
 	called := false
 	svc := service.NewAuthService(
 		fakeUserAuthStore{
-			createWithSessionFn: func(_ context.Context, value user.User, session repository.CreateAuthSessionInput) (user.User, error) {
+			createWithSessionFn: func(_ context.Context, value user.User, session repository.CreateAuthSessionInput) (user.User, repository.AuthSession, error) {
 				if session.TokenHash == "" {
 					t.Fatalf("expected session token hash")
 				}
@@ -245,10 +262,15 @@ func TestAuthServiceRegisterUsesCreateWithSession(t *testing.T) {
 				}
 				called = true
 				value.ID = 1
-				return value, nil
+				return value, repository.AuthSession{ID: 3, UserID: 1}, nil
 			},
 		},
-		fakeTokenIssuer{},
+		fakeTokenIssuer{generateFn: func(_, sessionID uint) (string, error) {
+			if sessionID != 3 {
+				t.Fatalf("expected token bound to session 3, got %d", sessionID)
+			}
+			return "access-token", nil
+		}},
 		fakeAuthSessionStore{},
 	)
 	_, err := svc.Register(context.Background(), service.RegisterInput{
@@ -264,6 +286,65 @@ func TestAuthServiceRegisterUsesCreateWithSession(t *testing.T) {
 	}
 }
 
+func TestAuthServiceRegisterBreachedPassword(t *testing.T) {
+	t.Run("breached password is rejected", func(t *testing.T) {
+		svc := service.NewAuthService(
+			fakeUserAuthStore{
+				createWithSessionFn: func(_ context.Context, _ user.User, _ repository.CreateAuthSessionInput) (user.User, repository.AuthSession, error) {
+					t.Fatalf("expected user not to be created")
+					return user.User{}, repository.AuthSession{}, nil
+				},
+			},
+			fakeTokenIssuer{},
+			fakeAuthSessionStore{},
+		)
+		svc.SetBreachedPasswordChecker(fakeBreachedPasswordChecker{
+			isBreachedFn: func(password string) (bool, error) { return password == "SuperSecret1!", nil },
+		})
+		_, err := svc.Register(context.Background(), service.RegisterInput{
+			Name:     "A",
+			Email:    "a@example.com",
+			Password: "SuperSecret1!",
+		})
+		if !errors.Is(err, service.ErrPasswordCompromised) {
+			t.Fatalf("expected ErrPasswordCompromised, got %v", err)
+		}
+	})
+
+	t.Run("checker failure is returned", func(t *testing.T) {
+		checkErr := errors.New("read failed")
+		svc := service.NewAuthService(fakeUserAuthStore{}, fakeTokenIssuer{}, fakeAuthSessionStore{})
+		svc.SetBreachedPasswordChecker(fakeBreachedPasswordChecker{
+			isBreachedFn: func(_ string) (bool, error) { return false, checkErr },
+		})
+		_, err := svc.Register(context.Background(), service.RegisterInput{
+			Name:     "A",
+			Email:    "a@example.com",
+			Password: "SuperSecret1!",
+		})
+		if !errors.Is(err, checkErr) {
+			t.Fatalf("expected checker error, got %v", err)
+		}
+	})
+
+	t.Run("clean password registers with tracker and checker", func(t *testing.T) {
+		svc := service.NewAuthService(
+			fakeUserAuthStore{},
+			fakeTokenIssuer{},
+			fakeAuthSessionStore{},
+			fakeLoginAttemptTracker{},
+		)
+		svc.SetBreachedPasswordChecker(fakeBreachedPasswordChecker{})
+		if _, err := svc.Register(context.Background(), service.RegisterInput{
+			Name:     "A",
+			Email:    "a@example.com",
+			Password: "SuperSecret1!",
+		}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
 func TestAuthServiceLoginAttemptLockout(t *testing.T) {
 	t.Run("blocked login returns too many attempts", func(t *testing.T) {
 		svc := service.NewAuthService(
@@ -6,7 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"goal-bite-api/internal/catalog"
 	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/user"
 	"goal-bite-api/internal/repository"
 	"goal-bite-api/internal/service"
 )
@@ -14,11 +18,23 @@ import (
 type fakeFoodStore struct {
 	createFn     func(ctx context.Context, value food.Food) (food.Food, error)
 	getFn        func(ctx context.Context, id uint) (food.Food, error)
-	getBarcodeFn func(ctx context.Context, barcode string) (food.Food, error)
-	listFn       func(ctx context.Context, limit, offset int) ([]food.Food, error)
-	searchFn     func(ctx context.Context, query string, limit, offset int) ([]food.Food, error)
+	byIDsFn      func(ctx context.Context, ids []uint) ([]food.Food, error)
+	getBarcodeFn func(ctx context.Context, userID uint, barcode string) (food.Food, error)
+	getScopeFn   func(ctx context.Context, userID uint, visibility food.Visibility, barcode string) (food.Food, error)
+	listFn       func(ctx context.Context, q repository.FoodListQuery) ([]food.Food, error)
+	facetsFn     func(ctx context.Context, q repository.FoodListQuery) (repository.FoodFacets, error)
+	categoriesFn func(ctx context.Context) ([]food.Category, error)
+	categoryFn   func(ctx context.Context, id uint) (food.Category, error)
+	aliasFn      func(ctx context.Context, aliases []string) (food.Category, error)
 	updateFn     func(ctx context.Context, id uint, updates repository.FoodUpdate) (food.Food, error)
-	deleteFn     func(ctx context.Context, id uint) error
+	archiveFn    func(ctx context.Context, id uint, at time.Time) error
+	restoreFn    func(ctx context.Context, id uint) (food.Food, error)
+	versionsFn   func(ctx context.Context, foodID uint) ([]food.Version, error)
+	getVersionFn func(ctx context.Context, foodID uint, version int) (food.Version, error)
+	candidatesFn func(ctx context.Context, q repository.DuplicateCandidateQuery) ([]food.Food, error)
+	mergeFn      func(ctx context.Context, in repository.FoodMerge) (repository.FoodMergeResult, error)
+	suspectsFn   func(ctx context.Context, userID uint, limit, offset int) ([]food.Food, error)
+	setImageFn   func(ctx context.Context, id uint, img *repository.Image) (food.Food, error)
 }
 
 func (f fakeFoodStore) Create(ctx context.Context, value food.Food) (food.Food, error) {
@@ -35,25 +51,60 @@ func (f fakeFoodStore) GetByID(ctx context.Context, id uint) (food.Food, error)
 	return f.getFn(ctx, id)
 }
 
-func (f fakeFoodStore) GetByBarcode(ctx context.Context, barcode string) (food.Food, error) {
+func (f fakeFoodStore) ListByIDs(ctx context.Context, ids []uint) ([]food.Food, error) {
+	if f.byIDsFn == nil {
+		return nil, nil
+	}
+	return f.byIDsFn(ctx, ids)
+}
+
+func (f fakeFoodStore) GetByBarcode(ctx context.Context, userID uint, barcode string) (food.Food, error) {
 	if f.getBarcodeFn == nil {
 		return food.Food{}, repository.ErrNotFound
 	}
-	return f.getBarcodeFn(ctx, barcode)
+	return f.getBarcodeFn(ctx, userID, barcode)
+}
+
+func (f fakeFoodStore) GetByBarcodeInScope(ctx context.Context, userID uint, visibility food.Visibility, barcode string) (food.Food, error) {
+	if f.getScopeFn == nil {
+		return food.Food{}, repository.ErrNotFound
+	}
+	return f.getScopeFn(ctx, userID, visibility, barcode)
 }
 
-func (f fakeFoodStore) List(ctx context.Context, limit, offset int) ([]food.Food, error) {
+func (f fakeFoodStore) List(ctx context.Context, q repository.FoodListQuery) ([]food.Food, error) {
 	if f.listFn == nil {
 		return nil, nil
 	}
-	return f.listFn(ctx, limit, offset)
+	return f.listFn(ctx, q)
 }
 
-func (f fakeFoodStore) SearchByName(ctx context.Context, query string, limit, offset int) ([]food.Food, error) {
-	if f.searchFn == nil {
+func (f fakeFoodStore) Facets(ctx context.Context, q repository.FoodListQuery) (repository.FoodFacets, error) {
+	if f.facetsFn == nil {
+		return repository.FoodFacets{}, nil
+	}
+	return f.facetsFn(ctx, q)
+}
+
+func (f fakeFoodStore) ListCategories(ctx context.Context) ([]food.Category, error) {
+	if f.categoriesFn == nil {
 		return nil, nil
 	}
-	return f.searchFn(ctx, query, limit, offset)
+	return f.categoriesFn(ctx)
+}
+
+func (f fakeFoodStore) GetCategory(ctx context.Context, id uint) (food.Category, error) {
+	if f.categoryFn == nil {
+		return food.Category{ID: id}, nil
+	}
+	return f.categoryFn(ctx, id)
+}
+
+func (f fakeFoodStore) CategoryByAlias(ctx context.Context, aliases []string) (food.Category, error) {
+	if f.aliasFn == nil {
+		return food.Category{}, repository.ErrNotFound
+	}
+	return f.aliasFn(ctx, aliases)
 }
 
 func (f fakeFoodStore) Update(ctx context.Context, id uint, updates repository.FoodUpdate) (food.Food, error) {
@@ -63,11 +114,123 @@ func (f fakeFoodStore) Update(ctx context.Context, id uint, updates repository.F
 	return f.updateFn(ctx, id, updates)
 }
 
-func (f fakeFoodStore) Delete(ctx context.Context, id uint) error {
-	if f.deleteFn == nil {
+func (f fakeFoodStore) Archive(ctx context.Context, id uint, at time.Time) error {
+	if f.archiveFn == nil {
 		return nil
 	}
-	return f.deleteFn(ctx, id)
+	return f.archiveFn(ctx, id, at)
+}
+
+func (f fakeFoodStore) Restore(ctx context.Context, id uint) (food.Food, error) {
+	if f.restoreFn == nil {
+		return food.Food{ID: id}, nil
+	}
+	return f.restoreFn(ctx, id)
+}
+
+func (f fakeFoodStore) ListVersions(ctx context.Context, foodID uint) ([]food.Version, error) {
+	if f.versionsFn == nil {
+		return nil, nil
+	}
+	return f.versionsFn(ctx, foodID)
+}
+
+func (f fakeFoodStore) GetVersion(ctx context.Context, foodID uint, version int) (food.Version, error) {
+	if f.getVersionFn == nil {
+		return food.Version{}, repository.ErrNotFound
+	}
+	return f.getVersionFn(ctx, foodID, version)
+}
+
+func (f fakeFoodStore) ListDuplicateCandidates(ctx context.Context, q repository.DuplicateCandidateQuery) ([]food.Food, error) {
+	if f.candidatesFn == nil {
+		return nil, nil
+	}
+	return f.candidatesFn(ctx, q)
+}
+
+func (f fakeFoodStore) Merge(ctx context.Context, in repository.FoodMerge) (repository.FoodMergeResult, error) {
+	if f.mergeFn == nil {
+		return repository.FoodMergeResult{}, nil
+	}
+	return f.mergeFn(ctx, in)
+}
+
+func (f fakeFoodStore) ListNutritionSuspects(ctx context.Context, userID uint, limit, offset int) ([]food.Food, error) {
+	if f.suspectsFn == nil {
+		return nil, nil
+	}
+	return f.suspectsFn(ctx, userID, limit, offset)
+}
+
+func (f fakeFoodStore) SetImage(ctx context.Context, id uint, img *repository.Image) (food.Food, error) {
+	if f.setImageFn == nil {
+		return food.Food{ID: id}, nil
+	}
+	return f.setImageFn(ctx, id, img)
+}
+
+type fakeBarcodeProvider struct {
+	calls    int
+	products map[string]catalog.Product
+	err      error
+}
+
+func (f *fakeBarcodeProvider) Name() string {
+	return "fake"
+}
+
+func (f *fakeBarcodeProvider) Lookup(_ context.Context, barcode string) (catalog.Product, error) {
+	f.calls++
+	if f.err != nil {
+		return catalog.Product{}, f.err
+	}
+	product, ok := f.products[barcode]
+	if !ok {
+		return catalog.Product{}, catalog.ErrNotFound
+	}
+	return product, nil
+}
+
+type fakeBarcodeMisses map[string]time.Time
+
+func (f fakeBarcodeMisses) HasMiss(_ context.Context, source, barcode string, since time.Time) (bool, error) {
+	at, ok := f[source+":"+barcode]
+	return ok && !at.Before(since), nil
+}
+
+func (f fakeBarcodeMisses) RecordMiss(_ context.Context, source, barcode string, at time.Time) error {
+	f[source+":"+barcode] = at
+	return nil
+}
+
+type fakeRecipeRecalculator struct {
+	ids     []uint
+	foodIDs []uint
+	failing map[uint]bool
+}
+
+func (f *fakeRecipeRecalculator) Recalculate(_ context.Context, id uint) (recipe.Recipe, error) {
+	f.ids = append(f.ids, id)
+	if f.failing[id] {
+		return recipe.Recipe{}, service.ErrRecipeTooDeep
+	}
+	return recipe.Recipe{ID: id}, nil
+}
+
+func (f *fakeRecipeRecalculator) FoodChanged(_ context.Context, foodID uint) error {
+	f.foodIDs = append(f.foodIDs, foodID)
+	return nil
+}
+
+func (f *fakeRecipeRecalculator) RecipeChanged(_ context.Context, _ uint) error {
+	return nil
+}
+
+type fakeFoodAdmins map[uint]bool
+
+func (f fakeFoodAdmins) GetByID(_ context.Context, id uint) (user.User, error) {
+	return user.User{ID: id, IsAdmin: f[id]}, nil
 }
 
 func TestFoodService(t *testing.T) {
@@ -123,17 +286,17 @@ func TestFoodService(t *testing.T) {
 		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
 			return food.Food{}, repository.ErrNotFound
 		}})
-		_, err := svc.GetByID(context.Background(), 10)
+		_, err := svc.GetByID(context.Background(), 1, 10)
 		if !errors.Is(err, service.ErrFoodNotFound) {
 			t.Fatalf("expected ErrFoodNotFound, got %v", err)
 		}
 	})
 
 	t.Run("get by barcode maps not found", func(t *testing.T) {
-		svc := service.NewFoodService(fakeFoodStore{getBarcodeFn: func(_ context.Context, _ string) (food.Food, error) {
+		svc := service.NewFoodService(fakeFoodStore{getBarcodeFn: func(_ context.Context, _ uint, _ string) (food.Food, error) {
 			return food.Food{}, repository.ErrNotFound
 		}})
-		_, err := svc.GetByBarcode(context.Background(), "5901234123457")
+		_, err := svc.GetByBarcode(context.Background(), 1, "5901234123457")
 		if !errors.Is(err, service.ErrFoodBarcodeNotFound) {
 			t.Fatalf("expected ErrFoodBarcodeNotFound, got %v", err)
 		}
@@ -141,7 +304,7 @@ func TestFoodService(t *testing.T) {
 
 	t.Run("list validates pagination", func(t *testing.T) {
 		svc := service.NewFoodService(fakeFoodStore{})
-		_, err := svc.List(context.Background(), 0, 0)
+		_, err := svc.List(context.Background(), 1, service.FoodListInput{})
 		if !errors.Is(err, service.ErrInvalidPagination) {
 			t.Fatalf("expected ErrInvalidPagination, got %v", err)
 		}
@@ -149,7 +312,7 @@ func TestFoodService(t *testing.T) {
 
 	t.Run("search validates pagination", func(t *testing.T) {
 		svc := service.NewFoodService(fakeFoodStore{})
-		_, err := svc.Search(context.Background(), "egg", 0, 0)
+		_, err := svc.Search(context.Background(), 1, service.FoodListInput{Query: "egg"})
 		if !errors.Is(err, service.ErrInvalidPagination) {
 			t.Fatalf("expected ErrInvalidPagination, got %v", err)
 		}
@@ -158,19 +321,19 @@ func TestFoodService(t *testing.T) {
 	t.Run("search trims query and calls repo search", func(t *testing.T) {
 		called := false
 		svc := service.NewFoodService(fakeFoodStore{
-			searchFn: func(_ context.Context, query string, limit, offset int) ([]food.Food, error) {
+			listFn: func(_ context.Context, q repository.FoodListQuery) ([]food.Food, error) {
 				called = true
-				if query != "egg" || limit != 20 || offset != 0 {
-					t.Fatalf("unexpected args: query=%q limit=%d offset=%d", query, limit, offset)
+				if q.UserID != 3 || q.Query != "egg" || q.Limit != 20 || q.Offset != 0 {
+					t.Fatalf("unexpected query %+v", q)
 				}
 				return []food.Food{{ID: 1, Name: "Egg"}}, nil
 			},
 		})
-		values, err := svc.Search(context.Background(), "  egg  ", 20, 0)
+		values, err := svc.Search(context.Background(), 3, service.FoodListInput{Query: "  egg  ", Limit: 20})
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
-		if !called || len(values) != 1 {
+		if !called || len(values.Items) != 1 {
 			t.Fatalf("expected one value from search")
 		}
 	})
@@ -228,4 +391,840 @@ func TestFoodService(t *testing.T) {
 			t.Fatalf("expected ErrFoodForbidden, got %v", err)
 		}
 	})
+
+	t.Run("create defaults to private visibility", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		got, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Rice"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got.Visibility != food.VisibilityPrivate {
+			t.Fatalf("expected private visibility, got %q", got.Visibility)
+		}
+	})
+
+	t.Run("create rejects unknown visibility", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		_, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Rice", Visibility: "friends"})
+		if !errors.Is(err, service.ErrInvalidFoodVisibility) {
+			t.Fatalf("expected ErrInvalidFoodVisibility, got %v", err)
+		}
+	})
+
+	t.Run("create verified requires admin", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		svc.SetAdminReader(fakeFoodAdmins{2: true})
+		_, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Rice", Visibility: food.VisibilityVerified})
+		if !errors.Is(err, service.ErrFoodVerificationForbidden) {
+			t.Fatalf("expected ErrFoodVerificationForbidden, got %v", err)
+		}
+		if _, err := svc.Create(context.Background(), 2, service.CreateFoodInput{Name: "Rice", Visibility: food.VisibilityVerified}); err != nil {
+			t.Fatalf("expected admin create to succeed, got %v", err)
+		}
+	})
+
+	t.Run("create checks barcode within visibility scope", func(t *testing.T) {
+		barcode := "5901234123457"
+		svc := service.NewFoodService(fakeFoodStore{
+			getScopeFn: func(_ context.Context, userID uint, visibility food.Visibility, got string) (food.Food, error) {
+				if got != barcode {
+					t.Fatalf("unexpected barcode %q", got)
+				}
+				// Only another user's public entry holds the barcode.
+				if visibility == food.VisibilityPublic {
+					return food.Food{ID: 5, UserID: 9, Visibility: food.VisibilityPublic}, nil
+				}
+				if visibility != food.VisibilityPrivate || userID != 1 {
+					t.Fatalf("unexpected scope user=%d visibility=%q", userID, visibility)
+				}
+				return food.Food{}, repository.ErrNotFound
+			},
+		})
+		if _, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Rice", Barcode: &barcode}); err != nil {
+			t.Fatalf("expected private create to succeed, got %v", err)
+		}
+		_, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Rice", Barcode: &barcode, Visibility: food.VisibilityPublic})
+		if !errors.Is(err, service.ErrFoodBarcodeExists) {
+			t.Fatalf("expected ErrFoodBarcodeExists, got %v", err)
+		}
+	})
+
+	t.Run("get by id hides other users private foods", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+			return food.Food{ID: 1, UserID: 9, Visibility: food.VisibilityPrivate}, nil
+		}})
+		if _, err := svc.GetByID(context.Background(), 9, 1); err != nil {
+			t.Fatalf("expected owner to see food, got %v", err)
+		}
+		_, err := svc.GetByID(context.Background(), 7, 1)
+		if !errors.Is(err, service.ErrFoodNotFound) {
+			t.Fatalf("expected ErrFoodNotFound, got %v", err)
+		}
+	})
+
+	t.Run("list scopes to caller", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{
+			listFn: func(_ context.Context, q repository.FoodListQuery) ([]food.Food, error) {
+				if q.UserID != 4 {
+					t.Fatalf("expected caller 4, got %d", q.UserID)
+				}
+				return nil, nil
+			},
+		})
+		if _, err := svc.List(context.Background(), 4, service.FoodListInput{Limit: 20}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("update of verified food requires admin", func(t *testing.T) {
+		name := "New Rice"
+		store := fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7, Visibility: food.VisibilityVerified}, nil
+			},
+			updateFn: func(_ context.Context, _ uint, updates repository.FoodUpdate) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7, Name: *updates.Name, Visibility: food.VisibilityVerified}, nil
+			},
+		}
+		svc := service.NewFoodService(store)
+		svc.SetAdminReader(fakeFoodAdmins{2: true})
+		_, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{Name: &name})
+		if !errors.Is(err, service.ErrFoodForbidden) {
+			t.Fatalf("expected ErrFoodForbidden for owner, got %v", err)
+		}
+		if _, err := svc.Update(context.Background(), 2, 1, service.UpdateFoodInput{Name: &name}); err != nil {
+			t.Fatalf("expected admin update to succeed, got %v", err)
+		}
+	})
+
+	t.Run("update to verified requires admin", func(t *testing.T) {
+		verified := food.VisibilityVerified
+		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+			return food.Food{ID: 1, UserID: 7, Visibility: food.VisibilityPublic}, nil
+		}})
+		_, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{Visibility: &verified})
+		if !errors.Is(err, service.ErrFoodVerificationForbidden) {
+			t.Fatalf("expected ErrFoodVerificationForbidden, got %v", err)
+		}
+	})
+
+	t.Run("update visibility rechecks barcode in new scope", func(t *testing.T) {
+		public := food.VisibilityPublic
+		barcode := "5901234123457"
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7, Barcode: &barcode, Visibility: food.VisibilityPrivate}, nil
+			},
+			getScopeFn: func(_ context.Context, _ uint, visibility food.Visibility, _ string) (food.Food, error) {
+				if visibility != food.VisibilityPublic {
+					t.Fatalf("expected public scope, got %q", visibility)
+				}
+				return food.Food{ID: 5, UserID: 9, Visibility: food.VisibilityPublic}, nil
+			},
+		})
+		_, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{Visibility: &public})
+		if !errors.Is(err, service.ErrFoodBarcodeExists) {
+			t.Fatalf("expected ErrFoodBarcodeExists, got %v", err)
+		}
+	})
+
+	t.Run("verify requires admin", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		_, err := svc.Verify(context.Background(), 7, 1)
+		if !errors.Is(err, service.ErrFoodVerificationForbidden) {
+			t.Fatalf("expected ErrFoodVerificationForbidden, got %v", err)
+		}
+	})
+
+	t.Run("verify promotes public food", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7, Visibility: food.VisibilityPublic}, nil
+			},
+			updateFn: func(_ context.Context, _ uint, updates repository.FoodUpdate) (food.Food, error) {
+				if updates.Visibility == nil || *updates.Visibility != food.VisibilityVerified {
+					t.Fatalf("expected verified update, got %#v", updates.Visibility)
+				}
+				return food.Food{ID: 1, UserID: 7, Visibility: food.VisibilityVerified}, nil
+			},
+		})
+		svc.SetAdminReader(fakeFoodAdmins{2: true})
+		got, err := svc.Verify(context.Background(), 2, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got.Visibility != food.VisibilityVerified {
+			t.Fatalf("expected verified food, got %q", got.Visibility)
+		}
+	})
+
+	t.Run("update records editor", func(t *testing.T) {
+		name := "Oats"
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7}, nil
+			},
+			updateFn: func(_ context.Context, _ uint, updates repository.FoodUpdate) (food.Food, error) {
+				if updates.EditedBy != 7 {
+					t.Fatalf("expected editor 7, got %d", updates.EditedBy)
+				}
+				return food.Food{ID: 1, UserID: 7, Name: name, CurrentVersion: 2}, nil
+			},
+		})
+		if _, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{Name: &name}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("list versions hides other users private foods", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 9, Visibility: food.VisibilityPrivate}, nil
+			},
+			versionsFn: func(_ context.Context, _ uint) ([]food.Version, error) {
+				t.Fatalf("versions must not be read for hidden food")
+				return nil, nil
+			},
+		})
+		_, err := svc.ListVersions(context.Background(), 7, 1)
+		if !errors.Is(err, service.ErrFoodNotFound) {
+			t.Fatalf("expected ErrFoodNotFound, got %v", err)
+		}
+	})
+
+	t.Run("rollback restores version values", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				brand := "Acme"
+				return food.Food{ID: 1, UserID: 7, Name: "Oats v3", BrandName: &brand, KcalPer100g: 500, CurrentVersion: 3}, nil
+			},
+			getVersionFn: func(_ context.Context, foodID uint, version int) (food.Version, error) {
+				if foodID != 1 || version != 1 {
+					t.Fatalf("unexpected version lookup food=%d version=%d", foodID, version)
+				}
+				return food.Version{FoodID: 1, Version: 1, Name: "Oats", KcalPer100g: 389}, nil
+			},
+			updateFn: func(_ context.Context, _ uint, updates repository.FoodUpdate) (food.Food, error) {
+				if updates.Name == nil || *updates.Name != "Oats" || *updates.KcalPer100g != 389 {
+					t.Fatalf("expected version 1 values, got %#v", updates)
+				}
+				if !updates.ClearBrandName || !updates.ClearBarcode {
+					t.Fatalf("expected missing brand and barcode to be cleared")
+				}
+				if updates.RestoredFrom == nil || *updates.RestoredFrom != 1 || updates.EditedBy != 7 {
+					t.Fatalf("expected rollback provenance, got %#v", updates)
+				}
+				if updates.Visibility != nil {
+					t.Fatalf("rollback must not change visibility")
+				}
+				return food.Food{ID: 1, UserID: 7, Name: "Oats", KcalPer100g: 389, CurrentVersion: 4}, nil
+			},
+		})
+		got, err := svc.Rollback(context.Background(), 7, 1, 1)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if got.CurrentVersion != 4 {
+			t.Fatalf("expected new version 4, got %d", got.CurrentVersion)
+		}
+	})
+
+	t.Run("rollback maps missing version", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+			return food.Food{ID: 1, UserID: 7}, nil
+		}})
+		_, err := svc.Rollback(context.Background(), 7, 1, 9)
+		if !errors.Is(err, service.ErrFoodVersionNotFound) {
+			t.Fatalf("expected ErrFoodVersionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("rollback forbidden for non owner", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+			return food.Food{ID: 1, UserID: 9, Visibility: food.VisibilityPublic}, nil
+		}})
+		_, err := svc.Rollback(context.Background(), 7, 1, 1)
+		if !errors.Is(err, service.ErrFoodForbidden) {
+			t.Fatalf("expected ErrFoodForbidden, got %v", err)
+		}
+	})
+
+	t.Run("delete archives the food", func(t *testing.T) {
+		archived := false
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7}, nil
+			},
+			archiveFn: func(_ context.Context, id uint, at time.Time) error {
+				if id != 1 || at.IsZero() {
+					t.Fatalf("unexpected archive args: %d %v", id, at)
+				}
+				archived = true
+				return nil
+			},
+		})
+		if err := svc.Delete(context.Background(), 7, 1); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !archived {
+			t.Fatal("expected food to be archived")
+		}
+	})
+
+	t.Run("update rejects archived food", func(t *testing.T) {
+		archivedAt := time.Now().UTC()
+		name := "Rice"
+		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+			return food.Food{ID: 1, UserID: 7, ArchivedAt: &archivedAt}, nil
+		}})
+		_, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{Name: &name})
+		if !errors.Is(err, service.ErrFoodArchived) {
+			t.Fatalf("expected ErrFoodArchived, got %v", err)
+		}
+	})
+
+	t.Run("restore conflicts when barcode was taken", func(t *testing.T) {
+		archivedAt := time.Now().UTC()
+		barcode := "5901234123457"
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7, Barcode: &barcode, Visibility: food.VisibilityPrivate, ArchivedAt: &archivedAt}, nil
+			},
+			getScopeFn: func(_ context.Context, _ uint, _ food.Visibility, _ string) (food.Food, error) {
+				return food.Food{ID: 2, UserID: 7}, nil
+			},
+		})
+		_, err := svc.Restore(context.Background(), 7, 1)
+		if !errors.Is(err, service.ErrFoodBarcodeExists) {
+			t.Fatalf("expected ErrFoodBarcodeExists, got %v", err)
+		}
+	})
+
+	t.Run("find duplicates scores reordered names and skips unrelated foods", func(t *testing.T) {
+		var query repository.DuplicateCandidateQuery
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, id uint) (food.Food, error) {
+				return food.Food{ID: id, UserID: 7, Name: "Yogurt, greek", KcalPer100g: 97, ProteinPer100g: 9, CarbsPer100g: 4, FatPer100g: 5, Visibility: food.VisibilityPublic}, nil
+			},
+			candidatesFn: func(_ context.Context, q repository.DuplicateCandidateQuery) ([]food.Food, error) {
+				query = q
+				return []food.Food{
+					{ID: 2, Name: "Greek yogurt 2%", KcalPer100g: 73, ProteinPer100g: 10, CarbsPer100g: 4, FatPer100g: 2},
+					{ID: 3, Name: "Greek yogurt", KcalPer100g: 97, ProteinPer100g: 9, CarbsPer100g: 4, FatPer100g: 5},
+					{ID: 4, Name: "Greek salad", KcalPer100g: 90, ProteinPer100g: 3, CarbsPer100g: 5, FatPer100g: 7},
+				}, nil
+			},
+		})
+		values, err := svc.FindDuplicates(context.Background(), 7, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if query.ExcludeID != 1 || len(query.NameTerms) != 2 {
+			t.Fatalf("unexpected candidate query: %+v", query)
+		}
+		if len(values) != 2 || values[0].Food.ID != 3 || values[1].Food.ID != 2 {
+			t.Fatalf("unexpected duplicates: %+v", values)
+		}
+		if values[0].Score != 1 {
+			t.Fatalf("expected exact duplicate to score 1, got %v", values[0].Score)
+		}
+	})
+
+	t.Run("find duplicates treats shared barcode as a match", func(t *testing.T) {
+		barcode := "5901234123457"
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, id uint) (food.Food, error) {
+				return food.Food{ID: id, UserID: 7, Name: "Oats", Barcode: &barcode, KcalPer100g: 389}, nil
+			},
+			candidatesFn: func(_ context.Context, _ repository.DuplicateCandidateQuery) ([]food.Food, error) {
+				return []food.Food{{ID: 2, Name: "Porridge flakes", Barcode: &barcode, KcalPer100g: 370}}, nil
+			},
+		})
+		values, err := svc.FindDuplicates(context.Background(), 7, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(values) != 1 || !values[0].BarcodeMatch || values[0].Score != 1 {
+			t.Fatalf("unexpected duplicates: %+v", values)
+		}
+	})
+
+	t.Run("merge requires admin", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		svc.SetAdminReader(fakeFoodAdmins{})
+		_, err := svc.Merge(context.Background(), 7, 1, []uint{2})
+		if !errors.Is(err, service.ErrFoodMergeForbidden) {
+			t.Fatalf("expected ErrFoodMergeForbidden, got %v", err)
+		}
+	})
+
+	t.Run("merge rejects survivor in merged list", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		svc.SetAdminReader(fakeFoodAdmins{1: true})
+		_, err := svc.Merge(context.Background(), 1, 3, []uint{2, 3})
+		if !errors.Is(err, service.ErrInvalidFoodMerge) {
+			t.Fatalf("expected ErrInvalidFoodMerge, got %v", err)
+		}
+	})
+
+	t.Run("merge rejects public food into private survivor", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, id uint) (food.Food, error) {
+			if id == 1 {
+				return food.Food{ID: 1, UserID: 1, Visibility: food.VisibilityPrivate}, nil
+			}
+			return food.Food{ID: id, UserID: 1, Visibility: food.VisibilityPublic}, nil
+		}})
+		svc.SetAdminReader(fakeFoodAdmins{1: true})
+		_, err := svc.Merge(context.Background(), 1, 1, []uint{2})
+		if !errors.Is(err, service.ErrInvalidFoodMerge) {
+			t.Fatalf("expected ErrInvalidFoodMerge, got %v", err)
+		}
+	})
+
+	t.Run("merge recalculates affected recipes", func(t *testing.T) {
+		recalculator := &fakeRecipeRecalculator{}
+		var got repository.FoodMerge
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, id uint) (food.Food, error) {
+				return food.Food{ID: id, UserID: 9, Visibility: food.VisibilityPublic}, nil
+			},
+			mergeFn: func(_ context.Context, in repository.FoodMerge) (repository.FoodMergeResult, error) {
+				got = in
+				return repository.FoodMergeResult{
+					Merges:    []food.Merge{{SurvivorFoodID: 1, MergedFoodID: 2, MealItemsMoved: 3}},
+					RecipeIDs: []uint{5, 6},
+				}, nil
+			},
+		})
+		svc.SetAdminReader(fakeFoodAdmins{1: true})
+		svc.SetRecipeRecalculator(recalculator)
+		result, err := svc.Merge(context.Background(), 1, 1, []uint{2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.SurvivorID != 1 || len(got.MergedIDs) != 1 || got.MergedBy != 1 {
+			t.Fatalf("unexpected merge input: %+v", got)
+		}
+		if len(recalculator.ids) != 2 || len(result.RecalculatedRecipeIDs) != 2 || len(result.Merges) != 1 {
+			t.Fatalf("unexpected result: %+v (recalculated %v)", result, recalculator.ids)
+		}
+	})
+
+	t.Run("merge succeeds when a recipe fails to recalculate", func(t *testing.T) {
+		recalculator := &fakeRecipeRecalculator{failing: map[uint]bool{5: true}}
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, id uint) (food.Food, error) {
+				return food.Food{ID: id, UserID: 9, Visibility: food.VisibilityPublic}, nil
+			},
+			mergeFn: func(_ context.Context, _ repository.FoodMerge) (repository.FoodMergeResult, error) {
+				return repository.FoodMergeResult{Merges: []food.Merge{{SurvivorFoodID: 1, MergedFoodID: 2}}, RecipeIDs: []uint{5, 6}}, nil
+			},
+		})
+		svc.SetAdminReader(fakeFoodAdmins{1: true})
+		svc.SetRecipeRecalculator(recalculator)
+		result, err := svc.Merge(context.Background(), 1, 1, []uint{2})
+		if err != nil {
+			t.Fatalf("expected the committed merge to succeed, got %v", err)
+		}
+		if len(recalculator.ids) != 2 || len(result.RecalculatedRecipeIDs) != 1 || result.RecalculatedRecipeIDs[0] != 6 {
+			t.Fatalf("expected recipe 6 recalculated past the failing 5, got %+v (tried %v)", result, recalculator.ids)
+		}
+	})
+
+	t.Run("barcode miss imports product from provider", func(t *testing.T) {
+		brand := "Fage"
+		provider := &fakeBarcodeProvider{products: map[string]catalog.Product{
+			"5200435000027": {Barcode: "5200435000027", Name: "Total Greek Yoghurt", BrandName: &brand, KcalPer100g: 97, ProteinPer100g: 9, Ref: "https://example.com/p/5200435000027"},
+		}}
+		var created food.Food
+		svc := service.NewFoodService(fakeFoodStore{createFn: func(_ context.Context, value food.Food) (food.Food, error) {
+			created = value
+			value.ID = 11
+			return value, nil
+		}})
+		svc.SetBarcodeLookup(service.BarcodeLookup{Provider: provider, Misses: fakeBarcodeMisses{}, MissTTL: time.Hour})
+
+		value, err := svc.GetByBarcode(context.Background(), 7, "5200435000027")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if value.ID != 11 || created.UserID != 7 || created.Visibility != food.VisibilityPublic {
+			t.Fatalf("unexpected import: %+v", created)
+		}
+		if created.Source != "fake" || created.SourceRef == nil || created.ImportedAt == nil || !created.Imported() {
+			t.Fatalf("expected provenance on imported food, got %+v", created)
+		}
+	})
+
+	t.Run("barcode import maps source categories", func(t *testing.T) {
+		provider := &fakeBarcodeProvider{products: map[string]catalog.Product{
+			"5200435000027": {Barcode: "5200435000027", Name: "Total Greek Yoghurt", KcalPer100g: 97, Categories: []string{"en:dairies", "en:yogurts"}},
+		}}
+		var created food.Food
+		svc := service.NewFoodService(fakeFoodStore{
+			aliasFn: func(_ context.Context, aliases []string) (food.Category, error) {
+				if len(aliases) != 2 {
+					t.Fatalf("expected source categories, got %v", aliases)
+				}
+				return food.Category{ID: 16, Slug: "yogurt"}, nil
+			},
+			createFn: func(_ context.Context, value food.Food) (food.Food, error) {
+				created = value
+				return value, nil
+			},
+		})
+		svc.SetBarcodeLookup(service.BarcodeLookup{Provider: provider})
+
+		if _, err := svc.GetByBarcode(context.Background(), 7, "5200435000027"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created.CategoryID == nil || *created.CategoryID != 16 {
+			t.Fatalf("expected category 16, got %v", created.CategoryID)
+		}
+	})
+
+	t.Run("barcode miss is cached", func(t *testing.T) {
+		provider := &fakeBarcodeProvider{}
+		misses := fakeBarcodeMisses{}
+		svc := service.NewFoodService(fakeFoodStore{})
+		svc.SetBarcodeLookup(service.BarcodeLookup{Provider: provider, Misses: misses, MissTTL: time.Hour})
+
+		for i := 0; i < 2; i++ {
+			_, err := svc.GetByBarcode(context.Background(), 7, "4000000000000")
+			if !errors.Is(err, service.ErrFoodBarcodeNotFound) {
+				t.Fatalf("expected ErrFoodBarcodeNotFound, got %v", err)
+			}
+		}
+		if provider.calls != 1 || len(misses) != 1 {
+			t.Fatalf("expected one provider call and one cached miss, got %d calls and %v", provider.calls, misses)
+		}
+	})
+
+	t.Run("barcode provider failure is not cached", func(t *testing.T) {
+		provider := &fakeBarcodeProvider{err: catalog.ErrUnavailable}
+		misses := fakeBarcodeMisses{}
+		svc := service.NewFoodService(fakeFoodStore{})
+		svc.SetBarcodeLookup(service.BarcodeLookup{Provider: provider, Misses: misses, MissTTL: time.Hour})
+
+		_, err := svc.GetByBarcode(context.Background(), 7, "4000000000000")
+		if !errors.Is(err, service.ErrBarcodeLookupUnavailable) {
+			t.Fatalf("expected ErrBarcodeLookupUnavailable, got %v", err)
+		}
+		if len(misses) != 0 {
+			t.Fatalf("expected no cached miss, got %v", misses)
+		}
+	})
+
+	t.Run("imported food is editable only by admins", func(t *testing.T) {
+		name := "Yoghurt"
+		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+			return food.Food{ID: 1, UserID: 7, Visibility: food.VisibilityPublic, Source: "open_food_facts"}, nil
+		}})
+		svc.SetAdminReader(fakeFoodAdmins{})
+		_, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{Name: &name})
+		if !errors.Is(err, service.ErrFoodForbidden) {
+			t.Fatalf("expected ErrFoodForbidden, got %v", err)
+		}
+	})
+
+	t.Run("create returns nutrition warnings in lenient mode", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		got, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Oil", KcalPer100g: 50, FatPer100g: 40})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got.Warnings) != 1 || got.Warnings[0].Code != food.WarningKcalMismatch {
+			t.Fatalf("expected kcal_mismatch warning, got %#v", got.Warnings)
+		}
+	})
+
+	t.Run("create rejects inconsistent nutrition in strict mode", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{createFn: func(_ context.Context, _ food.Food) (food.Food, error) {
+			t.Fatal("expected create to be rejected")
+			return food.Food{}, nil
+		}})
+		_, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Bar", KcalPer100g: 400, ProteinPer100g: 60, CarbsPer100g: 50, FatPer100g: 0, StrictNutrition: true})
+		if !errors.Is(err, service.ErrInconsistentNutrition) {
+			t.Fatalf("expected ErrInconsistentNutrition, got %v", err)
+		}
+		var nutritionErr *service.NutritionError
+		if !errors.As(err, &nutritionErr) || len(nutritionErr.Warnings) != 1 || nutritionErr.Warnings[0].Code != food.WarningMacrosExceed100g {
+			t.Fatalf("expected macros_exceed_100g, got %v", err)
+		}
+	})
+
+	t.Run("create counts alcohol energy", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		got, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Wine", KcalPer100g: 83, CarbsPer100g: 2.6, AlcoholPer100g: 10.3, StrictNutrition: true})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got.Warnings) != 0 {
+			t.Fatalf("expected no warnings, got %#v", got.Warnings)
+		}
+	})
+
+	t.Run("update rejects inconsistent nutrition in strict mode", func(t *testing.T) {
+		kcal := 20.0
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7, KcalPer100g: 130, ProteinPer100g: 2.7, CarbsPer100g: 28, FatPer100g: 0.3}, nil
+			},
+			updateFn: func(_ context.Context, _ uint, _ repository.FoodUpdate) (food.Food, error) {
+				t.Fatal("expected update to be rejected")
+				return food.Food{}, nil
+			},
+		})
+		_, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{KcalPer100g: &kcal, StrictNutrition: true})
+		if !errors.Is(err, service.ErrInconsistentNutrition) {
+			t.Fatalf("expected ErrInconsistentNutrition, got %v", err)
+		}
+	})
+
+	t.Run("nutrition report lists failed checks", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{suspectsFn: func(_ context.Context, _ uint, _, _ int) ([]food.Food, error) {
+			return []food.Food{
+				{ID: 1, Name: "Oil", KcalPer100g: 50, FatPer100g: 40},
+				{ID: 2, Name: "Rice", KcalPer100g: 130, ProteinPer100g: 2.7, CarbsPer100g: 28, FatPer100g: 0.3},
+			}, nil
+		}})
+		got, err := svc.NutritionReport(context.Background(), 1, 20, 0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got) != 1 || got[0].Food.ID != 1 || got[0].EstimatedKcal != 360 {
+			t.Fatalf("unexpected report %#v", got)
+		}
+	})
+
+	t.Run("create normalizes tags", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{createFn: func(_ context.Context, value food.Food) (food.Food, error) {
+			return value, nil
+		}})
+		got, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Oats", KcalPer100g: 389, ProteinPer100g: 16.9, CarbsPer100g: 66.3, FatPer100g: 6.9, Tags: []string{" Breakfast ", "high  protein", "breakfast"}})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got.Tags) != 2 || got.Tags[0] != "breakfast" || got.Tags[1] != "high protein" {
+			t.Fatalf("unexpected tags %q", got.Tags)
+		}
+	})
+
+	t.Run("create rejects empty tag", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		_, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Oats", Tags: []string{"  "}})
+		if !errors.Is(err, service.ErrInvalidFoodTags) {
+			t.Fatalf("expected ErrInvalidFoodTags, got %v", err)
+		}
+	})
+
+	t.Run("create rejects unknown category", func(t *testing.T) {
+		categoryID := uint(999)
+		svc := service.NewFoodService(fakeFoodStore{categoryFn: func(_ context.Context, _ uint) (food.Category, error) {
+			return food.Category{}, repository.ErrNotFound
+		}})
+		_, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Oats", CategoryID: &categoryID})
+		if !errors.Is(err, service.ErrFoodCategoryNotFound) {
+			t.Fatalf("expected ErrFoodCategoryNotFound, got %v", err)
+		}
+	})
+
+	t.Run("update with category 0 clears category", func(t *testing.T) {
+		categoryID := uint(0)
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7}, nil
+			},
+			updateFn: func(_ context.Context, _ uint, updates repository.FoodUpdate) (food.Food, error) {
+				if !updates.ClearCategory || updates.CategoryID != nil {
+					t.Fatalf("expected category to be cleared, got %+v", updates)
+				}
+				return food.Food{ID: 1, UserID: 7}, nil
+			},
+		})
+		if _, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{CategoryID: &categoryID}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("search returns facets for all matches", func(t *testing.T) {
+		categoryID := uint(1)
+		svc := service.NewFoodService(fakeFoodStore{
+			listFn: func(_ context.Context, q repository.FoodListQuery) ([]food.Food, error) {
+				if q.CategoryID == nil || *q.CategoryID != 1 || len(q.Tags) != 1 || q.Tags[0] != "breakfast" {
+					t.Fatalf("unexpected query %+v", q)
+				}
+				return []food.Food{{ID: 3, Name: "Yogurt"}}, nil
+			},
+			facetsFn: func(_ context.Context, _ repository.FoodListQuery) (repository.FoodFacets, error) {
+				return repository.FoodFacets{
+					Categories: []repository.FoodCategoryCount{{CategoryID: 16, Slug: "yogurt", Name: "Yogurt", Count: 4}},
+					Tags:       []repository.FoodTagCount{{Tag: "breakfast", Count: 4}},
+				}, nil
+			},
+		})
+		got, err := svc.Search(context.Background(), 1, service.FoodListInput{CategoryID: &categoryID, Tags: []string{"Breakfast"}, Limit: 1})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got.Items) != 1 || len(got.Facets.Categories) != 1 || got.Facets.Categories[0].Count != 4 || len(got.Facets.Tags) != 1 {
+			t.Fatalf("unexpected result %+v", got)
+		}
+	})
+
+	t.Run("create normalizes allergens and diet flags", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		got, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Tofu", Allergens: []string{" Soybeans ", "soybeans"}, DietFlags: []string{"Vegan", "gluten_free"}})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got.Allergens) != 1 || got.Allergens[0] != "soybeans" {
+			t.Fatalf("unexpected allergens %q", got.Allergens)
+		}
+		if len(got.DietFlags) != 3 || got.DietFlags[0] != "gluten_free" || got.DietFlags[1] != "vegan" || got.DietFlags[2] != "vegetarian" {
+			t.Fatalf("unexpected diet flags %q", got.DietFlags)
+		}
+	})
+
+	t.Run("create rejects unknown allergen", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		_, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Tofu", Allergens: []string{"kiwi"}})
+		if !errors.Is(err, service.ErrInvalidDietaryInfo) {
+			t.Fatalf("expected ErrInvalidDietaryInfo, got %v", err)
+		}
+	})
+
+	t.Run("update rejects diet flag contradicted by stored allergens", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7, Allergens: []string{"milk"}}, nil
+			},
+		})
+		flags := []string{"vegan"}
+		_, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{DietFlags: &flags})
+		if !errors.Is(err, service.ErrInvalidDietaryInfo) {
+			t.Fatalf("expected ErrInvalidDietaryInfo, got %v", err)
+		}
+	})
+
+	t.Run("list excludes conflicts with the caller's restrictions", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{
+			listFn: func(_ context.Context, q repository.FoodListQuery) ([]food.Food, error) {
+				if len(q.AvoidAllergens) != 1 || q.AvoidAllergens[0] != "peanuts" || len(q.RequiredDietFlags) != 1 || q.RequiredDietFlags[0] != "halal" {
+					t.Fatalf("unexpected query %+v", q)
+				}
+				return nil, nil
+			},
+		})
+		svc.SetRestrictionReader(fakeUserReader{result: user.User{ID: 1, AvoidAllergens: []string{"peanuts"}, RequiredDietFlags: []string{"halal"}}})
+		if _, err := svc.List(context.Background(), 1, service.FoodListInput{ExcludeConflicts: true, Limit: 20}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("create normalizes translations", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		got, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Rice", Translations: []locale.Translation{
+			{Locale: "fr-FR", Name: " Riz "},
+			{Locale: "DE", Name: "Reis", Synonyms: []string{"Langkornreis", "reis", "langkornreis"}},
+		}})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got.Translations) != 2 || got.Translations[0].Locale != "de" || got.Translations[1].Locale != "fr" || got.Translations[1].Name != "Riz" {
+			t.Fatalf("unexpected translations %+v", got.Translations)
+		}
+		if synonyms := got.Translations[0].Synonyms; len(synonyms) != 1 || synonyms[0] != "Langkornreis" {
+			t.Fatalf("unexpected synonyms %q", synonyms)
+		}
+	})
+
+	t.Run("create rejects repeated locale", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{})
+		_, err := svc.Create(context.Background(), 1, service.CreateFoodInput{Name: "Rice", Translations: []locale.Translation{
+			{Locale: "de", Name: "Reis"},
+			{Locale: "de-AT", Name: "Reis"},
+		}})
+		if !errors.Is(err, service.ErrInvalidTranslations) {
+			t.Fatalf("expected ErrInvalidTranslations, got %v", err)
+		}
+	})
+
+	t.Run("update replaces translations", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 1, UserID: 7}, nil
+			},
+			updateFn: func(_ context.Context, _ uint, updates repository.FoodUpdate) (food.Food, error) {
+				if updates.Translations == nil || len(*updates.Translations) != 0 {
+					t.Fatalf("expected translations to be cleared, got %v", updates.Translations)
+				}
+				return food.Food{ID: 1}, nil
+			},
+		})
+		translations := []locale.Translation{}
+		if _, err := svc.Update(context.Background(), 7, 1, service.UpdateFoodInput{Translations: &translations}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("nutrition changes recalculate dependent recipes", func(t *testing.T) {
+		recalculator := &fakeRecipeRecalculator{}
+		svc := service.NewFoodService(fakeFoodStore{
+			getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{ID: 3, UserID: 7}, nil
+			},
+			updateFn: func(_ context.Context, id uint, _ repository.FoodUpdate) (food.Food, error) {
+				return food.Food{ID: id}, nil
+			},
+		})
+		svc.SetRecipeRecalculator(recalculator)
+
+		name := "Chicken breast"
+		if _, err := svc.Update(context.Background(), 7, 3, service.UpdateFoodInput{Name: &name}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(recalculator.foodIDs) != 0 {
+			t.Fatalf("expected a name change to leave recipes alone, got %v", recalculator.foodIDs)
+		}
+		protein := 31.0
+		if _, err := svc.Update(context.Background(), 7, 3, service.UpdateFoodInput{ProteinPer100g: &protein}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(recalculator.foodIDs) != 1 || recalculator.foodIDs[0] != 3 {
+			t.Fatalf("expected recipes of food 3 to follow, got %v", recalculator.foodIDs)
+		}
+	})
+
+	t.Run("list searches in the given languages", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{
+			listFn: func(_ context.Context, q repository.FoodListQuery) ([]food.Food, error) {
+				if q.Query != "reis" || len(q.Languages) != 2 || q.Languages[0] != "de" || q.Languages[1] != "en" {
+					t.Fatalf("unexpected query %+v", q)
+				}
+				return nil, nil
+			},
+		})
+		if _, err := svc.List(context.Background(), 1, service.FoodListInput{Query: " reis ", Languages: []string{"de", "en"}, Limit: 20}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("categories are returned as a tree", func(t *testing.T) {
+		dairy := uint(1)
+		svc := service.NewFoodService(fakeFoodStore{categoriesFn: func(_ context.Context) ([]food.Category, error) {
+			return []food.Category{
+				{ID: 1, Slug: "dairy", Name: "Dairy"},
+				{ID: 2, Slug: "fruits", Name: "Fruits"},
+				{ID: 16, ParentID: &dairy, Slug: "yogurt", Name: "Yogurt"},
+			}, nil
+		}})
+		got, err := svc.Categories(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got) != 2 || len(got[0].Children) != 1 || got[0].Children[0].Slug != "yogurt" || len(got[1].Children) != 0 {
+			t.Fatalf("unexpected tree %+v", got)
+		}
+	})
 }
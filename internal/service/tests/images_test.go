@@ -0,0 +1,202 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/meal"
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/repository"
+	"goal-bite-api/internal/service"
+)
+
+type fakeBlobStore struct {
+	objects map[string]string
+	putErr  error
+}
+
+func (f *fakeBlobStore) Put(_ context.Context, key, contentType string, _ []byte) error {
+	if f.putErr != nil {
+		return f.putErr
+	}
+	f.objects[key] = contentType
+	return nil
+}
+
+func (f *fakeBlobStore) Delete(_ context.Context, key string) error {
+	delete(f.objects, key)
+	return nil
+}
+
+func (f *fakeBlobStore) URL(key string) string {
+	return "https://cdn.test/" + key
+}
+
+func (f *fakeBlobStore) keys() []string {
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 40, 20))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFoodServiceSetImage(t *testing.T) {
+	oldKey := "foods/3/old.png"
+	owned := func(_ context.Context, id uint) (food.Food, error) {
+		return food.Food{ID: id, UserID: 7, Visibility: food.VisibilityPrivate, ImageKey: &oldKey}, nil
+	}
+
+	t.Run("replaces the previous image", func(t *testing.T) {
+		blobs := &fakeBlobStore{objects: map[string]string{oldKey: "image/png", "foods/3/old_thumb.jpg": "image/jpeg"}}
+		var saved *repository.Image
+		svc := service.NewFoodService(fakeFoodStore{getFn: owned, setImageFn: func(_ context.Context, id uint, img *repository.Image) (food.Food, error) {
+			saved = img
+			return food.Food{ID: id, ImageURL: &img.URL, ThumbnailURL: &img.ThumbnailURL}, nil
+		}})
+		svc.SetImageUploads(service.ImageUploads{Store: blobs})
+
+		got, err := svc.SetImage(context.Background(), 7, 3, testPNG(t))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if saved == nil || !strings.HasPrefix(saved.Key, "foods/3/") || !strings.HasSuffix(saved.Key, ".png") {
+			t.Fatalf("unexpected saved image %+v", saved)
+		}
+		thumb := strings.TrimSuffix(saved.Key, ".png") + "_thumb.jpg"
+		if want := []string{saved.Key, thumb}; strings.Join(blobs.keys(), ",") != strings.Join(want, ",") {
+			t.Fatalf("expected only the new renditions to remain, got %v", blobs.keys())
+		}
+		if blobs.objects[thumb] != "image/jpeg" || *got.ThumbnailURL != "https://cdn.test/"+thumb {
+			t.Fatalf("unexpected thumbnail %q %q", blobs.objects[thumb], *got.ThumbnailURL)
+		}
+	})
+
+	t.Run("keeps the previous image when saving fails", func(t *testing.T) {
+		blobs := &fakeBlobStore{objects: map[string]string{oldKey: "image/png"}}
+		svc := service.NewFoodService(fakeFoodStore{getFn: owned, setImageFn: func(_ context.Context, _ uint, _ *repository.Image) (food.Food, error) {
+			return food.Food{}, errors.New("db down")
+		}})
+		svc.SetImageUploads(service.ImageUploads{Store: blobs})
+
+		if _, err := svc.SetImage(context.Background(), 7, 3, testPNG(t)); err == nil {
+			t.Fatal("expected error")
+		}
+		if got := blobs.keys(); len(got) != 1 || got[0] != oldKey {
+			t.Fatalf("expected only the previous image to remain, got %v", got)
+		}
+	})
+
+	t.Run("rejects unsupported content", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{getFn: owned})
+		svc.SetImageUploads(service.ImageUploads{Store: &fakeBlobStore{objects: map[string]string{}}})
+		if _, err := svc.SetImage(context.Background(), 7, 3, []byte("<svg></svg>")); !errors.Is(err, service.ErrUnsupportedImageType) {
+			t.Fatalf("expected ErrUnsupportedImageType, got %v", err)
+		}
+	})
+
+	t.Run("reports storage failures", func(t *testing.T) {
+		blobs := &fakeBlobStore{objects: map[string]string{}, putErr: errors.New("bucket gone")}
+		svc := service.NewFoodService(fakeFoodStore{getFn: owned})
+		svc.SetImageUploads(service.ImageUploads{Store: blobs})
+		if _, err := svc.SetImage(context.Background(), 7, 3, testPNG(t)); !errors.Is(err, service.ErrImageStorageUnavailable) {
+			t.Fatalf("expected ErrImageStorageUnavailable, got %v", err)
+		}
+	})
+
+	t.Run("requires a configured store", func(t *testing.T) {
+		svc := service.NewFoodService(fakeFoodStore{getFn: owned})
+		if _, err := svc.SetImage(context.Background(), 7, 3, testPNG(t)); !errors.Is(err, service.ErrImageStorageUnavailable) {
+			t.Fatalf("expected ErrImageStorageUnavailable, got %v", err)
+		}
+	})
+
+	t.Run("rejects archived foods", func(t *testing.T) {
+		archivedAt := time.Now().UTC()
+		svc := service.NewFoodService(fakeFoodStore{getFn: func(_ context.Context, id uint) (food.Food, error) {
+			return food.Food{ID: id, UserID: 7, Visibility: food.VisibilityPrivate, ArchivedAt: &archivedAt}, nil
+		}})
+		svc.SetImageUploads(service.ImageUploads{Store: &fakeBlobStore{objects: map[string]string{}}})
+		if _, err := svc.SetImage(context.Background(), 7, 3, testPNG(t)); !errors.Is(err, service.ErrFoodArchived) {
+			t.Fatalf("expected ErrFoodArchived, got %v", err)
+		}
+	})
+}
+
+func TestRecipeServiceSetImageRequiresOwner(t *testing.T) {
+	svc := service.NewRecipeService(fakeRecipeStore{getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+		return recipe.Recipe{ID: id, UserID: 9}, nil
+	}}, fakeFoodReader{})
+	svc.SetImageUploads(service.ImageUploads{Store: &fakeBlobStore{objects: map[string]string{}}})
+
+	if _, err := svc.SetImage(context.Background(), 7, 1, testPNG(t)); !errors.Is(err, service.ErrRecipeForbidden) {
+		t.Fatalf("expected ErrRecipeForbidden, got %v", err)
+	}
+}
+
+func TestRecipeServiceSetImageKeyHasNoID(t *testing.T) {
+	var saved *repository.Image
+	svc := service.NewRecipeService(fakeRecipeStore{
+		getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+			return recipe.Recipe{ID: id, UserID: 7}, nil
+		},
+		imageFn: func(_ context.Context, id uint, img *repository.Image) (recipe.Recipe, error) {
+			saved = img
+			return recipe.Recipe{ID: id}, nil
+		},
+	}, fakeFoodReader{})
+	svc.SetImageUploads(service.ImageUploads{Store: &fakeBlobStore{objects: map[string]string{}}})
+
+	if _, err := svc.SetImage(context.Background(), 7, 12, testPNG(t)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// Share links show the URL, so it must not give the recipe away.
+	if saved == nil || !strings.HasPrefix(saved.Key, "recipes/") || strings.Count(saved.Key, "/") != 1 {
+		t.Fatalf("expected a key without the recipe ID, got %+v", saved)
+	}
+}
+
+func TestMealServiceRemoveImage(t *testing.T) {
+	key := "meals/5/a.jpg"
+	blobs := &fakeBlobStore{objects: map[string]string{key: "image/jpeg", "meals/5/a_thumb.jpg": "image/jpeg"}}
+	svc := service.NewMealService(
+		fakeMealStore{
+			getForUserFn: func(_ context.Context, userID, id uint) (meal.Meal, error) {
+				return meal.Meal{ID: id, UserID: userID, ImageKey: &key}, nil
+			},
+			setImageForUserFn: func(_ context.Context, userID, mealID uint, img *repository.Image) (meal.Meal, error) {
+				if img != nil {
+					t.Fatalf("expected image to be cleared, got %+v", img)
+				}
+				return meal.Meal{ID: mealID, UserID: userID}, nil
+			},
+		},
+		fakeFoodStore{},
+		fakeRecipeReader{},
+	)
+	svc.SetImageUploads(service.ImageUploads{Store: blobs})
+
+	if _, err := svc.RemoveImage(context.Background(), 1, 5); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(blobs.objects) != 0 {
+		t.Fatalf("expected renditions to be deleted, got %v", blobs.keys())
+	}
+}
@@ -0,0 +1,285 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/domain/user"
+	"goal-bite-api/internal/mail"
+	"goal-bite-api/internal/repository"
+	"goal-bite-api/internal/service"
+)
+
+type fakeMagicLinkStore struct {
+	createFn  func(ctx context.Context, in repository.CreateMagicLinkTokenInput) (repository.MagicLinkToken, error)
+	consumeFn func(ctx context.Context, tokenHash string, now time.Time) (repository.MagicLinkToken, error)
+}
+
+func (f fakeMagicLinkStore) Create(ctx context.Context, in repository.CreateMagicLinkTokenInput) (repository.MagicLinkToken, error) {
+	if f.createFn == nil {
+		return repository.MagicLinkToken{ID: 1, UserID: in.UserID}, nil
+	}
+	return f.createFn(ctx, in)
+}
+
+func (f fakeMagicLinkStore) Consume(ctx context.Context, tokenHash string, now time.Time) (repository.MagicLinkToken, error) {
+	if f.consumeFn == nil {
+		return repository.MagicLinkToken{}, repository.ErrNotFound
+	}
+	return f.consumeFn(ctx, tokenHash, now)
+}
+
+type fakeMailer struct {
+	sendFn func(ctx context.Context, msg mail.Message) error
+}
+
+func (f fakeMailer) Send(ctx context.Context, msg mail.Message) error {
+	if f.sendFn == nil {
+		return nil
+	}
+	return f.sendFn(ctx, msg)
+}
+
+func TestMagicLinkServiceRequest(t *testing.T) {
+	cfg := service.MagicLinkConfig{BaseURL: "https://app.example.com/magic", TTL: 10 * time.Minute}
+
+	t.Run("unknown email is accepted silently", func(t *testing.T) {
+		created, mailed := false, false
+		svc := service.NewMagicLinkService(
+			fakeUserAuthStore{},
+			fakeMagicLinkStore{createFn: func(_ context.Context, _ repository.CreateMagicLinkTokenInput) (repository.MagicLinkToken, error) {
+				created = true
+				return repository.MagicLinkToken{}, nil
+			}},
+			fakeAuthSessionStore{},
+			fakeTokenIssuer{},
+			fakeMailer{sendFn: func(_ context.Context, _ mail.Message) error {
+				mailed = true
+				return nil
+			}},
+			cfg,
+		)
+		if err := svc.RequestMagicLink(context.Background(), "nobody@example.com"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		svc.Wait()
+		if created || mailed {
+			t.Fatalf("expected no token and no mail, got created=%v mailed=%v", created, mailed)
+		}
+	})
+
+	t.Run("invalid email returns invalid email", func(t *testing.T) {
+		svc := service.NewMagicLinkService(fakeUserAuthStore{}, fakeMagicLinkStore{}, fakeAuthSessionStore{}, fakeTokenIssuer{}, fakeMailer{}, cfg)
+		if err := svc.RequestMagicLink(context.Background(), "not-an-email"); !errors.Is(err, service.ErrInvalidEmail) {
+			t.Fatalf("expected ErrInvalidEmail, got %v", err)
+		}
+	})
+
+	t.Run("known email stores hashed token and mails link", func(t *testing.T) {
+		var stored repository.CreateMagicLinkTokenInput
+		var sent mail.Message
+		svc := service.NewMagicLinkService(
+			fakeUserAuthStore{getByEmailFn: func(_ context.Context, email string) (user.User, error) {
+				return user.User{ID: 3, Name: "A", Email: email}, nil
+			}},
+			fakeMagicLinkStore{createFn: func(_ context.Context, in repository.CreateMagicLinkTokenInput) (repository.MagicLinkToken, error) {
+				stored = in
+				return repository.MagicLinkToken{ID: 1, UserID: in.UserID}, nil
+			}},
+			fakeAuthSessionStore{},
+			fakeTokenIssuer{},
+			fakeMailer{sendFn: func(_ context.Context, msg mail.Message) error {
+				sent = msg
+				return nil
+			}},
+			cfg,
+		)
+		before := time.Now().UTC()
+		if err := svc.RequestMagicLink(context.Background(), "a@example.com"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		svc.Wait()
+		if stored.UserID != 3 || stored.TokenHash == "" {
+			t.Fatalf("unexpected stored token: %+v", stored)
+		}
+		if stored.ExpiresAt.Before(before.Add(9*time.Minute)) || stored.ExpiresAt.After(time.Now().UTC().Add(10*time.Minute)) {
+			t.Fatalf("unexpected expiry: %v", stored.ExpiresAt)
+		}
+		if sent.To != "a@example.com" || !strings.Contains(sent.Body, "https://app.example.com/magic?token=") {
+			t.Fatalf("unexpected mail: %+v", sent)
+		}
+		if strings.Contains(sent.Body, stored.TokenHash) {
+			t.Fatalf("expected mail to carry the raw token, not its hash")
+		}
+	})
+
+	t.Run("known and unknown emails answer alike when the mailer fails", func(t *testing.T) {
+		var logs bytes.Buffer
+		failing := cfg
+		failing.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+		svc := service.NewMagicLinkService(
+			fakeUserAuthStore{getByEmailFn: func(_ context.Context, email string) (user.User, error) {
+				if email != "a@example.com" {
+					return user.User{}, repository.ErrNotFound
+				}
+				return user.User{ID: 3, Email: email}, nil
+			}},
+			fakeMagicLinkStore{},
+			fakeAuthSessionStore{},
+			fakeTokenIssuer{},
+			fakeMailer{sendFn: func(_ context.Context, _ mail.Message) error {
+				return errors.New("smtp down")
+			}},
+			failing,
+		)
+		known := svc.RequestMagicLink(context.Background(), "a@example.com")
+		unknown := svc.RequestMagicLink(context.Background(), "nobody@example.com")
+		if known != nil || unknown != nil {
+			t.Fatalf("expected both requests to succeed, got %v and %v", known, unknown)
+		}
+		svc.Wait()
+		if !strings.Contains(logs.String(), "smtp down") {
+			t.Fatalf("expected the mailer failure to be logged, got %q", logs.String())
+		}
+	})
+
+	t.Run("sending outlives the request context", func(t *testing.T) {
+		var sendErr error
+		svc := service.NewMagicLinkService(
+			fakeUserAuthStore{getByEmailFn: func(_ context.Context, email string) (user.User, error) {
+				return user.User{ID: 3, Email: email}, nil
+			}},
+			fakeMagicLinkStore{},
+			fakeAuthSessionStore{},
+			fakeTokenIssuer{},
+			fakeMailer{sendFn: func(ctx context.Context, _ mail.Message) error {
+				sendErr = ctx.Err()
+				return nil
+			}},
+			cfg,
+		)
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := svc.RequestMagicLink(ctx, "a@example.com"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		cancel()
+		svc.Wait()
+		if sendErr != nil {
+			t.Fatalf("expected the send to ignore the cancelled request, got %v", sendErr)
+		}
+	})
+}
+
+func TestMagicLinkServiceRequestThrottle(t *testing.T) {
+	users := fakeUserAuthStore{getByEmailFn: func(_ context.Context, email string) (user.User, error) {
+		return user.User{ID: 3, Email: email}, nil
+	}}
+
+	t.Run("repeated requests within the cooldown send once", func(t *testing.T) {
+		var mu sync.Mutex
+		var sent []string
+		svc := service.NewMagicLinkService(users, fakeMagicLinkStore{}, fakeAuthSessionStore{}, fakeTokenIssuer{}, fakeMailer{sendFn: func(_ context.Context, msg mail.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, msg.To)
+			return nil
+		}}, service.MagicLinkConfig{Cooldown: time.Hour})
+
+		for _, email := range []string{"a@example.com", "A@example.com ", "a@example.com", "b@example.com"} {
+			if err := svc.RequestMagicLink(context.Background(), email); err != nil {
+				t.Fatalf("%s: expected no error, got %v", email, err)
+			}
+		}
+		svc.Wait()
+		sort.Strings(sent)
+		if strings.Join(sent, ",") != "a@example.com,b@example.com" {
+			t.Fatalf("expected one mail per email, got %v", sent)
+		}
+	})
+
+	t.Run("requests beyond the pending sends are dropped", func(t *testing.T) {
+		var logs bytes.Buffer
+		release := make(chan struct{})
+		started := make(chan string, 3)
+		svc := service.NewMagicLinkService(users, fakeMagicLinkStore{}, fakeAuthSessionStore{}, fakeTokenIssuer{}, fakeMailer{sendFn: func(_ context.Context, msg mail.Message) error {
+			started <- msg.To
+			<-release
+			return nil
+		}}, service.MagicLinkConfig{MaxPendingSends: 1, Logger: slog.New(slog.NewTextHandler(&logs, nil))})
+
+		if err := svc.RequestMagicLink(context.Background(), "a@example.com"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		<-started
+		if err := svc.RequestMagicLink(context.Background(), "b@example.com"); err != nil {
+			t.Fatalf("expected a dropped request to answer alike, got %v", err)
+		}
+		close(release)
+		svc.Wait()
+		if !strings.Contains(logs.String(), "saturated") {
+			t.Fatalf("expected the dropped request to be logged, got %q", logs.String())
+		}
+
+		// The dropped email got nothing, so it may ask again at once.
+		if err := svc.RequestMagicLink(context.Background(), "b@example.com"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		svc.Wait()
+		if len(started) != 1 || <-started != "b@example.com" {
+			t.Fatalf("expected the retried request to send")
+		}
+	})
+}
+
+func TestMagicLinkServiceConsume(t *testing.T) {
+	cfg := service.MagicLinkConfig{BaseURL: "https://app.example.com/magic"}
+
+	t.Run("unknown or used token is rejected", func(t *testing.T) {
+		svc := service.NewMagicLinkService(fakeUserAuthStore{}, fakeMagicLinkStore{}, fakeAuthSessionStore{}, fakeTokenIssuer{}, fakeMailer{}, cfg)
+		if _, err := svc.ConsumeMagicLink(context.Background(), "used"); !errors.Is(err, service.ErrInvalidMagicLinkToken) {
+			t.Fatalf("expected ErrInvalidMagicLinkToken, got %v", err)
+		}
+	})
+
+	t.Run("valid token issues session", func(t *testing.T) {
+		sessionCreated := false
+		svc := service.NewMagicLinkService(
+			fakeUserAuthStore{getByIDFn: func(_ context.Context, id uint) (user.User, error) {
+				return user.User{ID: id, Email: "a@example.com", PasswordHash: "secret"}, nil
+			}},
+			fakeMagicLinkStore{consumeFn: func(_ context.Context, tokenHash string, _ time.Time) (repository.MagicLinkToken, error) {
+				if tokenHash == "" || tokenHash == "raw-token" {
+					t.Fatalf("expected hashed token lookup")
+				}
+				return repository.MagicLinkToken{ID: 1, UserID: 3}, nil
+			}},
+			fakeAuthSessionStore{createFn: func(_ context.Context, in repository.CreateAuthSessionInput) (repository.AuthSession, error) {
+				if in.UserID != 3 {
+					t.Fatalf("expected session for user 3, got %d", in.UserID)
+				}
+				sessionCreated = true
+				return repository.AuthSession{ID: 1, UserID: in.UserID}, nil
+			}},
+			fakeTokenIssuer{},
+			fakeMailer{},
+			cfg,
+		)
+		out, err := svc.ConsumeMagicLink(context.Background(), "raw-token")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if out.AccessToken == "" || out.RefreshToken == "" || !sessionCreated {
+			t.Fatalf("expected issued session, got %+v", out)
+		}
+		if out.User.PasswordHash != "" {
+			t.Fatalf("expected password hash to be stripped")
+		}
+	})
+}
@@ -3,13 +3,16 @@ package service_test
 import (
 	"context"
 	"errors"
+	"math"
 	"testing"
 	"time"
 
 	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/domain/meal"
 	"goal-bite-api/internal/domain/mealitem"
 	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/user"
 	"goal-bite-api/internal/repository"
 	"goal-bite-api/internal/service"
 )
@@ -28,6 +31,7 @@ type fakeMealStore struct {
 	updateItemForUserFn func(ctx context.Context, userID, mealID, itemID uint, in repository.AddMealItemInput) (mealitem.MealItem, error)
 	deleteItemForUserFn func(ctx context.Context, userID, mealID, itemID uint) error
 	dailyFn             func(ctx context.Context, userID uint, date time.Time) (repository.DailyTotals, error)
+	setImageForUserFn   func(ctx context.Context, userID, mealID uint, img *repository.Image) (meal.Meal, error)
 }
 
 func (f fakeMealStore) Create(ctx context.Context, in repository.CreateMealInput) (meal.Meal, error) {
@@ -86,6 +90,13 @@ func (f fakeMealStore) GetDailyTotals(ctx context.Context, userID uint, date tim
 	return f.dailyFn(ctx, userID, date)
 }
 
+func (f fakeMealStore) SetImageForUser(ctx context.Context, userID, mealID uint, img *repository.Image) (meal.Meal, error) {
+	if f.setImageForUserFn == nil {
+		return meal.Meal{ID: mealID, UserID: userID}, nil
+	}
+	return f.setImageForUserFn(ctx, userID, mealID, img)
+}
+
 func (f fakeMealStore) UpdateForUser(ctx context.Context, userID, mealID uint, in repository.UpdateMealInput) (meal.Meal, error) {
 	if f.updateForUserFn == nil {
 		return meal.Meal{}, nil
@@ -122,7 +133,8 @@ func (f fakeMealStore) DeleteItemForUser(ctx context.Context, userID, mealID, it
 }
 
 type fakeRecipeReader struct {
-	getFn func(ctx context.Context, id uint) (recipe.Recipe, error)
+	getFn   func(ctx context.Context, id uint) (recipe.Recipe, error)
+	byIDsFn func(ctx context.Context, ids []uint) ([]recipe.Recipe, error)
 }
 
 func (f fakeRecipeReader) GetByID(ctx context.Context, id uint) (recipe.Recipe, error) {
@@ -132,6 +144,13 @@ func (f fakeRecipeReader) GetByID(ctx context.Context, id uint) (recipe.Recipe,
 	return f.getFn(ctx, id)
 }
 
+func (f fakeRecipeReader) ListByIDs(ctx context.Context, ids []uint) ([]recipe.Recipe, error) {
+	if f.byIDsFn == nil {
+		return nil, nil
+	}
+	return f.byIDsFn(ctx, ids)
+}
+
 func TestMealServiceAddItemFoodSnapshot(t *testing.T) {
 	fid := uint(1)
 	svc := service.NewMealService(
@@ -139,18 +158,60 @@ func TestMealServiceAddItemFoodSnapshot(t *testing.T) {
 			if in.KcalPer100g != 130 {
 				t.Fatalf("expected snapshot kcal 130, got %v", in.KcalPer100g)
 			}
+			if in.FoodVersion == nil || *in.FoodVersion != 3 {
+				t.Fatalf("expected food version 3, got %v", in.FoodVersion)
+			}
 			return mealitem.MealItem{ID: 1, MealID: 1, FoodID: &fid, WeightG: in.WeightG}, nil
 		}},
 		fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
-			return food.Food{KcalPer100g: 130, ProteinPer100g: 2.7, CarbsPer100g: 28, FatPer100g: 0.3}, nil
+			return food.Food{KcalPer100g: 130, ProteinPer100g: 2.7, CarbsPer100g: 28, FatPer100g: 0.3, CurrentVersion: 3}, nil
+		}},
+		fakeRecipeReader{},
+	)
+
+	_, err := svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{FoodID: &fid, WeightG: 200})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestMealServiceAddItemArchivedFood(t *testing.T) {
+	fid := uint(1)
+	archivedAt := time.Now().UTC()
+	svc := service.NewMealService(
+		fakeMealStore{},
+		fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+			return food.Food{ID: fid, UserID: 1, KcalPer100g: 130, ArchivedAt: &archivedAt}, nil
 		}},
 		fakeRecipeReader{},
 	)
 
 	_, err := svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{FoodID: &fid, WeightG: 200})
+	if !errors.Is(err, service.ErrFoodArchived) {
+		t.Fatalf("expected ErrFoodArchived, got %v", err)
+	}
+}
+
+func TestMealServiceAddItemWarnsAboutDietaryConflicts(t *testing.T) {
+	rid := uint(4)
+	svc := service.NewMealService(
+		fakeMealStore{addItemForUserFn: func(_ context.Context, _ uint, _ uint, in repository.AddMealItemInput) (mealitem.MealItem, error) {
+			return mealitem.MealItem{ID: 1, MealID: 1, RecipeID: in.RecipeID, WeightG: in.WeightG}, nil
+		}},
+		fakeFoodStore{},
+		fakeRecipeReader{getFn: func(_ context.Context, _ uint) (recipe.Recipe, error) {
+			return recipe.Recipe{ID: rid, Allergens: []string{"milk", "peanuts"}, DietFlags: []string{"gluten_free"}}, nil
+		}},
+	)
+	svc.SetRestrictionReader(fakeUserReader{result: user.User{ID: 1, AvoidAllergens: []string{"peanuts"}, RequiredDietFlags: []string{"gluten_free", "vegetarian"}}})
+
+	got, err := svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{RecipeID: &rid, WeightG: 150})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+	if len(got.Warnings) != 2 || got.Warnings[0].Code != "allergen" || got.Warnings[0].Value != "peanuts" || got.Warnings[1].Code != "diet" || got.Warnings[1].Value != "vegetarian" {
+		t.Fatalf("unexpected warnings %+v", got.Warnings)
+	}
 }
 
 func TestMealServiceAddItemXORValidation(t *testing.T) {
@@ -163,6 +224,84 @@ func TestMealServiceAddItemXORValidation(t *testing.T) {
 	}
 }
 
+func TestMealServiceAddItemServings(t *testing.T) {
+	fid := uint(1)
+	rid := uint(2)
+	servings := 1.5
+	svc := service.NewMealService(
+		fakeMealStore{addItemForUserFn: func(_ context.Context, _, _ uint, in repository.AddMealItemInput) (mealitem.MealItem, error) {
+			if in.WeightG != 225 {
+				t.Fatalf("expected 1.5 servings of 150g to log 225g, got %v", in.WeightG)
+			}
+			if in.Servings == nil || *in.Servings != servings {
+				t.Fatalf("expected servings kept, got %v", in.Servings)
+			}
+			return mealitem.MealItem{RecipeID: in.RecipeID, WeightG: in.WeightG, Servings: in.Servings}, nil
+		}},
+		fakeFoodStore{},
+		fakeRecipeReader{getFn: func(_ context.Context, _ uint) (recipe.Recipe, error) {
+			return recipe.Recipe{ID: rid, YieldWeightG: 600, Servings: 4, KcalPer100g: 120}, nil
+		}},
+	)
+
+	if _, err := svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{RecipeID: &rid, Servings: &servings}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, err := svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{FoodID: &fid, Servings: &servings})
+	if !errors.Is(err, service.ErrInvalidItemServings) {
+		t.Fatalf("expected ErrInvalidItemServings for a food, got %v", err)
+	}
+	_, err = svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{RecipeID: &rid, WeightG: 100, Servings: &servings})
+	if !errors.Is(err, service.ErrInvalidItemServings) {
+		t.Fatalf("expected ErrInvalidItemServings for weight and servings, got %v", err)
+	}
+}
+
+func TestMealServiceAddItemCookedWeight(t *testing.T) {
+	fid := uint(1)
+	rid := uint(2)
+	rice := uint(4)
+	boiled := uint(3)
+	cooked := 280.0
+	var got repository.AddMealItemInput
+	svc := service.NewMealService(
+		fakeMealStore{addItemForUserFn: func(_ context.Context, _, _ uint, in repository.AddMealItemInput) (mealitem.MealItem, error) {
+			got = in
+			return mealitem.MealItem{}, nil
+		}},
+		fakeFoodStore{getFn: func(_ context.Context, id uint) (food.Food, error) {
+			return food.Food{ID: id, CategoryID: &rice, KcalPer100g: 360, ProteinPer100g: 7, CarbsPer100g: 80, FatPer100g: 1}, nil
+		}},
+		fakeRecipeReader{},
+	)
+	svc.SetCookingFactors(fakeMoistureFactors{fakeCookingFactors{boiled: {0: 1, rice: 2.8}}, 16})
+
+	if _, err := svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{FoodID: &fid, CookedWeightG: &cooked, CookingMethodID: &boiled}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if math.Abs(got.WeightG-100) > 1e-9 || got.KcalPer100g != 360 || got.CookedWeightG == nil || *got.CookedWeightG != cooked || *got.CookingMethodID != boiled {
+		t.Fatalf("expected 280g of boiled rice to log as 100g raw, got %+v", got)
+	}
+	// 100g of raw rice hold 12g of water, sixteen times that after boiling.
+	if got.WaterChangeG == nil || *got.WaterChangeG != 180 {
+		t.Fatalf("expected boiled rice to take up 180g of water, got %v", got.WaterChangeG)
+	}
+
+	unknown := uint(9)
+	_, err := svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{FoodID: &fid, CookedWeightG: &cooked, CookingMethodID: &unknown})
+	if !errors.Is(err, service.ErrCookingMethodNotFound) {
+		t.Fatalf("expected ErrCookingMethodNotFound, got %v", err)
+	}
+	_, err = svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{RecipeID: &rid, CookedWeightG: &cooked, CookingMethodID: &boiled})
+	if !errors.Is(err, service.ErrInvalidCookedWeight) {
+		t.Fatalf("expected ErrInvalidCookedWeight for a recipe, got %v", err)
+	}
+	_, err = svc.AddItem(context.Background(), 1, 1, service.AddMealItemInput{FoodID: &fid, CookedWeightG: &cooked})
+	if !errors.Is(err, service.ErrInvalidCookedWeight) {
+		t.Fatalf("expected ErrInvalidCookedWeight without a method, got %v", err)
+	}
+}
+
 func TestMealServiceGetDailyTotals(t *testing.T) {
 	svc := service.NewMealService(
 		fakeMealStore{dailyFn: func(_ context.Context, userID uint, _ time.Time) (repository.DailyTotals, error) {
@@ -234,8 +373,10 @@ func TestMealServiceUpdateItemRecomputesSnapshot(t *testing.T) {
 				return mealitem.MealItem{ID: itemID, MealID: 1, FoodID: in.FoodID, WeightG: in.WeightG, KcalPer100g: in.KcalPer100g}, nil
 			},
 		},
+		// Archived foods keep resolving for items that already use them.
 		fakeFoodStore{getFn: func(_ context.Context, _ uint) (food.Food, error) {
-			return food.Food{KcalPer100g: 130, ProteinPer100g: 2.7, CarbsPer100g: 28, FatPer100g: 0.3}, nil
+			archivedAt := time.Now().UTC()
+			return food.Food{KcalPer100g: 130, ProteinPer100g: 2.7, CarbsPer100g: 28, FatPer100g: 0.3, ArchivedAt: &archivedAt}, nil
 		}},
 		fakeRecipeReader{},
 	)
@@ -245,3 +386,160 @@ func TestMealServiceUpdateItemRecomputesSnapshot(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
+
+func TestMealServiceUpdateItemKeepsServings(t *testing.T) {
+	rid := uint(2)
+	otherRecipe := uint(3)
+	servings := 2.0
+	var got repository.AddMealItemInput
+	svc := service.NewMealService(
+		fakeMealStore{
+			getItemForUserFn: func(_ context.Context, _, _, _ uint) (mealitem.MealItem, error) {
+				return mealitem.MealItem{ID: 5, MealID: 1, RecipeID: &rid, WeightG: 300, Servings: &servings}, nil
+			},
+			updateItemForUserFn: func(_ context.Context, _, _, _ uint, in repository.AddMealItemInput) (mealitem.MealItem, error) {
+				got = in
+				return mealitem.MealItem{}, nil
+			},
+		},
+		fakeFoodStore{},
+		fakeRecipeReader{getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+			if id == otherRecipe {
+				return recipe.Recipe{ID: id, YieldWeightG: 1000, Servings: 4}, nil
+			}
+			return recipe.Recipe{ID: id, YieldWeightG: 600, Servings: 4}, nil
+		}},
+	)
+
+	if _, err := svc.UpdateItem(context.Background(), 1, 1, 5, service.UpdateMealItemInput{RecipeID: &otherRecipe}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.WeightG != 500 || got.Servings == nil || *got.Servings != 2 {
+		t.Fatalf("expected 2 servings of the new recipe to be 500g, got %v g and %v servings", got.WeightG, got.Servings)
+	}
+
+	weight := 120.0
+	if _, err := svc.UpdateItem(context.Background(), 1, 1, 5, service.UpdateMealItemInput{WeightG: &weight}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.WeightG != 120 || got.Servings != nil {
+		t.Fatalf("expected a weight to replace servings, got %v g and %v servings", got.WeightG, got.Servings)
+	}
+}
+
+func TestMealServiceUpdateItemKeepsCookedWeight(t *testing.T) {
+	fid := uint(1)
+	otherFood := uint(6)
+	meat := uint(4)
+	grilled := uint(3)
+	cooked := 140.0
+	var got repository.AddMealItemInput
+	svc := service.NewMealService(
+		fakeMealStore{
+			getItemForUserFn: func(_ context.Context, _, _, _ uint) (mealitem.MealItem, error) {
+				return mealitem.MealItem{ID: 5, MealID: 1, FoodID: &fid, WeightG: 200, CookedWeightG: &cooked, CookingMethodID: &grilled}, nil
+			},
+			updateItemForUserFn: func(_ context.Context, _, _, _ uint, in repository.AddMealItemInput) (mealitem.MealItem, error) {
+				got = in
+				return mealitem.MealItem{}, nil
+			},
+		},
+		fakeFoodStore{getFn: func(_ context.Context, id uint) (food.Food, error) {
+			if id == otherFood {
+				return food.Food{ID: id}, nil
+			}
+			return food.Food{ID: id, CategoryID: &meat}, nil
+		}},
+		fakeRecipeReader{},
+	)
+	svc.SetCookingFactors(fakeCookingFactors{grilled: {0: 0.8, meat: 0.7}})
+
+	if _, err := svc.UpdateItem(context.Background(), 1, 1, 5, service.UpdateMealItemInput{FoodID: &otherFood}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if math.Abs(got.WeightG-175) > 1e-9 || got.CookedWeightG == nil || *got.CookedWeightG != cooked {
+		t.Fatalf("expected 140g cooked to convert with the new food's factor, got %+v", got)
+	}
+
+	more := 210.0
+	if _, err := svc.UpdateItem(context.Background(), 1, 1, 5, service.UpdateMealItemInput{CookedWeightG: &more}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if math.Abs(got.WeightG-300) > 1e-9 || *got.CookingMethodID != grilled {
+		t.Fatalf("expected 210g of grilled meat to be 300g raw, got %+v", got)
+	}
+
+	weight := 120.0
+	if _, err := svc.UpdateItem(context.Background(), 1, 1, 5, service.UpdateMealItemInput{WeightG: &weight}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.WeightG != 120 || got.CookedWeightG != nil || got.CookingMethodID != nil || got.WaterChangeG != nil {
+		t.Fatalf("expected a weight to replace the cooked weight, got %+v", got)
+	}
+	if _, err := svc.UpdateItem(context.Background(), 1, 1, 5, service.UpdateMealItemInput{WeightG: &weight, CookedWeightG: &more}); !errors.Is(err, service.ErrInvalidCookedWeight) {
+		t.Fatalf("expected ErrInvalidCookedWeight, got %v", err)
+	}
+}
+
+func TestMealServiceBreakdown(t *testing.T) {
+	rice, oil, bowl := uint(1), uint(2), uint(3)
+	var foodLookups, recipeLookups [][]uint
+	svc := service.NewMealService(
+		fakeMealStore{getForUserFn: func(_ context.Context, userID, id uint) (meal.Meal, error) {
+			return meal.Meal{ID: id, UserID: userID, Items: []mealitem.MealItem{
+				{ID: 10, FoodID: &rice, WeightG: 200, KcalPer100g: 130, ProteinPer100g: 2.5, CarbsPer100g: 28},
+				{ID: 11, FoodID: &oil, WeightG: 10, KcalPer100g: 884, FatPer100g: 100},
+				{ID: 12, RecipeID: &bowl, WeightG: 100, KcalPer100g: 100, ProteinPer100g: 10, CarbsPer100g: 10, FatPer100g: 2},
+			}}, nil
+		}},
+		fakeFoodStore{byIDsFn: func(_ context.Context, ids []uint) ([]food.Food, error) {
+			foodLookups = append(foodLookups, ids)
+			return []food.Food{
+				{ID: rice, Name: "Rice", Visibility: food.VisibilityPublic, Translations: []locale.Translation{{Locale: "de", Name: "Reis"}}},
+				{ID: oil, Name: "Secret oil", UserID: 9, Visibility: food.VisibilityPrivate},
+			}, nil
+		}},
+		fakeRecipeReader{byIDsFn: func(_ context.Context, ids []uint) ([]recipe.Recipe, error) {
+			recipeLookups = append(recipeLookups, ids)
+			return []recipe.Recipe{{ID: bowl, Name: "Bowl"}}, nil
+		}},
+	)
+
+	_, breakdown, err := svc.Breakdown(context.Background(), 1, 5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(foodLookups) != 1 || len(foodLookups[0]) != 2 || len(recipeLookups) != 1 {
+		t.Fatalf("expected one bulk lookup each, got foods %v and recipes %v", foodLookups, recipeLookups)
+	}
+	breakdown.Localize([]string{"de"})
+	items := breakdown.Items
+	if len(items) != 3 || items[0].DisplayName != "Reis" || items[1].Name != "" || items[2].Name != "Bowl" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if breakdown.Total.Kcal < 448.3 || breakdown.Total.Kcal > 448.5 || breakdown.Total.FatG != 12 {
+		t.Fatalf("unexpected total: %+v", breakdown.Total)
+	}
+	if items[0].Contribution.Kcal != 260 || items[1].Share.FatPct < 83.3 || items[1].Share.FatPct > 83.4 {
+		t.Fatalf("unexpected contributions: %+v", items)
+	}
+	// 15 g protein, 66 g carbs and 12 g fat give 60, 264 and 108 kcal.
+	split := breakdown.CalorieSplit
+	if split.ProteinPct < 13.8 || split.ProteinPct > 13.9 || split.CarbsPct < 61.1 || split.CarbsPct > 61.2 || split.FatPct != 25 {
+		t.Fatalf("unexpected calorie split: %+v", split)
+	}
+}
+
+func TestMealServiceBreakdownOfMissingMeal(t *testing.T) {
+	svc := service.NewMealService(
+		fakeMealStore{getForUserFn: func(_ context.Context, _, _ uint) (meal.Meal, error) {
+			return meal.Meal{}, repository.ErrNotFound
+		}},
+		fakeFoodStore{},
+		fakeRecipeReader{},
+	)
+
+	if _, _, err := svc.Breakdown(context.Background(), 1, 5); !errors.Is(err, service.ErrMealNotFound) {
+		t.Fatalf("expected ErrMealNotFound, got %v", err)
+	}
+}
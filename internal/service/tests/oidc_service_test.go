@@ -0,0 +1,238 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/domain/user"
+	"goal-bite-api/internal/oidc"
+	"goal-bite-api/internal/oidc/oidctest"
+	"goal-bite-api/internal/repository"
+	"goal-bite-api/internal/service"
+)
+
+// memoryOIDCStore keeps identities and login states so tests can run the
+// full redirect flow against the fake provider.
+type memoryOIDCStore struct {
+	identities []repository.OIDCIdentity
+	states     map[string]repository.OIDCLoginState
+}
+
+func newMemoryOIDCStore() *memoryOIDCStore {
+	return &memoryOIDCStore{states: make(map[string]repository.OIDCLoginState)}
+}
+
+type memoryOIDCIdentities struct{ *memoryOIDCStore }
+
+func (m memoryOIDCIdentities) Create(_ context.Context, in repository.CreateOIDCIdentityInput) (repository.OIDCIdentity, error) {
+	for _, value := range m.identities {
+		if value.Provider == in.Provider && value.Subject == in.Subject {
+			return repository.OIDCIdentity{}, repository.ErrConflict
+		}
+	}
+	value := repository.OIDCIdentity{ID: uint(len(m.identities) + 1), UserID: in.UserID, Provider: in.Provider, Subject: in.Subject, Email: in.Email}
+	m.identities = append(m.identities, value)
+	return value, nil
+}
+
+func (m memoryOIDCIdentities) GetByProviderSubject(_ context.Context, provider, subject string) (repository.OIDCIdentity, error) {
+	for _, value := range m.identities {
+		if value.Provider == provider && value.Subject == subject {
+			return value, nil
+		}
+	}
+	return repository.OIDCIdentity{}, repository.ErrNotFound
+}
+
+type memoryOIDCStates struct{ *memoryOIDCStore }
+
+func (m memoryOIDCStates) Create(_ context.Context, in repository.CreateOIDCLoginStateInput) (repository.OIDCLoginState, error) {
+	value := repository.OIDCLoginState{StateHash: in.StateHash, Provider: in.Provider, CodeVerifier: in.CodeVerifier, Nonce: in.Nonce, ExpiresAt: in.ExpiresAt}
+	m.states[in.StateHash] = value
+	return value, nil
+}
+
+func (m memoryOIDCStates) Consume(_ context.Context, stateHash, provider string, now time.Time) (repository.OIDCLoginState, error) {
+	value, ok := m.states[stateHash]
+	if !ok || value.Provider != provider || !value.ExpiresAt.After(now) {
+		return repository.OIDCLoginState{}, repository.ErrNotFound
+	}
+	delete(m.states, stateHash)
+	return value, nil
+}
+
+func newOIDCTestService(t *testing.T, users fakeUserAuthStore) (*service.OIDCService, *oidctest.Provider, *memoryOIDCStore) {
+	t.Helper()
+	idp, err := oidctest.New("goal-bite", "secret")
+	if err != nil {
+		t.Fatalf("new fake provider: %v", err)
+	}
+	t.Cleanup(idp.Close)
+	provider := oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "acme",
+		Issuer:       idp.Issuer(),
+		ClientID:     "goal-bite",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/auth/oidc/acme/callback",
+	}, nil)
+	store := newMemoryOIDCStore()
+	svc := service.NewOIDCService(users, memoryOIDCIdentities{store}, memoryOIDCStates{store}, fakeAuthSessionStore{}, fakeTokenIssuer{}, provider)
+	return svc, idp, store
+}
+
+func runOIDCLogin(t *testing.T, svc *service.OIDCService, idp *oidctest.Provider) (service.AuthResult, error) {
+	t.Helper()
+	start, err := svc.BeginLogin(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("begin login: %v", err)
+	}
+	code, state, err := idp.Authorize(start.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	return svc.FinishLogin(context.Background(), "acme", code, state)
+}
+
+func TestOIDCServiceLinksExistingUserByVerifiedEmail(t *testing.T) {
+	svc, idp, store := newOIDCTestService(t, fakeUserAuthStore{
+		getByEmailFn: func(_ context.Context, email string) (user.User, error) {
+			if email != "a@example.com" {
+				return user.User{}, repository.ErrNotFound
+			}
+			return user.User{ID: 7, Name: "A", Email: email, PasswordHash: "hash"}, nil
+		},
+		getByIDFn: func(_ context.Context, id uint) (user.User, error) {
+			return user.User{ID: id, Name: "A", Email: "a@example.com"}, nil
+		},
+		createFn: func(_ context.Context, _ user.User) (user.User, error) {
+			t.Fatalf("expected existing user to be linked, not created")
+			return user.User{}, nil
+		},
+	})
+	idp.SetUser(oidctest.User{Subject: "sub-1", Email: "A@Example.com", EmailVerified: true})
+
+	out, err := runOIDCLogin(t, svc, idp)
+	if err != nil {
+		t.Fatalf("finish login: %v", err)
+	}
+	if out.User.ID != 7 || out.AccessToken == "" || out.RefreshToken == "" || out.User.PasswordHash != "" {
+		t.Fatalf("unexpected auth result: %+v", out)
+	}
+	if len(store.identities) != 1 || store.identities[0].UserID != 7 || store.identities[0].Subject != "sub-1" {
+		t.Fatalf("expected identity link, got %+v", store.identities)
+	}
+
+	// Second login resolves through the stored identity even if the email changed.
+	idp.SetUser(oidctest.User{Subject: "sub-1", Email: "renamed@example.com"})
+	out, err = runOIDCLogin(t, svc, idp)
+	if err != nil || out.User.ID != 7 {
+		t.Fatalf("expected linked identity login, got %+v %v", out, err)
+	}
+	if len(store.identities) != 1 {
+		t.Fatalf("expected no new identity, got %d", len(store.identities))
+	}
+}
+
+func TestOIDCServiceCreatesNewUser(t *testing.T) {
+	var created user.User
+	svc, idp, _ := newOIDCTestService(t, fakeUserAuthStore{
+		createFn: func(_ context.Context, value user.User) (user.User, error) {
+			value.ID = 11
+			created = value
+			return value, nil
+		},
+	})
+	idp.SetUser(oidctest.User{Subject: "sub-2", Email: "new@example.com", EmailVerified: true, Name: "New Person"})
+
+	out, err := runOIDCLogin(t, svc, idp)
+	if err != nil {
+		t.Fatalf("finish login: %v", err)
+	}
+	if out.User.ID != 11 || created.Name != "New Person" || created.Email != "new@example.com" || created.PasswordHash != "" {
+		t.Fatalf("unexpected created user: %+v", created)
+	}
+}
+
+func TestOIDCServiceConcurrentFirstLogin(t *testing.T) {
+	// Another first login for the same subject creates the account and links
+	// it between this login's lookups and its inserts.
+	var store *memoryOIDCStore
+	raced := false
+	svc, idp, store := newOIDCTestService(t, fakeUserAuthStore{
+		getByEmailFn: func(_ context.Context, email string) (user.User, error) {
+			if !raced {
+				return user.User{}, repository.ErrNotFound
+			}
+			return user.User{ID: 11, Email: email}, nil
+		},
+		getByIDFn: func(_ context.Context, id uint) (user.User, error) {
+			return user.User{ID: id, Email: "new@example.com"}, nil
+		},
+		createFn: func(_ context.Context, _ user.User) (user.User, error) {
+			raced = true
+			store.identities = append(store.identities, repository.OIDCIdentity{ID: 1, UserID: 11, Provider: "acme", Subject: "sub-3"})
+			return user.User{}, repository.ErrConflict
+		},
+	})
+	idp.SetUser(oidctest.User{Subject: "sub-3", Email: "new@example.com", EmailVerified: true})
+
+	out, err := runOIDCLogin(t, svc, idp)
+	if err != nil {
+		t.Fatalf("expected the losing login to succeed, got %v", err)
+	}
+	if out.User.ID != 11 || out.AccessToken == "" {
+		t.Fatalf("expected to sign in as the account the other login created, got %+v", out)
+	}
+	if len(store.identities) != 1 {
+		t.Fatalf("expected one identity, got %+v", store.identities)
+	}
+}
+
+func TestOIDCServiceRejections(t *testing.T) {
+	t.Run("unverified email", func(t *testing.T) {
+		svc, idp, _ := newOIDCTestService(t, fakeUserAuthStore{})
+		idp.SetUser(oidctest.User{Subject: "sub-3", Email: "a@example.com", EmailVerified: false})
+		if _, err := runOIDCLogin(t, svc, idp); !errors.Is(err, service.ErrOIDCEmailNotVerified) {
+			t.Fatalf("expected ErrOIDCEmailNotVerified, got %v", err)
+		}
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		svc, _, _ := newOIDCTestService(t, fakeUserAuthStore{})
+		if _, err := svc.BeginLogin(context.Background(), "nope"); !errors.Is(err, service.ErrOIDCProviderNotFound) {
+			t.Fatalf("expected ErrOIDCProviderNotFound, got %v", err)
+		}
+	})
+
+	t.Run("state is single use", func(t *testing.T) {
+		svc, idp, _ := newOIDCTestService(t, fakeUserAuthStore{})
+		idp.SetUser(oidctest.User{Subject: "sub-4", Email: "b@example.com", EmailVerified: true})
+		start, _ := svc.BeginLogin(context.Background(), "acme")
+		code, state, err := idp.Authorize(start.AuthorizationURL)
+		if err != nil {
+			t.Fatalf("authorize: %v", err)
+		}
+		if _, err := svc.FinishLogin(context.Background(), "acme", code, state); err != nil {
+			t.Fatalf("finish login: %v", err)
+		}
+		if _, err := svc.FinishLogin(context.Background(), "acme", code, state); !errors.Is(err, service.ErrInvalidOIDCState) {
+			t.Fatalf("expected ErrInvalidOIDCState, got %v", err)
+		}
+	})
+
+	t.Run("forged code", func(t *testing.T) {
+		svc, _, _ := newOIDCTestService(t, fakeUserAuthStore{})
+		start, err := svc.BeginLogin(context.Background(), "acme")
+		if err != nil {
+			t.Fatalf("begin login: %v", err)
+		}
+		authURL, _ := url.Parse(start.AuthorizationURL)
+		state := authURL.Query().Get("state")
+		if _, err := svc.FinishLogin(context.Background(), "acme", "forged", state); !errors.Is(err, service.ErrOIDCLoginFailed) {
+			t.Fatalf("expected ErrOIDCLoginFailed, got %v", err)
+		}
+	})
+}
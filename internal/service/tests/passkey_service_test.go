@@ -0,0 +1,271 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/domain/passkey"
+	"goal-bite-api/internal/domain/user"
+	"goal-bite-api/internal/repository"
+	"goal-bite-api/internal/service"
+	"goal-bite-api/internal/webauthn"
+	"goal-bite-api/internal/webauthn/webauthntest"
+)
+
+type fakePasskeyStore struct {
+	createFn            func(ctx context.Context, value passkey.Passkey) (passkey.Passkey, error)
+	getByIDFn           func(ctx context.Context, id uint) (passkey.Passkey, error)
+	getByCredentialIDFn func(ctx context.Context, credentialID string) (passkey.Passkey, error)
+	listByUserIDFn      func(ctx context.Context, userID uint) ([]passkey.Passkey, error)
+	recordUseFn         func(ctx context.Context, id uint, previousCount, signCount uint32, at time.Time) error
+	deleteFn            func(ctx context.Context, id uint) error
+}
+
+func (f fakePasskeyStore) Create(ctx context.Context, value passkey.Passkey) (passkey.Passkey, error) {
+	if f.createFn == nil {
+		value.ID = 1
+		return value, nil
+	}
+	return f.createFn(ctx, value)
+}
+
+func (f fakePasskeyStore) GetByID(ctx context.Context, id uint) (passkey.Passkey, error) {
+	if f.getByIDFn == nil {
+		return passkey.Passkey{}, repository.ErrNotFound
+	}
+	return f.getByIDFn(ctx, id)
+}
+
+func (f fakePasskeyStore) GetByCredentialID(ctx context.Context, credentialID string) (passkey.Passkey, error) {
+	if f.getByCredentialIDFn == nil {
+		return passkey.Passkey{}, repository.ErrNotFound
+	}
+	return f.getByCredentialIDFn(ctx, credentialID)
+}
+
+func (f fakePasskeyStore) ListByUserID(ctx context.Context, userID uint) ([]passkey.Passkey, error) {
+	if f.listByUserIDFn == nil {
+		return []passkey.Passkey{}, nil
+	}
+	return f.listByUserIDFn(ctx, userID)
+}
+
+func (f fakePasskeyStore) RecordUse(ctx context.Context, id uint, previousCount, signCount uint32, at time.Time) error {
+	if f.recordUseFn == nil {
+		return nil
+	}
+	return f.recordUseFn(ctx, id, previousCount, signCount, at)
+}
+
+func (f fakePasskeyStore) Delete(ctx context.Context, id uint) error {
+	if f.deleteFn == nil {
+		return nil
+	}
+	return f.deleteFn(ctx, id)
+}
+
+// memoryChallengeStore keeps issued challenges so tests can run full ceremonies.
+type memoryChallengeStore struct {
+	items map[string]repository.WebAuthnChallenge
+}
+
+func newMemoryChallengeStore() *memoryChallengeStore {
+	return &memoryChallengeStore{items: make(map[string]repository.WebAuthnChallenge)}
+}
+
+func (m *memoryChallengeStore) Create(_ context.Context, in repository.CreateWebAuthnChallengeInput) (repository.WebAuthnChallenge, error) {
+	value := repository.WebAuthnChallenge{
+		ID:        uint(len(m.items) + 1),
+		Challenge: in.Challenge,
+		Purpose:   in.Purpose,
+		UserID:    in.UserID,
+		ExpiresAt: in.ExpiresAt,
+	}
+	m.items[in.Challenge] = value
+	return value, nil
+}
+
+func (m *memoryChallengeStore) Consume(_ context.Context, challenge, purpose string, now time.Time) (repository.WebAuthnChallenge, error) {
+	value, ok := m.items[challenge]
+	if !ok || value.Purpose != purpose || !value.ExpiresAt.After(now) {
+		return repository.WebAuthnChallenge{}, repository.ErrNotFound
+	}
+	delete(m.items, challenge)
+	return value, nil
+}
+
+var passkeyTestConfig = webauthn.Config{
+	RPID:    "localhost",
+	RPName:  "Goal Bite",
+	Origins: []string{"http://localhost:3000"},
+}
+
+func passkeyTestUsers() fakeUserAuthStore {
+	return fakeUserAuthStore{
+		getByIDFn: func(_ context.Context, id uint) (user.User, error) {
+			return user.User{ID: id, Name: "A", Email: "a@example.com", PasswordHash: "secret"}, nil
+		},
+	}
+}
+
+func TestPasskeyServiceRegistrationAndLogin(t *testing.T) {
+	ctx := context.Background()
+	authenticator, err := webauthntest.New("localhost", "http://localhost:3000")
+	if err != nil {
+		t.Fatalf("new authenticator: %v", err)
+	}
+
+	var stored passkey.Passkey
+	var recordedCount uint32
+	store := fakePasskeyStore{
+		createFn: func(_ context.Context, value passkey.Passkey) (passkey.Passkey, error) {
+			value.ID = 9
+			stored = value
+			return value, nil
+		},
+		getByCredentialIDFn: func(_ context.Context, credentialID string) (passkey.Passkey, error) {
+			if stored.ID == 0 || stored.CredentialID != credentialID {
+				return passkey.Passkey{}, repository.ErrNotFound
+			}
+			return stored, nil
+		},
+		recordUseFn: func(_ context.Context, id uint, previousCount, signCount uint32, _ time.Time) error {
+			if id != stored.ID || previousCount != stored.SignCount {
+				t.Fatalf("unexpected record use args: %d %d", id, previousCount)
+			}
+			recordedCount = signCount
+			return nil
+		},
+	}
+	sessionCreated := false
+	svc := service.NewPasskeyService(
+		passkeyTestUsers(),
+		store,
+		newMemoryChallengeStore(),
+		fakeAuthSessionStore{createFn: func(_ context.Context, in repository.CreateAuthSessionInput) (repository.AuthSession, error) {
+			sessionCreated = in.UserID == 7
+			return repository.AuthSession{ID: 1, UserID: in.UserID}, nil
+		}},
+		fakeTokenIssuer{},
+		passkeyTestConfig,
+	)
+
+	options, err := svc.BeginRegistration(ctx, 7)
+	if err != nil {
+		t.Fatalf("begin registration: %v", err)
+	}
+	if options.User.Name != "a@example.com" || options.RP.ID != "localhost" {
+		t.Fatalf("unexpected creation options: %+v", options)
+	}
+	registration, err := authenticator.Create(options)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	created, err := svc.FinishRegistration(ctx, 7, service.FinishPasskeyRegistrationInput{Name: "Laptop", Credential: registration})
+	if err != nil {
+		t.Fatalf("finish registration: %v", err)
+	}
+	if created.UserID != 7 || created.Name != "Laptop" || len(stored.PublicKey) == 0 {
+		t.Fatalf("unexpected stored passkey: %+v", stored)
+	}
+	if created.PublicKey != nil {
+		t.Fatalf("expected public key to be omitted from result")
+	}
+
+	loginOptions, err := svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("begin login: %v", err)
+	}
+	assertion, err := authenticator.Get(loginOptions)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	out, err := svc.FinishLogin(ctx, assertion)
+	if err != nil {
+		t.Fatalf("finish login: %v", err)
+	}
+	if out.AccessToken == "" || out.RefreshToken == "" || out.User.ID != 7 || !sessionCreated {
+		t.Fatalf("unexpected auth result: %+v", out)
+	}
+	if out.User.PasswordHash != "" {
+		t.Fatalf("expected password hash to be stripped")
+	}
+	if recordedCount != 1 {
+		t.Fatalf("expected sign count 1 to be recorded, got %d", recordedCount)
+	}
+
+	if _, err := svc.FinishLogin(ctx, assertion); !errors.Is(err, service.ErrPasskeyLoginFailed) {
+		t.Fatalf("expected replay to fail with ErrPasskeyLoginFailed, got %v", err)
+	}
+}
+
+func TestPasskeyServiceRegistrationRejections(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("challenge issued to another user", func(t *testing.T) {
+		authenticator, _ := webauthntest.New("localhost", "http://localhost:3000")
+		svc := service.NewPasskeyService(passkeyTestUsers(), fakePasskeyStore{}, newMemoryChallengeStore(), fakeAuthSessionStore{}, fakeTokenIssuer{}, passkeyTestConfig)
+		options, _ := svc.BeginRegistration(ctx, 7)
+		registration, _ := authenticator.Create(options)
+		_, err := svc.FinishRegistration(ctx, 8, service.FinishPasskeyRegistrationInput{Name: "Laptop", Credential: registration})
+		if !errors.Is(err, service.ErrInvalidPasskeyResponse) {
+			t.Fatalf("expected ErrInvalidPasskeyResponse, got %v", err)
+		}
+	})
+
+	t.Run("already registered credential", func(t *testing.T) {
+		authenticator, _ := webauthntest.New("localhost", "http://localhost:3000")
+		svc := service.NewPasskeyService(passkeyTestUsers(), fakePasskeyStore{
+			getByCredentialIDFn: func(_ context.Context, _ string) (passkey.Passkey, error) {
+				return passkey.Passkey{ID: 1, UserID: 7}, nil
+			},
+		}, newMemoryChallengeStore(), fakeAuthSessionStore{}, fakeTokenIssuer{}, passkeyTestConfig)
+		options, _ := svc.BeginRegistration(ctx, 7)
+		registration, _ := authenticator.Create(options)
+		_, err := svc.FinishRegistration(ctx, 7, service.FinishPasskeyRegistrationInput{Name: "Laptop", Credential: registration})
+		if !errors.Is(err, service.ErrPasskeyAlreadyRegistered) {
+			t.Fatalf("expected ErrPasskeyAlreadyRegistered, got %v", err)
+		}
+	})
+
+	t.Run("blank name", func(t *testing.T) {
+		svc := service.NewPasskeyService(passkeyTestUsers(), fakePasskeyStore{}, newMemoryChallengeStore(), fakeAuthSessionStore{}, fakeTokenIssuer{}, passkeyTestConfig)
+		_, err := svc.FinishRegistration(ctx, 7, service.FinishPasskeyRegistrationInput{Name: "  "})
+		if !errors.Is(err, service.ErrInvalidPasskeyName) {
+			t.Fatalf("expected ErrInvalidPasskeyName, got %v", err)
+		}
+	})
+}
+
+func TestPasskeyServiceLoginUnknownCredential(t *testing.T) {
+	ctx := context.Background()
+	authenticator, _ := webauthntest.New("localhost", "http://localhost:3000")
+	svc := service.NewPasskeyService(passkeyTestUsers(), fakePasskeyStore{}, newMemoryChallengeStore(), fakeAuthSessionStore{}, fakeTokenIssuer{}, passkeyTestConfig)
+
+	options, err := svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("begin login: %v", err)
+	}
+	assertion, _ := authenticator.Get(options)
+	if _, err := svc.FinishLogin(ctx, assertion); !errors.Is(err, service.ErrPasskeyLoginFailed) {
+		t.Fatalf("expected ErrPasskeyLoginFailed, got %v", err)
+	}
+}
+
+func TestPasskeyServiceDelete(t *testing.T) {
+	store := fakePasskeyStore{
+		getByIDFn: func(_ context.Context, id uint) (passkey.Passkey, error) {
+			return passkey.Passkey{ID: id, UserID: 7}, nil
+		},
+	}
+	svc := service.NewPasskeyService(passkeyTestUsers(), store, newMemoryChallengeStore(), fakeAuthSessionStore{}, fakeTokenIssuer{}, passkeyTestConfig)
+
+	if err := svc.Delete(context.Background(), 8, 3); !errors.Is(err, service.ErrPasskeyForbidden) {
+		t.Fatalf("expected ErrPasskeyForbidden, got %v", err)
+	}
+	if err := svc.Delete(context.Background(), 7, 3); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -3,22 +3,36 @@ package service_test
 import (
 	"context"
 	"errors"
+	"math"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"goal-bite-api/internal/domain/cooking"
 	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/domain/recipe"
 	"goal-bite-api/internal/domain/recipeingredient"
+	"goal-bite-api/internal/domain/user"
 	"goal-bite-api/internal/repository"
 	"goal-bite-api/internal/service"
 )
 
 type fakeRecipeStore struct {
-	createFn func(ctx context.Context, in repository.RecipeCreate) (recipe.Recipe, error)
-	getFn    func(ctx context.Context, id uint) (recipe.Recipe, error)
-	listFn   func(ctx context.Context, limit, offset int) ([]recipe.Recipe, error)
-	searchFn func(ctx context.Context, query string, limit, offset int) ([]recipe.Recipe, error)
-	updateFn func(ctx context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error)
-	deleteFn func(ctx context.Context, id uint) error
+	createFn  func(ctx context.Context, in repository.RecipeCreate) (recipe.Recipe, error)
+	getFn     func(ctx context.Context, id uint) (recipe.Recipe, error)
+	listFn    func(ctx context.Context, q repository.RecipeListQuery) ([]recipe.Recipe, error)
+	byIDsFn   func(ctx context.Context, ids []uint) ([]recipe.Recipe, error)
+	forksFn   func(ctx context.Context, id uint, limit, offset int) ([]recipe.Recipe, error)
+	updateFn  func(ctx context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error)
+	archiveFn func(ctx context.Context, id uint, at time.Time) error
+	restoreFn func(ctx context.Context, id uint) (recipe.Recipe, error)
+	imageFn   func(ctx context.Context, id uint, img *repository.Image) (recipe.Recipe, error)
+	markFn    func(ctx context.Context, foodID uint) ([]uint, error)
+	parentsFn func(ctx context.Context, id uint) ([]uint, error)
+	staleFn   func(ctx context.Context, afterID uint, limit int) ([]uint, error)
+	depthFn   func(ctx context.Context, id uint, limit int) (int, error)
 }
 
 func (f fakeRecipeStore) Create(ctx context.Context, in repository.RecipeCreate) (recipe.Recipe, error) {
@@ -35,18 +49,25 @@ func (f fakeRecipeStore) GetByID(ctx context.Context, id uint) (recipe.Recipe, e
 	return f.getFn(ctx, id)
 }
 
-func (f fakeRecipeStore) List(ctx context.Context, limit, offset int) ([]recipe.Recipe, error) {
+func (f fakeRecipeStore) List(ctx context.Context, q repository.RecipeListQuery) ([]recipe.Recipe, error) {
 	if f.listFn == nil {
 		return nil, nil
 	}
-	return f.listFn(ctx, limit, offset)
+	return f.listFn(ctx, q)
 }
 
-func (f fakeRecipeStore) SearchByName(ctx context.Context, query string, limit, offset int) ([]recipe.Recipe, error) {
-	if f.searchFn == nil {
+func (f fakeRecipeStore) ListByIDs(ctx context.Context, ids []uint) ([]recipe.Recipe, error) {
+	if f.byIDsFn == nil {
 		return nil, nil
 	}
-	return f.searchFn(ctx, query, limit, offset)
+	return f.byIDsFn(ctx, ids)
+}
+
+func (f fakeRecipeStore) ListForks(ctx context.Context, id uint, limit, offset int) ([]recipe.Recipe, error) {
+	if f.forksFn == nil {
+		return nil, nil
+	}
+	return f.forksFn(ctx, id, limit, offset)
 }
 
 func (f fakeRecipeStore) Update(ctx context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error) {
@@ -56,15 +77,58 @@ func (f fakeRecipeStore) Update(ctx context.Context, id uint, in repository.Reci
 	return f.updateFn(ctx, id, in)
 }
 
-func (f fakeRecipeStore) Delete(ctx context.Context, id uint) error {
-	if f.deleteFn == nil {
+func (f fakeRecipeStore) Archive(ctx context.Context, id uint, at time.Time) error {
+	if f.archiveFn == nil {
 		return nil
 	}
-	return f.deleteFn(ctx, id)
+	return f.archiveFn(ctx, id, at)
+}
+
+func (f fakeRecipeStore) Restore(ctx context.Context, id uint) (recipe.Recipe, error) {
+	if f.restoreFn == nil {
+		return recipe.Recipe{ID: id}, nil
+	}
+	return f.restoreFn(ctx, id)
+}
+
+func (f fakeRecipeStore) SetImage(ctx context.Context, id uint, img *repository.Image) (recipe.Recipe, error) {
+	if f.imageFn == nil {
+		return recipe.Recipe{ID: id}, nil
+	}
+	return f.imageFn(ctx, id, img)
+}
+
+func (f fakeRecipeStore) MarkStaleByFood(ctx context.Context, foodID uint) ([]uint, error) {
+	if f.markFn == nil {
+		return nil, nil
+	}
+	return f.markFn(ctx, foodID)
+}
+
+func (f fakeRecipeStore) MarkStaleByRecipe(ctx context.Context, id uint) ([]uint, error) {
+	if f.parentsFn == nil {
+		return nil, nil
+	}
+	return f.parentsFn(ctx, id)
+}
+
+func (f fakeRecipeStore) ParentDepth(ctx context.Context, id uint, limit int) (int, error) {
+	if f.depthFn == nil {
+		return 0, nil
+	}
+	return f.depthFn(ctx, id, limit)
+}
+
+func (f fakeRecipeStore) ListStale(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	if f.staleFn == nil {
+		return nil, nil
+	}
+	return f.staleFn(ctx, afterID, limit)
 }
 
 type fakeFoodReader struct {
-	getFn func(ctx context.Context, id uint) (food.Food, error)
+	getFn   func(ctx context.Context, id uint) (food.Food, error)
+	byIDsFn func(ctx context.Context, ids []uint) ([]food.Food, error)
 }
 
 func (f fakeFoodReader) GetByID(ctx context.Context, id uint) (food.Food, error) {
@@ -74,17 +138,61 @@ func (f fakeFoodReader) GetByID(ctx context.Context, id uint) (food.Food, error)
 	return f.getFn(ctx, id)
 }
 
+func (f fakeFoodReader) ListByIDs(ctx context.Context, ids []uint) ([]food.Food, error) {
+	if f.byIDsFn == nil {
+		return nil, nil
+	}
+	return f.byIDsFn(ctx, ids)
+}
+
+// fakeCookingFactors holds the yield factor of each cooking method and
+// category, keyed by method and then category, 0 for the default.
+type fakeCookingFactors map[uint]map[uint]float64
+
+func (f fakeCookingFactors) ListMethods(_ context.Context) ([]cooking.Method, error) {
+	return nil, nil
+}
+
+func (f fakeCookingFactors) YieldFactor(_ context.Context, methodID uint, categoryID *uint) (cooking.Factor, error) {
+	factors, ok := f[methodID]
+	if !ok {
+		return cooking.Factor{}, repository.ErrNotFound
+	}
+	if categoryID != nil {
+		if factor, ok := factors[*categoryID]; ok {
+			return cooking.Factor{MethodID: methodID, CategoryID: categoryID, YieldFactor: factor}, nil
+		}
+	}
+	return cooking.Factor{MethodID: methodID, YieldFactor: factors[0]}, nil
+}
+
+// fakeMoistureFactors gives every factor of the wrapped fakeCookingFactors
+// the same moisture retention.
+type fakeMoistureFactors struct {
+	fakeCookingFactors
+	retention float64
+}
+
+func (f fakeMoistureFactors) YieldFactor(ctx context.Context, methodID uint, categoryID *uint) (cooking.Factor, error) {
+	factor, err := f.fakeCookingFactors.YieldFactor(ctx, methodID, categoryID)
+	factor.MoistureRetention = f.retention
+	return factor, err
+}
+
 func TestRecipeServiceCreate(t *testing.T) {
 	svc := service.NewRecipeService(
 		fakeRecipeStore{createFn: func(_ context.Context, in repository.RecipeCreate) (recipe.Recipe, error) {
 			if in.KcalPer100g <= 0 {
 				t.Fatalf("expected calculated kcal_per_100g > 0")
 			}
+			if len(in.Ingredients) != 1 || in.Ingredients[0].FoodVersion == nil || *in.Ingredients[0].FoodVersion != 2 {
+				t.Fatalf("expected ingredient stamped with food version 2, got %#v", in.Ingredients)
+			}
 			return recipe.Recipe{ID: 1, Name: in.Name, YieldWeightG: in.YieldWeightG, KcalPer100g: in.KcalPer100g, ProteinPer100g: in.ProteinPer100g, CarbsPer100g: in.CarbsPer100g, FatPer100g: in.FatPer100g}, nil
 		}},
 		fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
 			if id == 1 {
-				return food.Food{Name: "Beef", KcalPer100g: 250, ProteinPer100g: 26, CarbsPer100g: 0, FatPer100g: 15}, nil
+				return food.Food{Name: "Beef", KcalPer100g: 250, ProteinPer100g: 26, CarbsPer100g: 0, FatPer100g: 15, CurrentVersion: 2}, nil
 			}
 			return food.Food{}, repository.ErrNotFound
 		}},
@@ -102,6 +210,184 @@ func TestRecipeServiceCreate(t *testing.T) {
 	}
 }
 
+func TestRecipeServiceServings(t *testing.T) {
+	store := fakeRecipeStore{
+		createFn: func(_ context.Context, in repository.RecipeCreate) (recipe.Recipe, error) {
+			if in.Servings != 1 || in.ServingName != recipe.DefaultServingName {
+				t.Fatalf("expected default servings, got %d %q", in.Servings, in.ServingName)
+			}
+			return recipe.Recipe{ID: 1}, nil
+		},
+		getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+			foodID := uint(1)
+			return recipe.Recipe{ID: id, YieldWeightG: 800, Servings: 4, KcalPer100g: 150, Ingredients: []recipeingredient.RecipeIngredient{
+				{FoodID: &foodID, RawWeightG: 500},
+			}}, nil
+		},
+	}
+	svc := service.NewRecipeService(store, fakeFoodReader{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+		return food.Food{KcalPer100g: 100}, nil
+	}})
+	in := service.CreateRecipeInput{Name: "Stew", YieldWeightG: 800, Ingredients: []service.RecipeIngredientInput{{FoodID: 1, RawWeightG: 500}}}
+	if _, err := svc.Create(context.Background(), 1, in); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	in.Servings = recipe.MaxServings + 1
+	if _, err := svc.Create(context.Background(), 1, in); !errors.Is(err, service.ErrInvalidServings) {
+		t.Fatalf("expected ErrInvalidServings, got %v", err)
+	}
+	in.Servings = 2
+	in.ServingName = strings.Repeat("x", recipe.MaxServingNameLength+1)
+	if _, err := svc.Create(context.Background(), 1, in); !errors.Is(err, service.ErrInvalidServingName) {
+		t.Fatalf("expected ErrInvalidServingName, got %v", err)
+	}
+
+	scaled, err := svc.Scale(context.Background(), 1, 6)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if scaled.Servings != 6 || scaled.YieldWeightG != 1200 || scaled.Ingredients[0].RawWeightG != 750 {
+		t.Fatalf("expected ingredients scaled by 1.5, got %+v", scaled)
+	}
+	if scaled.PerServing.WeightG != 200 || scaled.PerServing.Kcal != 300 || scaled.PerBatch.Kcal != 1800 {
+		t.Fatalf("unexpected portions %+v %+v", scaled.PerServing, scaled.PerBatch)
+	}
+	if _, err := svc.Scale(context.Background(), 1, 0); !errors.Is(err, service.ErrInvalidServings) {
+		t.Fatalf("expected ErrInvalidServings, got %v", err)
+	}
+}
+
+func TestRecipeServiceImport(t *testing.T) {
+	finder := fakeFoodStore{candidatesFn: func(_ context.Context, q repository.DuplicateCandidateQuery) ([]food.Food, error) {
+		if q.UserID != 7 {
+			t.Fatalf("expected candidates for user 7, got %d", q.UserID)
+		}
+		return []food.Food{
+			{ID: 1, Name: "Chicken breast, raw"},
+			{ID: 2, Name: "Chicken thigh"},
+			{ID: 3, Name: "Reis", Translations: []locale.Translation{{Locale: "en", Name: "Rice"}}},
+			{ID: 4, Name: "Olive oil"},
+		}, nil
+	}}
+	svc := service.NewRecipeService(fakeRecipeStore{}, fakeFoodReader{})
+	svc.SetFoodCandidateFinder(finder)
+
+	draft, err := svc.Import(context.Background(), 7, "200 g chicken breast\n1 cup rice\n2 eggs")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if draft.Servings != 1 || draft.YieldWeightG != 440 || len(draft.Ingredients) != 3 {
+		t.Fatalf("unexpected draft %+v", draft)
+	}
+	chicken := draft.Ingredients[0]
+	if chicken.FoodID == nil || *chicken.FoodID != 1 || chicken.Matches[0].Confidence < service.ImportMatchThreshold {
+		t.Fatalf("expected chicken breast matched, got %+v", chicken)
+	}
+	rice := draft.Ingredients[1]
+	if rice.FoodID == nil || *rice.FoodID != 3 || !rice.ApproximateWeight {
+		t.Fatalf("expected rice matched by translation with approximate weight, got %+v", rice)
+	}
+	eggs := draft.Ingredients[2]
+	if eggs.FoodID != nil || eggs.RawWeightG != nil || len(eggs.Matches) != 0 {
+		t.Fatalf("expected eggs unmatched and without weight, got %+v", eggs)
+	}
+
+	if _, err := svc.Import(context.Background(), 7, "<html><body>nothing</body></html>"); !errors.Is(err, service.ErrInvalidRecipeImport) {
+		t.Fatalf("expected ErrInvalidRecipeImport, got %v", err)
+	}
+	if _, err := svc.Import(context.Background(), 0, "1 egg"); !errors.Is(err, service.ErrInvalidUserID) {
+		t.Fatalf("expected ErrInvalidUserID, got %v", err)
+	}
+}
+
+func TestRecipeServiceDetails(t *testing.T) {
+	foodID := uint(1)
+	store := fakeRecipeStore{
+		createFn: func(_ context.Context, in repository.RecipeCreate) (recipe.Recipe, error) {
+			if in.Description != "Hearty stew." || in.PrepTimeMin != 20 || in.CookTimeMin != 90 {
+				t.Fatalf("unexpected details %+v", in)
+			}
+			if len(in.Tags) != 1 || in.Tags[0] != "slow cooker" || len(in.Instructions) != 2 || in.Instructions[0] != "Brown the beef." {
+				t.Fatalf("expected normalized tags and steps, got %q %q", in.Tags, in.Instructions)
+			}
+			if in.Ingredients[0].Note != "cut in cubes" {
+				t.Fatalf("expected ingredient note kept, got %q", in.Ingredients[0].Note)
+			}
+			return recipe.Recipe{ID: 1}, nil
+		},
+		getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+			return recipe.Recipe{ID: id, UserID: 1, YieldWeightG: 1000, Ingredients: []recipeingredient.RecipeIngredient{
+				{FoodID: &foodID, RawWeightG: 500, Note: "diced"},
+			}}, nil
+		},
+		updateFn: func(_ context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error) {
+			if in.Ingredients == nil || (*in.Ingredients)[0].Note != "diced" {
+				t.Fatalf("expected recalculation to keep ingredient notes, got %+v", in.Ingredients)
+			}
+			if in.CookTimeMin == nil || *in.CookTimeMin != 0 || in.PrepTimeMin != nil {
+				t.Fatalf("expected only cook time cleared, got %+v", in)
+			}
+			return recipe.Recipe{ID: id}, nil
+		},
+		listFn: func(_ context.Context, q repository.RecipeListQuery) ([]recipe.Recipe, error) {
+			if len(q.Tags) != 1 || q.Tags[0] != "dinner" || q.MaxTotalTimeMin != 30 {
+				t.Fatalf("unexpected list query %+v", q)
+			}
+			return nil, nil
+		},
+	}
+	svc := service.NewRecipeService(store, fakeFoodReader{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+		return food.Food{KcalPer100g: 250}, nil
+	}})
+
+	in := service.CreateRecipeInput{
+		Name:         "Goulash",
+		YieldWeightG: 1000,
+		Description:  "  Hearty stew. ",
+		PrepTimeMin:  20,
+		CookTimeMin:  90,
+		Tags:         []string{"Slow  Cooker", "slow cooker"},
+		Instructions: []string{" Brown the beef. ", "Simmer."},
+		Ingredients:  []service.RecipeIngredientInput{{FoodID: 1, RawWeightG: 500, Note: " cut in  cubes "}},
+	}
+	if _, err := svc.Create(context.Background(), 1, in); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	invalid := []struct {
+		edit func(*service.CreateRecipeInput)
+		err  error
+	}{
+		{func(in *service.CreateRecipeInput) { in.CookTimeMin = recipe.MaxTimeMin + 1 }, service.ErrInvalidRecipeTime},
+		{func(in *service.CreateRecipeInput) { in.Tags = []string{" "} }, service.ErrInvalidRecipeTags},
+		{func(in *service.CreateRecipeInput) { in.Instructions = []string{"Brown the beef.", ""} }, service.ErrInvalidInstructions},
+		{func(in *service.CreateRecipeInput) {
+			in.Description = strings.Repeat("x", recipe.MaxDescriptionLength+1)
+		}, service.ErrInvalidRecipeDescription},
+		{func(in *service.CreateRecipeInput) {
+			in.Ingredients = []service.RecipeIngredientInput{{FoodID: 1, RawWeightG: 500, Note: strings.Repeat("x", recipeingredient.MaxNoteLength+1)}}
+		}, service.ErrInvalidRecipeIngredients},
+	}
+	for _, c := range invalid {
+		bad := in
+		c.edit(&bad)
+		if _, err := svc.Create(context.Background(), 1, bad); !errors.Is(err, c.err) {
+			t.Fatalf("expected %v, got %v", c.err, err)
+		}
+	}
+
+	yield := 900.0
+	cleared := 0
+	if _, err := svc.Update(context.Background(), 1, 1, service.UpdateRecipeInput{YieldWeightG: &yield, CookTimeMin: &cleared}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := svc.List(context.Background(), 1, service.RecipeListInput{Tags: []string{"Dinner"}, MaxTotalTimeMin: 30, Limit: 20}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
 func TestRecipeServiceIngredientFoodMissing(t *testing.T) {
 	svc := service.NewRecipeService(
 		fakeRecipeStore{},
@@ -122,11 +408,60 @@ func TestRecipeServiceIngredientFoodMissing(t *testing.T) {
 	}
 }
 
+func TestRecipeServiceIngredientFoodPrivateToOtherUser(t *testing.T) {
+	svc := service.NewRecipeService(
+		fakeRecipeStore{},
+		fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+			return food.Food{ID: id, UserID: 9, Visibility: food.VisibilityPrivate, KcalPer100g: 100}, nil
+		}},
+	)
+
+	_, err := svc.Create(context.Background(), 1, service.CreateRecipeInput{
+		Name:         "Goulash",
+		YieldWeightG: 1000,
+		Ingredients: []service.RecipeIngredientInput{
+			{FoodID: 3, RawWeightG: 200},
+		},
+	})
+	if !errors.Is(err, service.ErrIngredientFoodNotFound) {
+		t.Fatalf("expected ErrIngredientFoodNotFound, got %v", err)
+	}
+}
+
+func TestRecipeServiceIngredientFoodArchived(t *testing.T) {
+	archivedAt := time.Now().UTC()
+	archivedFood := fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+		return food.Food{ID: id, UserID: 7, KcalPer100g: 100, ArchivedAt: &archivedAt}, nil
+	}}
+
+	t.Run("rejects archived food in new recipe", func(t *testing.T) {
+		svc := service.NewRecipeService(fakeRecipeStore{}, archivedFood)
+		_, err := svc.Create(context.Background(), 7, service.CreateRecipeInput{
+			Name:         "Goulash",
+			YieldWeightG: 1000,
+			Ingredients:  []service.RecipeIngredientInput{{FoodID: 3, RawWeightG: 200}},
+		})
+		if !errors.Is(err, service.ErrIngredientFoodArchived) {
+			t.Fatalf("expected ErrIngredientFoodArchived, got %v", err)
+		}
+	})
+
+	t.Run("keeps archived food already in recipe", func(t *testing.T) {
+		svc := service.NewRecipeService(fakeRecipeStore{getFn: func(_ context.Context, _ uint) (recipe.Recipe, error) {
+			return recipe.Recipe{ID: 1, UserID: 7, YieldWeightG: 1000, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: uintRef(3), RawWeightG: 500}}}, nil
+		}}, archivedFood)
+		yield := 900.0
+		if _, err := svc.Update(context.Background(), 7, 1, service.UpdateRecipeInput{YieldWeightG: &yield}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+}
+
 func TestRecipeServiceUpdateRecalculates(t *testing.T) {
 	svc := service.NewRecipeService(
 		fakeRecipeStore{
 			getFn: func(_ context.Context, _ uint) (recipe.Recipe, error) {
-				return recipe.Recipe{ID: 1, UserID: 7, Name: "Goulash", YieldWeightG: 1000, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: 1, RawWeightG: 500}}}, nil
+				return recipe.Recipe{ID: 1, UserID: 7, Name: "Goulash", YieldWeightG: 1000, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: uintRef(1), RawWeightG: 500}}}, nil
 			},
 			updateFn: func(_ context.Context, _ uint, in repository.RecipeUpdate) (recipe.Recipe, error) {
 				if in.KcalPer100g == nil || *in.KcalPer100g <= 0 {
@@ -148,11 +483,392 @@ func TestRecipeServiceUpdateRecalculates(t *testing.T) {
 	}
 }
 
+func TestRecipeServiceEstimatesYield(t *testing.T) {
+	pasta, sauce := uint(1), uint(2)
+	pastaCategory := uint(9)
+	boiled := uint(3)
+	var created repository.RecipeCreate
+	var updated repository.RecipeUpdate
+	svc := service.NewRecipeService(
+		fakeRecipeStore{
+			createFn: func(_ context.Context, in repository.RecipeCreate) (recipe.Recipe, error) {
+				created = in
+				return recipe.Recipe{ID: 1}, nil
+			},
+			getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+				return recipe.Recipe{ID: id, UserID: 7, YieldWeightG: 320, YieldEstimated: true, Ingredients: []recipeingredient.RecipeIngredient{
+					{FoodID: &pasta, RawWeightG: 100, CookingMethodID: &boiled},
+					{FoodID: &sauce, RawWeightG: 100},
+				}}, nil
+			},
+			updateFn: func(_ context.Context, _ uint, in repository.RecipeUpdate) (recipe.Recipe, error) {
+				updated = in
+				return recipe.Recipe{ID: 1}, nil
+			},
+		},
+		fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+			if id == pasta {
+				return food.Food{ID: id, CategoryID: &pastaCategory, KcalPer100g: 350}, nil
+			}
+			return food.Food{ID: id, KcalPer100g: 50}, nil
+		}},
+	)
+	svc.SetCookingFactors(fakeCookingFactors{boiled: {0: 1, pastaCategory: 2.2}})
+
+	in := service.CreateRecipeInput{Name: "Pasta al pomodoro", Ingredients: []service.RecipeIngredientInput{
+		{FoodID: pasta, RawWeightG: 100, CookingMethodID: boiled},
+		{FoodID: sauce, RawWeightG: 100},
+	}}
+	if _, err := svc.Create(context.Background(), 7, in); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !created.YieldEstimated || math.Abs(created.YieldWeightG-320) > 1e-9 || math.Abs(created.KcalPer100g-400.0/3.2) > 1e-9 {
+		t.Fatalf("expected 220g cooked pasta and 100g sauce, got %+v", created)
+	}
+	if created.Ingredients[0].CookingMethodID == nil || *created.Ingredients[0].CookingMethodID != boiled || created.Ingredients[1].CookingMethodID != nil {
+		t.Fatalf("expected only the pasta to keep its cooking method, got %+v", created.Ingredients)
+	}
+
+	in.YieldWeightG = 400
+	if _, err := svc.Create(context.Background(), 7, in); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.YieldEstimated || created.YieldWeightG != 400 {
+		t.Fatalf("expected a stated yield to be kept, got %+v", created)
+	}
+
+	in.Ingredients[0].CookingMethodID = 42
+	if _, err := svc.Create(context.Background(), 7, in); !errors.Is(err, service.ErrCookingMethodNotFound) {
+		t.Fatalf("expected ErrCookingMethodNotFound, got %v", err)
+	}
+
+	// Estimated yields follow the ingredients until a yield is stated.
+	ingredients := []service.RecipeIngredientInput{{FoodID: pasta, RawWeightG: 200, CookingMethodID: boiled}}
+	if _, err := svc.Update(context.Background(), 7, 1, service.UpdateRecipeInput{Ingredients: &ingredients}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.YieldEstimated == nil || !*updated.YieldEstimated || math.Abs(*updated.YieldWeightG-440) > 1e-9 {
+		t.Fatalf("expected the yield estimated again, got %v %v", updated.YieldWeightG, updated.YieldEstimated)
+	}
+	yield := 500.0
+	if _, err := svc.Update(context.Background(), 7, 1, service.UpdateRecipeInput{YieldWeightG: &yield}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if *updated.YieldEstimated || *updated.YieldWeightG != 500 {
+		t.Fatalf("expected the stated yield, got %v %v", *updated.YieldWeightG, *updated.YieldEstimated)
+	}
+
+	sub := []service.RecipeIngredientInput{{SubRecipeID: 5, RawWeightG: 100, CookingMethodID: boiled}}
+	if _, err := svc.Update(context.Background(), 7, 1, service.UpdateRecipeInput{Ingredients: &sub}); !errors.Is(err, service.ErrInvalidRecipeIngredients) {
+		t.Fatalf("expected ErrInvalidRecipeIngredients for a cooked sub-recipe, got %v", err)
+	}
+}
+
+func TestRecipeServiceSubRecipeReestimatesYield(t *testing.T) {
+	pasta, pastaCategory, boiled := uint(1), uint(9), uint(3)
+	var updated repository.RecipeUpdate
+	svc := service.NewRecipeService(
+		fakeRecipeStore{
+			getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+				if id == 2 {
+					// Estimated at 100 g before pasta was boiled by a factor of 2.5.
+					return recipe.Recipe{ID: 2, UserID: 7, YieldWeightG: 100, YieldEstimated: true, Ingredients: []recipeingredient.RecipeIngredient{
+						{FoodID: &pasta, RawWeightG: 100, CookingMethodID: &boiled},
+					}}, nil
+				}
+				return recipe.Recipe{ID: 1, UserID: 7, YieldWeightG: 100, Ingredients: []recipeingredient.RecipeIngredient{
+					{SubRecipeID: uintRef(2), RawWeightG: 100},
+				}}, nil
+			},
+			updateFn: func(_ context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error) {
+				updated = in
+				return recipe.Recipe{ID: id}, nil
+			},
+		},
+		fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+			return food.Food{ID: id, CategoryID: &pastaCategory, KcalPer100g: 350}, nil
+		}},
+	)
+	svc.SetCookingFactors(fakeCookingFactors{boiled: {0: 1, pastaCategory: 2.5}})
+
+	if _, err := svc.Recalculate(context.Background(), 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.KcalPer100g == nil || math.Abs(*updated.KcalPer100g-140) > 1e-9 {
+		t.Fatalf("expected 350 kcal over 250 g of cooked pasta, got %+v", updated.KcalPer100g)
+	}
+}
+
+func TestRecipeServiceFoodChanged(t *testing.T) {
+	newService := func(stale []uint, recalculated *[]uint) *service.RecipeService {
+		return service.NewRecipeService(
+			fakeRecipeStore{
+				markFn: func(_ context.Context, foodID uint) ([]uint, error) {
+					if foodID != 3 {
+						t.Fatalf("expected food 3, got %d", foodID)
+					}
+					return stale, nil
+				},
+				getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+					return recipe.Recipe{ID: id, UserID: 7, YieldWeightG: 200, Stale: true, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: uintRef(3), RawWeightG: 200}}}, nil
+				},
+				updateFn: func(_ context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error) {
+					if in.Stale == nil || *in.Stale || in.ProteinPer100g == nil || *in.ProteinPer100g != 31 {
+						t.Fatalf("expected recalculated values clearing stale, got %+v", in)
+					}
+					*recalculated = append(*recalculated, id)
+					return recipe.Recipe{ID: id}, nil
+				},
+			},
+			fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+				return food.Food{ID: id, UserID: 7, Name: "Chicken breast", KcalPer100g: 165, ProteinPer100g: 31}, nil
+			}},
+		)
+	}
+
+	t.Run("small fan-out is recalculated right away", func(t *testing.T) {
+		var recalculated []uint
+		svc := newService([]uint{4, 5}, &recalculated)
+		if err := svc.FoodChanged(context.Background(), 3); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(recalculated) != 2 {
+			t.Fatalf("expected both recipes recalculated, got %v", recalculated)
+		}
+	})
+
+	t.Run("large fan-out is left stale", func(t *testing.T) {
+		var recalculated []uint
+		svc := newService([]uint{4, 5}, &recalculated)
+		svc.SetSyncLimit(1)
+		if err := svc.FoodChanged(context.Background(), 3); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(recalculated) != 0 {
+			t.Fatalf("expected recipes to stay stale, got %v", recalculated)
+		}
+	})
+}
+
+func TestRecipeServiceRecalculateStale(t *testing.T) {
+	var recalculated []uint
+	svc := service.NewRecipeService(
+		fakeRecipeStore{
+			staleFn: func(_ context.Context, afterID uint, limit int) ([]uint, error) {
+				if afterID != 2 || limit != 50 {
+					t.Fatalf("expected recipes after 2, limit 50, got %d, %d", afterID, limit)
+				}
+				return []uint{3, 4, 5}, nil
+			},
+			getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+				switch id {
+				case 3:
+					// Its food is gone, so it fails on every pass.
+					return recipe.Recipe{ID: id, YieldWeightG: 100, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: uintRef(9), RawWeightG: 100}}}, nil
+				case 5:
+					return recipe.Recipe{}, repository.ErrNotFound
+				}
+				return recipe.Recipe{ID: id, YieldWeightG: 100, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: uintRef(3), RawWeightG: 100}}}, nil
+			},
+			updateFn: func(_ context.Context, id uint, _ repository.RecipeUpdate) (recipe.Recipe, error) {
+				recalculated = append(recalculated, id)
+				return recipe.Recipe{ID: id}, nil
+			},
+		},
+		fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+			if id == 9 {
+				return food.Food{}, repository.ErrNotFound
+			}
+			return food.Food{ID: id, KcalPer100g: 165}, nil
+		}},
+	)
+
+	batch, err := svc.RecalculateStale(context.Background(), 2, 50)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(recalculated) != 1 || recalculated[0] != 4 {
+		t.Fatalf("expected recipe 4 recalculated past the failing 3, got %v", recalculated)
+	}
+	if batch.Checked != 3 || batch.Recalculated != 1 || batch.LastID != 5 || len(batch.Failed) != 1 || batch.Failed[3] == nil {
+		t.Fatalf("expected 3 checked, 1 recalculated and recipe 3 failed, got %+v", batch)
+	}
+}
+
+func TestRecipeServiceRefresh(t *testing.T) {
+	store := fakeRecipeStore{getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+		return recipe.Recipe{ID: id, UserID: 7, YieldWeightG: 100, Stale: true, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: uintRef(3), RawWeightG: 100}}}, nil
+	}}
+	store.updateFn = func(_ context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error) {
+		if in.Stale == nil || *in.Stale {
+			t.Fatalf("expected stale to be cleared")
+		}
+		return recipe.Recipe{ID: id, KcalPer100g: *in.KcalPer100g}, nil
+	}
+	svc := service.NewRecipeService(store, fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+		return food.Food{ID: id, KcalPer100g: 165}, nil
+	}})
+
+	if _, err := svc.Refresh(context.Background(), 8, 1); !errors.Is(err, service.ErrRecipeForbidden) {
+		t.Fatalf("expected ErrRecipeForbidden, got %v", err)
+	}
+	got, err := svc.Refresh(context.Background(), 7, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.KcalPer100g != 165 {
+		t.Fatalf("expected recalculated kcal, got %v", got.KcalPer100g)
+	}
+}
+
+func TestRecipeServiceSubRecipes(t *testing.T) {
+	// Recipe 2 is 100 g of a 200 kcal/100g food cooked down to 50 g, so
+	// 400 kcal/100g. Recipe 3 uses recipe 2 and recipe 4 uses recipe 3.
+	recipes := map[uint]recipe.Recipe{
+		2: {ID: 2, UserID: 9, YieldWeightG: 50, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: uintRef(1), RawWeightG: 100}}},
+		3: {ID: 3, UserID: 7, YieldWeightG: 100, Ingredients: []recipeingredient.RecipeIngredient{{SubRecipeID: uintRef(2), RawWeightG: 100}}},
+		4: {ID: 4, UserID: 7, YieldWeightG: 100, Ingredients: []recipeingredient.RecipeIngredient{{SubRecipeID: uintRef(3), RawWeightG: 100}}},
+	}
+	store := fakeRecipeStore{getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+		value, ok := recipes[id]
+		if !ok {
+			return recipe.Recipe{}, repository.ErrNotFound
+		}
+		return value, nil
+	}}
+	foods := fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+		return food.Food{ID: id, Visibility: food.VisibilityPublic, KcalPer100g: 200, ProteinPer100g: 10}, nil
+	}}
+
+	t.Run("create resolves sub-recipes from their ingredients", func(t *testing.T) {
+		create := store
+		create.createFn = func(_ context.Context, in repository.RecipeCreate) (recipe.Recipe, error) {
+			if in.KcalPer100g != 100 || in.ProteinPer100g != 5 {
+				t.Fatalf("expected 100 kcal and 5 g protein per 100g, got %+v", in)
+			}
+			if len(in.Ingredients) != 1 || in.Ingredients[0].SubRecipeID == nil || *in.Ingredients[0].SubRecipeID != 2 || in.Ingredients[0].FoodID != nil {
+				t.Fatalf("expected sub-recipe ingredient, got %+v", in.Ingredients)
+			}
+			return recipe.Recipe{ID: 5}, nil
+		}
+		svc := service.NewRecipeService(create, foods)
+		_, err := svc.Create(context.Background(), 7, service.CreateRecipeInput{
+			Name:         "Burrito bowl",
+			YieldWeightG: 100,
+			Ingredients:  []service.RecipeIngredientInput{{SubRecipeID: 2, RawWeightG: 25}},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("create rejects missing sub-recipes", func(t *testing.T) {
+		svc := service.NewRecipeService(store, foods)
+		_, err := svc.Create(context.Background(), 7, service.CreateRecipeInput{
+			Name:         "Burrito bowl",
+			YieldWeightG: 100,
+			Ingredients:  []service.RecipeIngredientInput{{SubRecipeID: 99, RawWeightG: 25}},
+		})
+		if !errors.Is(err, service.ErrIngredientRecipeNotFound) {
+			t.Fatalf("expected ErrIngredientRecipeNotFound, got %v", err)
+		}
+	})
+
+	t.Run("update rejects cycles", func(t *testing.T) {
+		svc := service.NewRecipeService(store, foods)
+		ingredients := []service.RecipeIngredientInput{{SubRecipeID: 4, RawWeightG: 10}}
+		_, err := svc.Update(context.Background(), 7, 3, service.UpdateRecipeInput{Ingredients: &ingredients})
+		if !errors.Is(err, service.ErrRecipeCycle) {
+			t.Fatalf("expected ErrRecipeCycle, got %v", err)
+		}
+	})
+
+	t.Run("nesting counts recipes above the updated one", func(t *testing.T) {
+		deep := store
+		deep.depthFn = func(_ context.Context, id uint, limit int) (int, error) {
+			if id != 3 || limit != recipeingredient.MaxDepth {
+				t.Fatalf("unexpected depth lookup %d %d", id, limit)
+			}
+			return recipeingredient.MaxDepth, nil
+		}
+		svc := service.NewRecipeService(deep, foods)
+		ingredients := []service.RecipeIngredientInput{{SubRecipeID: 2, RawWeightG: 10}}
+		_, err := svc.Update(context.Background(), 7, 3, service.UpdateRecipeInput{Ingredients: &ingredients})
+		if !errors.Is(err, service.ErrRecipeTooDeep) {
+			t.Fatalf("expected ErrRecipeTooDeep, got %v", err)
+		}
+	})
+
+	t.Run("update recalculates parents", func(t *testing.T) {
+		var recalculated []uint
+		update := store
+		update.updateFn = func(_ context.Context, id uint, in repository.RecipeUpdate) (recipe.Recipe, error) {
+			recalculated = append(recalculated, id)
+			return recipe.Recipe{ID: id}, nil
+		}
+		update.parentsFn = func(_ context.Context, id uint) ([]uint, error) {
+			if id != 3 {
+				t.Fatalf("expected parents of recipe 3, got %d", id)
+			}
+			return []uint{4}, nil
+		}
+		svc := service.NewRecipeService(update, foods)
+		yield := 80.0
+		if _, err := svc.Update(context.Background(), 7, 3, service.UpdateRecipeInput{YieldWeightG: &yield}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(recalculated) != 2 || recalculated[0] != 3 || recalculated[1] != 4 {
+			t.Fatalf("expected recipe 3 then its parent 4, got %v", recalculated)
+		}
+	})
+}
+
+func TestRecipeServiceTranslations(t *testing.T) {
+	t.Run("create stores normalized translations", func(t *testing.T) {
+		svc := service.NewRecipeService(
+			fakeRecipeStore{createFn: func(_ context.Context, in repository.RecipeCreate) (recipe.Recipe, error) {
+				if len(in.Translations) != 1 || in.Translations[0].Locale != "hu" || in.Translations[0].Name != "Gulyás" {
+					t.Fatalf("unexpected translations %+v", in.Translations)
+				}
+				return recipe.Recipe{ID: 1}, nil
+			}},
+			fakeFoodReader{getFn: func(_ context.Context, _ uint) (food.Food, error) {
+				return food.Food{Name: "Beef", KcalPer100g: 250}, nil
+			}},
+		)
+
+		_, err := svc.Create(context.Background(), 7, service.CreateRecipeInput{
+			Name:         "Goulash",
+			YieldWeightG: 1000,
+			Ingredients:  []service.RecipeIngredientInput{{FoodID: 1, RawWeightG: 500}},
+			Translations: []locale.Translation{{Locale: "hu-HU", Name: "  Gulyás "}},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("update rejects an empty name", func(t *testing.T) {
+		svc := service.NewRecipeService(
+			fakeRecipeStore{getFn: func(_ context.Context, _ uint) (recipe.Recipe, error) {
+				return recipe.Recipe{ID: 1, UserID: 7}, nil
+			}},
+			fakeFoodReader{},
+		)
+
+		translations := []locale.Translation{{Locale: "hu", Name: " "}}
+		_, err := svc.Update(context.Background(), 7, 1, service.UpdateRecipeInput{Translations: &translations})
+		if !errors.Is(err, service.ErrInvalidTranslations) {
+			t.Fatalf("expected ErrInvalidTranslations, got %v", err)
+		}
+	})
+}
+
 func TestRecipeServiceUpdateForbidden(t *testing.T) {
 	svc := service.NewRecipeService(
 		fakeRecipeStore{
 			getFn: func(_ context.Context, _ uint) (recipe.Recipe, error) {
-				return recipe.Recipe{ID: 1, UserID: 9, Name: "Goulash", YieldWeightG: 1000, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: 1, RawWeightG: 500}}}, nil
+				return recipe.Recipe{ID: 1, UserID: 9, Name: "Goulash", YieldWeightG: 1000, Ingredients: []recipeingredient.RecipeIngredient{{FoodID: uintRef(1), RawWeightG: 500}}}, nil
 			},
 		},
 		fakeFoodReader{},
@@ -181,23 +897,23 @@ func TestRecipeServiceDeleteForbidden(t *testing.T) {
 	}
 }
 
-func TestRecipeServiceSearch(t *testing.T) {
+func TestRecipeServiceList(t *testing.T) {
 	t.Run("validates pagination", func(t *testing.T) {
 		svc := service.NewRecipeService(fakeRecipeStore{}, fakeFoodReader{})
-		_, err := svc.Search(context.Background(), "soup", 0, 0)
+		_, err := svc.List(context.Background(), 7, service.RecipeListInput{Query: "soup"})
 		if !errors.Is(err, service.ErrInvalidPagination) {
 			t.Fatalf("expected ErrInvalidPagination, got %v", err)
 		}
 	})
 
-	t.Run("trims query and calls repo search", func(t *testing.T) {
+	t.Run("trims query and passes it to repo", func(t *testing.T) {
 		called := false
 		svc := service.NewRecipeService(
 			fakeRecipeStore{
-				searchFn: func(_ context.Context, query string, limit, offset int) ([]recipe.Recipe, error) {
+				listFn: func(_ context.Context, q repository.RecipeListQuery) ([]recipe.Recipe, error) {
 					called = true
-					if query != "soup" || limit != 20 || offset != 0 {
-						t.Fatalf("unexpected args: query=%q limit=%d offset=%d", query, limit, offset)
+					if q.Query != "soup" || q.Limit != 20 || q.Offset != 0 {
+						t.Fatalf("unexpected query: %+v", q)
 					}
 					return []recipe.Recipe{{ID: 1, Name: "Soup"}}, nil
 				},
@@ -205,7 +921,7 @@ func TestRecipeServiceSearch(t *testing.T) {
 			fakeFoodReader{},
 		)
 
-		values, err := svc.Search(context.Background(), "  soup  ", 20, 0)
+		values, err := svc.List(context.Background(), 7, service.RecipeListInput{Query: "  soup  ", Limit: 20})
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -213,4 +929,201 @@ func TestRecipeServiceSearch(t *testing.T) {
 			t.Fatalf("expected one value from search")
 		}
 	})
+
+	t.Run("exclude conflicts filters by the user's restrictions", func(t *testing.T) {
+		var got repository.RecipeListQuery
+		svc := service.NewRecipeService(
+			fakeRecipeStore{
+				listFn: func(_ context.Context, q repository.RecipeListQuery) ([]recipe.Recipe, error) {
+					got = q
+					return nil, nil
+				},
+			},
+			fakeFoodReader{},
+		)
+		svc.SetRestrictionReader(fakeUserReader{result: user.User{ID: 7, AvoidAllergens: []string{"peanuts"}, RequiredDietFlags: []string{"vegetarian"}}})
+
+		if _, err := svc.List(context.Background(), 7, service.RecipeListInput{Limit: 20}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got.AvoidAllergens) != 0 || len(got.RequiredDietFlags) != 0 {
+			t.Fatalf("expected no restrictions without exclude_conflicts, got %+v", got)
+		}
+		if _, err := svc.List(context.Background(), 7, service.RecipeListInput{ExcludeConflicts: true, Limit: 20}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(got.AvoidAllergens) != 1 || got.AvoidAllergens[0] != "peanuts" || len(got.RequiredDietFlags) != 1 || got.RequiredDietFlags[0] != "vegetarian" {
+			t.Fatalf("unexpected restrictions: %+v", got)
+		}
+	})
+}
+
+func uintRef(v uint) *uint {
+	return &v
+}
+
+func TestRecipeServiceFork(t *testing.T) {
+	beef, onion := uint(1), uint(2)
+	original := recipe.Recipe{
+		ID: 3, UserID: 5, Name: "Goulash", YieldWeightG: 1000, Servings: 4, ServingName: "bowl",
+		PrepTimeMin: 20, Tags: []string{"dinner"}, Instructions: []string{"Brown the beef."},
+		Ingredients: []recipeingredient.RecipeIngredient{
+			{FoodID: &beef, RawWeightG: 500, Note: "cubed"},
+			{FoodID: &onion, RawWeightG: 200},
+		},
+	}
+	store := fakeRecipeStore{
+		getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+			if id == 3 {
+				return original, nil
+			}
+			return recipe.Recipe{}, repository.ErrNotFound
+		},
+		createFn: func(_ context.Context, in repository.RecipeCreate) (recipe.Recipe, error) {
+			if in.UserID != 7 || in.ForkedFromID == nil || *in.ForkedFromID != 3 {
+				t.Fatalf("expected fork of 3 owned by 7, got %+v", in)
+			}
+			if in.Name != "Goulash" || in.Servings != 4 || in.ServingName != "bowl" || in.PrepTimeMin != 20 || !slices.Equal(in.Tags, original.Tags) {
+				t.Fatalf("expected details copied, got %+v", in)
+			}
+			if len(in.Ingredients) != 2 || in.Ingredients[0].Note != "cubed" || in.KcalPer100g != 160 {
+				t.Fatalf("expected ingredients copied and recalculated, got %+v", in)
+			}
+			return recipe.Recipe{ID: 9}, nil
+		},
+	}
+	// Beef is private to the original's owner; the fork keeps it anyway.
+	foods := fakeFoodReader{getFn: func(_ context.Context, id uint) (food.Food, error) {
+		if id == beef {
+			return food.Food{ID: beef, UserID: 5, KcalPer100g: 250, Visibility: food.VisibilityPrivate}, nil
+		}
+		return food.Food{ID: onion, KcalPer100g: 175, Visibility: food.VisibilityPublic}, nil
+	}}
+	svc := service.NewRecipeService(store, foods)
+
+	if _, err := svc.Fork(context.Background(), 7, 3); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := svc.Fork(context.Background(), 7, 4); !errors.Is(err, service.ErrRecipeNotFound) {
+		t.Fatalf("expected ErrRecipeNotFound, got %v", err)
+	}
+	if _, err := svc.Fork(context.Background(), 0, 3); !errors.Is(err, service.ErrInvalidUserID) {
+		t.Fatalf("expected ErrInvalidUserID, got %v", err)
+	}
+}
+
+func TestRecipeServiceForkChanges(t *testing.T) {
+	beef, onion, pepper := uint(1), uint(2), uint(3)
+	origin := uint(3)
+	original := recipe.Recipe{
+		ID: 3, Name: "Goulash", YieldWeightG: 1000, KcalPer100g: 160, Tags: []string{"dinner"},
+		Ingredients: []recipeingredient.RecipeIngredient{
+			{FoodID: &beef, RawWeightG: 500, Note: "cubed"},
+			{FoodID: &onion, RawWeightG: 200},
+			{FoodID: &onion, RawWeightG: 50},
+		},
+	}
+	fork := recipe.Recipe{
+		ID: 9, ForkedFromID: &origin, Name: "Lighter goulash", YieldWeightG: 1000, KcalPer100g: 135, Tags: []string{"dinner"},
+		Ingredients: []recipeingredient.RecipeIngredient{
+			{FoodID: &beef, RawWeightG: 400, Note: "cubed"},
+			{FoodID: &onion, RawWeightG: 200},
+			{FoodID: &pepper, RawWeightG: 100},
+		},
+	}
+	store := fakeRecipeStore{
+		getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+			switch id {
+			case 3:
+				return original, nil
+			case 9:
+				return fork, nil
+			}
+			return recipe.Recipe{}, repository.ErrNotFound
+		},
+		forksFn: func(_ context.Context, id uint, _, _ int) ([]recipe.Recipe, error) {
+			if id != 3 {
+				t.Fatalf("unexpected forks of %d", id)
+			}
+			return []recipe.Recipe{fork}, nil
+		},
+	}
+	svc := service.NewRecipeService(store, fakeFoodReader{})
+
+	changes, err := svc.Changes(context.Background(), 9)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if changes.OriginID != 3 || !slices.Equal(changes.Fields, []string{"name"}) || changes.Per100g.Kcal != -25 {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if len(changes.Ingredients) != 3 {
+		t.Fatalf("expected 3 ingredient changes, got %+v", changes.Ingredients)
+	}
+	changed, removed, added := changes.Ingredients[0], changes.Ingredients[1], changes.Ingredients[2]
+	if changed.Change != service.IngredientChanged || *changed.FoodID != beef || *changed.FromRawWeightG != 500 || *changed.ToRawWeightG != 400 {
+		t.Fatalf("expected beef reduced, got %+v", changed)
+	}
+	if removed.Change != service.IngredientRemoved || *removed.FoodID != onion || *removed.FromRawWeightG != 50 || removed.ToRawWeightG != nil {
+		t.Fatalf("expected second onion removed, got %+v", removed)
+	}
+	if added.Change != service.IngredientAdded || *added.FoodID != pepper || *added.ToRawWeightG != 100 {
+		t.Fatalf("expected pepper added, got %+v", added)
+	}
+
+	forks, err := svc.Forks(context.Background(), 3, 20, 0)
+	if err != nil || len(forks) != 1 || forks[0].Recipe.ID != 9 || len(forks[0].Changes.Ingredients) != 3 {
+		t.Fatalf("unexpected forks %+v, %v", forks, err)
+	}
+	if _, err := svc.Changes(context.Background(), 3); !errors.Is(err, service.ErrRecipeNotForked) {
+		t.Fatalf("expected ErrRecipeNotForked, got %v", err)
+	}
+}
+
+func TestRecipeServiceBreakdown(t *testing.T) {
+	beef, paprika, stock := uint(1), uint(2), uint(3)
+	svc := service.NewRecipeService(
+		fakeRecipeStore{
+			getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+				return recipe.Recipe{ID: id, Name: "Goulash", YieldWeightG: 1000, Ingredients: []recipeingredient.RecipeIngredient{
+					{ID: 1, FoodID: &beef, RawWeightG: 500},
+					{ID: 2, FoodID: &paprika, RawWeightG: 10},
+					{ID: 3, SubRecipeID: &stock, RawWeightG: 400},
+				}}, nil
+			},
+			byIDsFn: func(_ context.Context, ids []uint) ([]recipe.Recipe, error) {
+				if !slices.Equal(ids, []uint{stock}) {
+					t.Fatalf("unexpected sub-recipe lookup %v", ids)
+				}
+				return []recipe.Recipe{{ID: stock, Name: "Beef stock", KcalPer100g: 10, ProteinPer100g: 1, CarbsPer100g: 1}}, nil
+			},
+		},
+		fakeFoodReader{
+			getFn: func(_ context.Context, id uint) (food.Food, error) {
+				t.Fatalf("expected foods to be loaded in bulk, got a lookup of %d", id)
+				return food.Food{}, nil
+			},
+			byIDsFn: func(_ context.Context, ids []uint) ([]food.Food, error) {
+				return []food.Food{
+					{ID: beef, Name: "Beef chuck", Visibility: food.VisibilityPrivate, UserID: 7, KcalPer100g: 200, ProteinPer100g: 20, FatPer100g: 13},
+					{ID: paprika, Name: "Paprika", Visibility: food.VisibilityPublic, KcalPer100g: 280, ProteinPer100g: 14, CarbsPer100g: 54, FatPer100g: 13},
+				}, nil
+			},
+		},
+	)
+
+	_, breakdown, err := svc.Breakdown(context.Background(), 8, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	items := breakdown.Items
+	if len(items) != 3 || items[0].Name != "" || items[1].Name != "Paprika" || items[2].Name != "Beef stock" {
+		t.Fatalf("expected names of visible sources only, got %+v", items)
+	}
+	if items[0].Contribution.Kcal != 1000 || items[2].Contribution.Kcal != 40 || breakdown.Total.Kcal != 1068 {
+		t.Fatalf("unexpected contributions: %+v", breakdown)
+	}
+	if items[0].Share.FatPct < 98 || items[2].Share.FatPct != 0 {
+		t.Fatalf("unexpected fat shares: %+v", items)
+	}
 }
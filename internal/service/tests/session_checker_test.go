@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/service"
+)
+
+type fakeSessionActivityStore struct {
+	isActiveFn func(ctx context.Context, id uint, now time.Time) (bool, error)
+}
+
+func (f fakeSessionActivityStore) IsActive(ctx context.Context, id uint, now time.Time) (bool, error) {
+	if f.isActiveFn == nil {
+		return true, nil
+	}
+	return f.isActiveFn(ctx, id, now)
+}
+
+func TestCachedSessionCheckerCachesWithinTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)
+	calls := 0
+	active := true
+	checker := service.NewCachedSessionChecker(fakeSessionActivityStore{
+		isActiveFn: func(_ context.Context, id uint, _ time.Time) (bool, error) {
+			if id != 4 {
+				t.Fatalf("expected session id 4, got %d", id)
+			}
+			calls++
+			return active, nil
+		},
+	}, 5*time.Second)
+
+	for _, at := range []time.Time{now, now.Add(4 * time.Second)} {
+		ok, err := checker.IsSessionActive(ctx, 4, at)
+		if err != nil || !ok {
+			t.Fatalf("expected active session, got %v %v", ok, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one store lookup within ttl, got %d", calls)
+	}
+
+	active = false
+	ok, err := checker.IsSessionActive(ctx, 4, now.Add(5*time.Second))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ok {
+		t.Fatalf("expected revoked session to be seen after ttl")
+	}
+	if calls != 2 {
+		t.Fatalf("expected second store lookup after ttl, got %d", calls)
+	}
+}
+
+func TestCachedSessionCheckerDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 2, 18, 12, 0, 0, 0, time.UTC)
+	fail := true
+	checker := service.NewCachedSessionChecker(fakeSessionActivityStore{
+		isActiveFn: func(_ context.Context, _ uint, _ time.Time) (bool, error) {
+			if fail {
+				return false, errors.New("db down")
+			}
+			return true, nil
+		},
+	}, time.Minute)
+
+	if _, err := checker.IsSessionActive(ctx, 1, now); err == nil {
+		t.Fatalf("expected store error")
+	}
+	fail = false
+	ok, err := checker.IsSessionActive(ctx, 1, now)
+	if err != nil || !ok {
+		t.Fatalf("expected active session after recovery, got %v %v", ok, err)
+	}
+}
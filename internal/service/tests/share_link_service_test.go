@@ -0,0 +1,294 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/domain/meal"
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/sharelink"
+	"goal-bite-api/internal/repository"
+	"goal-bite-api/internal/service"
+)
+
+type fakeShareLinkStore struct {
+	createFn func(ctx context.Context, in repository.ShareLinkCreate) (sharelink.ShareLink, error)
+	revokeFn func(ctx context.Context, userID, id uint, now time.Time) error
+	openFn   func(ctx context.Context, tokenHash string, now time.Time) (sharelink.ShareLink, error)
+	// accesses, when set, collects the ids of the links accessed.
+	accesses *[]uint
+}
+
+func (f fakeShareLinkStore) Create(ctx context.Context, in repository.ShareLinkCreate) (sharelink.ShareLink, error) {
+	if f.createFn != nil {
+		return f.createFn(ctx, in)
+	}
+	return sharelink.ShareLink{ID: 1, UserID: in.UserID, TokenHash: in.TokenHash, RecipeID: in.RecipeID, MealID: in.MealID, ExpiresAt: in.ExpiresAt}, nil
+}
+
+func (f fakeShareLinkStore) ListByUser(_ context.Context, _ uint, _, _ int) ([]sharelink.ShareLink, error) {
+	return nil, nil
+}
+
+func (f fakeShareLinkStore) Revoke(ctx context.Context, userID, id uint, now time.Time) error {
+	if f.revokeFn == nil {
+		return repository.ErrNotFound
+	}
+	return f.revokeFn(ctx, userID, id, now)
+}
+
+func (f fakeShareLinkStore) GetActive(ctx context.Context, tokenHash string, now time.Time) (sharelink.ShareLink, error) {
+	if f.openFn == nil {
+		return sharelink.ShareLink{}, repository.ErrNotFound
+	}
+	return f.openFn(ctx, tokenHash, now)
+}
+
+func (f fakeShareLinkStore) RecordAccess(_ context.Context, id uint, _ time.Time) error {
+	if f.accesses != nil {
+		*f.accesses = append(*f.accesses, id)
+	}
+	return nil
+}
+
+type fakeSharedRecipes struct {
+	getFn       func(ctx context.Context, id uint) (recipe.Recipe, error)
+	breakdownFn func(ctx context.Context, userID, id uint) (recipe.Recipe, service.NutritionBreakdown, error)
+}
+
+func (f fakeSharedRecipes) GetByID(ctx context.Context, id uint) (recipe.Recipe, error) {
+	if f.getFn == nil {
+		return recipe.Recipe{}, service.ErrRecipeNotFound
+	}
+	return f.getFn(ctx, id)
+}
+
+func (f fakeSharedRecipes) Breakdown(ctx context.Context, userID, id uint) (recipe.Recipe, service.NutritionBreakdown, error) {
+	if f.breakdownFn == nil {
+		return recipe.Recipe{}, service.NutritionBreakdown{}, service.ErrRecipeNotFound
+	}
+	return f.breakdownFn(ctx, userID, id)
+}
+
+type fakeSharedMeals struct {
+	getFn       func(ctx context.Context, userID, id uint) (meal.Meal, error)
+	breakdownFn func(ctx context.Context, userID, id uint) (meal.Meal, service.NutritionBreakdown, error)
+}
+
+func (f fakeSharedMeals) GetByID(ctx context.Context, userID, id uint) (meal.Meal, error) {
+	if f.getFn == nil {
+		return meal.Meal{}, service.ErrMealNotFound
+	}
+	return f.getFn(ctx, userID, id)
+}
+
+func (f fakeSharedMeals) Breakdown(ctx context.Context, userID, id uint) (meal.Meal, service.NutritionBreakdown, error) {
+	if f.breakdownFn == nil {
+		return meal.Meal{}, service.NutritionBreakdown{}, service.ErrMealNotFound
+	}
+	return f.breakdownFn(ctx, userID, id)
+}
+
+func TestShareLinkServiceShareRecipe(t *testing.T) {
+	archivedAt := time.Now()
+	recipes := fakeSharedRecipes{getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+		switch id {
+		case 1:
+			return recipe.Recipe{ID: 1, UserID: 7}, nil
+		case 2:
+			return recipe.Recipe{ID: 2, UserID: 8}, nil
+		case 3:
+			return recipe.Recipe{ID: 3, UserID: 7, ArchivedAt: &archivedAt}, nil
+		}
+		return recipe.Recipe{}, service.ErrRecipeNotFound
+	}}
+	var stored repository.ShareLinkCreate
+	store := fakeShareLinkStore{createFn: func(_ context.Context, in repository.ShareLinkCreate) (sharelink.ShareLink, error) {
+		stored = in
+		return sharelink.ShareLink{ID: 4, UserID: in.UserID, TokenHash: in.TokenHash, RecipeID: in.RecipeID, ExpiresAt: in.ExpiresAt}, nil
+	}}
+	svc := service.NewShareLinkService(store, recipes, fakeSharedMeals{})
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	value, err := svc.ShareRecipe(context.Background(), 7, 1, service.CreateShareLinkInput{ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("share recipe: %v", err)
+	}
+	if len(value.Token) < 40 || stored.TokenHash == "" || strings.Contains(stored.TokenHash, value.Token) {
+		t.Fatalf("expected a long token stored only as a hash, got token %q hash %q", value.Token, stored.TokenHash)
+	}
+	if stored.RecipeID == nil || *stored.RecipeID != 1 || stored.MealID != nil || stored.ExpiresAt != &expiresAt {
+		t.Fatalf("expected a link to recipe 1 with the expiry, got %+v", stored)
+	}
+	other, err := svc.ShareRecipe(context.Background(), 7, 1, service.CreateShareLinkInput{})
+	if err != nil || other.Token == value.Token {
+		t.Fatalf("expected a fresh token per link, got %q and %v", other.Token, err)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	cases := []struct {
+		name     string
+		recipeID uint
+		in       service.CreateShareLinkInput
+		want     error
+	}{
+		{"expiry in the past", 1, service.CreateShareLinkInput{ExpiresAt: &past}, service.ErrInvalidShareLinkExpiry},
+		{"other user's recipe", 2, service.CreateShareLinkInput{}, service.ErrRecipeForbidden},
+		{"archived recipe", 3, service.CreateShareLinkInput{}, service.ErrRecipeArchived},
+		{"unknown recipe", 9, service.CreateShareLinkInput{}, service.ErrRecipeNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := svc.ShareRecipe(context.Background(), 7, tc.recipeID, tc.in); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestShareLinkServiceShareMealChecksOwner(t *testing.T) {
+	meals := fakeSharedMeals{getFn: func(_ context.Context, userID, id uint) (meal.Meal, error) {
+		if userID != 7 || id != 2 {
+			return meal.Meal{}, service.ErrMealNotFound
+		}
+		return meal.Meal{ID: 2, UserID: 7}, nil
+	}}
+	svc := service.NewShareLinkService(fakeShareLinkStore{}, fakeSharedRecipes{}, meals)
+
+	value, err := svc.ShareMeal(context.Background(), 7, 2, service.CreateShareLinkInput{})
+	if err != nil || value.MealID == nil || *value.MealID != 2 || value.Kind() != sharelink.KindMeal {
+		t.Fatalf("expected a link to meal 2, got %+v, %v", value, err)
+	}
+	if _, err := svc.ShareMeal(context.Background(), 8, 2, service.CreateShareLinkInput{}); !errors.Is(err, service.ErrMealNotFound) {
+		t.Fatalf("expected ErrMealNotFound for another user's meal, got %v", err)
+	}
+}
+
+func TestShareLinkServiceOpenRecipe(t *testing.T) {
+	recipeID := uint(1)
+	var hash string
+	var accesses []uint
+	store := fakeShareLinkStore{
+		accesses: &accesses,
+		createFn: func(_ context.Context, in repository.ShareLinkCreate) (sharelink.ShareLink, error) {
+			hash = in.TokenHash
+			return sharelink.ShareLink{ID: 1, UserID: in.UserID, RecipeID: in.RecipeID}, nil
+		},
+		openFn: func(_ context.Context, tokenHash string, _ time.Time) (sharelink.ShareLink, error) {
+			if tokenHash != hash {
+				return sharelink.ShareLink{}, repository.ErrNotFound
+			}
+			return sharelink.ShareLink{ID: 1, UserID: 7, RecipeID: &recipeID}, nil
+		},
+	}
+	var readAs uint
+	imageKey, imageURL := "recipes/5f2c9a.jpg", "https://cdn.test/recipes/5f2c9a.jpg"
+	recipes := fakeSharedRecipes{
+		getFn: func(_ context.Context, _ uint) (recipe.Recipe, error) {
+			return recipe.Recipe{ID: 1, UserID: 7}, nil
+		},
+		breakdownFn: func(_ context.Context, userID, _ uint) (recipe.Recipe, service.NutritionBreakdown, error) {
+			readAs = userID
+			foodID := uint(4)
+			return recipe.Recipe{ID: 1, UserID: 7, Name: "Rice bowl", Servings: 2, ImageKey: &imageKey, ImageURL: &imageURL},
+				service.NutritionBreakdown{Items: []service.BreakdownItem{{ID: 3, FoodID: &foodID, Name: "Rice"}}, Total: service.NutrientAmounts{Kcal: 520}}, nil
+		},
+	}
+	svc := service.NewShareLinkService(store, recipes, fakeSharedMeals{})
+	link, err := svc.ShareRecipe(context.Background(), 7, 1, service.CreateShareLinkInput{})
+	if err != nil {
+		t.Fatalf("share recipe: %v", err)
+	}
+
+	view, err := svc.Open(context.Background(), link.Token)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if view.Kind != sharelink.KindRecipe || view.Recipe == nil || view.Recipe.Name != "Rice bowl" || view.Nutrition.Total.Kcal != 520 {
+		t.Fatalf("expected the recipe with its nutrition, got %+v", view)
+	}
+	if readAs != 7 {
+		t.Fatalf("expected the breakdown read as the owner, got user %d", readAs)
+	}
+	body, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(body), `"id"`) || strings.Contains(string(body), "_id") {
+		t.Fatalf("expected no IDs in the view, got %s", body)
+	}
+	if view.Recipe.ImageURL == nil || *view.Recipe.ImageURL != imageURL || len(view.Nutrition.Items) != 1 || view.Nutrition.Items[0].Name != "Rice" {
+		t.Fatalf("expected the image and the named ingredient, got %s", body)
+	}
+
+	// Images stored before keys dropped the recipe ID would reveal it.
+	imageKey, imageURL = "recipes/1/5f2c9a.jpg", "https://cdn.test/recipes/1/5f2c9a.jpg"
+	view, err = svc.Open(context.Background(), link.Token)
+	if err != nil || view.Recipe.ImageURL != nil || view.Recipe.ThumbnailURL != nil {
+		t.Fatalf("expected a legacy image to be left out, got %+v, %v", view.Recipe, err)
+	}
+
+	if len(accesses) != 2 || accesses[0] != 1 {
+		t.Fatalf("expected two accesses to link 1, got %v", accesses)
+	}
+
+	for _, token := range []string{"", "  ", "not-a-token"} {
+		if _, err := svc.Open(context.Background(), token); !errors.Is(err, service.ErrShareLinkNotFound) {
+			t.Fatalf("token %q: expected ErrShareLinkNotFound, got %v", token, err)
+		}
+	}
+}
+
+func TestShareLinkServiceOpenDeletedRecipe(t *testing.T) {
+	recipeID := uint(1)
+	var accesses []uint
+	store := fakeShareLinkStore{accesses: &accesses, openFn: func(_ context.Context, _ string, _ time.Time) (sharelink.ShareLink, error) {
+		return sharelink.ShareLink{ID: 1, UserID: 7, RecipeID: &recipeID}, nil
+	}}
+	archivedAt := time.Now()
+	recipes := fakeSharedRecipes{breakdownFn: func(_ context.Context, _, _ uint) (recipe.Recipe, service.NutritionBreakdown, error) {
+		return recipe.Recipe{ID: 1, UserID: 7, Name: "Rice bowl", ArchivedAt: &archivedAt}, service.NutritionBreakdown{}, nil
+	}}
+	svc := service.NewShareLinkService(store, recipes, fakeSharedMeals{})
+
+	if _, err := svc.Open(context.Background(), "token"); !errors.Is(err, service.ErrShareLinkNotFound) {
+		t.Fatalf("expected ErrShareLinkNotFound, got %v", err)
+	}
+	if len(accesses) != 0 {
+		t.Fatalf("expected no access counted, got %v", accesses)
+	}
+}
+
+func TestShareLinkServiceOpenMissingMeal(t *testing.T) {
+	mealID := uint(2)
+	var accesses []uint
+	store := fakeShareLinkStore{accesses: &accesses, openFn: func(_ context.Context, _ string, _ time.Time) (sharelink.ShareLink, error) {
+		return sharelink.ShareLink{ID: 1, UserID: 7, MealID: &mealID}, nil
+	}}
+	svc := service.NewShareLinkService(store, fakeSharedRecipes{}, fakeSharedMeals{})
+
+	if _, err := svc.Open(context.Background(), "token"); !errors.Is(err, service.ErrShareLinkNotFound) {
+		t.Fatalf("expected ErrShareLinkNotFound, got %v", err)
+	}
+	if len(accesses) != 0 {
+		t.Fatalf("expected no access counted, got %v", accesses)
+	}
+}
+
+func TestShareLinkServiceRevoke(t *testing.T) {
+	svc := service.NewShareLinkService(fakeShareLinkStore{revokeFn: func(_ context.Context, userID, id uint, _ time.Time) error {
+		if userID != 7 || id != 3 {
+			return repository.ErrNotFound
+		}
+		return nil
+	}}, fakeSharedRecipes{}, fakeSharedMeals{})
+
+	if err := svc.Revoke(context.Background(), 7, 3); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := svc.Revoke(context.Background(), 8, 3); !errors.Is(err, service.ErrShareLinkNotFound) {
+		t.Fatalf("expected ErrShareLinkNotFound, got %v", err)
+	}
+}
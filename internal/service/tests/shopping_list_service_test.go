@@ -0,0 +1,218 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/domain/food"
+	"goal-bite-api/internal/domain/meal"
+	"goal-bite-api/internal/domain/mealitem"
+	"goal-bite-api/internal/domain/recipe"
+	"goal-bite-api/internal/domain/recipeingredient"
+	"goal-bite-api/internal/domain/shoppinglist"
+	"goal-bite-api/internal/repository"
+	"goal-bite-api/internal/service"
+)
+
+type fakeShoppingListStore struct {
+	createFn  func(ctx context.Context, in repository.ShoppingListCreate) (shoppinglist.ShoppingList, error)
+	getFn     func(ctx context.Context, userID, id uint) (shoppinglist.ShoppingList, error)
+	checkedFn func(ctx context.Context, userID, listID, itemID uint, checked bool) (shoppinglist.Item, error)
+	deleteFn  func(ctx context.Context, userID, id uint) error
+}
+
+func (f fakeShoppingListStore) Create(ctx context.Context, in repository.ShoppingListCreate) (shoppinglist.ShoppingList, error) {
+	if f.createFn != nil {
+		return f.createFn(ctx, in)
+	}
+	out := shoppinglist.ShoppingList{ID: 1, UserID: in.UserID, Name: in.Name}
+	for i, item := range in.Items {
+		out.Items = append(out.Items, shoppinglist.Item{ID: uint(i + 1), FoodID: item.FoodID, WeightG: item.WeightG})
+	}
+	return out, nil
+}
+
+func (f fakeShoppingListStore) GetForUser(ctx context.Context, userID, id uint) (shoppinglist.ShoppingList, error) {
+	if f.getFn == nil {
+		return shoppinglist.ShoppingList{}, repository.ErrNotFound
+	}
+	return f.getFn(ctx, userID, id)
+}
+
+func (f fakeShoppingListStore) ListByUser(_ context.Context, _ uint, _, _ int) ([]shoppinglist.ShoppingList, error) {
+	return nil, nil
+}
+
+func (f fakeShoppingListStore) SetItemChecked(ctx context.Context, userID, listID, itemID uint, checked bool) (shoppinglist.Item, error) {
+	if f.checkedFn == nil {
+		return shoppinglist.Item{}, repository.ErrNotFound
+	}
+	return f.checkedFn(ctx, userID, listID, itemID, checked)
+}
+
+func (f fakeShoppingListStore) DeleteForUser(ctx context.Context, userID, id uint) error {
+	if f.deleteFn == nil {
+		return repository.ErrNotFound
+	}
+	return f.deleteFn(ctx, userID, id)
+}
+
+type fakePlannedMeals func(ctx context.Context, userID uint, from, to time.Time) ([]meal.Meal, error)
+
+func (f fakePlannedMeals) ListByUserBetween(ctx context.Context, userID uint, from, to time.Time) ([]meal.Meal, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return f(ctx, userID, from, to)
+}
+
+// shoppingRecipes serves a dinner of 2 servings using half of a 400 g sauce
+// batch, and a 50 g single-serving dressing.
+func shoppingRecipes() fakeRecipeReader {
+	pasta, tomato := uint(10), uint(11)
+	sauce := uint(2)
+	recipes := map[uint]recipe.Recipe{
+		1: {ID: 1, Servings: 2, YieldWeightG: 500, Ingredients: []recipeingredient.RecipeIngredient{
+			{FoodID: &pasta, RawWeightG: 100},
+			{SubRecipeID: &sauce, RawWeightG: 200},
+		}},
+		2: {ID: 2, Servings: 4, YieldWeightG: 400, Ingredients: []recipeingredient.RecipeIngredient{
+			{FoodID: &pasta, RawWeightG: 100},
+			{FoodID: &tomato, RawWeightG: 300},
+		}},
+		3: {ID: 3, Servings: 1, YieldWeightG: 50, Ingredients: []recipeingredient.RecipeIngredient{
+			{FoodID: &tomato, RawWeightG: 50},
+		}},
+	}
+	return fakeRecipeReader{getFn: func(_ context.Context, id uint) (recipe.Recipe, error) {
+		r, ok := recipes[id]
+		if !ok {
+			return recipe.Recipe{}, repository.ErrNotFound
+		}
+		return r, nil
+	}}
+}
+
+func shoppingFoods() fakeFoodReader {
+	return fakeFoodReader{byIDsFn: func(_ context.Context, ids []uint) ([]food.Food, error) {
+		return []food.Food{
+			{ID: 10, Name: "Spaghetti", Visibility: food.VisibilityPublic},
+			{ID: 11, Name: "Secret tomato", UserID: 9, Visibility: food.VisibilityPrivate},
+		}, nil
+	}}
+}
+
+func weightsByFood(items []shoppinglist.Item) map[uint]float64 {
+	out := map[uint]float64{}
+	for _, item := range items {
+		out[item.FoodID] = item.WeightG
+	}
+	return out
+}
+
+func TestShoppingListServiceMergesRecipes(t *testing.T) {
+	svc := service.NewShoppingListService(fakeShoppingListStore{}, shoppingRecipes(), shoppingFoods(), nil)
+
+	value, err := svc.Create(context.Background(), 1, service.CreateShoppingListInput{
+		Recipes: []service.ShoppingListRecipeInput{{RecipeID: 1, Servings: 4}, {RecipeID: 3}},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if value.Name != shoppinglist.DefaultName || len(value.Items) != 2 {
+		t.Fatalf("expected a default-named list of two foods, got %+v", value)
+	}
+	// Twice the dinner: 200 g pasta, plus half the sauce batch twice over.
+	weights := weightsByFood(value.Items)
+	if math.Abs(weights[10]-300) > 1e-9 || math.Abs(weights[11]-350) > 1e-9 {
+		t.Fatalf("expected 300 g pasta and 350 g tomato, got %v", weights)
+	}
+	if value.Items[0].FoodID != 10 || value.Items[0].Name != "Spaghetti" || value.Items[1].Name != "" {
+		t.Fatalf("expected names only for visible foods in first-seen order, got %+v", value.Items)
+	}
+}
+
+func TestShoppingListServiceFromPlannedMeals(t *testing.T) {
+	pasta, dressing := uint(10), uint(3)
+	var gotFrom, gotTo time.Time
+	meals := fakePlannedMeals(func(_ context.Context, _ uint, from, to time.Time) ([]meal.Meal, error) {
+		gotFrom, gotTo = from, to
+		return []meal.Meal{
+			{Items: []mealitem.MealItem{{FoodID: &pasta, WeightG: 150}}},
+			{Items: []mealitem.MealItem{{RecipeID: &dressing, WeightG: 25}, {FoodID: &pasta, WeightG: 50}}},
+		}, nil
+	})
+	svc := service.NewShoppingListService(fakeShoppingListStore{}, shoppingRecipes(), shoppingFoods(), meals)
+
+	value, err := svc.Create(context.Background(), 1, service.CreateShoppingListInput{Name: "Week", From: "2026-02-16", To: "2026-02-22"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !gotFrom.Equal(time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)) || !gotTo.Equal(time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the whole last day included, got %v to %v", gotFrom, gotTo)
+	}
+	weights := weightsByFood(value.Items)
+	if weights[10] != 200 || weights[11] != 25 {
+		t.Fatalf("expected 200 g pasta and half the dressing's tomato, got %v", weights)
+	}
+}
+
+func TestShoppingListServiceCreateValidation(t *testing.T) {
+	archivedAt := time.Now()
+	reader := shoppingRecipes()
+	get := reader.getFn
+	reader.getFn = func(ctx context.Context, id uint) (recipe.Recipe, error) {
+		if id == 4 {
+			return recipe.Recipe{ID: 4, Servings: 1, YieldWeightG: 100, ArchivedAt: &archivedAt}, nil
+		}
+		return get(ctx, id)
+	}
+	svc := service.NewShoppingListService(fakeShoppingListStore{}, reader, shoppingFoods(), fakePlannedMeals(nil))
+
+	cases := []struct {
+		name string
+		in   service.CreateShoppingListInput
+		want error
+	}{
+		{"no source", service.CreateShoppingListInput{}, service.ErrInvalidShoppingListSource},
+		{"both sources", service.CreateShoppingListInput{Recipes: []service.ShoppingListRecipeInput{{RecipeID: 1}}, From: "2026-02-16", To: "2026-02-17"}, service.ErrInvalidShoppingListSource},
+		{"negative servings", service.CreateShoppingListInput{Recipes: []service.ShoppingListRecipeInput{{RecipeID: 1, Servings: -1}}}, service.ErrInvalidShoppingListSource},
+		{"range too long", service.CreateShoppingListInput{From: "2026-01-01", To: "2026-02-01"}, service.ErrInvalidDateRange},
+		{"range reversed", service.CreateShoppingListInput{From: "2026-02-17", To: "2026-02-16"}, service.ErrInvalidDateRange},
+		{"unknown recipe", service.CreateShoppingListInput{Recipes: []service.ShoppingListRecipeInput{{RecipeID: 99}}}, service.ErrRecipeSourceNotFound},
+		{"archived recipe", service.CreateShoppingListInput{Recipes: []service.ShoppingListRecipeInput{{RecipeID: 4}}}, service.ErrRecipeArchived},
+	}
+	for _, tc := range cases {
+		if _, err := svc.Create(context.Background(), 1, tc.in); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestShoppingListServiceNotFound(t *testing.T) {
+	svc := service.NewShoppingListService(fakeShoppingListStore{}, fakeRecipeReader{}, fakeFoodReader{}, nil)
+
+	if _, err := svc.GetByID(context.Background(), 1, 5); !errors.Is(err, service.ErrShoppingListNotFound) {
+		t.Fatalf("expected ErrShoppingListNotFound, got %v", err)
+	}
+	if _, err := svc.SetItemChecked(context.Background(), 1, 5, 6, true); !errors.Is(err, service.ErrShoppingListItemNotFound) {
+		t.Fatalf("expected ErrShoppingListItemNotFound, got %v", err)
+	}
+	if err := svc.Delete(context.Background(), 1, 5); !errors.Is(err, service.ErrShoppingListNotFound) {
+		t.Fatalf("expected ErrShoppingListNotFound, got %v", err)
+	}
+}
+
+func TestShoppingListText(t *testing.T) {
+	value := shoppinglist.ShoppingList{Name: "Week", Items: []shoppinglist.Item{
+		{FoodID: 10, Name: "Spaghetti", DisplayName: "Špageti", WeightG: 333.333, Checked: true},
+		{FoodID: 11, WeightG: 25},
+	}}
+	want := "Week\n\n[x] Špageti — 333.3 g\n[ ] Food #11 — 25 g\n"
+	if got := value.Text(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -92,4 +92,57 @@ func TestUserServiceUpdate(t *testing.T) {
 			t.Fatalf("expected no error, got %v", err)
 		}
 	})
+
+	t.Run("normalizes dietary restrictions", func(t *testing.T) {
+		svc := service.NewUserService(fakeUserReader{
+			updateFn: func(_ context.Context, _ uint, updates repository.UserUpdate) (user.User, error) {
+				if updates.AvoidAllergens == nil || len(*updates.AvoidAllergens) != 2 || (*updates.AvoidAllergens)[0] != "nuts" || (*updates.AvoidAllergens)[1] != "peanuts" {
+					t.Fatalf("unexpected allergens %v", updates.AvoidAllergens)
+				}
+				if updates.RequiredDietFlags == nil || len(*updates.RequiredDietFlags) != 0 {
+					t.Fatalf("expected diet flags to be cleared, got %v", updates.RequiredDietFlags)
+				}
+				return user.User{ID: 1}, nil
+			},
+		})
+		allergens := []string{"Peanuts", "nuts"}
+		flags := []string{}
+		_, err := svc.Update(context.Background(), 1, service.UpdateUserInput{AvoidAllergens: &allergens, RequiredDietFlags: &flags})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("normalizes languages", func(t *testing.T) {
+		svc := service.NewUserService(fakeUserReader{
+			updateFn: func(_ context.Context, _ uint, updates repository.UserUpdate) (user.User, error) {
+				if updates.Languages == nil || len(*updates.Languages) != 2 || (*updates.Languages)[0] != "de" || (*updates.Languages)[1] != "en" {
+					t.Fatalf("unexpected languages %v", updates.Languages)
+				}
+				return user.User{ID: 1}, nil
+			},
+		})
+		languages := []string{"de-AT", "EN", "de"}
+		if _, err := svc.Update(context.Background(), 1, service.UpdateUserInput{Languages: &languages}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid language", func(t *testing.T) {
+		svc := service.NewUserService(fakeUserReader{})
+		languages := []string{"german"}
+		_, err := svc.Update(context.Background(), 1, service.UpdateUserInput{Languages: &languages})
+		if !errors.Is(err, service.ErrInvalidUserProfile) {
+			t.Fatalf("expected ErrInvalidUserProfile, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown diet flag", func(t *testing.T) {
+		svc := service.NewUserService(fakeUserReader{})
+		flags := []string{"keto"}
+		_, err := svc.Update(context.Background(), 1, service.UpdateUserInput{RequiredDietFlags: &flags})
+		if !errors.Is(err, service.ErrInvalidUserProfile) {
+			t.Fatalf("expected ErrInvalidUserProfile, got %v", err)
+		}
+	})
 }
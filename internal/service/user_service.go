@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"goal-bite-api/internal/domain/dietary"
+	"goal-bite-api/internal/domain/locale"
 	"goal-bite-api/internal/domain/user"
 	"goal-bite-api/internal/repository"
 )
@@ -49,10 +51,17 @@ type UpdateUserInput struct {
 	HeightCM         *float64
 	ActivityLevelSet bool
 	ActivityLevel    *string
+	// AvoidAllergens and RequiredDietFlags replace the stored restrictions;
+	// an empty slice clears them.
+	AvoidAllergens    *[]string
+	RequiredDietFlags *[]string
+	// Languages replaces the languages names are shown in, most preferred
+	// first.
+	Languages *[]string
 }
 
 func (s *UserService) Update(ctx context.Context, id uint, in UpdateUserInput) (user.User, error) {
-	if in.Name == nil && !in.SexSet && !in.BirthDateSet && !in.HeightCMSet && !in.ActivityLevelSet {
+	if in.Name == nil && !in.SexSet && !in.BirthDateSet && !in.HeightCMSet && !in.ActivityLevelSet && in.AvoidAllergens == nil && in.RequiredDietFlags == nil && in.Languages == nil {
 		return user.User{}, ErrNoFieldsToUpdate
 	}
 
@@ -87,6 +96,27 @@ func (s *UserService) Update(ctx context.Context, id uint, in UpdateUserInput) (
 			return user.User{}, ErrInvalidUserProfile
 		}
 	}
+	if in.AvoidAllergens != nil {
+		allergens, ok := dietary.NormalizeAllergens(*in.AvoidAllergens)
+		if !ok {
+			return user.User{}, ErrInvalidUserProfile
+		}
+		updates.AvoidAllergens = &allergens
+	}
+	if in.RequiredDietFlags != nil {
+		flags, ok := dietary.NormalizeDietFlags(*in.RequiredDietFlags)
+		if !ok {
+			return user.User{}, ErrInvalidUserProfile
+		}
+		updates.RequiredDietFlags = &flags
+	}
+	if in.Languages != nil {
+		languages, ok := locale.NormalizeLanguages(*in.Languages)
+		if !ok {
+			return user.User{}, ErrInvalidUserProfile
+		}
+		updates.Languages = &languages
+	}
 	updates.SexSet = in.SexSet
 	updates.Sex = in.Sex
 	updates.BirthDateSet = in.BirthDateSet
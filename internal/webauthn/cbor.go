@@ -0,0 +1,183 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// The decoder below covers the CBOR subset authenticators emit for
+// attestation objects and COSE keys (RFC 8949, definite lengths only).
+
+var errMalformedCBOR = errors.New("malformed cbor")
+
+const maxCBORDepth = 16
+
+// decodeCBOR decodes the first CBOR item in data and returns it together with
+// the number of bytes consumed. Integers decode as int64, byte strings as
+// []byte, text as string, arrays as []any and maps as map[any]any.
+func decodeCBOR(data []byte) (any, int, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (any, int, error) {
+	if depth > maxCBORDepth || len(data) == 0 {
+		return nil, 0, errMalformedCBOR
+	}
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+
+	if major == 7 {
+		return decodeCBORSimple(data, info)
+	}
+
+	arg, n, err := decodeCBORArgument(data, info)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, 0, errMalformedCBOR
+		}
+		return int64(arg), n, nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, 0, errMalformedCBOR
+		}
+		return -1 - int64(arg), n, nil
+	case 2, 3:
+		if arg > uint64(len(data)-n) {
+			return nil, 0, errMalformedCBOR
+		}
+		end := n + int(arg)
+		if major == 2 {
+			out := make([]byte, arg)
+			copy(out, data[n:end])
+			return out, end, nil
+		}
+		return string(data[n:end]), end, nil
+	case 4:
+		if arg > uint64(len(data)) {
+			return nil, 0, errMalformedCBOR
+		}
+		out := make([]any, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, used, err := decodeCBORItem(data[n:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			out = append(out, item)
+			n += used
+		}
+		return out, n, nil
+	case 5:
+		if arg > uint64(len(data)) {
+			return nil, 0, errMalformedCBOR
+		}
+		out := make(map[any]any, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, used, err := decodeCBORItem(data[n:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			n += used
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, 0, errMalformedCBOR
+			}
+			value, used, err := decodeCBORItem(data[n:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			n += used
+			out[key] = value
+		}
+		return out, n, nil
+	case 6:
+		// Tags carry no meaning for WebAuthn structures; return the tagged item.
+		item, used, err := decodeCBORItem(data[n:], depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		return item, n + used, nil
+	}
+	return nil, 0, errMalformedCBOR
+}
+
+func decodeCBORArgument(data []byte, info byte) (uint64, int, error) {
+	switch {
+	case info < 24:
+		return uint64(info), 1, nil
+	case info == 24:
+		if len(data) < 2 {
+			return 0, 0, errMalformedCBOR
+		}
+		return uint64(data[1]), 2, nil
+	case info == 25:
+		if len(data) < 3 {
+			return 0, 0, errMalformedCBOR
+		}
+		return uint64(binary.BigEndian.Uint16(data[1:3])), 3, nil
+	case info == 26:
+		if len(data) < 5 {
+			return 0, 0, errMalformedCBOR
+		}
+		return uint64(binary.BigEndian.Uint32(data[1:5])), 5, nil
+	case info == 27:
+		if len(data) < 9 {
+			return 0, 0, errMalformedCBOR
+		}
+		return binary.BigEndian.Uint64(data[1:9]), 9, nil
+	}
+	// 28-30 are reserved and 31 marks indefinite length, which is not used here.
+	return 0, 0, errMalformedCBOR
+}
+
+func decodeCBORSimple(data []byte, info byte) (any, int, error) {
+	switch info {
+	case 20:
+		return false, 1, nil
+	case 21:
+		return true, 1, nil
+	case 22, 23:
+		return nil, 1, nil
+	case 25:
+		if len(data) < 3 {
+			return nil, 0, errMalformedCBOR
+		}
+		return halfToFloat64(binary.BigEndian.Uint16(data[1:3])), 3, nil
+	case 26:
+		if len(data) < 5 {
+			return nil, 0, errMalformedCBOR
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data[1:5]))), 5, nil
+	case 27:
+		if len(data) < 9 {
+			return nil, 0, errMalformedCBOR
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data[1:9])), 9, nil
+	}
+	return nil, 0, errMalformedCBOR
+}
+
+func halfToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	frac := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 31:
+		if frac == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	}
+	return sign * math.Ldexp(frac+1024, exp-25)
+}
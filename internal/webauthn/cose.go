@@ -0,0 +1,124 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// COSE algorithm identifiers accepted for passkeys.
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms lists the COSE algorithms offered in creation options,
+// in order of preference.
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+const (
+	coseKeyType   int64 = 1
+	coseAlgorithm int64 = 3
+	coseCurveOrN  int64 = -1
+	coseXOrE      int64 = -2
+	coseY         int64 = -3
+
+	coseKeyTypeOKP int64 = 1
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+
+	coseCurveP256    int64 = 1
+	coseCurveEd25519 int64 = 6
+)
+
+var ErrUnsupportedKey = errors.New("unsupported credential public key")
+
+// publicKey verifies signatures made with a credential's private key.
+type publicKey interface {
+	verify(message, signature []byte) bool
+}
+
+type ecdsaKey struct{ key *ecdsa.PublicKey }
+
+func (k ecdsaKey) verify(message, signature []byte) bool {
+	digest := sha256.Sum256(message)
+	return ecdsa.VerifyASN1(k.key, digest[:], signature)
+}
+
+type ed25519Key struct{ key ed25519.PublicKey }
+
+func (k ed25519Key) verify(message, signature []byte) bool {
+	return ed25519.Verify(k.key, message, signature)
+}
+
+type rsaKey struct{ key *rsa.PublicKey }
+
+func (k rsaKey) verify(message, signature []byte) bool {
+	digest := sha256.Sum256(message)
+	return rsa.VerifyPKCS1v15(k.key, crypto.SHA256, digest[:], signature) == nil
+}
+
+// parseCOSEKey decodes a COSE_Key (RFC 9053) into a verifier.
+func parseCOSEKey(raw []byte) (publicKey, int64, error) {
+	decoded, n, err := decodeCBOR(raw)
+	if err != nil {
+		return nil, 0, ErrUnsupportedKey
+	}
+	if n != len(raw) {
+		return nil, 0, ErrUnsupportedKey
+	}
+	m, ok := decoded.(map[any]any)
+	if !ok {
+		return nil, 0, ErrUnsupportedKey
+	}
+	kty, _ := m[coseKeyType].(int64)
+	alg, _ := m[coseAlgorithm].(int64)
+
+	switch {
+	case kty == coseKeyTypeEC2 && alg == AlgES256:
+		crv, _ := m[coseCurveOrN].(int64)
+		x, _ := m[coseXOrE].([]byte)
+		y, _ := m[coseY].([]byte)
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, ErrUnsupportedKey
+		}
+		uncompressed := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(uncompressed); err != nil {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return ecdsaKey{key: &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}}, alg, nil
+	case kty == coseKeyTypeOKP && alg == AlgEdDSA:
+		crv, _ := m[coseCurveOrN].(int64)
+		x, _ := m[coseXOrE].([]byte)
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return ed25519Key{key: ed25519.PublicKey(x)}, alg, nil
+	case kty == coseKeyTypeRSA && alg == AlgRS256:
+		nBytes, _ := m[coseCurveOrN].([]byte)
+		eBytes, _ := m[coseXOrE].([]byte)
+		if len(nBytes) < 256 || len(eBytes) == 0 || len(eBytes) > 4 {
+			return nil, 0, ErrUnsupportedKey
+		}
+		e := new(big.Int).SetBytes(eBytes)
+		if e.Int64() < 3 {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return rsaKey{key: &rsa.PublicKey{
+			N: new(big.Int).SetBytes(nBytes),
+			E: int(e.Int64()),
+		}}, alg, nil
+	}
+	return nil, 0, ErrUnsupportedKey
+}
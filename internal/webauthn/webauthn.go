@@ -0,0 +1,397 @@
+// Package webauthn implements the relying-party side of WebAuthn passkey
+// registration and authentication (W3C Web Authentication Level 2) using only
+// the standard library. Attestation statements are not verified: options ask
+// for "none" conveyance, which is what consumer passkey providers return.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidResponse     = errors.New("invalid webauthn response")
+	ErrChallengeMismatch   = errors.New("webauthn challenge mismatch")
+	ErrOriginMismatch      = errors.New("webauthn origin not allowed")
+	ErrRPIDMismatch        = errors.New("webauthn rp id mismatch")
+	ErrUserNotPresent      = errors.New("webauthn user presence missing")
+	ErrUserNotVerified     = errors.New("webauthn user verification missing")
+	ErrInvalidSignature    = errors.New("webauthn signature invalid")
+	ErrSignCountRegression = errors.New("webauthn sign count did not increase")
+)
+
+const (
+	flagUserPresent      byte = 0x01
+	flagUserVerified     byte = 0x04
+	flagAttestedCredData byte = 0x40
+
+	challengeSize      = 32
+	maxCredentialIDLen = 1023
+)
+
+type Config struct {
+	RPID    string
+	RPName  string
+	Origins []string
+	Timeout time.Duration
+	// RequireUserVerification rejects ceremonies where the authenticator did
+	// not verify the user (PIN, biometrics).
+	RequireUserVerification bool
+}
+
+type RelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserEntity struct {
+	ID          string `json:"id" example:"AAAAAAAAAAc"`
+	Name        string `json:"name" example:"john@gmail.com"`
+	DisplayName string `json:"displayName" example:"John Doe"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type" example:"public-key"`
+	Alg  int64  `json:"alg" example:"-7"`
+}
+
+type CredentialDescriptor struct {
+	Type       string   `json:"type" example:"public-key"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey" example:"required"`
+	UserVerification string `json:"userVerification" example:"preferred"`
+}
+
+// CreationOptions is the JSON form of PublicKeyCredentialCreationOptions;
+// binary fields are base64url encoded without padding.
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     RelyingParty           `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout" example:"300000"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation" example:"none"`
+}
+
+// RequestOptions is the JSON form of PublicKeyCredentialRequestOptions.
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout" example:"300000"`
+	RPID             string                 `json:"rpId" example:"localhost"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification" example:"preferred"`
+}
+
+// RegistrationResponse is PublicKeyCredential.toJSON() for a create() call.
+type RegistrationResponse struct {
+	ID                      string              `json:"id"`
+	RawID                   string              `json:"rawId"`
+	Type                    string              `json:"type" example:"public-key"`
+	AuthenticatorAttachment string              `json:"authenticatorAttachment,omitempty"`
+	ClientExtensionResults  map[string]any      `json:"clientExtensionResults,omitempty"`
+	Response                AttestationResponse `json:"response"`
+}
+
+type AttestationResponse struct {
+	ClientDataJSON     string   `json:"clientDataJSON"`
+	AttestationObject  string   `json:"attestationObject"`
+	Transports         []string `json:"transports,omitempty"`
+	AuthenticatorData  string   `json:"authenticatorData,omitempty"`
+	PublicKey          string   `json:"publicKey,omitempty"`
+	PublicKeyAlgorithm int64    `json:"publicKeyAlgorithm,omitempty"`
+}
+
+// AssertionResponse is PublicKeyCredential.toJSON() for a get() call.
+type AssertionResponse struct {
+	ID                      string            `json:"id"`
+	RawID                   string            `json:"rawId"`
+	Type                    string            `json:"type" example:"public-key"`
+	AuthenticatorAttachment string            `json:"authenticatorAttachment,omitempty"`
+	ClientExtensionResults  map[string]any    `json:"clientExtensionResults,omitempty"`
+	Response                AssertionEvidence `json:"response"`
+}
+
+type AssertionEvidence struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// Credential is what a relying party stores after a successful registration.
+type Credential struct {
+	ID         []byte
+	PublicKey  []byte
+	SignCount  uint32
+	Transports []string
+}
+
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+func NewChallenge() ([]byte, error) {
+	buf := make([]byte, challengeSize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func (c Config) CreationOptions(challenge []byte, user UserEntity, exclude []CredentialDescriptor) CreationOptions {
+	params := make([]CredentialParameter, 0, len(SupportedAlgorithms))
+	for _, alg := range SupportedAlgorithms {
+		params = append(params, CredentialParameter{Type: "public-key", Alg: alg})
+	}
+	if exclude == nil {
+		exclude = []CredentialDescriptor{}
+	}
+	return CreationOptions{
+		Challenge:          EncodeBase64URL(challenge),
+		RP:                 RelyingParty{ID: c.RPID, Name: c.RPName},
+		User:               user,
+		PubKeyCredParams:   params,
+		Timeout:            c.Timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "required",
+			UserVerification: c.userVerification(),
+		},
+		Attestation: "none",
+	}
+}
+
+// RequestOptions builds options for a discoverable-credential login, so the
+// caller does not have to name an account before the ceremony.
+func (c Config) RequestOptions(challenge []byte) RequestOptions {
+	return RequestOptions{
+		Challenge:        EncodeBase64URL(challenge),
+		Timeout:          c.Timeout.Milliseconds(),
+		RPID:             c.RPID,
+		AllowCredentials: []CredentialDescriptor{},
+		UserVerification: c.userVerification(),
+	}
+}
+
+// ParseClientData decodes the base64url clientDataJSON sent by the browser.
+func ParseClientData(encoded string) (ClientData, error) {
+	raw, err := DecodeBase64URL(encoded)
+	if err != nil {
+		return ClientData{}, ErrInvalidResponse
+	}
+	var cd ClientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return ClientData{}, ErrInvalidResponse
+	}
+	return cd, nil
+}
+
+// VerifyRegistration checks an attestation against the challenge issued for
+// it and returns the credential to store.
+func (c Config) VerifyRegistration(resp RegistrationResponse, challenge []byte) (Credential, error) {
+	if resp.Type != "public-key" {
+		return Credential{}, ErrInvalidResponse
+	}
+	if _, err := c.verifyClientData(resp.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return Credential{}, err
+	}
+
+	rawAttestation, err := DecodeBase64URL(resp.Response.AttestationObject)
+	if err != nil {
+		return Credential{}, ErrInvalidResponse
+	}
+	decoded, _, err := decodeCBOR(rawAttestation)
+	if err != nil {
+		return Credential{}, ErrInvalidResponse
+	}
+	attestation, ok := decoded.(map[any]any)
+	if !ok {
+		return Credential{}, ErrInvalidResponse
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return Credential{}, ErrInvalidResponse
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return Credential{}, err
+	}
+	if err := c.verifyAuthenticatorData(authData); err != nil {
+		return Credential{}, err
+	}
+	if authData.credentialID == nil {
+		return Credential{}, ErrInvalidResponse
+	}
+	if rawID, err := DecodeBase64URL(resp.RawID); err != nil || !bytes.Equal(rawID, authData.credentialID) {
+		return Credential{}, ErrInvalidResponse
+	}
+	if _, _, err := parseCOSEKey(authData.publicKey); err != nil {
+		return Credential{}, err
+	}
+
+	return Credential{
+		ID:         authData.credentialID,
+		PublicKey:  authData.publicKey,
+		SignCount:  authData.signCount,
+		Transports: resp.Response.Transports,
+	}, nil
+}
+
+// VerifyAssertion checks a login assertion for a stored credential and
+// returns the authenticator's new signature counter.
+func (c Config) VerifyAssertion(resp AssertionResponse, challenge []byte, cred Credential) (uint32, error) {
+	if resp.Type != "public-key" {
+		return 0, ErrInvalidResponse
+	}
+	if rawID, err := DecodeBase64URL(resp.RawID); err != nil || !bytes.Equal(rawID, cred.ID) {
+		return 0, ErrInvalidResponse
+	}
+	rawClientData, err := c.verifyClientData(resp.Response.ClientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return 0, err
+	}
+
+	rawAuthData, err := DecodeBase64URL(resp.Response.AuthenticatorData)
+	if err != nil {
+		return 0, ErrInvalidResponse
+	}
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.verifyAuthenticatorData(authData); err != nil {
+		return 0, err
+	}
+
+	key, _, err := parseCOSEKey(cred.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+	signature, err := DecodeBase64URL(resp.Response.Signature)
+	if err != nil {
+		return 0, ErrInvalidResponse
+	}
+	clientDataHash := sha256.Sum256(rawClientData)
+	signed := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+	if !key.verify(signed, signature) {
+		return 0, ErrInvalidSignature
+	}
+
+	// Authenticators that do not implement counters always report zero.
+	if (authData.signCount != 0 || cred.SignCount != 0) && authData.signCount <= cred.SignCount {
+		return 0, ErrSignCountRegression
+	}
+	return authData.signCount, nil
+}
+
+func (c Config) userVerification() string {
+	if c.RequireUserVerification {
+		return "required"
+	}
+	return "preferred"
+}
+
+func (c Config) verifyClientData(encoded, ceremony string, challenge []byte) ([]byte, error) {
+	raw, err := DecodeBase64URL(encoded)
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+	var cd ClientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return nil, ErrInvalidResponse
+	}
+	if cd.Type != ceremony {
+		return nil, ErrInvalidResponse
+	}
+	got, err := DecodeBase64URL(cd.Challenge)
+	if err != nil || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return nil, ErrChallengeMismatch
+	}
+	if cd.CrossOrigin || !slices.Contains(c.Origins, cd.Origin) {
+		return nil, ErrOriginMismatch
+	}
+	return raw, nil
+}
+
+func (c Config) verifyAuthenticatorData(authData authenticatorData) error {
+	rpIDHash := sha256.Sum256([]byte(c.RPID))
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rpIDHash[:]) != 1 {
+		return ErrRPIDMismatch
+	}
+	if authData.flags&flagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+	if c.RequireUserVerification && authData.flags&flagUserVerified == 0 {
+		return ErrUserNotVerified
+	}
+	return nil
+}
+
+func parseAuthenticatorData(raw []byte) (authenticatorData, error) {
+	if len(raw) < 37 {
+		return authenticatorData{}, ErrInvalidResponse
+	}
+	out := authenticatorData{
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	if out.flags&flagAttestedCredData == 0 {
+		return out, nil
+	}
+
+	rest := raw[37:]
+	// aaguid (16) + credential id length (2)
+	if len(rest) < 18 {
+		return authenticatorData{}, ErrInvalidResponse
+	}
+	idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLen == 0 || idLen > maxCredentialIDLen || len(rest) < idLen {
+		return authenticatorData{}, ErrInvalidResponse
+	}
+	out.credentialID = append([]byte{}, rest[:idLen]...)
+	rest = rest[idLen:]
+
+	_, keyLen, err := decodeCBOR(rest)
+	if err != nil {
+		return authenticatorData{}, ErrInvalidResponse
+	}
+	out.publicKey = append([]byte{}, rest[:keyLen]...)
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package webauthn_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goal-bite-api/internal/webauthn"
+	"goal-bite-api/internal/webauthn/webauthntest"
+)
+
+func testConfig() webauthn.Config {
+	return webauthn.Config{
+		RPID:    "localhost",
+		RPName:  "Goal Bite",
+		Origins: []string{"http://localhost:3000"},
+		Timeout: 5 * time.Minute,
+	}
+}
+
+func register(t *testing.T, cfg webauthn.Config, authn *webauthntest.Authenticator) webauthn.Credential {
+	t.Helper()
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		t.Fatalf("challenge: %v", err)
+	}
+	opts := cfg.CreationOptions(challenge, webauthn.UserEntity{ID: "AAAAAAAAAAc", Name: "a@example.com", DisplayName: "A"}, nil)
+	resp, err := authn.Create(opts)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	cred, err := cfg.VerifyRegistration(resp, challenge)
+	if err != nil {
+		t.Fatalf("verify registration: %v", err)
+	}
+	return cred
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	cfg := testConfig()
+	authn, err := webauthntest.New("localhost", "http://localhost:3000")
+	if err != nil {
+		t.Fatalf("new authenticator: %v", err)
+	}
+	cred := register(t, cfg, authn)
+	if string(cred.ID) != string(authn.CredentialID()) {
+		t.Fatalf("unexpected credential id")
+	}
+
+	for want := uint32(1); want <= 2; want++ {
+		challenge, _ := webauthn.NewChallenge()
+		resp, err := authn.Get(cfg.RequestOptions(challenge))
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		count, err := cfg.VerifyAssertion(resp, challenge, cred)
+		if err != nil {
+			t.Fatalf("verify assertion: %v", err)
+		}
+		if count != want {
+			t.Fatalf("expected sign count %d, got %d", want, count)
+		}
+		cred.SignCount = count
+	}
+}
+
+func TestRegistrationRejections(t *testing.T) {
+	cfg := testConfig()
+
+	t.Run("wrong challenge", func(t *testing.T) {
+		authn, _ := webauthntest.New("localhost", "http://localhost:3000")
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := authn.Create(cfg.CreationOptions(challenge, webauthn.UserEntity{ID: "AQ"}, nil))
+		other, _ := webauthn.NewChallenge()
+		if _, err := cfg.VerifyRegistration(resp, other); !errors.Is(err, webauthn.ErrChallengeMismatch) {
+			t.Fatalf("expected ErrChallengeMismatch, got %v", err)
+		}
+	})
+
+	t.Run("foreign origin", func(t *testing.T) {
+		authn, _ := webauthntest.New("localhost", "https://evil.example.com")
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := authn.Create(cfg.CreationOptions(challenge, webauthn.UserEntity{ID: "AQ"}, nil))
+		if _, err := cfg.VerifyRegistration(resp, challenge); !errors.Is(err, webauthn.ErrOriginMismatch) {
+			t.Fatalf("expected ErrOriginMismatch, got %v", err)
+		}
+	})
+
+	t.Run("other relying party", func(t *testing.T) {
+		authn, _ := webauthntest.New("example.com", "http://localhost:3000")
+		challenge, _ := webauthn.NewChallenge()
+		opts := cfg.CreationOptions(challenge, webauthn.UserEntity{ID: "AQ"}, nil)
+		opts.RP.ID = "example.com"
+		resp, _ := authn.Create(opts)
+		if _, err := cfg.VerifyRegistration(resp, challenge); !errors.Is(err, webauthn.ErrRPIDMismatch) {
+			t.Fatalf("expected ErrRPIDMismatch, got %v", err)
+		}
+	})
+
+	t.Run("user verification required", func(t *testing.T) {
+		strict := cfg
+		strict.RequireUserVerification = true
+		authn, _ := webauthntest.New("localhost", "http://localhost:3000")
+		authn.UserVerified = false
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := authn.Create(strict.CreationOptions(challenge, webauthn.UserEntity{ID: "AQ"}, nil))
+		if _, err := strict.VerifyRegistration(resp, challenge); !errors.Is(err, webauthn.ErrUserNotVerified) {
+			t.Fatalf("expected ErrUserNotVerified, got %v", err)
+		}
+	})
+
+	t.Run("malformed attestation", func(t *testing.T) {
+		authn, _ := webauthntest.New("localhost", "http://localhost:3000")
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := authn.Create(cfg.CreationOptions(challenge, webauthn.UserEntity{ID: "AQ"}, nil))
+		resp.Response.AttestationObject = webauthn.EncodeBase64URL([]byte{0xa1, 0x63})
+		if _, err := cfg.VerifyRegistration(resp, challenge); !errors.Is(err, webauthn.ErrInvalidResponse) {
+			t.Fatalf("expected ErrInvalidResponse, got %v", err)
+		}
+	})
+}
+
+func TestAssertionRejections(t *testing.T) {
+	cfg := testConfig()
+	authn, _ := webauthntest.New("localhost", "http://localhost:3000")
+	cred := register(t, cfg, authn)
+
+	t.Run("tampered signature", func(t *testing.T) {
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := authn.Get(cfg.RequestOptions(challenge))
+		other, _ := webauthntest.New("localhost", "http://localhost:3000")
+		forged, _ := other.Get(cfg.RequestOptions(challenge))
+		resp.Response.Signature = forged.Response.Signature
+		if _, err := cfg.VerifyAssertion(resp, challenge, cred); !errors.Is(err, webauthn.ErrInvalidSignature) {
+			t.Fatalf("expected ErrInvalidSignature, got %v", err)
+		}
+	})
+
+	t.Run("sign count regression", func(t *testing.T) {
+		stored := cred
+		stored.SignCount = 50
+		challenge, _ := webauthn.NewChallenge()
+		resp, _ := authn.Get(cfg.RequestOptions(challenge))
+		if _, err := cfg.VerifyAssertion(resp, challenge, stored); !errors.Is(err, webauthn.ErrSignCountRegression) {
+			t.Fatalf("expected ErrSignCountRegression, got %v", err)
+		}
+	})
+
+	t.Run("replayed registration challenge type", func(t *testing.T) {
+		challenge, _ := webauthn.NewChallenge()
+		created, _ := authn.Create(cfg.CreationOptions(challenge, webauthn.UserEntity{ID: "AQ"}, nil))
+		resp, _ := authn.Get(cfg.RequestOptions(challenge))
+		resp.Response.ClientDataJSON = created.Response.ClientDataJSON
+		if _, err := cfg.VerifyAssertion(resp, challenge, cred); !errors.Is(err, webauthn.ErrInvalidResponse) {
+			t.Fatalf("expected ErrInvalidResponse, got %v", err)
+		}
+	})
+}
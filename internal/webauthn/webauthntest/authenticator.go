@@ -0,0 +1,185 @@
+// Package webauthntest provides a software passkey authenticator for tests.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"goal-bite-api/internal/webauthn"
+)
+
+// Authenticator is a single-credential ES256 authenticator that produces the
+// same JSON a browser returns from navigator.credentials.create() and get().
+type Authenticator struct {
+	RPID   string
+	Origin string
+	// UserVerified controls the UV flag in authenticator data.
+	UserVerified bool
+	// SignCount is the counter reported by the next assertion after it is
+	// incremented. Tests may set it directly to simulate a cloned key.
+	SignCount uint32
+
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+}
+
+func New(rpID, origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return &Authenticator{RPID: rpID, Origin: origin, UserVerified: true, key: key, credentialID: id}, nil
+}
+
+func (a *Authenticator) CredentialID() []byte {
+	return a.credentialID
+}
+
+func (a *Authenticator) Create(opts webauthn.CreationOptions) (webauthn.RegistrationResponse, error) {
+	if opts.RP.ID != a.RPID {
+		return webauthn.RegistrationResponse{}, errors.New("rp id mismatch")
+	}
+	userHandle, err := webauthn.DecodeBase64URL(opts.User.ID)
+	if err != nil {
+		return webauthn.RegistrationResponse{}, err
+	}
+	a.userHandle = userHandle
+
+	clientData, err := a.clientData("webauthn.create", opts.Challenge)
+	if err != nil {
+		return webauthn.RegistrationResponse{}, err
+	}
+
+	x := a.key.X.FillBytes(make([]byte, 32))
+	y := a.key.Y.FillBytes(make([]byte, 32))
+	coseKey := cborMap(
+		cborInt(1), cborInt(2), // kty: EC2
+		cborInt(3), cborInt(-7), // alg: ES256
+		cborInt(-1), cborInt(1), // crv: P-256
+		cborInt(-2), cborBytes(x),
+		cborInt(-3), cborBytes(y),
+	)
+
+	authData := a.authData(0x40)
+	authData = append(authData, make([]byte, 16)...) // aaguid
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, coseKey...)
+
+	attestation := cborMap(
+		cborText("fmt"), cborText("none"),
+		cborText("attStmt"), cborMap(),
+		cborText("authData"), cborBytes(authData),
+	)
+
+	id := webauthn.EncodeBase64URL(a.credentialID)
+	return webauthn.RegistrationResponse{
+		ID:    id,
+		RawID: id,
+		Type:  "public-key",
+		Response: webauthn.AttestationResponse{
+			ClientDataJSON:    webauthn.EncodeBase64URL(clientData),
+			AttestationObject: webauthn.EncodeBase64URL(attestation),
+			Transports:        []string{"internal"},
+		},
+	}, nil
+}
+
+func (a *Authenticator) Get(opts webauthn.RequestOptions) (webauthn.AssertionResponse, error) {
+	if opts.RPID != a.RPID {
+		return webauthn.AssertionResponse{}, errors.New("rp id mismatch")
+	}
+	clientData, err := a.clientData("webauthn.get", opts.Challenge)
+	if err != nil {
+		return webauthn.AssertionResponse{}, err
+	}
+
+	a.SignCount++
+	authData := a.authData(0)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return webauthn.AssertionResponse{}, err
+	}
+
+	id := webauthn.EncodeBase64URL(a.credentialID)
+	return webauthn.AssertionResponse{
+		ID:    id,
+		RawID: id,
+		Type:  "public-key",
+		Response: webauthn.AssertionEvidence{
+			ClientDataJSON:    webauthn.EncodeBase64URL(clientData),
+			AuthenticatorData: webauthn.EncodeBase64URL(authData),
+			Signature:         webauthn.EncodeBase64URL(signature),
+			UserHandle:        webauthn.EncodeBase64URL(a.userHandle),
+		},
+	}, nil
+}
+
+func (a *Authenticator) clientData(ceremony, challenge string) ([]byte, error) {
+	return json.Marshal(webauthn.ClientData{
+		Type:      ceremony,
+		Challenge: challenge,
+		Origin:    a.Origin,
+	})
+}
+
+func (a *Authenticator) authData(extraFlags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	flags := byte(0x01) | extraFlags
+	if a.UserVerified {
+		flags |= 0x04
+	}
+	out := append([]byte{}, rpIDHash[:]...)
+	out = append(out, flags)
+	return binary.BigEndian.AppendUint32(out, a.SignCount)
+}
+
+func cborHead(major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n <= 0xff:
+		return []byte{major<<5 | 24, byte(n)}
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+	}
+	return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, n)
+}
+
+func cborInt(v int64) []byte {
+	if v < 0 {
+		return cborHead(1, uint64(-1-v))
+	}
+	return cborHead(0, uint64(v))
+}
+
+func cborBytes(b []byte) []byte {
+	return append(cborHead(2, uint64(len(b))), b...)
+}
+
+func cborText(s string) []byte {
+	return append(cborHead(3, uint64(len(s))), s...)
+}
+
+// cborMap encodes alternating key/value items in the given order.
+func cborMap(items ...[]byte) []byte {
+	out := cborHead(5, uint64(len(items)/2))
+	for _, item := range items {
+		out = append(out, item...)
+	}
+	return out
+}